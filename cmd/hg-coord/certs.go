@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/auth"
+	hgtls "github.com/h3nr1-d14z/hybridgrid/internal/security/tls"
+)
+
+// newCertsCmd manages a certificate directory holding the CA that signs
+// coordinator, worker and client certificates for TLS and mTLS.
+func newCertsCmd() *cobra.Command {
+	certsCmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Manage the certificate authority for TLS and mTLS",
+	}
+
+	var dir string
+
+	var (
+		caName     string
+		caValidity time.Duration
+	)
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a CA in the certificate directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ca, err := hgtls.InitCA(dir, caName, caValidity)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Created CA %q in %s\n", caName, dir)
+			fmt.Printf("CA fingerprint: %s\n", ca.Fingerprint())
+			return nil
+		},
+	}
+	initCmd.Flags().StringVar(&dir, "dir", "", "Certificate directory (created if missing)")
+	initCmd.Flags().StringVar(&caName, "name", "Hybrid-Grid CA", "Common name of the CA")
+	initCmd.Flags().DurationVar(&caValidity, "validity", hgtls.DefaultCAValidity, "How long the CA is valid")
+	_ = initCmd.MarkFlagRequired("dir")
+
+	var (
+		server, worker, client string
+		hosts                  []string
+		outDir                 string
+		validity               time.Duration
+	)
+	issueCmd := &cobra.Command{
+		Use:   "issue",
+		Short: "Issue a certificate for a coordinator, worker or client",
+		Long: `Issue a certificate signed by the CA and write <name>.crt and <name>.key.
+
+  --server NAME   coordinator certificate (TLS server)
+  --worker NAME   worker certificate (TLS server and mTLS client)
+  --client NAME   hgbuild certificate (mTLS client)
+
+NAME becomes the certificate's CN and DNS name; use --host for further
+DNS names or IP addresses the holder is reached at. Names under
+` + hgtls.EnrolledWorkerDomain + ` are reserved for workers.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				name  string
+				usage hgtls.Usage
+				set   int
+			)
+			if server != "" {
+				name, usage, set = server, hgtls.UsageServer, set+1
+			}
+			if worker != "" {
+				name, usage, set = worker, hgtls.UsageServer|hgtls.UsageClient, set+1
+			}
+			if client != "" {
+				name, usage, set = client, hgtls.UsageClient, set+1
+			}
+			if set != 1 {
+				return fmt.Errorf("exactly one of --server, --worker or --client is required")
+			}
+			if worker == "" {
+				// Keep enrolled worker names apart from everyone else's
+				for _, host := range append([]string{name}, hosts...) {
+					if hgtls.InEnrolledWorkerDomain(host) {
+						return fmt.Errorf("%s is reserved for enrolled workers", hgtls.EnrolledWorkerDomain)
+					}
+				}
+			}
+
+			ca, err := hgtls.LoadCA(dir)
+			if err != nil {
+				return err
+			}
+			certPEM, keyPEM, err := ca.Issue(name, usage, hosts, validity)
+			if err != nil {
+				return err
+			}
+			if outDir == "" {
+				outDir = dir
+			}
+			certFile, keyFile, err := hgtls.WriteKeyPair(outDir, name, certPEM, keyPEM)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "Issued certificate for %q\n", name)
+			fmt.Printf("--tls-cert %s --tls-key %s --tls-ca %s\n", certFile, keyFile, filepath.Join(dir, hgtls.CACertFile))
+			return nil
+		},
+	}
+	issueCmd.Flags().StringVar(&dir, "dir", "", "Certificate directory holding the CA")
+	issueCmd.Flags().StringVar(&server, "server", "", "Issue a coordinator certificate for this name")
+	issueCmd.Flags().StringVar(&worker, "worker", "", "Issue a worker certificate for this name")
+	issueCmd.Flags().StringVar(&client, "client", "", "Issue a client certificate for this name")
+	issueCmd.Flags().StringSliceVar(&hosts, "host", nil, "Additional DNS names or IP addresses")
+	issueCmd.Flags().StringVar(&outDir, "out", "", "Directory to write the certificate and key to (default: --dir)")
+	issueCmd.Flags().DurationVar(&validity, "validity", hgtls.DefaultLeafValidity, "How long the certificate is valid")
+	_ = issueCmd.MarkFlagRequired("dir")
+
+	var (
+		joinWorker string
+		joinIPs    []string
+		ttl        time.Duration
+	)
+	joinTokenCmd := &cobra.Command{
+		Use:   "join-token",
+		Short: "Create a one-time token a new worker exchanges for a certificate",
+		Long: `Create a one-time join token. A new worker redeems it with
+'hg-worker enroll' against a coordinator started with --enroll-dir.
+
+The worker's certificate is issued for <worker>.` + hgtls.EnrolledWorkerDomain + `
+and the --ip addresses, whatever the worker asks for. Use the worker's
+hostname as --worker so it passes attestation.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			certName, err := hgtls.EnrolledWorkerName(joinWorker)
+			if err != nil {
+				return err
+			}
+			ca, err := hgtls.LoadCA(dir)
+			if err != nil {
+				return err
+			}
+			token, entry, err := auth.NewJoinTokens(filepath.Join(dir, hgtls.JoinTokensFile)).Create(joinWorker, joinIPs, ttl)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "Join token for %s valid until %s. It is shown only once:\n", certName, entry.ExpiresAt.Local().Format(time.RFC3339))
+			fmt.Println(token)
+			fmt.Fprintf(os.Stderr, "\nOn the worker:\n  hg-worker enroll --coordinator <addr> --join-token <token> --ca-fingerprint %s\n", ca.Fingerprint())
+			return nil
+		},
+	}
+	joinTokenCmd.Flags().StringVar(&dir, "dir", "", "Certificate directory holding the CA")
+	joinTokenCmd.Flags().StringVar(&joinWorker, "worker", "", "Name of the worker the token enrolls (its hostname)")
+	joinTokenCmd.Flags().StringSliceVar(&joinIPs, "ip", nil, "IP addresses the coordinator reaches the worker at")
+	joinTokenCmd.Flags().DurationVar(&ttl, "ttl", 24*time.Hour, "How long the token can be redeemed")
+	_ = joinTokenCmd.MarkFlagRequired("dir")
+	_ = joinTokenCmd.MarkFlagRequired("worker")
+
+	certsCmd.AddCommand(initCmd, issueCmd, joinTokenCmd)
+	return certsCmd
+}
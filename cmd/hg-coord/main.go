@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -13,16 +15,23 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/h3nr1-d14z/hybridgrid/internal/config"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/ha"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
 	coordserver "github.com/h3nr1-d14z/hybridgrid/internal/coordinator/server"
 	"github.com/h3nr1-d14z/hybridgrid/internal/discovery/mdns"
 	"github.com/h3nr1-d14z/hybridgrid/internal/logging"
 	"github.com/h3nr1-d14z/hybridgrid/internal/observability/dashboard"
 	observabilitymetrics "github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
 	"github.com/h3nr1-d14z/hybridgrid/internal/observability/tracing"
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/auth"
+	hgtls "github.com/h3nr1-d14z/hybridgrid/internal/security/tls"
 )
 
 var version = "v0.0.0-dev"
 
+// validSchedulers are the --scheduler values accepted by serve and replay.
+var validSchedulers = map[string]bool{"leastloaded": true, "simple": true, "p2c": true, "epsilon-greedy": true, "linucb": true, "heft": true}
+
 func main() {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 
@@ -67,14 +76,26 @@ It manages worker registration, task scheduling, and provides the dashboard.`,
 			grpcPort, _ := cmd.Flags().GetInt("grpc-port")
 			httpPort, _ := cmd.Flags().GetInt("http-port")
 			token, _ := cmd.Flags().GetString("token")
+			tokensFile, _ := cmd.Flags().GetString("tokens-file")
 			noMdns, _ := cmd.Flags().GetBool("no-mdns")
 			schedulerType, _ := cmd.Flags().GetString("scheduler")
 			taskLogPath, _ := cmd.Flags().GetString("task-log")
+			artifactDir, _ := cmd.Flags().GetString("artifact-dir")
+			buildCacheDir, _ := cmd.Flags().GetString("build-cache-dir")
+			buildCacheMaxMB, _ := cmd.Flags().GetInt64("build-cache-max-mb")
+			buildCacheTTLHours, _ := cmd.Flags().GetInt("build-cache-ttl-hours")
+			queueMaxDepth, _ := cmd.Flags().GetInt("queue-max-depth")
+			queueTimeout, _ := cmd.Flags().GetDuration("queue-timeout")
+			registryFile, _ := cmd.Flags().GetString("registry-file")
+			haLeaseFile, _ := cmd.Flags().GetString("ha-lease-file")
+			haLeaseTTL, _ := cmd.Flags().GetDuration("ha-lease-ttl")
+			advertiseAddr, _ := cmd.Flags().GetString("advertise-address")
+			workersFile, _ := cmd.Flags().GetString("workers-file")
 			epsilonValue, _ := cmd.Flags().GetFloat64("epsilon")
 			alphaValue, _ := cmd.Flags().GetFloat64("alpha")
+			schedulerStateFile, _ := cmd.Flags().GetString("scheduler-state-file")
 
 			// Validate scheduler choice (fail fast rather than silent fallback).
-			validSchedulers := map[string]bool{"leastloaded": true, "simple": true, "p2c": true, "epsilon-greedy": true, "linucb": true, "heft": true}
 			if !validSchedulers[schedulerType] {
 				return fmt.Errorf("invalid --scheduler %q; must be one of: leastloaded, simple, p2c, epsilon-greedy, linucb, heft", schedulerType)
 			}
@@ -95,12 +116,76 @@ It manages worker registration, task scheduling, and provides the dashboard.`,
 			if grpcPort == httpPort {
 				return fmt.Errorf("invalid configuration: coordinator.grpc_port and coordinator.http_port must be different, got %d for both", grpcPort)
 			}
+			if buildCacheMaxMB <= 0 {
+				return fmt.Errorf("invalid configuration: coordinator.build_cache_max_size_mb must be > 0, got %d", buildCacheMaxMB)
+			}
+			if buildCacheTTLHours <= 0 {
+				return fmt.Errorf("invalid configuration: coordinator.build_cache_ttl_hours must be > 0, got %d", buildCacheTTLHours)
+			}
+			if queueMaxDepth <= 0 {
+				return fmt.Errorf("invalid configuration: coordinator.queue_max_depth must be > 0, got %d", queueMaxDepth)
+			}
+			if queueTimeout <= 0 {
+				return fmt.Errorf("invalid configuration: coordinator.queue_timeout must be > 0, got %s", queueTimeout)
+			}
+			if tokensFile != "" {
+				if _, err := auth.LoadTokenStore(tokensFile); err != nil {
+					return fmt.Errorf("invalid configuration: coordinator.tokens_file: %w", err)
+				}
+			}
+			if registryFile != "" {
+				if _, err := registry.NewFileStore(registryFile).Load(); err != nil {
+					return fmt.Errorf("invalid configuration: coordinator.registry_file: %w", err)
+				}
+			}
+			if haLeaseFile != "" && registryFile == "" {
+				return fmt.Errorf("invalid configuration: coordinator.ha_lease_file requires coordinator.registry_file on the same shared storage")
+			}
+			if haLeaseTTL <= 0 {
+				return fmt.Errorf("invalid configuration: coordinator.ha_lease_ttl must be > 0, got %s", haLeaseTTL)
+			}
+			var staticWorkers []coordserver.StaticWorker
+			if workersFile != "" {
+				fileCfg, err := config.Load(workersFile)
+				if err != nil {
+					return fmt.Errorf("invalid configuration: coordinator.workers_file: %w", err)
+				}
+				for i, w := range fileCfg.Workers {
+					if err := w.Validate(); err != nil {
+						return fmt.Errorf("invalid configuration: coordinator.workers_file: workers[%d]: %w", i, err)
+					}
+					staticWorkers = append(staticWorkers, coordserver.StaticWorker{
+						Address:       w.Address,
+						LAN:           w.LAN,
+						BandwidthMbps: w.BandwidthMbps,
+					})
+				}
+			}
 
 			// TLS flags
 			tlsCert, _ := cmd.Flags().GetString("tls-cert")
 			tlsKey, _ := cmd.Flags().GetString("tls-key")
 			tlsCA, _ := cmd.Flags().GetString("tls-ca")
 			tlsRequireClientCert, _ := cmd.Flags().GetBool("tls-require-client-cert")
+			tlsReloadInterval, _ := cmd.Flags().GetDuration("tls-reload-interval")
+			workerCertAllow, _ := cmd.Flags().GetStringSlice("worker-cert-allow")
+			enrollDir, _ := cmd.Flags().GetString("enroll-dir")
+			if enrollDir != "" {
+				if _, err := hgtls.LoadCA(enrollDir); err != nil {
+					return fmt.Errorf("invalid configuration: coordinator.enroll_dir: %w", err)
+				}
+				if tlsCert == "" {
+					return fmt.Errorf("invalid configuration: coordinator.enroll_dir requires --tls-cert")
+				}
+			}
+			if len(workerCertAllow) > 0 {
+				if !tlsRequireClientCert {
+					return fmt.Errorf("invalid configuration: coordinator.worker_cert_allow requires --tls-require-client-cert")
+				}
+				if err := hgtls.ValidatePatterns(workerCertAllow); err != nil {
+					return fmt.Errorf("invalid configuration: coordinator.worker_cert_allow: %w", err)
+				}
+			}
 
 			// Tracing flags
 			tracingEnable, _ := cmd.Flags().GetBool("tracing-enable")
@@ -152,13 +237,23 @@ It manages worker registration, task scheduling, and provides the dashboard.`,
 			cfg := coordserver.DefaultConfig()
 			cfg.Port = grpcPort
 			cfg.AuthToken = token
+			cfg.TokensFile = tokensFile
 			cfg.HeartbeatTTL = 60 * time.Second
 			cfg.RequestTimeout = 120 * time.Second
 			cfg.EnableRequestID = true
 			cfg.SchedulerType = schedulerType
 			cfg.TaskLogPath = taskLogPath
+			cfg.ArtifactDir = artifactDir
+			cfg.BuildCacheDir = buildCacheDir
+			cfg.BuildCacheMaxSizeMB = buildCacheMaxMB
+			cfg.BuildCacheTTLHours = buildCacheTTLHours
+			cfg.MaxQueueDepth = queueMaxDepth
+			cfg.QueueTimeout = queueTimeout
+			cfg.RegistryFile = registryFile
+			cfg.StaticWorkers = staticWorkers
 			cfg.EpsilonValue = epsilonValue
 			cfg.AlphaValue = alphaValue
+			cfg.SchedulerStateFile = schedulerStateFile
 			cfg.Tracing.Enable = tracingEnable
 			cfg.Tracing.Endpoint = tracingEndpoint
 			cfg.Tracing.ServiceName = tracingServiceName
@@ -167,6 +262,27 @@ It manages worker registration, task scheduling, and provides the dashboard.`,
 			cfg.Tracing.Timeout = tracingTimeout
 			cfg.Tracing.BatchSize = tracingBatchSize
 
+			hostname, _ := os.Hostname()
+			if advertiseAddr == "" {
+				advertiseAddr = net.JoinHostPort(hostname, strconv.Itoa(grpcPort))
+			}
+			cfg.HA = ha.Config{
+				LeaseFile: haLeaseFile,
+				LeaseTTL:  haLeaseTTL,
+				ID:        fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+				Address:   advertiseAddr,
+			}
+			if len(staticWorkers) > 0 {
+				log.Info().Int("workers", len(staticWorkers)).Str("file", workersFile).Msg("Static workers configured")
+			}
+			if haLeaseFile != "" {
+				log.Info().
+					Str("lease_file", haLeaseFile).
+					Dur("lease_ttl", haLeaseTTL).
+					Str("advertise_address", advertiseAddr).
+					Msg("Leader election enabled")
+			}
+
 			// Configure TLS from CLI flags
 			anyTLSFlags := tlsCert != "" || tlsKey != "" || tlsCA != "" || tlsRequireClientCert
 			cfg.TLS.CertFile = tlsCert
@@ -174,6 +290,8 @@ It manages worker registration, task scheduling, and provides the dashboard.`,
 			cfg.TLS.ClientCA = tlsCA
 			cfg.TLS.RequireClientCert = tlsRequireClientCert
 			cfg.TLS.Enabled = anyTLSFlags
+			cfg.WorkerCertAllowlist = workerCertAllow
+			cfg.EnrollDir = enrollDir
 
 			// Validate TLS configuration if any TLS flags were provided
 			if cfg.TLS.Enabled {
@@ -184,7 +302,14 @@ It manages worker registration, task scheduling, and provides the dashboard.`,
 				log.Info().
 					Str("cert", tlsCert).
 					Bool("mtls", tlsRequireClientCert).
+					Strs("worker_cert_allow", workerCertAllow).
 					Msg("TLS enabled")
+
+				// Pick up rotated certificates without a restart
+				if tlsReloadInterval > 0 {
+					go hgtls.WatchReloads(ctx, tlsReloadInterval)
+				}
+				hgtls.ReloadOnSignal(ctx, syscall.SIGHUP)
 			} else {
 				log.Debug().Msg("TLS disabled")
 			}
@@ -214,6 +339,11 @@ It manages worker registration, task scheduling, and provides the dashboard.`,
 			// Wire up event notifications from coordinator to dashboard
 			onStart, onComplete := dashSrv.CreateEventNotifier()
 			srv.SetEventNotifier(&eventNotifierWrapper{onStart: onStart, onComplete: onComplete})
+			if logSource := srv.NewTaskLogSource(); logSource != nil {
+				dashSrv.Hub().SetTaskLogSource(logSource)
+			} else {
+				log.Info().Msg("Dashboard task logs disabled: no API tokens configured")
+			}
 
 			go func() {
 				if err := dashSrv.Start(); err != nil {
@@ -223,35 +353,41 @@ It manages worker registration, task scheduling, and provides the dashboard.`,
 
 			log.Info().Int("port", httpPort).Msg("Dashboard server started")
 
-			// Start mDNS announcer (unless disabled)
+			// Start mDNS announcer (unless disabled). Only the leader is
+			// announced, so discovery finds the coordinator dispatching builds.
 			var mdnsAnnouncer *mdns.CoordAnnouncer
 			if !noMdns {
-				hostname, _ := os.Hostname()
 				mdnsAnnouncer = mdns.NewCoordAnnouncer(mdns.CoordAnnouncerConfig{
 					Instance:   fmt.Sprintf("hg-coord-%s", hostname),
 					GRPCPort:   grpcPort,
 					HTTPPort:   httpPort,
 					Version:    version,
-					InstanceID: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+					InstanceID: cfg.HA.ID,
 				})
 
-				if err := mdnsAnnouncer.Start(); err != nil {
-					log.Warn().Err(err).Msg("Failed to start mDNS announcer (continuing without)")
-				} else {
-					log.Info().
-						Str("service", mdns.CoordServiceType).
-						Msg("Coordinator discoverable via mDNS")
-				}
+				srv.OnLeadershipChange(func(leader bool) {
+					if !leader {
+						mdnsAnnouncer.Stop()
+						return
+					}
+					if err := mdnsAnnouncer.Start(); err != nil {
+						log.Warn().Err(err).Msg("Failed to start mDNS announcer (continuing without)")
+					} else {
+						log.Info().
+							Str("service", mdns.CoordServiceType).
+							Msg("Coordinator discoverable via mDNS")
+					}
+				})
 			}
 
 			select {
 			case sig := <-sigCh:
 				log.Info().Str("signal", sig.String()).Msg("Received shutdown signal")
+				dashSrv.Stop()
+				srv.Stop()
 				if mdnsAnnouncer != nil {
 					mdnsAnnouncer.Stop()
 				}
-				dashSrv.Stop()
-				srv.Stop()
 				return nil
 			case err := <-errCh:
 				return fmt.Errorf("server error: %w", err)
@@ -262,15 +398,31 @@ It manages worker registration, task scheduling, and provides the dashboard.`,
 	serveCmd.Flags().Int("grpc-port", 9000, "gRPC server port")
 	serveCmd.Flags().Int("http-port", 8080, "HTTP/Dashboard port")
 	serveCmd.Flags().String("token", "", "Authentication token")
+	serveCmd.Flags().String("tokens-file", cfg.Coordinator.TokensFile, "YAML file of named, scoped API tokens (reloaded on change; see 'hg-coord token')")
 	serveCmd.Flags().Bool("no-mdns", false, "Disable mDNS advertisement")
 	serveCmd.Flags().String("scheduler", "leastloaded", "Scheduler type: leastloaded, simple, p2c, epsilon-greedy, linucb, heft")
 	serveCmd.Flags().String("task-log", "", "Path to per-task JSON Lines log file (default: stdout)")
+	serveCmd.Flags().String("artifact-dir", "", "Directory for build artifacts awaiting download (default: system temp dir)")
+	serveCmd.Flags().String("build-cache-dir", cfg.Coordinator.BuildCacheDir, "Directory for cached Flutter/Unity/project build results (kept across restarts)")
+	serveCmd.Flags().Int64("build-cache-max-mb", cfg.Coordinator.BuildCacheMaxSizeMB, "Maximum build cache size in MB; least recently used results are evicted")
+	serveCmd.Flags().Int("build-cache-ttl-hours", cfg.Coordinator.BuildCacheTTLHours, "Hours a cached build result stays valid")
+	serveCmd.Flags().Int("queue-max-depth", cfg.Coordinator.QueueMaxDepth, "Maximum requests waiting for a free worker; further requests fail immediately")
+	serveCmd.Flags().Duration("queue-timeout", cfg.Coordinator.QueueTimeout, "How long a request may wait for a free worker")
+	serveCmd.Flags().String("registry-file", cfg.Coordinator.RegistryFile, "File keeping registered workers and their task history across restarts (default: in memory only)")
+	serveCmd.Flags().String("ha-lease-file", cfg.Coordinator.HALeaseFile, "Lease file on storage shared with standby coordinators; the holder dispatches builds (requires --registry-file on the same storage)")
+	serveCmd.Flags().Duration("ha-lease-ttl", cfg.Coordinator.HALeaseTTL, "How long the leader holds the lease without renewing it; roughly the failover time")
+	serveCmd.Flags().String("advertise-address", cfg.Coordinator.AdvertiseAddress, "Address clients reach this coordinator at, handed out while it is the leader (default: hostname:grpc-port)")
+	serveCmd.Flags().String("workers-file", cfg.Coordinator.WorkersFile, "Config file whose workers: list names static workers (e.g. WAN build farms) the coordinator registers and probes itself")
 	serveCmd.Flags().Float64("epsilon", 0.1, "Exploration rate for epsilon-greedy scheduler (in [0, 1]; ignored otherwise)")
 	serveCmd.Flags().Float64("alpha", 1.0, "LinUCB exploration coefficient α (in [0, 10]; ignored for other schedulers)")
+	serveCmd.Flags().String("scheduler-state-file", cfg.Coordinator.SchedulerStateFile, "File keeping what the epsilon-greedy, linucb or heft scheduler learned about each worker across restarts (default: start cold)")
 	serveCmd.Flags().String("tls-cert", "", "Path to TLS certificate file (PEM format)")
 	serveCmd.Flags().String("tls-key", "", "Path to TLS private key file (PEM format)")
 	serveCmd.Flags().String("tls-ca", "", "Path to CA certificate for client verification (mTLS)")
 	serveCmd.Flags().Bool("tls-require-client-cert", false, "Require client certificates (mTLS)")
+	serveCmd.Flags().Duration("tls-reload-interval", hgtls.DefaultReloadInterval, "How often to check TLS certificate files for changes (0 disables; SIGHUP always reloads)")
+	serveCmd.Flags().String("enroll-dir", cfg.Coordinator.EnrollDir, "Certificate directory from 'hg-coord certs init'; lets workers exchange join tokens for certificates")
+	serveCmd.Flags().StringSlice("worker-cert-allow", cfg.Coordinator.WorkerCertAllow, "Only accept workers whose client cert SAN/CN matches one of these globs and their reported hostname (requires --tls-require-client-cert)")
 	serveCmd.Flags().Bool("tracing-enable", false, "Enable OpenTelemetry tracing")
 	serveCmd.Flags().String("tracing-endpoint", "localhost:4317", "OTLP gRPC endpoint")
 	serveCmd.Flags().Float64("tracing-sample-rate", 0.1, "Tracing sample rate (0.0-1.0)")
@@ -279,7 +431,7 @@ It manages worker registration, task scheduling, and provides the dashboard.`,
 	serveCmd.Flags().Duration("tracing-timeout", 10*time.Second, "Timeout for OTLP exports")
 	serveCmd.Flags().Int("tracing-batch-size", 512, "Max spans to batch before export")
 
-	rootCmd.AddCommand(versionCmd, serveCmd)
+	rootCmd.AddCommand(versionCmd, serveCmd, newTokenCmd(), newCertsCmd(), newReplayCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -289,18 +441,18 @@ It manages worker registration, task scheduling, and provides the dashboard.`,
 
 // eventNotifierWrapper adapts dashboard callbacks to coordinator's EventNotifier interface.
 type eventNotifierWrapper struct {
-	onStart    func(id, buildType, status, workerID string, startedAt int64)
-	onComplete func(id, buildType, status, workerID string, startedAt, completedAt, durationMs int64, exitCode int32, errorMsg string)
+	onStart    func(id, buildType, status, workerID, client, project string, startedAt int64)
+	onComplete func(id, buildType, status, workerID, client, project string, startedAt, completedAt, durationMs int64, exitCode int32, errorMsg string)
 }
 
 func (w *eventNotifierWrapper) NotifyTaskStarted(event *coordserver.TaskEvent) {
 	if w.onStart != nil {
-		w.onStart(event.ID, event.BuildType, event.Status, event.WorkerID, event.StartedAt)
+		w.onStart(event.ID, event.BuildType, event.Status, event.WorkerID, event.Client, event.Project, event.StartedAt)
 	}
 }
 
 func (w *eventNotifierWrapper) NotifyTaskCompleted(event *coordserver.TaskEvent) {
 	if w.onComplete != nil {
-		w.onComplete(event.ID, event.BuildType, event.Status, event.WorkerID, event.StartedAt, event.CompletedAt, event.DurationMs, event.ExitCode, event.ErrorMessage)
+		w.onComplete(event.ID, event.BuildType, event.Status, event.WorkerID, event.Client, event.Project, event.StartedAt, event.CompletedAt, event.DurationMs, event.ExitCode, event.ErrorMessage)
 	}
 }
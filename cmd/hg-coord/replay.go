@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/replay"
+	coordserver "github.com/h3nr1-d14z/hybridgrid/internal/coordinator/server"
+)
+
+// newReplayCmd compares schedulers offline by replaying the tasks of a
+// coordinator task log against a simulated worker pool.
+func newReplayCmd() *cobra.Command {
+	var (
+		taskLog    string
+		schedulers []string
+		epsilon    float64
+		alpha      float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Compare schedulers by replaying a task log",
+		Long: `replay rebuilds the worker pool recorded in a task log (hg-coord serve
+--task-log), replays the logged compiles at their original arrival times with
+each scheduler, and reports the makespan, the mean and p95 latency from
+arrival to completion, and the regret: the compile time spent beyond the
+fastest worker free at each dispatch.
+
+Compile times on other workers are estimated from each worker's logged
+compile time per source byte, so results are only as good as the log's
+coverage of the pool.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, name := range schedulers {
+				if !validSchedulers[name] {
+					return fmt.Errorf("invalid --scheduler %q; must be one of: leastloaded, simple, p2c, epsilon-greedy, linucb, heft", name)
+				}
+			}
+			if epsilon < 0 || epsilon > 1 {
+				return fmt.Errorf("invalid --epsilon %v; must be in [0, 1]", epsilon)
+			}
+			if alpha < 0 || alpha > 10 {
+				return fmt.Errorf("invalid --alpha %v; must be in [0, 10]", alpha)
+			}
+
+			f, err := os.Open(taskLog)
+			if err != nil {
+				return err
+			}
+			records, err := coordserver.ReadTaskLog(f)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("read %s: %w", taskLog, err)
+			}
+			trace, err := replay.NewTrace(records)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Replaying %d tasks on %d workers\n", len(trace.Tasks), len(trace.Workers))
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "SCHEDULER\tTASKS\tMAKESPAN\tMEAN LATENCY\tP95 LATENCY\tREGRET")
+			for _, name := range schedulers {
+				res, err := replay.Run(trace, name, replay.Options{Epsilon: epsilon, Alpha: alpha})
+				if err != nil {
+					return fmt.Errorf("replay %s: %w", name, err)
+				}
+				fmt.Fprintf(tw, "%s\t%d\t%v\t%v\t%v\t%v\n", res.Scheduler, res.Tasks,
+					res.Makespan.Round(time.Millisecond), res.MeanLatency.Round(time.Millisecond),
+					res.P95Latency.Round(time.Millisecond), res.Regret.Round(time.Millisecond))
+			}
+			return tw.Flush()
+		},
+	}
+	cmd.Flags().StringVar(&taskLog, "task-log", "", "Task log (JSON Lines) written by hg-coord serve --task-log")
+	cmd.Flags().StringSliceVar(&schedulers, "scheduler", []string{"leastloaded", "p2c", "epsilon-greedy", "linucb", "heft"}, "Schedulers to compare")
+	cmd.Flags().Float64Var(&epsilon, "epsilon", 0.1, "Exploration rate for epsilon-greedy (in [0, 1])")
+	cmd.Flags().Float64Var(&alpha, "alpha", 1.0, "LinUCB exploration coefficient α (in [0, 10])")
+	_ = cmd.MarkFlagRequired("task-log")
+	return cmd
+}
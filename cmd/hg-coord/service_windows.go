@@ -15,6 +15,7 @@ import (
 	"golang.org/x/sys/windows/svc/eventlog"
 	"golang.org/x/sys/windows/svc/mgr"
 
+	"github.com/h3nr1-d14z/hybridgrid/internal/config"
 	coordserver "github.com/h3nr1-d14z/hybridgrid/internal/coordinator/server"
 	"github.com/h3nr1-d14z/hybridgrid/internal/discovery/mdns"
 	"github.com/h3nr1-d14z/hybridgrid/internal/observability/dashboard"
@@ -58,6 +59,7 @@ func (s *coordService) Execute(args []string, r <-chan svc.ChangeRequest, change
 	cfg.AuthToken = s.token
 	cfg.HeartbeatTTL = 60 * time.Second
 	cfg.RequestTimeout = 120 * time.Second
+	cfg.BuildCacheDir = config.DefaultConfig().Coordinator.BuildCacheDir
 
 	srv := coordserver.New(cfg)
 
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/auth"
+)
+
+// newTokenCmd manages the named API tokens of a tokens file. A running
+// coordinator picks up changes to the file without a restart.
+func newTokenCmd() *cobra.Command {
+	tokenCmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage scoped API tokens",
+	}
+
+	var (
+		tokensFile string
+		name       string
+		project    string
+		scopes     []string
+	)
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a token and add its hash to the tokens file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			token, entry, err := auth.NewTokenEntry(name, project, scopes)
+			if err != nil {
+				return err
+			}
+
+			file, err := auth.ReadTokenFile(tokensFile)
+			if errors.Is(err, fs.ErrNotExist) {
+				file = &auth.TokenFile{}
+			} else if err != nil {
+				return err
+			}
+			if err := file.Add(entry); err != nil {
+				return err
+			}
+			if err := auth.WriteTokenFile(tokensFile, file); err != nil {
+				return fmt.Errorf("write tokens file: %w", err)
+			}
+
+			fmt.Fprintf(os.Stderr, "Added token %q to %s. It is shown only once:\n", name, tokensFile)
+			fmt.Println(token)
+			return nil
+		},
+	}
+	createCmd.Flags().StringVar(&tokensFile, "tokens-file", "", "Tokens file to add the token to (created if missing)")
+	createCmd.Flags().StringVar(&name, "name", "", "Name identifying the token's user, team or pipeline")
+	createCmd.Flags().StringVar(&project, "project", "", "Project the token's tasks are attributed to")
+	createCmd.Flags().StringSliceVar(&scopes, "scopes", []string{string(auth.ScopeClientSubmit)}, "Scopes to grant: worker:register, client:submit, admin")
+	_ = createCmd.MarkFlagRequired("tokens-file")
+	_ = createCmd.MarkFlagRequired("name")
+
+	revokeCmd := &cobra.Command{
+		Use:   "revoke",
+		Short: "Remove a token from the tokens file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := auth.ReadTokenFile(tokensFile)
+			if err != nil {
+				return err
+			}
+			if !file.Remove(name) {
+				return fmt.Errorf("no token named %q in %s", name, tokensFile)
+			}
+			if err := auth.WriteTokenFile(tokensFile, file); err != nil {
+				return fmt.Errorf("write tokens file: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "Revoked token %q\n", name)
+			return nil
+		},
+	}
+	revokeCmd.Flags().StringVar(&tokensFile, "tokens-file", "", "Tokens file to remove the token from")
+	revokeCmd.Flags().StringVar(&name, "name", "", "Name of the token to revoke")
+	_ = revokeCmd.MarkFlagRequired("tokens-file")
+	_ = revokeCmd.MarkFlagRequired("name")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the tokens in the tokens file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := auth.ReadTokenFile(tokensFile)
+			if err != nil {
+				return err
+			}
+			for _, e := range file.Tokens {
+				fmt.Printf("%-24s %-16s %v\n", e.Name, e.Project, e.Scopes)
+			}
+			return nil
+		},
+	}
+	listCmd.Flags().StringVar(&tokensFile, "tokens-file", "", "Tokens file to list")
+	_ = listCmd.MarkFlagRequired("tokens-file")
+
+	tokenCmd.AddCommand(createCmd, revokeCmd, listCmd)
+	return tokenCmd
+}
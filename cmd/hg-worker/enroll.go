@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	hgtls "github.com/h3nr1-d14z/hybridgrid/internal/security/tls"
+)
+
+// joinTokenEnv is the environment variable read when --join-token is not
+// set, so the token need not appear in the process list.
+const joinTokenEnv = "HG_JOIN_TOKEN"
+
+// newEnrollCmd obtains a worker certificate from a coordinator started
+// with --enroll-dir, in exchange for a join token from
+// 'hg-coord certs join-token'.
+func newEnrollCmd() *cobra.Command {
+	var (
+		coordinator   string
+		joinToken     string
+		caFingerprint string
+		name          string
+		outDir        string
+		timeout       time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "enroll",
+		Short: "Exchange a join token for a worker certificate",
+		Long: `Exchange a one-time join token for a certificate signed by the
+coordinator's CA. The private key is generated locally and never sent.
+
+The coordinator is trusted by the fingerprint of its CA, which
+'hg-coord certs join-token' prints. The certificate's name and IP
+addresses are those the join token was created with.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if joinToken == "" {
+				joinToken = os.Getenv(joinTokenEnv)
+			}
+			if joinToken == "" {
+				return fmt.Errorf("--join-token or %s is required", joinTokenEnv)
+			}
+			if name == "" {
+				hostname, err := os.Hostname()
+				if err != nil {
+					return fmt.Errorf("get hostname: %w", err)
+				}
+				name = hostname
+			}
+
+			tlsConfig, err := hgtls.PinnedCATLS(caFingerprint)
+			if err != nil {
+				return err
+			}
+			conn, err := grpc.NewClient(coordinator, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+			if err != nil {
+				return fmt.Errorf("failed to connect to coordinator: %w", err)
+			}
+			defer conn.Close()
+
+			csrPEM, keyPEM, err := hgtls.NewCSR(name)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			resp, err := pb.NewBuildServiceClient(conn).Enroll(ctx, &pb.EnrollRequest{
+				JoinToken: joinToken,
+				CsrPem:    csrPEM,
+				Name:      name,
+			})
+			if err != nil {
+				return fmt.Errorf("enrolment failed: %w", err)
+			}
+			if !hgtls.CertMatchesFingerprint(resp.CaPem, caFingerprint) {
+				return fmt.Errorf("coordinator returned a CA that does not match %s", caFingerprint)
+			}
+
+			certFile, keyFile, err := hgtls.WriteKeyPair(outDir, resp.Name, resp.CertificatePem, keyPEM)
+			if err != nil {
+				return err
+			}
+			caFile := filepath.Join(outDir, hgtls.CACertFile)
+			if err := os.WriteFile(caFile, resp.CaPem, 0o644); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "Enrolled as %q. Start the worker with:\n", resp.Name)
+			fmt.Printf("hg-worker serve --coordinator %s --tls-cert %s --tls-key %s --tls-ca %s\n", coordinator, certFile, keyFile, caFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&coordinator, "coordinator", "", "Coordinator address")
+	cmd.Flags().StringVar(&joinToken, "join-token", "", "One-time join token (default: $"+joinTokenEnv+")")
+	cmd.Flags().StringVar(&caFingerprint, "ca-fingerprint", "", "SHA-256 fingerprint of the coordinator's CA (sha256:...)")
+	cmd.Flags().StringVar(&name, "name", "", "Name to request, for the coordinator's log (default: hostname)")
+	cmd.Flags().StringVar(&outDir, "out-dir", ".", "Directory to write the certificate, key and CA to")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Enrolment timeout")
+	_ = cmd.MarkFlagRequired("coordinator")
+	_ = cmd.MarkFlagRequired("ca-fingerprint")
+	return cmd
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -25,6 +26,7 @@ import (
 	"github.com/h3nr1-d14z/hybridgrid/internal/logging"
 	observabilitymetrics "github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
 	"github.com/h3nr1-d14z/hybridgrid/internal/observability/tracing"
+	hgtls "github.com/h3nr1-d14z/hybridgrid/internal/security/tls"
 	workerserver "github.com/h3nr1-d14z/hybridgrid/internal/worker/server"
 )
 
@@ -71,6 +73,7 @@ It executes build tasks received from the coordinator.`,
 			}
 
 			coordinator, _ := cmd.Flags().GetString("coordinator")
+			noRegister, _ := cmd.Flags().GetBool("no-register")
 			port, _ := cmd.Flags().GetInt("port")
 			httpPort, _ := cmd.Flags().GetInt("http-port")
 			token, _ := cmd.Flags().GetString("token")
@@ -81,6 +84,7 @@ It executes build tasks received from the coordinator.`,
 			tlsKey, _ := cmd.Flags().GetString("tls-key")
 			tlsCA, _ := cmd.Flags().GetString("tls-ca")
 			tlsRequireClientCert, _ := cmd.Flags().GetBool("tls-require-client-cert")
+			tlsReloadInterval, _ := cmd.Flags().GetDuration("tls-reload-interval")
 			tracingEnable, _ := cmd.Flags().GetBool("tracing-enable")
 			tracingEndpoint, _ := cmd.Flags().GetString("tracing-endpoint")
 			tracingSampleRate, _ := cmd.Flags().GetFloat64("tracing-sample-rate")
@@ -100,7 +104,9 @@ It executes build tasks received from the coordinator.`,
 			}
 
 			// Resolve coordinator address
-			if coordinator == "" {
+			if noRegister {
+				coordinator = ""
+			} else if coordinator == "" {
 				log.Info().Dur("timeout", discoveryTimeout).Msg("No coordinator specified, trying mDNS discovery")
 
 				browser := mdns.NewCoordBrowser(mdns.CoordBrowserConfig{
@@ -195,6 +201,12 @@ It executes build tasks received from the coordinator.`,
 					Str("cert", tlsCert).
 					Bool("mtls", tlsRequireClientCert).
 					Msg("TLS enabled")
+
+				// Pick up rotated certificates without a restart
+				if tlsReloadInterval > 0 {
+					go hgtls.WatchReloads(ctx, tlsReloadInterval)
+				}
+				hgtls.ReloadOnSignal(ctx, syscall.SIGHUP)
 			} else {
 				log.Debug().Msg("TLS disabled")
 			}
@@ -205,52 +217,64 @@ It executes build tasks received from the coordinator.`,
 			caps.MaxParallelTasks = int32(maxParallel)
 			caps.Version = version
 
-			// Connect to coordinator
-			cli, err := client.New(client.Config{
-				Address:       coordinator,
-				AuthToken:     token,
-				Timeout:       30 * time.Second,
-				Insecure:      !cfg.TLS.Enabled,
-				EnableTracing: tracingEnable,
-				TLS:           cfg.TLS,
-			})
-			if err != nil {
-				return fmt.Errorf("failed to connect to coordinator: %w", err)
-			}
-			defer cli.Close()
-
-			// Determine worker address to advertise
-			workerAddr := advertiseAddr
-			if workerAddr == "" {
-				// Auto-detect outbound IP by checking which interface routes to coordinator
-				if ip := getOutboundIP(coordinator); ip != "" {
-					workerAddr = fmt.Sprintf("%s:%d", ip, port)
-					log.Info().Str("detected_ip", ip).Msg("Auto-detected outbound IP for advertisement")
-				} else {
-					workerAddr = fmt.Sprintf("%s:%d", hostname, port)
-					log.Warn().Str("fallback", workerAddr).Msg("Could not detect IP, using hostname")
+			// Register with the coordinator, unless coordinators list this
+			// worker as a static worker and probe it themselves
+			var (
+				cli               *client.Client
+				regReq            *pb.HandshakeRequest
+				heartbeatInterval time.Duration
+			)
+			if noRegister {
+				log.Info().Msg("Not registering with a coordinator; waiting to be probed as a static worker")
+			} else {
+				// Connect to coordinator
+				cli, err = client.New(client.Config{
+					Address:       coordinator,
+					AuthToken:     token,
+					Timeout:       30 * time.Second,
+					Insecure:      !cfg.TLS.Enabled,
+					EnableTracing: tracingEnable,
+					TLS:           cfg.TLS,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to connect to coordinator: %w", err)
+				}
+				defer cli.Close()
+
+				// Determine worker address to advertise
+				workerAddr := advertiseAddr
+				if workerAddr == "" {
+					// Auto-detect outbound IP by checking which interface routes to coordinator
+					if ip := getOutboundIP(coordinator); ip != "" {
+						workerAddr = fmt.Sprintf("%s:%d", ip, port)
+						log.Info().Str("detected_ip", ip).Msg("Auto-detected outbound IP for advertisement")
+					} else {
+						workerAddr = fmt.Sprintf("%s:%d", hostname, port)
+						log.Warn().Str("fallback", workerAddr).Msg("Could not detect IP, using hostname")
+					}
 				}
-			}
 
-			// Register with coordinator
-			regReq := &pb.HandshakeRequest{
-				Capabilities:  caps,
-				AuthToken:     token,
-				WorkerAddress: workerAddr,
-			}
-			resp, err := cli.Handshake(context.Background(), regReq)
-			if err != nil {
-				return fmt.Errorf("handshake failed: %w", err)
-			}
+				// Register with coordinator
+				regReq = &pb.HandshakeRequest{
+					Capabilities:  caps,
+					AuthToken:     token,
+					WorkerAddress: workerAddr,
+				}
+				resp, err := cli.Handshake(context.Background(), regReq)
+				if err != nil {
+					return fmt.Errorf("handshake failed: %w", err)
+				}
 
-			if !resp.Accepted {
-				return fmt.Errorf("worker registration rejected: %s", resp.Message)
-			}
+				if !resp.Accepted {
+					return fmt.Errorf("worker registration rejected: %s", resp.Message)
+				}
 
-			log.Info().
-				Str("worker_id", resp.AssignedWorkerId).
-				Int32("heartbeat_interval", resp.HeartbeatIntervalSeconds).
-				Msg("Worker registered successfully")
+				log.Info().
+					Str("worker_id", resp.AssignedWorkerId).
+					Int32("heartbeat_interval", resp.HeartbeatIntervalSeconds).
+					Msg("Worker registered successfully")
+				heartbeatInterval = time.Duration(resp.HeartbeatIntervalSeconds) * time.Second
+			}
 
 			// Handle shutdown signals
 			sigCh := make(chan os.Signal, 1)
@@ -291,27 +315,23 @@ It executes build tasks received from the coordinator.`,
 				}
 			}()
 
-			// Start heartbeat loop using Handshake to update registry
-			heartbeatInterval := time.Duration(resp.HeartbeatIntervalSeconds) * time.Second
-			go func() {
-				ticker := time.NewTicker(heartbeatInterval)
-				defer ticker.Stop()
-
-				for {
-					select {
-					case <-ticker.C:
-						// Re-send handshake to update heartbeat in coordinator registry
-						hResp, err := cli.Handshake(context.Background(), regReq)
-						if err != nil {
-							log.Warn().Err(err).Msg("Heartbeat failed")
-						} else {
-							log.Debug().Bool("accepted", hResp.Accepted).Msg("Heartbeat sent")
+			// Start heartbeat loop using Handshake to update registry,
+			// followed by a load report
+			if cli != nil {
+				go func() {
+					ticker := time.NewTicker(heartbeatInterval)
+					defer ticker.Stop()
+
+					for {
+						select {
+						case <-ticker.C:
+							sendHeartbeat(cli, regReq, srv)
+						case <-stopHeartbeatCh:
+							return
 						}
-					case <-stopHeartbeatCh:
-						return
 					}
-				}
-			}()
+				}()
+			}
 
 			select {
 			case sig := <-sigCh:
@@ -331,7 +351,8 @@ It executes build tasks received from the coordinator.`,
 
 	serveCmd.Flags().Int("port", 50052, "Worker gRPC port")
 	serveCmd.Flags().Int("http-port", 9090, "Worker HTTP/metrics port")
-	serveCmd.Flags().String("coordinator", "", "Coordinator address (empty for mDNS auto-discovery)")
+	serveCmd.Flags().String("coordinator", "", "Coordinator address, or comma-separated addresses for failover (empty for mDNS auto-discovery)")
+	serveCmd.Flags().Bool("no-register", false, "Don't register with a coordinator; serve coordinators that list this worker in their workers: file (e.g. a WAN build farm)")
 	serveCmd.Flags().String("advertise-address", "", "Address to advertise to coordinator (default: hostname:port)")
 	serveCmd.Flags().String("token", "", "Authentication token")
 	serveCmd.Flags().Int("max-parallel", 0, "Max parallel tasks (0 = auto)")
@@ -340,6 +361,7 @@ It executes build tasks received from the coordinator.`,
 	serveCmd.Flags().String("tls-key", "", "Path to TLS private key file (PEM format)")
 	serveCmd.Flags().String("tls-ca", "", "Path to CA certificate for client verification (mTLS)")
 	serveCmd.Flags().Bool("tls-require-client-cert", false, "Require client certificates (mTLS)")
+	serveCmd.Flags().Duration("tls-reload-interval", hgtls.DefaultReloadInterval, "How often to check TLS certificate files for changes (0 disables; SIGHUP always reloads)")
 	serveCmd.Flags().Bool("tracing-enable", false, "Enable OpenTelemetry tracing")
 	serveCmd.Flags().String("tracing-endpoint", "localhost:4317", "OTLP gRPC endpoint")
 	serveCmd.Flags().Float64("tracing-sample-rate", 0.1, "Tracing sample rate (0.0-1.0)")
@@ -348,7 +370,7 @@ It executes build tasks received from the coordinator.`,
 	serveCmd.Flags().Duration("tracing-timeout", 10*time.Second, "Timeout for OTLP exports")
 	serveCmd.Flags().Int("tracing-batch-size", 512, "Max spans to batch before export")
 
-	rootCmd.AddCommand(versionCmd, serveCmd)
+	rootCmd.AddCommand(versionCmd, serveCmd, newEnrollCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -356,9 +378,38 @@ It executes build tasks received from the coordinator.`,
 	}
 }
 
+// sendHeartbeat re-sends the handshake to update the worker's heartbeat in
+// the coordinator registry, then reports the host load so the scheduler
+// can avoid workers busy with other work.
+func sendHeartbeat(cli *client.Client, regReq *pb.HandshakeRequest, srv *workerserver.Server) {
+	hResp, err := cli.Handshake(context.Background(), regReq)
+	if err != nil {
+		log.Warn().Err(err).Msg("Heartbeat failed")
+		return
+	}
+	log.Debug().Bool("accepted", hResp.Accepted).Msg("Heartbeat sent")
+	if !hResp.Accepted {
+		return
+	}
+
+	load := srv.Load()
+	if _, err := cli.ReportLoad(context.Background(), hResp.AssignedWorkerId, load); err != nil {
+		// Coordinators older than the worker do not implement ReportLoad
+		log.Debug().Err(err).Msg("Load report failed")
+		return
+	}
+	log.Debug().
+		Float32("cpu_percent", load.CpuUsagePercent).
+		Float32("memory_percent", load.MemoryUsagePercent).
+		Msg("Load reported")
+}
+
 // getOutboundIP returns the preferred outbound IP for reaching the target address.
 // This finds which local IP would be used to connect to the coordinator.
+// For a comma-separated list of coordinators the first one is used.
 func getOutboundIP(target string) string {
+	target, _, _ = strings.Cut(target, ",")
+	target = strings.TrimSpace(target)
 	host := target
 	if parsedHost, _, err := net.SplitHostPort(target); err == nil {
 		host = parsedHost
@@ -154,12 +154,7 @@ func (s *workerService) Execute(args []string, r <-chan svc.ChangeRequest, chang
 		for {
 			select {
 			case <-ticker.C:
-				hResp, err := cli.Handshake(context.Background(), regReq)
-				if err != nil {
-					log.Warn().Err(err).Msg("Heartbeat failed")
-				} else {
-					log.Debug().Bool("accepted", hResp.Accepted).Msg("Heartbeat sent")
-				}
+				sendHeartbeat(cli, regReq, srv)
 			case <-stopHeartbeat:
 				return
 			}
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -19,7 +21,11 @@ import (
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
 	"github.com/h3nr1-d14z/hybridgrid/internal/cache"
 	"github.com/h3nr1-d14z/hybridgrid/internal/cli/build"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/cargo"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/cocos"
 	"github.com/h3nr1-d14z/hybridgrid/internal/cli/flutter"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/golang"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/node"
 	"github.com/h3nr1-d14z/hybridgrid/internal/cli/output"
 	"github.com/h3nr1-d14z/hybridgrid/internal/cli/unity"
 	"github.com/h3nr1-d14z/hybridgrid/internal/compiler"
@@ -34,10 +40,14 @@ import (
 
 var (
 	version           = "v0.0.0-dev"
+	authToken         string
+	buildGraphFile    string
 	cfgFile           string
 	coordinator       string
+	distributedLink   bool
 	insecure          bool
 	noFallback        bool
+	remoteCache       string
 	timeout           time.Duration
 	verbose           bool
 	tlsCert           string
@@ -50,9 +60,13 @@ var (
 )
 
 const (
-	wrapperCCEnv  = "HG_WRAP_CC_MODE"
-	wrapperCXXEnv = "HG_WRAP_CXX_MODE"
-	noFallbackEnv = "HG_NO_FALLBACK"
+	wrapperCCEnv   = "HG_WRAP_CC_MODE"
+	wrapperCXXEnv  = "HG_WRAP_CXX_MODE"
+	noFallbackEnv  = "HG_NO_FALLBACK"
+	remoteCacheEnv = "HG_REMOTE_CACHE"
+	distLinkEnv    = "HG_DISTRIBUTED_LINK"
+	tokenEnv       = "HG_TOKEN"
+	buildIDEnv     = "HG_BUILD_ID"
 )
 
 func main() {
@@ -83,8 +97,12 @@ Quick start:
 
 Environment:
   HG_COORDINATOR    Coordinator address (default: auto-discover via mDNS)
+  HG_TOKEN          API token identifying this client to the coordinator
   HG_CC             C compiler to use (default: gcc)
-  HG_CXX            C++ compiler to use (default: g++)`,
+  HG_CXX            C++ compiler to use (default: g++)
+  HG_REMOTE_CACHE   Bazel HTTP remote cache URL shared as a second-level cache
+  HG_DISTRIBUTED_LINK=1  Run link steps on a worker with the same OS/arch
+  HG_BUILD_ID       Build whose graph was submitted with --build-graph (set by make/ninja/wrap)`,
 		Run: func(cmd *cobra.Command, args []string) {
 			cmd.Help()
 		},
@@ -162,9 +180,13 @@ Environment:
 	}
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVarP(&coordinator, "coordinator", "C", "", "coordinator address (auto-discover if empty)")
+	rootCmd.PersistentFlags().StringVarP(&coordinator, "coordinator", "C", "", "coordinator address, or comma-separated addresses for failover (auto-discover if empty)")
+	rootCmd.PersistentFlags().StringVar(&authToken, "token", "", "API token identifying this client to the coordinator (or set HG_TOKEN)")
 	rootCmd.PersistentFlags().BoolVar(&insecure, "insecure", true, "use insecure connection")
 	rootCmd.PersistentFlags().BoolVar(&noFallback, "no-fallback", false, "disable local fallback when coordinator is unavailable")
+	rootCmd.PersistentFlags().StringVar(&remoteCache, "remote-cache", "", "Bazel HTTP remote cache URL (/ac + /cas) used behind the local cache")
+	rootCmd.PersistentFlags().BoolVar(&distributedLink, "distributed-link", false, "ship link steps (object files and static libraries) to a worker with the same OS/arch")
+	rootCmd.PersistentFlags().StringVar(&buildGraphFile, "build-graph", "", "Makefile or compile_commands.json whose critical path make/ninja/wrap compile first")
 	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 2*time.Minute, "connection timeout")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().StringVar(&tlsCert, "tls-cert", "", "Path to TLS certificate file (PEM format)")
@@ -182,6 +204,10 @@ Environment:
 		newBuildCmd(),
 		newFlutterCmd(),
 		newUnityCmd(),
+		newCocosCmd(),
+		newCargoCmd(),
+		newGoCmd(),
+		newNodeCmd(),
 		newConfigCmd(),
 		newCacheCmd(),
 		newGraphCmd(),
@@ -194,7 +220,17 @@ Environment:
 		newWrapCmd(),
 	)
 
-	if err := rootCmd.Execute(); err != nil {
+	// Ctrl-C cancels in-flight requests; remote builds are cancelled on
+	// the coordinator and its workers rather than left running. A second
+	// Ctrl-C exits immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	err = rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -457,6 +493,62 @@ func newUnityCmd() *cobra.Command {
 	return unity.NewCommand(deps)
 }
 
+func newCocosCmd() *cobra.Command {
+	deps := cocos.Dependencies{
+		CoordinatorAddr: getCoordinatorAddress,
+		NewClient: func(address string, requestTimeout time.Duration) (cocos.BuildClient, error) {
+			cfg := newClientConfig(address, requestTimeout)
+			return client.New(cfg)
+		},
+		RequestTimeout: 25 * time.Minute,
+		BuildTimeout:   20 * time.Minute,
+	}
+
+	return cocos.NewCommand(deps)
+}
+
+func newCargoCmd() *cobra.Command {
+	deps := cargo.Dependencies{
+		CoordinatorAddr: getCoordinatorAddress,
+		NewClient: func(address string, requestTimeout time.Duration) (cargo.BuildClient, error) {
+			cfg := newClientConfig(address, requestTimeout)
+			return client.New(cfg)
+		},
+		RequestTimeout: 25 * time.Minute,
+		BuildTimeout:   20 * time.Minute,
+	}
+
+	return cargo.NewCommand(deps)
+}
+
+func newGoCmd() *cobra.Command {
+	deps := golang.Dependencies{
+		CoordinatorAddr: getCoordinatorAddress,
+		NewClient: func(address string, requestTimeout time.Duration) (golang.BuildClient, error) {
+			cfg := newClientConfig(address, requestTimeout)
+			return client.New(cfg)
+		},
+		RequestTimeout: 25 * time.Minute,
+		BuildTimeout:   20 * time.Minute,
+	}
+
+	return golang.NewCommand(deps)
+}
+
+func newNodeCmd() *cobra.Command {
+	deps := node.Dependencies{
+		CoordinatorAddr: getCoordinatorAddress,
+		NewClient: func(address string, requestTimeout time.Duration) (node.BuildClient, error) {
+			cfg := newClientConfig(address, requestTimeout)
+			return client.New(cfg)
+		},
+		RequestTimeout: 25 * time.Minute,
+		BuildTimeout:   20 * time.Minute,
+	}
+
+	return node.NewCommand(deps)
+}
+
 // detectCompiler returns an appropriate compiler based on file extension.
 func detectCompiler(file string) string {
 	ext := strings.ToLower(filepath.Ext(file))
@@ -760,7 +852,7 @@ Examples:
 		DisableFlagParsing: true,
 		SilenceUsage:       true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCompiler("gcc", "HG_CC", args)
+			return runCompiler(cmd.Context(), "gcc", "HG_CC", args)
 		},
 	}
 }
@@ -778,7 +870,7 @@ Examples:
 		DisableFlagParsing: true,
 		SilenceUsage:       true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCompiler("g++", "HG_CXX", args)
+			return runCompiler(cmd.Context(), "g++", "HG_CXX", args)
 		},
 	}
 }
@@ -788,12 +880,18 @@ Examples:
 func filterHgbuildFlags(args []string) []string {
 	var filtered []string
 	skipNext := false
+	tokenNext := false
 
 	for _, arg := range args {
 		if skipNext {
 			skipNext = false
 			continue
 		}
+		if tokenNext {
+			authToken = arg
+			tokenNext = false
+			continue
+		}
 
 		// Skip hgbuild-specific flags
 		switch {
@@ -802,6 +900,12 @@ func filterHgbuildFlags(args []string) []string {
 			continue
 		case strings.HasPrefix(arg, "--coordinator="):
 			continue
+		case arg == "--token":
+			tokenNext = true
+			continue
+		case strings.HasPrefix(arg, "--token="):
+			authToken = strings.TrimPrefix(arg, "--token=")
+			continue
 		case arg == "--timeout":
 			skipNext = true
 			continue
@@ -812,6 +916,9 @@ func filterHgbuildFlags(args []string) []string {
 		case arg == "--no-fallback":
 			noFallback = true
 			continue
+		case arg == "--distributed-link":
+			distributedLink = true
+			continue
 		case arg == "--verbose" || arg == "-v":
 			// Set verbose flag and skip
 			verbose = true
@@ -825,7 +932,7 @@ func filterHgbuildFlags(args []string) []string {
 }
 
 // runCompiler handles distributed compilation for cc/c++ commands.
-func runCompiler(defaultCompiler, envVar string, args []string) error {
+func runCompiler(ctx context.Context, defaultCompiler, envVar string, args []string) error {
 	// Check HG_VERBOSE environment variable
 	if os.Getenv("HG_VERBOSE") == "1" {
 		verbose = true
@@ -848,13 +955,24 @@ func runCompiler(defaultCompiler, envVar string, args []string) error {
 		return fmt.Errorf("failed to parse compiler arguments")
 	}
 
-	// Check if this is distributable
+	// Check if this is distributable. Links are only shipped when opted in
+	// and when they depend on nothing but object files and static libraries.
+	var link *compiler.RemoteLink
 	if !parsed.IsDistributable() {
-		// Run locally for linking, preprocessing-only, etc.
-		if verbose {
-			fmt.Fprintf(os.Stderr, "[local] Non-distributable: %s\n", strings.Join(fullArgs, " "))
+		if parsed.IsLink && distributedLinkEnabled() {
+			var err error
+			link, err = compiler.PlanRemoteLink(fullArgs)
+			if err != nil && verbose {
+				fmt.Fprintf(os.Stderr, "[local] Link not distributable: %v\n", err)
+			}
+		}
+		if link == nil {
+			// Run locally for linking, preprocessing-only, etc.
+			if verbose {
+				fmt.Fprintf(os.Stderr, "[local] Non-distributable: %s\n", strings.Join(fullArgs, " "))
+			}
+			return runLocalCompiler(comp, compilerArgs)
 		}
-		return runLocalCompiler(comp, compilerArgs)
 	}
 
 	// Get coordinator address (auto-discover if not specified)
@@ -880,6 +998,7 @@ func runCompiler(defaultCompiler, envVar string, args []string) error {
 	cfg.Timeout = 5 * time.Minute
 	cfg.FallbackEnabled = fallbackEnabled()
 	cfg.Verbose = verbose
+	cfg.RemoteCacheURL = getRemoteCacheURL()
 
 	svc, err := build.New(cfg)
 	if err != nil {
@@ -905,6 +1024,10 @@ func runCompiler(defaultCompiler, envVar string, args []string) error {
 	}
 	svc.SetClient(c)
 
+	if link != nil {
+		return runRemoteLink(ctx, svc, link, comp, compilerArgs)
+	}
+
 	// Determine output file
 	outputFile := parsed.OutputFile
 	if outputFile == "" && len(parsed.InputFiles) > 0 {
@@ -921,9 +1044,9 @@ func runCompiler(defaultCompiler, envVar string, args []string) error {
 		Args:       parsed,
 		TargetArch: parseArch(parsed.TargetArch),
 		Timeout:    5 * time.Minute,
+		BuildID:    os.Getenv(buildIDEnv),
 	}
 
-	ctx := context.Background()
 	result, err := svc.Build(ctx, req)
 	if err != nil {
 		return err
@@ -957,6 +1080,57 @@ func runCompiler(defaultCompiler, envVar string, args []string) error {
 	return nil
 }
 
+// runRemoteLink links on a worker and writes the result, rerunning the
+// original command locally if the remote link fails for any reason.
+func runRemoteLink(ctx context.Context, svc *build.Service, link *compiler.RemoteLink, comp string, compilerArgs []string) error {
+	result, err := svc.Link(ctx, &build.LinkRequest{
+		TaskID:  generateTaskID(),
+		Link:    link,
+		Timeout: 5 * time.Minute,
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !fallbackEnabled() {
+			return fmt.Errorf("remote link failed and fallback disabled: %w", err)
+		}
+		if verbose {
+			fmt.Fprintf(os.Stderr, "[local] Remote link failed: %v\n", err)
+		}
+		return runLocalCompiler(comp, compilerArgs)
+	}
+
+	// Linker warnings
+	if result.Stderr != "" {
+		fmt.Fprint(os.Stderr, result.Stderr)
+	}
+
+	if err := writeOutputFile(link.Output, result.ObjectFile); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	// Linked outputs are executables or shared libraries.
+	outputPath, err := resolveOutputPath(link.Output)
+	if err != nil {
+		return err
+	}
+	// #nosec G302,G703 -- outputPath is validated by resolveOutputPath.
+	if err := os.Chmod(outputPath, 0755); err != nil {
+		return fmt.Errorf("failed to make output executable: %w", err)
+	}
+
+	if verbose {
+		status := "[remote]"
+		if result.CacheHit {
+			status = "[cache]"
+		}
+		fmt.Fprintf(os.Stderr, "%s link %d inputs -> %s (%.2fs)\n",
+			status, len(link.Inputs), link.Output, result.Duration.Seconds())
+	}
+
+	return nil
+}
+
 // runLocalCompiler runs the compiler locally (for non-distributable operations).
 func runLocalCompiler(compiler string, args []string) error {
 	resolvedCompiler, err := resolveLocalCompilerPath(compiler)
@@ -1090,6 +1264,31 @@ func isSafeExecutableName(name string) bool {
 	return true
 }
 
+// getRemoteCacheURL returns the remote cache URL from the flag or env.
+func getRemoteCacheURL() string {
+	if remoteCache != "" {
+		return remoteCache
+	}
+	return os.Getenv(remoteCacheEnv)
+}
+
+// distributedLinkEnabled reports whether link steps should be sent to
+// workers, from the flag or env.
+func distributedLinkEnabled() bool {
+	if distributedLink {
+		return true
+	}
+	return strings.TrimSpace(os.Getenv(distLinkEnv)) == "1"
+}
+
+// getAuthToken returns the API token from the flag or env.
+func getAuthToken() string {
+	if authToken != "" {
+		return authToken
+	}
+	return os.Getenv(tokenEnv)
+}
+
 // getCoordinatorAddress gets the coordinator address from flags, env, or mDNS.
 func getCoordinatorAddress() string {
 	// 1. Check command-line flag
@@ -1134,7 +1333,8 @@ func newMakeCmd() *cobra.Command {
 Examples:
   hgbuild make
   hgbuild make -j8
-  hgbuild make clean all`,
+  hgbuild make clean all
+  hgbuild make --build-graph Makefile -j8`,
 		DisableFlagParsing: true,
 		SilenceUsage:       true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -1151,7 +1351,8 @@ func newNinjaCmd() *cobra.Command {
 
 Examples:
   hgbuild ninja
-  hgbuild ninja -j8`,
+  hgbuild ninja -j8
+  hgbuild ninja --build-graph compile_commands.json`,
 		DisableFlagParsing: true,
 		SilenceUsage:       true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -1185,7 +1386,8 @@ Examples:
 func filterHgbuildWrapperFlags(args []string) []string {
 	filtered := make([]string, 0, len(args))
 
-	for _, arg := range args {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 		switch {
 		case arg == "--no-fallback":
 			noFallback = true
@@ -1198,6 +1400,16 @@ func filterHgbuildWrapperFlags(args []string) []string {
 				}
 				continue
 			}
+		case arg == "--distributed-link":
+			distributedLink = true
+			continue
+		case arg == "--build-graph" && i+1 < len(args):
+			i++
+			buildGraphFile = args[i]
+			continue
+		case strings.HasPrefix(arg, "--build-graph="):
+			buildGraphFile = strings.TrimPrefix(arg, "--build-graph=")
+			continue
 		}
 
 		filtered = append(filtered, arg)
@@ -1246,6 +1458,27 @@ func wrapBuildCommand(command string, args []string) error {
 		env = setEnv(env, noFallbackEnv, "1")
 	}
 
+	if remoteCache != "" {
+		env = setEnv(env, remoteCacheEnv, remoteCache)
+	}
+
+	if authToken != "" {
+		env = setEnv(env, tokenEnv, authToken)
+	}
+
+	if distributedLink {
+		env = setEnv(env, distLinkEnv, "1")
+	}
+
+	if buildGraphFile != "" {
+		buildID, err := submitBuildGraph(buildGraphFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: build graph not submitted, compiling in arrival order: %v\n", err)
+		} else {
+			env = setEnv(env, buildIDEnv, buildID)
+		}
+	}
+
 	// Pass through verbose flag
 	if verbose {
 		env = setEnv(env, "HG_VERBOSE", "1")
@@ -1274,6 +1507,49 @@ func wrapBuildCommand(command string, args []string) error {
 	return cmd.Run()
 }
 
+// submitBuildGraph parses the Makefile or compile_commands.json at path
+// and sends the critical-path rank of each translation unit to the
+// coordinator. It returns the build ID compiles name to be ranked.
+func submitBuildGraph(path string) (string, error) {
+	coordAddr := getCoordinatorAddress()
+	if coordAddr == "" {
+		return "", fmt.Errorf("no coordinator available")
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	g, err := graph.NewParser().ParseAuto(absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	ranks := g.CriticalPathRanks(filepath.Dir(absPath))
+	if len(ranks) == 0 {
+		return "", fmt.Errorf("no source files found in %s", path)
+	}
+
+	c, err := client.New(newClientConfig(coordAddr, timeout))
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	buildID := "build-" + hex.EncodeToString(b)
+	accepted, err := c.SubmitBuildGraph(context.Background(), buildID, ranks)
+	if err != nil {
+		return "", err
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "[wrap] Submitted %d translation unit ranks as %s\n", accepted, buildID)
+	}
+	return buildID, nil
+}
+
 func injectWrappedCompilerMode() {
 	mode := ""
 	switch {
@@ -1328,6 +1604,7 @@ func fallbackEnabled() bool {
 func newClientConfig(address string, requestTimeout time.Duration) client.Config {
 	clientCfg := client.Config{
 		Address:       address,
+		AuthToken:     getAuthToken(),
 		Insecure:      insecure,
 		Timeout:       requestTimeout,
 		EnableTracing: tracingEnable,
@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestFallbackEnabled_Default(t *testing.T) {
@@ -78,6 +79,55 @@ func TestFilterHgbuildWrapperFlags_SetsNoFallback(t *testing.T) {
 	}
 }
 
+func TestDistributedLinkEnabled(t *testing.T) {
+	distributedLink = false
+	defer func() {
+		distributedLink = false
+	}()
+
+	t.Setenv(distLinkEnv, "")
+	if distributedLinkEnabled() {
+		t.Fatal("distributed linking should be off by default")
+	}
+
+	filtered := filterHgbuildFlags([]string{"--distributed-link", "main.o", "-o", "app"})
+	if containsArg(filtered, "--distributed-link") {
+		t.Fatal("expected --distributed-link to be removed from compiler args")
+	}
+	if !distributedLinkEnabled() {
+		t.Fatal("expected --distributed-link to enable distributed linking")
+	}
+
+	distributedLink = false
+	t.Setenv(distLinkEnv, "1")
+	if !distributedLinkEnabled() {
+		t.Fatalf("expected %s=1 to enable distributed linking", distLinkEnv)
+	}
+}
+
+func TestGetAuthToken(t *testing.T) {
+	authToken = ""
+	defer func() {
+		authToken = ""
+	}()
+
+	t.Setenv(tokenEnv, "from-env")
+	if got := getAuthToken(); got != "from-env" {
+		t.Fatalf("getAuthToken() = %q, want token from %s", got, tokenEnv)
+	}
+
+	filtered := filterHgbuildFlags([]string{"--token", "from-flag", "-c", "main.c"})
+	if containsArg(filtered, "--token") || containsArg(filtered, "from-flag") {
+		t.Fatalf("expected --token and its value to be removed, got %v", filtered)
+	}
+	if got := getAuthToken(); got != "from-flag" {
+		t.Fatalf("getAuthToken() = %q, want the --token value", got)
+	}
+	if got := newClientConfig("localhost:9000", time.Second).AuthToken; got != "from-flag" {
+		t.Fatalf("client config token = %q, want the --token value", got)
+	}
+}
+
 func TestFilterHgbuildWrapperFlags_WrapCommandStillPresent(t *testing.T) {
 	noFallback = false
 	defer func() {
@@ -210,6 +210,7 @@ const (
 	TaskStatus_STATUS_COMPLETED   TaskStatus = 3
 	TaskStatus_STATUS_FAILED      TaskStatus = 4
 	TaskStatus_STATUS_TIMEOUT     TaskStatus = 5
+	TaskStatus_STATUS_CANCELLED   TaskStatus = 6
 )
 
 // Enum value maps for TaskStatus.
@@ -221,6 +222,7 @@ var (
 		3: "STATUS_COMPLETED",
 		4: "STATUS_FAILED",
 		5: "STATUS_TIMEOUT",
+		6: "STATUS_CANCELLED",
 	}
 	TaskStatus_value = map[string]int32{
 		"STATUS_UNSPECIFIED": 0,
@@ -229,6 +231,7 @@ var (
 		"STATUS_COMPLETED":   3,
 		"STATUS_FAILED":      4,
 		"STATUS_TIMEOUT":     5,
+		"STATUS_CANCELLED":   6,
 	}
 )
 
@@ -259,6 +262,55 @@ func (TaskStatus) EnumDescriptor() ([]byte, []int) {
 	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{3}
 }
 
+type LogStream int32
+
+const (
+	LogStream_LOG_STREAM_UNSPECIFIED LogStream = 0
+	LogStream_LOG_STREAM_STDOUT      LogStream = 1
+	LogStream_LOG_STREAM_STDERR      LogStream = 2
+)
+
+// Enum value maps for LogStream.
+var (
+	LogStream_name = map[int32]string{
+		0: "LOG_STREAM_UNSPECIFIED",
+		1: "LOG_STREAM_STDOUT",
+		2: "LOG_STREAM_STDERR",
+	}
+	LogStream_value = map[string]int32{
+		"LOG_STREAM_UNSPECIFIED": 0,
+		"LOG_STREAM_STDOUT":      1,
+		"LOG_STREAM_STDERR":      2,
+	}
+)
+
+func (x LogStream) Enum() *LogStream {
+	p := new(LogStream)
+	*p = x
+	return p
+}
+
+func (x LogStream) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LogStream) Descriptor() protoreflect.EnumDescriptor {
+	return file_hybridgrid_v1_build_proto_enumTypes[4].Descriptor()
+}
+
+func (LogStream) Type() protoreflect.EnumType {
+	return &file_hybridgrid_v1_build_proto_enumTypes[4]
+}
+
+func (x LogStream) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LogStream.Descriptor instead.
+func (LogStream) EnumDescriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{4}
+}
+
 type CppConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Compiler      string                 `protobuf:"bytes,1,opt,name=compiler,proto3" json:"compiler,omitempty"` // gcc, g++, clang, clang++
@@ -597,7 +649,9 @@ type GoConfig struct {
 	Goos          string                 `protobuf:"bytes,2,opt,name=goos,proto3" json:"goos,omitempty"`
 	Goarch        string                 `protobuf:"bytes,3,opt,name=goarch,proto3" json:"goarch,omitempty"`
 	BuildTags     []string               `protobuf:"bytes,4,rep,name=build_tags,json=buildTags,proto3" json:"build_tags,omitempty"`
-	Ldflags       map[string]string      `protobuf:"bytes,5,rep,name=ldflags,proto3" json:"ldflags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Ldflags       map[string]string      `protobuf:"bytes,5,rep,name=ldflags,proto3" json:"ldflags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // "-X" values by symbol; keys starting with "-" are raw flags
+	Packages      []string               `protobuf:"bytes,6,rep,name=packages,proto3" json:"packages,omitempty"`                                                                         // package patterns, e.g. "./cmd/..." (default ".")
+	GoSumHash     string                 `protobuf:"bytes,7,opt,name=go_sum_hash,json=goSumHash,proto3" json:"go_sum_hash,omitempty"`                                                    // SHA-256 of go.sum, part of the cache key
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -667,12 +721,28 @@ func (x *GoConfig) GetLdflags() map[string]string {
 	return nil
 }
 
+func (x *GoConfig) GetPackages() []string {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+func (x *GoConfig) GetGoSumHash() string {
+	if x != nil {
+		return x.GoSumHash
+	}
+	return ""
+}
+
 type NodeConfig struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	NodeVersion    string                 `protobuf:"bytes,1,opt,name=node_version,json=nodeVersion,proto3" json:"node_version,omitempty"`
 	PackageManager string                 `protobuf:"bytes,2,opt,name=package_manager,json=packageManager,proto3" json:"package_manager,omitempty"` // npm, yarn, pnpm
 	BuildScript    string                 `protobuf:"bytes,3,opt,name=build_script,json=buildScript,proto3" json:"build_script,omitempty"`          // e.g., "build", "build:prod"
 	EnvVars        map[string]string      `protobuf:"bytes,4,rep,name=env_vars,json=envVars,proto3" json:"env_vars,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	OutputDir      string                 `protobuf:"bytes,5,opt,name=output_dir,json=outputDir,proto3" json:"output_dir,omitempty"`          // build output to return (default: dist, build or out)
+	LockfileHash   string                 `protobuf:"bytes,6,opt,name=lockfile_hash,json=lockfileHash,proto3" json:"lockfile_hash,omitempty"` // SHA-256 of the package manager lockfile, part of the cache key
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -735,6 +805,20 @@ func (x *NodeConfig) GetEnvVars() map[string]string {
 	return nil
 }
 
+func (x *NodeConfig) GetOutputDir() string {
+	if x != nil {
+		return x.OutputDir
+	}
+	return ""
+}
+
+func (x *NodeConfig) GetLockfileHash() string {
+	if x != nil {
+		return x.LockfileHash
+	}
+	return ""
+}
+
 type CppCapability struct {
 	state        protoimpl.MessageState `protogen:"open.v1"`
 	Compilers    []string               `protobuf:"bytes,1,rep,name=compilers,proto3" json:"compilers,omitempty"` // [gcc, clang, cl.exe]
@@ -1491,6 +1575,7 @@ type BuildRequest struct {
 	DockerImage    string `protobuf:"bytes,20,opt,name=docker_image,json=dockerImage,proto3" json:"docker_image,omitempty"`           // Override default image
 	TimeoutSeconds int32  `protobuf:"varint,21,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // Override default timeout
 	Priority       int32  `protobuf:"varint,22,opt,name=priority,proto3" json:"priority,omitempty"`                                   // Task priority (0-100)
+	DeferArtifacts bool   `protobuf:"varint,23,opt,name=defer_artifacts,json=deferArtifacts,proto3" json:"defer_artifacts,omitempty"` // Omit artifacts bytes; download with FetchArtifacts
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -1651,6 +1736,13 @@ func (x *BuildRequest) GetPriority() int32 {
 	return 0
 }
 
+func (x *BuildRequest) GetDeferArtifacts() bool {
+	if x != nil {
+		return x.DeferArtifacts
+	}
+	return false
+}
+
 type isBuildRequest_Config interface {
 	isBuildRequest_Config()
 }
@@ -1703,6 +1795,7 @@ type ArtifactInfo struct {
 	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"` // Path within archive
 	SizeBytes     int64                  `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
 	Checksum      string                 `protobuf:"bytes,4,opt,name=checksum,proto3" json:"checksum,omitempty"` // SHA256
+	Mode          uint32                 `protobuf:"varint,5,opt,name=mode,proto3" json:"mode,omitempty"`        // Unix permission bits (0 = unknown)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1765,6 +1858,13 @@ func (x *ArtifactInfo) GetChecksum() string {
 	return ""
 }
 
+func (x *ArtifactInfo) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
 type BuildResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Result
@@ -1977,6 +2077,9 @@ type BuildMetadata struct {
 	TotalSizeBytes int64                  `protobuf:"varint,5,opt,name=total_size_bytes,json=totalSizeBytes,proto3" json:"total_size_bytes,omitempty"`
 	ConfigJson     string                 `protobuf:"bytes,6,opt,name=config_json,json=configJson,proto3" json:"config_json,omitempty"` // Config as JSON for flexibility
 	DockerImage    string                 `protobuf:"bytes,7,opt,name=docker_image,json=dockerImage,proto3" json:"docker_image,omitempty"`
+	TimeoutSeconds int32                  `protobuf:"varint,8,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`  // Override default timeout
+	Priority       int32                  `protobuf:"varint,9,opt,name=priority,proto3" json:"priority,omitempty"`                                    // Task priority (0-100)
+	DeferArtifacts bool                   `protobuf:"varint,10,opt,name=defer_artifacts,json=deferArtifacts,proto3" json:"defer_artifacts,omitempty"` // Omit artifacts bytes; download with FetchArtifacts
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -2060,6 +2163,153 @@ func (x *BuildMetadata) GetDockerImage() string {
 	return ""
 }
 
+func (x *BuildMetadata) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *BuildMetadata) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *BuildMetadata) GetDeferArtifacts() bool {
+	if x != nil {
+		return x.DeferArtifacts
+	}
+	return false
+}
+
+type FetchArtifactsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchArtifactsRequest) Reset() {
+	*x = FetchArtifactsRequest{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchArtifactsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchArtifactsRequest) ProtoMessage() {}
+
+func (x *FetchArtifactsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchArtifactsRequest.ProtoReflect.Descriptor instead.
+func (*FetchArtifactsRequest) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *FetchArtifactsRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+type ArtifactChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ArtifactChunk_Info
+	//	*ArtifactChunk_Data
+	Payload       isArtifactChunk_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArtifactChunk) Reset() {
+	*x = ArtifactChunk{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArtifactChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArtifactChunk) ProtoMessage() {}
+
+func (x *ArtifactChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArtifactChunk.ProtoReflect.Descriptor instead.
+func (*ArtifactChunk) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ArtifactChunk) GetPayload() isArtifactChunk_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ArtifactChunk) GetInfo() *ArtifactInfo {
+	if x != nil {
+		if x, ok := x.Payload.(*ArtifactChunk_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *ArtifactChunk) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*ArtifactChunk_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+type isArtifactChunk_Payload interface {
+	isArtifactChunk_Payload()
+}
+
+type ArtifactChunk_Info struct {
+	Info *ArtifactInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"` // Starts the next artifact
+}
+
+type ArtifactChunk_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"` // Content of the current artifact
+}
+
+func (*ArtifactChunk_Info) isArtifactChunk_Payload() {}
+
+func (*ArtifactChunk_Data) isArtifactChunk_Payload() {}
+
 type CompileRequest struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
 	TaskId             string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
@@ -2078,13 +2328,22 @@ type CompileRequest struct {
 	SourceFilename string            `protobuf:"bytes,21,opt,name=source_filename,json=sourceFilename,proto3" json:"source_filename,omitempty"`                                                                     // Original filename with extension (e.g., "main.cpp")
 	IncludeFiles   map[string][]byte `protobuf:"bytes,22,rep,name=include_files,json=includeFiles,proto3" json:"include_files,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Bundled project headers (path -> content)
 	IncludePaths   []string          `protobuf:"bytes,23,rep,name=include_paths,json=includePaths,proto3" json:"include_paths,omitempty"`                                                                           // -I paths for headers
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Link mode: compiler_args are the link flags and link_inputs holds the
+	// object files and static libraries they reference (name -> content).
+	// The linked output comes back in CompileResponse.object_file.
+	Link       bool              `protobuf:"varint,24,opt,name=link,proto3" json:"link,omitempty"`
+	LinkInputs map[string][]byte `protobuf:"bytes,25,rep,name=link_inputs,json=linkInputs,proto3" json:"link_inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Critical-path hints: the build registered with SubmitBuildGraph and the
+	// absolute path of the source file, looked up in that build's ranks.
+	BuildId       string `protobuf:"bytes,26,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	SourcePath    string `protobuf:"bytes,27,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CompileRequest) Reset() {
 	*x = CompileRequest{}
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[22]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2096,7 +2355,7 @@ func (x *CompileRequest) String() string {
 func (*CompileRequest) ProtoMessage() {}
 
 func (x *CompileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[22]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2109,7 +2368,7 @@ func (x *CompileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CompileRequest.ProtoReflect.Descriptor instead.
 func (*CompileRequest) Descriptor() ([]byte, []int) {
-	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{22}
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *CompileRequest) GetTaskId() string {
@@ -2217,6 +2476,34 @@ func (x *CompileRequest) GetIncludePaths() []string {
 	return nil
 }
 
+func (x *CompileRequest) GetLink() bool {
+	if x != nil {
+		return x.Link
+	}
+	return false
+}
+
+func (x *CompileRequest) GetLinkInputs() map[string][]byte {
+	if x != nil {
+		return x.LinkInputs
+	}
+	return nil
+}
+
+func (x *CompileRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *CompileRequest) GetSourcePath() string {
+	if x != nil {
+		return x.SourcePath
+	}
+	return ""
+}
+
 type CompileResponse struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	Status            TaskStatus             `protobuf:"varint,1,opt,name=status,proto3,enum=hybridgrid.v1.TaskStatus" json:"status,omitempty"`
@@ -2234,7 +2521,7 @@ type CompileResponse struct {
 
 func (x *CompileResponse) Reset() {
 	*x = CompileResponse{}
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[23]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2246,7 +2533,7 @@ func (x *CompileResponse) String() string {
 func (*CompileResponse) ProtoMessage() {}
 
 func (x *CompileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[23]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2259,7 +2546,7 @@ func (x *CompileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CompileResponse.ProtoReflect.Descriptor instead.
 func (*CompileResponse) Descriptor() ([]byte, []int) {
-	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{23}
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *CompileResponse) GetStatus() TaskStatus {
@@ -2333,7 +2620,7 @@ type HealthRequest struct {
 
 func (x *HealthRequest) Reset() {
 	*x = HealthRequest{}
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[24]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2345,7 +2632,7 @@ func (x *HealthRequest) String() string {
 func (*HealthRequest) ProtoMessage() {}
 
 func (x *HealthRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[24]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2358,7 +2645,7 @@ func (x *HealthRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
 func (*HealthRequest) Descriptor() ([]byte, []int) {
-	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{24}
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{26}
 }
 
 type HealthResponse struct {
@@ -2375,7 +2662,7 @@ type HealthResponse struct {
 
 func (x *HealthResponse) Reset() {
 	*x = HealthResponse{}
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[25]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2387,7 +2674,7 @@ func (x *HealthResponse) String() string {
 func (*HealthResponse) ProtoMessage() {}
 
 func (x *HealthResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[25]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2400,7 +2687,7 @@ func (x *HealthResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
 func (*HealthResponse) Descriptor() ([]byte, []int) {
-	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{25}
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *HealthResponse) GetHealthy() bool {
@@ -2453,7 +2740,7 @@ type WorkerStatusRequest struct {
 
 func (x *WorkerStatusRequest) Reset() {
 	*x = WorkerStatusRequest{}
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[26]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2465,7 +2752,7 @@ func (x *WorkerStatusRequest) String() string {
 func (*WorkerStatusRequest) ProtoMessage() {}
 
 func (x *WorkerStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[26]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2478,7 +2765,7 @@ func (x *WorkerStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkerStatusRequest.ProtoReflect.Descriptor instead.
 func (*WorkerStatusRequest) Descriptor() ([]byte, []int) {
-	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{26}
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{28}
 }
 
 type WorkerStatusResponse struct {
@@ -2492,7 +2779,7 @@ type WorkerStatusResponse struct {
 
 func (x *WorkerStatusResponse) Reset() {
 	*x = WorkerStatusResponse{}
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[27]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2504,7 +2791,7 @@ func (x *WorkerStatusResponse) String() string {
 func (*WorkerStatusResponse) ProtoMessage() {}
 
 func (x *WorkerStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[27]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2517,7 +2804,7 @@ func (x *WorkerStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkerStatusResponse.ProtoReflect.Descriptor instead.
 func (*WorkerStatusResponse) Descriptor() ([]byte, []int) {
-	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{27}
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *WorkerStatusResponse) GetWorkers() []*WorkerStatusResponse_WorkerInfo {
@@ -2551,7 +2838,7 @@ type WorkersForBuildRequest struct {
 
 func (x *WorkersForBuildRequest) Reset() {
 	*x = WorkersForBuildRequest{}
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[28]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2563,7 +2850,7 @@ func (x *WorkersForBuildRequest) String() string {
 func (*WorkersForBuildRequest) ProtoMessage() {}
 
 func (x *WorkersForBuildRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[28]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2576,7 +2863,7 @@ func (x *WorkersForBuildRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkersForBuildRequest.ProtoReflect.Descriptor instead.
 func (*WorkersForBuildRequest) Descriptor() ([]byte, []int) {
-	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{28}
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *WorkersForBuildRequest) GetBuildType() BuildType {
@@ -2603,7 +2890,7 @@ type WorkersForBuildResponse struct {
 
 func (x *WorkersForBuildResponse) Reset() {
 	*x = WorkersForBuildResponse{}
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[29]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2615,7 +2902,7 @@ func (x *WorkersForBuildResponse) String() string {
 func (*WorkersForBuildResponse) ProtoMessage() {}
 
 func (x *WorkersForBuildResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[29]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2628,7 +2915,7 @@ func (x *WorkersForBuildResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkersForBuildResponse.ProtoReflect.Descriptor instead.
 func (*WorkersForBuildResponse) Descriptor() ([]byte, []int) {
-	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{29}
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *WorkersForBuildResponse) GetWorkerIds() []string {
@@ -2655,7 +2942,7 @@ type ReportCacheHitRequest struct {
 
 func (x *ReportCacheHitRequest) Reset() {
 	*x = ReportCacheHitRequest{}
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[30]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2667,7 +2954,7 @@ func (x *ReportCacheHitRequest) String() string {
 func (*ReportCacheHitRequest) ProtoMessage() {}
 
 func (x *ReportCacheHitRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[30]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2680,7 +2967,7 @@ func (x *ReportCacheHitRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReportCacheHitRequest.ProtoReflect.Descriptor instead.
 func (*ReportCacheHitRequest) Descriptor() ([]byte, []int) {
-	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{30}
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *ReportCacheHitRequest) GetHits() int32 {
@@ -2700,7 +2987,7 @@ type ReportCacheHitResponse struct {
 
 func (x *ReportCacheHitResponse) Reset() {
 	*x = ReportCacheHitResponse{}
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[31]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2712,7 +2999,7 @@ func (x *ReportCacheHitResponse) String() string {
 func (*ReportCacheHitResponse) ProtoMessage() {}
 
 func (x *ReportCacheHitResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[31]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2725,7 +3012,7 @@ func (x *ReportCacheHitResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReportCacheHitResponse.ProtoReflect.Descriptor instead.
 func (*ReportCacheHitResponse) Descriptor() ([]byte, []int) {
-	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{31}
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *ReportCacheHitResponse) GetAcknowledged() bool {
@@ -2735,27 +3022,691 @@ func (x *ReportCacheHitResponse) GetAcknowledged() bool {
 	return false
 }
 
-type WorkerStatusResponse_WorkerInfo struct {
-	state               protoimpl.MessageState `protogen:"open.v1"`
-	WorkerId            string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
-	Host                string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
-	Port                int32                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
-	NativeArch          Architecture           `protobuf:"varint,4,opt,name=native_arch,json=nativeArch,proto3,enum=hybridgrid.v1.Architecture" json:"native_arch,omitempty"`
-	CpuCores            int32                  `protobuf:"varint,5,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
-	MemoryBytes         int64                  `protobuf:"varint,6,opt,name=memory_bytes,json=memoryBytes,proto3" json:"memory_bytes,omitempty"`
-	ActiveTasks         int32                  `protobuf:"varint,7,opt,name=active_tasks,json=activeTasks,proto3" json:"active_tasks,omitempty"`
-	TotalTasksCompleted int64                  `protobuf:"varint,8,opt,name=total_tasks_completed,json=totalTasksCompleted,proto3" json:"total_tasks_completed,omitempty"`
-	AvgLatencyMs        float32                `protobuf:"fixed32,9,opt,name=avg_latency_ms,json=avgLatencyMs,proto3" json:"avg_latency_ms,omitempty"`
-	CircuitState        string                 `protobuf:"bytes,10,opt,name=circuit_state,json=circuitState,proto3" json:"circuit_state,omitempty"`          // CLOSED, HALF_OPEN, OPEN
-	DiscoverySource     string                 `protobuf:"bytes,11,opt,name=discovery_source,json=discoverySource,proto3" json:"discovery_source,omitempty"` // LAN, WAN
-	LastHeartbeatUnix   int64                  `protobuf:"varint,12,opt,name=last_heartbeat_unix,json=lastHeartbeatUnix,proto3" json:"last_heartbeat_unix,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
-}
+// Request to cancel a task
+type CancelTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelTaskRequest) Reset() {
+	*x = CancelTaskRequest{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTaskRequest) ProtoMessage() {}
+
+func (x *CancelTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTaskRequest.ProtoReflect.Descriptor instead.
+func (*CancelTaskRequest) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CancelTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// Response for task cancellation
+type CancelTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cancelled     bool                   `protobuf:"varint,1,opt,name=cancelled,proto3" json:"cancelled,omitempty"` // False if no queued or running task has this ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelTaskResponse) Reset() {
+	*x = CancelTaskResponse{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTaskResponse) ProtoMessage() {}
+
+func (x *CancelTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTaskResponse.ProtoReflect.Descriptor instead.
+func (*CancelTaskResponse) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CancelTaskResponse) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+// Request to follow a task's output
+type WatchTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTaskRequest) Reset() {
+	*x = WatchTaskRequest{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTaskRequest) ProtoMessage() {}
+
+func (x *WatchTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTaskRequest.ProtoReflect.Descriptor instead.
+func (*WatchTaskRequest) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *WatchTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// One line of task output, streamed while the task runs
+type TaskLogLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Stream        LogStream              `protobuf:"varint,2,opt,name=stream,proto3,enum=hybridgrid.v1.LogStream" json:"stream,omitempty"`
+	Line          string                 `protobuf:"bytes,3,opt,name=line,proto3" json:"line,omitempty"`                                   // Without the trailing newline
+	TimestampMs   int64                  `protobuf:"varint,4,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"` // When the worker read the line
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskLogLine) Reset() {
+	*x = TaskLogLine{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskLogLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskLogLine) ProtoMessage() {}
+
+func (x *TaskLogLine) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskLogLine.ProtoReflect.Descriptor instead.
+func (*TaskLogLine) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *TaskLogLine) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskLogLine) GetStream() LogStream {
+	if x != nil {
+		return x.Stream
+	}
+	return LogStream_LOG_STREAM_UNSPECIFIED
+}
+
+func (x *TaskLogLine) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+func (x *TaskLogLine) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+// Request for a certificate signed by the coordinator's CA
+type EnrollRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JoinToken     string                 `protobuf:"bytes,1,opt,name=join_token,json=joinToken,proto3" json:"join_token,omitempty"`
+	CsrPem        []byte                 `protobuf:"bytes,2,opt,name=csr_pem,json=csrPem,proto3" json:"csr_pem,omitempty"`                // PKCS#10 request; the private key stays on the worker
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                                  // Informational; the join token fixes the name
+	IpAddresses   []string               `protobuf:"bytes,4,rep,name=ip_addresses,json=ipAddresses,proto3" json:"ip_addresses,omitempty"` // Must be among the join token's IP addresses
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollRequest) Reset() {
+	*x = EnrollRequest{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollRequest) ProtoMessage() {}
+
+func (x *EnrollRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollRequest.ProtoReflect.Descriptor instead.
+func (*EnrollRequest) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *EnrollRequest) GetJoinToken() string {
+	if x != nil {
+		return x.JoinToken
+	}
+	return ""
+}
+
+func (x *EnrollRequest) GetCsrPem() []byte {
+	if x != nil {
+		return x.CsrPem
+	}
+	return nil
+}
+
+func (x *EnrollRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EnrollRequest) GetIpAddresses() []string {
+	if x != nil {
+		return x.IpAddresses
+	}
+	return nil
+}
+
+// Signed certificate for an enrolled worker
+type EnrollResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CertificatePem []byte                 `protobuf:"bytes,1,opt,name=certificate_pem,json=certificatePem,proto3" json:"certificate_pem,omitempty"`
+	CaPem          []byte                 `protobuf:"bytes,2,opt,name=ca_pem,json=caPem,proto3" json:"ca_pem,omitempty"` // CA to trust for the coordinator and other workers
+	Name           string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                // Name the certificate was issued for
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EnrollResponse) Reset() {
+	*x = EnrollResponse{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollResponse) ProtoMessage() {}
+
+func (x *EnrollResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollResponse.ProtoReflect.Descriptor instead.
+func (*EnrollResponse) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *EnrollResponse) GetCertificatePem() []byte {
+	if x != nil {
+		return x.CertificatePem
+	}
+	return nil
+}
+
+func (x *EnrollResponse) GetCaPem() []byte {
+	if x != nil {
+		return x.CaPem
+	}
+	return nil
+}
+
+func (x *EnrollResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Critical-path hints submitted at the start of a build
+type SubmitBuildGraphRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BuildId       string                 `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"` // Chosen by the client, sent again in each CompileRequest
+	Ranks         []*TranslationUnitRank `protobuf:"bytes,2,rep,name=ranks,proto3" json:"ranks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitBuildGraphRequest) Reset() {
+	*x = SubmitBuildGraphRequest{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitBuildGraphRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitBuildGraphRequest) ProtoMessage() {}
+
+func (x *SubmitBuildGraphRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitBuildGraphRequest.ProtoReflect.Descriptor instead.
+func (*SubmitBuildGraphRequest) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SubmitBuildGraphRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *SubmitBuildGraphRequest) GetRanks() []*TranslationUnitRank {
+	if x != nil {
+		return x.Ranks
+	}
+	return nil
+}
+
+// Upward rank of one translation unit in the build graph
+type TranslationUnitRank struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SourcePath    string                 `protobuf:"bytes,1,opt,name=source_path,json=sourcePath,proto3" json:"source_path,omitempty"`   // Absolute path of the source file
+	UpwardRank    float64                `protobuf:"fixed64,2,opt,name=upward_rank,json=upwardRank,proto3" json:"upward_rank,omitempty"` // Longest path to the end of the build, scaled to (0, 1]
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranslationUnitRank) Reset() {
+	*x = TranslationUnitRank{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranslationUnitRank) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranslationUnitRank) ProtoMessage() {}
+
+func (x *TranslationUnitRank) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranslationUnitRank.ProtoReflect.Descriptor instead.
+func (*TranslationUnitRank) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *TranslationUnitRank) GetSourcePath() string {
+	if x != nil {
+		return x.SourcePath
+	}
+	return ""
+}
+
+func (x *TranslationUnitRank) GetUpwardRank() float64 {
+	if x != nil {
+		return x.UpwardRank
+	}
+	return 0
+}
+
+// Response for a build graph submission
+type SubmitBuildGraphResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      int32                  `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"` // Number of ranks stored
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitBuildGraphResponse) Reset() {
+	*x = SubmitBuildGraphResponse{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitBuildGraphResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitBuildGraphResponse) ProtoMessage() {}
+
+func (x *SubmitBuildGraphResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitBuildGraphResponse.ProtoReflect.Descriptor instead.
+func (*SubmitBuildGraphResponse) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *SubmitBuildGraphResponse) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+// Load of a worker's host, including work that is not Hybrid-Grid's
+type WorkerLoad struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	CpuUsagePercent    float32                `protobuf:"fixed32,1,opt,name=cpu_usage_percent,json=cpuUsagePercent,proto3" json:"cpu_usage_percent,omitempty"`          // Busy share of all cores since the previous sample, 0-100
+	MemoryUsagePercent float32                `protobuf:"fixed32,2,opt,name=memory_usage_percent,json=memoryUsagePercent,proto3" json:"memory_usage_percent,omitempty"` // Memory in use, excluding reclaimable caches, 0-100
+	DiskFreeBytes      int64                  `protobuf:"varint,3,opt,name=disk_free_bytes,json=diskFreeBytes,proto3" json:"disk_free_bytes,omitempty"`                 // Free space where builds run
+	LoadAverage_1M     float64                `protobuf:"fixed64,4,opt,name=load_average_1m,json=loadAverage1m,proto3" json:"load_average_1m,omitempty"`                // Zero where the OS has no load average
+	RunningContainers  int32                  `protobuf:"varint,5,opt,name=running_containers,json=runningContainers,proto3" json:"running_containers,omitempty"`       // Running Docker containers; zero without Docker
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WorkerLoad) Reset() {
+	*x = WorkerLoad{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerLoad) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerLoad) ProtoMessage() {}
+
+func (x *WorkerLoad) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerLoad.ProtoReflect.Descriptor instead.
+func (*WorkerLoad) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *WorkerLoad) GetCpuUsagePercent() float32 {
+	if x != nil {
+		return x.CpuUsagePercent
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetMemoryUsagePercent() float32 {
+	if x != nil {
+		return x.MemoryUsagePercent
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetDiskFreeBytes() int64 {
+	if x != nil {
+		return x.DiskFreeBytes
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetLoadAverage_1M() float64 {
+	if x != nil {
+		return x.LoadAverage_1M
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetRunningContainers() int32 {
+	if x != nil {
+		return x.RunningContainers
+	}
+	return 0
+}
+
+// Load report sent by a worker with each heartbeat
+type ReportLoadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Load          *WorkerLoad            `protobuf:"bytes,2,opt,name=load,proto3" json:"load,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportLoadRequest) Reset() {
+	*x = ReportLoadRequest{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportLoadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportLoadRequest) ProtoMessage() {}
+
+func (x *ReportLoadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportLoadRequest.ProtoReflect.Descriptor instead.
+func (*ReportLoadRequest) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ReportLoadRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *ReportLoadRequest) GetLoad() *WorkerLoad {
+	if x != nil {
+		return x.Load
+	}
+	return nil
+}
+
+// Response for a load report
+type ReportLoadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"` // False if the worker is not registered; it should handshake again
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportLoadResponse) Reset() {
+	*x = ReportLoadResponse{}
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportLoadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportLoadResponse) ProtoMessage() {}
+
+func (x *ReportLoadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportLoadResponse.ProtoReflect.Descriptor instead.
+func (*ReportLoadResponse) Descriptor() ([]byte, []int) {
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ReportLoadResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+type WorkerStatusResponse_WorkerInfo struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId            string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Host                string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Port                int32                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	NativeArch          Architecture           `protobuf:"varint,4,opt,name=native_arch,json=nativeArch,proto3,enum=hybridgrid.v1.Architecture" json:"native_arch,omitempty"`
+	CpuCores            int32                  `protobuf:"varint,5,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	MemoryBytes         int64                  `protobuf:"varint,6,opt,name=memory_bytes,json=memoryBytes,proto3" json:"memory_bytes,omitempty"`
+	ActiveTasks         int32                  `protobuf:"varint,7,opt,name=active_tasks,json=activeTasks,proto3" json:"active_tasks,omitempty"`
+	TotalTasksCompleted int64                  `protobuf:"varint,8,opt,name=total_tasks_completed,json=totalTasksCompleted,proto3" json:"total_tasks_completed,omitempty"`
+	AvgLatencyMs        float32                `protobuf:"fixed32,9,opt,name=avg_latency_ms,json=avgLatencyMs,proto3" json:"avg_latency_ms,omitempty"`
+	CircuitState        string                 `protobuf:"bytes,10,opt,name=circuit_state,json=circuitState,proto3" json:"circuit_state,omitempty"`          // CLOSED, HALF_OPEN, OPEN
+	DiscoverySource     string                 `protobuf:"bytes,11,opt,name=discovery_source,json=discoverySource,proto3" json:"discovery_source,omitempty"` // LAN, WAN
+	LastHeartbeatUnix   int64                  `protobuf:"varint,12,opt,name=last_heartbeat_unix,json=lastHeartbeatUnix,proto3" json:"last_heartbeat_unix,omitempty"`
+	// Full capabilities, reported by workers so a coordinator listing them
+	// as static workers can register them without a handshake.
+	Capabilities  *WorkerCapabilities `protobuf:"bytes,13,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Load          *WorkerLoad         `protobuf:"bytes,14,opt,name=load,proto3" json:"load,omitempty"` // Latest host load, if the worker reported one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
 func (x *WorkerStatusResponse_WorkerInfo) Reset() {
 	*x = WorkerStatusResponse_WorkerInfo{}
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[38]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2767,7 +3718,7 @@ func (x *WorkerStatusResponse_WorkerInfo) String() string {
 func (*WorkerStatusResponse_WorkerInfo) ProtoMessage() {}
 
 func (x *WorkerStatusResponse_WorkerInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_hybridgrid_v1_build_proto_msgTypes[38]
+	mi := &file_hybridgrid_v1_build_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2780,7 +3731,7 @@ func (x *WorkerStatusResponse_WorkerInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WorkerStatusResponse_WorkerInfo.ProtoReflect.Descriptor instead.
 func (*WorkerStatusResponse_WorkerInfo) Descriptor() ([]byte, []int) {
-	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{27, 0}
+	return file_hybridgrid_v1_build_proto_rawDescGZIP(), []int{29, 0}
 }
 
 func (x *WorkerStatusResponse_WorkerInfo) GetWorkerId() string {
@@ -2867,6 +3818,20 @@ func (x *WorkerStatusResponse_WorkerInfo) GetLastHeartbeatUnix() int64 {
 	return 0
 }
 
+func (x *WorkerStatusResponse_WorkerInfo) GetCapabilities() *WorkerCapabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *WorkerStatusResponse_WorkerInfo) GetLoad() *WorkerLoad {
+	if x != nil {
+		return x.Load
+	}
+	return nil
+}
+
 var File_hybridgrid_v1_build_proto protoreflect.FileDescriptor
 
 const file_hybridgrid_v1_build_proto_rawDesc = "" +
@@ -2909,7 +3874,7 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"\ttoolchain\x18\x01 \x01(\tR\ttoolchain\x12\x16\n" +
 	"\x06target\x18\x02 \x01(\tR\x06target\x12\x1a\n" +
 	"\bfeatures\x18\x03 \x03(\tR\bfeatures\x12\x18\n" +
-	"\arelease\x18\x04 \x01(\bR\arelease\"\xf0\x01\n" +
+	"\arelease\x18\x04 \x01(\bR\arelease\"\xac\x02\n" +
 	"\bGoConfig\x12\x1d\n" +
 	"\n" +
 	"go_version\x18\x01 \x01(\tR\tgoVersion\x12\x12\n" +
@@ -2917,16 +3882,21 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"\x06goarch\x18\x03 \x01(\tR\x06goarch\x12\x1d\n" +
 	"\n" +
 	"build_tags\x18\x04 \x03(\tR\tbuildTags\x12>\n" +
-	"\aldflags\x18\x05 \x03(\v2$.hybridgrid.v1.GoConfig.LdflagsEntryR\aldflags\x1a:\n" +
+	"\aldflags\x18\x05 \x03(\v2$.hybridgrid.v1.GoConfig.LdflagsEntryR\aldflags\x12\x1a\n" +
+	"\bpackages\x18\x06 \x03(\tR\bpackages\x12\x1e\n" +
+	"\vgo_sum_hash\x18\a \x01(\tR\tgoSumHash\x1a:\n" +
 	"\fLdflagsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xfa\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbe\x02\n" +
 	"\n" +
 	"NodeConfig\x12!\n" +
 	"\fnode_version\x18\x01 \x01(\tR\vnodeVersion\x12'\n" +
 	"\x0fpackage_manager\x18\x02 \x01(\tR\x0epackageManager\x12!\n" +
 	"\fbuild_script\x18\x03 \x01(\tR\vbuildScript\x12A\n" +
-	"\benv_vars\x18\x04 \x03(\v2&.hybridgrid.v1.NodeConfig.EnvVarsEntryR\aenvVars\x1a:\n" +
+	"\benv_vars\x18\x04 \x03(\v2&.hybridgrid.v1.NodeConfig.EnvVarsEntryR\aenvVars\x12\x1d\n" +
+	"\n" +
+	"output_dir\x18\x05 \x01(\tR\toutputDir\x12#\n" +
+	"\rlockfile_hash\x18\x06 \x01(\tR\flockfileHash\x1a:\n" +
 	"\fEnvVarsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf1\x01\n" +
@@ -2992,7 +3962,7 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"\baccepted\x18\x01 \x01(\bR\baccepted\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
 	"\x12assigned_worker_id\x18\x03 \x01(\tR\x10assignedWorkerId\x12<\n" +
-	"\x1aheartbeat_interval_seconds\x18\x04 \x01(\x05R\x18heartbeatIntervalSeconds\"\x9a\x06\n" +
+	"\x1aheartbeat_interval_seconds\x18\x04 \x01(\x05R\x18heartbeatIntervalSeconds\"\xc3\x06\n" +
 	"\fBuildRequest\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1f\n" +
 	"\vsource_hash\x18\x02 \x01(\tR\n" +
@@ -3014,14 +3984,16 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"nodeConfig\x12!\n" +
 	"\fdocker_image\x18\x14 \x01(\tR\vdockerImage\x12'\n" +
 	"\x0ftimeout_seconds\x18\x15 \x01(\x05R\x0etimeoutSeconds\x12\x1a\n" +
-	"\bpriority\x18\x16 \x01(\x05R\bpriorityB\b\n" +
-	"\x06config\"q\n" +
+	"\bpriority\x18\x16 \x01(\x05R\bpriority\x12'\n" +
+	"\x0fdefer_artifacts\x18\x17 \x01(\bR\x0edeferArtifactsB\b\n" +
+	"\x06config\"\x85\x01\n" +
 	"\fArtifactInfo\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
 	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1d\n" +
 	"\n" +
 	"size_bytes\x18\x03 \x01(\x03R\tsizeBytes\x12\x1a\n" +
-	"\bchecksum\x18\x04 \x01(\tR\bchecksum\"\xf3\x02\n" +
+	"\bchecksum\x18\x04 \x01(\tR\bchecksum\x12\x12\n" +
+	"\x04mode\x18\x05 \x01(\rR\x04mode\"\xf3\x02\n" +
 	"\rBuildResponse\x121\n" +
 	"\x06status\x18\x01 \x01(\x0e2\x19.hybridgrid.v1.TaskStatusR\x06status\x12\x1c\n" +
 	"\tartifacts\x18\x02 \x01(\fR\tartifacts\x12@\n" +
@@ -3039,7 +4011,7 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"BuildChunk\x12:\n" +
 	"\bmetadata\x18\x01 \x01(\v2\x1c.hybridgrid.v1.BuildMetadataH\x00R\bmetadata\x12#\n" +
 	"\fsource_chunk\x18\x02 \x01(\fH\x00R\vsourceChunkB\t\n" +
-	"\apayload\"\xb8\x02\n" +
+	"\apayload\"\xa6\x03\n" +
 	"\rBuildMetadata\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1f\n" +
 	"\vsource_hash\x18\x02 \x01(\tR\n" +
@@ -3050,7 +4022,17 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"\x10total_size_bytes\x18\x05 \x01(\x03R\x0etotalSizeBytes\x12\x1f\n" +
 	"\vconfig_json\x18\x06 \x01(\tR\n" +
 	"configJson\x12!\n" +
-	"\fdocker_image\x18\a \x01(\tR\vdockerImage\"\xd0\x05\n" +
+	"\fdocker_image\x18\a \x01(\tR\vdockerImage\x12'\n" +
+	"\x0ftimeout_seconds\x18\b \x01(\x05R\x0etimeoutSeconds\x12\x1a\n" +
+	"\bpriority\x18\t \x01(\x05R\bpriority\x12'\n" +
+	"\x0fdefer_artifacts\x18\n" +
+	" \x01(\bR\x0edeferArtifacts\"0\n" +
+	"\x15FetchArtifactsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"c\n" +
+	"\rArtifactChunk\x121\n" +
+	"\x04info\x18\x01 \x01(\v2\x1b.hybridgrid.v1.ArtifactInfoH\x00R\x04info\x12\x14\n" +
+	"\x04data\x18\x02 \x01(\fH\x00R\x04dataB\t\n" +
+	"\apayload\"\xaf\a\n" +
 	"\x0eCompileRequest\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1f\n" +
 	"\vsource_hash\x18\x02 \x01(\tR\n" +
@@ -3071,9 +4053,18 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"raw_source\x18\x14 \x01(\fR\trawSource\x12'\n" +
 	"\x0fsource_filename\x18\x15 \x01(\tR\x0esourceFilename\x12T\n" +
 	"\rinclude_files\x18\x16 \x03(\v2/.hybridgrid.v1.CompileRequest.IncludeFilesEntryR\fincludeFiles\x12#\n" +
-	"\rinclude_paths\x18\x17 \x03(\tR\fincludePaths\x1a?\n" +
+	"\rinclude_paths\x18\x17 \x03(\tR\fincludePaths\x12\x12\n" +
+	"\x04link\x18\x18 \x01(\bR\x04link\x12N\n" +
+	"\vlink_inputs\x18\x19 \x03(\v2-.hybridgrid.v1.CompileRequest.LinkInputsEntryR\n" +
+	"linkInputs\x12\x19\n" +
+	"\bbuild_id\x18\x1a \x01(\tR\abuildId\x12\x1f\n" +
+	"\vsource_path\x18\x1b \x01(\tR\n" +
+	"sourcePath\x1a?\n" +
 	"\x11IncludeFilesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01\x1a=\n" +
+	"\x0fLinkInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01\"\xc2\x02\n" +
 	"\x0fCompileResponse\x121\n" +
 	"\x06status\x18\x01 \x01(\x0e2\x19.hybridgrid.v1.TaskStatusR\x06status\x12\x1f\n" +
@@ -3095,11 +4086,11 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"\x11cpu_usage_percent\x18\x04 \x01(\x02R\x0fcpuUsagePercent\x120\n" +
 	"\x14memory_usage_percent\x18\x05 \x01(\x02R\x12memoryUsagePercent\x12%\n" +
 	"\x0euptime_seconds\x18\x06 \x01(\x03R\ruptimeSeconds\"\x15\n" +
-	"\x13WorkerStatusRequest\"\xfd\x04\n" +
+	"\x13WorkerStatusRequest\"\xf3\x05\n" +
 	"\x14WorkerStatusResponse\x12H\n" +
 	"\aworkers\x18\x01 \x03(\v2..hybridgrid.v1.WorkerStatusResponse.WorkerInfoR\aworkers\x12#\n" +
 	"\rtotal_workers\x18\x02 \x01(\x05R\ftotalWorkers\x12'\n" +
-	"\x0fhealthy_workers\x18\x03 \x01(\x05R\x0ehealthyWorkers\x1a\xcc\x03\n" +
+	"\x0fhealthy_workers\x18\x03 \x01(\x05R\x0ehealthyWorkers\x1a\xc2\x04\n" +
 	"\n" +
 	"WorkerInfo\x12\x1b\n" +
 	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x12\n" +
@@ -3115,7 +4106,9 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"\rcircuit_state\x18\n" +
 	" \x01(\tR\fcircuitState\x12)\n" +
 	"\x10discovery_source\x18\v \x01(\tR\x0fdiscoverySource\x12.\n" +
-	"\x13last_heartbeat_unix\x18\f \x01(\x03R\x11lastHeartbeatUnix\"\x99\x01\n" +
+	"\x13last_heartbeat_unix\x18\f \x01(\x03R\x11lastHeartbeatUnix\x12E\n" +
+	"\fcapabilities\x18\r \x01(\v2!.hybridgrid.v1.WorkerCapabilitiesR\fcapabilities\x12-\n" +
+	"\x04load\x18\x0e \x01(\v2\x19.hybridgrid.v1.WorkerLoadR\x04load\"\x99\x01\n" +
 	"\x16WorkersForBuildRequest\x127\n" +
 	"\n" +
 	"build_type\x18\x01 \x01(\x0e2\x18.hybridgrid.v1.BuildTypeR\tbuildType\x12F\n" +
@@ -3127,7 +4120,50 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"\x15ReportCacheHitRequest\x12\x12\n" +
 	"\x04hits\x18\x01 \x01(\x05R\x04hits\"<\n" +
 	"\x16ReportCacheHitResponse\x12\"\n" +
-	"\facknowledged\x18\x01 \x01(\bR\facknowledged*U\n" +
+	"\facknowledged\x18\x01 \x01(\bR\facknowledged\",\n" +
+	"\x11CancelTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"2\n" +
+	"\x12CancelTaskResponse\x12\x1c\n" +
+	"\tcancelled\x18\x01 \x01(\bR\tcancelled\"+\n" +
+	"\x10WatchTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"\x8f\x01\n" +
+	"\vTaskLogLine\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x120\n" +
+	"\x06stream\x18\x02 \x01(\x0e2\x18.hybridgrid.v1.LogStreamR\x06stream\x12\x12\n" +
+	"\x04line\x18\x03 \x01(\tR\x04line\x12!\n" +
+	"\ftimestamp_ms\x18\x04 \x01(\x03R\vtimestampMs\"~\n" +
+	"\rEnrollRequest\x12\x1d\n" +
+	"\n" +
+	"join_token\x18\x01 \x01(\tR\tjoinToken\x12\x17\n" +
+	"\acsr_pem\x18\x02 \x01(\fR\x06csrPem\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12!\n" +
+	"\fip_addresses\x18\x04 \x03(\tR\vipAddresses\"d\n" +
+	"\x0eEnrollResponse\x12'\n" +
+	"\x0fcertificate_pem\x18\x01 \x01(\fR\x0ecertificatePem\x12\x15\n" +
+	"\x06ca_pem\x18\x02 \x01(\fR\x05caPem\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"n\n" +
+	"\x17SubmitBuildGraphRequest\x12\x19\n" +
+	"\bbuild_id\x18\x01 \x01(\tR\abuildId\x128\n" +
+	"\x05ranks\x18\x02 \x03(\v2\".hybridgrid.v1.TranslationUnitRankR\x05ranks\"W\n" +
+	"\x13TranslationUnitRank\x12\x1f\n" +
+	"\vsource_path\x18\x01 \x01(\tR\n" +
+	"sourcePath\x12\x1f\n" +
+	"\vupward_rank\x18\x02 \x01(\x01R\n" +
+	"upwardRank\"6\n" +
+	"\x18SubmitBuildGraphResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\x05R\baccepted\"\xe9\x01\n" +
+	"\n" +
+	"WorkerLoad\x12*\n" +
+	"\x11cpu_usage_percent\x18\x01 \x01(\x02R\x0fcpuUsagePercent\x120\n" +
+	"\x14memory_usage_percent\x18\x02 \x01(\x02R\x12memoryUsagePercent\x12&\n" +
+	"\x0fdisk_free_bytes\x18\x03 \x01(\x03R\rdiskFreeBytes\x12&\n" +
+	"\x0fload_average_1m\x18\x04 \x01(\x01R\rloadAverage1m\x12-\n" +
+	"\x12running_containers\x18\x05 \x01(\x05R\x11runningContainers\"_\n" +
+	"\x11ReportLoadRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12-\n" +
+	"\x04load\x18\x02 \x01(\v2\x19.hybridgrid.v1.WorkerLoadR\x04load\"0\n" +
+	"\x12ReportLoadResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\bR\baccepted*U\n" +
 	"\fArchitecture\x12\x14\n" +
 	"\x10ARCH_UNSPECIFIED\x10\x00\x12\x0f\n" +
 	"\vARCH_X86_64\x10\x01\x12\x0e\n" +
@@ -3152,7 +4188,7 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"\x10PLATFORM_WINDOWS\x10\x04\x12\x12\n" +
 	"\x0ePLATFORM_LINUX\x10\x05\x12\x12\n" +
 	"\x0ePLATFORM_MACOS\x10\x06\x12\x12\n" +
-	"\x0ePLATFORM_WEBGL\x10\a*\x88\x01\n" +
+	"\x0ePLATFORM_WEBGL\x10\a*\x9e\x01\n" +
 	"\n" +
 	"TaskStatus\x12\x16\n" +
 	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x11\n" +
@@ -3160,16 +4196,29 @@ const file_hybridgrid_v1_build_proto_rawDesc = "" +
 	"\x0eSTATUS_RUNNING\x10\x02\x12\x14\n" +
 	"\x10STATUS_COMPLETED\x10\x03\x12\x11\n" +
 	"\rSTATUS_FAILED\x10\x04\x12\x12\n" +
-	"\x0eSTATUS_TIMEOUT\x10\x052\xa2\x05\n" +
+	"\x0eSTATUS_TIMEOUT\x10\x05\x12\x14\n" +
+	"\x10STATUS_CANCELLED\x10\x06*U\n" +
+	"\tLogStream\x12\x1a\n" +
+	"\x16LOG_STREAM_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11LOG_STREAM_STDOUT\x10\x01\x12\x15\n" +
+	"\x11LOG_STREAM_STDERR\x10\x022\x98\t\n" +
 	"\fBuildService\x12N\n" +
 	"\tHandshake\x12\x1f.hybridgrid.v1.HandshakeRequest\x1a .hybridgrid.v1.HandshakeResponse\x12B\n" +
 	"\x05Build\x12\x1b.hybridgrid.v1.BuildRequest\x1a\x1c.hybridgrid.v1.BuildResponse\x12H\n" +
-	"\vStreamBuild\x12\x19.hybridgrid.v1.BuildChunk\x1a\x1c.hybridgrid.v1.BuildResponse(\x01\x12H\n" +
+	"\vStreamBuild\x12\x19.hybridgrid.v1.BuildChunk\x1a\x1c.hybridgrid.v1.BuildResponse(\x01\x12V\n" +
+	"\x0eFetchArtifacts\x12$.hybridgrid.v1.FetchArtifactsRequest\x1a\x1c.hybridgrid.v1.ArtifactChunk0\x01\x12H\n" +
 	"\aCompile\x12\x1d.hybridgrid.v1.CompileRequest\x1a\x1e.hybridgrid.v1.CompileResponse\x12J\n" +
 	"\vHealthCheck\x12\x1c.hybridgrid.v1.HealthRequest\x1a\x1d.hybridgrid.v1.HealthResponse\x12Z\n" +
 	"\x0fGetWorkerStatus\x12\".hybridgrid.v1.WorkerStatusRequest\x1a#.hybridgrid.v1.WorkerStatusResponse\x12c\n" +
 	"\x12GetWorkersForBuild\x12%.hybridgrid.v1.WorkersForBuildRequest\x1a&.hybridgrid.v1.WorkersForBuildResponse\x12]\n" +
-	"\x0eReportCacheHit\x12$.hybridgrid.v1.ReportCacheHitRequest\x1a%.hybridgrid.v1.ReportCacheHitResponseBDZBgithub.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1;hybridgridv1b\x06proto3"
+	"\x0eReportCacheHit\x12$.hybridgrid.v1.ReportCacheHitRequest\x1a%.hybridgrid.v1.ReportCacheHitResponse\x12Q\n" +
+	"\n" +
+	"CancelTask\x12 .hybridgrid.v1.CancelTaskRequest\x1a!.hybridgrid.v1.CancelTaskResponse\x12J\n" +
+	"\tWatchTask\x12\x1f.hybridgrid.v1.WatchTaskRequest\x1a\x1a.hybridgrid.v1.TaskLogLine0\x01\x12E\n" +
+	"\x06Enroll\x12\x1c.hybridgrid.v1.EnrollRequest\x1a\x1d.hybridgrid.v1.EnrollResponse\x12c\n" +
+	"\x10SubmitBuildGraph\x12&.hybridgrid.v1.SubmitBuildGraphRequest\x1a'.hybridgrid.v1.SubmitBuildGraphResponse\x12Q\n" +
+	"\n" +
+	"ReportLoad\x12 .hybridgrid.v1.ReportLoadRequest\x1a!.hybridgrid.v1.ReportLoadResponseBDZBgithub.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1;hybridgridv1b\x06proto3"
 
 var (
 	file_hybridgrid_v1_build_proto_rawDescOnce sync.Once
@@ -3183,115 +4232,150 @@ func file_hybridgrid_v1_build_proto_rawDescGZIP() []byte {
 	return file_hybridgrid_v1_build_proto_rawDescData
 }
 
-var file_hybridgrid_v1_build_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_hybridgrid_v1_build_proto_msgTypes = make([]protoimpl.MessageInfo, 39)
+var file_hybridgrid_v1_build_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_hybridgrid_v1_build_proto_msgTypes = make([]protoimpl.MessageInfo, 54)
 var file_hybridgrid_v1_build_proto_goTypes = []any{
 	(Architecture)(0),                       // 0: hybridgrid.v1.Architecture
 	(BuildType)(0),                          // 1: hybridgrid.v1.BuildType
 	(TargetPlatform)(0),                     // 2: hybridgrid.v1.TargetPlatform
 	(TaskStatus)(0),                         // 3: hybridgrid.v1.TaskStatus
-	(*CppConfig)(nil),                       // 4: hybridgrid.v1.CppConfig
-	(*FlutterConfig)(nil),                   // 5: hybridgrid.v1.FlutterConfig
-	(*UnityConfig)(nil),                     // 6: hybridgrid.v1.UnityConfig
-	(*CocosConfig)(nil),                     // 7: hybridgrid.v1.CocosConfig
-	(*RustConfig)(nil),                      // 8: hybridgrid.v1.RustConfig
-	(*GoConfig)(nil),                        // 9: hybridgrid.v1.GoConfig
-	(*NodeConfig)(nil),                      // 10: hybridgrid.v1.NodeConfig
-	(*CppCapability)(nil),                   // 11: hybridgrid.v1.CppCapability
-	(*FlutterCapability)(nil),               // 12: hybridgrid.v1.FlutterCapability
-	(*UnityCapability)(nil),                 // 13: hybridgrid.v1.UnityCapability
-	(*CocosCapability)(nil),                 // 14: hybridgrid.v1.CocosCapability
-	(*RustCapability)(nil),                  // 15: hybridgrid.v1.RustCapability
-	(*GoCapability)(nil),                    // 16: hybridgrid.v1.GoCapability
-	(*NodeCapability)(nil),                  // 17: hybridgrid.v1.NodeCapability
-	(*WorkerCapabilities)(nil),              // 18: hybridgrid.v1.WorkerCapabilities
-	(*HandshakeRequest)(nil),                // 19: hybridgrid.v1.HandshakeRequest
-	(*HandshakeResponse)(nil),               // 20: hybridgrid.v1.HandshakeResponse
-	(*BuildRequest)(nil),                    // 21: hybridgrid.v1.BuildRequest
-	(*ArtifactInfo)(nil),                    // 22: hybridgrid.v1.ArtifactInfo
-	(*BuildResponse)(nil),                   // 23: hybridgrid.v1.BuildResponse
-	(*BuildChunk)(nil),                      // 24: hybridgrid.v1.BuildChunk
-	(*BuildMetadata)(nil),                   // 25: hybridgrid.v1.BuildMetadata
-	(*CompileRequest)(nil),                  // 26: hybridgrid.v1.CompileRequest
-	(*CompileResponse)(nil),                 // 27: hybridgrid.v1.CompileResponse
-	(*HealthRequest)(nil),                   // 28: hybridgrid.v1.HealthRequest
-	(*HealthResponse)(nil),                  // 29: hybridgrid.v1.HealthResponse
-	(*WorkerStatusRequest)(nil),             // 30: hybridgrid.v1.WorkerStatusRequest
-	(*WorkerStatusResponse)(nil),            // 31: hybridgrid.v1.WorkerStatusResponse
-	(*WorkersForBuildRequest)(nil),          // 32: hybridgrid.v1.WorkersForBuildRequest
-	(*WorkersForBuildResponse)(nil),         // 33: hybridgrid.v1.WorkersForBuildResponse
-	(*ReportCacheHitRequest)(nil),           // 34: hybridgrid.v1.ReportCacheHitRequest
-	(*ReportCacheHitResponse)(nil),          // 35: hybridgrid.v1.ReportCacheHitResponse
-	nil,                                     // 36: hybridgrid.v1.FlutterConfig.DartDefinesEntry
-	nil,                                     // 37: hybridgrid.v1.UnityConfig.ExtraArgsEntry
-	nil,                                     // 38: hybridgrid.v1.CocosConfig.PlatformOptionsEntry
-	nil,                                     // 39: hybridgrid.v1.GoConfig.LdflagsEntry
-	nil,                                     // 40: hybridgrid.v1.NodeConfig.EnvVarsEntry
-	nil,                                     // 41: hybridgrid.v1.CompileRequest.IncludeFilesEntry
-	(*WorkerStatusResponse_WorkerInfo)(nil), // 42: hybridgrid.v1.WorkerStatusResponse.WorkerInfo
+	(LogStream)(0),                          // 4: hybridgrid.v1.LogStream
+	(*CppConfig)(nil),                       // 5: hybridgrid.v1.CppConfig
+	(*FlutterConfig)(nil),                   // 6: hybridgrid.v1.FlutterConfig
+	(*UnityConfig)(nil),                     // 7: hybridgrid.v1.UnityConfig
+	(*CocosConfig)(nil),                     // 8: hybridgrid.v1.CocosConfig
+	(*RustConfig)(nil),                      // 9: hybridgrid.v1.RustConfig
+	(*GoConfig)(nil),                        // 10: hybridgrid.v1.GoConfig
+	(*NodeConfig)(nil),                      // 11: hybridgrid.v1.NodeConfig
+	(*CppCapability)(nil),                   // 12: hybridgrid.v1.CppCapability
+	(*FlutterCapability)(nil),               // 13: hybridgrid.v1.FlutterCapability
+	(*UnityCapability)(nil),                 // 14: hybridgrid.v1.UnityCapability
+	(*CocosCapability)(nil),                 // 15: hybridgrid.v1.CocosCapability
+	(*RustCapability)(nil),                  // 16: hybridgrid.v1.RustCapability
+	(*GoCapability)(nil),                    // 17: hybridgrid.v1.GoCapability
+	(*NodeCapability)(nil),                  // 18: hybridgrid.v1.NodeCapability
+	(*WorkerCapabilities)(nil),              // 19: hybridgrid.v1.WorkerCapabilities
+	(*HandshakeRequest)(nil),                // 20: hybridgrid.v1.HandshakeRequest
+	(*HandshakeResponse)(nil),               // 21: hybridgrid.v1.HandshakeResponse
+	(*BuildRequest)(nil),                    // 22: hybridgrid.v1.BuildRequest
+	(*ArtifactInfo)(nil),                    // 23: hybridgrid.v1.ArtifactInfo
+	(*BuildResponse)(nil),                   // 24: hybridgrid.v1.BuildResponse
+	(*BuildChunk)(nil),                      // 25: hybridgrid.v1.BuildChunk
+	(*BuildMetadata)(nil),                   // 26: hybridgrid.v1.BuildMetadata
+	(*FetchArtifactsRequest)(nil),           // 27: hybridgrid.v1.FetchArtifactsRequest
+	(*ArtifactChunk)(nil),                   // 28: hybridgrid.v1.ArtifactChunk
+	(*CompileRequest)(nil),                  // 29: hybridgrid.v1.CompileRequest
+	(*CompileResponse)(nil),                 // 30: hybridgrid.v1.CompileResponse
+	(*HealthRequest)(nil),                   // 31: hybridgrid.v1.HealthRequest
+	(*HealthResponse)(nil),                  // 32: hybridgrid.v1.HealthResponse
+	(*WorkerStatusRequest)(nil),             // 33: hybridgrid.v1.WorkerStatusRequest
+	(*WorkerStatusResponse)(nil),            // 34: hybridgrid.v1.WorkerStatusResponse
+	(*WorkersForBuildRequest)(nil),          // 35: hybridgrid.v1.WorkersForBuildRequest
+	(*WorkersForBuildResponse)(nil),         // 36: hybridgrid.v1.WorkersForBuildResponse
+	(*ReportCacheHitRequest)(nil),           // 37: hybridgrid.v1.ReportCacheHitRequest
+	(*ReportCacheHitResponse)(nil),          // 38: hybridgrid.v1.ReportCacheHitResponse
+	(*CancelTaskRequest)(nil),               // 39: hybridgrid.v1.CancelTaskRequest
+	(*CancelTaskResponse)(nil),              // 40: hybridgrid.v1.CancelTaskResponse
+	(*WatchTaskRequest)(nil),                // 41: hybridgrid.v1.WatchTaskRequest
+	(*TaskLogLine)(nil),                     // 42: hybridgrid.v1.TaskLogLine
+	(*EnrollRequest)(nil),                   // 43: hybridgrid.v1.EnrollRequest
+	(*EnrollResponse)(nil),                  // 44: hybridgrid.v1.EnrollResponse
+	(*SubmitBuildGraphRequest)(nil),         // 45: hybridgrid.v1.SubmitBuildGraphRequest
+	(*TranslationUnitRank)(nil),             // 46: hybridgrid.v1.TranslationUnitRank
+	(*SubmitBuildGraphResponse)(nil),        // 47: hybridgrid.v1.SubmitBuildGraphResponse
+	(*WorkerLoad)(nil),                      // 48: hybridgrid.v1.WorkerLoad
+	(*ReportLoadRequest)(nil),               // 49: hybridgrid.v1.ReportLoadRequest
+	(*ReportLoadResponse)(nil),              // 50: hybridgrid.v1.ReportLoadResponse
+	nil,                                     // 51: hybridgrid.v1.FlutterConfig.DartDefinesEntry
+	nil,                                     // 52: hybridgrid.v1.UnityConfig.ExtraArgsEntry
+	nil,                                     // 53: hybridgrid.v1.CocosConfig.PlatformOptionsEntry
+	nil,                                     // 54: hybridgrid.v1.GoConfig.LdflagsEntry
+	nil,                                     // 55: hybridgrid.v1.NodeConfig.EnvVarsEntry
+	nil,                                     // 56: hybridgrid.v1.CompileRequest.IncludeFilesEntry
+	nil,                                     // 57: hybridgrid.v1.CompileRequest.LinkInputsEntry
+	(*WorkerStatusResponse_WorkerInfo)(nil), // 58: hybridgrid.v1.WorkerStatusResponse.WorkerInfo
 }
 var file_hybridgrid_v1_build_proto_depIdxs = []int32{
 	0,  // 0: hybridgrid.v1.CppConfig.target_arch:type_name -> hybridgrid.v1.Architecture
-	36, // 1: hybridgrid.v1.FlutterConfig.dart_defines:type_name -> hybridgrid.v1.FlutterConfig.DartDefinesEntry
-	37, // 2: hybridgrid.v1.UnityConfig.extra_args:type_name -> hybridgrid.v1.UnityConfig.ExtraArgsEntry
-	38, // 3: hybridgrid.v1.CocosConfig.platform_options:type_name -> hybridgrid.v1.CocosConfig.PlatformOptionsEntry
-	39, // 4: hybridgrid.v1.GoConfig.ldflags:type_name -> hybridgrid.v1.GoConfig.LdflagsEntry
-	40, // 5: hybridgrid.v1.NodeConfig.env_vars:type_name -> hybridgrid.v1.NodeConfig.EnvVarsEntry
+	51, // 1: hybridgrid.v1.FlutterConfig.dart_defines:type_name -> hybridgrid.v1.FlutterConfig.DartDefinesEntry
+	52, // 2: hybridgrid.v1.UnityConfig.extra_args:type_name -> hybridgrid.v1.UnityConfig.ExtraArgsEntry
+	53, // 3: hybridgrid.v1.CocosConfig.platform_options:type_name -> hybridgrid.v1.CocosConfig.PlatformOptionsEntry
+	54, // 4: hybridgrid.v1.GoConfig.ldflags:type_name -> hybridgrid.v1.GoConfig.LdflagsEntry
+	55, // 5: hybridgrid.v1.NodeConfig.env_vars:type_name -> hybridgrid.v1.NodeConfig.EnvVarsEntry
 	2,  // 6: hybridgrid.v1.FlutterCapability.platforms:type_name -> hybridgrid.v1.TargetPlatform
 	2,  // 7: hybridgrid.v1.UnityCapability.build_targets:type_name -> hybridgrid.v1.TargetPlatform
 	2,  // 8: hybridgrid.v1.CocosCapability.platforms:type_name -> hybridgrid.v1.TargetPlatform
 	0,  // 9: hybridgrid.v1.WorkerCapabilities.native_arch:type_name -> hybridgrid.v1.Architecture
-	11, // 10: hybridgrid.v1.WorkerCapabilities.cpp:type_name -> hybridgrid.v1.CppCapability
-	12, // 11: hybridgrid.v1.WorkerCapabilities.flutter:type_name -> hybridgrid.v1.FlutterCapability
-	13, // 12: hybridgrid.v1.WorkerCapabilities.unity:type_name -> hybridgrid.v1.UnityCapability
-	14, // 13: hybridgrid.v1.WorkerCapabilities.cocos:type_name -> hybridgrid.v1.CocosCapability
-	15, // 14: hybridgrid.v1.WorkerCapabilities.rust:type_name -> hybridgrid.v1.RustCapability
-	16, // 15: hybridgrid.v1.WorkerCapabilities.go:type_name -> hybridgrid.v1.GoCapability
-	17, // 16: hybridgrid.v1.WorkerCapabilities.nodejs:type_name -> hybridgrid.v1.NodeCapability
-	18, // 17: hybridgrid.v1.HandshakeRequest.capabilities:type_name -> hybridgrid.v1.WorkerCapabilities
+	12, // 10: hybridgrid.v1.WorkerCapabilities.cpp:type_name -> hybridgrid.v1.CppCapability
+	13, // 11: hybridgrid.v1.WorkerCapabilities.flutter:type_name -> hybridgrid.v1.FlutterCapability
+	14, // 12: hybridgrid.v1.WorkerCapabilities.unity:type_name -> hybridgrid.v1.UnityCapability
+	15, // 13: hybridgrid.v1.WorkerCapabilities.cocos:type_name -> hybridgrid.v1.CocosCapability
+	16, // 14: hybridgrid.v1.WorkerCapabilities.rust:type_name -> hybridgrid.v1.RustCapability
+	17, // 15: hybridgrid.v1.WorkerCapabilities.go:type_name -> hybridgrid.v1.GoCapability
+	18, // 16: hybridgrid.v1.WorkerCapabilities.nodejs:type_name -> hybridgrid.v1.NodeCapability
+	19, // 17: hybridgrid.v1.HandshakeRequest.capabilities:type_name -> hybridgrid.v1.WorkerCapabilities
 	1,  // 18: hybridgrid.v1.BuildRequest.build_type:type_name -> hybridgrid.v1.BuildType
 	2,  // 19: hybridgrid.v1.BuildRequest.target_platform:type_name -> hybridgrid.v1.TargetPlatform
-	4,  // 20: hybridgrid.v1.BuildRequest.cpp_config:type_name -> hybridgrid.v1.CppConfig
-	5,  // 21: hybridgrid.v1.BuildRequest.flutter_config:type_name -> hybridgrid.v1.FlutterConfig
-	6,  // 22: hybridgrid.v1.BuildRequest.unity_config:type_name -> hybridgrid.v1.UnityConfig
-	7,  // 23: hybridgrid.v1.BuildRequest.cocos_config:type_name -> hybridgrid.v1.CocosConfig
-	8,  // 24: hybridgrid.v1.BuildRequest.rust_config:type_name -> hybridgrid.v1.RustConfig
-	9,  // 25: hybridgrid.v1.BuildRequest.go_config:type_name -> hybridgrid.v1.GoConfig
-	10, // 26: hybridgrid.v1.BuildRequest.node_config:type_name -> hybridgrid.v1.NodeConfig
+	5,  // 20: hybridgrid.v1.BuildRequest.cpp_config:type_name -> hybridgrid.v1.CppConfig
+	6,  // 21: hybridgrid.v1.BuildRequest.flutter_config:type_name -> hybridgrid.v1.FlutterConfig
+	7,  // 22: hybridgrid.v1.BuildRequest.unity_config:type_name -> hybridgrid.v1.UnityConfig
+	8,  // 23: hybridgrid.v1.BuildRequest.cocos_config:type_name -> hybridgrid.v1.CocosConfig
+	9,  // 24: hybridgrid.v1.BuildRequest.rust_config:type_name -> hybridgrid.v1.RustConfig
+	10, // 25: hybridgrid.v1.BuildRequest.go_config:type_name -> hybridgrid.v1.GoConfig
+	11, // 26: hybridgrid.v1.BuildRequest.node_config:type_name -> hybridgrid.v1.NodeConfig
 	3,  // 27: hybridgrid.v1.BuildResponse.status:type_name -> hybridgrid.v1.TaskStatus
-	22, // 28: hybridgrid.v1.BuildResponse.artifact_list:type_name -> hybridgrid.v1.ArtifactInfo
-	25, // 29: hybridgrid.v1.BuildChunk.metadata:type_name -> hybridgrid.v1.BuildMetadata
+	23, // 28: hybridgrid.v1.BuildResponse.artifact_list:type_name -> hybridgrid.v1.ArtifactInfo
+	26, // 29: hybridgrid.v1.BuildChunk.metadata:type_name -> hybridgrid.v1.BuildMetadata
 	1,  // 30: hybridgrid.v1.BuildMetadata.build_type:type_name -> hybridgrid.v1.BuildType
 	2,  // 31: hybridgrid.v1.BuildMetadata.target_platform:type_name -> hybridgrid.v1.TargetPlatform
-	0,  // 32: hybridgrid.v1.CompileRequest.target_arch:type_name -> hybridgrid.v1.Architecture
-	0,  // 33: hybridgrid.v1.CompileRequest.client_arch:type_name -> hybridgrid.v1.Architecture
-	41, // 34: hybridgrid.v1.CompileRequest.include_files:type_name -> hybridgrid.v1.CompileRequest.IncludeFilesEntry
-	3,  // 35: hybridgrid.v1.CompileResponse.status:type_name -> hybridgrid.v1.TaskStatus
-	42, // 36: hybridgrid.v1.WorkerStatusResponse.workers:type_name -> hybridgrid.v1.WorkerStatusResponse.WorkerInfo
-	1,  // 37: hybridgrid.v1.WorkersForBuildRequest.build_type:type_name -> hybridgrid.v1.BuildType
-	2,  // 38: hybridgrid.v1.WorkersForBuildRequest.target_platform:type_name -> hybridgrid.v1.TargetPlatform
-	0,  // 39: hybridgrid.v1.WorkerStatusResponse.WorkerInfo.native_arch:type_name -> hybridgrid.v1.Architecture
-	19, // 40: hybridgrid.v1.BuildService.Handshake:input_type -> hybridgrid.v1.HandshakeRequest
-	21, // 41: hybridgrid.v1.BuildService.Build:input_type -> hybridgrid.v1.BuildRequest
-	24, // 42: hybridgrid.v1.BuildService.StreamBuild:input_type -> hybridgrid.v1.BuildChunk
-	26, // 43: hybridgrid.v1.BuildService.Compile:input_type -> hybridgrid.v1.CompileRequest
-	28, // 44: hybridgrid.v1.BuildService.HealthCheck:input_type -> hybridgrid.v1.HealthRequest
-	30, // 45: hybridgrid.v1.BuildService.GetWorkerStatus:input_type -> hybridgrid.v1.WorkerStatusRequest
-	32, // 46: hybridgrid.v1.BuildService.GetWorkersForBuild:input_type -> hybridgrid.v1.WorkersForBuildRequest
-	34, // 47: hybridgrid.v1.BuildService.ReportCacheHit:input_type -> hybridgrid.v1.ReportCacheHitRequest
-	20, // 48: hybridgrid.v1.BuildService.Handshake:output_type -> hybridgrid.v1.HandshakeResponse
-	23, // 49: hybridgrid.v1.BuildService.Build:output_type -> hybridgrid.v1.BuildResponse
-	23, // 50: hybridgrid.v1.BuildService.StreamBuild:output_type -> hybridgrid.v1.BuildResponse
-	27, // 51: hybridgrid.v1.BuildService.Compile:output_type -> hybridgrid.v1.CompileResponse
-	29, // 52: hybridgrid.v1.BuildService.HealthCheck:output_type -> hybridgrid.v1.HealthResponse
-	31, // 53: hybridgrid.v1.BuildService.GetWorkerStatus:output_type -> hybridgrid.v1.WorkerStatusResponse
-	33, // 54: hybridgrid.v1.BuildService.GetWorkersForBuild:output_type -> hybridgrid.v1.WorkersForBuildResponse
-	35, // 55: hybridgrid.v1.BuildService.ReportCacheHit:output_type -> hybridgrid.v1.ReportCacheHitResponse
-	48, // [48:56] is the sub-list for method output_type
-	40, // [40:48] is the sub-list for method input_type
-	40, // [40:40] is the sub-list for extension type_name
-	40, // [40:40] is the sub-list for extension extendee
-	0,  // [0:40] is the sub-list for field type_name
+	23, // 32: hybridgrid.v1.ArtifactChunk.info:type_name -> hybridgrid.v1.ArtifactInfo
+	0,  // 33: hybridgrid.v1.CompileRequest.target_arch:type_name -> hybridgrid.v1.Architecture
+	0,  // 34: hybridgrid.v1.CompileRequest.client_arch:type_name -> hybridgrid.v1.Architecture
+	56, // 35: hybridgrid.v1.CompileRequest.include_files:type_name -> hybridgrid.v1.CompileRequest.IncludeFilesEntry
+	57, // 36: hybridgrid.v1.CompileRequest.link_inputs:type_name -> hybridgrid.v1.CompileRequest.LinkInputsEntry
+	3,  // 37: hybridgrid.v1.CompileResponse.status:type_name -> hybridgrid.v1.TaskStatus
+	58, // 38: hybridgrid.v1.WorkerStatusResponse.workers:type_name -> hybridgrid.v1.WorkerStatusResponse.WorkerInfo
+	1,  // 39: hybridgrid.v1.WorkersForBuildRequest.build_type:type_name -> hybridgrid.v1.BuildType
+	2,  // 40: hybridgrid.v1.WorkersForBuildRequest.target_platform:type_name -> hybridgrid.v1.TargetPlatform
+	4,  // 41: hybridgrid.v1.TaskLogLine.stream:type_name -> hybridgrid.v1.LogStream
+	46, // 42: hybridgrid.v1.SubmitBuildGraphRequest.ranks:type_name -> hybridgrid.v1.TranslationUnitRank
+	48, // 43: hybridgrid.v1.ReportLoadRequest.load:type_name -> hybridgrid.v1.WorkerLoad
+	0,  // 44: hybridgrid.v1.WorkerStatusResponse.WorkerInfo.native_arch:type_name -> hybridgrid.v1.Architecture
+	19, // 45: hybridgrid.v1.WorkerStatusResponse.WorkerInfo.capabilities:type_name -> hybridgrid.v1.WorkerCapabilities
+	48, // 46: hybridgrid.v1.WorkerStatusResponse.WorkerInfo.load:type_name -> hybridgrid.v1.WorkerLoad
+	20, // 47: hybridgrid.v1.BuildService.Handshake:input_type -> hybridgrid.v1.HandshakeRequest
+	22, // 48: hybridgrid.v1.BuildService.Build:input_type -> hybridgrid.v1.BuildRequest
+	25, // 49: hybridgrid.v1.BuildService.StreamBuild:input_type -> hybridgrid.v1.BuildChunk
+	27, // 50: hybridgrid.v1.BuildService.FetchArtifacts:input_type -> hybridgrid.v1.FetchArtifactsRequest
+	29, // 51: hybridgrid.v1.BuildService.Compile:input_type -> hybridgrid.v1.CompileRequest
+	31, // 52: hybridgrid.v1.BuildService.HealthCheck:input_type -> hybridgrid.v1.HealthRequest
+	33, // 53: hybridgrid.v1.BuildService.GetWorkerStatus:input_type -> hybridgrid.v1.WorkerStatusRequest
+	35, // 54: hybridgrid.v1.BuildService.GetWorkersForBuild:input_type -> hybridgrid.v1.WorkersForBuildRequest
+	37, // 55: hybridgrid.v1.BuildService.ReportCacheHit:input_type -> hybridgrid.v1.ReportCacheHitRequest
+	39, // 56: hybridgrid.v1.BuildService.CancelTask:input_type -> hybridgrid.v1.CancelTaskRequest
+	41, // 57: hybridgrid.v1.BuildService.WatchTask:input_type -> hybridgrid.v1.WatchTaskRequest
+	43, // 58: hybridgrid.v1.BuildService.Enroll:input_type -> hybridgrid.v1.EnrollRequest
+	45, // 59: hybridgrid.v1.BuildService.SubmitBuildGraph:input_type -> hybridgrid.v1.SubmitBuildGraphRequest
+	49, // 60: hybridgrid.v1.BuildService.ReportLoad:input_type -> hybridgrid.v1.ReportLoadRequest
+	21, // 61: hybridgrid.v1.BuildService.Handshake:output_type -> hybridgrid.v1.HandshakeResponse
+	24, // 62: hybridgrid.v1.BuildService.Build:output_type -> hybridgrid.v1.BuildResponse
+	24, // 63: hybridgrid.v1.BuildService.StreamBuild:output_type -> hybridgrid.v1.BuildResponse
+	28, // 64: hybridgrid.v1.BuildService.FetchArtifacts:output_type -> hybridgrid.v1.ArtifactChunk
+	30, // 65: hybridgrid.v1.BuildService.Compile:output_type -> hybridgrid.v1.CompileResponse
+	32, // 66: hybridgrid.v1.BuildService.HealthCheck:output_type -> hybridgrid.v1.HealthResponse
+	34, // 67: hybridgrid.v1.BuildService.GetWorkerStatus:output_type -> hybridgrid.v1.WorkerStatusResponse
+	36, // 68: hybridgrid.v1.BuildService.GetWorkersForBuild:output_type -> hybridgrid.v1.WorkersForBuildResponse
+	38, // 69: hybridgrid.v1.BuildService.ReportCacheHit:output_type -> hybridgrid.v1.ReportCacheHitResponse
+	40, // 70: hybridgrid.v1.BuildService.CancelTask:output_type -> hybridgrid.v1.CancelTaskResponse
+	42, // 71: hybridgrid.v1.BuildService.WatchTask:output_type -> hybridgrid.v1.TaskLogLine
+	44, // 72: hybridgrid.v1.BuildService.Enroll:output_type -> hybridgrid.v1.EnrollResponse
+	47, // 73: hybridgrid.v1.BuildService.SubmitBuildGraph:output_type -> hybridgrid.v1.SubmitBuildGraphResponse
+	50, // 74: hybridgrid.v1.BuildService.ReportLoad:output_type -> hybridgrid.v1.ReportLoadResponse
+	61, // [61:75] is the sub-list for method output_type
+	47, // [47:61] is the sub-list for method input_type
+	47, // [47:47] is the sub-list for extension type_name
+	47, // [47:47] is the sub-list for extension extendee
+	0,  // [0:47] is the sub-list for field type_name
 }
 
 func init() { file_hybridgrid_v1_build_proto_init() }
@@ -3312,13 +4396,17 @@ func file_hybridgrid_v1_build_proto_init() {
 		(*BuildChunk_Metadata)(nil),
 		(*BuildChunk_SourceChunk)(nil),
 	}
+	file_hybridgrid_v1_build_proto_msgTypes[23].OneofWrappers = []any{
+		(*ArtifactChunk_Info)(nil),
+		(*ArtifactChunk_Data)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hybridgrid_v1_build_proto_rawDesc), len(file_hybridgrid_v1_build_proto_rawDesc)),
-			NumEnums:      4,
-			NumMessages:   39,
+			NumEnums:      5,
+			NumMessages:   54,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
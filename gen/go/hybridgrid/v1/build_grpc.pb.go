@@ -22,11 +22,17 @@ const (
 	BuildService_Handshake_FullMethodName          = "/hybridgrid.v1.BuildService/Handshake"
 	BuildService_Build_FullMethodName              = "/hybridgrid.v1.BuildService/Build"
 	BuildService_StreamBuild_FullMethodName        = "/hybridgrid.v1.BuildService/StreamBuild"
+	BuildService_FetchArtifacts_FullMethodName     = "/hybridgrid.v1.BuildService/FetchArtifacts"
 	BuildService_Compile_FullMethodName            = "/hybridgrid.v1.BuildService/Compile"
 	BuildService_HealthCheck_FullMethodName        = "/hybridgrid.v1.BuildService/HealthCheck"
 	BuildService_GetWorkerStatus_FullMethodName    = "/hybridgrid.v1.BuildService/GetWorkerStatus"
 	BuildService_GetWorkersForBuild_FullMethodName = "/hybridgrid.v1.BuildService/GetWorkersForBuild"
 	BuildService_ReportCacheHit_FullMethodName     = "/hybridgrid.v1.BuildService/ReportCacheHit"
+	BuildService_CancelTask_FullMethodName         = "/hybridgrid.v1.BuildService/CancelTask"
+	BuildService_WatchTask_FullMethodName          = "/hybridgrid.v1.BuildService/WatchTask"
+	BuildService_Enroll_FullMethodName             = "/hybridgrid.v1.BuildService/Enroll"
+	BuildService_SubmitBuildGraph_FullMethodName   = "/hybridgrid.v1.BuildService/SubmitBuildGraph"
+	BuildService_ReportLoad_FullMethodName         = "/hybridgrid.v1.BuildService/ReportLoad"
 )
 
 // BuildServiceClient is the client API for BuildService service.
@@ -39,6 +45,8 @@ type BuildServiceClient interface {
 	Build(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*BuildResponse, error)
 	// Streaming build for large projects (>10MB)
 	StreamBuild(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[BuildChunk, BuildResponse], error)
+	// Chunked download of a deferred build's artifacts
+	FetchArtifacts(ctx context.Context, in *FetchArtifactsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArtifactChunk], error)
 	// Legacy C/C++ compilation (backward compatibility)
 	Compile(ctx context.Context, in *CompileRequest, opts ...grpc.CallOption) (*CompileResponse, error)
 	// Health check
@@ -49,6 +57,16 @@ type BuildServiceClient interface {
 	GetWorkersForBuild(ctx context.Context, in *WorkersForBuildRequest, opts ...grpc.CallOption) (*WorkersForBuildResponse, error)
 	// Report client-side cache hit (for dashboard stats)
 	ReportCacheHit(ctx context.Context, in *ReportCacheHitRequest, opts ...grpc.CallOption) (*ReportCacheHitResponse, error)
+	// Cancel a queued or running task (Client → Coordinator, Coordinator → Worker)
+	CancelTask(ctx context.Context, in *CancelTaskRequest, opts ...grpc.CallOption) (*CancelTaskResponse, error)
+	// Live output of a queued or running task (Client → Coordinator, Coordinator → Worker)
+	WatchTask(ctx context.Context, in *WatchTaskRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TaskLogLine], error)
+	// Exchange a one-time join token for a signed certificate (Worker → Coordinator)
+	Enroll(ctx context.Context, in *EnrollRequest, opts ...grpc.CallOption) (*EnrollResponse, error)
+	// Upward-rank hints for the translation units of a build (Client → Coordinator)
+	SubmitBuildGraph(ctx context.Context, in *SubmitBuildGraphRequest, opts ...grpc.CallOption) (*SubmitBuildGraphResponse, error)
+	// Periodic host load report (Worker → Coordinator)
+	ReportLoad(ctx context.Context, in *ReportLoadRequest, opts ...grpc.CallOption) (*ReportLoadResponse, error)
 }
 
 type buildServiceClient struct {
@@ -92,6 +110,25 @@ func (c *buildServiceClient) StreamBuild(ctx context.Context, opts ...grpc.CallO
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BuildService_StreamBuildClient = grpc.ClientStreamingClient[BuildChunk, BuildResponse]
 
+func (c *buildServiceClient) FetchArtifacts(ctx context.Context, in *FetchArtifactsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArtifactChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BuildService_ServiceDesc.Streams[1], BuildService_FetchArtifacts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FetchArtifactsRequest, ArtifactChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_FetchArtifactsClient = grpc.ServerStreamingClient[ArtifactChunk]
+
 func (c *buildServiceClient) Compile(ctx context.Context, in *CompileRequest, opts ...grpc.CallOption) (*CompileResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CompileResponse)
@@ -142,6 +179,65 @@ func (c *buildServiceClient) ReportCacheHit(ctx context.Context, in *ReportCache
 	return out, nil
 }
 
+func (c *buildServiceClient) CancelTask(ctx context.Context, in *CancelTaskRequest, opts ...grpc.CallOption) (*CancelTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelTaskResponse)
+	err := c.cc.Invoke(ctx, BuildService_CancelTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *buildServiceClient) WatchTask(ctx context.Context, in *WatchTaskRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TaskLogLine], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BuildService_ServiceDesc.Streams[2], BuildService_WatchTask_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTaskRequest, TaskLogLine]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_WatchTaskClient = grpc.ServerStreamingClient[TaskLogLine]
+
+func (c *buildServiceClient) Enroll(ctx context.Context, in *EnrollRequest, opts ...grpc.CallOption) (*EnrollResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollResponse)
+	err := c.cc.Invoke(ctx, BuildService_Enroll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *buildServiceClient) SubmitBuildGraph(ctx context.Context, in *SubmitBuildGraphRequest, opts ...grpc.CallOption) (*SubmitBuildGraphResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitBuildGraphResponse)
+	err := c.cc.Invoke(ctx, BuildService_SubmitBuildGraph_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *buildServiceClient) ReportLoad(ctx context.Context, in *ReportLoadRequest, opts ...grpc.CallOption) (*ReportLoadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportLoadResponse)
+	err := c.cc.Invoke(ctx, BuildService_ReportLoad_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BuildServiceServer is the server API for BuildService service.
 // All implementations must embed UnimplementedBuildServiceServer
 // for forward compatibility.
@@ -152,6 +248,8 @@ type BuildServiceServer interface {
 	Build(context.Context, *BuildRequest) (*BuildResponse, error)
 	// Streaming build for large projects (>10MB)
 	StreamBuild(grpc.ClientStreamingServer[BuildChunk, BuildResponse]) error
+	// Chunked download of a deferred build's artifacts
+	FetchArtifacts(*FetchArtifactsRequest, grpc.ServerStreamingServer[ArtifactChunk]) error
 	// Legacy C/C++ compilation (backward compatibility)
 	Compile(context.Context, *CompileRequest) (*CompileResponse, error)
 	// Health check
@@ -162,6 +260,16 @@ type BuildServiceServer interface {
 	GetWorkersForBuild(context.Context, *WorkersForBuildRequest) (*WorkersForBuildResponse, error)
 	// Report client-side cache hit (for dashboard stats)
 	ReportCacheHit(context.Context, *ReportCacheHitRequest) (*ReportCacheHitResponse, error)
+	// Cancel a queued or running task (Client → Coordinator, Coordinator → Worker)
+	CancelTask(context.Context, *CancelTaskRequest) (*CancelTaskResponse, error)
+	// Live output of a queued or running task (Client → Coordinator, Coordinator → Worker)
+	WatchTask(*WatchTaskRequest, grpc.ServerStreamingServer[TaskLogLine]) error
+	// Exchange a one-time join token for a signed certificate (Worker → Coordinator)
+	Enroll(context.Context, *EnrollRequest) (*EnrollResponse, error)
+	// Upward-rank hints for the translation units of a build (Client → Coordinator)
+	SubmitBuildGraph(context.Context, *SubmitBuildGraphRequest) (*SubmitBuildGraphResponse, error)
+	// Periodic host load report (Worker → Coordinator)
+	ReportLoad(context.Context, *ReportLoadRequest) (*ReportLoadResponse, error)
 	mustEmbedUnimplementedBuildServiceServer()
 }
 
@@ -181,6 +289,9 @@ func (UnimplementedBuildServiceServer) Build(context.Context, *BuildRequest) (*B
 func (UnimplementedBuildServiceServer) StreamBuild(grpc.ClientStreamingServer[BuildChunk, BuildResponse]) error {
 	return status.Error(codes.Unimplemented, "method StreamBuild not implemented")
 }
+func (UnimplementedBuildServiceServer) FetchArtifacts(*FetchArtifactsRequest, grpc.ServerStreamingServer[ArtifactChunk]) error {
+	return status.Error(codes.Unimplemented, "method FetchArtifacts not implemented")
+}
 func (UnimplementedBuildServiceServer) Compile(context.Context, *CompileRequest) (*CompileResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Compile not implemented")
 }
@@ -196,6 +307,21 @@ func (UnimplementedBuildServiceServer) GetWorkersForBuild(context.Context, *Work
 func (UnimplementedBuildServiceServer) ReportCacheHit(context.Context, *ReportCacheHitRequest) (*ReportCacheHitResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ReportCacheHit not implemented")
 }
+func (UnimplementedBuildServiceServer) CancelTask(context.Context, *CancelTaskRequest) (*CancelTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelTask not implemented")
+}
+func (UnimplementedBuildServiceServer) WatchTask(*WatchTaskRequest, grpc.ServerStreamingServer[TaskLogLine]) error {
+	return status.Error(codes.Unimplemented, "method WatchTask not implemented")
+}
+func (UnimplementedBuildServiceServer) Enroll(context.Context, *EnrollRequest) (*EnrollResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Enroll not implemented")
+}
+func (UnimplementedBuildServiceServer) SubmitBuildGraph(context.Context, *SubmitBuildGraphRequest) (*SubmitBuildGraphResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SubmitBuildGraph not implemented")
+}
+func (UnimplementedBuildServiceServer) ReportLoad(context.Context, *ReportLoadRequest) (*ReportLoadResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReportLoad not implemented")
+}
 func (UnimplementedBuildServiceServer) mustEmbedUnimplementedBuildServiceServer() {}
 func (UnimplementedBuildServiceServer) testEmbeddedByValue()                      {}
 
@@ -260,6 +386,17 @@ func _BuildService_StreamBuild_Handler(srv interface{}, stream grpc.ServerStream
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BuildService_StreamBuildServer = grpc.ClientStreamingServer[BuildChunk, BuildResponse]
 
+func _BuildService_FetchArtifacts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchArtifactsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BuildServiceServer).FetchArtifacts(m, &grpc.GenericServerStream[FetchArtifactsRequest, ArtifactChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_FetchArtifactsServer = grpc.ServerStreamingServer[ArtifactChunk]
+
 func _BuildService_Compile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CompileRequest)
 	if err := dec(in); err != nil {
@@ -350,6 +487,89 @@ func _BuildService_ReportCacheHit_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BuildService_CancelTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).CancelTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_CancelTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).CancelTask(ctx, req.(*CancelTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BuildService_WatchTask_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTaskRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BuildServiceServer).WatchTask(m, &grpc.GenericServerStream[WatchTaskRequest, TaskLogLine]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_WatchTaskServer = grpc.ServerStreamingServer[TaskLogLine]
+
+func _BuildService_Enroll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).Enroll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_Enroll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).Enroll(ctx, req.(*EnrollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BuildService_SubmitBuildGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitBuildGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).SubmitBuildGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_SubmitBuildGraph_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).SubmitBuildGraph(ctx, req.(*SubmitBuildGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BuildService_ReportLoad_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportLoadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).ReportLoad(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_ReportLoad_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).ReportLoad(ctx, req.(*ReportLoadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BuildService_ServiceDesc is the grpc.ServiceDesc for BuildService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -385,6 +605,22 @@ var BuildService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReportCacheHit",
 			Handler:    _BuildService_ReportCacheHit_Handler,
 		},
+		{
+			MethodName: "CancelTask",
+			Handler:    _BuildService_CancelTask_Handler,
+		},
+		{
+			MethodName: "Enroll",
+			Handler:    _BuildService_Enroll_Handler,
+		},
+		{
+			MethodName: "SubmitBuildGraph",
+			Handler:    _BuildService_SubmitBuildGraph_Handler,
+		},
+		{
+			MethodName: "ReportLoad",
+			Handler:    _BuildService_ReportLoad_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -392,6 +628,16 @@ var BuildService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _BuildService_StreamBuild_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "FetchArtifacts",
+			Handler:       _BuildService_FetchArtifacts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchTask",
+			Handler:       _BuildService_WatchTask_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "hybridgrid/v1/build.proto",
 }
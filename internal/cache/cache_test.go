@@ -93,6 +93,17 @@ func TestCompilationKey(t *testing.T) {
 	if ck.Build() != ck2.Build() {
 		t.Error("Same flags in different order should produce same key")
 	}
+
+	// An empty TargetOS keeps existing keys; a set one separates them
+	withOS := *ck
+	withOS.TargetOS = "linux"
+	if withOS.Build() == key1 {
+		t.Error("TargetOS should change the key")
+	}
+	withOS.TargetOS = ""
+	if withOS.Build() != key1 {
+		t.Error("Empty TargetOS should not change the key")
+	}
 }
 
 func TestStore(t *testing.T) {
@@ -587,6 +598,26 @@ func TestCompilationKey_DifferentInputs(t *testing.T) {
 	}
 }
 
+func TestLinkInputsHash(t *testing.T) {
+	args := []string{"inputs/0-main.o", "inputs/1-libfoo.a", "-lpthread"}
+	inputs := map[string]string{"inputs/0-main.o": "aaa", "inputs/1-libfoo.a": "bbb"}
+	base := LinkInputsHash(args, inputs)
+
+	if LinkInputsHash(args, map[string]string{"inputs/1-libfoo.a": "bbb", "inputs/0-main.o": "aaa"}) != base {
+		t.Error("Input map order should not change the hash")
+	}
+
+	reordered := []string{"inputs/1-libfoo.a", "inputs/0-main.o", "-lpthread"}
+	if LinkInputsHash(reordered, inputs) == base {
+		t.Error("Argument order should change the hash")
+	}
+
+	changed := map[string]string{"inputs/0-main.o": "aaa", "inputs/1-libfoo.a": "ccc"}
+	if LinkInputsHash(args, changed) == base {
+		t.Error("Different input contents should produce different hash")
+	}
+}
+
 func TestNewStore_CreateDirError(t *testing.T) {
 	var invalidPath string
 
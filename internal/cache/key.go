@@ -97,6 +97,9 @@ type CompilationKey struct {
 	Flags       []string
 	Defines     []string
 	SourceHash  string
+	// TargetOS is folded into the key only when set, so keys built without
+	// it are unchanged.
+	TargetOS string
 }
 
 // Build generates the cache key.
@@ -109,6 +112,31 @@ func (c *CompilationKey) Build() string {
 	kb.AddSortedStrings(c.Flags)
 	kb.AddSortedStrings(c.Defines)
 	kb.AddString(c.SourceHash)
+	if c.TargetOS != "" {
+		kb.AddString(c.TargetOS)
+	}
+
+	return kb.Sum()
+}
+
+// LinkInputsHash hashes a link step for use as CompilationKey.SourceHash.
+// Arguments are hashed in order, since the linker resolves static libraries
+// in command-line order, followed by each input name and its content hash.
+func LinkInputsHash(args []string, inputHashes map[string]string) string {
+	kb := NewKeyBuilder()
+
+	kb.AddString("link")
+	kb.AddStrings(args)
+
+	names := make([]string, 0, len(inputHashes))
+	for name := range inputHashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		kb.AddString(name)
+		kb.AddString(inputHashes[name])
+	}
 
 	return kb.Sum()
 }
@@ -211,3 +239,94 @@ func UnityCacheKey(config *pb.UnityConfig, projectHash string, unityVersion stri
 
 	return kb.Sum()
 }
+
+// CocosCacheKey generates a deterministic cache key for a Cocos Creator
+// build. It incorporates the project hash, Creator version, target platform,
+// build mode (empty means release) and platform options (sorted for
+// determinism).
+func CocosCacheKey(config *pb.CocosConfig, projectHash string, cocosVersion string, targetPlatform pb.TargetPlatform) string {
+	kb := NewKeyBuilder()
+
+	kb.AddString(projectHash)
+	kb.AddString(cocosVersion)
+	kb.AddString(targetPlatform.String())
+
+	buildMode := strings.ToLower(strings.TrimSpace(config.GetBuildMode()))
+	if buildMode == "" {
+		buildMode = "release"
+	}
+	kb.AddString(buildMode)
+
+	keys := make([]string, 0, len(config.GetPlatformOptions()))
+	for k := range config.GetPlatformOptions() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		kb.AddString(k)
+		kb.AddString(config.GetPlatformOptions()[k])
+	}
+
+	return kb.Sum()
+}
+
+// GoCacheKey generates a deterministic cache key for a Go build. It
+// incorporates the go.sum hash, the module source hash, Go version,
+// GOOS/GOARCH, build tags, ldflags and package patterns (all sorted for
+// determinism). A nil config hashes like an empty one.
+func GoCacheKey(config *pb.GoConfig, goSumHash string, sourceHash string, goVersion string) string {
+	kb := NewKeyBuilder()
+
+	kb.AddString(goSumHash)
+	kb.AddString(sourceHash)
+	kb.AddString(goVersion)
+	kb.AddString(config.GetGoos())
+	kb.AddString(config.GetGoarch())
+
+	tags := append([]string(nil), config.GetBuildTags()...)
+	sort.Strings(tags)
+	kb.AddString(strings.Join(tags, ","))
+
+	keys := make([]string, 0, len(config.GetLdflags()))
+	for k := range config.GetLdflags() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		kb.AddString(k)
+		kb.AddString(config.GetLdflags()[k])
+	}
+
+	packages := append([]string(nil), config.GetPackages()...)
+	sort.Strings(packages)
+	kb.AddString(strings.Join(packages, " "))
+
+	return kb.Sum()
+}
+
+// NodeCacheKey generates a deterministic cache key for a Node.js build. It
+// incorporates the lockfile hash, the project source hash, Node.js version,
+// package manager, build script, output directory and environment variables
+// (sorted for determinism). A nil config hashes like an empty one.
+func NodeCacheKey(config *pb.NodeConfig, lockfileHash string, sourceHash string, nodeVersion string) string {
+	kb := NewKeyBuilder()
+
+	kb.AddString(lockfileHash)
+	kb.AddString(sourceHash)
+	kb.AddString(nodeVersion)
+	kb.AddString(config.GetPackageManager())
+	kb.AddString(config.GetBuildScript())
+	kb.AddString(config.GetOutputDir())
+
+	keys := make([]string, 0, len(config.GetEnvVars()))
+	for k := range config.GetEnvVars() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		kb.AddString(k)
+		kb.AddString(config.GetEnvVars()[k])
+	}
+
+	return kb.Sum()
+}
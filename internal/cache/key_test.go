@@ -308,3 +308,135 @@ func TestUnityCacheKey_NilConfig(t *testing.T) {
 		t.Errorf("nil config and empty config produced different keys: %q vs %q", keyNil, keyEmpty)
 	}
 }
+
+func TestCocosCacheKey_Deterministic(t *testing.T) {
+	config1 := &pb.CocosConfig{PlatformOptions: map[string]string{"md5Cache": "true", "packageName": "com.example"}}
+	config2 := &pb.CocosConfig{BuildMode: "Release", PlatformOptions: map[string]string{"packageName": "com.example", "md5Cache": "true"}}
+
+	key1 := CocosCacheKey(config1, "abc123", "3.8.2", pb.TargetPlatform_PLATFORM_ANDROID)
+	key2 := CocosCacheKey(config2, "abc123", "3.8.2", pb.TargetPlatform_PLATFORM_ANDROID)
+	if key1 != key2 {
+		t.Errorf("equivalent configs produced different keys: %q vs %q", key1, key2)
+	}
+}
+
+func TestCocosCacheKey_DifferentInputs(t *testing.T) {
+	base := CocosCacheKey(&pb.CocosConfig{}, "abc123", "3.8.2", pb.TargetPlatform_PLATFORM_WEB)
+
+	variants := map[string]string{
+		"project":  CocosCacheKey(&pb.CocosConfig{}, "xyz789", "3.8.2", pb.TargetPlatform_PLATFORM_WEB),
+		"version":  CocosCacheKey(&pb.CocosConfig{}, "abc123", "3.8.3", pb.TargetPlatform_PLATFORM_WEB),
+		"platform": CocosCacheKey(&pb.CocosConfig{}, "abc123", "3.8.2", pb.TargetPlatform_PLATFORM_ANDROID),
+		"mode":     CocosCacheKey(&pb.CocosConfig{BuildMode: "debug"}, "abc123", "3.8.2", pb.TargetPlatform_PLATFORM_WEB),
+		"options":  CocosCacheKey(&pb.CocosConfig{PlatformOptions: map[string]string{"md5Cache": "true"}}, "abc123", "3.8.2", pb.TargetPlatform_PLATFORM_WEB),
+	}
+	for name, key := range variants {
+		if key == base {
+			t.Errorf("different %s produced same key: %q", name, key)
+		}
+	}
+}
+
+func TestGoCacheKey_Deterministic(t *testing.T) {
+	config := &pb.GoConfig{
+		Goos:      "linux",
+		Goarch:    "arm64",
+		BuildTags: []string{"netgo", "osusergo"},
+		Ldflags:   map[string]string{"main.version": "1.2.3", "-s": ""},
+		Packages:  []string{"./cmd/api", "./cmd/worker"},
+	}
+
+	key1 := GoCacheKey(config, "sum123", "src123", "1.22")
+	key2 := GoCacheKey(config, "sum123", "src123", "1.22")
+	if key1 != key2 {
+		t.Errorf("same inputs produced different keys: %q vs %q", key1, key2)
+	}
+
+	reordered := &pb.GoConfig{
+		Goos:      "linux",
+		Goarch:    "arm64",
+		BuildTags: []string{"osusergo", "netgo"},
+		Ldflags:   map[string]string{"-s": "", "main.version": "1.2.3"},
+		Packages:  []string{"./cmd/worker", "./cmd/api"},
+	}
+	if key3 := GoCacheKey(reordered, "sum123", "src123", "1.22"); key3 != key1 {
+		t.Errorf("reordered tags/packages produced different key: %q vs %q", key3, key1)
+	}
+}
+
+func TestGoCacheKey_DifferentInputs(t *testing.T) {
+	base := &pb.GoConfig{Goos: "linux", Goarch: "amd64"}
+	key := GoCacheKey(base, "sum123", "src123", "1.22")
+
+	variants := map[string]string{
+		"go.sum":  GoCacheKey(base, "sum456", "src123", "1.22"),
+		"source":  GoCacheKey(base, "sum123", "src456", "1.22"),
+		"version": GoCacheKey(base, "sum123", "src123", "1.23"),
+		"goos":    GoCacheKey(&pb.GoConfig{Goos: "windows", Goarch: "amd64"}, "sum123", "src123", "1.22"),
+		"goarch":  GoCacheKey(&pb.GoConfig{Goos: "linux", Goarch: "arm64"}, "sum123", "src123", "1.22"),
+		"tags":    GoCacheKey(&pb.GoConfig{Goos: "linux", Goarch: "amd64", BuildTags: []string{"netgo"}}, "sum123", "src123", "1.22"),
+		"ldflags": GoCacheKey(&pb.GoConfig{Goos: "linux", Goarch: "amd64", Ldflags: map[string]string{"main.version": "2"}}, "sum123", "src123", "1.22"),
+		"package": GoCacheKey(&pb.GoConfig{Goos: "linux", Goarch: "amd64", Packages: []string{"./cmd/api"}}, "sum123", "src123", "1.22"),
+	}
+	for name, other := range variants {
+		if other == key {
+			t.Errorf("different %s produced same key: %q", name, key)
+		}
+	}
+}
+
+func TestGoCacheKey_NilConfig(t *testing.T) {
+	keyNil := GoCacheKey(nil, "sum123", "src123", "1.22")
+	keyEmpty := GoCacheKey(&pb.GoConfig{}, "sum123", "src123", "1.22")
+	if keyNil == "" {
+		t.Error("nil config produced empty key")
+	}
+	if keyNil != keyEmpty {
+		t.Errorf("nil config and empty config produced different keys: %q vs %q", keyNil, keyEmpty)
+	}
+}
+
+func TestNodeCacheKey_Deterministic(t *testing.T) {
+	config := &pb.NodeConfig{
+		PackageManager: "pnpm",
+		BuildScript:    "build",
+		EnvVars:        map[string]string{"NODE_ENV": "production", "API_URL": "https://api"},
+	}
+
+	key1 := NodeCacheKey(config, "lock123", "src123", "20")
+	key2 := NodeCacheKey(config, "lock123", "src123", "20")
+	if key1 != key2 {
+		t.Errorf("same inputs produced different keys: %q vs %q", key1, key2)
+	}
+}
+
+func TestNodeCacheKey_DifferentInputs(t *testing.T) {
+	base := &pb.NodeConfig{PackageManager: "npm", BuildScript: "build"}
+	key := NodeCacheKey(base, "lock123", "src123", "20")
+
+	variants := map[string]string{
+		"lockfile": NodeCacheKey(base, "lock456", "src123", "20"),
+		"source":   NodeCacheKey(base, "lock123", "src456", "20"),
+		"version":  NodeCacheKey(base, "lock123", "src123", "22"),
+		"manager":  NodeCacheKey(&pb.NodeConfig{PackageManager: "yarn", BuildScript: "build"}, "lock123", "src123", "20"),
+		"script":   NodeCacheKey(&pb.NodeConfig{PackageManager: "npm", BuildScript: "build:prod"}, "lock123", "src123", "20"),
+		"output":   NodeCacheKey(&pb.NodeConfig{PackageManager: "npm", BuildScript: "build", OutputDir: "public"}, "lock123", "src123", "20"),
+		"env":      NodeCacheKey(&pb.NodeConfig{PackageManager: "npm", BuildScript: "build", EnvVars: map[string]string{"NODE_ENV": "test"}}, "lock123", "src123", "20"),
+	}
+	for name, other := range variants {
+		if other == key {
+			t.Errorf("different %s produced same key: %q", name, key)
+		}
+	}
+}
+
+func TestNodeCacheKey_NilConfig(t *testing.T) {
+	keyNil := NodeCacheKey(nil, "lock123", "src123", "20")
+	keyEmpty := NodeCacheKey(&pb.NodeConfig{}, "lock123", "src123", "20")
+	if keyNil == "" {
+		t.Error("nil config produced empty key")
+	}
+	if keyNil != keyEmpty {
+		t.Errorf("nil config and empty config produced different keys: %q vs %q", keyNil, keyEmpty)
+	}
+}
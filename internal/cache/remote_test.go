@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var sha256Path = regexp.MustCompile(`^/(ac|cas)/[0-9a-f]{64}$`)
+
+// fakeBazelCache is a minimal bazel-remote: GET/PUT on /ac/<sha256> and
+// /cas/<sha256>, rejecting CAS uploads whose content does not match the path.
+type fakeBazelCache struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+	puts  int
+}
+
+func newFakeBazelCache(t *testing.T) (*fakeBazelCache, *httptest.Server) {
+	t.Helper()
+
+	f := &fakeBazelCache{blobs: make(map[string][]byte)}
+	srv := httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeBazelCache) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !sha256Path.MatchString(r.URL.Path) {
+		http.Error(w, "bad path", http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := f.blobs[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(r.URL.Path, "/cas/") && r.URL.Path != "/cas/"+sha256Hex(data) {
+			http.Error(w, "digest mismatch", http.StatusBadRequest)
+			return
+		}
+		f.blobs[r.URL.Path] = data
+		f.puts++
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestNewHTTPBackend_RejectsInvalidURL(t *testing.T) {
+	for _, raw := range []string{"", "cache.local:8080", "grpc://cache.local", "http://"} {
+		if _, err := NewHTTPBackend(raw, time.Second); err == nil {
+			t.Errorf("NewHTTPBackend(%q) expected error", raw)
+		}
+	}
+}
+
+func TestHTTPBackend_RoundTrip(t *testing.T) {
+	fake, srv := newFakeBazelCache(t)
+
+	backend, err := NewHTTPBackend(srv.URL+"/", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := backend.Get("abc123"); ok || err != nil {
+		t.Fatalf("Get on empty cache = %v, %v; want miss", ok, err)
+	}
+
+	if err := backend.Put("abc123", []byte("object file")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if fake.puts != 2 {
+		t.Fatalf("expected CAS and AC uploads, got %d", fake.puts)
+	}
+
+	data, ok, err := backend.Get("abc123")
+	if err != nil || !ok {
+		t.Fatalf("Get = %v, %v; want hit", ok, err)
+	}
+	if string(data) != "object file" {
+		t.Fatalf("Get data = %q", data)
+	}
+}
+
+func TestHTTPBackend_RejectsCorruptBlob(t *testing.T) {
+	fake, srv := newFakeBazelCache(t)
+
+	backend, err := NewHTTPBackend(srv.URL, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Put("key", []byte("good")); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.mu.Lock()
+	fake.blobs["/cas/"+sha256Hex([]byte("good"))] = []byte("evil")
+	fake.mu.Unlock()
+
+	if _, ok, err := backend.Get("key"); ok || err == nil {
+		t.Fatalf("Get = %v, %v; want digest error", ok, err)
+	}
+}
+
+func TestActionResultRoundTrip(t *testing.T) {
+	hash := sha256Hex([]byte("data"))
+	gotHash, gotSize, err := decodeActionResult(encodeActionResult(hash, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotHash != hash || gotSize != 4 {
+		t.Fatalf("decodeActionResult = %s, %d", gotHash, gotSize)
+	}
+
+	if _, _, err := decodeActionResult([]byte("not a proto")); err == nil {
+		t.Fatal("expected error for malformed action result")
+	}
+}
+
+func TestStore_RemoteBackendIsL2(t *testing.T) {
+	_, srv := newFakeBazelCache(t)
+
+	backend, err := NewHTTPBackend(srv.URL, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A teammate's store writes through to the remote.
+	writer, err := NewStore(t.TempDir(), 10, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.SetBackend(backend)
+	if err := writer.PutBytes("obj1", []byte("compiled")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Put("obj2", strings.NewReader("streamed")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh local store reads through and keeps a local copy.
+	reader, err := NewStore(t.TempDir(), 10, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader.SetBackend(backend)
+
+	for key, want := range map[string]string{"obj1": "compiled", "obj2": "streamed"} {
+		data, ok := reader.GetBytes(key)
+		if !ok || string(data) != want {
+			t.Fatalf("GetBytes(%q) = %q, %v; want %q", key, data, ok, want)
+		}
+	}
+	if reader.Stats().Entries != 2 {
+		t.Fatalf("expected remote hits to fill the local store, got %d entries", reader.Stats().Entries)
+	}
+
+	// Local copies keep working without the remote.
+	srv.Close()
+	if data, ok := reader.GetBytes("obj1"); !ok || string(data) != "compiled" {
+		t.Fatalf("GetBytes after remote shutdown = %q, %v", data, ok)
+	}
+	if _, ok := reader.GetBytes("missing"); ok {
+		t.Fatal("expected miss when remote is unreachable")
+	}
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -78,6 +79,9 @@ type Store struct {
 	mu        sync.RWMutex
 	entries   map[string]*Entry
 	totalSize int64
+
+	// backend, when set, is an L2 cache behind the local directory.
+	backend Backend
 }
 
 // NewStore creates a new cache store.
@@ -102,14 +106,37 @@ func NewStore(dir string, maxSizeMB int64, ttlHours int) (*Store, error) {
 	return s, nil
 }
 
+// SetBackend makes b a second-level cache: local misses are read through it
+// (and copied into the local store), and every Put is written through to it.
+// Backend errors are logged and treated as misses.
+func (s *Store) SetBackend(b Backend) {
+	s.mu.Lock()
+	s.backend = b
+	s.mu.Unlock()
+}
+
 // Get retrieves a cached item.
 func (s *Store) Get(key string) (io.ReadCloser, bool) {
 	m := metrics.Default()
+	if f, ok := s.getLocal(key); ok {
+		m.RecordCacheHit()
+		return f, true
+	}
+
+	if data, ok := s.getRemote(key); ok {
+		m.RecordCacheHit()
+		return io.NopCloser(bytes.NewReader(data)), true
+	}
+
+	m.RecordCacheMiss()
+	return nil, false
+}
+
+func (s *Store) getLocal(key string) (io.ReadCloser, bool) {
 	s.mu.RLock()
 	entry, ok := s.entries[key]
 	if !ok {
 		s.mu.RUnlock()
-		m.RecordCacheMiss()
 		return nil, false
 	}
 
@@ -117,7 +144,6 @@ func (s *Store) Get(key string) (io.ReadCloser, bool) {
 	if time.Since(entry.CreatedAt) > s.ttl {
 		s.mu.RUnlock()
 		s.Delete(key)
-		m.RecordCacheMiss()
 		return nil, false
 	}
 
@@ -126,7 +152,6 @@ func (s *Store) Get(key string) (io.ReadCloser, bool) {
 	s.mu.RUnlock()
 	if err != nil {
 		s.Delete(key)
-		m.RecordCacheMiss()
 		return nil, false
 	}
 
@@ -138,12 +163,68 @@ func (s *Store) Get(key string) (io.ReadCloser, bool) {
 	}
 	s.mu.Unlock()
 
-	m.RecordCacheHit()
 	return f, true
 }
 
+// getRemote reads key from the backend and fills the local store with it.
+func (s *Store) getRemote(key string) ([]byte, bool) {
+	s.mu.RLock()
+	backend := s.backend
+	s.mu.RUnlock()
+	if backend == nil {
+		return nil, false
+	}
+
+	data, ok, err := backend.Get(key)
+	if err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("Remote cache read failed")
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	if err := s.putLocal(key, bytes.NewReader(data)); err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("Failed to store remote cache entry locally")
+	}
+	return data, true
+}
+
+// putRemote writes data through to the backend, if any.
+func (s *Store) putRemote(key string, data []byte) {
+	s.mu.RLock()
+	backend := s.backend
+	s.mu.RUnlock()
+	if backend == nil {
+		return
+	}
+
+	if err := backend.Put(key, data); err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("Remote cache write failed")
+	}
+}
+
 // Put stores an item in the cache.
 func (s *Store) Put(key string, r io.Reader) error {
+	if err := s.putLocal(key, r); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	hasBackend := s.backend != nil
+	s.mu.RUnlock()
+	if hasBackend {
+		data, err := os.ReadFile(s.keyPath(key))
+		if err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("Failed to read cache entry for remote write")
+			return nil
+		}
+		s.putRemote(key, data)
+	}
+	return nil
+}
+
+func (s *Store) putLocal(key string, r io.Reader) error {
 	// Validate cache key for platform compatibility
 	if err := validateCacheKey(key); err != nil {
 		return fmt.Errorf("invalid cache key: %w", err)
@@ -156,12 +237,13 @@ func (s *Store) Put(key string, r io.Reader) error {
 		return fmt.Errorf("failed to create cache subdir: %w", err)
 	}
 
-	// Write to temp file first
-	tmpPath := path + ".tmp"
-	f, err := os.Create(tmpPath)
+	// Write to a unique temp file first so concurrent writers of the same
+	// key never interleave
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return fmt.Errorf("failed to create temp file: %w", err)
 	}
+	tmpPath := f.Name()
 
 	size, err := io.Copy(f, r)
 	f.Close()
@@ -234,7 +316,12 @@ func (s *Store) PutBytes(key string, data []byte) error {
 	s.mu.Unlock()
 
 	s.evictIfNeeded()
-	return s.saveIndex()
+	if err := s.saveIndex(); err != nil {
+		return err
+	}
+
+	s.putRemote(key, data)
+	return nil
 }
 
 // GetBytes retrieves bytes from cache.
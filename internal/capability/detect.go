@@ -42,6 +42,9 @@ func Detect() *pb.WorkerCapabilities {
 	// Detect Unity capabilities
 	caps.Unity = detectUnity()
 
+	// Detect Cocos Creator capabilities
+	caps.Cocos = detectCocos()
+
 	return caps
 }
 
@@ -600,3 +603,101 @@ func detectUnityBuildTargets(editorPath string) []pb.TargetPlatform {
 
 	return targets
 }
+
+func detectCocos() *pb.CocosCapability {
+	versions := detectCocosVersions()
+	if len(versions) == 0 {
+		return nil
+	}
+
+	hasAndroidSDK := os.Getenv("ANDROID_HOME") != "" || os.Getenv("ANDROID_SDK_ROOT") != ""
+	return &pb.CocosCapability{
+		Versions:  versions,
+		Platforms: cocosPlatforms(runtime.GOOS, hasAndroidSDK),
+	}
+}
+
+// detectCocosVersions lists the Cocos Creator editors installed through
+// Cocos Dashboard. Creator is only distributed for macOS and Windows.
+func detectCocosVersions() []string {
+	var patterns []string
+
+	switch runtime.GOOS {
+	case "darwin":
+		patterns = []string{"/Applications/Cocos/Creator/*/CocosCreator.app"}
+	case "windows":
+		patterns = []string{`C:\ProgramData\cocos\editors\Creator\*\CocosCreator.exe`}
+	}
+
+	versions := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			version := extractCocosVersion(match)
+			if version != "" && !seen[version] {
+				versions = append(versions, version)
+				seen[version] = true
+			}
+		}
+	}
+
+	return versions
+}
+
+// extractCocosVersion returns the version directory that holds a Creator
+// editor, e.g. "3.8.2" for /Applications/Cocos/Creator/3.8.2/CocosCreator.app.
+func extractCocosVersion(path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	version := parts[len(parts)-2]
+	if !isCocosVersion(version) {
+		return ""
+	}
+	return version
+}
+
+func isCocosVersion(s string) bool {
+	if strings.Count(s, ".") < 1 {
+		return false
+	}
+	for _, part := range strings.Split(s, ".") {
+		if part == "" {
+			return false
+		}
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// cocosPlatforms returns the platforms Creator can publish to on goos. Web
+// builds need nothing beyond the editor; native builds need the platform's
+// SDK.
+func cocosPlatforms(goos string, hasAndroidSDK bool) []pb.TargetPlatform {
+	platforms := []pb.TargetPlatform{pb.TargetPlatform_PLATFORM_WEB}
+
+	if hasAndroidSDK {
+		platforms = append(platforms, pb.TargetPlatform_PLATFORM_ANDROID)
+	}
+
+	switch goos {
+	case "darwin":
+		platforms = append(platforms, pb.TargetPlatform_PLATFORM_IOS, pb.TargetPlatform_PLATFORM_MACOS)
+	case "windows":
+		platforms = append(platforms, pb.TargetPlatform_PLATFORM_WINDOWS)
+	}
+
+	return platforms
+}
@@ -1042,3 +1042,100 @@ func TestDetectUnity_BuildTargetsForNonexistent(t *testing.T) {
 		t.Errorf("Expected no targets for nonexistent path, got: %v", targets)
 	}
 }
+
+func TestDetectCocos(t *testing.T) {
+	caps := detectCocos()
+
+	if caps == nil {
+		t.Log("Cocos Creator not installed (this is OK)")
+		return
+	}
+	if len(caps.Versions) == 0 {
+		t.Error("Cocos capability returned but no versions found")
+	}
+	t.Logf("Cocos versions: %v", caps.Versions)
+	t.Logf("Cocos platforms: %v", caps.Platforms)
+}
+
+func TestDetectCocos_VersionExtraction(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "macOS Dashboard path",
+			path: "/Applications/Cocos/Creator/3.8.2/CocosCreator.app",
+			want: "3.8.2",
+		},
+		{
+			name: "Windows Dashboard path",
+			path: `C:\ProgramData\cocos\editors\Creator\2.4.13\CocosCreator.exe`,
+			want: "2.4.13",
+		},
+		{
+			name: "Standalone macOS",
+			path: "/Applications/CocosCreator.app",
+			want: "",
+		},
+		{
+			name: "Non-version directory",
+			path: "/Applications/Cocos/Creator/latest/CocosCreator.app",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractCocosVersion(tt.path)
+			if got != tt.want {
+				t.Errorf("extractCocosVersion(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCocosPlatforms(t *testing.T) {
+	tests := []struct {
+		name          string
+		goos          string
+		hasAndroidSDK bool
+		want          []pb.TargetPlatform
+	}{
+		{
+			name: "linux web only",
+			goos: "linux",
+			want: []pb.TargetPlatform{pb.TargetPlatform_PLATFORM_WEB},
+		},
+		{
+			name:          "darwin with android",
+			goos:          "darwin",
+			hasAndroidSDK: true,
+			want: []pb.TargetPlatform{
+				pb.TargetPlatform_PLATFORM_WEB,
+				pb.TargetPlatform_PLATFORM_ANDROID,
+				pb.TargetPlatform_PLATFORM_IOS,
+				pb.TargetPlatform_PLATFORM_MACOS,
+			},
+		},
+		{
+			name: "windows",
+			goos: "windows",
+			want: []pb.TargetPlatform{pb.TargetPlatform_PLATFORM_WEB, pb.TargetPlatform_PLATFORM_WINDOWS},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cocosPlatforms(tt.goos, tt.hasAndroidSDK)
+			if len(got) != len(tt.want) {
+				t.Fatalf("cocosPlatforms() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("cocosPlatforms() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
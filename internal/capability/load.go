@@ -0,0 +1,158 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+// minSampleInterval bounds how often a LoadSampler measures the host, so
+// frequent health checks do not each run docker ps. Callers within the
+// interval get the previous sample.
+const minSampleInterval = 5 * time.Second
+
+// dockerPsTimeout bounds docker ps, which hangs while the daemon starts.
+const dockerPsTimeout = 5 * time.Second
+
+// LoadSampler measures the load of the host a worker runs on, including
+// work that is not Hybrid-Grid's, such as its owner compiling locally.
+// Measurements the platform does not provide are reported as zero.
+type LoadSampler struct {
+	dir    string // Builds run here; free space is that of its file system
+	docker bool   // Count running containers
+
+	mu        sync.Mutex
+	last      *pb.WorkerLoad
+	sampledAt time.Time
+	prevBusy  uint64
+	prevTotal uint64
+}
+
+// NewLoadSampler creates a sampler reporting the free space of the file
+// system holding dir, and running containers if docker is true.
+func NewLoadSampler(dir string, docker bool) *LoadSampler {
+	return &LoadSampler{dir: dir, docker: docker}
+}
+
+// Sample returns the current load of the host. CPU usage is averaged since
+// the previous sample, or since boot for the first one.
+func (s *LoadSampler) Sample() *pb.WorkerLoad {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.last != nil && time.Since(s.sampledAt) < minSampleInterval {
+		return proto.Clone(s.last).(*pb.WorkerLoad)
+	}
+
+	load := &pb.WorkerLoad{
+		MemoryUsagePercent: memoryUsagePercent(),
+		DiskFreeBytes:      diskFreeBytes(s.dir),
+		LoadAverage_1M:     loadAverage1m(),
+	}
+	if busy, total, ok := cpuTimes(); ok {
+		if total > s.prevTotal && busy >= s.prevBusy {
+			load.CpuUsagePercent = float32(100 * float64(busy-s.prevBusy) / float64(total-s.prevTotal))
+		} else if s.last != nil {
+			load.CpuUsagePercent = s.last.CpuUsagePercent
+		}
+		s.prevBusy, s.prevTotal = busy, total
+	}
+	if s.docker {
+		load.RunningContainers = runningContainers()
+	}
+
+	s.last = load
+	s.sampledAt = time.Now()
+	return proto.Clone(load).(*pb.WorkerLoad)
+}
+
+// runningContainers counts the containers docker ps lists.
+func runningContainers() int32 {
+	ctx, cancel := context.WithTimeout(context.Background(), dockerPsTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "docker", "ps", "--quiet").Output()
+	if err != nil {
+		return 0
+	}
+	return int32(len(bytes.Fields(out)))
+}
+
+// parseProcStat returns the busy and total CPU time of all cores from the
+// aggregate "cpu" line of /proc/stat. Idle and I/O wait count as not busy;
+// guest time is already part of user time.
+func parseProcStat(data string) (busy, total uint64, ok bool) {
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		// user nice system idle iowait irq softirq steal
+		var idle uint64
+		for i, f := range fields[1:] {
+			if i >= 8 {
+				break
+			}
+			v, err := strconv.ParseUint(f, 10, 64)
+			if err != nil {
+				return 0, 0, false
+			}
+			total += v
+			if i == 3 || i == 4 {
+				idle += v
+			}
+		}
+		return total - idle, total, total > 0
+	}
+	return 0, 0, false
+}
+
+// parseMemInfo returns the share of memory in use from /proc/meminfo,
+// counting page cache and other reclaimable memory as available.
+func parseMemInfo(data string) float32 {
+	total, available := int64(0), int64(-1)
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total = v
+		case "MemAvailable:":
+			available = v
+		}
+	}
+	if total <= 0 || available < 0 || available > total {
+		return 0
+	}
+	return float32(100 * float64(total-available) / float64(total))
+}
+
+// parseLoadAvg returns the first number of /proc/loadavg or of the output
+// of sysctl -n vm.loadavg ("{ 1.20 0.98 0.87 }").
+func parseLoadAvg(data string) float64 {
+	for _, f := range strings.Fields(data) {
+		if f == "{" {
+			continue
+		}
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return 0
+		}
+		return v
+	}
+	return 0
+}
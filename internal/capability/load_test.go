@@ -0,0 +1,73 @@
+package capability
+
+import (
+	"math"
+	"runtime"
+	"testing"
+)
+
+func TestParseProcStat(t *testing.T) {
+	data := "cpu  400 100 300 3000 200 0 0 0 50 0\ncpu0 200 50 150 1500 100 0 0 0 25 0\nintr 12345\n"
+
+	busy, total, ok := parseProcStat(data)
+	if !ok {
+		t.Fatal("expected the aggregate cpu line to parse")
+	}
+	if total != 4000 || busy != 800 {
+		t.Errorf("busy/total = %d/%d, want 800/4000", busy, total)
+	}
+
+	if _, _, ok := parseProcStat("cpu0 1 2 3 4 5\n"); ok {
+		t.Error("expected no result without an aggregate cpu line")
+	}
+}
+
+func TestParseMemInfo(t *testing.T) {
+	data := "MemTotal:       16000000 kB\nMemFree:         1000000 kB\nMemAvailable:    4000000 kB\n"
+	if got := parseMemInfo(data); math.Abs(float64(got)-75) > 1e-4 {
+		t.Errorf("memory usage = %v, want 75", got)
+	}
+
+	// Kernels before 3.14 have no MemAvailable
+	if got := parseMemInfo("MemTotal: 16000000 kB\nMemFree: 1000000 kB\n"); got != 0 {
+		t.Errorf("memory usage = %v, want 0 without MemAvailable", got)
+	}
+}
+
+func TestParseLoadAvg(t *testing.T) {
+	tests := map[string]float64{
+		"1.25 0.98 0.87 2/512 12345\n": 1.25,
+		"{ 3.50 2.10 1.00 }\n":         3.5,
+		"":                             0,
+		"garbage":                      0,
+	}
+	for data, want := range tests {
+		if got := parseLoadAvg(data); got != want {
+			t.Errorf("parseLoadAvg(%q) = %v, want %v", data, got, want)
+		}
+	}
+}
+
+func TestLoadSampler_Sample(t *testing.T) {
+	s := NewLoadSampler(t.TempDir(), false)
+
+	load := s.Sample()
+	if load.CpuUsagePercent < 0 || load.CpuUsagePercent > 100 {
+		t.Errorf("cpu usage = %v, want 0-100", load.CpuUsagePercent)
+	}
+	if load.MemoryUsagePercent < 0 || load.MemoryUsagePercent > 100 {
+		t.Errorf("memory usage = %v, want 0-100", load.MemoryUsagePercent)
+	}
+	if load.RunningContainers != 0 {
+		t.Errorf("running containers = %d, want 0 without Docker", load.RunningContainers)
+	}
+	if runtime.GOOS == "linux" && (load.DiskFreeBytes <= 0 || load.MemoryUsagePercent == 0) {
+		t.Errorf("load = %v, want disk and memory measured on Linux", load)
+	}
+
+	// Within minSampleInterval the previous sample is returned
+	load.CpuUsagePercent = -1
+	if again := s.Sample(); again.DiskFreeBytes != load.DiskFreeBytes || again.CpuUsagePercent == -1 {
+		t.Errorf("second sample = %v, want a copy of the first", again)
+	}
+}
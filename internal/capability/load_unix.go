@@ -0,0 +1,67 @@
+//go:build !windows
+
+package capability
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// cpuTimes returns the busy and total CPU time of the host since boot.
+// Only Linux reports them.
+func cpuTimes() (busy, total uint64, ok bool) {
+	if runtime.GOOS != "linux" {
+		return 0, 0, false
+	}
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return 0, 0, false
+	}
+	return parseProcStat(string(data))
+}
+
+// memoryUsagePercent returns the share of memory in use. Only Linux
+// reports it.
+func memoryUsagePercent() float32 {
+	if runtime.GOOS != "linux" {
+		return 0
+	}
+	data, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	return parseMemInfo(string(data))
+}
+
+// loadAverage1m returns the one-minute load average.
+func loadAverage1m() float64 {
+	switch runtime.GOOS {
+	case "linux":
+		data, err := os.ReadFile("/proc/loadavg")
+		if err != nil {
+			return 0
+		}
+		return parseLoadAvg(string(data))
+	case "darwin":
+		out, err := exec.Command("sysctl", "-n", "vm.loadavg").Output()
+		if err != nil {
+			return 0
+		}
+		return parseLoadAvg(string(out))
+	default:
+		return 0
+	}
+}
+
+// diskFreeBytes returns the space available to unprivileged users on the
+// file system holding dir.
+func diskFreeBytes(dir string) int64 {
+	var st unix.Statfs_t
+	if err := unix.Statfs(dir, &st); err != nil {
+		return 0
+	}
+	return int64(st.Bavail) * int64(st.Bsize)
+}
@@ -0,0 +1,77 @@
+//go:build windows
+
+package capability
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32                 = windows.NewLazySystemDLL("kernel32.dll")
+	procGetSystemTimes       = kernel32.NewProc("GetSystemTimes")
+	procGlobalMemoryStatusEx = kernel32.NewProc("GlobalMemoryStatusEx")
+)
+
+// memoryStatusEx mirrors MEMORYSTATUSEX.
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// cpuTimes returns the busy and total CPU time of the host since boot.
+// Kernel time as reported by GetSystemTimes includes idle time.
+func cpuTimes() (busy, total uint64, ok bool) {
+	var idle, kernel, user windows.Filetime
+	r, _, _ := procGetSystemTimes.Call(
+		uintptr(unsafe.Pointer(&idle)),
+		uintptr(unsafe.Pointer(&kernel)),
+		uintptr(unsafe.Pointer(&user)),
+	)
+	if r == 0 {
+		return 0, 0, false
+	}
+	total = filetimeTicks(kernel) + filetimeTicks(user)
+	return total - filetimeTicks(idle), total, total > 0
+}
+
+func filetimeTicks(ft windows.Filetime) uint64 {
+	return uint64(ft.HighDateTime)<<32 | uint64(ft.LowDateTime)
+}
+
+// memoryUsagePercent returns the share of physical memory in use.
+func memoryUsagePercent() float32 {
+	st := memoryStatusEx{Length: uint32(unsafe.Sizeof(memoryStatusEx{}))}
+	r, _, _ := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&st)))
+	if r == 0 || st.TotalPhys == 0 {
+		return 0
+	}
+	return float32(100 * float64(st.TotalPhys-st.AvailPhys) / float64(st.TotalPhys))
+}
+
+// loadAverage1m returns zero: Windows has no load average.
+func loadAverage1m() float64 {
+	return 0
+}
+
+// diskFreeBytes returns the space available to the worker's user on the
+// volume holding dir.
+func diskFreeBytes(dir string) int64 {
+	path, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0
+	}
+	var free uint64
+	if err := windows.GetDiskFreeSpaceEx(path, &free, nil, nil); err != nil {
+		return 0
+	}
+	return int64(free)
+}
@@ -21,6 +21,10 @@ import (
 	"github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
 )
 
+// remoteCacheTimeout bounds each request to the remote cache so an
+// unreachable server does not stall every compile.
+const remoteCacheTimeout = 10 * time.Second
+
 // Service handles distributed compilation with preprocessing and caching.
 type Service struct {
 	cache        *cache.Store
@@ -44,6 +48,7 @@ type Config struct {
 	Verbose         bool
 	MaxRetries      int           // Max retries for transient failures
 	RetryDelay      time.Duration // Initial delay between retries
+	RemoteCacheURL  string        // Bazel HTTP remote cache used as L2 (empty = local only)
 }
 
 // DefaultConfig returns sensible defaults.
@@ -72,6 +77,15 @@ func New(cfg Config) (*Service, error) {
 		cacheStore = nil
 	}
 
+	if cacheStore != nil && cfg.RemoteCacheURL != "" {
+		backend, err := cache.NewHTTPBackend(cfg.RemoteCacheURL, remoteCacheTimeout)
+		if err != nil {
+			log.Warn().Err(err).Msg("Ignoring remote cache, continuing with local cache only")
+		} else {
+			cacheStore.SetBackend(backend)
+		}
+	}
+
 	// Initialize preprocessor
 	preprocessor := compiler.NewPreprocessor(compiler.DefaultPreprocessorConfig())
 
@@ -112,6 +126,7 @@ type Request struct {
 	Args       *compiler.ParsedArgs
 	TargetArch pb.Architecture
 	Timeout    time.Duration
+	BuildID    string // Build whose graph was submitted to the coordinator, if any
 }
 
 // Result represents a build result.
@@ -221,6 +236,10 @@ func (s *Service) Build(ctx context.Context, req *Request) (*Result, error) {
 		}
 
 		// Remote failed, log warning and try fallback
+		if err != nil && ctx.Err() != nil {
+			// The caller gave up (Ctrl-C); do not start a local compile.
+			return nil, ctx.Err()
+		}
 		if err != nil {
 			log.Warn().Err(err).Str("file", req.SourceFile).Msg("Remote compilation failed, trying fallback")
 			result.FallbackReason = fmt.Sprintf("remote error: %v", err)
@@ -394,54 +413,11 @@ func (s *Service) compileRemotePreprocessed(ctx context.Context, req *Request, p
 		TimeoutSeconds:     int32(req.Timeout.Seconds()),
 		ClientOs:           getClientOS(),
 		ClientArch:         getClientArch(),
+		BuildId:            req.BuildID,
+		SourcePath:         buildGraphSourcePath(req),
 	}
 
-	var lastErr error
-	delay := s.retryDelay
-	maxRetries := s.maxRetries
-	if maxRetries <= 0 {
-		maxRetries = 1
-	}
-
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		if attempt > 0 {
-			if s.verbose {
-				log.Debug().
-					Int("attempt", attempt+1).
-					Str("task_id", req.TaskID).
-					Msg("Retrying remote compilation")
-			}
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(delay):
-			}
-			delay *= 2
-			if delay > 5*time.Second {
-				delay = 5 * time.Second
-			}
-		}
-
-		resp, err := s.client.Compile(ctx, compileReq)
-		if err != nil {
-			lastErr = err
-			if isRetryableError(err) {
-				continue
-			}
-			return nil, err
-		}
-
-		return &remoteResult{
-			ObjectFile:      resp.ObjectFile,
-			ExitCode:        int(resp.ExitCode),
-			Stdout:          resp.Stdout,
-			Stderr:          resp.Stderr,
-			CompilationTime: time.Duration(resp.CompilationTimeMs) * time.Millisecond,
-			WorkerID:        resp.WorkerId,
-		}, nil
-	}
-
-	return nil, fmt.Errorf("remote compilation failed after %d attempts: %w", maxRetries, lastErr)
+	return s.sendCompile(ctx, compileReq)
 }
 
 // compileRemoteRaw sends raw source to a remote worker for cross-compilation.
@@ -457,8 +433,29 @@ func (s *Service) compileRemoteRaw(ctx context.Context, req *Request, rawSource
 		TimeoutSeconds: int32(req.Timeout.Seconds()),
 		ClientOs:       getClientOS(),
 		ClientArch:     getClientArch(),
+		BuildId:        req.BuildID,
+		SourcePath:     buildGraphSourcePath(req),
 	}
 
+	return s.sendCompile(ctx, compileReq)
+}
+
+// buildGraphSourcePath returns the absolute source path the coordinator
+// looks up in the build's graph, or "" when no graph was submitted.
+func buildGraphSourcePath(req *Request) string {
+	if req.BuildID == "" {
+		return ""
+	}
+	path, err := filepath.Abs(req.SourceFile)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// sendCompile sends compileReq to the coordinator, retrying transient
+// failures with exponential backoff.
+func (s *Service) sendCompile(ctx context.Context, compileReq *pb.CompileRequest) (*remoteResult, error) {
 	var lastErr error
 	delay := s.retryDelay
 	maxRetries := s.maxRetries
@@ -473,7 +470,7 @@ func (s *Service) compileRemoteRaw(ctx context.Context, req *Request, rawSource
 					Int("attempt", attempt+1).
 					Int("max_retries", maxRetries).
 					Dur("delay", delay).
-					Str("task_id", req.TaskID).
+					Str("task_id", compileReq.TaskId).
 					Msg("Retrying remote compilation")
 			}
 			select {
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cache"
 	"github.com/h3nr1-d14z/hybridgrid/internal/compiler"
 	"github.com/h3nr1-d14z/hybridgrid/internal/grpc/client"
 )
@@ -70,6 +71,22 @@ func TestNew_CacheFailure(t *testing.T) {
 	}
 }
 
+func TestNew_InvalidRemoteCacheKeepsLocalCache(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.RemoteCacheURL = "grpc://cache.local:9092"
+
+	svc, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New should not fail with an unusable remote cache: %v", err)
+	}
+	defer svc.Close()
+
+	if svc.cache == nil {
+		t.Fatal("local cache should still be available")
+	}
+}
+
 func TestService_Build_LocalFallback(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping in short mode")
@@ -955,3 +972,144 @@ func TestService_Build_RemoteFailureFallsBack(t *testing.T) {
 		t.Fatalf("unexpected exit code %d: %s", result.ExitCode, result.Stderr)
 	}
 }
+
+func newTestLink(t *testing.T) *compiler.RemoteLink {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	mainObj := filepath.Join(tmpDir, "main.o")
+	lib := filepath.Join(tmpDir, "libfoo.a")
+	if err := os.WriteFile(mainObj, []byte("main-object"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lib, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	link, err := compiler.PlanRemoteLink([]string{"gcc", mainObj, lib, "-o", filepath.Join(tmpDir, "app")})
+	if err != nil {
+		t.Fatalf("PlanRemoteLink failed: %v", err)
+	}
+	return link
+}
+
+func TestService_Link_CacheHit(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+
+	svc, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer svc.Close()
+
+	link := newTestLink(t)
+	key := svc.generateCacheKeyLink(link, compiler.Version(link.Compiler), map[string]string{
+		"inputs/0-main.o":   cache.HashBytes([]byte("main-object")),
+		"inputs/1-libfoo.a": cache.HashBytes([]byte("archive")),
+	})
+	if err := svc.cache.PutBytes(key, []byte("linked-binary")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := svc.Link(context.Background(), &LinkRequest{TaskID: "link-cache", Link: link, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+	if !result.CacheHit {
+		t.Error("expected cache hit")
+	}
+	if string(result.ObjectFile) != "linked-binary" {
+		t.Errorf("unexpected output: %q", result.ObjectFile)
+	}
+
+	// Changing an input must miss the cache; without a coordinator that is an error.
+	if err := os.WriteFile(link.Inputs["inputs/1-libfoo.a"], []byte("rebuilt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Link(context.Background(), &LinkRequest{TaskID: "link-miss", Link: link, Timeout: time.Second}); err == nil {
+		t.Fatal("expected error for changed input without coordinator")
+	}
+}
+
+func TestService_GenerateCacheKeyLink_CompilerVersion(t *testing.T) {
+	svc := &Service{}
+	link := newTestLink(t)
+	hashes := map[string]string{"inputs/0-main.o": cache.HashBytes([]byte("main-object"))}
+
+	gcc13 := svc.generateCacheKeyLink(link, "gcc (GCC) 13.2.0", hashes)
+	if gcc13 == svc.generateCacheKeyLink(link, "gcc (GCC) 14.1.0", hashes) {
+		t.Error("expected a different key for another compiler version")
+	}
+	if gcc13 != svc.generateCacheKeyLink(link, "gcc (GCC) 13.2.0", hashes) {
+		t.Error("expected the same key for the same compiler version")
+	}
+}
+
+func TestService_Link_RemoteFailure(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+
+	svc, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer svc.Close()
+
+	grpcClient, err := client.New(client.Config{
+		Address:  "localhost:1",
+		Insecure: true,
+		Timeout:  20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("client.New failed: %v", err)
+	}
+	defer grpcClient.Close()
+	svc.SetClient(grpcClient)
+	svc.maxRetries = 1
+	svc.retryDelay = time.Millisecond
+
+	_, err = svc.Link(context.Background(), &LinkRequest{TaskID: "link-remote", Link: newTestLink(t), Timeout: time.Second})
+	if err == nil {
+		t.Fatal("expected remote link to fail")
+	}
+	if !strings.Contains(err.Error(), "remote compilation failed after 1 attempts") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestService_Link_MissingInput(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+
+	svc, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer svc.Close()
+
+	link := &compiler.RemoteLink{
+		Compiler: "gcc",
+		Args:     []string{"inputs/0-missing.o"},
+		Inputs:   map[string]string{"inputs/0-missing.o": filepath.Join(t.TempDir(), "missing.o")},
+		Output:   "app",
+	}
+	if _, err := svc.Link(context.Background(), &LinkRequest{TaskID: "link-missing", Link: link}); err == nil {
+		t.Fatal("expected error for missing input")
+	}
+}
+
+func TestBuildGraphSourcePath(t *testing.T) {
+	if got := buildGraphSourcePath(&Request{SourceFile: "main.c"}); got != "" {
+		t.Errorf("expected no source path without a build ID, got %q", got)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := buildGraphSourcePath(&Request{SourceFile: "src/main.c", BuildID: "build-1"})
+	if want := filepath.Join(wd, "src", "main.c"); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,128 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cache"
+	"github.com/h3nr1-d14z/hybridgrid/internal/compiler"
+)
+
+// LinkRequest represents a link step to run on a worker.
+type LinkRequest struct {
+	TaskID  string
+	Link    *compiler.RemoteLink
+	Timeout time.Duration
+}
+
+// Link runs a link step on a worker with the same OS and architecture as
+// this machine, shipping the object files and static libraries it needs.
+// Results are cached like compiles. Unlike Build, Link does not fall back
+// by itself: any remote failure, including a non-zero linker exit, is
+// returned as an error so the caller can rerun the original command
+// locally, where missing system libraries and the like are authoritative.
+func (s *Service) Link(ctx context.Context, req *LinkRequest) (*Result, error) {
+	startTime := time.Now()
+
+	inputs := make(map[string][]byte, len(req.Link.Inputs))
+	hashes := make(map[string]string, len(req.Link.Inputs))
+	for name, path := range req.Link.Inputs {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read link input: %w", err)
+		}
+		inputs[name] = data
+		hashes[name] = cache.HashBytes(data)
+	}
+
+	compilerVersion := compiler.Version(req.Link.Compiler)
+	cacheKey := s.generateCacheKeyLink(req.Link, compilerVersion, hashes)
+	if s.cache != nil {
+		if cached, ok := s.cache.GetBytes(cacheKey); ok {
+			if s.client != nil {
+				_ = s.client.ReportCacheHit(ctx, 1)
+			}
+			if s.verbose {
+				log.Info().
+					Str("output", req.Link.Output).
+					Str("cache_key", cacheKey).
+					Msg("[cache] Link cache hit")
+			}
+			return &Result{
+				ObjectFile: cached,
+				CacheHit:   true,
+				Duration:   time.Since(startTime),
+			}, nil
+		}
+	}
+
+	if s.client == nil {
+		return nil, fmt.Errorf("no coordinator connection")
+	}
+
+	linkResult, err := s.sendCompile(ctx, &pb.CompileRequest{
+		TaskId:          req.TaskID,
+		Compiler:        req.Link.Compiler,
+		CompilerVersion: compilerVersion,
+		CompilerArgs:    req.Link.Args,
+		Link:            true,
+		LinkInputs:      inputs,
+		TargetArch:      getClientArch(),
+		TimeoutSeconds:  int32(req.Timeout.Seconds()),
+		ClientOs:        getClientOS(),
+		ClientArch:      getClientArch(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if linkResult.ExitCode != 0 {
+		return nil, fmt.Errorf("remote link exited with code %d: %s", linkResult.ExitCode, linkResult.Stderr)
+	}
+	if len(linkResult.ObjectFile) == 0 {
+		return nil, fmt.Errorf("remote link returned no output")
+	}
+
+	if s.cache != nil {
+		if err := s.cache.PutBytes(cacheKey, linkResult.ObjectFile); err != nil {
+			log.Warn().Err(err).Msg("Failed to store in cache")
+		}
+	}
+
+	if s.verbose {
+		log.Info().
+			Str("output", req.Link.Output).
+			Str("worker", linkResult.WorkerID).
+			Int("inputs", len(inputs)).
+			Dur("link_time", linkResult.CompilationTime).
+			Msg("[remote] Link complete")
+	}
+
+	return &Result{
+		ObjectFile:      linkResult.ObjectFile,
+		Stdout:          linkResult.Stdout,
+		Stderr:          linkResult.Stderr,
+		CompilationTime: linkResult.CompilationTime,
+		WorkerID:        linkResult.WorkerID,
+		Duration:        time.Since(startTime),
+	}, nil
+}
+
+// generateCacheKeyLink generates a cache key covering the compiler version,
+// the link arguments and the contents of every input. Links always target
+// the client's platform, so its OS and architecture are part of the key.
+func (s *Service) generateCacheKeyLink(link *compiler.RemoteLink, compilerVersion string, inputHashes map[string]string) string {
+	key := &cache.CompilationKey{
+		Compiler:    link.Compiler,
+		CompilerVer: compilerVersion,
+		TargetArch:  getClientArch().String(),
+		Flags:       link.Args,
+		SourceHash:  cache.LinkInputsHash(link.Args, inputHashes),
+		TargetOS:    getClientOS(),
+	}
+	return key.Build()
+}
@@ -0,0 +1,33 @@
+package cargo
+
+import (
+	"strings"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/submit"
+)
+
+// spoolSourceArchive writes the crate tarball to a temporary file.
+func spoolSourceArchive(projectPath string) (*submit.Archive, error) {
+	return submit.SpoolArchive(projectPath, shouldExclude)
+}
+
+func shouldExclude(relPath string, isDir bool) bool {
+	relPath = strings.TrimPrefix(relPath, "./")
+	if relPath == "" {
+		return false
+	}
+
+	parts := strings.Split(relPath, "/")
+	if len(parts) > 0 {
+		switch parts[0] {
+		case "target", ".git":
+			return true
+		}
+	}
+
+	if !isDir && strings.HasSuffix(relPath, ".DS_Store") {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,176 @@
+package cargo
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+type fakeClient struct {
+	lastRequest  *pb.BuildRequest
+	lastMetadata *pb.BuildMetadata
+	resp         *pb.BuildResponse
+	fetchedTask  string
+}
+
+func (f *fakeClient) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+	f.lastRequest = req
+	if f.resp != nil {
+		return f.resp, nil
+	}
+	return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+}
+
+func (f *fakeClient) StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error) {
+	f.lastMetadata = metadata
+	if _, err := io.Copy(io.Discard, source); err != nil {
+		return nil, err
+	}
+	return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+}
+
+func (f *fakeClient) FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error {
+	f.fetchedTask = taskID
+	return nil
+}
+
+func (f *fakeClient) Close() error {
+	return nil
+}
+
+func newTestCommand(client *fakeClient) *cobra.Command {
+	return NewCommand(Dependencies{
+		CoordinatorAddr: func() string { return "coordinator:9000" },
+		NewClient: func(address string, timeout time.Duration) (BuildClient, error) {
+			return client, nil
+		},
+		RequestTimeout: time.Second,
+	})
+}
+
+func writeCrate(t *testing.T) string {
+	t.Helper()
+
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"Cargo.toml":          "[package]\nname = \"demo\"\n",
+		"src/main.rs":         "fn main() {}\n",
+		"target/debug/stale":  "old build output",
+		".git/HEAD":           "ref: refs/heads/main\n",
+		"benches/bench.rs":    "",
+		"target-notes/README": "kept",
+	}
+	for name, contents := range files {
+		path := filepath.Join(projectDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return projectDir
+}
+
+func TestCargoCLI_RequiresCargoToml(t *testing.T) {
+	cmd := newTestCommand(&fakeClient{})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"build", "--project", t.TempDir()})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "Cargo.toml") {
+		t.Fatalf("expected missing Cargo.toml error, got %v", err)
+	}
+}
+
+func TestCargoCLI_BuildFlags(t *testing.T) {
+	client := &fakeClient{}
+	cmd := newTestCommand(client)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{
+		"build",
+		"--project", writeCrate(t),
+		"--release",
+		"--target", "aarch64-unknown-linux-gnu",
+		"--toolchain", "nightly",
+		"--features", "tls,metrics",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	req := client.lastRequest
+	if req == nil {
+		t.Fatal("expected build request to be sent")
+	}
+	if req.BuildType != pb.BuildType_BUILD_TYPE_RUST {
+		t.Fatalf("unexpected build type: %v", req.BuildType)
+	}
+
+	cfg := req.GetRustConfig()
+	if cfg == nil {
+		t.Fatal("expected rust config")
+	}
+	if !cfg.Release || cfg.Target != "aarch64-unknown-linux-gnu" || cfg.Toolchain != "nightly" {
+		t.Fatalf("unexpected rust config: %v", cfg)
+	}
+	if strings.Join(cfg.Features, ",") != "tls,metrics" {
+		t.Fatalf("unexpected features: %v", cfg.Features)
+	}
+	if len(req.SourceArchive) == 0 || req.SourceHash == "" {
+		t.Fatal("expected source archive and hash")
+	}
+	if !req.DeferArtifacts {
+		t.Fatal("expected artifacts to be deferred")
+	}
+}
+
+func TestCargoCLI_ReportsBuildFailure(t *testing.T) {
+	client := &fakeClient{resp: &pb.BuildResponse{
+		Status:   pb.TaskStatus_STATUS_FAILED,
+		ExitCode: 101,
+		Stderr:   "error[E0425]: cannot find value",
+	}}
+	cmd := newTestCommand(client)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"build", "--project", writeCrate(t)})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "exit 101") || !strings.Contains(err.Error(), "E0425") {
+		t.Fatalf("expected cargo failure, got %v", err)
+	}
+}
+
+func TestShouldExclude(t *testing.T) {
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "target", isDir: true, want: true},
+		{path: "target/debug/demo", want: true},
+		{path: ".git", isDir: true, want: true},
+		{path: "target-notes/README", want: false},
+		{path: "src/main.rs", want: false},
+		{path: "Cargo.lock", want: false},
+		{path: "src/.DS_Store", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := shouldExclude(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("shouldExclude(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
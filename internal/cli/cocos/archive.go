@@ -0,0 +1,32 @@
+package cocos
+
+import (
+	"strings"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/submit"
+)
+
+// spoolSourceArchive writes the project tarball to a temporary file.
+func spoolSourceArchive(projectPath string) (*submit.Archive, error) {
+	return submit.SpoolArchive(projectPath, shouldExclude)
+}
+
+// shouldExclude skips the directories Creator regenerates (library, temp,
+// local, build) along with version control metadata.
+func shouldExclude(relPath string, isDir bool) bool {
+	relPath = strings.TrimPrefix(relPath, "./")
+	if relPath == "" {
+		return false
+	}
+
+	switch strings.Split(relPath, "/")[0] {
+	case "library", "temp", "local", "build", ".git":
+		return true
+	}
+
+	if !isDir && strings.HasSuffix(relPath, ".DS_Store") {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,175 @@
+package cocos
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+type fakeClient struct {
+	lastRequest *pb.BuildRequest
+}
+
+func (f *fakeClient) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+	f.lastRequest = req
+	return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+}
+
+func (f *fakeClient) StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error) {
+	if _, err := io.Copy(io.Discard, source); err != nil {
+		return nil, err
+	}
+	return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+}
+
+func (f *fakeClient) FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error {
+	return nil
+}
+
+func (f *fakeClient) Close() error {
+	return nil
+}
+
+func newTestCommand(client *fakeClient) *cobra.Command {
+	return NewCommand(Dependencies{
+		CoordinatorAddr: func() string { return "coordinator:9000" },
+		NewClient: func(address string, timeout time.Duration) (BuildClient, error) {
+			return client, nil
+		},
+		RequestTimeout: time.Second,
+	})
+}
+
+func writeProject(t *testing.T) string {
+	t.Helper()
+
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, "assets"), 0755); err != nil {
+		t.Fatalf("failed to create assets: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "assets", "main.scene"), []byte("[]"), 0644); err != nil {
+		t.Fatalf("failed to write scene: %v", err)
+	}
+	return projectDir
+}
+
+func TestParseTargetPlatform(t *testing.T) {
+	tests := []struct {
+		input string
+		want  pb.TargetPlatform
+	}{
+		{input: "android", want: pb.TargetPlatform_PLATFORM_ANDROID},
+		{input: "iOS", want: pb.TargetPlatform_PLATFORM_IOS},
+		{input: "web", want: pb.TargetPlatform_PLATFORM_WEB},
+		{input: "windows", want: pb.TargetPlatform_PLATFORM_WINDOWS},
+		{input: "macos", want: pb.TargetPlatform_PLATFORM_MACOS},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTargetPlatform(tt.input)
+		if err != nil || got != tt.want {
+			t.Fatalf("parseTargetPlatform(%q) = %v, %v, want %v", tt.input, got, err, tt.want)
+		}
+	}
+
+	if _, err := parseTargetPlatform("linux"); err == nil || !strings.Contains(err.Error(), "invalid platform") {
+		t.Fatalf("expected invalid platform error, got %v", err)
+	}
+}
+
+func TestCocosCLI_RequiresAssets(t *testing.T) {
+	cmd := newTestCommand(&fakeClient{})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"build", "web", "--project", t.TempDir()})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "assets") {
+		t.Fatalf("expected missing assets error, got %v", err)
+	}
+}
+
+func TestCocosCLI_BuildRequestUsesCocosConfig(t *testing.T) {
+	client := &fakeClient{}
+	cmd := newTestCommand(client)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{
+		"build", "android",
+		"--project", writeProject(t),
+		"--cocos-version", "3.8",
+		"--build-mode", "Debug",
+		"--option", "packageName=com.example.game",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	req := client.lastRequest
+	if req == nil {
+		t.Fatal("expected build request to be sent")
+	}
+	if req.BuildType != pb.BuildType_BUILD_TYPE_COCOS || req.TargetPlatform != pb.TargetPlatform_PLATFORM_ANDROID {
+		t.Fatalf("unexpected build type/platform: %v %v", req.BuildType, req.TargetPlatform)
+	}
+
+	cfg := req.GetCocosConfig()
+	if cfg == nil {
+		t.Fatal("expected cocos config")
+	}
+	if cfg.CocosVersion != "3.8" || cfg.BuildMode != "debug" {
+		t.Fatalf("unexpected cocos config: %v", cfg)
+	}
+	if cfg.PlatformOptions["packageName"] != "com.example.game" {
+		t.Fatalf("unexpected platform options: %v", cfg.PlatformOptions)
+	}
+	if req.SourceHash == "" || len(req.SourceArchive) == 0 {
+		t.Fatal("expected source archive and hash")
+	}
+	if !req.DeferArtifacts {
+		t.Fatal("expected artifacts to be downloaded with FetchArtifacts")
+	}
+}
+
+func TestCocosCLI_RejectsInvalidBuildMode(t *testing.T) {
+	cmd := newTestCommand(&fakeClient{})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"build", "web", "--project", writeProject(t), "--build-mode", "profile"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "build-mode") {
+		t.Fatalf("expected build-mode error, got %v", err)
+	}
+}
+
+func TestShouldExclude(t *testing.T) {
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "library", isDir: true, want: true},
+		{path: "temp/asset-db.json", want: true},
+		{path: "build/web-mobile/index.html", want: true},
+		{path: ".git/HEAD", want: true},
+		{path: "assets/textures/build/bg.png", want: false},
+		{path: "settings/v2/packages/builder.json", want: false},
+		{path: "assets/.DS_Store", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := shouldExclude(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("shouldExclude(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
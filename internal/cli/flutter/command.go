@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/submit"
 )
 
 const (
@@ -24,6 +26,8 @@ const (
 
 type BuildClient interface {
 	Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error)
+	StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error)
+	FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error
 	Close() error
 }
 
@@ -34,6 +38,9 @@ type Dependencies struct {
 	NewClient       ClientFactory
 	RequestTimeout  time.Duration
 	BuildTimeout    time.Duration
+	// StreamThreshold is the archive size above which the project is
+	// uploaded with StreamBuild. Zero uses submit.DefaultStreamThreshold.
+	StreamThreshold int64
 }
 
 func NewCommand(deps Dependencies) *cobra.Command {
@@ -67,6 +74,7 @@ func newBuildTargetCmd(outputType string, deps Dependencies) *cobra.Command {
 		projectPath string
 		buildMode   string
 		flavor      string
+		outputDir   string
 	)
 
 	cmd := &cobra.Command{
@@ -91,23 +99,35 @@ func newBuildTargetCmd(outputType string, deps Dependencies) *cobra.Command {
 				return err
 			}
 
-			req, err := buildRequest(projectPath, outputType, mode, strings.TrimSpace(flavor), deps.BuildTimeout)
+			archive, err := spoolSourceArchive(projectPath)
 			if err != nil {
 				return err
 			}
+			defer archive.Remove()
 
-			resp, err := runBuild(cmd.Context(), deps, req)
+			req, err := buildRequest(archive.Hash, outputType, mode, strings.TrimSpace(flavor), deps.BuildTimeout)
 			if err != nil {
 				return err
 			}
 
-			return printBuildResult(cmd, resp)
+			outDir := strings.TrimSpace(outputDir)
+			if outDir == "" {
+				outDir = projectPath
+			}
+
+			resp, err := runBuild(cmd.Context(), deps, req, archive, outDir, cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+
+			return printBuildResult(cmd, resp, outDir)
 		},
 	}
 
 	cmd.Flags().StringVar(&projectPath, "project", "", "path to Flutter project")
 	cmd.Flags().StringVar(&buildMode, "build-mode", defaultBuildMode, "build mode (debug, profile, release)")
 	cmd.Flags().StringVar(&flavor, "flavor", "", "build flavor")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "directory to write artifacts to (default: the project directory)")
 
 	return cmd
 }
@@ -127,12 +147,7 @@ func normalizeBuildMode(value string) (string, error) {
 	}
 }
 
-func buildRequest(projectPath, outputType, buildMode, flavor string, buildTimeout time.Duration) (*pb.BuildRequest, error) {
-	archive, hash, err := createSourceArchive(projectPath)
-	if err != nil {
-		return nil, err
-	}
-
+func buildRequest(sourceHash, outputType, buildMode, flavor string, buildTimeout time.Duration) (*pb.BuildRequest, error) {
 	mode, err := formatBuildMode(outputType, buildMode)
 	if err != nil {
 		return nil, err
@@ -140,10 +155,9 @@ func buildRequest(projectPath, outputType, buildMode, flavor string, buildTimeou
 
 	req := &pb.BuildRequest{
 		TaskId:         generateTaskID(),
-		SourceHash:     hash,
+		SourceHash:     sourceHash,
 		BuildType:      pb.BuildType_BUILD_TYPE_FLUTTER,
 		TargetPlatform: pb.TargetPlatform_PLATFORM_ANDROID,
-		SourceArchive:  archive,
 		Config: &pb.BuildRequest_FlutterConfig{
 			FlutterConfig: &pb.FlutterConfig{
 				BuildMode: mode,
@@ -174,7 +188,12 @@ func formatBuildMode(outputType, buildMode string) (string, error) {
 	return fmt.Sprintf("%s-%s", outputType, mode), nil
 }
 
-func runBuild(ctx context.Context, deps Dependencies, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+// runBuild submits req to the coordinator. When archive is set it becomes the
+// request's source, inlined or streamed depending on deps.StreamThreshold.
+// The build's output is streamed to progress while it runs. When outputDir
+// is set the artifacts are downloaded into it, with a progress bar written
+// to progress.
+func runBuild(ctx context.Context, deps Dependencies, req *pb.BuildRequest, archive *submit.Archive, outputDir string, progress io.Writer) (*pb.BuildResponse, error) {
 	if deps.CoordinatorAddr == nil {
 		return nil, fmt.Errorf("coordinator resolver not configured")
 	}
@@ -197,15 +216,25 @@ func runBuild(ctx context.Context, deps Dependencies, req *pb.BuildRequest) (*pb
 		ctx = context.Background()
 	}
 
-	resp, err := c.Build(ctx, req)
+	req.DeferArtifacts = outputDir != ""
+
+	stopTail := submit.Tail(ctx, c, req.TaskId, progress)
+	resp, err := submit.Submit(ctx, c, req, archive, deps.StreamThreshold)
+	stopTail()
 	if err != nil {
 		return nil, fmt.Errorf("build failed: %w", err)
 	}
 
+	if outputDir != "" {
+		if _, err := submit.SaveArtifacts(ctx, c, req.TaskId, resp, outputDir, progress); err != nil {
+			return nil, fmt.Errorf("failed to download artifacts: %w", err)
+		}
+	}
+
 	return resp, nil
 }
 
-func printBuildResult(cmd *cobra.Command, resp *pb.BuildResponse) error {
+func printBuildResult(cmd *cobra.Command, resp *pb.BuildResponse, outputDir string) error {
 	if resp == nil {
 		return fmt.Errorf("empty build response")
 	}
@@ -230,6 +259,9 @@ func printBuildResult(cmd *cobra.Command, resp *pb.BuildResponse) error {
 	for _, artifact := range resp.ArtifactList {
 		cmd.Printf("%s (%d bytes)\n", artifact.Path, artifact.SizeBytes)
 	}
+	if outputDir != "" && len(resp.ArtifactList) > 0 {
+		cmd.Printf("Artifacts saved to %s\n", outputDir)
+	}
 
 	return nil
 }
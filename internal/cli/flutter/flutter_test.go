@@ -2,6 +2,8 @@ package flutter
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"os"
 	"path/filepath"
@@ -13,16 +15,44 @@ import (
 )
 
 type fakeClient struct {
-	lastRequest *pb.BuildRequest
+	lastRequest  *pb.BuildRequest
+	lastMetadata *pb.BuildMetadata
+	streamed     []byte
+	artifactList []*pb.ArtifactInfo
+	chunks       []*pb.ArtifactChunk
+	fetchedTask  string
 }
 
 func (f *fakeClient) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
 	f.lastRequest = req
+	return &pb.BuildResponse{
+		Status:       pb.TaskStatus_STATUS_COMPLETED,
+		ArtifactList: f.artifactList,
+	}, nil
+}
+
+func (f *fakeClient) StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error) {
+	f.lastMetadata = metadata
+	data, err := io.ReadAll(source)
+	if err != nil {
+		return nil, err
+	}
+	f.streamed = data
 	return &pb.BuildResponse{
 		Status: pb.TaskStatus_STATUS_COMPLETED,
 	}, nil
 }
 
+func (f *fakeClient) FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error {
+	f.fetchedTask = taskID
+	for _, chunk := range f.chunks {
+		if err := handle(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (f *fakeClient) Close() error {
 	return nil
 }
@@ -112,3 +142,103 @@ func TestFlutterCLI_BuildModeFlavor(t *testing.T) {
 		t.Fatal("expected source hash")
 	}
 }
+
+func TestFlutterCLI_LargeArchiveUsesStreamBuild(t *testing.T) {
+	client := &fakeClient{}
+	cmd := NewCommand(Dependencies{
+		CoordinatorAddr: func() string { return "coordinator:9000" },
+		NewClient: func(address string, timeout time.Duration) (BuildClient, error) {
+			return client, nil
+		},
+		RequestTimeout:  time.Second,
+		StreamThreshold: 16,
+	})
+
+	projectDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(projectDir, "pubspec.yaml"), []byte("name: demo\n"), 0644)
+	if err != nil {
+		t.Fatalf("failed to write test project file: %v", err)
+	}
+
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"build", "apk", "--project", projectDir, "--flavor", "staging"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	if client.lastRequest != nil {
+		t.Fatal("expected unary Build not to be used")
+	}
+	if client.lastMetadata == nil {
+		t.Fatal("expected stream metadata")
+	}
+	if client.lastMetadata.BuildType != pb.BuildType_BUILD_TYPE_FLUTTER {
+		t.Fatalf("unexpected build type: %v", client.lastMetadata.BuildType)
+	}
+	if client.lastMetadata.SourceHash == "" {
+		t.Fatal("expected source hash")
+	}
+	if !strings.Contains(client.lastMetadata.ConfigJson, "staging") {
+		t.Fatalf("expected flavor in config_json, got %q", client.lastMetadata.ConfigJson)
+	}
+	if int64(len(client.streamed)) != client.lastMetadata.TotalSizeBytes {
+		t.Fatalf("streamed %d bytes, metadata says %d", len(client.streamed), client.lastMetadata.TotalSizeBytes)
+	}
+}
+
+func TestFlutterCLI_DownloadsArtifactsToOutputDir(t *testing.T) {
+	apk := []byte("fake apk contents")
+	sum := sha256.Sum256(apk)
+	client := &fakeClient{
+		artifactList: []*pb.ArtifactInfo{{
+			Name:      "app-release.apk",
+			Path:      "build/app/outputs/flutter-apk/app-release.apk",
+			SizeBytes: int64(len(apk)),
+			Checksum:  hex.EncodeToString(sum[:]),
+		}},
+	}
+	client.chunks = []*pb.ArtifactChunk{
+		{Payload: &pb.ArtifactChunk_Info{Info: client.artifactList[0]}},
+		{Payload: &pb.ArtifactChunk_Data{Data: apk[:5]}},
+		{Payload: &pb.ArtifactChunk_Data{Data: apk[5:]}},
+	}
+	cmd := NewCommand(Dependencies{
+		CoordinatorAddr: func() string { return "coordinator:9000" },
+		NewClient: func(address string, timeout time.Duration) (BuildClient, error) {
+			return client, nil
+		},
+		RequestTimeout: time.Second,
+	})
+
+	projectDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(projectDir, "pubspec.yaml"), []byte("name: demo\n"), 0644)
+	if err != nil {
+		t.Fatalf("failed to write test project file: %v", err)
+	}
+	outputDir := t.TempDir()
+
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"build", "apk", "--project", projectDir, "--output-dir", outputDir})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	if !client.lastRequest.DeferArtifacts {
+		t.Fatal("expected artifacts to be deferred")
+	}
+	if client.fetchedTask != client.lastRequest.TaskId {
+		t.Fatalf("fetched task %q, want %q", client.fetchedTask, client.lastRequest.TaskId)
+	}
+
+	got, err := os.ReadFile(filepath.Join(outputDir, "build", "app", "outputs", "flutter-apk", "app-release.apk"))
+	if err != nil {
+		t.Fatalf("expected artifact on disk: %v", err)
+	}
+	if string(got) != string(apk) {
+		t.Fatalf("unexpected artifact contents: %q", got)
+	}
+}
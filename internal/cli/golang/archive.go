@@ -0,0 +1,54 @@
+package golang
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/submit"
+)
+
+// spoolSourceArchive writes the module tarball to a temporary file.
+func spoolSourceArchive(projectPath string) (*submit.Archive, error) {
+	return submit.SpoolArchive(projectPath, shouldExclude)
+}
+
+func shouldExclude(relPath string, isDir bool) bool {
+	relPath = strings.TrimPrefix(relPath, "./")
+	if relPath == "" {
+		return false
+	}
+
+	if relPath == ".git" || strings.HasPrefix(relPath, ".git/") {
+		return true
+	}
+
+	if !isDir && strings.HasSuffix(relPath, ".DS_Store") {
+		return true
+	}
+
+	return false
+}
+
+// hashGoSum returns the SHA-256 of the module's go.sum, or "" when the module
+// has no dependencies.
+func hashGoSum(projectPath string) (string, error) {
+	file, err := os.Open(filepath.Join(projectPath, "go.sum"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.sum: %w", err)
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("failed to read go.sum: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
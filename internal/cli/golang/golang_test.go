@@ -0,0 +1,184 @@
+package golang
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+type fakeClient struct {
+	lastRequest *pb.BuildRequest
+	resp        *pb.BuildResponse
+}
+
+func (f *fakeClient) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+	f.lastRequest = req
+	if f.resp != nil {
+		return f.resp, nil
+	}
+	return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+}
+
+func (f *fakeClient) StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error) {
+	if _, err := io.Copy(io.Discard, source); err != nil {
+		return nil, err
+	}
+	return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+}
+
+func (f *fakeClient) FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error {
+	return nil
+}
+
+func (f *fakeClient) Close() error {
+	return nil
+}
+
+func newTestCommand(client *fakeClient) *cobra.Command {
+	return NewCommand(Dependencies{
+		CoordinatorAddr: func() string { return "coordinator:9000" },
+		NewClient: func(address string, timeout time.Duration) (BuildClient, error) {
+			return client, nil
+		},
+		RequestTimeout: time.Second,
+	})
+}
+
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	projectDir := t.TempDir()
+	for name, contents := range files {
+		path := filepath.Join(projectDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return projectDir
+}
+
+func TestGoCLI_RequiresGoMod(t *testing.T) {
+	cmd := newTestCommand(&fakeClient{})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"build", "--project", t.TempDir()})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "go.mod") {
+		t.Fatalf("expected missing go.mod error, got %v", err)
+	}
+}
+
+func TestGoCLI_BuildFlags(t *testing.T) {
+	goSum := "example.com/dep v1.0.0 h1:abc=\n"
+	projectDir := writeModule(t, map[string]string{
+		"go.mod":          "module example.com/demo\n",
+		"go.sum":          goSum,
+		"cmd/api/main.go": "package main\n",
+	})
+
+	client := &fakeClient{}
+	cmd := newTestCommand(client)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{
+		"build", "./cmd/...",
+		"--project", projectDir,
+		"--go-version", "1.22",
+		"--goos", "linux",
+		"--goarch", "arm64",
+		"--tags", "netgo,osusergo",
+		"--ldflags", "main.version=1.2.3",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	req := client.lastRequest
+	if req == nil {
+		t.Fatal("expected build request to be sent")
+	}
+	if req.BuildType != pb.BuildType_BUILD_TYPE_GO {
+		t.Fatalf("unexpected build type: %v", req.BuildType)
+	}
+
+	cfg := req.GetGoConfig()
+	if cfg == nil {
+		t.Fatal("expected go config")
+	}
+	if cfg.GoVersion != "1.22" || cfg.Goos != "linux" || cfg.Goarch != "arm64" {
+		t.Fatalf("unexpected go config: %v", cfg)
+	}
+	if strings.Join(cfg.BuildTags, ",") != "netgo,osusergo" {
+		t.Fatalf("unexpected tags: %v", cfg.BuildTags)
+	}
+	if cfg.Ldflags["main.version"] != "1.2.3" {
+		t.Fatalf("unexpected ldflags: %v", cfg.Ldflags)
+	}
+	if strings.Join(cfg.Packages, " ") != "./cmd/..." {
+		t.Fatalf("unexpected packages: %v", cfg.Packages)
+	}
+
+	sum := sha256.Sum256([]byte(goSum))
+	if cfg.GoSumHash != hex.EncodeToString(sum[:]) {
+		t.Fatalf("unexpected go.sum hash: %s", cfg.GoSumHash)
+	}
+	if req.SourceHash == "" || len(req.SourceArchive) == 0 {
+		t.Fatal("expected source archive and hash")
+	}
+}
+
+func TestGoCLI_ReportsBuildFailure(t *testing.T) {
+	client := &fakeClient{resp: &pb.BuildResponse{
+		Status:   pb.TaskStatus_STATUS_FAILED,
+		ExitCode: 1,
+		Stderr:   "./main.go:3:2: undefined: foo",
+	}}
+	cmd := newTestCommand(client)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"build", "--project", writeModule(t, map[string]string{"go.mod": "module example.com/demo\n"})})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "undefined: foo") {
+		t.Fatalf("expected go build failure, got %v", err)
+	}
+	if client.lastRequest.GetGoConfig().GetGoSumHash() != "" {
+		t.Fatal("expected empty go.sum hash for a module without go.sum")
+	}
+}
+
+func TestShouldExclude(t *testing.T) {
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: ".git", isDir: true, want: true},
+		{path: ".git/HEAD", want: true},
+		{path: ".github/workflows/ci.yml", want: false},
+		{path: "vendor/modules.txt", want: false},
+		{path: "main.go", want: false},
+		{path: "pkg/.DS_Store", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := shouldExclude(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("shouldExclude(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/submit"
+)
+
+// lockfiles are checked in the order the worker uses to pick a package
+// manager.
+var lockfiles = []string{"pnpm-lock.yaml", "yarn.lock", "package-lock.json", "npm-shrinkwrap.json"}
+
+// spoolSourceArchive writes the project tarball to a temporary file.
+func spoolSourceArchive(projectPath string) (*submit.Archive, error) {
+	return submit.SpoolArchive(projectPath, shouldExclude)
+}
+
+func shouldExclude(relPath string, isDir bool) bool {
+	relPath = strings.TrimPrefix(relPath, "./")
+	if relPath == "" {
+		return false
+	}
+
+	parts := strings.Split(relPath, "/")
+	for _, part := range parts {
+		if part == "node_modules" {
+			return true
+		}
+	}
+	if parts[0] == ".git" {
+		return true
+	}
+
+	if !isDir && strings.HasSuffix(relPath, ".DS_Store") {
+		return true
+	}
+
+	return false
+}
+
+// hashLockfile returns the SHA-256 of the project's lockfile, or "" when it
+// has none.
+func hashLockfile(projectPath string) (string, error) {
+	for _, name := range lockfiles {
+		file, err := os.Open(filepath.Join(projectPath, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		h := sha256.New()
+		_, err = io.Copy(h, file)
+		file.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,242 @@
+package node
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/submit"
+)
+
+type BuildClient interface {
+	Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error)
+	StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error)
+	FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error
+	Close() error
+}
+
+type ClientFactory func(address string, timeout time.Duration) (BuildClient, error)
+
+type Dependencies struct {
+	CoordinatorAddr func() string
+	NewClient       ClientFactory
+	RequestTimeout  time.Duration
+	BuildTimeout    time.Duration
+	// StreamThreshold is the archive size above which the project is
+	// uploaded with StreamBuild. Zero uses submit.DefaultStreamThreshold.
+	StreamThreshold int64
+}
+
+func NewCommand(deps Dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Build Node.js projects with Hybrid-Grid",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newBuildCmd(deps))
+	return cmd
+}
+
+func newBuildCmd(deps Dependencies) *cobra.Command {
+	var (
+		projectPath    string
+		script         string
+		packageManager string
+		nodeVersion    string
+		envVars        map[string]string
+		distDir        string
+		outputDir      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "build",
+		Short: "Install dependencies and run a build script on a remote worker",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectPath = strings.TrimSpace(projectPath)
+			if projectPath == "" {
+				return fmt.Errorf("project required")
+			}
+
+			info, err := os.Stat(projectPath)
+			if err != nil {
+				return fmt.Errorf("project not found: %w", err)
+			}
+			if !info.IsDir() {
+				return fmt.Errorf("project must be a directory")
+			}
+			if _, err := os.Stat(filepath.Join(projectPath, "package.json")); err != nil {
+				return fmt.Errorf("project has no package.json")
+			}
+
+			manager, err := normalizePackageManager(packageManager)
+			if err != nil {
+				return err
+			}
+
+			lockfileHash, err := hashLockfile(projectPath)
+			if err != nil {
+				return err
+			}
+
+			archive, err := spoolSourceArchive(projectPath)
+			if err != nil {
+				return err
+			}
+			defer archive.Remove()
+
+			req := buildRequest(archive.Hash, &pb.NodeConfig{
+				NodeVersion:    strings.TrimSpace(nodeVersion),
+				PackageManager: manager,
+				BuildScript:    strings.TrimSpace(script),
+				EnvVars:        envVars,
+				OutputDir:      strings.TrimSpace(distDir),
+				LockfileHash:   lockfileHash,
+			}, deps.BuildTimeout)
+
+			outDir := strings.TrimSpace(outputDir)
+			if outDir == "" {
+				outDir = projectPath
+			}
+
+			resp, err := runBuild(cmd.Context(), deps, req, archive, outDir, cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+
+			return printBuildResult(cmd, resp, outDir)
+		},
+	}
+
+	cmd.Flags().StringVar(&projectPath, "project", ".", "path to the project (directory containing package.json)")
+	cmd.Flags().StringVar(&script, "script", "build", "package.json script to run")
+	cmd.Flags().StringVar(&packageManager, "package-manager", "", "package manager (npm, yarn, pnpm; default: detected from the lockfile)")
+	cmd.Flags().StringVar(&nodeVersion, "node-version", "", "required Node.js version (e.g. 20)")
+	cmd.Flags().StringToStringVar(&envVars, "env", nil, "environment variables for the install and build (KEY=VALUE)")
+	cmd.Flags().StringVar(&distDir, "dist-dir", "", "build output directory to return (default: dist, build or out)")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "directory to write artifacts to (default: the project directory)")
+
+	return cmd
+}
+
+func normalizePackageManager(value string) (string, error) {
+	manager := strings.ToLower(strings.TrimSpace(value))
+	switch manager {
+	case "", "npm", "yarn", "pnpm":
+		return manager, nil
+	default:
+		return "", fmt.Errorf("invalid package-manager %q (supported: npm, yarn, pnpm)", value)
+	}
+}
+
+func buildRequest(sourceHash string, nodeConfig *pb.NodeConfig, buildTimeout time.Duration) *pb.BuildRequest {
+	req := &pb.BuildRequest{
+		TaskId:     generateTaskID(),
+		SourceHash: sourceHash,
+		BuildType:  pb.BuildType_BUILD_TYPE_NODEJS,
+		Config: &pb.BuildRequest_NodeConfig{
+			NodeConfig: nodeConfig,
+		},
+	}
+
+	if buildTimeout > 0 {
+		req.TimeoutSeconds = int32(buildTimeout.Seconds())
+	}
+
+	return req
+}
+
+// runBuild submits req to the coordinator. When archive is set it becomes the
+// request's source, inlined or streamed depending on deps.StreamThreshold.
+// When outputDir is set the artifacts are downloaded into it, with a progress
+// bar written to progress.
+func runBuild(ctx context.Context, deps Dependencies, req *pb.BuildRequest, archive *submit.Archive, outputDir string, progress io.Writer) (*pb.BuildResponse, error) {
+	if deps.CoordinatorAddr == nil {
+		return nil, fmt.Errorf("coordinator resolver not configured")
+	}
+	if deps.NewClient == nil {
+		return nil, fmt.Errorf("client factory not configured")
+	}
+
+	addr := strings.TrimSpace(deps.CoordinatorAddr())
+	if addr == "" {
+		return nil, fmt.Errorf("coordinator unavailable")
+	}
+
+	c, err := deps.NewClient(addr, deps.RequestTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer c.Close()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	req.DeferArtifacts = outputDir != ""
+
+	resp, err := submit.Submit(ctx, c, req, archive, deps.StreamThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("build failed: %w", err)
+	}
+
+	if outputDir != "" {
+		if _, err := submit.SaveArtifacts(ctx, c, req.TaskId, resp, outputDir, progress); err != nil {
+			return nil, fmt.Errorf("failed to download artifacts: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+func printBuildResult(cmd *cobra.Command, resp *pb.BuildResponse, outputDir string) error {
+	if resp == nil {
+		return fmt.Errorf("empty build response")
+	}
+
+	if resp.Status != pb.TaskStatus_STATUS_COMPLETED {
+		message := fmt.Sprintf("build failed with status %s", resp.Status.String())
+		if resp.ExitCode != 0 {
+			message = fmt.Sprintf("%s (exit %d)", message, resp.ExitCode)
+		}
+		if strings.TrimSpace(resp.Stderr) != "" {
+			message = fmt.Sprintf("%s: %s", message, strings.TrimSpace(resp.Stderr))
+		}
+		return errors.New(message)
+	}
+
+	if resp.FromCache {
+		cmd.Println("Build completed (cache hit)")
+	} else {
+		cmd.Println("Build completed")
+	}
+
+	for _, artifact := range resp.ArtifactList {
+		cmd.Printf("%s (%d bytes)\n", artifact.Path, artifact.SizeBytes)
+	}
+	if outputDir != "" && len(resp.ArtifactList) > 0 {
+		cmd.Printf("Artifacts saved to %s\n", outputDir)
+	}
+
+	return nil
+}
+
+func generateTaskID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("task-%d", time.Now().UnixNano())
+	}
+	return fmt.Sprintf("task-%s-%d", hex.EncodeToString(b), time.Now().UnixNano()%10000)
+}
@@ -0,0 +1,196 @@
+package node
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+type fakeClient struct {
+	lastRequest *pb.BuildRequest
+	resp        *pb.BuildResponse
+}
+
+func (f *fakeClient) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+	f.lastRequest = req
+	if f.resp != nil {
+		return f.resp, nil
+	}
+	return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+}
+
+func (f *fakeClient) StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error) {
+	if _, err := io.Copy(io.Discard, source); err != nil {
+		return nil, err
+	}
+	return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+}
+
+func (f *fakeClient) FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error {
+	return nil
+}
+
+func (f *fakeClient) Close() error {
+	return nil
+}
+
+func newTestCommand(client *fakeClient) *cobra.Command {
+	return NewCommand(Dependencies{
+		CoordinatorAddr: func() string { return "coordinator:9000" },
+		NewClient: func(address string, timeout time.Duration) (BuildClient, error) {
+			return client, nil
+		},
+		RequestTimeout: time.Second,
+	})
+}
+
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	projectDir := t.TempDir()
+	for name, contents := range files {
+		path := filepath.Join(projectDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return projectDir
+}
+
+func TestNodeCLI_RequiresPackageJSON(t *testing.T) {
+	cmd := newTestCommand(&fakeClient{})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"build", "--project", t.TempDir()})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "package.json") {
+		t.Fatalf("expected missing package.json error, got %v", err)
+	}
+}
+
+func TestNodeCLI_BuildFlags(t *testing.T) {
+	lockfile := "lockfileVersion: '9.0'\n"
+	projectDir := writeModule(t, map[string]string{
+		"package.json":        `{"name":"demo"}`,
+		"pnpm-lock.yaml":      lockfile,
+		"package-lock.json":   "{}",
+		"src/index.ts":        "export {}",
+		"node_modules/x/a.js": "module.exports = 1",
+	})
+
+	client := &fakeClient{}
+	cmd := newTestCommand(client)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{
+		"build",
+		"--project", projectDir,
+		"--package-manager", "PNPM",
+		"--script", "build:prod",
+		"--node-version", "20",
+		"--env", "NODE_ENV=production",
+		"--dist-dir", "public",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	req := client.lastRequest
+	if req == nil {
+		t.Fatal("expected build request to be sent")
+	}
+	if req.BuildType != pb.BuildType_BUILD_TYPE_NODEJS {
+		t.Fatalf("unexpected build type: %v", req.BuildType)
+	}
+
+	cfg := req.GetNodeConfig()
+	if cfg == nil {
+		t.Fatal("expected node config")
+	}
+	if cfg.PackageManager != "pnpm" || cfg.BuildScript != "build:prod" || cfg.NodeVersion != "20" || cfg.OutputDir != "public" {
+		t.Fatalf("unexpected node config: %v", cfg)
+	}
+	if cfg.EnvVars["NODE_ENV"] != "production" {
+		t.Fatalf("unexpected env vars: %v", cfg.EnvVars)
+	}
+
+	sum := sha256.Sum256([]byte(lockfile))
+	if cfg.LockfileHash != hex.EncodeToString(sum[:]) {
+		t.Fatalf("expected pnpm-lock.yaml hash, got %s", cfg.LockfileHash)
+	}
+	if req.SourceHash == "" || len(req.SourceArchive) == 0 {
+		t.Fatal("expected source archive and hash")
+	}
+}
+
+func TestNodeCLI_RejectsUnknownPackageManager(t *testing.T) {
+	cmd := newTestCommand(&fakeClient{})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{
+		"build",
+		"--project", writeModule(t, map[string]string{"package.json": "{}"}),
+		"--package-manager", "bun",
+	})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "package-manager") {
+		t.Fatalf("expected package-manager error, got %v", err)
+	}
+}
+
+func TestNodeCLI_ReportsBuildFailure(t *testing.T) {
+	client := &fakeClient{resp: &pb.BuildResponse{
+		Status:   pb.TaskStatus_STATUS_FAILED,
+		ExitCode: 1,
+		Stderr:   "npm ERR! Missing script: \"build\"",
+	}}
+	cmd := newTestCommand(client)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"build", "--project", writeModule(t, map[string]string{"package.json": "{}"})})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "Missing script") {
+		t.Fatalf("expected build failure, got %v", err)
+	}
+	if client.lastRequest.GetNodeConfig().GetLockfileHash() != "" {
+		t.Fatal("expected empty lockfile hash for a project without a lockfile")
+	}
+}
+
+func TestShouldExclude(t *testing.T) {
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "node_modules", isDir: true, want: true},
+		{path: "packages/web/node_modules/react/index.js", want: true},
+		{path: ".git/HEAD", want: true},
+		{path: ".github/workflows/ci.yml", want: false},
+		{path: "src/index.ts", want: false},
+		{path: "src/.DS_Store", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := shouldExclude(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("shouldExclude(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package submit
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ExcludeFunc reports whether a project-relative path (slash separated)
+// should be left out of the source archive.
+type ExcludeFunc func(relPath string, isDir bool) bool
+
+// Archive is a project source tarball spooled to a temporary file.
+type Archive struct {
+	Path string
+	Size int64
+	Hash string // hex SHA-256 of the tar stream
+}
+
+// Open opens the spooled archive for reading.
+func (a *Archive) Open() (*os.File, error) {
+	return os.Open(a.Path)
+}
+
+// ReadAll loads the archive into memory for the unary Build RPC.
+func (a *Archive) ReadAll() ([]byte, error) {
+	return os.ReadFile(a.Path)
+}
+
+// Remove deletes the spooled archive.
+func (a *Archive) Remove() error {
+	if a == nil || a.Path == "" {
+		return nil
+	}
+	return os.Remove(a.Path)
+}
+
+// SpoolArchive writes a tar of projectPath to a temporary file, skipping
+// entries matched by exclude, and returns its size and hash. Callers must
+// Remove the archive when done.
+func SpoolArchive(projectPath string, exclude ExcludeFunc) (*Archive, error) {
+	root, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path: %w", err)
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("project must be a directory")
+	}
+
+	file, err := os.CreateTemp("", "hg-source-*.tar")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive file: %w", err)
+	}
+
+	hash := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(file, hash)}
+	if err := writeTar(root, counter, exclude); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return nil, err
+	}
+
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return nil, fmt.Errorf("failed to finalize archive: %w", err)
+	}
+
+	return &Archive{
+		Path: file.Name(),
+		Size: counter.n,
+		Hash: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+func writeTar(root string, w io.Writer, exclude ExcludeFunc) error {
+	tarWriter := tar.NewWriter(w)
+
+	walkErr := filepath.WalkDir(root, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if path == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if exclude != nil && exclude(rel, entry.IsDir()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		fileInfo, err := file.Stat()
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(fileInfo, "")
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+		header.Name = rel
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			_ = file.Close()
+			return err
+		}
+
+		if _, err := io.Copy(tarWriter, file); err != nil {
+			_ = file.Close()
+			return err
+		}
+
+		return file.Close()
+	})
+
+	if walkErr != nil {
+		_ = tarWriter.Close()
+		return fmt.Errorf("failed to archive project: %w", walkErr)
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,257 @@
+package submit
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/output"
+)
+
+// ArtifactFetcher is the subset of the coordinator client used to download
+// the artifacts of a build submitted with defer_artifacts.
+type ArtifactFetcher interface {
+	FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error
+}
+
+// SaveArtifacts writes the artifacts of a completed build under outputDir,
+// keeping each artifact's relative path. Artifacts inlined in resp are
+// unpacked directly; otherwise they are downloaded chunk by chunk with
+// FetchArtifacts. Each file is verified against its ArtifactInfo checksum
+// before it is moved into place. When progress is non-nil a download bar is
+// written to it. The returned paths are the files written.
+func SaveArtifacts(ctx context.Context, f ArtifactFetcher, taskID string, resp *pb.BuildResponse, outputDir string, progress io.Writer) ([]string, error) {
+	if resp == nil || resp.Status != pb.TaskStatus_STATUS_COMPLETED || len(resp.ArtifactList) == 0 {
+		return nil, nil
+	}
+
+	w := newArtifactWriter(outputDir, resp.ArtifactList, progress)
+	defer w.abort()
+
+	if len(resp.Artifacts) > 0 {
+		if err := w.unpack(resp.Artifacts); err != nil {
+			return nil, err
+		}
+	} else if err := f.FetchArtifacts(ctx, taskID, w.handle); err != nil {
+		return nil, err
+	}
+
+	return w.close()
+}
+
+// artifactWriter streams artifacts to temporary files next to their final
+// location, hashing as it goes.
+type artifactWriter struct {
+	outputDir string
+	expected  map[string]*pb.ArtifactInfo
+	order     []string
+	bar       *output.ProgressBar
+
+	current *pendingArtifact
+	written map[string]string
+}
+
+type pendingArtifact struct {
+	info *pb.ArtifactInfo
+	perm os.FileMode
+	dest string
+	file *os.File
+	hash hash.Hash
+	size int64
+}
+
+func newArtifactWriter(outputDir string, artifactList []*pb.ArtifactInfo, progress io.Writer) *artifactWriter {
+	w := &artifactWriter{
+		outputDir: outputDir,
+		expected:  make(map[string]*pb.ArtifactInfo, len(artifactList)),
+		written:   make(map[string]string, len(artifactList)),
+	}
+
+	var total int64
+	for _, info := range artifactList {
+		w.expected[info.Path] = info
+		w.order = append(w.order, info.Path)
+		total += info.SizeBytes
+	}
+
+	if progress != nil {
+		w.bar = output.DownloadProgressWithWriter(total, "Downloading artifacts", progress)
+	}
+	return w
+}
+
+func (w *artifactWriter) handle(chunk *pb.ArtifactChunk) error {
+	switch payload := chunk.Payload.(type) {
+	case *pb.ArtifactChunk_Info:
+		return w.begin(payload.Info)
+	case *pb.ArtifactChunk_Data:
+		_, err := w.Write(payload.Data)
+		return err
+	}
+	return nil
+}
+
+// unpack writes the artifacts of an inline tar archive.
+func (w *artifactWriter) unpack(archive []byte) error {
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read artifacts: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if err := w.begin(&pb.ArtifactInfo{
+			Name:      filepath.Base(header.Name),
+			Path:      header.Name,
+			SizeBytes: header.Size,
+			Mode:      uint32(header.Mode) & 0o777,
+		}); err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, reader); err != nil {
+			return err
+		}
+	}
+}
+
+// begin finishes the current artifact and starts writing info.
+func (w *artifactWriter) begin(info *pb.ArtifactInfo) error {
+	if err := w.finish(); err != nil {
+		return err
+	}
+	if info == nil {
+		return fmt.Errorf("empty artifact header")
+	}
+
+	// The build response's list is authoritative for checksums; only the
+	// stream knows the file mode.
+	perm := os.FileMode(0o644)
+	if info.Mode&0o111 != 0 {
+		perm = 0o755
+	}
+	if expected, ok := w.expected[info.Path]; ok {
+		info = expected
+	}
+
+	rel := filepath.FromSlash(info.Path)
+	if !filepath.IsLocal(rel) {
+		return fmt.Errorf("invalid artifact path %q", info.Path)
+	}
+	dest := filepath.Join(w.outputDir, rel)
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*.part")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dest, err)
+	}
+
+	w.current = &pendingArtifact{
+		info: info,
+		perm: perm,
+		dest: dest,
+		file: file,
+		hash: sha256.New(),
+	}
+	return nil
+}
+
+// Write appends p to the current artifact.
+func (w *artifactWriter) Write(p []byte) (int, error) {
+	if w.current == nil {
+		return 0, fmt.Errorf("artifact data received before header")
+	}
+
+	n, err := w.current.file.Write(p)
+	w.current.hash.Write(p[:n])
+	w.current.size += int64(n)
+	if w.bar != nil {
+		_ = w.bar.Add(n)
+	}
+	if err != nil {
+		return n, fmt.Errorf("failed to write %s: %w", w.current.dest, err)
+	}
+	return n, nil
+}
+
+// finish verifies the current artifact and moves it into place.
+func (w *artifactWriter) finish() error {
+	cur := w.current
+	if cur == nil {
+		return nil
+	}
+	w.current = nil
+
+	if err := cur.file.Close(); err != nil {
+		_ = os.Remove(cur.file.Name())
+		return fmt.Errorf("failed to write %s: %w", cur.dest, err)
+	}
+
+	if cur.info.SizeBytes > 0 && cur.size != cur.info.SizeBytes {
+		_ = os.Remove(cur.file.Name())
+		return fmt.Errorf("size mismatch for %s: expected %d bytes, got %d", cur.info.Path, cur.info.SizeBytes, cur.size)
+	}
+	if cur.info.Checksum != "" {
+		if sum := hex.EncodeToString(cur.hash.Sum(nil)); sum != cur.info.Checksum {
+			_ = os.Remove(cur.file.Name())
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", cur.info.Path, cur.info.Checksum, sum)
+		}
+	}
+
+	if err := os.Chmod(cur.file.Name(), cur.perm); err != nil {
+		_ = os.Remove(cur.file.Name())
+		return fmt.Errorf("failed to write %s: %w", cur.dest, err)
+	}
+	if err := os.Rename(cur.file.Name(), cur.dest); err != nil {
+		_ = os.Remove(cur.file.Name())
+		return fmt.Errorf("failed to write %s: %w", cur.dest, err)
+	}
+
+	w.written[cur.info.Path] = cur.dest
+	return nil
+}
+
+// close finishes the last artifact and checks that every artifact in the
+// build response arrived.
+func (w *artifactWriter) close() ([]string, error) {
+	if err := w.finish(); err != nil {
+		return nil, err
+	}
+	if w.bar != nil {
+		_ = w.bar.Finish()
+	}
+
+	paths := make([]string, 0, len(w.order))
+	for _, p := range w.order {
+		dest, ok := w.written[p]
+		if !ok {
+			return nil, fmt.Errorf("artifact %s missing from download", p)
+		}
+		paths = append(paths, dest)
+	}
+	return paths, nil
+}
+
+// abort discards a partially written artifact.
+func (w *artifactWriter) abort() {
+	if w.current != nil {
+		_ = w.current.file.Close()
+		_ = os.Remove(w.current.file.Name())
+		w.current = nil
+	}
+}
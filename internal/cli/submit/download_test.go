@@ -0,0 +1,205 @@
+package submit
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+type fakeFetcher struct {
+	chunks []*pb.ArtifactChunk
+}
+
+func (f *fakeFetcher) FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error {
+	for _, chunk := range f.chunks {
+		if err := handle(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func artifactInfo(path string, data []byte) *pb.ArtifactInfo {
+	sum := sha256.Sum256(data)
+	return &pb.ArtifactInfo{
+		Name:      filepath.Base(path),
+		Path:      path,
+		SizeBytes: int64(len(data)),
+		Checksum:  hex.EncodeToString(sum[:]),
+	}
+}
+
+func completed(list ...*pb.ArtifactInfo) *pb.BuildResponse {
+	return &pb.BuildResponse{
+		Status:       pb.TaskStatus_STATUS_COMPLETED,
+		ArtifactList: list,
+	}
+}
+
+func TestSaveArtifacts_StreamsChunksToDisk(t *testing.T) {
+	apk := []byte("apk bytes")
+	aab := []byte("bundle bytes")
+	apkInfo := artifactInfo("out/app.apk", apk)
+	aabInfo := artifactInfo("out/app.aab", aab)
+
+	fetcher := &fakeFetcher{chunks: []*pb.ArtifactChunk{
+		{Payload: &pb.ArtifactChunk_Info{Info: apkInfo}},
+		{Payload: &pb.ArtifactChunk_Data{Data: apk[:3]}},
+		{Payload: &pb.ArtifactChunk_Data{Data: apk[3:]}},
+		{Payload: &pb.ArtifactChunk_Info{Info: aabInfo}},
+		{Payload: &pb.ArtifactChunk_Data{Data: aab}},
+	}}
+
+	outputDir := t.TempDir()
+	var progress bytes.Buffer
+	paths, err := SaveArtifacts(context.Background(), fetcher, "task-1", completed(apkInfo, aabInfo), outputDir, &progress)
+	if err != nil {
+		t.Fatalf("SaveArtifacts failed: %v", err)
+	}
+
+	want := []string{filepath.Join(outputDir, "out", "app.apk"), filepath.Join(outputDir, "out", "app.aab")}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+	for i, data := range [][]byte{apk, aab} {
+		got, err := os.ReadFile(want[i])
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", want[i], err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("%s: got %q, want %q", want[i], got, data)
+		}
+	}
+	if !strings.Contains(progress.String(), "Downloading artifacts") {
+		t.Fatalf("expected progress output, got %q", progress.String())
+	}
+
+	entries, err := os.ReadDir(filepath.Join(outputDir, "out"))
+	if err != nil {
+		t.Fatalf("failed to list output: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected no leftover partial files, got %d entries", len(entries))
+	}
+}
+
+func TestSaveArtifacts_ChecksumMismatch(t *testing.T) {
+	info := artifactInfo("app.apk", []byte("expected"))
+	fetcher := &fakeFetcher{chunks: []*pb.ArtifactChunk{
+		{Payload: &pb.ArtifactChunk_Info{Info: info}},
+		{Payload: &pb.ArtifactChunk_Data{Data: []byte("tampered")}},
+	}}
+
+	outputDir := t.TempDir()
+	_, err := SaveArtifacts(context.Background(), fetcher, "task-1", completed(info), outputDir, nil)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatalf("failed to list output: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected corrupt artifact to be discarded, found %d entries", len(entries))
+	}
+}
+
+func TestSaveArtifacts_MissingArtifact(t *testing.T) {
+	apk := []byte("apk")
+	apkInfo := artifactInfo("app.apk", apk)
+	fetcher := &fakeFetcher{chunks: []*pb.ArtifactChunk{
+		{Payload: &pb.ArtifactChunk_Info{Info: apkInfo}},
+		{Payload: &pb.ArtifactChunk_Data{Data: apk}},
+	}}
+
+	resp := completed(apkInfo, artifactInfo("app.aab", []byte("aab")))
+	_, err := SaveArtifacts(context.Background(), fetcher, "task-1", resp, t.TempDir(), nil)
+	if err == nil || !strings.Contains(err.Error(), "app.aab missing") {
+		t.Fatalf("expected missing artifact error, got %v", err)
+	}
+}
+
+func TestSaveArtifacts_RejectsEscapingPath(t *testing.T) {
+	info := artifactInfo("../evil", []byte("x"))
+	fetcher := &fakeFetcher{chunks: []*pb.ArtifactChunk{
+		{Payload: &pb.ArtifactChunk_Info{Info: info}},
+	}}
+
+	_, err := SaveArtifacts(context.Background(), fetcher, "task-1", completed(info), t.TempDir(), nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid artifact path") {
+		t.Fatalf("expected invalid path error, got %v", err)
+	}
+}
+
+func TestSaveArtifacts_UnpacksInlineArchive(t *testing.T) {
+	apk := []byte("inline apk")
+	info := artifactInfo("build/app.apk", apk)
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	if err := tw.WriteHeader(&tar.Header{Name: info.Path, Mode: 0o644, Size: int64(len(apk))}); err != nil {
+		t.Fatalf("failed to write header: %v", err)
+	}
+	if _, err := tw.Write(apk); err != nil {
+		t.Fatalf("failed to write data: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar: %v", err)
+	}
+
+	resp := completed(info)
+	resp.Artifacts = archive.Bytes()
+
+	outputDir := t.TempDir()
+	if _, err := SaveArtifacts(context.Background(), &fakeFetcher{}, "task-1", resp, outputDir, nil); err != nil {
+		t.Fatalf("SaveArtifacts failed: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(outputDir, "build", "app.apk"))
+	if err != nil {
+		t.Fatalf("expected artifact on disk: %v", err)
+	}
+	if !bytes.Equal(got, apk) {
+		t.Fatalf("got %q, want %q", got, apk)
+	}
+}
+
+func TestSaveArtifacts_KeepsExecutableBit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not meaningful on windows")
+	}
+
+	bin := []byte("\x7fELF")
+	listed := artifactInfo("target/release/demo", bin)
+	streamed := artifactInfo("target/release/demo", bin)
+	streamed.Mode = 0o755
+
+	fetcher := &fakeFetcher{chunks: []*pb.ArtifactChunk{
+		{Payload: &pb.ArtifactChunk_Info{Info: streamed}},
+		{Payload: &pb.ArtifactChunk_Data{Data: bin}},
+	}}
+
+	outputDir := t.TempDir()
+	paths, err := SaveArtifacts(context.Background(), fetcher, "task-1", completed(listed), outputDir, nil)
+	if err != nil {
+		t.Fatalf("SaveArtifacts failed: %v", err)
+	}
+
+	info, err := os.Stat(paths[0])
+	if err != nil {
+		t.Fatalf("failed to stat artifact: %v", err)
+	}
+	if info.Mode().Perm()&0o111 == 0 {
+		t.Fatalf("expected executable artifact, got mode %v", info.Mode())
+	}
+}
@@ -0,0 +1,121 @@
+package submit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/grpc/buildstream"
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/validation"
+)
+
+// DefaultStreamThreshold is the archive size above which builds are uploaded
+// with StreamBuild instead of inlining the archive in a unary Build request.
+const DefaultStreamThreshold = 10 * 1024 * 1024
+
+// cancelTimeout bounds the CancelTask call sent after the caller gives up.
+const cancelTimeout = 5 * time.Second
+
+// tailDrain bounds how long Tail waits for the last lines of output once
+// the build has returned.
+const tailDrain = 2 * time.Second
+
+// Client is the subset of the coordinator client used to submit builds.
+type Client interface {
+	Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error)
+	StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error)
+}
+
+// Canceller is implemented by clients that can cancel a submitted task.
+type Canceller interface {
+	CancelTask(ctx context.Context, taskID string) (bool, error)
+}
+
+// Watcher is implemented by clients that can follow a task's output.
+type Watcher interface {
+	WatchTask(ctx context.Context, taskID string, handle func(*pb.TaskLogLine) error) error
+}
+
+// Tail writes the output of taskID to w line by line while it builds, if c
+// is a Watcher. Start it before Submit so no output is missed; the returned
+// func stops tailing and must be called once Submit returns. Coordinators
+// that cannot stream output are tolerated silently.
+func Tail(ctx context.Context, c Client, taskID string, w io.Writer) func() {
+	watcher, ok := c.(Watcher)
+	if !ok || taskID == "" || w == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = watcher.WatchTask(ctx, taskID, func(line *pb.TaskLogLine) error {
+			_, err := fmt.Fprintln(w, line.Line)
+			return err
+		})
+	}()
+
+	return func() {
+		select {
+		case <-done:
+		case <-time.After(tailDrain):
+		}
+		cancel()
+		<-done
+	}
+}
+
+// Submit sends req with archive as its source. Archives up to threshold bytes
+// are inlined in a unary Build request; larger ones are streamed from disk.
+// A threshold <= 0 selects DefaultStreamThreshold. A nil archive sends req
+// unchanged.
+//
+// If ctx is cancelled (for example by Ctrl-C) and c is a Canceller, the
+// build is cancelled on the coordinator too, so a long Unity or Gradle build
+// does not keep running on the worker.
+func Submit(ctx context.Context, c Client, req *pb.BuildRequest, archive *Archive, threshold int64) (*pb.BuildResponse, error) {
+	resp, err := submit(ctx, c, req, archive, threshold)
+	if ctx.Err() != nil {
+		if canceller, ok := c.(Canceller); ok {
+			cancelCtx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+			defer cancel()
+			_, _ = canceller.CancelTask(cancelCtx, req.TaskId)
+		}
+	}
+	return resp, err
+}
+
+func submit(ctx context.Context, c Client, req *pb.BuildRequest, archive *Archive, threshold int64) (*pb.BuildResponse, error) {
+	if archive == nil {
+		return c.Build(ctx, req)
+	}
+
+	if threshold <= 0 {
+		threshold = DefaultStreamThreshold
+	}
+
+	if archive.Size <= threshold && archive.Size <= validation.MaxSourceArchiveBytes {
+		data, err := archive.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read source archive: %w", err)
+		}
+		req.SourceArchive = data
+		return c.Build(ctx, req)
+	}
+
+	metadata, err := buildstream.MetadataFromRequest(req, archive.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := archive.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open source archive: %w", err)
+	}
+	defer file.Close()
+
+	return c.StreamBuild(ctx, metadata, file)
+}
@@ -0,0 +1,97 @@
+package submit
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+// blockingClient holds every build until its context is cancelled.
+type blockingClient struct {
+	started   chan struct{}
+	cancelled []string
+}
+
+func (c *blockingClient) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+	close(c.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *blockingClient) StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error) {
+	return c.Build(ctx, nil)
+}
+
+func (c *blockingClient) CancelTask(ctx context.Context, taskID string) (bool, error) {
+	c.cancelled = append(c.cancelled, taskID)
+	return true, nil
+}
+
+func TestSubmit_CancelsRemoteTaskWhenContextCancelled(t *testing.T) {
+	c := &blockingClient{started: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-c.started
+		cancel()
+	}()
+
+	_, err := Submit(ctx, c, &pb.BuildRequest{TaskId: "unity-1"}, nil, 0)
+	if err == nil {
+		t.Fatal("Submit() error = nil, want cancellation error")
+	}
+	if len(c.cancelled) != 1 || c.cancelled[0] != "unity-1" {
+		t.Fatalf("CancelTask calls = %v, want [unity-1]", c.cancelled)
+	}
+}
+
+// watchingClient streams fixed output for any task and completes builds
+// immediately.
+type watchingClient struct {
+	lines []string
+}
+
+func (c *watchingClient) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+	return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+}
+
+func (c *watchingClient) StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error) {
+	return c.Build(ctx, nil)
+}
+
+func (c *watchingClient) WatchTask(ctx context.Context, taskID string, handle func(*pb.TaskLogLine) error) error {
+	for _, line := range c.lines {
+		if err := handle(&pb.TaskLogLine{TaskId: taskID, Line: line}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestTail_WritesTaskOutput(t *testing.T) {
+	c := &watchingClient{lines: []string{"Building Unity player", "Build succeeded"}}
+	var out strings.Builder
+
+	stop := Tail(context.Background(), c, "unity-1", &out)
+	if _, err := Submit(context.Background(), c, &pb.BuildRequest{TaskId: "unity-1"}, nil, 0); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	stop()
+
+	if want := "Building Unity player\nBuild succeeded\n"; out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestTail_IgnoresClientsWithoutWatch(t *testing.T) {
+	c := &blockingClient{started: make(chan struct{})}
+	var out strings.Builder
+
+	Tail(context.Background(), c, "unity-1", &out)()
+	if out.Len() != 0 {
+		t.Fatalf("output = %q, want none", out.String())
+	}
+}
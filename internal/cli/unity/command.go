@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -13,10 +14,13 @@ import (
 	"github.com/spf13/cobra"
 
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cli/submit"
 )
 
 type BuildClient interface {
 	Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error)
+	StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error)
+	FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error
 	Close() error
 }
 
@@ -27,6 +31,9 @@ type Dependencies struct {
 	NewClient       ClientFactory
 	RequestTimeout  time.Duration
 	BuildTimeout    time.Duration
+	// StreamThreshold is the archive size above which the project is
+	// uploaded with StreamBuild. Zero uses submit.DefaultStreamThreshold.
+	StreamThreshold int64
 }
 
 func NewCommand(deps Dependencies) *cobra.Command {
@@ -47,6 +54,7 @@ func NewCommand(deps Dependencies) *cobra.Command {
 			buildMethod, _ := cmd.Flags().GetString("build-method")
 			unityVersion, _ := cmd.Flags().GetString("unity-version")
 			scriptingBackend, _ := cmd.Flags().GetString("scripting-backend")
+			outputDir, _ := cmd.Flags().GetString("output-dir")
 
 			projectPath = strings.TrimSpace(projectPath)
 			if projectPath == "" {
@@ -76,17 +84,25 @@ func NewCommand(deps Dependencies) *cobra.Command {
 				return err
 			}
 
-			req, err := buildRequest(projectPath, platform, strings.TrimSpace(unityVersion), buildMethod, backend, deps.BuildTimeout)
+			archive, err := spoolSourceArchive(projectPath)
 			if err != nil {
 				return err
 			}
+			defer archive.Remove()
 
-			resp, err := runBuild(cmd.Context(), deps, req)
+			req := buildRequest(archive.Hash, platform, strings.TrimSpace(unityVersion), buildMethod, backend, deps.BuildTimeout)
+
+			outDir := strings.TrimSpace(outputDir)
+			if outDir == "" {
+				outDir = projectPath
+			}
+
+			resp, err := runBuild(cmd.Context(), deps, req, archive, outDir, cmd.ErrOrStderr())
 			if err != nil {
 				return err
 			}
 
-			return printBuildResult(cmd, resp)
+			return printBuildResult(cmd, resp, outDir)
 		},
 	}
 
@@ -94,6 +110,7 @@ func NewCommand(deps Dependencies) *cobra.Command {
 	buildCmd.Flags().String("build-method", "", "Unity build method (for example: BuildScript.Build)")
 	buildCmd.Flags().String("unity-version", "", "Unity editor version")
 	buildCmd.Flags().String("scripting-backend", "", "scripting backend (mono, il2cpp)")
+	buildCmd.Flags().String("output-dir", "", "directory to write artifacts to (default: the project directory)")
 
 	_ = buildCmd.MarkFlagRequired("project")
 	_ = buildCmd.MarkFlagRequired("build-method")
@@ -136,18 +153,12 @@ func normalizeScriptingBackend(value string) (string, error) {
 	}
 }
 
-func buildRequest(projectPath string, platform pb.TargetPlatform, unityVersion, buildMethod, scriptingBackend string, buildTimeout time.Duration) (*pb.BuildRequest, error) {
-	archive, hash, err := createSourceArchive(projectPath)
-	if err != nil {
-		return nil, err
-	}
-
+func buildRequest(sourceHash string, platform pb.TargetPlatform, unityVersion, buildMethod, scriptingBackend string, buildTimeout time.Duration) *pb.BuildRequest {
 	req := &pb.BuildRequest{
 		TaskId:         generateTaskID(),
-		SourceHash:     hash,
+		SourceHash:     sourceHash,
 		BuildType:      pb.BuildType_BUILD_TYPE_UNITY,
 		TargetPlatform: platform,
-		SourceArchive:  archive,
 		Config: &pb.BuildRequest_UnityConfig{
 			UnityConfig: &pb.UnityConfig{
 				UnityVersion:     unityVersion,
@@ -162,10 +173,15 @@ func buildRequest(projectPath string, platform pb.TargetPlatform, unityVersion,
 		req.TimeoutSeconds = int32(buildTimeout.Seconds())
 	}
 
-	return req, nil
+	return req
 }
 
-func runBuild(ctx context.Context, deps Dependencies, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+// runBuild submits req to the coordinator. When archive is set it becomes the
+// request's source, inlined or streamed depending on deps.StreamThreshold.
+// The build's output is streamed to progress while it runs. When outputDir
+// is set the artifacts are downloaded into it, with a progress bar written
+// to progress.
+func runBuild(ctx context.Context, deps Dependencies, req *pb.BuildRequest, archive *submit.Archive, outputDir string, progress io.Writer) (*pb.BuildResponse, error) {
 	if deps.CoordinatorAddr == nil {
 		return nil, fmt.Errorf("coordinator resolver not configured")
 	}
@@ -188,15 +204,25 @@ func runBuild(ctx context.Context, deps Dependencies, req *pb.BuildRequest) (*pb
 		ctx = context.Background()
 	}
 
-	resp, err := c.Build(ctx, req)
+	req.DeferArtifacts = outputDir != ""
+
+	stopTail := submit.Tail(ctx, c, req.TaskId, progress)
+	resp, err := submit.Submit(ctx, c, req, archive, deps.StreamThreshold)
+	stopTail()
 	if err != nil {
 		return nil, fmt.Errorf("build failed: %w", err)
 	}
 
+	if outputDir != "" {
+		if _, err := submit.SaveArtifacts(ctx, c, req.TaskId, resp, outputDir, progress); err != nil {
+			return nil, fmt.Errorf("failed to download artifacts: %w", err)
+		}
+	}
+
 	return resp, nil
 }
 
-func printBuildResult(cmd *cobra.Command, resp *pb.BuildResponse) error {
+func printBuildResult(cmd *cobra.Command, resp *pb.BuildResponse, outputDir string) error {
 	if resp == nil {
 		return fmt.Errorf("empty build response")
 	}
@@ -221,6 +247,9 @@ func printBuildResult(cmd *cobra.Command, resp *pb.BuildResponse) error {
 	for _, artifact := range resp.ArtifactList {
 		cmd.Printf("%s (%d bytes)\n", artifact.Path, artifact.SizeBytes)
 	}
+	if outputDir != "" && len(resp.ArtifactList) > 0 {
+		cmd.Printf("Artifacts saved to %s\n", outputDir)
+	}
 
 	return nil
 }
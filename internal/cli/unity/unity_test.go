@@ -13,7 +13,8 @@ import (
 )
 
 type fakeClient struct {
-	lastRequest *pb.BuildRequest
+	lastRequest  *pb.BuildRequest
+	lastMetadata *pb.BuildMetadata
 }
 
 func (f *fakeClient) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
@@ -21,6 +22,18 @@ func (f *fakeClient) Build(ctx context.Context, req *pb.BuildRequest) (*pb.Build
 	return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
 }
 
+func (f *fakeClient) StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error) {
+	f.lastMetadata = metadata
+	if _, err := io.Copy(io.Discard, source); err != nil {
+		return nil, err
+	}
+	return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+}
+
+func (f *fakeClient) FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error {
+	return nil
+}
+
 func (f *fakeClient) Close() error {
 	return nil
 }
@@ -223,6 +236,9 @@ func TestUnityCLI_BuildRequestUsesUnityConfig(t *testing.T) {
 	if client.lastRequest.SourceHash == "" {
 		t.Fatal("expected source hash")
 	}
+	if !client.lastRequest.DeferArtifacts {
+		t.Fatal("expected artifacts to be downloaded with FetchArtifacts")
+	}
 }
 
 func TestRunBuild_UsesBuildClient(t *testing.T) {
@@ -239,7 +255,7 @@ func TestRunBuild_UsesBuildClient(t *testing.T) {
 	}
 
 	req := &pb.BuildRequest{TaskId: "task-123"}
-	resp, err := runBuild(context.Background(), deps, req)
+	resp, err := runBuild(context.Background(), deps, req, nil, "", nil)
 	if err != nil {
 		t.Fatalf("runBuild failed: %v", err)
 	}
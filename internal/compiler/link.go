@@ -0,0 +1,136 @@
+package compiler
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RemoteLinkOutput is the name the linked output is written to on the worker.
+const RemoteLinkOutput = "a.out"
+
+// RemoteLink is a link command rewritten to run in an empty directory on
+// another machine.
+type RemoteLink struct {
+	Compiler string
+	// Args are the link arguments with every input replaced by its remote
+	// name and -o removed.
+	Args []string
+	// Inputs maps remote input names to local paths.
+	Inputs map[string]string
+	// Output is the local path the linked output should be written to.
+	Output string
+}
+
+// linkValueFlags take their value as the next argument.
+var linkValueFlags = map[string]bool{
+	"-l": true, "-u": true, "-e": true, "-z": true, "-x": true,
+	"-arch": true, "-target": true, "-framework": true, "-Xlinker": true,
+}
+
+// linkFileFlags reference local files or directories that would not exist
+// on a worker.
+var linkFileFlags = []string{
+	"-L", "-B", "-T", "-F", "-specs", "--specs", "--sysroot", "-isysroot",
+	"-fprofile-use", "-fprofile-instr-use", "-fsanitize-blacklist",
+	"-fsanitize-ignorelist", "@",
+}
+
+// linkerFileOptions are linker options (passed via -Wl or -Xlinker) that
+// read or write local files.
+var linkerFileOptions = []string{
+	"-L", "-T", "--script", "--version-script", "--dynamic-list",
+	"--retain-symbols-file", "-Map", "--sysroot", "@",
+}
+
+// PlanRemoteLink rewrites a link command line so it can run on a worker.
+// Only object files and static libraries are shipped; commands that depend
+// on any other local file (library search paths, linker scripts, shared
+// libraries, response files) are rejected with the reason.
+func PlanRemoteLink(args []string) (*RemoteLink, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("no link arguments")
+	}
+
+	link := &RemoteLink{
+		Compiler: args[0],
+		Args:     make([]string, 0, len(args)),
+		Inputs:   make(map[string]string),
+		Output:   RemoteLinkOutput,
+	}
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "-c" || arg == "-E" || arg == "-S":
+			return nil, fmt.Errorf("%s is not a link step", arg)
+
+		case arg == "-o":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("-o without a value")
+			}
+			i++
+			link.Output = args[i]
+
+		case strings.HasPrefix(arg, "-o"):
+			link.Output = arg[2:]
+
+		case linkValueFlags[arg]:
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s without a value", arg)
+			}
+			i++
+			if arg == "-Xlinker" && readsLinkerFile(args[i]) {
+				return nil, fmt.Errorf("linker option %s references a local file", args[i])
+			}
+			link.Args = append(link.Args, arg, args[i])
+
+		case strings.HasPrefix(arg, "-Wl,"):
+			for _, opt := range strings.Split(arg[4:], ",") {
+				if readsLinkerFile(opt) {
+					return nil, fmt.Errorf("linker option %s references a local file", opt)
+				}
+			}
+			link.Args = append(link.Args, arg)
+
+		case hasAnyPrefix(arg, linkFileFlags):
+			return nil, fmt.Errorf("%s references a local file", arg)
+
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			link.Args = append(link.Args, arg)
+
+		case isObjectFile(arg) || isStaticLibrary(arg):
+			name := "inputs/" + strconv.Itoa(len(link.Inputs)) + "-" + filepath.Base(arg)
+			link.Inputs[name] = arg
+			link.Args = append(link.Args, name)
+
+		default:
+			return nil, fmt.Errorf("input %s is not an object file or static library", arg)
+		}
+	}
+
+	if len(link.Inputs) == 0 {
+		return nil, fmt.Errorf("no object files to link")
+	}
+	return link, nil
+}
+
+func readsLinkerFile(opt string) bool {
+	return hasAnyPrefix(opt, linkerFileOptions)
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func isStaticLibrary(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".a" || ext == ".lib"
+}
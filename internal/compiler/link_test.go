@@ -0,0 +1,73 @@
+package compiler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanRemoteLink(t *testing.T) {
+	link, err := PlanRemoteLink([]string{
+		"g++", "-O2", "main.o", "build/util.o", "-o", "bin/app",
+		"third_party/libgtest.a", "-lpthread", "-Wl,--as-needed", "-u", "main",
+	})
+	if err != nil {
+		t.Fatalf("PlanRemoteLink failed: %v", err)
+	}
+
+	if link.Compiler != "g++" {
+		t.Errorf("Expected compiler 'g++', got '%s'", link.Compiler)
+	}
+	if link.Output != "bin/app" {
+		t.Errorf("Expected output 'bin/app', got '%s'", link.Output)
+	}
+
+	expectedArgs := []string{
+		"-O2", "inputs/0-main.o", "inputs/1-util.o", "inputs/2-libgtest.a",
+		"-lpthread", "-Wl,--as-needed", "-u", "main",
+	}
+	if !reflect.DeepEqual(link.Args, expectedArgs) {
+		t.Errorf("Expected args %v, got %v", expectedArgs, link.Args)
+	}
+
+	expectedInputs := map[string]string{
+		"inputs/0-main.o":     "main.o",
+		"inputs/1-util.o":     "build/util.o",
+		"inputs/2-libgtest.a": "third_party/libgtest.a",
+	}
+	if !reflect.DeepEqual(link.Inputs, expectedInputs) {
+		t.Errorf("Expected inputs %v, got %v", expectedInputs, link.Inputs)
+	}
+}
+
+func TestPlanRemoteLink_DefaultOutput(t *testing.T) {
+	link, err := PlanRemoteLink([]string{"gcc", "foo.o"})
+	if err != nil {
+		t.Fatalf("PlanRemoteLink failed: %v", err)
+	}
+	if link.Output != RemoteLinkOutput {
+		t.Errorf("Expected output '%s', got '%s'", RemoteLinkOutput, link.Output)
+	}
+}
+
+func TestPlanRemoteLink_Rejects(t *testing.T) {
+	tests := [][]string{
+		{"gcc"},
+		{"gcc", "-c", "foo.c"},                          // not a link
+		{"gcc", "-o", "app"},                            // nothing to ship
+		{"gcc", "main.c", "-o", "app"},                  // source input
+		{"gcc", "main.o", "libfoo.so"},                  // shared library
+		{"gcc", "main.o", "-L/opt/lib", "-lfoo"},        // search path
+		{"gcc", "main.o", "-T", "link.ld"},              // linker script
+		{"gcc", "main.o", "-Wl,--version-script=v.map"}, // linker script via -Wl
+		{"gcc", "main.o", "-Xlinker", "-Map=out.map"},   // linker output file
+		{"gcc", "main.o", "--sysroot=/opt/sysroot"},     // sysroot
+		{"gcc", "@args.rsp"},                            // response file
+		{"gcc", "main.o", "-o"},                         // dangling -o
+	}
+
+	for _, args := range tests {
+		if _, err := PlanRemoteLink(args); err == nil {
+			t.Errorf("Args %v: expected error", args)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package compiler
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// versionTimeout bounds how long `<compiler> --version` may take.
+const versionTimeout = 10 * time.Second
+
+// versions remembers Version results by compiler, so a build running many
+// links asks each compiler only once.
+var versions sync.Map
+
+// Version returns the first line of `compiler --version`, such as
+// "gcc (GCC) 13.2.0" or "Apple clang version 15.0.0 (clang-1500.1.0.2.5)".
+// It is empty if the compiler cannot be run. Results are remembered for
+// the life of the process.
+func Version(compiler string) string {
+	if v, ok := versions.Load(compiler); ok {
+		return v.(string)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
+
+	var version string
+	if out, err := exec.CommandContext(ctx, compiler, "--version").Output(); err == nil {
+		line, _, _ := bytes.Cut(out, []byte("\n"))
+		version = string(bytes.TrimSpace(line))
+	}
+	versions.Store(compiler, version)
+	return version
+}
@@ -0,0 +1,27 @@
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the compiler")
+	}
+
+	fakeGcc := filepath.Join(t.TempDir(), "gcc")
+	script := "#!/bin/sh\necho 'gcc (GCC) 13.2.0'\necho 'Copyright (C) 2023 Free Software Foundation, Inc.'\n"
+	if err := os.WriteFile(fakeGcc, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := Version(fakeGcc); got != "gcc (GCC) 13.2.0" {
+		t.Errorf("Version() = %q, want first line of --version", got)
+	}
+	if got := Version(filepath.Join(t.TempDir(), "missing-cc")); got != "" {
+		t.Errorf("Version() of a missing compiler = %q, want empty", got)
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -35,6 +36,18 @@ type Config struct {
 
 	// Tracing settings
 	Tracing TracingConfig `mapstructure:"tracing"`
+
+	// Static workers the coordinator registers itself
+	Workers []StaticWorkerConfig `mapstructure:"workers"`
+}
+
+// StaticWorkerConfig is a worker the coordinator registers and probes
+// instead of waiting for it to handshake, such as a build farm in another
+// office.
+type StaticWorkerConfig struct {
+	Address       string  `mapstructure:"address"`
+	LAN           bool    `mapstructure:"lan"`            // Tagged WAN unless set
+	BandwidthMbps float64 `mapstructure:"bandwidth_mbps"` // Zero if unknown
 }
 
 // TLSConfig holds TLS/mTLS settings.
@@ -75,6 +88,38 @@ type CoordinatorConfig struct {
 	TLSCert    string `mapstructure:"tls_cert"`
 	TLSKey     string `mapstructure:"tls_key"`
 	MDNSEnable bool   `mapstructure:"mdns_enable"`
+
+	// Named API tokens with scopes, stored as SHA-256 hashes.
+	TokensFile string `mapstructure:"tokens_file"`
+
+	// Glob patterns for worker certificate names; requires mTLS.
+	WorkerCertAllow []string `mapstructure:"worker_cert_allow"`
+
+	// Certificate directory whose CA signs certificates for enrolling workers.
+	EnrollDir string `mapstructure:"enroll_dir"`
+
+	// Build result cache for Flutter, Unity and other project builds.
+	BuildCacheDir       string `mapstructure:"build_cache_dir"`
+	BuildCacheMaxSizeMB int64  `mapstructure:"build_cache_max_size_mb"`
+	BuildCacheTTLHours  int    `mapstructure:"build_cache_ttl_hours"`
+
+	// Dispatch queue for requests waiting on a free worker.
+	QueueMaxDepth int           `mapstructure:"queue_max_depth"`
+	QueueTimeout  time.Duration `mapstructure:"queue_timeout"`
+
+	// File keeping registered workers and their statistics across restarts.
+	RegistryFile string `mapstructure:"registry_file"`
+
+	// Leader election among coordinators sharing the registry file.
+	HALeaseFile      string        `mapstructure:"ha_lease_file"`
+	HALeaseTTL       time.Duration `mapstructure:"ha_lease_ttl"`
+	AdvertiseAddress string        `mapstructure:"advertise_address"`
+
+	// Config file whose workers: list the coordinator registers itself.
+	WorkersFile string `mapstructure:"workers_file"`
+
+	// File keeping the learned state of learning schedulers across restarts.
+	SchedulerStateFile string `mapstructure:"scheduler_state_file"`
 }
 
 // WorkerConfig holds worker-specific settings.
@@ -120,6 +165,15 @@ func DefaultConfig() *Config {
 			GRPCPort:   9000,
 			HTTPPort:   8080,
 			MDNSEnable: true,
+
+			BuildCacheDir:       filepath.Join(cacheDir, "hybridgrid-builds"),
+			BuildCacheMaxSizeMB: 10240, // 10GB
+			BuildCacheTTLHours:  168,   // 7 days
+
+			QueueMaxDepth: 1000,
+			QueueTimeout:  2 * time.Minute,
+
+			HALeaseTTL: 15 * time.Second,
 		},
 		Worker: WorkerConfig{
 			Port:         9001,
@@ -205,6 +259,12 @@ func setDefaults(v *viper.Viper, cfg *Config) {
 	v.SetDefault("coordinator.grpc_port", cfg.Coordinator.GRPCPort)
 	v.SetDefault("coordinator.http_port", cfg.Coordinator.HTTPPort)
 	v.SetDefault("coordinator.mdns_enable", cfg.Coordinator.MDNSEnable)
+	v.SetDefault("coordinator.build_cache_dir", cfg.Coordinator.BuildCacheDir)
+	v.SetDefault("coordinator.build_cache_max_size_mb", cfg.Coordinator.BuildCacheMaxSizeMB)
+	v.SetDefault("coordinator.build_cache_ttl_hours", cfg.Coordinator.BuildCacheTTLHours)
+	v.SetDefault("coordinator.queue_max_depth", cfg.Coordinator.QueueMaxDepth)
+	v.SetDefault("coordinator.queue_timeout", cfg.Coordinator.QueueTimeout)
+	v.SetDefault("coordinator.ha_lease_ttl", cfg.Coordinator.HALeaseTTL)
 
 	v.SetDefault("worker.port", cfg.Worker.Port)
 	v.SetDefault("worker.max_parallel", cfg.Worker.MaxParallel)
@@ -245,9 +305,23 @@ coordinator:
   grpc_port: 9000
   http_port: 8080
   auth_token: ""
+  # tokens_file: /etc/hybridgrid/tokens.yaml  # Named, scoped tokens (hg-coord token create)
   mdns_enable: true
   # tls_cert: /path/to/cert.pem
   # tls_key: /path/to/key.pem
+  # worker_cert_allow: ["*.build.example.com"]  # Worker cert SAN/CN patterns (needs mTLS)
+  # enroll_dir: /etc/hybridgrid/pki  # CA for worker enrolment (hg-coord certs init)
+  # build_cache_dir: ~/.cache/hybridgrid-builds
+  build_cache_max_size_mb: 10240
+  build_cache_ttl_hours: 168
+  queue_max_depth: 1000  # Requests waiting for a free worker
+  queue_timeout: 2m
+  # registry_file: /var/lib/hybridgrid/workers.json  # Keep workers across restarts
+  # ha_lease_file: /mnt/shared/hybridgrid/leader.lease  # Standby coordinators (needs a shared registry_file)
+  # ha_lease_ttl: 15s
+  # advertise_address: coord-a.example.com:9000  # Leader address handed to clients
+  # workers_file: /etc/hybridgrid/hybridgrid.yaml  # File with the workers: list below
+  # scheduler_state_file: /var/lib/hybridgrid/scheduler.json  # Keep learned scheduler state across restarts
 
 worker:
   port: 9001
@@ -300,6 +374,15 @@ tracing:
   batch_size: 512               # Max spans to batch before export
   # headers:                      # Additional OTLP headers (optional)
   #   header_name: header_value
+
+# Static workers the coordinator registers and probes itself (optional),
+# for workers that cannot discover or reach it, e.g. a remote build farm.
+# Start them with 'hg-worker serve --no-register'.
+# workers:
+#   - address: farm-1.tokyo.example.com:9001
+#     bandwidth_mbps: 100   # Used to estimate upload times; 0 = unknown
+#   - address: 10.0.0.20:9001
+#     lan: true             # Tagged WAN unless set
 `
 	return os.WriteFile(path, []byte(example), 0644)
 }
@@ -355,6 +438,26 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	// Validate static workers
+	for i := range c.Workers {
+		if err := c.Workers[i].Validate(); err != nil {
+			return fmt.Errorf("config: workers[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate validates a static worker entry.
+func (c *StaticWorkerConfig) Validate() error {
+	if _, _, err := net.SplitHostPort(c.Address); err != nil {
+		return fmt.Errorf("address must be host:port, got %q", c.Address)
+	}
+
+	if c.BandwidthMbps < 0 {
+		return fmt.Errorf("bandwidth_mbps must be >= 0, got %v", c.BandwidthMbps)
+	}
+
 	return nil
 }
 
@@ -372,6 +475,30 @@ func (c *CoordinatorConfig) Validate() error {
 		return fmt.Errorf("config: coordinator.grpc_port and coordinator.http_port must be different, got %d for both", c.GRPCPort)
 	}
 
+	if c.BuildCacheMaxSizeMB <= 0 {
+		return fmt.Errorf("config: coordinator.build_cache_max_size_mb must be > 0, got %d", c.BuildCacheMaxSizeMB)
+	}
+
+	if c.BuildCacheTTLHours <= 0 {
+		return fmt.Errorf("config: coordinator.build_cache_ttl_hours must be > 0, got %d", c.BuildCacheTTLHours)
+	}
+
+	if c.QueueMaxDepth <= 0 {
+		return fmt.Errorf("config: coordinator.queue_max_depth must be > 0, got %d", c.QueueMaxDepth)
+	}
+
+	if c.QueueTimeout <= 0 {
+		return fmt.Errorf("config: coordinator.queue_timeout must be > 0, got %s", c.QueueTimeout)
+	}
+
+	if c.HALeaseFile != "" && c.RegistryFile == "" {
+		return fmt.Errorf("config: coordinator.ha_lease_file requires coordinator.registry_file")
+	}
+
+	if c.HALeaseTTL <= 0 {
+		return fmt.Errorf("config: coordinator.ha_lease_ttl must be > 0, got %s", c.HALeaseTTL)
+	}
+
 	return nil
 }
 
@@ -143,6 +143,67 @@ log:
 	}
 }
 
+func TestLoad_StaticWorkers(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "hybridgrid.yaml")
+
+	configContent := `
+workers:
+  - address: farm-1.example.com:9001
+    bandwidth_mbps: 100
+  - address: 10.0.0.20:9001
+    lan: true
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if len(cfg.Workers) != 2 {
+		t.Fatalf("len(Workers) = %d, want 2", len(cfg.Workers))
+	}
+	if cfg.Workers[0].Address != "farm-1.example.com:9001" || cfg.Workers[0].LAN || cfg.Workers[0].BandwidthMbps != 100 {
+		t.Errorf("Workers[0] = %+v", cfg.Workers[0])
+	}
+	if !cfg.Workers[1].LAN {
+		t.Errorf("Workers[1] = %+v, want LAN", cfg.Workers[1])
+	}
+}
+
+func TestValidate_StaticWorkers(t *testing.T) {
+	tests := []struct {
+		name      string
+		worker    StaticWorkerConfig
+		wantError bool
+		errMsg    string
+	}{
+		{"valid", StaticWorkerConfig{Address: "farm-1:9001", BandwidthMbps: 100}, false, ""},
+		{"missing port", StaticWorkerConfig{Address: "farm-1"}, true, "workers[0]: address"},
+		{"negative bandwidth", StaticWorkerConfig{Address: "farm-1:9001", BandwidthMbps: -1}, true, "bandwidth_mbps"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Workers = []StaticWorkerConfig{tt.worker}
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantError {
+				t.Errorf("Validate() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError && err != nil && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %s, want to contain %s", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
 func TestLoad_InvalidConfigFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "invalid.yaml")
@@ -226,6 +287,11 @@ func TestConfig_CacheDir(t *testing.T) {
 	if cfg.Cache.Dir == "" {
 		t.Error("Cache.Dir should not be empty")
 	}
+
+	// Cache.Clear must not remove coordinator build results
+	if cfg.Coordinator.BuildCacheDir == "" || cfg.Coordinator.BuildCacheDir == cfg.Cache.Dir {
+		t.Errorf("Coordinator.BuildCacheDir = %q, want a directory separate from Cache.Dir", cfg.Coordinator.BuildCacheDir)
+	}
 }
 
 // Validation Tests
@@ -324,6 +390,99 @@ func TestValidate_WorkerMaxParallel(t *testing.T) {
 	}
 }
 
+func TestValidate_CoordinatorBuildCache(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxSize   int64
+		ttl       int
+		wantError bool
+		errMsg    string
+	}{
+		{"build cache valid", 10240, 168, false, ""},
+		{"build cache max size 0", 0, 168, true, "build_cache_max_size_mb"},
+		{"build cache ttl 0", 10240, 0, true, "build_cache_ttl_hours"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Coordinator.BuildCacheMaxSizeMB = tt.maxSize
+			cfg.Coordinator.BuildCacheTTLHours = tt.ttl
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantError {
+				t.Errorf("Validate() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError && err != nil && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %s, want to contain %s", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidate_CoordinatorQueue(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxDepth  int
+		timeout   time.Duration
+		wantError bool
+		errMsg    string
+	}{
+		{"queue valid", 1000, 2 * time.Minute, false, ""},
+		{"queue depth 0", 0, 2 * time.Minute, true, "queue_max_depth"},
+		{"queue timeout 0", 1000, 0, true, "queue_timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Coordinator.QueueMaxDepth = tt.maxDepth
+			cfg.Coordinator.QueueTimeout = tt.timeout
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantError {
+				t.Errorf("Validate() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError && err != nil && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %s, want to contain %s", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidate_CoordinatorHA(t *testing.T) {
+	tests := []struct {
+		name         string
+		leaseFile    string
+		registryFile string
+		ttl          time.Duration
+		wantError    bool
+		errMsg       string
+	}{
+		{"ha disabled", "", "", 15 * time.Second, false, ""},
+		{"ha valid", "/shared/leader.lease", "/shared/workers.json", 15 * time.Second, false, ""},
+		{"ha without registry file", "/shared/leader.lease", "", 15 * time.Second, true, "registry_file"},
+		{"ha lease ttl 0", "/shared/leader.lease", "/shared/workers.json", 0, true, "ha_lease_ttl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Coordinator.HALeaseFile = tt.leaseFile
+			cfg.Coordinator.RegistryFile = tt.registryFile
+			cfg.Coordinator.HALeaseTTL = tt.ttl
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantError {
+				t.Errorf("Validate() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError && err != nil && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %s, want to contain %s", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
 func TestValidate_CacheConfig(t *testing.T) {
 	tests := []struct {
 		name      string
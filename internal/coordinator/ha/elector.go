@@ -0,0 +1,157 @@
+package ha
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RoleMetadataKey is the gRPC trailer in which a coordinator running leader
+// election reports its role, RoleLeader or RoleStandby, so that clients can
+// tell a standby's rejection from the leader's own Unavailable errors.
+const RoleMetadataKey = "hg-coordinator-role"
+
+// Roles reported in RoleMetadataKey.
+const (
+	RoleLeader  = "leader"
+	RoleStandby = "standby"
+)
+
+// Config holds the leader election settings of a coordinator.
+type Config struct {
+	// LeaseFile is the lease shared by all coordinators. Empty disables
+	// leader election; the coordinator is then always the leader.
+	LeaseFile string
+	// LeaseTTL is how long the leader holds the lease without renewing
+	// it. Zero means DefaultLeaseTTL.
+	LeaseTTL time.Duration
+	// ID identifies this coordinator in the lease.
+	ID string
+	// Address is where clients reach this coordinator, handed to clients
+	// that reach a follower.
+	Address string
+}
+
+// Elector takes and renews the lease for one coordinator and tracks
+// whether it is the leader.
+type Elector struct {
+	lease   *Lease
+	id      string
+	address string
+
+	mu        sync.RWMutex
+	leader    bool
+	current   LeaseRecord
+	expiresAt time.Time // of the lease while held by this coordinator
+
+	// cbMu serializes role changes with the callbacks they notify.
+	cbMu      sync.Mutex
+	callbacks []func(leader bool)
+}
+
+// NewElector creates an elector for cfg. It does nothing until Run.
+func NewElector(cfg Config) *Elector {
+	return &Elector{
+		lease:   NewLease(cfg.LeaseFile, cfg.LeaseTTL),
+		id:      cfg.ID,
+		address: cfg.Address,
+	}
+}
+
+// Run takes the lease when it is free and renews it while held, checking
+// three times per TTL. When ctx is done it releases the lease so another
+// coordinator takes over at once.
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.lease.TTL() / 3)
+	defer ticker.Stop()
+
+	e.step()
+	for {
+		select {
+		case <-ctx.Done():
+			if e.IsLeader() {
+				if err := e.lease.Release(e.id); err != nil {
+					log.Warn().Err(err).Msg("Failed to release leader lease")
+				}
+			}
+			e.setLeader(false)
+			return
+		case <-ticker.C:
+			e.step()
+		}
+	}
+}
+
+// step tries to take or renew the lease once.
+func (e *Elector) step() {
+	rec, held, err := e.lease.TryAcquire(e.id, e.address)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to renew leader lease")
+		// Step down before the lease can expire and be taken by another
+		// coordinator; renewals happen every TTL/3.
+		e.mu.RLock()
+		expiring := e.leader && time.Now().After(e.expiresAt.Add(-e.lease.TTL()/3))
+		e.mu.RUnlock()
+		if expiring {
+			e.setLeader(false)
+		}
+		return
+	}
+
+	e.mu.Lock()
+	e.current = rec
+	if held {
+		e.expiresAt = rec.ExpiresAt
+	}
+	e.mu.Unlock()
+	e.setLeader(held)
+}
+
+func (e *Elector) setLeader(leader bool) {
+	e.cbMu.Lock()
+	defer e.cbMu.Unlock()
+
+	e.mu.Lock()
+	changed := e.leader != leader
+	e.leader = leader
+	rec := e.current
+	e.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	if leader {
+		log.Info().Str("id", e.id).Uint64("term", rec.Term).Msg("Became leader coordinator")
+	} else {
+		log.Info().Str("id", e.id).Msg("No longer the leader coordinator")
+	}
+	for _, fn := range e.callbacks {
+		fn(leader)
+	}
+}
+
+// IsLeader reports whether this coordinator holds the lease.
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader
+}
+
+// Leader returns the lease as last seen, naming the current leader.
+func (e *Elector) Leader() LeaseRecord {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.current
+}
+
+// OnChange calls fn with the current role, then whenever it changes.
+// Calls are serialized; fn must not call OnChange.
+func (e *Elector) OnChange(fn func(leader bool)) {
+	e.cbMu.Lock()
+	defer e.cbMu.Unlock()
+
+	e.callbacks = append(e.callbacks, fn)
+	fn(e.IsLeader())
+}
@@ -0,0 +1,114 @@
+package ha
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testTTL = 150 * time.Millisecond
+
+// startElector runs an elector until the returned stop function is called.
+func startElector(t *testing.T, lease, id string) (*Elector, func()) {
+	t.Helper()
+	e := NewElector(Config{LeaseFile: lease, LeaseTTL: testTTL, ID: id, Address: id + ":9000"})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(done)
+	}()
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+	t.Cleanup(stop)
+	return e, stop
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * testTTL)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestElector_OneLeader(t *testing.T) {
+	lease := filepath.Join(t.TempDir(), "leader.lease")
+
+	var electors []*Elector
+	var stops []func()
+	for _, id := range []string{"coord-a", "coord-b", "coord-c"} {
+		e, stop := startElector(t, lease, id)
+		electors = append(electors, e)
+		stops = append(stops, stop)
+	}
+
+	leaders := func() []int {
+		var idx []int
+		for i, e := range electors {
+			if e.IsLeader() {
+				idx = append(idx, i)
+			}
+		}
+		return idx
+	}
+	waitFor(t, "a leader", func() bool { return len(leaders()) == 1 })
+
+	// Let a few renewals happen; leadership must not move or split
+	first := leaders()[0]
+	time.Sleep(2 * testTTL)
+	if got := leaders(); len(got) != 1 || got[0] != first {
+		t.Fatalf("leaders = %v, want only %d", got, first)
+	}
+	for _, e := range electors {
+		if got := e.Leader().Address; e != electors[first] && got != electors[first].address {
+			t.Errorf("follower sees leader %q, want %q", got, electors[first].address)
+		}
+	}
+
+	// Stopping the leader hands over to another coordinator
+	stops[first]()
+	waitFor(t, "a new leader", func() bool {
+		l := leaders()
+		return len(l) == 1 && l[0] != first
+	})
+}
+
+func TestElector_OnChange(t *testing.T) {
+	lease := filepath.Join(t.TempDir(), "leader.lease")
+	e := NewElector(Config{LeaseFile: lease, LeaseTTL: testTTL, ID: "coord-a"})
+
+	var mu sync.Mutex
+	var roles []bool
+	e.OnChange(func(leader bool) {
+		mu.Lock()
+		roles = append(roles, leader)
+		mu.Unlock()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(done)
+	}()
+	waitFor(t, "leadership", e.IsLeader)
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(roles) != 3 || roles[0] || !roles[1] || roles[2] {
+		t.Errorf("roles = %v, want [false true false]", roles)
+	}
+}
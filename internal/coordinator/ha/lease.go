@@ -0,0 +1,180 @@
+// Package ha elects one leader among several coordinators sharing a
+// registry, so builds keep being dispatched when a coordinator goes down.
+package ha
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultLeaseTTL is how long a leader holds the lease without renewing
+// it, and so roughly how long a failover takes.
+const DefaultLeaseTTL = 15 * time.Second
+
+const (
+	// lockWait bounds how long TryAcquire waits for another coordinator
+	// to finish updating the lease.
+	lockWait = 2 * time.Second
+	// staleLock is the age after which a lock left by a coordinator that
+	// died while updating the lease is removed.
+	staleLock = 10 * time.Second
+)
+
+// LeaseRecord is the content of the lease file.
+type LeaseRecord struct {
+	// Holder is the ID of the coordinator holding the lease.
+	Holder string `json:"holder"`
+	// Address is where clients reach the holder.
+	Address string `json:"address"`
+	// Term increases every time the lease changes hands.
+	Term uint64 `json:"term"`
+	// ExpiresAt is when the lease is free to take unless renewed.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Valid reports whether the lease is held at now.
+func (r LeaseRecord) Valid(now time.Time) bool {
+	return r.Holder != "" && now.Before(r.ExpiresAt)
+}
+
+// Lease is a leadership lease kept in a file on storage shared by all
+// coordinators, such as an NFS mount. Updates are serialized with an
+// exclusively created lock file next to it. The coordinators' clocks must
+// be synchronized to well within the lease TTL.
+type Lease struct {
+	path string
+	ttl  time.Duration
+}
+
+// NewLease returns the lease kept in the file at path.
+func NewLease(path string, ttl time.Duration) *Lease {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+	return &Lease{path: path, ttl: ttl}
+}
+
+// TTL returns how long the lease is held without renewing it.
+func (l *Lease) TTL() time.Duration {
+	return l.ttl
+}
+
+// TryAcquire takes the lease for id if it is free or expired, or renews it
+// if id already holds it. It returns the lease as it stands afterwards and
+// whether id holds it.
+func (l *Lease) TryAcquire(id, address string) (LeaseRecord, bool, error) {
+	unlock, err := l.lock()
+	if err != nil {
+		return LeaseRecord{}, false, err
+	}
+	defer unlock()
+
+	rec, err := l.read()
+	if err != nil {
+		return LeaseRecord{}, false, err
+	}
+	now := time.Now()
+	if rec.Holder != id && rec.Valid(now) {
+		return rec, false, nil
+	}
+
+	if rec.Holder != id {
+		rec.Term++
+	}
+	rec.Holder = id
+	rec.Address = address
+	rec.ExpiresAt = now.Add(l.ttl)
+	if err := l.write(rec); err != nil {
+		return LeaseRecord{}, false, err
+	}
+	return rec, true, nil
+}
+
+// Release expires the lease if id holds it, so another coordinator can
+// take over without waiting for the TTL.
+func (l *Lease) Release(id string) error {
+	unlock, err := l.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	rec, err := l.read()
+	if err != nil {
+		return err
+	}
+	if rec.Holder != id {
+		return nil
+	}
+	rec.ExpiresAt = time.Now()
+	return l.write(rec)
+}
+
+// Current returns the lease as last written.
+func (l *Lease) Current() (LeaseRecord, error) {
+	return l.read()
+}
+
+// lock creates the lock file, waiting while another coordinator holds it.
+func (l *Lease) lock() (func(), error) {
+	lockPath := l.path + ".lock"
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("lease %s is locked by another coordinator", l.path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// read returns the lease file, or an unheld lease if there is none yet.
+func (l *Lease) read() (LeaseRecord, error) {
+	var rec LeaseRecord
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return rec, nil
+	}
+	if err != nil {
+		return rec, err
+	}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, fmt.Errorf("failed to parse lease %s: %w", l.path, err)
+	}
+	return rec, nil
+}
+
+func (l *Lease) write(rec LeaseRecord) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), l.path)
+}
@@ -0,0 +1,87 @@
+package ha
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLease_TryAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader.lease")
+	lease := NewLease(path, 200*time.Millisecond)
+
+	rec, held, err := lease.TryAcquire("coord-a", "a:9000")
+	if err != nil || !held {
+		t.Fatalf("TryAcquire() on free lease = %v, %v", held, err)
+	}
+	if rec.Term != 1 || rec.Address != "a:9000" {
+		t.Errorf("lease = %+v, want term 1 at a:9000", rec)
+	}
+
+	rec, held, err = lease.TryAcquire("coord-b", "b:9000")
+	if err != nil || held {
+		t.Fatalf("TryAcquire() on held lease = %v, %v", held, err)
+	}
+	if rec.Holder != "coord-a" {
+		t.Errorf("holder = %q, want coord-a", rec.Holder)
+	}
+
+	// Renewing keeps the term
+	rec, held, err = lease.TryAcquire("coord-a", "a:9000")
+	if err != nil || !held || rec.Term != 1 {
+		t.Fatalf("renewal = %+v, %v, %v", rec, held, err)
+	}
+
+	// A lease not renewed within the TTL can be taken over
+	time.Sleep(250 * time.Millisecond)
+	rec, held, err = lease.TryAcquire("coord-b", "b:9000")
+	if err != nil || !held {
+		t.Fatalf("TryAcquire() on expired lease = %v, %v", held, err)
+	}
+	if rec.Term != 2 {
+		t.Errorf("term = %d, want 2 after takeover", rec.Term)
+	}
+}
+
+func TestLease_Release(t *testing.T) {
+	lease := NewLease(filepath.Join(t.TempDir(), "leader.lease"), time.Hour)
+
+	if _, held, _ := lease.TryAcquire("coord-a", "a:9000"); !held {
+		t.Fatal("expected coord-a to take the lease")
+	}
+	if err := lease.Release("coord-b"); err != nil {
+		t.Fatalf("Release by non-holder failed: %v", err)
+	}
+	if _, held, _ := lease.TryAcquire("coord-b", "b:9000"); held {
+		t.Fatal("Release by non-holder freed the lease")
+	}
+
+	if err := lease.Release("coord-a"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, held, _ := lease.TryAcquire("coord-b", "b:9000"); !held {
+		t.Error("expected coord-b to take a released lease")
+	}
+}
+
+func TestLease_StaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader.lease")
+	lease := NewLease(path, time.Hour)
+
+	// Left behind by a coordinator that died while updating the lease
+	if err := os.WriteFile(path+".lock", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLock)
+	if err := os.Chtimes(path+".lock", old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, held, err := lease.TryAcquire("coord-a", "a:9000"); err != nil || !held {
+		t.Errorf("TryAcquire() with stale lock = %v, %v", held, err)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Error("lock file left behind")
+	}
+}
@@ -0,0 +1,306 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+// DefaultFlushInterval is how often a PersistentRegistry saves changes.
+const DefaultFlushInterval = 5 * time.Second
+
+// restoreMaxAge drops saved workers not heard from for this long, so
+// decommissioned machines do not linger across restarts.
+const restoreMaxAge = 7 * 24 * time.Hour
+
+// Store saves and loads the workers of a PersistentRegistry.
+type Store interface {
+	// Load returns the saved workers, or none if nothing was saved yet.
+	Load() ([]*WorkerInfo, error)
+
+	// Save replaces the saved workers.
+	Save(workers []*WorkerInfo) error
+}
+
+// PersistentRegistry is an InMemoryRegistry whose workers and task
+// statistics are saved to a Store, so a restarted coordinator keeps its
+// workers and the history the schedulers rank them by. Restored workers
+// are in WorkerStateUnknown and not scheduled until they heartbeat.
+//
+// Circuit breaker state is not saved: it describes the last few seconds of
+// calls from one coordinator, and restored workers must heartbeat before
+// they are scheduled anyway, so every circuit starts closed.
+type PersistentRegistry struct {
+	*InMemoryRegistry
+
+	store     Store
+	dirty     atomic.Bool
+	flushStop chan struct{}
+	flushDone chan struct{}
+}
+
+// NewPersistentRegistry creates a registry holding the workers saved in
+// store, and saves changes every flushInterval and on Stop.
+func NewPersistentRegistry(ttl time.Duration, store Store, flushInterval time.Duration) (*PersistentRegistry, error) {
+	r := &PersistentRegistry{
+		InMemoryRegistry: NewInMemoryRegistry(ttl),
+		store:            store,
+		flushStop:        make(chan struct{}),
+		flushDone:        make(chan struct{}),
+	}
+	if err := r.Reload(); err != nil {
+		r.InMemoryRegistry.Stop()
+		return nil, err
+	}
+
+	go r.flushLoop(flushInterval)
+
+	return r, nil
+}
+
+// Reload replaces the workers with the saved ones, as at startup, and
+// drops unsaved changes. A coordinator taking over from another calls it
+// to pick up the workers the other saved to a shared store.
+func (r *PersistentRegistry) Reload() error {
+	workers, err := r.store.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load workers: %w", err)
+	}
+
+	restored := make([]*WorkerInfo, 0, len(workers))
+	for _, w := range workers {
+		if time.Since(w.LastHeartbeat) <= restoreMaxAge {
+			restored = append(restored, w)
+		}
+	}
+
+	r.dirty.Store(false)
+	r.restore(restored)
+	if len(restored) > 0 {
+		log.Info().Int("workers", len(restored)).Msg("Restored workers from registry store")
+	}
+	return nil
+}
+
+// Add registers a new worker or updates an existing one.
+func (r *PersistentRegistry) Add(worker *WorkerInfo) error {
+	return r.changed(r.InMemoryRegistry.Add(worker))
+}
+
+// Remove unregisters a worker.
+func (r *PersistentRegistry) Remove(id string) error {
+	return r.changed(r.InMemoryRegistry.Remove(id))
+}
+
+// UpdateState updates a worker's state.
+func (r *PersistentRegistry) UpdateState(id string, state WorkerState) error {
+	return r.changed(r.InMemoryRegistry.UpdateState(id, state))
+}
+
+// UpdateHeartbeat updates the last heartbeat time.
+func (r *PersistentRegistry) UpdateHeartbeat(id string) error {
+	return r.changed(r.InMemoryRegistry.UpdateHeartbeat(id))
+}
+
+// IncrementTasks increments the active task count.
+func (r *PersistentRegistry) IncrementTasks(id string) error {
+	return r.changed(r.InMemoryRegistry.IncrementTasks(id))
+}
+
+// DecrementTasks decrements the active task count.
+func (r *PersistentRegistry) DecrementTasks(id string, success bool, compileTime time.Duration) error {
+	return r.changed(r.InMemoryRegistry.DecrementTasks(id, success, compileTime))
+}
+
+// changed marks the registry for saving unless the update failed.
+func (r *PersistentRegistry) changed(err error) error {
+	if err == nil {
+		r.dirty.Store(true)
+	}
+	return err
+}
+
+// Flush saves the workers if they changed since the last save.
+func (r *PersistentRegistry) Flush() error {
+	if !r.dirty.Swap(false) {
+		return nil
+	}
+	if err := r.store.Save(r.List()); err != nil {
+		r.dirty.Store(true)
+		return err
+	}
+	return nil
+}
+
+func (r *PersistentRegistry) flushLoop(interval time.Duration) {
+	defer close(r.flushDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.Flush(); err != nil {
+				log.Warn().Err(err).Msg("Failed to save worker registry")
+			}
+		case <-r.flushStop:
+			return
+		}
+	}
+}
+
+// Stop saves pending changes and stops the background goroutines.
+func (r *PersistentRegistry) Stop() {
+	close(r.flushStop)
+	<-r.flushDone
+	if err := r.Flush(); err != nil {
+		log.Warn().Err(err).Msg("Failed to save worker registry")
+	}
+	r.InMemoryRegistry.Stop()
+}
+
+// FileStore is a Store keeping workers in a JSON file, replaced
+// atomically on every save.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore returns a Store backed by the file at path. The file and
+// its directory are created on the first save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// fileStoreVersion is the format of the file written by FileStore.
+const fileStoreVersion = 1
+
+type storedRegistry struct {
+	Version int            `json:"version"`
+	Workers []storedWorker `json:"workers"`
+}
+
+type storedWorker struct {
+	ID              string          `json:"id"`
+	Address         string          `json:"address"`
+	Capabilities    json.RawMessage `json:"capabilities,omitempty"`
+	DiscoverySource string          `json:"discovery_source,omitempty"`
+	LastHeartbeat   time.Time       `json:"last_heartbeat"`
+	RegisteredAt    time.Time       `json:"registered_at"`
+	MaxParallel     int32           `json:"max_parallel"`
+	RTT             time.Duration   `json:"rtt_ns,omitempty"`
+	BandwidthMbps   float64         `json:"bandwidth_mbps,omitempty"`
+	TotalTasks      int64           `json:"total_tasks"`
+	SuccessfulTasks int64           `json:"successful_tasks"`
+	FailedTasks     int64           `json:"failed_tasks"`
+	AvgCompileTime  time.Duration   `json:"avg_compile_time_ns"`
+}
+
+// Load reads the workers from the file. A missing file holds no workers.
+func (s *FileStore) Load() ([]*WorkerInfo, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stored storedRegistry
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.path, err)
+	}
+	if stored.Version != fileStoreVersion {
+		return nil, fmt.Errorf("%s has unsupported version %d", s.path, stored.Version)
+	}
+
+	workers := make([]*WorkerInfo, 0, len(stored.Workers))
+	for _, sw := range stored.Workers {
+		w := &WorkerInfo{
+			ID:              sw.ID,
+			Address:         sw.Address,
+			DiscoverySource: sw.DiscoverySource,
+			LastHeartbeat:   sw.LastHeartbeat,
+			RegisteredAt:    sw.RegisteredAt,
+			MaxParallel:     sw.MaxParallel,
+			RTT:             sw.RTT,
+			BandwidthMbps:   sw.BandwidthMbps,
+			TotalTasks:      sw.TotalTasks,
+			SuccessfulTasks: sw.SuccessfulTasks,
+			FailedTasks:     sw.FailedTasks,
+			AvgCompileTime:  sw.AvgCompileTime,
+		}
+		if len(sw.Capabilities) > 0 {
+			w.Capabilities = &pb.WorkerCapabilities{}
+			if err := protojson.Unmarshal(sw.Capabilities, w.Capabilities); err != nil {
+				return nil, fmt.Errorf("failed to parse capabilities of worker %s: %w", sw.ID, err)
+			}
+		}
+		workers = append(workers, w)
+	}
+	return workers, nil
+}
+
+// Save writes the workers to a temporary file and renames it over the
+// file, so a crash never leaves a partial registry behind.
+func (s *FileStore) Save(workers []*WorkerInfo) error {
+	stored := storedRegistry{
+		Version: fileStoreVersion,
+		Workers: make([]storedWorker, 0, len(workers)),
+	}
+	for _, w := range workers {
+		sw := storedWorker{
+			ID:              w.ID,
+			Address:         w.Address,
+			DiscoverySource: w.DiscoverySource,
+			LastHeartbeat:   w.LastHeartbeat,
+			RegisteredAt:    w.RegisteredAt,
+			MaxParallel:     w.MaxParallel,
+			RTT:             w.RTT,
+			BandwidthMbps:   w.BandwidthMbps,
+			TotalTasks:      w.TotalTasks,
+			SuccessfulTasks: w.SuccessfulTasks,
+			FailedTasks:     w.FailedTasks,
+			AvgCompileTime:  w.AvgCompileTime,
+		}
+		if w.Capabilities != nil {
+			caps, err := protojson.Marshal(w.Capabilities)
+			if err != nil {
+				return fmt.Errorf("failed to encode capabilities of worker %s: %w", w.ID, err)
+			}
+			sw.Capabilities = caps
+		}
+		stored.Workers = append(stored.Workers, sw)
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,227 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+func openPersistent(t *testing.T, path string) *PersistentRegistry {
+	t.Helper()
+	r, err := NewPersistentRegistry(30*time.Second, NewFileStore(path), time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentRegistry failed: %v", err)
+	}
+	return r
+}
+
+func TestPersistentRegistry_RestoresWorkers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry", "workers.json")
+
+	r := openPersistent(t, path)
+	err := r.Add(&WorkerInfo{
+		ID:              "cpp-worker",
+		Address:         "10.0.0.5:50052",
+		MaxParallel:     8,
+		DiscoverySource: DiscoverySourceWAN,
+		RTT:             80 * time.Millisecond,
+		BandwidthMbps:   100,
+		Capabilities: &pb.WorkerCapabilities{
+			Hostname:   "build-1",
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			Cpp:        &pb.CppCapability{Compilers: []string{"gcc"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	r.IncrementTasks("cpp-worker")
+	r.DecrementTasks("cpp-worker", false, 0)
+	r.IncrementTasks("cpp-worker")
+	r.DecrementTasks("cpp-worker", true, 200*time.Millisecond)
+	r.IncrementTasks("cpp-worker") // still running at shutdown
+	r.Stop()
+
+	r = openPersistent(t, path)
+	defer r.Stop()
+
+	got, ok := r.Get("cpp-worker")
+	if !ok {
+		t.Fatal("worker not restored")
+	}
+	if got.State != WorkerStateUnknown {
+		t.Errorf("Expected state unknown, got %s", got.State)
+	}
+	if got.Address != "10.0.0.5:50052" || got.MaxParallel != 8 {
+		t.Errorf("Restored address %q, max parallel %d", got.Address, got.MaxParallel)
+	}
+	if !got.IsWAN() || got.RTT != 80*time.Millisecond || got.BandwidthMbps != 100 {
+		t.Errorf("Restored source %q, RTT %s, bandwidth %v Mbps", got.DiscoverySource, got.RTT, got.BandwidthMbps)
+	}
+	if got.Capabilities.GetHostname() != "build-1" || len(got.Capabilities.GetCpp().GetCompilers()) != 1 {
+		t.Errorf("Capabilities not restored: %v", got.Capabilities)
+	}
+	if got.TotalTasks != 3 || got.SuccessfulTasks != 1 || got.FailedTasks != 1 {
+		t.Errorf("Expected 3 total, 1 successful, 1 failed task, got %d, %d, %d", got.TotalTasks, got.SuccessfulTasks, got.FailedTasks)
+	}
+	if got.AvgCompileTime != 200*time.Millisecond {
+		t.Errorf("Expected average compile time 200ms, got %s", got.AvgCompileTime)
+	}
+	if got.ActiveTasks != 0 {
+		t.Errorf("Expected no active tasks after restart, got %d", got.ActiveTasks)
+	}
+
+	// Not scheduled until the worker is heard from again
+	if n := len(r.ListByCapability(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64)); n != 0 {
+		t.Errorf("Expected restored worker not to be schedulable, got %d workers", n)
+	}
+	r.cleanupStaleWorkers()
+	if got, _ := r.Get("cpp-worker"); got.State != WorkerStateUnknown {
+		t.Errorf("Expected cleanup to keep state unknown, got %s", got.State)
+	}
+	if err := r.Add(&WorkerInfo{ID: "cpp-worker", Address: "10.0.0.5:50052", Capabilities: got.Capabilities}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if n := len(r.ListByCapability(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64)); n != 1 {
+		t.Errorf("Expected worker to be schedulable after heartbeat, got %d workers", n)
+	}
+	if got, _ := r.Get("cpp-worker"); got.TotalTasks != 3 {
+		t.Errorf("Expected heartbeat to keep task history, got %d total tasks", got.TotalTasks)
+	}
+}
+
+func TestPersistentRegistry_Remove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workers.json")
+
+	r := openPersistent(t, path)
+	r.Add(&WorkerInfo{ID: "a", Address: "localhost:50051"})
+	r.Add(&WorkerInfo{ID: "b", Address: "localhost:50052"})
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	r.Remove("a")
+	r.Stop()
+
+	r = openPersistent(t, path)
+	defer r.Stop()
+	if _, ok := r.Get("a"); ok {
+		t.Error("Expected removed worker not to be restored")
+	}
+	if r.Count() != 1 {
+		t.Errorf("Expected 1 worker, got %d", r.Count())
+	}
+}
+
+func TestPersistentRegistry_DropsLongGoneWorkers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workers.json")
+	err := NewFileStore(path).Save([]*WorkerInfo{
+		{ID: "recent", LastHeartbeat: time.Now().Add(-time.Hour)},
+		{ID: "gone", LastHeartbeat: time.Now().Add(-restoreMaxAge - time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	r := openPersistent(t, path)
+	defer r.Stop()
+	if _, ok := r.Get("recent"); !ok {
+		t.Error("Expected recent worker to be restored")
+	}
+	if _, ok := r.Get("gone"); ok {
+		t.Error("Expected worker gone for longer than restoreMaxAge to be dropped")
+	}
+}
+
+func TestFileStore_Load(t *testing.T) {
+	dir := t.TempDir()
+
+	workers, err := NewFileStore(filepath.Join(dir, "missing.json")).Load()
+	if err != nil || len(workers) != 0 {
+		t.Errorf("Load() of missing file = %v, %v", workers, err)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPersistentRegistry(30*time.Second, NewFileStore(corrupt), time.Hour); err == nil {
+		t.Error("Expected error for corrupt registry file")
+	}
+
+	future := filepath.Join(dir, "future.json")
+	if err := os.WriteFile(future, []byte(`{"version": 99, "workers": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(future).Load(); err == nil {
+		t.Error("Expected error for unsupported version")
+	}
+}
+
+func TestPersistentRegistry_ReloadSharedStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workers.json")
+
+	// Two coordinators sharing the store; only one is active at a time
+	active := openPersistent(t, path)
+	defer active.Stop()
+	standby := openPersistent(t, path)
+	defer standby.Stop()
+
+	active.Add(&WorkerInfo{ID: "a", Address: "localhost:50051"})
+	if err := active.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	if err := standby.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	got, ok := standby.Get("a")
+	if !ok {
+		t.Fatal("Expected standby to pick up the saved worker")
+	}
+	if got.State != WorkerStateUnknown {
+		t.Errorf("Expected state unknown, got %s", got.State)
+	}
+
+	// Reload drops workers no longer saved
+	active.Remove("a")
+	active.Flush()
+	standby.Reload()
+	if standby.Count() != 0 {
+		t.Errorf("Expected 0 workers after reload, got %d", standby.Count())
+	}
+}
+
+func TestPersistentRegistry_ReloadOverwritesWorkers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workers.json")
+
+	active := openPersistent(t, path)
+	defer active.Stop()
+	standby := openPersistent(t, path)
+	defer standby.Stop()
+
+	// The standby still knows the worker from when it last led
+	standby.Add(&WorkerInfo{ID: "a", Address: "old-host:50051", TotalTasks: 3})
+	standby.UpdateState("a", WorkerStateIdle)
+
+	active.Add(&WorkerInfo{ID: "a", Address: "new-host:50051", TotalTasks: 40})
+	if err := active.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	if err := standby.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	got, ok := standby.Get("a")
+	if !ok {
+		t.Fatal("Expected the saved worker")
+	}
+	if got.Address != "new-host:50051" || got.TotalTasks != 40 {
+		t.Errorf("Expected the saved worker to replace the one in memory, got %s with %d tasks", got.Address, got.TotalTasks)
+	}
+	if got.State != WorkerStateUnknown {
+		t.Errorf("Expected state unknown, got %s", got.State)
+	}
+}
@@ -19,6 +19,9 @@ const (
 	WorkerStateIdle WorkerState = iota
 	WorkerStateBusy
 	WorkerStateUnhealthy
+	// WorkerStateUnknown marks a worker restored by a PersistentRegistry
+	// that has not been heard from since the coordinator started.
+	WorkerStateUnknown
 )
 
 func (s WorkerState) String() string {
@@ -29,21 +32,37 @@ func (s WorkerState) String() string {
 		return "busy"
 	case WorkerStateUnhealthy:
 		return "unhealthy"
+	case WorkerStateUnknown:
+		return "unknown"
 	}
 	return "unknown"
 }
 
+// Discovery sources of statically configured workers.
+const (
+	DiscoverySourceLAN = "LAN"
+	DiscoverySourceWAN = "WAN"
+)
+
 // WorkerInfo stores information about a registered worker.
 type WorkerInfo struct {
 	ID              string
 	Address         string
 	Capabilities    *pb.WorkerCapabilities
 	State           WorkerState
-	DiscoverySource string // "mdns", "wan", "manual"
+	DiscoverySource string // "mdns", "LAN", "WAN"; empty for self-registered workers
 	LastHeartbeat   time.Time
 	RegisteredAt    time.Time
 	MaxParallel     int32 // Max concurrent tasks this worker can handle
 
+	// Network, measured or configured for workers the coordinator probes
+	RTT           time.Duration // Zero if not measured
+	BandwidthMbps float64       // Zero if unknown
+
+	// Host load, as last reported by the worker; nil until it reports one.
+	// It is not persisted.
+	Load *pb.WorkerLoad
+
 	// Metrics
 	ActiveTasks     int32
 	TotalTasks      int64
@@ -52,9 +71,14 @@ type WorkerInfo struct {
 	AvgCompileTime  time.Duration
 }
 
+// IsWAN returns true if the worker is reached over a wide-area network.
+func (w *WorkerInfo) IsWAN() bool {
+	return w.DiscoverySource == DiscoverySourceWAN
+}
+
 // IsHealthy returns true if the worker is considered healthy.
 func (w *WorkerInfo) IsHealthy(ttl time.Duration) bool {
-	if w.State == WorkerStateUnhealthy {
+	if w.State == WorkerStateUnhealthy || w.State == WorkerStateUnknown {
 		return false
 	}
 	return time.Since(w.LastHeartbeat) <= ttl
@@ -83,6 +107,9 @@ type Registry interface {
 	// UpdateHeartbeat updates the last heartbeat time.
 	UpdateHeartbeat(id string) error
 
+	// UpdateLoad stores the host load a worker reported.
+	UpdateLoad(id string, load *pb.WorkerLoad) error
+
 	// IncrementTasks increments the active task count.
 	IncrementTasks(id string) error
 
@@ -123,11 +150,18 @@ func (r *InMemoryRegistry) Add(worker *WorkerInfo) error {
 	if existing, exists := r.workers[worker.ID]; exists {
 		// Update existing worker's capabilities and heartbeat
 		existing.Capabilities = worker.Capabilities
-		existing.Address = worker.Address
 		existing.MaxParallel = worker.MaxParallel
+		// The address and network of a statically configured worker come
+		// from its probes; a handshake from the same worker keeps them.
+		if worker.DiscoverySource != "" || existing.DiscoverySource == "" {
+			existing.Address = worker.Address
+			existing.DiscoverySource = worker.DiscoverySource
+			existing.RTT = worker.RTT
+			existing.BandwidthMbps = worker.BandwidthMbps
+		}
 		existing.LastHeartbeat = time.Now()
-		// Reset unhealthy state when worker heartbeats
-		if existing.State == WorkerStateUnhealthy {
+		// Reset unhealthy or unknown state when worker heartbeats
+		if existing.State == WorkerStateUnhealthy || existing.State == WorkerStateUnknown {
 			existing.State = WorkerStateIdle
 		}
 		return nil
@@ -208,6 +242,9 @@ func cloneWorkerInfo(worker *WorkerInfo) *WorkerInfo {
 	if worker.Capabilities != nil {
 		copy.Capabilities = proto.Clone(worker.Capabilities).(*pb.WorkerCapabilities)
 	}
+	if worker.Load != nil {
+		copy.Load = proto.Clone(worker.Load).(*pb.WorkerLoad)
+	}
 
 	return &copy
 }
@@ -277,6 +314,14 @@ func (r *InMemoryRegistry) matchesCapability(caps *pb.WorkerCapabilities, buildT
 		if caps.Flutter == nil {
 			return false
 		}
+	case pb.BuildType_BUILD_TYPE_UNITY:
+		if caps.Unity == nil {
+			return false
+		}
+	case pb.BuildType_BUILD_TYPE_COCOS:
+		if caps.Cocos == nil {
+			return false
+		}
 	case pb.BuildType_BUILD_TYPE_UNSPECIFIED:
 		// Any worker can handle unspecified
 		return true
@@ -310,12 +355,26 @@ func (r *InMemoryRegistry) UpdateHeartbeat(id string) error {
 	}
 
 	worker.LastHeartbeat = time.Now()
-	if worker.State == WorkerStateUnhealthy {
+	if worker.State == WorkerStateUnhealthy || worker.State == WorkerStateUnknown {
 		worker.State = WorkerStateIdle
 	}
 	return nil
 }
 
+// UpdateLoad stores the host load a worker reported.
+func (r *InMemoryRegistry) UpdateLoad(id string, load *pb.WorkerLoad) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	worker, ok := r.workers[id]
+	if !ok {
+		return fmt.Errorf("worker %s not found", id)
+	}
+
+	worker.Load = proto.Clone(load).(*pb.WorkerLoad)
+	return nil
+}
+
 // IncrementTasks increments the active task count.
 func (r *InMemoryRegistry) IncrementTasks(id string) error {
 	r.mu.Lock()
@@ -390,17 +449,34 @@ func (r *InMemoryRegistry) cleanupLoop() {
 }
 
 // cleanupStaleWorkers marks workers as unhealthy if they haven't sent heartbeat.
+// Restored workers stay unknown until they do.
 func (r *InMemoryRegistry) cleanupStaleWorkers() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	for _, w := range r.workers {
-		if time.Since(w.LastHeartbeat) > r.ttl {
+		if w.State != WorkerStateUnknown && time.Since(w.LastHeartbeat) > r.ttl {
 			w.State = WorkerStateUnhealthy
 		}
 	}
 }
 
+// restore replaces the workers with those saved by a previous
+// coordinator, in the unknown state and keeping their task statistics.
+// Tasks that were running died with that coordinator.
+func (r *InMemoryRegistry) restore(workers []*WorkerInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.workers = make(map[string]*WorkerInfo, len(workers))
+	for _, w := range workers {
+		w.State = WorkerStateUnknown
+		w.ActiveTasks = 0
+		r.workers[w.ID] = w
+	}
+	r.updateWorkerMetrics()
+}
+
 // Stop stops the cleanup goroutine.
 func (r *InMemoryRegistry) Stop() {
 	close(r.stopCh)
@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -8,293 +9,387 @@ import (
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
 )
 
-func newTestRegistry() *InMemoryRegistry {
-	return NewInMemoryRegistry(30 * time.Second)
+// testRegistry is a Registry the tests can stop.
+type testRegistry interface {
+	Registry
+	Stop()
 }
 
-func TestAdd(t *testing.T) {
-	r := newTestRegistry()
-	defer r.Stop()
-
-	worker := &WorkerInfo{
-		ID:      "worker-1",
-		Address: "localhost:50051",
-		Capabilities: &pb.WorkerCapabilities{
-			Hostname: "test-host",
-			CpuCores: 4,
-		},
-	}
-
-	err := r.Add(worker)
-	if err != nil {
-		t.Fatalf("Add failed: %v", err)
-	}
+// registryBackends are the Registry implementations the tests run against.
+var registryBackends = []struct {
+	name string
+	new  func(t *testing.T) testRegistry
+}{
+	{"memory", func(t *testing.T) testRegistry {
+		return NewInMemoryRegistry(30 * time.Second)
+	}},
+	{"persistent", func(t *testing.T) testRegistry {
+		store := NewFileStore(filepath.Join(t.TempDir(), "workers.json"))
+		r, err := NewPersistentRegistry(30*time.Second, store, DefaultFlushInterval)
+		if err != nil {
+			t.Fatalf("NewPersistentRegistry failed: %v", err)
+		}
+		return r
+	}},
+}
 
-	if r.Count() != 1 {
-		t.Errorf("Expected count 1, got %d", r.Count())
+// forEachBackend runs fn as a subtest against every Registry implementation.
+func forEachBackend(t *testing.T, fn func(t *testing.T, r testRegistry)) {
+	for _, b := range registryBackends {
+		t.Run(b.name, func(t *testing.T) {
+			r := b.new(t)
+			defer r.Stop()
+			fn(t, r)
+		})
 	}
+}
 
-	// Adding same worker again should succeed (update/heartbeat behavior)
-	err = r.Add(worker)
-	if err != nil {
-		t.Errorf("Re-adding worker should succeed (heartbeat): %v", err)
-	}
+func TestAdd(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, r testRegistry) {
+		worker := &WorkerInfo{
+			ID:      "worker-1",
+			Address: "localhost:50051",
+			Capabilities: &pb.WorkerCapabilities{
+				Hostname: "test-host",
+				CpuCores: 4,
+			},
+		}
+
+		err := r.Add(worker)
+		if err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+
+		if r.Count() != 1 {
+			t.Errorf("Expected count 1, got %d", r.Count())
+		}
+
+		// Adding same worker again should succeed (update/heartbeat behavior)
+		err = r.Add(worker)
+		if err != nil {
+			t.Errorf("Re-adding worker should succeed (heartbeat): %v", err)
+		}
+
+		// Count should still be 1 (update, not duplicate)
+		if r.Count() != 1 {
+			t.Errorf("Expected count 1 after re-add, got %d", r.Count())
+		}
+	})
+}
 
-	// Count should still be 1 (update, not duplicate)
-	if r.Count() != 1 {
-		t.Errorf("Expected count 1 after re-add, got %d", r.Count())
-	}
+func TestAdd_HandshakeKeepsProbedNetwork(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, r testRegistry) {
+		caps := &pb.WorkerCapabilities{Hostname: "farm-1", CpuCores: 32}
+		// A static worker probed by the coordinator...
+		if err := r.Add(&WorkerInfo{
+			ID:              "worker-farm-1",
+			Address:         "farm-1.example.com:50052",
+			Capabilities:    caps,
+			DiscoverySource: DiscoverySourceWAN,
+			RTT:             80 * time.Millisecond,
+			BandwidthMbps:   100,
+		}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		// ...that also registers itself with --coordinator.
+		if err := r.Add(&WorkerInfo{
+			ID:           "worker-farm-1",
+			Address:      "10.0.0.7:50052",
+			Capabilities: caps,
+			MaxParallel:  8,
+		}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+
+		w, _ := r.Get("worker-farm-1")
+		if !w.IsWAN() || w.RTT != 80*time.Millisecond || w.BandwidthMbps != 100 {
+			t.Errorf("handshake cleared the probed network: source %q, RTT %v, bandwidth %v", w.DiscoverySource, w.RTT, w.BandwidthMbps)
+		}
+		if w.Address != "farm-1.example.com:50052" {
+			t.Errorf("Address = %q, want the configured one", w.Address)
+		}
+		if w.MaxParallel != 8 {
+			t.Errorf("MaxParallel = %d, want 8", w.MaxParallel)
+		}
+	})
 }
 
 func TestRemove(t *testing.T) {
-	r := newTestRegistry()
-	defer r.Stop()
+	forEachBackend(t, func(t *testing.T, r testRegistry) {
+		worker := &WorkerInfo{
+			ID:      "worker-1",
+			Address: "localhost:50051",
+		}
 
-	worker := &WorkerInfo{
-		ID:      "worker-1",
-		Address: "localhost:50051",
-	}
+		r.Add(worker)
 
-	r.Add(worker)
+		err := r.Remove("worker-1")
+		if err != nil {
+			t.Fatalf("Remove failed: %v", err)
+		}
 
-	err := r.Remove("worker-1")
-	if err != nil {
-		t.Fatalf("Remove failed: %v", err)
-	}
+		if r.Count() != 0 {
+			t.Errorf("Expected count 0, got %d", r.Count())
+		}
 
-	if r.Count() != 0 {
-		t.Errorf("Expected count 0, got %d", r.Count())
-	}
-
-	// Try to remove non-existent
-	err = r.Remove("worker-1")
-	if err == nil {
-		t.Error("Expected error for non-existent worker")
-	}
+		// Try to remove non-existent
+		err = r.Remove("worker-1")
+		if err == nil {
+			t.Error("Expected error for non-existent worker")
+		}
+	})
 }
 
 func TestGet(t *testing.T) {
-	r := newTestRegistry()
-	defer r.Stop()
-
-	worker := &WorkerInfo{
-		ID:      "worker-1",
-		Address: "localhost:50051",
-	}
+	forEachBackend(t, func(t *testing.T, r testRegistry) {
+		worker := &WorkerInfo{
+			ID:      "worker-1",
+			Address: "localhost:50051",
+		}
 
-	r.Add(worker)
+		r.Add(worker)
 
-	got, ok := r.Get("worker-1")
-	if !ok {
-		t.Fatal("Expected to find worker")
-	}
+		got, ok := r.Get("worker-1")
+		if !ok {
+			t.Fatal("Expected to find worker")
+		}
 
-	if got.ID != "worker-1" {
-		t.Errorf("Expected ID 'worker-1', got '%s'", got.ID)
-	}
+		if got.ID != "worker-1" {
+			t.Errorf("Expected ID 'worker-1', got '%s'", got.ID)
+		}
 
-	// Test not found
-	_, ok = r.Get("nonexistent")
-	if ok {
-		t.Error("Expected not to find nonexistent worker")
-	}
+		// Test not found
+		_, ok = r.Get("nonexistent")
+		if ok {
+			t.Error("Expected not to find nonexistent worker")
+		}
+	})
 }
 
 func TestList(t *testing.T) {
-	r := newTestRegistry()
-	defer r.Stop()
+	forEachBackend(t, func(t *testing.T, r testRegistry) {
+		for i := 0; i < 3; i++ {
+			r.Add(&WorkerInfo{
+				ID:      string(rune('a' + i)),
+				Address: "localhost:50051",
+			})
+		}
+
+		list := r.List()
+		if len(list) != 3 {
+			t.Errorf("Expected 3 workers, got %d", len(list))
+		}
+	})
+}
 
-	for i := 0; i < 3; i++ {
+func TestListByCapability(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, r testRegistry) {
+		// Add C++ capable worker
 		r.Add(&WorkerInfo{
-			ID:      string(rune('a' + i)),
+			ID:      "cpp-worker",
 			Address: "localhost:50051",
+			Capabilities: &pb.WorkerCapabilities{
+				NativeArch: pb.Architecture_ARCH_X86_64,
+				Cpp: &pb.CppCapability{
+					Compilers: []string{"gcc", "clang"},
+				},
+			},
 		})
-	}
-
-	list := r.List()
-	if len(list) != 3 {
-		t.Errorf("Expected 3 workers, got %d", len(list))
-	}
-}
 
-func TestListByCapability(t *testing.T) {
-	r := newTestRegistry()
-	defer r.Stop()
-
-	// Add C++ capable worker
-	r.Add(&WorkerInfo{
-		ID:      "cpp-worker",
-		Address: "localhost:50051",
-		Capabilities: &pb.WorkerCapabilities{
-			NativeArch: pb.Architecture_ARCH_X86_64,
-			Cpp: &pb.CppCapability{
-				Compilers: []string{"gcc", "clang"},
+		// Add Go capable worker
+		r.Add(&WorkerInfo{
+			ID:      "go-worker",
+			Address: "localhost:50052",
+			Capabilities: &pb.WorkerCapabilities{
+				NativeArch: pb.Architecture_ARCH_X86_64,
+				Go: &pb.GoCapability{
+					Version: "1.22.0",
+				},
 			},
-		},
-	})
+		})
 
-	// Add Go capable worker
-	r.Add(&WorkerInfo{
-		ID:      "go-worker",
-		Address: "localhost:50052",
-		Capabilities: &pb.WorkerCapabilities{
-			NativeArch: pb.Architecture_ARCH_X86_64,
-			Go: &pb.GoCapability{
-				Version: "1.22.0",
-			},
-		},
+		// Query for C++ workers
+		cppWorkers := r.ListByCapability(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64)
+		if len(cppWorkers) != 1 {
+			t.Errorf("Expected 1 C++ worker, got %d", len(cppWorkers))
+		}
+
+		if len(cppWorkers) > 0 && cppWorkers[0].ID != "cpp-worker" {
+			t.Errorf("Expected cpp-worker, got %s", cppWorkers[0].ID)
+		}
+
+		// Query for Go workers
+		goWorkers := r.ListByCapability(pb.BuildType_BUILD_TYPE_GO, pb.Architecture_ARCH_UNSPECIFIED)
+		if len(goWorkers) != 1 {
+			t.Errorf("Expected 1 Go worker, got %d", len(goWorkers))
+		}
+
+		// Neither worker has Unity installed
+		if unityWorkers := r.ListByCapability(pb.BuildType_BUILD_TYPE_UNITY, pb.Architecture_ARCH_UNSPECIFIED); len(unityWorkers) != 0 {
+			t.Errorf("Expected no Unity workers, got %d", len(unityWorkers))
+		}
 	})
-
-	// Query for C++ workers
-	cppWorkers := r.ListByCapability(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64)
-	if len(cppWorkers) != 1 {
-		t.Errorf("Expected 1 C++ worker, got %d", len(cppWorkers))
-	}
-
-	if len(cppWorkers) > 0 && cppWorkers[0].ID != "cpp-worker" {
-		t.Errorf("Expected cpp-worker, got %s", cppWorkers[0].ID)
-	}
-
-	// Query for Go workers
-	goWorkers := r.ListByCapability(pb.BuildType_BUILD_TYPE_GO, pb.Architecture_ARCH_UNSPECIFIED)
-	if len(goWorkers) != 1 {
-		t.Errorf("Expected 1 Go worker, got %d", len(goWorkers))
-	}
 }
 
 func TestUpdateState(t *testing.T) {
-	r := newTestRegistry()
-	defer r.Stop()
+	forEachBackend(t, func(t *testing.T, r testRegistry) {
+		r.Add(&WorkerInfo{
+			ID:      "worker-1",
+			Address: "localhost:50051",
+		})
 
-	r.Add(&WorkerInfo{
-		ID:      "worker-1",
-		Address: "localhost:50051",
+		err := r.UpdateState("worker-1", WorkerStateBusy)
+		if err != nil {
+			t.Fatalf("UpdateState failed: %v", err)
+		}
+
+		got, _ := r.Get("worker-1")
+		if got.State != WorkerStateBusy {
+			t.Errorf("Expected state Busy, got %s", got.State)
+		}
+
+		// Test non-existent
+		err = r.UpdateState("nonexistent", WorkerStateBusy)
+		if err == nil {
+			t.Error("Expected error for non-existent worker")
+		}
 	})
-
-	err := r.UpdateState("worker-1", WorkerStateBusy)
-	if err != nil {
-		t.Fatalf("UpdateState failed: %v", err)
-	}
-
-	got, _ := r.Get("worker-1")
-	if got.State != WorkerStateBusy {
-		t.Errorf("Expected state Busy, got %s", got.State)
-	}
-
-	// Test non-existent
-	err = r.UpdateState("nonexistent", WorkerStateBusy)
-	if err == nil {
-		t.Error("Expected error for non-existent worker")
-	}
 }
 
 func TestUpdateHeartbeat(t *testing.T) {
-	r := newTestRegistry()
-	defer r.Stop()
-
-	r.Add(&WorkerInfo{
-		ID:      "worker-1",
-		Address: "localhost:50051",
-	})
+	forEachBackend(t, func(t *testing.T, r testRegistry) {
+		r.Add(&WorkerInfo{
+			ID:      "worker-1",
+			Address: "localhost:50051",
+		})
 
-	// Mark as unhealthy
-	r.UpdateState("worker-1", WorkerStateUnhealthy)
+		// Mark as unhealthy
+		r.UpdateState("worker-1", WorkerStateUnhealthy)
 
-	// Heartbeat should restore to idle
-	err := r.UpdateHeartbeat("worker-1")
-	if err != nil {
-		t.Fatalf("UpdateHeartbeat failed: %v", err)
-	}
+		// Heartbeat should restore to idle
+		err := r.UpdateHeartbeat("worker-1")
+		if err != nil {
+			t.Fatalf("UpdateHeartbeat failed: %v", err)
+		}
 
-	got, _ := r.Get("worker-1")
-	if got.State != WorkerStateIdle {
-		t.Errorf("Expected state Idle after heartbeat, got %s", got.State)
-	}
+		got, _ := r.Get("worker-1")
+		if got.State != WorkerStateIdle {
+			t.Errorf("Expected state Idle after heartbeat, got %s", got.State)
+		}
+	})
 }
 
-func TestTaskTracking(t *testing.T) {
-	r := newTestRegistry()
-	defer r.Stop()
-
-	r.Add(&WorkerInfo{
-		ID:      "worker-1",
-		Address: "localhost:50051",
-	})
+func TestUpdateLoad(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, r testRegistry) {
+		r.Add(&WorkerInfo{
+			ID:      "worker-1",
+			Address: "localhost:50051",
+		})
 
-	// Start task
-	err := r.IncrementTasks("worker-1")
-	if err != nil {
-		t.Fatalf("IncrementTasks failed: %v", err)
-	}
+		load := &pb.WorkerLoad{CpuUsagePercent: 85, MemoryUsagePercent: 40, RunningContainers: 2}
+		if err := r.UpdateLoad("worker-1", load); err != nil {
+			t.Fatalf("UpdateLoad failed: %v", err)
+		}
+		load.CpuUsagePercent = 0
 
-	got, _ := r.Get("worker-1")
-	if got.ActiveTasks != 1 {
-		t.Errorf("Expected 1 active task, got %d", got.ActiveTasks)
-	}
-	if got.State != WorkerStateBusy {
-		t.Errorf("Expected state Busy, got %s", got.State)
-	}
+		// A later heartbeat keeps the load
+		r.Add(&WorkerInfo{
+			ID:      "worker-1",
+			Address: "localhost:50051",
+		})
 
-	// Complete task
-	err = r.DecrementTasks("worker-1", true, 100*time.Millisecond)
-	if err != nil {
-		t.Fatalf("DecrementTasks failed: %v", err)
-	}
+		got, _ := r.Get("worker-1")
+		if got.Load == nil || got.Load.CpuUsagePercent != 85 || got.Load.RunningContainers != 2 {
+			t.Errorf("Expected the reported load to be stored, got %v", got.Load)
+		}
 
-	got, _ = r.Get("worker-1")
-	if got.ActiveTasks != 0 {
-		t.Errorf("Expected 0 active tasks, got %d", got.ActiveTasks)
-	}
-	if got.SuccessfulTasks != 1 {
-		t.Errorf("Expected 1 successful task, got %d", got.SuccessfulTasks)
-	}
-	if got.State != WorkerStateIdle {
-		t.Errorf("Expected state Idle, got %s", got.State)
-	}
+		if err := r.UpdateLoad("missing", load); err == nil {
+			t.Error("Expected an error for an unknown worker")
+		}
+	})
 }
 
-func TestConcurrentAccess(t *testing.T) {
-	r := newTestRegistry()
-	defer r.Stop()
-
-	// Add some workers
-	for i := 0; i < 10; i++ {
+func TestTaskTracking(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, r testRegistry) {
 		r.Add(&WorkerInfo{
-			ID:      string(rune('a' + i)),
+			ID:      "worker-1",
 			Address: "localhost:50051",
 		})
-	}
-
-	var wg sync.WaitGroup
-	concurrency := 100
-
-	// Concurrent reads
-	wg.Add(concurrency)
-	for i := 0; i < concurrency; i++ {
-		go func() {
-			defer wg.Done()
-			r.List()
-			r.Get("a")
-			r.Count()
-		}()
-	}
 
-	// Concurrent writes
-	wg.Add(concurrency)
-	for i := 0; i < concurrency; i++ {
-		go func(n int) {
-			defer wg.Done()
-			r.UpdateHeartbeat(string(rune('a' + (n % 10))))
-			r.UpdateState(string(rune('a'+(n%10))), WorkerStateIdle)
-		}(i)
-	}
-
-	wg.Wait()
+		// Start task
+		err := r.IncrementTasks("worker-1")
+		if err != nil {
+			t.Fatalf("IncrementTasks failed: %v", err)
+		}
+
+		got, _ := r.Get("worker-1")
+		if got.ActiveTasks != 1 {
+			t.Errorf("Expected 1 active task, got %d", got.ActiveTasks)
+		}
+		if got.State != WorkerStateBusy {
+			t.Errorf("Expected state Busy, got %s", got.State)
+		}
+
+		// Complete task
+		err = r.DecrementTasks("worker-1", true, 100*time.Millisecond)
+		if err != nil {
+			t.Fatalf("DecrementTasks failed: %v", err)
+		}
+
+		got, _ = r.Get("worker-1")
+		if got.ActiveTasks != 0 {
+			t.Errorf("Expected 0 active tasks, got %d", got.ActiveTasks)
+		}
+		if got.SuccessfulTasks != 1 {
+			t.Errorf("Expected 1 successful task, got %d", got.SuccessfulTasks)
+		}
+		if got.State != WorkerStateIdle {
+			t.Errorf("Expected state Idle, got %s", got.State)
+		}
+	})
+}
 
-	// Should not panic or race
-	if r.Count() != 10 {
-		t.Errorf("Expected 10 workers, got %d", r.Count())
-	}
+func TestConcurrentAccess(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, r testRegistry) {
+		// Add some workers
+		for i := 0; i < 10; i++ {
+			r.Add(&WorkerInfo{
+				ID:      string(rune('a' + i)),
+				Address: "localhost:50051",
+			})
+		}
+
+		var wg sync.WaitGroup
+		concurrency := 100
+
+		// Concurrent reads
+		wg.Add(concurrency)
+		for i := 0; i < concurrency; i++ {
+			go func() {
+				defer wg.Done()
+				r.List()
+				r.Get("a")
+				r.Count()
+			}()
+		}
+
+		// Concurrent writes
+		wg.Add(concurrency)
+		for i := 0; i < concurrency; i++ {
+			go func(n int) {
+				defer wg.Done()
+				r.UpdateHeartbeat(string(rune('a' + (n % 10))))
+				r.UpdateState(string(rune('a'+(n%10))), WorkerStateIdle)
+			}(i)
+		}
+
+		wg.Wait()
+
+		// Should not panic or race
+		if r.Count() != 10 {
+			t.Errorf("Expected 10 workers, got %d", r.Count())
+		}
+	})
 }
 
 func TestWorkerInfoIsHealthy(t *testing.T) {
@@ -321,6 +416,14 @@ func TestWorkerInfoIsHealthy(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "restored, not seen yet",
+			worker: &WorkerInfo{
+				State:         WorkerStateUnknown,
+				LastHeartbeat: time.Now(),
+			},
+			expected: false,
+		},
 		{
 			name: "stale heartbeat",
 			worker: &WorkerInfo{
@@ -0,0 +1,441 @@
+// Package replay evaluates schedulers offline by replaying the tasks of a
+// coordinator task log against a simulated worker pool, so a scheduler
+// change can be compared with the current one before it is rolled out.
+package replay
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/resilience"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/scheduler"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/server"
+)
+
+// Task is a recorded task to replay.
+type Task struct {
+	ID string
+	// Arrival is when the task reached the coordinator, relative to the
+	// first task of the trace.
+	Arrival         time.Duration
+	TargetArch      pb.Architecture
+	SourceSizeBytes int
+	// WorkMs is the compile time in milliseconds on a worker of speed 1.
+	WorkMs  float64
+	Success bool
+	// UpwardRank is the task's rank in its build graph, 0 if unknown.
+	UpwardRank float64
+}
+
+// Worker is a worker of the simulated pool, reconstructed from the tasks
+// it ran.
+type Worker struct {
+	ID         string
+	NativeArch pb.Architecture
+	// CrossCompile is set when the worker ran tasks for another
+	// architecture.
+	CrossCompile    bool
+	CPUCores        int32
+	MemBytes        int64
+	MaxParallel     int32
+	DiscoverySource string
+	// Speed multiplies a task's work to give its compile time on this
+	// worker; 1 is the pool's average and lower is faster.
+	Speed float64
+	// OverheadMs is the mean time a task spent in the worker call beyond
+	// compiling, such as transfers.
+	OverheadMs float64
+}
+
+// Trace is the workload and worker pool to replay.
+type Trace struct {
+	Tasks   []Task
+	Workers []Worker
+}
+
+// NewTrace builds a trace from task log records. Only C/C++ compiles that
+// reached a worker are replayed.
+//
+// A task's compile time on another worker is estimated by scaling with
+// the workers' speeds. A worker's speed is its compile time per source
+// byte relative to the whole log's, which keeps it from looking slow
+// just because a scheduler sent it the larger translation units.
+func NewTrace(records []server.TaskLogRecord) (*Trace, error) {
+	type workerStats struct {
+		worker      Worker
+		compileMs   float64
+		sourceBytes float64
+		compiled    int
+		overheadMs  float64
+		calls       int
+	}
+
+	var (
+		recs       []server.TaskLogRecord
+		arrivals   []time.Time
+		stats      = make(map[string]*workerStats)
+		totalMs    float64
+		totalBytes float64
+		compiled   int
+	)
+	for _, r := range records {
+		if r.Event != "task_completed" || r.BuildType != "cpp" || r.FromCache || r.WorkerID == "" {
+			continue
+		}
+		recs = append(recs, r)
+		arrivals = append(arrivals, r.TS.Add(-time.Duration(r.TotalDurationMs)*time.Millisecond))
+
+		ws, ok := stats[r.WorkerID]
+		if !ok {
+			ws = &workerStats{worker: Worker{ID: r.WorkerID}}
+			stats[r.WorkerID] = ws
+		}
+		w := &ws.worker
+		w.NativeArch = parseArch(r.WorkerNativeArch)
+		w.CPUCores = r.WorkerCPUCores
+		w.MemBytes = r.WorkerMemBytes
+		w.MaxParallel = r.WorkerMaxParallel
+		w.DiscoverySource = r.WorkerDiscoverySource
+		if arch := parseArch(r.TargetArch); arch != pb.Architecture_ARCH_UNSPECIFIED && arch != w.NativeArch {
+			w.CrossCompile = true
+		}
+		if r.WorkerRPCLatencyMs > 0 {
+			ws.overheadMs += math.Max(0, float64(r.WorkerRPCLatencyMs-r.CompileTimeMs))
+			ws.calls++
+		}
+		if r.Success && r.CompileTimeMs > 0 {
+			ws.compileMs += float64(r.CompileTimeMs)
+			ws.sourceBytes += float64(r.SourceSizeBytes)
+			ws.compiled++
+			totalMs += float64(r.CompileTimeMs)
+			totalBytes += float64(r.SourceSizeBytes)
+			compiled++
+		}
+	}
+	if len(recs) == 0 {
+		return nil, errors.New("no C/C++ compiles dispatched to a worker in the task log")
+	}
+
+	trace := &Trace{}
+	ids := make([]string, 0, len(stats))
+	for id := range stats {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		ws := stats[id]
+		w := ws.worker
+		w.Speed = 1
+		switch {
+		case ws.compiled == 0:
+		case totalBytes > 0 && ws.sourceBytes > 0:
+			w.Speed = (ws.compileMs / ws.sourceBytes) / (totalMs / totalBytes)
+		default:
+			w.Speed = (ws.compileMs / float64(ws.compiled)) / (totalMs / float64(compiled))
+		}
+		if ws.calls > 0 {
+			w.OverheadMs = ws.overheadMs / float64(ws.calls)
+		}
+		stats[id].worker = w
+		trace.Workers = append(trace.Workers, w)
+	}
+
+	start := arrivals[0]
+	for _, a := range arrivals {
+		if a.Before(start) {
+			start = a
+		}
+	}
+	for i, r := range recs {
+		trace.Tasks = append(trace.Tasks, Task{
+			ID:              r.TaskID,
+			Arrival:         arrivals[i].Sub(start),
+			TargetArch:      parseArch(r.TargetArch),
+			SourceSizeBytes: r.SourceSizeBytes,
+			WorkMs:          float64(r.CompileTimeMs) / stats[r.WorkerID].worker.Speed,
+			Success:         r.Success,
+			UpwardRank:      r.UpwardRank,
+		})
+	}
+	sort.SliceStable(trace.Tasks, func(i, j int) bool { return trace.Tasks[i].Arrival < trace.Tasks[j].Arrival })
+	return trace, nil
+}
+
+// parseArch parses an architecture as logged, such as "ARCH_X86_64".
+func parseArch(name string) pb.Architecture {
+	return pb.Architecture(pb.Architecture_value[name])
+}
+
+// Options configure the replayed schedulers as the matching hg-coord
+// serve flags do.
+type Options struct {
+	// Epsilon is the exploration rate of epsilon-greedy.
+	Epsilon float64
+	// Alpha is the LinUCB exploration coefficient.
+	Alpha float64
+	// RequestTimeout normalises the learners' rewards. Zero means two
+	// minutes, as on the coordinator.
+	RequestTimeout time.Duration
+}
+
+// Result summarises the replay of a trace with one scheduler.
+type Result struct {
+	Scheduler string
+	Tasks     int
+	// Makespan is the time from the first arrival to the last completion.
+	Makespan time.Duration
+	// MeanLatency and P95Latency cover the time from arrival to
+	// completion, including time queued for a free worker.
+	MeanLatency time.Duration
+	P95Latency  time.Duration
+	// Regret is the total time the chosen workers took beyond the fastest
+	// worker with a free slot at each dispatch.
+	Regret time.Duration
+}
+
+// Run replays trace with the scheduler of the given type, as hg-coord
+// serve --scheduler names it.
+//
+// As on the coordinator, a task waits while every worker that could run
+// it is at capacity, and learning schedulers are fed each outcome. Waiting
+// tasks are dispatched in decreasing upward rank, then arrival order; fair
+// share between clients and priorities are not simulated. Schedulers that
+// pick at random give slightly different results on every run.
+func Run(trace *Trace, schedulerType string, opts Options) (*Result, error) {
+	reg := registry.NewInMemoryRegistry(24 * time.Hour)
+	defer reg.Stop()
+
+	workers := make(map[string]*Worker, len(trace.Workers))
+	for i := range trace.Workers {
+		w := &trace.Workers[i]
+		workers[w.ID] = w
+		if err := reg.Add(&registry.WorkerInfo{
+			ID:      w.ID,
+			Address: "replay",
+			Capabilities: &pb.WorkerCapabilities{
+				WorkerId:    w.ID,
+				NativeArch:  w.NativeArch,
+				CpuCores:    w.CPUCores,
+				MemoryBytes: w.MemBytes,
+				Cpp:         &pb.CppCapability{Compilers: []string{"replay"}, CrossCompile: w.CrossCompile},
+			},
+			MaxParallel:     w.MaxParallel,
+			DiscoverySource: w.DiscoverySource,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	sim := &simulation{
+		trace:    trace,
+		workers:  workers,
+		registry: reg,
+		sched: server.NewScheduler(server.Config{
+			SchedulerType: schedulerType,
+			EpsilonValue:  opts.Epsilon,
+			AlphaValue:    opts.Alpha,
+		}, reg, resilience.NewCircuitManager(resilience.DefaultCircuitConfig())),
+		timeout: opts.RequestTimeout,
+	}
+	if err := sim.run(); err != nil {
+		return nil, err
+	}
+	return sim.result(schedulerType), nil
+}
+
+// simulation replays a trace in simulated time, in milliseconds.
+type simulation struct {
+	trace    *Trace
+	workers  map[string]*Worker
+	registry registry.Registry
+	sched    scheduler.Scheduler
+	timeout  time.Duration
+
+	now       float64
+	running   completions
+	latencies []float64
+	end       float64
+	regret    float64
+}
+
+// completion is a dispatched task finishing at a simulated time.
+type completion struct {
+	at        float64
+	task      int
+	workerID  string
+	compileMs float64
+}
+
+// completions is a min-heap of completion times.
+type completions []completion
+
+func (c completions) Len() int           { return len(c) }
+func (c completions) Less(i, j int) bool { return c[i].at < c[j].at }
+func (c completions) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c *completions) Push(x any)        { *c = append(*c, x.(completion)) }
+func (c *completions) Pop() any {
+	old := *c
+	last := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return last
+}
+
+func (s *simulation) run() error {
+	tasks := s.trace.Tasks
+	var waiting []int
+	next := 0
+	for next < len(tasks) || s.running.Len() > 0 {
+		if s.running.Len() > 0 && (next == len(tasks) || s.running[0].at <= ms(tasks[next].Arrival)) {
+			c := heap.Pop(&s.running).(completion)
+			s.now = c.at
+			s.complete(c)
+
+			still := waiting[:0]
+			for _, i := range waiting {
+				ok, err := s.dispatch(i)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					still = append(still, i)
+				}
+			}
+			waiting = still
+			continue
+		}
+
+		s.now = ms(tasks[next].Arrival)
+		ok, err := s.dispatch(next)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			waiting = s.enqueue(waiting, next)
+		}
+		next++
+	}
+	if len(waiting) > 0 {
+		return fmt.Errorf("%d tasks were never dispatched", len(waiting))
+	}
+	return nil
+}
+
+// enqueue inserts task i into the waiting list after every task ranked
+// at least as high.
+func (s *simulation) enqueue(waiting []int, i int) []int {
+	rank := s.trace.Tasks[i].UpwardRank
+	at := len(waiting)
+	for at > 0 && s.trace.Tasks[waiting[at-1]].UpwardRank < rank {
+		at--
+	}
+	waiting = append(waiting, 0)
+	copy(waiting[at+1:], waiting[at:])
+	waiting[at] = i
+	return waiting
+}
+
+// dispatch sends task i to the worker the scheduler selects, or reports
+// false when every worker that could run it is busy.
+func (s *simulation) dispatch(i int) (bool, error) {
+	t := &s.trace.Tasks[i]
+	matching := s.registry.ListByCapability(pb.BuildType_BUILD_TYPE_CPP, t.TargetArch)
+	if len(matching) == 0 {
+		return false, fmt.Errorf("task %s: no worker can build for %s", t.ID, t.TargetArch)
+	}
+	best := math.Inf(1)
+	for _, w := range matching {
+		if hasFreeSlot(w) {
+			best = math.Min(best, s.serviceMs(t, s.workers[w.ID]))
+		}
+	}
+	if math.IsInf(best, 1) {
+		return false, nil
+	}
+
+	ctx := scheduler.TaskContext{SourceSizeBytes: t.SourceSizeBytes, TaskID: t.ID, UpwardRank: t.UpwardRank}
+	chosen, _, err := scheduler.SelectWith(s.sched, pb.BuildType_BUILD_TYPE_CPP, t.TargetArch, "", ctx)
+	if errors.Is(err, scheduler.ErrNoMatchingWorkers) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("task %s: %w", t.ID, err)
+	}
+
+	w := s.workers[chosen.ID]
+	service := s.serviceMs(t, w)
+	s.regret += math.Max(0, service-best)
+	if err := s.registry.IncrementTasks(w.ID); err != nil {
+		return false, err
+	}
+	heap.Push(&s.running, completion{
+		at:        s.now + service,
+		task:      i,
+		workerID:  w.ID,
+		compileMs: t.WorkMs * w.Speed,
+	})
+	return true, nil
+}
+
+// complete books a finished task off its worker and feeds the outcome to
+// a learning scheduler with the reward the coordinator would give.
+func (s *simulation) complete(c completion) {
+	t := &s.trace.Tasks[c.task]
+	compileTime := time.Duration(c.compileMs * float64(time.Millisecond))
+	_ = s.registry.DecrementTasks(c.workerID, t.Success, compileTime)
+	if learner, ok := s.sched.(scheduler.LearningScheduler); ok {
+		reward := scheduler.LatencyReward(c.compileMs, t.Success, s.timeout)
+		learner.RecordOutcome(c.workerID, reward, t.Success, scheduler.TaskContext{SourceSizeBytes: t.SourceSizeBytes, TaskID: t.ID, UpwardRank: t.UpwardRank})
+	}
+	s.latencies = append(s.latencies, c.at-ms(t.Arrival))
+	s.end = math.Max(s.end, c.at)
+}
+
+// serviceMs is how long task t takes on worker w, from dispatch to
+// completion.
+func (s *simulation) serviceMs(t *Task, w *Worker) float64 {
+	return t.WorkMs*w.Speed + w.OverheadMs
+}
+
+func (s *simulation) result(schedulerType string) *Result {
+	r := &Result{
+		Scheduler: schedulerType,
+		Tasks:     len(s.latencies),
+		Makespan:  duration(s.end),
+		Regret:    duration(s.regret),
+	}
+	if len(s.latencies) == 0 {
+		return r
+	}
+	sorted := append([]float64(nil), s.latencies...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, l := range sorted {
+		sum += l
+	}
+	r.MeanLatency = duration(sum / float64(len(sorted)))
+	r.P95Latency = duration(sorted[int(math.Ceil(0.95*float64(len(sorted))))-1])
+	return r
+}
+
+// hasFreeSlot reports whether worker can accept another task.
+func hasFreeSlot(w *registry.WorkerInfo) bool {
+	maxParallel := w.MaxParallel
+	if maxParallel <= 0 {
+		maxParallel = 4
+	}
+	return w.ActiveTasks < maxParallel
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func duration(v float64) time.Duration {
+	return time.Duration(v * float64(time.Millisecond))
+}
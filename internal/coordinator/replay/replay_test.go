@@ -0,0 +1,194 @@
+package replay
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/server"
+)
+
+func record(taskID, workerID string, completed time.Time, compileMs, totalMs int64) server.TaskLogRecord {
+	return server.TaskLogRecord{
+		TS:                 completed,
+		Event:              "task_completed",
+		TaskID:             taskID,
+		BuildType:          "cpp",
+		WorkerID:           workerID,
+		WorkerNativeArch:   "ARCH_X86_64",
+		WorkerCPUCores:     8,
+		WorkerMaxParallel:  2,
+		TargetArch:         "ARCH_X86_64",
+		SourceSizeBytes:    1000,
+		CompileTimeMs:      compileMs,
+		WorkerRPCLatencyMs: compileMs + 20,
+		TotalDurationMs:    totalMs,
+		Success:            true,
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestNewTrace(t *testing.T) {
+	t0 := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	cached := record("cached", "fast", t0, 0, 1)
+	cached.FromCache = true
+	arm := record("t4", "slow", t0.Add(4*time.Second), 300, 300)
+	arm.TargetArch = "ARCH_ARM64"
+
+	trace, err := NewTrace([]server.TaskLogRecord{
+		record("t2", "fast", t0.Add(2*time.Second), 100, 1000),
+		record("t1", "fast", t0.Add(time.Second), 100, 1000),
+		record("t3", "slow", t0.Add(3*time.Second), 300, 300),
+		arm,
+		cached,
+	})
+	if err != nil {
+		t.Fatalf("NewTrace: %v", err)
+	}
+
+	if len(trace.Workers) != 2 {
+		t.Fatalf("workers = %d, want 2", len(trace.Workers))
+	}
+	fast, slow := trace.Workers[0], trace.Workers[1]
+	if fast.ID != "fast" || !approx(fast.Speed, 0.5) || !approx(fast.OverheadMs, 20) {
+		t.Errorf("fast worker = %+v, want speed 0.5 and overhead 20ms", fast)
+	}
+	if slow.ID != "slow" || !approx(slow.Speed, 1.5) || !slow.CrossCompile {
+		t.Errorf("slow worker = %+v, want speed 1.5 and cross-compiling", slow)
+	}
+	if fast.CrossCompile {
+		t.Error("fast worker only built for its own architecture")
+	}
+
+	wantIDs := []string{"t1", "t2", "t3", "t4"}
+	if len(trace.Tasks) != len(wantIDs) {
+		t.Fatalf("tasks = %d, want %d", len(trace.Tasks), len(wantIDs))
+	}
+	for i, task := range trace.Tasks {
+		if task.ID != wantIDs[i] {
+			t.Errorf("task %d = %s, want %s", i, task.ID, wantIDs[i])
+		}
+		if !approx(task.WorkMs, 200) {
+			t.Errorf("task %s work = %v, want 200", task.ID, task.WorkMs)
+		}
+	}
+	if trace.Tasks[0].Arrival != 0 || trace.Tasks[2].Arrival != 2700*time.Millisecond {
+		t.Errorf("arrivals = %v, %v, want 0s and 2.7s", trace.Tasks[0].Arrival, trace.Tasks[2].Arrival)
+	}
+	if trace.Tasks[3].TargetArch != pb.Architecture_ARCH_ARM64 {
+		t.Errorf("target arch = %v, want ARM64", trace.Tasks[3].TargetArch)
+	}
+}
+
+func TestNewTrace_Empty(t *testing.T) {
+	if _, err := NewTrace(nil); err == nil {
+		t.Error("expected an error for a log without compiles")
+	}
+}
+
+func TestRun_QueuesWhenWorkersBusy(t *testing.T) {
+	trace := &Trace{
+		Workers: []Worker{{ID: "only", NativeArch: pb.Architecture_ARCH_X86_64, MaxParallel: 1, Speed: 1}},
+		Tasks: []Task{
+			{ID: "a", TargetArch: pb.Architecture_ARCH_X86_64, WorkMs: 100, Success: true},
+			{ID: "b", TargetArch: pb.Architecture_ARCH_X86_64, WorkMs: 100, Success: true},
+			{ID: "c", TargetArch: pb.Architecture_ARCH_X86_64, WorkMs: 100, Success: true},
+		},
+	}
+
+	res, err := Run(trace, "leastloaded", Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.Tasks != 3 {
+		t.Errorf("tasks = %d, want 3", res.Tasks)
+	}
+	if res.Makespan != 300*time.Millisecond {
+		t.Errorf("makespan = %v, want 300ms", res.Makespan)
+	}
+	if res.MeanLatency != 200*time.Millisecond || res.P95Latency != 300*time.Millisecond {
+		t.Errorf("latency mean %v p95 %v, want 200ms and 300ms", res.MeanLatency, res.P95Latency)
+	}
+	if res.Regret != 0 {
+		t.Errorf("regret = %v, want 0 with a single worker", res.Regret)
+	}
+}
+
+func TestRun_WaitingTasksByRank(t *testing.T) {
+	trace := &Trace{
+		Workers: []Worker{{ID: "only", NativeArch: pb.Architecture_ARCH_X86_64, MaxParallel: 1, Speed: 1}},
+		Tasks: []Task{
+			{ID: "a", TargetArch: pb.Architecture_ARCH_X86_64, WorkMs: 100, Success: true},
+			{ID: "b", Arrival: time.Millisecond, TargetArch: pb.Architecture_ARCH_X86_64, WorkMs: 100, Success: true, UpwardRank: 0.2},
+			{ID: "c", Arrival: 2 * time.Millisecond, TargetArch: pb.Architecture_ARCH_X86_64, WorkMs: 10, Success: true, UpwardRank: 1},
+		},
+	}
+
+	res, err := Run(trace, "leastloaded", Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// c overtakes b: latencies 100ms, 108ms and 209ms.
+	if res.MeanLatency != 139*time.Millisecond {
+		t.Errorf("mean latency = %v, want 139ms", res.MeanLatency)
+	}
+}
+
+func TestRun_Regret(t *testing.T) {
+	// HEFT's cold prior favours the worker with more cores, which is the
+	// slower one here: 200ms instead of 50ms.
+	trace := &Trace{
+		Workers: []Worker{
+			{ID: "fast", NativeArch: pb.Architecture_ARCH_X86_64, CPUCores: 4, MaxParallel: 1, Speed: 0.5},
+			{ID: "slow", NativeArch: pb.Architecture_ARCH_X86_64, CPUCores: 16, MaxParallel: 1, Speed: 2},
+		},
+		Tasks: []Task{{ID: "a", TargetArch: pb.Architecture_ARCH_X86_64, WorkMs: 100, Success: true}},
+	}
+
+	res, err := Run(trace, "heft", Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.Regret != 150*time.Millisecond {
+		t.Errorf("regret = %v, want 150ms", res.Regret)
+	}
+	if res.Makespan != 200*time.Millisecond {
+		t.Errorf("makespan = %v, want 200ms", res.Makespan)
+	}
+}
+
+func TestRun_LearnersReplayEveryTask(t *testing.T) {
+	trace := &Trace{
+		Workers: []Worker{
+			{ID: "fast", NativeArch: pb.Architecture_ARCH_X86_64, MaxParallel: 2, Speed: 0.5},
+			{ID: "slow", NativeArch: pb.Architecture_ARCH_X86_64, MaxParallel: 2, Speed: 2},
+		},
+	}
+	for i := 0; i < 50; i++ {
+		trace.Tasks = append(trace.Tasks, Task{
+			ID:              "task-" + string(rune('A'+i%26)) + string(rune('a'+i/26)),
+			Arrival:         time.Duration(i) * 20 * time.Millisecond,
+			TargetArch:      pb.Architecture_ARCH_X86_64,
+			SourceSizeBytes: 1000 * (i + 1),
+			WorkMs:          100,
+			Success:         i%10 != 0,
+		})
+	}
+
+	for _, name := range []string{"p2c", "epsilon-greedy", "linucb", "heft"} {
+		res, err := Run(trace, name, Options{Epsilon: 0.1, Alpha: 1})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if res.Tasks != len(trace.Tasks) || res.Scheduler != name {
+			t.Errorf("%s: result %+v, want all %d tasks", name, res, len(trace.Tasks))
+		}
+		if res.P95Latency < res.MeanLatency/2 || res.Makespan < 980*time.Millisecond {
+			t.Errorf("%s: implausible result %+v", name, res)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+)
+
+// DefaultCheckpointInterval is how often a Checkpointer saves the learned
+// state.
+const DefaultCheckpointInterval = time.Minute
+
+// checkpointMaxAge drops the arms of workers not registered for this
+// long, so decommissioned machines do not linger in the checkpoint. It
+// matches the age after which the registry forgets saved workers.
+const checkpointMaxAge = 7 * 24 * time.Hour
+
+// checkpointVersion is the format of the file written by a Checkpointer.
+const checkpointVersion = 1
+
+type storedCheckpoint struct {
+	Version   int                  `json:"version"`
+	Scheduler string               `json:"scheduler"`
+	Arms      map[string]storedArm `json:"arms"`
+}
+
+type storedArm struct {
+	// LastSeen is when the worker was last in the registry.
+	LastSeen time.Time       `json:"last_seen"`
+	State    json.RawMessage `json:"state"`
+}
+
+// Checkpointer saves the learned state of a LearningScheduler to a JSON
+// file and restores it, so a restarted coordinator does not schedule
+// cold. The file names the scheduler type; the state of another type is
+// not restored.
+type Checkpointer struct {
+	path     string
+	name     string
+	learner  LearningScheduler
+	registry registry.Registry
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+	saved    []byte // learner state as of the last restore or save
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewCheckpointer returns a Checkpointer keeping the state of learner,
+// a scheduler of type name, in the file at path. The registry tells
+// which workers still exist. Nothing is read or written until Restore,
+// Save or Start.
+func NewCheckpointer(path, name string, learner LearningScheduler, reg registry.Registry) *Checkpointer {
+	return &Checkpointer{
+		path:     path,
+		name:     name,
+		learner:  learner,
+		registry: reg,
+		lastSeen: make(map[string]time.Time),
+	}
+}
+
+// Restore replaces the learner's state with the saved one. A missing
+// file, or one saved by another scheduler type, restores a cold learner.
+// A coordinator taking over from another calls it to pick up the state
+// the other saved.
+func (c *Checkpointer) Restore() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	arms := make(map[string]json.RawMessage, len(stored.Arms))
+	c.lastSeen = make(map[string]time.Time, len(stored.Arms))
+	for id, a := range stored.Arms {
+		if time.Since(a.LastSeen) > checkpointMaxAge {
+			continue
+		}
+		arms[id] = a.State
+		c.lastSeen[id] = a.LastSeen
+	}
+
+	restoreErr := c.learner.Restore(arms)
+	c.saved, _ = json.Marshal(c.learner.Checkpoint())
+	if len(arms) > 0 {
+		log.Info().Int("workers", len(arms)).Str("scheduler", c.name).Msg("Restored learned scheduler state")
+	}
+	if restoreErr != nil {
+		return fmt.Errorf("some workers start cold: %w", restoreErr)
+	}
+	return nil
+}
+
+func (c *Checkpointer) load() (storedCheckpoint, error) {
+	var stored storedCheckpoint
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return stored, nil
+	}
+	if err != nil {
+		return stored, err
+	}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return stored, fmt.Errorf("failed to parse %s: %w", c.path, err)
+	}
+	if stored.Version != checkpointVersion {
+		return stored, fmt.Errorf("%s has unsupported version %d", c.path, stored.Version)
+	}
+	if stored.Scheduler != c.name {
+		log.Info().Str("saved", stored.Scheduler).Str("scheduler", c.name).Msg("Ignoring learned state saved by another scheduler")
+		return storedCheckpoint{}, nil
+	}
+	return stored, nil
+}
+
+// Save writes the learner's state if it changed since the last restore
+// or save, so an idle standby coordinator never overwrites the leader's.
+// Arms of workers not seen in the registry for a week are dropped.
+func (c *Checkpointer) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	arms := c.learner.Checkpoint()
+	encoded, err := json.Marshal(arms)
+	if err != nil {
+		return err
+	}
+	if c.saved != nil && bytes.Equal(encoded, c.saved) {
+		return nil
+	}
+
+	now := time.Now()
+	stored := storedCheckpoint{
+		Version:   checkpointVersion,
+		Scheduler: c.name,
+		Arms:      make(map[string]storedArm, len(arms)),
+	}
+	lastSeen := make(map[string]time.Time, len(arms))
+	for id, state := range arms {
+		seen, ok := c.lastSeen[id]
+		_, registered := c.registry.Get(id)
+		if i := strings.LastIndexByte(id, '/'); !registered && i > 0 {
+			// A per-build-type arm, keyed as described at armKey.
+			_, registered = c.registry.Get(id[:i])
+		}
+		if registered || !ok {
+			seen = now
+		}
+		if now.Sub(seen) > checkpointMaxAge {
+			continue
+		}
+		stored.Arms[id] = storedArm{LastSeen: seen, State: state}
+		lastSeen[id] = seen
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(c.path, data); err != nil {
+		return err
+	}
+	c.lastSeen = lastSeen
+	c.saved = encoded
+	return nil
+}
+
+// Start saves the state every interval until Stop.
+func (c *Checkpointer) Start(interval time.Duration) {
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.Save(); err != nil {
+					log.Warn().Err(err).Msg("Failed to save learned scheduler state")
+				}
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops periodic saving and saves the state a last time.
+func (c *Checkpointer) Stop() {
+	if c.stop != nil {
+		close(c.stop)
+		<-c.done
+	}
+	if err := c.Save(); err != nil {
+		log.Warn().Err(err).Msg("Failed to save learned scheduler state")
+	}
+}
+
+// writeFileAtomic writes data to a temporary file and renames it over
+// path, so a crash never leaves a partial file behind.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+// writeCheckpoint writes a checkpoint file holding the given arms, all
+// last seen at lastSeen.
+func writeCheckpoint(t *testing.T, path, name string, lastSeen time.Time, arms map[string]string) {
+	t.Helper()
+	stored := storedCheckpoint{Version: checkpointVersion, Scheduler: name, Arms: map[string]storedArm{}}
+	for id, state := range arms {
+		stored.Arms[id] = storedArm{LastSeen: lastSeen, State: json.RawMessage(state)}
+	}
+	data, err := json.Marshal(stored)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+}
+
+func TestCheckpointer_EpsilonGreedyRoundTrip(t *testing.T) {
+	reg := newRegistryWithWorkers(t, 2)
+	path := filepath.Join(t.TempDir(), "scheduler.json")
+
+	s := NewEpsilonGreedyScheduler(EpsilonGreedyConfig{Registry: reg})
+	for _, r := range []float64{-1, -2, -3} {
+		s.RecordOutcome("worker-a", r, true, TaskContext{})
+	}
+	s.RecordOutcome("worker-b", -5, true, TaskContext{})
+	require.NoError(t, NewCheckpointer(path, "epsilon-greedy", s, reg).Save())
+
+	restored := NewEpsilonGreedyScheduler(EpsilonGreedyConfig{Registry: reg})
+	require.NoError(t, NewCheckpointer(path, "epsilon-greedy", restored, reg).Restore())
+
+	assert.InDelta(t, -2.0, restored.qValue("worker-a"), 1e-9)
+	assert.InDelta(t, -5.0, restored.qValue("worker-b"), 1e-9)
+	assert.Equal(t, int64(3), restored.values["worker-a"].n)
+}
+
+func TestCheckpointer_LinUCBRoundTrip(t *testing.T) {
+	reg := newRegistryWithWorkers(t, 2)
+	path := filepath.Join(t.TempDir(), "scheduler.json")
+
+	s := NewLinUCBScheduler(LinUCBConfig{Registry: reg})
+	for i := 0; i < 20; i++ {
+		ctx := TaskContext{SourceSizeBytes: 1000 * (i + 1), TaskID: "task-" + idForTest(i)}
+		w, _, err := s.SelectWithDispatchInfo(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64, "", ctx)
+		require.NoError(t, err)
+		s.RecordOutcome(w.ID, -float64(i%3), true, ctx)
+	}
+	require.NoError(t, NewCheckpointer(path, "linucb", s, reg).Save())
+
+	restored := NewLinUCBScheduler(LinUCBConfig{Registry: reg})
+	require.NoError(t, NewCheckpointer(path, "linucb", restored, reg).Restore())
+
+	require.Len(t, restored.arms, len(s.arms))
+	ctx := TaskContext{SourceSizeBytes: 4096}
+	for id, arm := range s.arms {
+		got := restored.arms[id]
+		require.NotNil(t, got, id)
+		assert.Equal(t, arm.count, got.count)
+		w, ok := reg.Get(id)
+		require.True(t, ok)
+		x := s.featureVector(w, pb.Architecture_ARCH_X86_64, ctx)
+		wantMean, wantBonus := s.score(id, x)
+		gotMean, gotBonus := restored.score(id, x)
+		assert.InDelta(t, wantMean, gotMean, 1e-9, "mean of %s", id)
+		assert.InDelta(t, wantBonus, gotBonus, 1e-9, "bonus of %s", id)
+	}
+}
+
+func TestCheckpointer_HEFTRoundTrip(t *testing.T) {
+	reg := newRegistryWithWorkers(t, 2)
+	path := filepath.Join(t.TempDir(), "scheduler.json")
+
+	s := NewHEFTScheduler(HEFTConfig{Registry: reg})
+	s.mu.Lock()
+	s.wbarLocked("worker-a").Update(250)
+	s.count["worker-a"] = 4
+	s.wbarLocked("worker-b").Update(800) // prior only, never observed
+	s.mu.Unlock()
+	require.NoError(t, NewCheckpointer(path, "heft", s, reg).Save())
+
+	restored := NewHEFTScheduler(HEFTConfig{Registry: reg})
+	require.NoError(t, NewCheckpointer(path, "heft", restored, reg).Restore())
+
+	require.Contains(t, restored.wbar, "worker-a")
+	assert.InDelta(t, 250.0, restored.wbar["worker-a"].Value(), 1e-9)
+	assert.Equal(t, int64(4), restored.count["worker-a"])
+	assert.NotContains(t, restored.wbar, "worker-b", "a worker holding only the prior is not checkpointed")
+}
+
+func TestCheckpointer_DropsStaleArms(t *testing.T) {
+	reg := newRegistryWithWorkers(t, 1)
+	path := filepath.Join(t.TempDir(), "scheduler.json")
+	writeCheckpoint(t, path, "epsilon-greedy", time.Now().Add(-checkpointMaxAge-time.Hour), map[string]string{
+		"worker-gone": `{"q":-1,"n":10}`,
+	})
+
+	s := NewEpsilonGreedyScheduler(EpsilonGreedyConfig{Registry: reg})
+	require.NoError(t, NewCheckpointer(path, "epsilon-greedy", s, reg).Restore())
+	assert.Empty(t, s.values)
+}
+
+func TestCheckpointer_SaveKeepsLastSeenOfUnregisteredWorkers(t *testing.T) {
+	reg := newRegistryWithWorkers(t, 1)
+	path := filepath.Join(t.TempDir(), "scheduler.json")
+	seen := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
+	writeCheckpoint(t, path, "epsilon-greedy", seen, map[string]string{
+		"worker-a":          `{"q":-1,"n":1}`,
+		"worker-a/unity":    `{"q":-1,"n":1}`,
+		"worker-gone":       `{"q":-2,"n":1}`,
+		"worker-gone/unity": `{"q":-2,"n":1}`,
+	})
+
+	s := NewEpsilonGreedyScheduler(EpsilonGreedyConfig{Registry: reg})
+	c := NewCheckpointer(path, "epsilon-greedy", s, reg)
+	require.NoError(t, c.Restore())
+	s.RecordOutcome("worker-a", -3, true, TaskContext{})
+	require.NoError(t, c.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var stored storedCheckpoint
+	require.NoError(t, json.Unmarshal(data, &stored))
+	assert.True(t, stored.Arms["worker-gone"].LastSeen.Equal(seen), "unregistered worker keeps its last_seen")
+	assert.True(t, stored.Arms["worker-gone/unity"].LastSeen.Equal(seen), "unregistered worker keeps its last_seen")
+	assert.True(t, stored.Arms["worker-a"].LastSeen.After(seen), "registered worker is seen now")
+	assert.True(t, stored.Arms["worker-a/unity"].LastSeen.After(seen), "build-type arm of a registered worker is seen now")
+}
+
+func TestCheckpointer_IgnoresOtherScheduler(t *testing.T) {
+	reg := newRegistryWithWorkers(t, 1)
+	path := filepath.Join(t.TempDir(), "scheduler.json")
+	writeCheckpoint(t, path, "heft", time.Now(), map[string]string{
+		"worker-a": `{"wbar":250,"count":4}`,
+	})
+
+	s := NewEpsilonGreedyScheduler(EpsilonGreedyConfig{Registry: reg})
+	require.NoError(t, NewCheckpointer(path, "epsilon-greedy", s, reg).Restore())
+	assert.Empty(t, s.values)
+}
+
+func TestCheckpointer_LinUCBSkipsOtherFeatureLayout(t *testing.T) {
+	reg := newRegistryWithWorkers(t, 1)
+	path := filepath.Join(t.TempDir(), "scheduler.json")
+	// The previous layout had Flutter and Unity dims and a build arm shared
+	// by every whole-project build type.
+	const prevDim = 13
+	a := make([]float64, prevDim*prevDim)
+	for i := 0; i < prevDim; i++ {
+		a[i*prevDim+i] = 1
+	}
+	prev, err := json.Marshal(linUCBCheckpoint{Dim: prevDim, A: a, B: make([]float64, prevDim), Count: 1})
+	require.NoError(t, err)
+	writeCheckpoint(t, path, "linucb", time.Now(), map[string]string{
+		"worker-a":       `{"dim":2,"a":[1,0,0,1],"b":[0,0],"count":1}`,
+		"worker-a/build": string(prev),
+	})
+
+	s := NewLinUCBScheduler(LinUCBConfig{Registry: reg})
+	err = NewCheckpointer(path, "linucb", s, reg).Restore()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "worker-a")
+	assert.Contains(t, err.Error(), "dimension 13, want 11")
+	assert.Empty(t, s.arms)
+}
+
+func TestCheckpointer_UnchangedStateIsNotSaved(t *testing.T) {
+	reg := newRegistryWithWorkers(t, 1)
+	path := filepath.Join(t.TempDir(), "scheduler.json")
+
+	s := NewEpsilonGreedyScheduler(EpsilonGreedyConfig{Registry: reg})
+	c := NewCheckpointer(path, "epsilon-greedy", s, reg)
+	require.NoError(t, c.Restore())
+	require.NoError(t, c.Save())
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "a cold learner must not overwrite the checkpoint")
+
+	s.RecordOutcome("worker-a", -1, true, TaskContext{})
+	c.Stop()
+	_, err = os.Stat(path)
+	assert.NoError(t, err, "Stop saves the changed state")
+}
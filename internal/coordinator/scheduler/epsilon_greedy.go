@@ -3,6 +3,9 @@ package scheduler
 import (
 	"crypto/rand"
 	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"sync"
 
@@ -18,6 +21,9 @@ import (
 //
 //	Q_{n+1} = Q_n + (R_n - Q_n) / n
 //
+// Each worker has one arm per build type, since a Unity build's reward
+// says nothing about how fast the worker compiles C++.
+//
 // Selection is ε-greedy: with probability ε the scheduler picks a
 // uniform random eligible worker (explore); otherwise it picks the
 // eligible worker with the highest Q (exploit). Cold workers (n == 0)
@@ -82,9 +88,9 @@ func (s *EpsilonGreedyScheduler) Select(buildType pb.BuildType, arch pb.Architec
 // DispatchInfo's QValueAtDispatch is the chosen worker's current
 // running-mean reward estimate (zero for cold workers), and
 // WasExploration reports whether the choice was random. ε-greedy is a
-// non-contextual bandit and ignores the TaskContext argument.
-func (s *EpsilonGreedyScheduler) SelectWithDispatchInfo(buildType pb.BuildType, arch pb.Architecture, clientOS string, _ TaskContext) (*registry.WorkerInfo, DispatchInfo, error) {
-	candidates, err := s.eligibleWorkers(buildType, arch, clientOS)
+// non-contextual bandit and uses the TaskContext only to filter workers.
+func (s *EpsilonGreedyScheduler) SelectWithDispatchInfo(buildType pb.BuildType, arch pb.Architecture, clientOS string, ctx TaskContext) (*registry.WorkerInfo, DispatchInfo, error) {
+	candidates, err := s.eligibleWorkers(buildType, arch, clientOS, ctx)
 	if err != nil {
 		return nil, DispatchInfo{}, err
 	}
@@ -92,7 +98,7 @@ func (s *EpsilonGreedyScheduler) SelectWithDispatchInfo(buildType pb.BuildType,
 		// Fast path: no choice, no exploration cost. Avoids the
 		// 1-worker overhead the M1 P2C benchmark exhibited.
 		w := candidates[0]
-		return w, DispatchInfo{QValueAtDispatch: s.qValue(w.ID), WasExploration: false}, nil
+		return w, DispatchInfo{QValueAtDispatch: s.qValue(armKey(w.ID, buildType)), WasExploration: false}, nil
 	}
 
 	exploring := s.epsilon > 0 && uniformFloat() < s.epsilon
@@ -100,54 +106,55 @@ func (s *EpsilonGreedyScheduler) SelectWithDispatchInfo(buildType pb.BuildType,
 	if exploring {
 		chosen = candidates[uniformInt(len(candidates))]
 	} else {
-		chosen = s.argmaxQ(candidates)
+		chosen = s.argmaxQ(candidates, buildType)
 	}
-	return chosen, DispatchInfo{QValueAtDispatch: s.qValue(chosen.ID), WasExploration: exploring}, nil
+	return chosen, DispatchInfo{QValueAtDispatch: s.qValue(armKey(chosen.ID, buildType)), WasExploration: exploring}, nil
 }
 
 // RecordOutcome implements LearningScheduler. It updates Q(a) using the
 // incremental sample-mean formula. Failed tasks still update the
 // estimator: a worker that consistently fails should have its Q drop
-// (assuming the caller passes a punishing reward on failure). The
-// TaskContext is unused (ε-greedy is non-contextual).
-func (s *EpsilonGreedyScheduler) RecordOutcome(workerID string, reward float64, _ bool, _ TaskContext) {
+// (assuming the caller passes a punishing reward on failure). Only
+// the TaskContext's build type is used, to pick the arm.
+func (s *EpsilonGreedyScheduler) RecordOutcome(workerID string, reward float64, _ bool, ctx TaskContext) {
 	if workerID == "" || math.IsNaN(reward) || math.IsInf(reward, 0) {
 		return
 	}
+	key := armKey(workerID, ctx.BuildType)
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	st, ok := s.values[workerID]
+	st, ok := s.values[key]
 	if !ok {
 		st = &armState{}
-		s.values[workerID] = st
+		s.values[key] = st
 	}
 	st.n++
 	// Sutton & Barto §2.4: Q_{n+1} = Q_n + (R_n - Q_n)/n
 	st.q += (reward - st.q) / float64(st.n)
 }
 
-// qValue returns the current Q estimate for a worker, or 0 if no
+// qValue returns the current Q estimate for an arm, or 0 if no
 // samples have been recorded.
-func (s *EpsilonGreedyScheduler) qValue(workerID string) float64 {
+func (s *EpsilonGreedyScheduler) qValue(key string) float64 {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if st, ok := s.values[workerID]; ok {
+	if st, ok := s.values[key]; ok {
 		return st.q
 	}
 	return 0
 }
 
-// argmaxQ returns the candidate with the highest Q. Ties are broken by
-// taking the first encountered (input order), which is the registry's
-// listing order — deterministic for a given cluster snapshot.
-func (s *EpsilonGreedyScheduler) argmaxQ(candidates []*registry.WorkerInfo) *registry.WorkerInfo {
+// argmaxQ returns the candidate with the highest Q for buildType. Ties
+// are broken by taking the first encountered (input order), which is the
+// registry's listing order — deterministic for a given cluster snapshot.
+func (s *EpsilonGreedyScheduler) argmaxQ(candidates []*registry.WorkerInfo, buildType pb.BuildType) *registry.WorkerInfo {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	var best *registry.WorkerInfo
 	bestQ := math.Inf(-1)
 	for _, w := range candidates {
 		q := 0.0
-		if st, ok := s.values[w.ID]; ok {
+		if st, ok := s.values[armKey(w.ID, buildType)]; ok {
 			q = st.q
 		}
 		if q > bestQ {
@@ -160,8 +167,8 @@ func (s *EpsilonGreedyScheduler) argmaxQ(candidates []*registry.WorkerInfo) *reg
 
 // eligibleWorkers applies the same admission rules as P2CScheduler so
 // the comparison in M3 evaluation is apples-to-apples.
-func (s *EpsilonGreedyScheduler) eligibleWorkers(buildType pb.BuildType, arch pb.Architecture, clientOS string) ([]*registry.WorkerInfo, error) {
-	workers := s.registry.ListByCapability(buildType, arch)
+func (s *EpsilonGreedyScheduler) eligibleWorkers(buildType pb.BuildType, arch pb.Architecture, clientOS string, ctx TaskContext) ([]*registry.WorkerInfo, error) {
+	workers := ctx.filter(s.registry.ListByCapability(buildType, arch))
 	if len(workers) == 0 {
 		if s.registry.Count() == 0 {
 			return nil, ErrNoWorkers
@@ -169,7 +176,7 @@ func (s *EpsilonGreedyScheduler) eligibleWorkers(buildType pb.BuildType, arch pb
 		return nil, ErrNoMatchingWorkers
 	}
 	if clientOS != "" {
-		workers = filterByOS(workers, clientOS)
+		workers = FilterByOS(workers, clientOS)
 		if len(workers) == 0 {
 			return nil, ErrNoMatchingWorkers
 		}
@@ -236,3 +243,46 @@ func uniformInt(n int) int {
 	}
 	return cryptoRandInt(n)
 }
+
+// epsilonGreedyArm is the checkpoint encoding of an armState.
+type epsilonGreedyArm struct {
+	Q float64 `json:"q"`
+	N int64   `json:"n"`
+}
+
+// Checkpoint implements LearningScheduler.
+func (s *EpsilonGreedyScheduler) Checkpoint() map[string]json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	arms := make(map[string]json.RawMessage, len(s.values))
+	for id, st := range s.values {
+		data, err := json.Marshal(epsilonGreedyArm{Q: st.q, N: st.n})
+		if err != nil {
+			continue
+		}
+		arms[id] = data
+	}
+	return arms
+}
+
+// Restore implements LearningScheduler.
+func (s *EpsilonGreedyScheduler) Restore(arms map[string]json.RawMessage) error {
+	values := make(map[string]*armState, len(arms))
+	var errs []error
+	for id, data := range arms {
+		var a epsilonGreedyArm
+		if err := json.Unmarshal(data, &a); err != nil {
+			errs = append(errs, fmt.Errorf("worker %s: %w", id, err))
+			continue
+		}
+		if a.N <= 0 || math.IsNaN(a.Q) || math.IsInf(a.Q, 0) {
+			errs = append(errs, fmt.Errorf("worker %s: invalid arm state", id))
+			continue
+		}
+		values[id] = &armState{q: a.Q, n: a.N}
+	}
+	s.mu.Lock()
+	s.values = values
+	s.mu.Unlock()
+	return errors.Join(errs...)
+}
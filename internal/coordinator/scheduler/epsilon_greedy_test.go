@@ -49,6 +49,20 @@ func TestEpsilonGreedy_RecordsRunningMean(t *testing.T) {
 	assert.InDelta(t, 2.0, s.qValue("worker-a"), 1e-9)
 }
 
+// TestEpsilonGreedy_ArmPerBuildType checks that rewards of one build
+// type do not move the estimate for another.
+func TestEpsilonGreedy_ArmPerBuildType(t *testing.T) {
+	reg := newRegistryWithWorkers(t, 1)
+	s := NewEpsilonGreedyScheduler(EpsilonGreedyConfig{Registry: reg, Epsilon: 0})
+
+	s.RecordOutcome("worker-a", -0.2, true, TaskContext{BuildType: pb.BuildType_BUILD_TYPE_CPP})
+	s.RecordOutcome("worker-a", -0.9, true, TaskContext{BuildType: pb.BuildType_BUILD_TYPE_UNITY})
+
+	assert.InDelta(t, -0.2, s.qValue("worker-a"), 1e-9)
+	assert.InDelta(t, -0.9, s.qValue("worker-a/unity"), 1e-9)
+	assert.Equal(t, 0.0, s.qValue("worker-a/flutter"))
+}
+
 // TestEpsilonGreedy_ConvergesToBestArm verifies that after enough
 // observations the highest-mean worker is reliably picked under
 // pure greedy (ε=0). Three workers with means -1, -2, -3 (higher is
@@ -190,6 +204,32 @@ func TestSelectWith_FallsBackForNonLearners(t *testing.T) {
 	assert.Equal(t, DispatchInfo{}, info)
 }
 
+// TestSelectWith_HonoursEligible checks that every scheduler only picks
+// workers the TaskContext's Eligible filter accepts.
+func TestSelectWith_HonoursEligible(t *testing.T) {
+	reg := newRegistryWithWorkers(t, 3)
+	ctx := TaskContext{Eligible: func(w *registry.WorkerInfo) bool { return w.ID == "worker-c" }}
+
+	for name, s := range map[string]Scheduler{
+		"simple":         NewSimpleScheduler(reg),
+		"leastloaded":    NewLeastLoadedScheduler(reg),
+		"p2c":            NewP2CScheduler(P2CConfig{Registry: reg}),
+		"epsilon-greedy": NewEpsilonGreedyScheduler(EpsilonGreedyConfig{Registry: reg, Epsilon: 1}),
+		"linucb":         NewLinUCBScheduler(LinUCBConfig{Registry: reg}),
+		"heft":           NewHEFTScheduler(HEFTConfig{Registry: reg}),
+	} {
+		for i := 0; i < 5; i++ {
+			w, _, err := SelectWith(s, pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64, "", ctx)
+			require.NoError(t, err, name)
+			assert.Equal(t, "worker-c", w.ID, name)
+		}
+	}
+
+	none := TaskContext{Eligible: func(*registry.WorkerInfo) bool { return false }}
+	_, _, err := SelectWith(NewLeastLoadedScheduler(reg), pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64, "", none)
+	assert.ErrorIs(t, err, ErrNoMatchingWorkers)
+}
+
 // TestSelectWith_UsesLearnerInterface confirms a LearningScheduler's
 // SelectWithDispatchInfo is preferred over Select when available.
 func TestSelectWith_UsesLearnerInterface(t *testing.T) {
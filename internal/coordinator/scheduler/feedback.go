@@ -1,6 +1,11 @@
 package scheduler
 
 import (
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
 	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
 )
@@ -22,6 +27,53 @@ type TaskContext struct {
 	// updated by this task) which causes target leakage and biases the
 	// learned parameters. See code-review finding CRITICAL-1.
 	TaskID string
+
+	// UpwardRank is the task's upward rank in the build graph the client
+	// submitted, scaled so the critical path has rank 1. Zero when the
+	// client submitted no graph or the graph does not list the source.
+	UpwardRank float64
+
+	// BuildType is the kind of task. Learners keep C++ compiles and
+	// whole-project builds, which take seconds to hours, in separate
+	// arms so a Unity build does not skew the estimates for sub-second
+	// compiles. Zero is treated as BUILD_TYPE_CPP.
+	BuildType pb.BuildType
+
+	// Eligible, when set, restricts selection to the workers it accepts,
+	// for requirements the registry does not index, such as a Flutter
+	// target platform or a Rust toolchain.
+	Eligible func(*registry.WorkerInfo) bool
+}
+
+// filter returns the workers ctx.Eligible accepts, or workers unchanged
+// when it is nil.
+func (ctx TaskContext) filter(workers []*registry.WorkerInfo) []*registry.WorkerInfo {
+	if ctx.Eligible == nil {
+		return workers
+	}
+	kept := make([]*registry.WorkerInfo, 0, len(workers))
+	for _, w := range workers {
+		if ctx.Eligible(w) {
+			kept = append(kept, w)
+		}
+	}
+	return kept
+}
+
+// armKey is the key learners store the estimates of workerID for
+// buildType under. C++ compiles keep the bare worker ID, so checkpoints
+// taken before whole-project builds reached the learners still restore.
+func armKey(workerID string, buildType pb.BuildType) string {
+	if isCompile(buildType) {
+		return workerID
+	}
+	return workerID + "/" + strings.ToLower(strings.TrimPrefix(buildType.String(), "BUILD_TYPE_"))
+}
+
+// isCompile reports whether buildType denotes a C++ compile rather than
+// a whole-project build.
+func isCompile(buildType pb.BuildType) bool {
+	return buildType == pb.BuildType_BUILD_TYPE_CPP || buildType == pb.BuildType_BUILD_TYPE_UNSPECIFIED
 }
 
 // DispatchInfo carries learner-internal state observed at the moment the
@@ -64,15 +116,66 @@ type LearningScheduler interface {
 	// contextual learners can compute the feature vector for the
 	// outcome update without rebuilding it from worker state.
 	RecordOutcome(workerID string, reward float64, success bool, ctx TaskContext)
+
+	// Checkpoint returns the learned parameters keyed by worker ID, in
+	// an encoding private to the implementation. Workers the learner
+	// has no observations for may be omitted.
+	Checkpoint() map[string]json.RawMessage
+
+	// Restore replaces the learned parameters with a previous
+	// Checkpoint of the same scheduler type. Entries that cannot be
+	// decoded, for example because the feature layout changed since,
+	// are skipped, leaving their workers cold, and reported in the
+	// returned error; the other entries are restored regardless.
+	Restore(arms map[string]json.RawMessage) error
+}
+
+// LatencyReward is the reward fed to a LearningScheduler for a task
+// taking latencyMs. Reward convention: higher is better. We use a
+// normalised negative log-latency so the reward magnitude does not dwarf
+// LinUCB's UCB exploration bonus during warm-up (code-review finding
+// HIGH-2). Normalisation divisor is log1p(timeout in ms) so the reward
+// lies in roughly [-1, 0]; failed tasks get -1. A non-positive timeout
+// means two minutes. The log transform compresses the heavy tail (M1
+// P99/P50 ≈ 29×); see docs/thesis/theory-notes.md §4.3 for the
+// empirical-choice rationale (this is NOT the Decima reward function).
+func LatencyReward(latencyMs float64, success bool, timeout time.Duration) float64 {
+	if !success {
+		return -1.0
+	}
+	timeoutMs := float64(timeout.Milliseconds())
+	if timeoutMs <= 0 {
+		timeoutMs = 120000 // 2-minute fallback
+	}
+	return -math.Log1p(latencyMs) / math.Log1p(timeoutMs)
 }
 
 // SelectWith dispatches to LearningScheduler when available, falling
 // back to the base Scheduler.Select for non-learning schedulers. The
-// returned DispatchInfo is zero-valued in the fallback case.
+// returned DispatchInfo is zero-valued in the fallback case. The
+// built-in schedulers honour ctx.Eligible; a worker another scheduler
+// picks against it is reported as ErrNoMatchingWorkers.
 func SelectWith(s Scheduler, buildType pb.BuildType, arch pb.Architecture, clientOS string, ctx TaskContext) (*registry.WorkerInfo, DispatchInfo, error) {
 	if learner, ok := s.(LearningScheduler); ok {
 		return learner.SelectWithDispatchInfo(buildType, arch, clientOS, ctx)
 	}
-	w, err := s.Select(buildType, arch, clientOS)
+	var (
+		w   *registry.WorkerInfo
+		err error
+	)
+	if cs, ok := s.(contextSelector); ok {
+		w, err = cs.selectWithContext(buildType, arch, clientOS, ctx)
+	} else {
+		w, err = s.Select(buildType, arch, clientOS)
+	}
+	if err == nil && ctx.Eligible != nil && !ctx.Eligible(w) {
+		return nil, DispatchInfo{}, ErrNoMatchingWorkers
+	}
 	return w, DispatchInfo{}, err
 }
+
+// contextSelector is implemented by the non-learning schedulers so
+// SelectWith can pass them the TaskContext their Select lacks.
+type contextSelector interface {
+	selectWithContext(buildType pb.BuildType, arch pb.Architecture, clientOS string, ctx TaskContext) (*registry.WorkerInfo, error)
+}
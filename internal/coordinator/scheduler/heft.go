@@ -1,6 +1,9 @@
 package scheduler
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"sync"
 
@@ -9,6 +12,10 @@ import (
 	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
 )
 
+// CriticalRankThreshold is the upward rank from which a task counts as on
+// the critical path of its build.
+const CriticalRankThreshold = 0.9
+
 // HEFTScheduler is the online adaptation of the Heterogeneous Earliest
 // Finish Time (HEFT) algorithm by Topcuoglu, Hariri, Wu (2002),
 // "Performance-effective and low-complexity task scheduling for
@@ -23,17 +30,27 @@ import (
 // (EFT, Eq. 3) greedy assignment. We therefore implement only the
 // EFT phase, computing
 //
-//	EST(task, p_j) = avail[j]                      (no predecessors → no comm. delay)
+//	EST(task, p_j) = avail[j] + c_j               (no predecessors; c_j is the input transfer)
 //	EFT(task, p_j) = w_{ij} + EST(task, p_j)
 //
-// where w_{ij} is a per-worker EWMA of observed compilation times and
-// avail[j] = ActiveTasks(j) × w̄_j approximates the queue-clear time.
+// where w_{ij} is a per-worker, per-build-type EWMA of observed times,
+// avail[j] = ActiveTasks(j) × w̄_j approximates the queue-clear time and
+// c_j = NetworkCostMs(p_j, source size) is the communication cost of
+// shipping the task's source to the worker, non-zero for probed (WAN)
+// workers only.
 //
 // Selection: argmin_{p_j} EFT(task, p_j). This is the canonical
 // HEFT-EFT rule with the streaming relaxation made explicit. Any
 // deviation from Topcuoglu 2002 is documented inline above the rule it
 // affects so it survives code review.
 //
+// When the client submits its build graph, TaskContext.UpwardRank carries
+// the full rank_u(n) of Eq. 1, normalised to the critical path. The
+// coordinator's queue dispatches a client's tasks in decreasing rank,
+// which is HEFT's task-prioritising phase; selection additionally keeps
+// the fastest worker's last free slot for tasks ranked at or above
+// CriticalRankThreshold.
+//
 // HEFTScheduler implements LearningScheduler so Compile() can feed it
 // observed compile times. Reward sign is preserved (higher is better)
 // but the relevant signal is the latency itself, which is also what
@@ -84,8 +101,8 @@ func (s *HEFTScheduler) Select(buildType pb.BuildType, arch pb.Architecture, cli
 // reports the negative of the chosen worker's EFT (so larger Q = better,
 // matching the convention used elsewhere). WasExploration is true only
 // when no historical data is available for any candidate (cold start).
-func (s *HEFTScheduler) SelectWithDispatchInfo(buildType pb.BuildType, arch pb.Architecture, clientOS string, _ TaskContext) (*registry.WorkerInfo, DispatchInfo, error) {
-	candidates, err := s.eligibleWorkers(buildType, arch, clientOS)
+func (s *HEFTScheduler) SelectWithDispatchInfo(buildType pb.BuildType, arch pb.Architecture, clientOS string, ctx TaskContext) (*registry.WorkerInfo, DispatchInfo, error) {
+	candidates, err := s.eligibleWorkers(buildType, arch, clientOS, ctx)
 	if err != nil {
 		return nil, DispatchInfo{}, err
 	}
@@ -96,11 +113,11 @@ func (s *HEFTScheduler) SelectWithDispatchInfo(buildType pb.BuildType, arch pb.A
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	bestEFT := -1.0
-	var best *registry.WorkerInfo
 	allCold := true
-	for _, w := range candidates {
-		wbar := s.wbarLocked(w.ID)
+	wij := make([]float64, len(candidates))
+	fastest := 0
+	for i, w := range candidates {
+		wbar := s.wbarLocked(armKey(w.ID, buildType))
 		if !wbar.IsInitialized() {
 			// Cold worker: prior estimate from worker capability heuristic.
 			// We adopt the cost-scheduler plan's hardware prior:
@@ -116,9 +133,30 @@ func (s *HEFTScheduler) SelectWithDispatchInfo(buildType pb.BuildType, arch pb.A
 		} else {
 			allCold = false
 		}
-		w_ij := wbar.Value()
-		avail := float64(w.ActiveTasks) * w_ij
-		eft := avail + w_ij
+		wij[i] = wbar.Value()
+		if wij[i] < wij[fastest] {
+			fastest = i
+		}
+	}
+
+	// Deviation: a task known to be off the critical path leaves the
+	// fastest worker's last free slot to critical ones. Without it the
+	// greedy EFT rule fills the fastest worker with whatever the queue
+	// dispatched first, and a critical task arriving a moment later lands
+	// on a slow worker and stretches the build.
+	reserved := -1
+	if ctx.UpwardRank > 0 && ctx.UpwardRank < CriticalRankThreshold && freeSlots(candidates[fastest]) == 1 {
+		reserved = fastest
+	}
+
+	bestEFT := -1.0
+	var best *registry.WorkerInfo
+	for i, w := range candidates {
+		if i == reserved {
+			continue
+		}
+		avail := float64(w.ActiveTasks) * wij[i]
+		eft := avail + NetworkCostMs(w, ctx.SourceSizeBytes) + wij[i]
 		if best == nil || eft < bestEFT {
 			best = w
 			bestEFT = eft
@@ -129,9 +167,12 @@ func (s *HEFTScheduler) SelectWithDispatchInfo(buildType pb.BuildType, arch pb.A
 
 // RecordOutcome implements LearningScheduler. Failed tasks still update
 // the EWMA: a worker that times out is genuinely slow for our purposes.
-// The TaskContext is unused — HEFT does not condition on per-task features.
-func (s *HEFTScheduler) RecordOutcome(workerID string, _ float64, _ bool, _ TaskContext) {
-	if workerID == "" {
+// HEFT does not condition on per-task features; the TaskContext only
+// tells compiles from whole-project builds. The registry tracks compile
+// times only, so the estimates for builds stay at the hardware prior
+// and their selection reduces to the least loaded, largest worker.
+func (s *HEFTScheduler) RecordOutcome(workerID string, _ float64, _ bool, ctx TaskContext) {
+	if workerID == "" || !isCompile(ctx.BuildType) {
 		return
 	}
 	s.mu.Lock()
@@ -146,6 +187,60 @@ func (s *HEFTScheduler) RecordOutcome(workerID string, _ float64, _ bool, _ Task
 	}
 }
 
+// heftCheckpoint is the checkpoint encoding of a worker's compile time
+// estimate.
+type heftCheckpoint struct {
+	WBar  float64 `json:"wbar"`
+	Count int64   `json:"count"`
+}
+
+// Checkpoint implements LearningScheduler. Workers whose estimate is
+// still the hardware prior are left out so they get a fresh prior.
+func (s *HEFTScheduler) Checkpoint() map[string]json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	arms := make(map[string]json.RawMessage, len(s.count))
+	for id, n := range s.count {
+		e, ok := s.wbar[id]
+		if !ok || n == 0 {
+			continue
+		}
+		data, err := json.Marshal(heftCheckpoint{WBar: e.Value(), Count: n})
+		if err != nil {
+			continue
+		}
+		arms[id] = data
+	}
+	return arms
+}
+
+// Restore implements LearningScheduler.
+func (s *HEFTScheduler) Restore(arms map[string]json.RawMessage) error {
+	wbar := make(map[string]*metrics.EWMA, len(arms))
+	count := make(map[string]int64, len(arms))
+	var errs []error
+	for id, data := range arms {
+		var c heftCheckpoint
+		if err := json.Unmarshal(data, &c); err != nil {
+			errs = append(errs, fmt.Errorf("worker %s: %w", id, err))
+			continue
+		}
+		if c.Count <= 0 || !(c.WBar > 0) || math.IsInf(c.WBar, 0) {
+			errs = append(errs, fmt.Errorf("worker %s: invalid compile time estimate", id))
+			continue
+		}
+		e := metrics.NewEWMA(s.alpha)
+		e.Update(c.WBar)
+		wbar[id] = e
+		count[id] = c.Count
+	}
+	s.mu.Lock()
+	s.wbar = wbar
+	s.count = count
+	s.mu.Unlock()
+	return errors.Join(errs...)
+}
+
 func (s *HEFTScheduler) wbarLocked(workerID string) *metrics.EWMA {
 	if e, ok := s.wbar[workerID]; ok {
 		return e
@@ -155,10 +250,19 @@ func (s *HEFTScheduler) wbarLocked(workerID string) *metrics.EWMA {
 	return e
 }
 
+// freeSlots returns how many more tasks w accepts.
+func freeSlots(w *registry.WorkerInfo) int32 {
+	maxP := w.MaxParallel
+	if maxP <= 0 {
+		maxP = 4
+	}
+	return maxP - w.ActiveTasks
+}
+
 // eligibleWorkers mirrors the admission rules of the other schedulers
 // for apples-to-apples comparison.
-func (s *HEFTScheduler) eligibleWorkers(buildType pb.BuildType, arch pb.Architecture, clientOS string) ([]*registry.WorkerInfo, error) {
-	workers := s.registry.ListByCapability(buildType, arch)
+func (s *HEFTScheduler) eligibleWorkers(buildType pb.BuildType, arch pb.Architecture, clientOS string, ctx TaskContext) ([]*registry.WorkerInfo, error) {
+	workers := ctx.filter(s.registry.ListByCapability(buildType, arch))
 	if len(workers) == 0 {
 		if s.registry.Count() == 0 {
 			return nil, ErrNoWorkers
@@ -166,7 +270,7 @@ func (s *HEFTScheduler) eligibleWorkers(buildType pb.BuildType, arch pb.Architec
 		return nil, ErrNoMatchingWorkers
 	}
 	if clientOS != "" {
-		workers = filterByOS(workers, clientOS)
+		workers = FilterByOS(workers, clientOS)
 		if len(workers) == 0 {
 			return nil, ErrNoMatchingWorkers
 		}
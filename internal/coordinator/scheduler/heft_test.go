@@ -119,6 +119,51 @@ func TestHEFT_QueueAware(t *testing.T) {
 	assert.Equal(t, "idle", w.ID, "queue-clear time should dominate over per-task speed")
 }
 
+// TestHEFT_KeepsLastFastSlotForCriticalPath — a task off the critical
+// path leaves the fastest worker's last free slot to critical tasks.
+func TestHEFT_KeepsLastFastSlotForCriticalPath(t *testing.T) {
+	reg := registry.NewInMemoryRegistry(60 * time.Second)
+	t.Cleanup(reg.Stop)
+
+	require.NoError(t, reg.Add(&registry.WorkerInfo{
+		ID: "fast",
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64, CpuCores: 16,
+			Cpp: &pb.CppCapability{Compilers: []string{"gcc"}},
+		},
+		MaxParallel: 2,
+		ActiveTasks: 1, // one slot left
+	}))
+	require.NoError(t, reg.Add(&registry.WorkerInfo{
+		ID: "slow",
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64, CpuCores: 4,
+			Cpp: &pb.CppCapability{Compilers: []string{"gcc"}},
+		},
+		MaxParallel: 4,
+	}))
+
+	s := NewHEFTScheduler(HEFTConfig{Registry: reg})
+	s.mu.Lock()
+	s.wbarLocked("fast").Update(200)
+	s.wbarLocked("slow").Update(1000)
+	s.mu.Unlock()
+
+	// EFT(fast)=200*1+200=400; EFT(slow)=1000.
+	for _, tc := range []struct {
+		rank float64
+		want string
+	}{
+		{rank: 0, want: "fast"},   // no graph submitted: plain EFT
+		{rank: 0.3, want: "slow"}, // off the critical path
+		{rank: 1, want: "fast"},   // on the critical path
+	} {
+		w, _, err := s.SelectWithDispatchInfo(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64, "", TaskContext{UpwardRank: tc.rank})
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, w.ID, "rank %v", tc.rank)
+	}
+}
+
 // TestHEFT_FastPathSingleCandidate — single worker bypasses scoring.
 func TestHEFT_FastPathSingleCandidate(t *testing.T) {
 	reg := newRegistryWithWorkers(t, 1)
@@ -1,6 +1,9 @@
 package scheduler
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"sync"
 
@@ -56,7 +59,10 @@ import (
 // not as a direct guarantee for this code.
 //
 // LinUCB is sensitive to non-linear reward structure and to drift in
-// θ_a^*. See docs/thesis/theory-notes.md §3.4 and §5 for caveats.
+// θ_a^*. See docs/thesis/theory-notes.md §3.4 and §5 for caveats. To
+// keep the rewards of hour-long Cocos builds out of the model for
+// seconds-long Go builds and sub-second compiles, each worker has an arm
+// per build type, keyed by armKey as in the other learners.
 type LinUCBScheduler struct {
 	registry       registry.Registry
 	circuitChecker CircuitChecker
@@ -65,7 +71,7 @@ type LinUCBScheduler struct {
 	dim            int
 
 	mu   sync.Mutex
-	arms map[string]*linUCBArm
+	arms map[string]*linUCBArm // keyed by armKey
 	// pendingX caches the feature vector observed at Select time for
 	// each in-flight task. RecordOutcome consumes the cached value so
 	// the bandit update sees the same x that drove selection — the
@@ -76,9 +82,9 @@ type LinUCBScheduler struct {
 	pendingX map[string]*mat.VecDense
 }
 
-// linUCBArm holds the per-worker bandit state. We keep both A and its
-// inverse so we can reconstruct from disk in the future and sanity-check
-// the Sherman-Morrison update against a fresh inversion in tests.
+// linUCBArm holds the bandit state of one of a worker's arms. We keep
+// both A and its inverse to sanity-check the Sherman-Morrison update
+// against a fresh inversion in tests.
 type linUCBArm struct {
 	A     *mat.Dense // d×d
 	Ainv  *mat.Dense // d×d cached inverse
@@ -145,7 +151,7 @@ func (s *LinUCBScheduler) Select(buildType pb.BuildType, arch pb.Architecture, c
 // "exploration" when the chosen arm's UCB bonus exceeds its mean term —
 // i.e. selection was driven by uncertainty rather than learned value.
 func (s *LinUCBScheduler) SelectWithDispatchInfo(buildType pb.BuildType, arch pb.Architecture, clientOS string, ctx TaskContext) (*registry.WorkerInfo, DispatchInfo, error) {
-	candidates, err := s.eligibleWorkers(buildType, arch, clientOS)
+	candidates, err := s.eligibleWorkers(buildType, arch, clientOS, ctx)
 	if err != nil {
 		return nil, DispatchInfo{}, err
 	}
@@ -165,7 +171,7 @@ func (s *LinUCBScheduler) SelectWithDispatchInfo(buildType pb.BuildType, arch pb
 	bestMean := math.Inf(-1)
 	for _, w := range candidates {
 		x := s.featureVector(w, arch, ctx)
-		mean, bonus := s.score(w.ID, x)
+		mean, bonus := s.score(armKey(w.ID, buildType), x)
 		p := mean + bonus
 		if p > bestP {
 			bestP = p
@@ -192,10 +198,10 @@ func (s *LinUCBScheduler) SelectWithDispatchInfo(buildType pb.BuildType, arch pb
 
 // score returns the mean estimate (θ̂^T x) and the UCB exploration bonus
 // (α √(x^T A^{-1} x)) for the given arm and context.
-func (s *LinUCBScheduler) score(workerID string, x *mat.VecDense) (mean, bonus float64) {
+func (s *LinUCBScheduler) score(key string, x *mat.VecDense) (mean, bonus float64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	arm := s.armForLocked(workerID)
+	arm := s.armForLocked(key)
 	if arm.dirty {
 		arm.theta = mulMatVec(arm.Ainv, arm.b)
 		arm.dirty = false
@@ -241,7 +247,7 @@ func (s *LinUCBScheduler) RecordOutcome(workerID string, reward float64, _ bool,
 	}
 	delete(s.pendingX, ctx.TaskID)
 
-	arm := s.armForLocked(workerID)
+	arm := s.armForLocked(armKey(workerID, ctx.BuildType))
 
 	// b ← b + r x  (Algorithm 1 line 13)
 	for i := 0; i < s.dim; i++ {
@@ -281,10 +287,10 @@ func (s *LinUCBScheduler) RecordOutcome(workerID string, reward float64, _ bool,
 	arm.dirty = true
 }
 
-// armForLocked returns (creating if needed) the bandit state for a
-// worker. Caller must hold s.mu.
-func (s *LinUCBScheduler) armForLocked(workerID string) *linUCBArm {
-	if a, ok := s.arms[workerID]; ok {
+// armForLocked returns (creating if needed) the bandit state for an
+// arm. Caller must hold s.mu.
+func (s *LinUCBScheduler) armForLocked(key string) *linUCBArm {
+	if a, ok := s.arms[key]; ok {
 		return a
 	}
 	d := s.dim
@@ -301,13 +307,13 @@ func (s *LinUCBScheduler) armForLocked(workerID string) *linUCBArm {
 		theta: mat.NewVecDense(d, nil),
 		dirty: false,
 	}
-	s.arms[workerID] = arm
+	s.arms[key] = arm
 	return arm
 }
 
 // eligibleWorkers applies the same admission rules as P2C and ε-greedy.
-func (s *LinUCBScheduler) eligibleWorkers(buildType pb.BuildType, arch pb.Architecture, clientOS string) ([]*registry.WorkerInfo, error) {
-	workers := s.registry.ListByCapability(buildType, arch)
+func (s *LinUCBScheduler) eligibleWorkers(buildType pb.BuildType, arch pb.Architecture, clientOS string, ctx TaskContext) ([]*registry.WorkerInfo, error) {
+	workers := ctx.filter(s.registry.ListByCapability(buildType, arch))
 	if len(workers) == 0 {
 		if s.registry.Count() == 0 {
 			return nil, ErrNoWorkers
@@ -315,7 +321,7 @@ func (s *LinUCBScheduler) eligibleWorkers(buildType pb.BuildType, arch pb.Archit
 		return nil, ErrNoMatchingWorkers
 	}
 	if clientOS != "" {
-		workers = filterByOS(workers, clientOS)
+		workers = FilterByOS(workers, clientOS)
 		if len(workers) == 0 {
 			return nil, ErrNoMatchingWorkers
 		}
@@ -360,13 +366,14 @@ func (s *LinUCBScheduler) eligibleWorkers(buildType pb.BuildType, arch pb.Archit
 // featureDim is the fixed feature-vector dimension. Increasing this
 // requires a one-time rebuild of all arm states.
 //
-// Layout (9 dims) — derived from the paper-skeleton.md §3.3 design but
-// pruned per code-review finding CRITICAL-2: build-type one-hot dims
-// were always set to (1, 0, 0) under the current Compile() path,
-// making them perfectly collinear with the bias. Removing them keeps
-// the design space identifiable and frees Sherman-Morrison from a
-// degenerate rank-2 subspace during warm-up. Build-type can be added
-// back when Flutter/Unity reach the learning path.
+// Layout (11 dims) — derived from the paper-skeleton.md §3.3 design.
+// There are no build-type dims: each arm learns from a single build
+// type, so per code-review finding CRITICAL-2 such a dim would be
+// constant within the arm, perfectly collinear with the bias, and
+// Sherman-Morrison would carry a degenerate rank-2 subspace during
+// warm-up. The host load dims are 0 until a worker reports its load, and
+// cover work the coordinator does not see, such as a laptop owner
+// compiling locally.
 //
 //	[0]   bias                                                      = 1.0
 //	[1]   log(1 + source_size_bytes) / log(1 + 4 MiB)               (≈ [0, 1])
@@ -377,7 +384,9 @@ func (s *LinUCBScheduler) eligibleWorkers(buildType pb.BuildType, arch pb.Archit
 //	[6]   worker.native_arch == target_arch                         (1.0 / 0.0)
 //	[7]   worker.active_tasks / max_parallel
 //	[8]   worker.recent_rpc_latency_ms / 100                        (capped at 1.0)
-func featureDim() int { return 9 }
+//	[9]   worker host CPU in use                                    (0-1)
+//	[10]  worker host memory in use                                 (0-1)
+func featureDim() int { return 11 }
 
 // sizeNormDenom is log1p of a "typical big translation unit" — a 4 MiB
 // preprocessed source. Using this denominator keeps the size feature
@@ -387,9 +396,8 @@ func featureDim() int { return 9 }
 // (code-review finding MED-4).
 var sizeNormDenom = math.Log1p(4 * 1024 * 1024)
 
-// featureVector builds x_{t,a} for a given (worker, target_arch, ctx).
-// All features are normalized roughly to [0, 1] so ‖x‖ stays bounded —
-// matching the Chu 2011 convention.
+// featureVector builds x_{t,a} for a given (worker, target_arch, ctx). All features are normalized roughly to [0, 1] so
+// ‖x‖ stays bounded — matching the Chu 2011 convention.
 func (s *LinUCBScheduler) featureVector(w *registry.WorkerInfo, targetArch pb.Architecture, ctx TaskContext) *mat.VecDense {
 	d := s.dim
 	x := mat.NewVecDense(d, nil)
@@ -401,7 +409,7 @@ func (s *LinUCBScheduler) featureVector(w *registry.WorkerInfo, targetArch pb.Ar
 	}
 	x.SetVec(1, logSize)
 
-	// target arch one-hot (build type omitted — see featureDim doc).
+	// target arch one-hot
 	switch targetArch {
 	case pb.Architecture_ARCH_X86_64:
 		x.SetVec(2, 1.0)
@@ -444,6 +452,10 @@ func (s *LinUCBScheduler) featureVector(w *registry.WorkerInfo, targetArch pb.Ar
 	}
 	x.SetVec(8, rttNorm)
 
+	// host load reported by the worker
+	x.SetVec(9, hostCPUBusy(w))
+	x.SetVec(10, hostMemoryBusy(w))
+
 	return x
 }
 
@@ -454,3 +466,79 @@ func mulMatVec(A *mat.Dense, v *mat.VecDense) *mat.VecDense {
 	out.MulVec(A, v)
 	return out
 }
+
+// linUCBCheckpoint is the checkpoint encoding of a linUCBArm. Only A and
+// b are stored; A^{-1} and θ̂ are derived from them on restore. Dim
+// guards against restoring arms learned under another feature layout.
+type linUCBCheckpoint struct {
+	Dim   int       `json:"dim"`
+	A     []float64 `json:"a"` // row-major d×d
+	B     []float64 `json:"b"`
+	Count int64     `json:"count"`
+}
+
+// Checkpoint implements LearningScheduler.
+func (s *LinUCBScheduler) Checkpoint() map[string]json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	arms := make(map[string]json.RawMessage, len(s.arms))
+	for id, arm := range s.arms {
+		if arm.count == 0 {
+			continue
+		}
+		c := linUCBCheckpoint{
+			Dim:   s.dim,
+			A:     make([]float64, 0, s.dim*s.dim),
+			B:     make([]float64, s.dim),
+			Count: arm.count,
+		}
+		for i := 0; i < s.dim; i++ {
+			c.A = append(c.A, arm.A.RawRowView(i)...)
+			c.B[i] = arm.b.AtVec(i)
+		}
+		data, err := json.Marshal(c)
+		if err != nil {
+			continue
+		}
+		arms[id] = data
+	}
+	return arms
+}
+
+// Restore implements LearningScheduler. A^{-1} is recomputed by a full
+// inversion, which also resets any drift the Sherman–Morrison updates
+// accumulated before the checkpoint.
+func (s *LinUCBScheduler) Restore(arms map[string]json.RawMessage) error {
+	d := s.dim
+	restored := make(map[string]*linUCBArm, len(arms))
+	var errs []error
+	for id, data := range arms {
+		var c linUCBCheckpoint
+		if err := json.Unmarshal(data, &c); err != nil {
+			errs = append(errs, fmt.Errorf("worker %s: %w", id, err))
+			continue
+		}
+		if c.Dim != d || len(c.A) != d*d || len(c.B) != d {
+			errs = append(errs, fmt.Errorf("worker %s: arm has dimension %d, want %d", id, c.Dim, d))
+			continue
+		}
+		A := mat.NewDense(d, d, c.A)
+		var Ainv mat.Dense
+		if err := Ainv.Inverse(A); err != nil {
+			errs = append(errs, fmt.Errorf("worker %s: %w", id, err))
+			continue
+		}
+		restored[id] = &linUCBArm{
+			A:     A,
+			Ainv:  &Ainv,
+			b:     mat.NewVecDense(d, c.B),
+			theta: mat.NewVecDense(d, nil),
+			dirty: true,
+			count: c.Count,
+		}
+	}
+	s.mu.Lock()
+	s.arms = restored
+	s.mu.Unlock()
+	return errors.Join(errs...)
+}
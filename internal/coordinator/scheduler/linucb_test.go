@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -178,7 +179,7 @@ func TestLinUCB_FeatureVectorDimensions(t *testing.T) {
 		ActiveTasks: 2,
 	}
 	x := s.featureVector(w, pb.Architecture_ARCH_ARM64, TaskContext{SourceSizeBytes: 1 << 16})
-	assert.Equal(t, s.dim, x.Len(), "feature dim must be 9 after build-type collinearity removal")
+	assert.Equal(t, 11, x.Len(), "feature dim must be 11 with the host load dims")
 	assert.Equal(t, 1.0, x.AtVec(0))            // bias
 	// dim 1: log size feature; just check it is in the expected band.
 	assert.Greater(t, x.AtVec(1), 0.5)
@@ -190,6 +191,59 @@ func TestLinUCB_FeatureVectorDimensions(t *testing.T) {
 	assert.InDelta(t, 0.5, x.AtVec(7), 1e-9)    // 2/4 active tasks
 }
 
+// TestLinUCB_HostLoadFeatures checks the host load dims: zero until the
+// worker reports its load, then the CPU and memory shares in use. A host
+// without CPU usage, like macOS, falls back to load average per core.
+func TestLinUCB_HostLoadFeatures(t *testing.T) {
+	reg := newRegistryWithWorkers(t, 1)
+	s := NewLinUCBScheduler(LinUCBConfig{Registry: reg})
+	w := &registry.WorkerInfo{ID: "x", Capabilities: &pb.WorkerCapabilities{CpuCores: 8}}
+
+	x := s.featureVector(w, pb.Architecture_ARCH_X86_64, TaskContext{})
+	assert.Equal(t, 0.0, x.AtVec(9))
+	assert.Equal(t, 0.0, x.AtVec(10))
+
+	w.Load = &pb.WorkerLoad{CpuUsagePercent: 80, MemoryUsagePercent: 25}
+	x = s.featureVector(w, pb.Architecture_ARCH_X86_64, TaskContext{})
+	assert.InDelta(t, 0.8, x.AtVec(9), 1e-6)
+	assert.InDelta(t, 0.25, x.AtVec(10), 1e-6)
+
+	w.Load = &pb.WorkerLoad{LoadAverage_1M: 6}
+	x = s.featureVector(w, pb.Architecture_ARCH_X86_64, TaskContext{})
+	assert.InDelta(t, 0.75, x.AtVec(9), 1e-9)
+}
+
+// TestLinUCB_BuildTypesLearnInSeparateArms verifies a Cocos outcome
+// updates the worker's Cocos arm and leaves its Go and compile arms
+// untouched.
+func TestLinUCB_BuildTypesLearnInSeparateArms(t *testing.T) {
+	reg := registry.NewInMemoryRegistry(time.Minute)
+	t.Cleanup(reg.Stop)
+	for _, id := range []string{"worker-a", "worker-b"} {
+		require.NoError(t, reg.Add(&registry.WorkerInfo{
+			ID:           id,
+			Capabilities: &pb.WorkerCapabilities{Cocos: &pb.CocosCapability{}, Go: &pb.GoCapability{}},
+			MaxParallel:  1,
+		}))
+	}
+	s := NewLinUCBScheduler(LinUCBConfig{Registry: reg})
+
+	ctx := TaskContext{TaskID: "cocos-1", BuildType: pb.BuildType_BUILD_TYPE_COCOS}
+	w, _, err := s.SelectWithDispatchInfo(pb.BuildType_BUILD_TYPE_COCOS, pb.Architecture_ARCH_UNSPECIFIED, "", ctx)
+	require.NoError(t, err)
+	s.RecordOutcome(w.ID, -0.9, true, ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	require.Contains(t, s.arms, w.ID+"/cocos")
+	assert.Equal(t, int64(1), s.arms[w.ID+"/cocos"].count)
+	for _, key := range []string{w.ID + "/go", w.ID} {
+		if arm, ok := s.arms[key]; ok {
+			assert.Zero(t, arm.count, "arm %s must not learn from a Cocos build", key)
+		}
+	}
+}
+
 // TestLinUCB_RewardMonotonicity verifies that giving better rewards to
 // one worker increases its θ̂ᵀx faster than another worker's. Uses the
 // pendingX cache via Select→RecordOutcome so the test exercises the
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+)
+
+// Limits past which a worker's host is saturated, for instance a
+// developer laptop whose owner is compiling locally. P2C passes saturated
+// workers over while others have capacity.
+const (
+	HostSaturatedPercent = 95.0    // CPU or memory usage
+	MinDiskFreeBytes     = 1 << 30 // Free space left for build directories
+)
+
+// hostCPUBusy returns the share of w's host CPU in use, in [0, 1], from
+// the worker's last load report. Where the OS reports no CPU usage, the
+// one-minute load average per core stands in. It includes the worker's
+// own Hybrid-Grid tasks and is zero until the worker reports its load.
+func hostCPUBusy(w *registry.WorkerInfo) float64 {
+	if w.Load == nil {
+		return 0
+	}
+	busy := float64(w.Load.CpuUsagePercent) / 100
+	if busy == 0 && w.Load.LoadAverage_1M > 0 && w.Capabilities != nil && w.Capabilities.CpuCores > 0 {
+		busy = w.Load.LoadAverage_1M / float64(w.Capabilities.CpuCores)
+	}
+	return clamp01(busy)
+}
+
+// hostMemoryBusy returns the share of w's host memory in use, in [0, 1],
+// or zero until the worker reports its load.
+func hostMemoryBusy(w *registry.WorkerInfo) float64 {
+	if w.Load == nil {
+		return 0
+	}
+	return clamp01(float64(w.Load.MemoryUsagePercent) / 100)
+}
+
+// hostSaturated returns true if w's host has no CPU, memory or disk to
+// spare for another task.
+func hostSaturated(w *registry.WorkerInfo) bool {
+	if w.Load == nil {
+		return false
+	}
+	if hostCPUBusy(w)*100 >= HostSaturatedPercent || hostMemoryBusy(w)*100 >= HostSaturatedPercent {
+		return true
+	}
+	return w.Load.DiskFreeBytes > 0 && w.Load.DiskFreeBytes < MinDiskFreeBytes
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,18 @@
+package scheduler
+
+import (
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+)
+
+// NetworkCostMs estimates the milliseconds a task spends on the network
+// reaching w: one round trip plus, when the worker's bandwidth is known,
+// the time to upload sizeBytes. Only workers the coordinator probes have a
+// measured RTT; for the others the cost is zero, as for a local network.
+func NetworkCostMs(w *registry.WorkerInfo, sizeBytes int) float64 {
+	cost := float64(w.RTT.Microseconds()) / 1000
+	if w.BandwidthMbps > 0 {
+		// bits / (Mbit/s * 1e6) seconds, in milliseconds
+		cost += float64(sizeBytes) * 8 / (w.BandwidthMbps * 1000)
+	}
+	return cost
+}
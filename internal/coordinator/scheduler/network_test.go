@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+)
+
+func addWANCppWorker(r *registry.InMemoryRegistry, id string, rtt time.Duration, bandwidthMbps float64) error {
+	return r.Add(&registry.WorkerInfo{
+		ID:              id,
+		Address:         "farm.example.com:50052",
+		DiscoverySource: registry.DiscoverySourceWAN,
+		RTT:             rtt,
+		BandwidthMbps:   bandwidthMbps,
+		MaxParallel:     4,
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			CpuCores:   16,
+			Cpp:        &pb.CppCapability{Compilers: []string{"gcc"}},
+		},
+	})
+}
+
+func TestNetworkCostMs(t *testing.T) {
+	tests := []struct {
+		name   string
+		worker registry.WorkerInfo
+		size   int
+		want   float64
+	}{
+		{"self-registered", registry.WorkerInfo{}, 1 << 20, 0},
+		{"rtt only", registry.WorkerInfo{RTT: 40 * time.Millisecond}, 1 << 20, 40},
+		{"rtt and bandwidth", registry.WorkerInfo{RTT: 40 * time.Millisecond, BandwidthMbps: 8}, 1000000, 1040},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NetworkCostMs(&tt.worker, tt.size); got != tt.want {
+				t.Errorf("NetworkCostMs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeastLoadedScheduler_PrefersLANOnTie(t *testing.T) {
+	reg := newTestRegistry()
+	defer reg.Stop()
+
+	addWANCppWorker(reg, "wan-worker", 80*time.Millisecond, 100)
+	addCppWorker(reg, "lan-worker")
+
+	s := NewLeastLoadedScheduler(reg)
+	for i := 0; i < 10; i++ {
+		worker, err := s.Select(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64, "")
+		if err != nil {
+			t.Fatalf("Select failed: %v", err)
+		}
+		if worker.ID != "lan-worker" {
+			t.Fatalf("Expected lan-worker on equal load, got %s", worker.ID)
+		}
+	}
+
+	// A busy LAN worker still loses to an idle WAN worker
+	reg.IncrementTasks("lan-worker")
+	worker, err := s.Select(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64, "")
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if worker.ID != "wan-worker" {
+		t.Errorf("Expected wan-worker (least loaded), got %s", worker.ID)
+	}
+}
+
+func TestScoreWorker_PenalizesWANRoundTrip(t *testing.T) {
+	reg := newTestRegistry()
+	defer reg.Stop()
+
+	addWANCppWorker(reg, "near", 5*time.Millisecond, 0)
+	addWANCppWorker(reg, "far", 200*time.Millisecond, 0)
+
+	s := NewP2CScheduler(P2CConfig{Registry: reg})
+	near, _ := reg.Get("near")
+	far, _ := reg.Get("far")
+
+	if s.scoreWorker(near, pb.Architecture_ARCH_X86_64) <= s.scoreWorker(far, pb.Architecture_ARCH_X86_64) {
+		t.Error("Expected the nearer worker to score higher")
+	}
+}
+
+func TestHEFT_AccountsForUploadTime(t *testing.T) {
+	reg := newTestRegistry()
+	defer reg.Stop()
+
+	// Same hardware prior; the WAN worker is behind a 10 Mbps link
+	addWANCppWorker(reg, "wan-worker", 50*time.Millisecond, 10)
+	reg.Add(&registry.WorkerInfo{
+		ID:          "lan-worker",
+		Address:     "localhost:50051",
+		MaxParallel: 4,
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			CpuCores:   16,
+			Cpp:        &pb.CppCapability{Compilers: []string{"gcc"}},
+		},
+	})
+	reg.IncrementTasks("lan-worker")
+
+	s := NewHEFTScheduler(HEFTConfig{Registry: reg})
+
+	// A tiny source: the queued task on the LAN worker costs more than the round trip
+	worker, _, err := s.SelectWithDispatchInfo(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64, "", TaskContext{SourceSizeBytes: 1024})
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if worker.ID != "wan-worker" {
+		t.Errorf("Expected wan-worker for a small source, got %s", worker.ID)
+	}
+
+	// 4 MiB take over 3s to upload at 10 Mbps; waiting for the LAN worker is faster
+	worker, _, err = s.SelectWithDispatchInfo(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64, "", TaskContext{SourceSizeBytes: 4 << 20})
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if worker.ID != "lan-worker" {
+		t.Errorf("Expected lan-worker for a large source, got %s", worker.ID)
+	}
+}
@@ -41,7 +41,11 @@ func NewSimpleScheduler(reg registry.Registry) *SimpleScheduler {
 
 // Select chooses the next available worker using round-robin.
 func (s *SimpleScheduler) Select(buildType pb.BuildType, arch pb.Architecture, clientOS string) (*registry.WorkerInfo, error) {
-	workers := s.registry.ListByCapability(buildType, arch)
+	return s.selectWithContext(buildType, arch, clientOS, TaskContext{})
+}
+
+func (s *SimpleScheduler) selectWithContext(buildType pb.BuildType, arch pb.Architecture, clientOS string, ctx TaskContext) (*registry.WorkerInfo, error) {
+	workers := ctx.filter(s.registry.ListByCapability(buildType, arch))
 	if len(workers) == 0 {
 		// Check if there are any workers at all
 		if s.registry.Count() == 0 {
@@ -52,7 +56,7 @@ func (s *SimpleScheduler) Select(buildType pb.BuildType, arch pb.Architecture, c
 
 	// Filter by OS if specified (prefer same-OS workers for native headers)
 	if clientOS != "" {
-		workers = filterByOS(workers, clientOS)
+		workers = FilterByOS(workers, clientOS)
 		if len(workers) == 0 {
 			return nil, ErrNoMatchingWorkers
 		}
@@ -111,9 +115,14 @@ func NewLeastLoadedScheduler(reg registry.Registry) *LeastLoadedScheduler {
 	}
 }
 
-// Select chooses the worker with the least load.
+// Select chooses the worker with the least load, preferring the one
+// cheapest to reach over the network among equally loaded workers.
 func (s *LeastLoadedScheduler) Select(buildType pb.BuildType, arch pb.Architecture, clientOS string) (*registry.WorkerInfo, error) {
-	workers := s.registry.ListByCapability(buildType, arch)
+	return s.selectWithContext(buildType, arch, clientOS, TaskContext{})
+}
+
+func (s *LeastLoadedScheduler) selectWithContext(buildType pb.BuildType, arch pb.Architecture, clientOS string, ctx TaskContext) (*registry.WorkerInfo, error) {
+	workers := ctx.filter(s.registry.ListByCapability(buildType, arch))
 	if len(workers) == 0 {
 		if s.registry.Count() == 0 {
 			return nil, ErrNoWorkers
@@ -123,7 +132,7 @@ func (s *LeastLoadedScheduler) Select(buildType pb.BuildType, arch pb.Architectu
 
 	// Filter by OS if specified
 	if clientOS != "" {
-		workers = filterByOS(workers, clientOS)
+		workers = FilterByOS(workers, clientOS)
 		if len(workers) == 0 {
 			return nil, ErrNoMatchingWorkers
 		}
@@ -135,7 +144,8 @@ func (s *LeastLoadedScheduler) Select(buildType pb.BuildType, arch pb.Architectu
 			continue
 		}
 
-		if best == nil || w.ActiveTasks < best.ActiveTasks {
+		if best == nil || w.ActiveTasks < best.ActiveTasks ||
+			(w.ActiveTasks == best.ActiveTasks && NetworkCostMs(w, 0) < NetworkCostMs(best, 0)) {
 			best = w
 		}
 	}
@@ -156,6 +166,7 @@ const (
 	ScorePerActiveTask   = -15.0 // -15 per active task
 	ScorePerMsLatency    = -0.5  // -0.5 per ms latency
 	ScoreLANSource       = 20.0  // +20 if LAN discovery
+	ScorePerContainer    = -5.0  // -5 per running Docker container on the host
 	ScoreMaxActiveTasks  = 8     // Workers above this are deprioritized
 )
 
@@ -193,7 +204,11 @@ func NewP2CScheduler(cfg P2CConfig) *P2CScheduler {
 
 // Select implements P2C: pick 2 random workers, select the one with higher score.
 func (s *P2CScheduler) Select(buildType pb.BuildType, arch pb.Architecture, clientOS string) (*registry.WorkerInfo, error) {
-	workers := s.registry.ListByCapability(buildType, arch)
+	return s.selectWithContext(buildType, arch, clientOS, TaskContext{})
+}
+
+func (s *P2CScheduler) selectWithContext(buildType pb.BuildType, arch pb.Architecture, clientOS string, ctx TaskContext) (*registry.WorkerInfo, error) {
+	workers := ctx.filter(s.registry.ListByCapability(buildType, arch))
 	if len(workers) == 0 {
 		if s.registry.Count() == 0 {
 			return nil, ErrNoWorkers
@@ -203,7 +218,7 @@ func (s *P2CScheduler) Select(buildType pb.BuildType, arch pb.Architecture, clie
 
 	// Filter by OS if specified (critical for avoiding cross-OS header issues)
 	if clientOS != "" {
-		workers = filterByOS(workers, clientOS)
+		workers = FilterByOS(workers, clientOS)
 		if len(workers) == 0 {
 			return nil, ErrNoMatchingWorkers
 		}
@@ -226,6 +241,11 @@ func (s *P2CScheduler) Select(buildType pb.BuildType, arch pb.Architecture, clie
 		if w.ActiveTasks >= maxParallel {
 			continue
 		}
+		// Skip hosts busy with other work, such as a laptop whose owner
+		// is compiling locally
+		if hostSaturated(w) {
+			continue
+		}
 		candidates = append(candidates, w)
 	}
 
@@ -289,25 +309,31 @@ func (s *P2CScheduler) scoreWorker(w *registry.WorkerInfo, targetArch pb.Archite
 		score += ScoreCrossCompile
 	}
 
-	// CPU cores (normalized, max 16 cores contribute)
+	// CPU cores (normalized, max 16 cores contribute), counting only the
+	// share the host's reported load leaves idle
 	cpuContrib := float64(caps.CpuCores)
 	if cpuContrib > 16 {
 		cpuContrib = 16
 	}
-	score += cpuContrib * ScorePerCPUCore
+	score += cpuContrib * (1 - hostCPUBusy(w)) * ScorePerCPUCore
 
-	// Memory (in GB, max 64GB contributes)
+	// Memory (in GB, max 64GB contributes), likewise only what is free
 	memGB := float64(caps.MemoryBytes) / (1024 * 1024 * 1024)
 	if memGB > 64 {
 		memGB = 64
 	}
-	score += memGB * ScorePerGBMemory
+	score += memGB * (1 - hostMemoryBusy(w)) * ScorePerGBMemory
 
 	// Active tasks penalty
 	score += float64(w.ActiveTasks) * ScorePerActiveTask
 
-	// Latency penalty
-	latencyMs := s.latencyTracker.Get(w.ID)
+	// Containers running on the host compete for it
+	if w.Load != nil {
+		score += float64(w.Load.RunningContainers) * ScorePerContainer
+	}
+
+	// Latency penalty, including the network round trip to WAN workers
+	latencyMs := s.latencyTracker.Get(w.ID) + NetworkCostMs(w, 0)
 	score += latencyMs * ScorePerMsLatency
 
 	// LAN source bonus (check discovery source if available)
@@ -354,11 +380,11 @@ func cryptoRandInt(n int) int {
 	return int(big.Int64())
 }
 
-// filterByOS filters workers by matching operating system.
+// FilterByOS filters workers by matching operating system.
 // Workers with matching OS can compile natively.
 // Workers with Docker can compile preprocessed source from any OS
 // by running inside a compatible Docker container (e.g., dockcross).
-func filterByOS(workers []*registry.WorkerInfo, clientOS string) []*registry.WorkerInfo {
+func FilterByOS(workers []*registry.WorkerInfo, clientOS string) []*registry.WorkerInfo {
 	if clientOS == "" {
 		return workers
 	}
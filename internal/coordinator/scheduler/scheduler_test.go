@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -310,6 +311,56 @@ func TestP2CScheduler_SkipsOverloadedWorkers(t *testing.T) {
 	}
 }
 
+func TestP2CScheduler_SkipsSaturatedHost(t *testing.T) {
+	reg := newTestRegistry()
+	defer reg.Stop()
+
+	// Worker-1 has the better hardware, but its owner is compiling locally
+	addDetailedCppWorker(reg, "worker-1", 16, 64, 0, "mdns")
+	addDetailedCppWorker(reg, "worker-2", 8, 16, 0, "mdns")
+	reg.UpdateLoad("worker-1", &pb.WorkerLoad{CpuUsagePercent: 98, MemoryUsagePercent: 50})
+
+	s := NewP2CScheduler(P2CConfig{Registry: reg})
+
+	for i := 0; i < 10; i++ {
+		worker, err := s.Select(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64, "")
+		if err != nil {
+			t.Fatalf("Select failed: %v", err)
+		}
+		if worker.ID != "worker-2" {
+			t.Errorf("Expected worker-2 (host not saturated), got %s", worker.ID)
+		}
+	}
+
+	// Once every host is saturated, they are still used
+	reg.UpdateLoad("worker-2", &pb.WorkerLoad{DiskFreeBytes: 100 << 20})
+	if _, err := s.Select(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64, ""); err != nil {
+		t.Errorf("Expected a saturated worker rather than %v", err)
+	}
+}
+
+func TestScoreWorker_HostLoad(t *testing.T) {
+	reg := newTestRegistry()
+	defer reg.Stop()
+
+	addDetailedCppWorker(reg, "idle", 8, 16, 0, "mdns")
+	addDetailedCppWorker(reg, "busy", 8, 16, 0, "mdns")
+	reg.UpdateLoad("idle", &pb.WorkerLoad{CpuUsagePercent: 0, MemoryUsagePercent: 0})
+	reg.UpdateLoad("busy", &pb.WorkerLoad{CpuUsagePercent: 50, MemoryUsagePercent: 50, RunningContainers: 2})
+
+	s := NewP2CScheduler(P2CConfig{Registry: reg})
+
+	idle, _ := reg.Get("idle")
+	busy, _ := reg.Get("busy")
+
+	// Half the cores and memory in use, plus two containers:
+	// 4 cores * 10 + 8GB * 5 + 2 * 5 = 90 less
+	diff := s.scoreWorker(idle, pb.Architecture_ARCH_X86_64) - s.scoreWorker(busy, pb.Architecture_ARCH_X86_64)
+	if math.Abs(diff-90) > 1e-9 {
+		t.Errorf("Expected the busy host to score 90 less, got %f", diff)
+	}
+}
+
 func TestP2CScheduler_ReportSuccess(t *testing.T) {
 	reg := newTestRegistry()
 	defer reg.Stop()
@@ -0,0 +1,255 @@
+package server
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
+)
+
+const (
+	// artifactChunkSize is the payload size of each FetchArtifacts data chunk.
+	artifactChunkSize = 256 * 1024
+
+	// defaultArtifactRetention applies when Config.ArtifactRetention is zero.
+	defaultArtifactRetention = time.Hour
+)
+
+// errNotArtifactOwner is returned by artifactSpool.open for another
+// client's build.
+var errNotArtifactOwner = errors.New("artifacts belong to another client")
+
+type spooledArtifacts struct {
+	owner        string // Client that submitted the build
+	path         string
+	artifactList []*pb.ArtifactInfo
+	expiresAt    time.Time
+}
+
+// artifactSpool keeps the artifact archives of builds submitted with
+// defer_artifacts on disk until the client downloads them.
+type artifactSpool struct {
+	dir       string
+	retention time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*spooledArtifacts
+}
+
+func newArtifactSpool(dir string, retention time.Duration) *artifactSpool {
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "hg-coord-artifacts")
+	}
+	if retention <= 0 {
+		retention = defaultArtifactRetention
+	}
+	return &artifactSpool{
+		dir:       dir,
+		retention: retention,
+		entries:   make(map[string]*spooledArtifacts),
+	}
+}
+
+// put writes archive to disk and makes it available under taskID to the
+// build's submitter owner, replacing any earlier archive for the same task.
+func (a *artifactSpool) put(taskID, owner string, archive []byte, artifactList []*pb.ArtifactInfo) error {
+	a.evictExpired(time.Now())
+
+	if err := os.MkdirAll(a.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create artifact dir: %w", err)
+	}
+
+	file, err := os.CreateTemp(a.dir, "task-*.tar")
+	if err != nil {
+		return fmt.Errorf("failed to create artifact file: %w", err)
+	}
+	if _, err := file.Write(archive); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("failed to write artifact file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("failed to write artifact file: %w", err)
+	}
+
+	entry := &spooledArtifacts{
+		owner:        owner,
+		path:         file.Name(),
+		artifactList: cloneArtifactList(artifactList),
+		expiresAt:    time.Now().Add(a.retention),
+	}
+
+	a.mu.Lock()
+	old := a.entries[taskID]
+	a.entries[taskID] = entry
+	a.mu.Unlock()
+
+	if old != nil {
+		_ = os.Remove(old.path)
+	}
+	return nil
+}
+
+// open returns the archive and artifact list spooled for taskID, or
+// errNotArtifactOwner if allowed rejects the build's submitter. The file
+// stays readable even if the entry expires while it is being streamed.
+func (a *artifactSpool) open(taskID string, allowed func(owner string) bool) (*os.File, []*pb.ArtifactInfo, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry, ok := a.entries[taskID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, nil, os.ErrNotExist
+	}
+	if !allowed(entry.owner) {
+		return nil, nil, errNotArtifactOwner
+	}
+
+	file, err := os.Open(entry.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, entry.artifactList, nil
+}
+
+func (a *artifactSpool) evictExpired(now time.Time) {
+	var expired []string
+
+	a.mu.Lock()
+	for taskID, entry := range a.entries {
+		if now.After(entry.expiresAt) {
+			expired = append(expired, entry.path)
+			delete(a.entries, taskID)
+		}
+	}
+	a.mu.Unlock()
+
+	for _, p := range expired {
+		_ = os.Remove(p)
+	}
+}
+
+// close removes every spooled archive.
+func (a *artifactSpool) close() {
+	a.mu.Lock()
+	entries := a.entries
+	a.entries = make(map[string]*spooledArtifacts)
+	a.mu.Unlock()
+
+	for _, entry := range entries {
+		_ = os.Remove(entry.path)
+	}
+}
+
+// deferArtifacts moves resp's artifact archive into the spool when the
+// client asked to download it with FetchArtifacts. If spooling fails the
+// archive stays inline so the build result is not lost.
+func (s *Server) deferArtifacts(ctx context.Context, req *pb.BuildRequest, resp *pb.BuildResponse) *pb.BuildResponse {
+	if !req.DeferArtifacts || resp == nil || len(resp.Artifacts) == 0 {
+		return resp
+	}
+
+	owner, _ := taskOwner(ctx)
+	if err := s.artifacts.put(req.TaskId, owner, resp.Artifacts, resp.ArtifactList); err != nil {
+		log.Warn().Err(err).Str("task_id", req.TaskId).Msg("Failed to spool artifacts; returning them inline")
+		return resp
+	}
+
+	resp.Artifacts = nil
+	return resp
+}
+
+// FetchArtifacts streams the artifacts of a build submitted with
+// defer_artifacts. Each artifact is sent as an ArtifactInfo header followed
+// by its content in data chunks, so clients can write it to disk and verify
+// the checksum without holding the archive in memory.
+func (s *Server) FetchArtifacts(req *pb.FetchArtifactsRequest, stream pb.BuildService_FetchArtifactsServer) error {
+	if req.TaskId == "" {
+		return status.Error(codes.InvalidArgument, "task_id required")
+	}
+
+	file, artifactList, err := s.artifacts.open(req.TaskId, taskAccess(stream.Context()))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return status.Errorf(codes.NotFound, "no artifacts for task %s", req.TaskId)
+		}
+		if errors.Is(err, errNotArtifactOwner) {
+			return status.Errorf(codes.PermissionDenied, "task %s was submitted by another client", req.TaskId)
+		}
+		return status.Errorf(codes.Internal, "failed to open artifacts: %v", err)
+	}
+	defer file.Close()
+
+	infos := make(map[string]*pb.ArtifactInfo, len(artifactList))
+	for _, info := range artifactList {
+		infos[info.Path] = info
+	}
+
+	var sent int64
+	defer func() {
+		metrics.Default().RecordTransfer("download", float64(sent))
+	}()
+
+	reader := tar.NewReader(file)
+	buf := make([]byte, artifactChunkSize)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read artifacts: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		info := &pb.ArtifactInfo{
+			Name:      path.Base(header.Name),
+			Path:      header.Name,
+			SizeBytes: header.Size,
+		}
+		if listed, ok := infos[header.Name]; ok {
+			info = proto.Clone(listed).(*pb.ArtifactInfo)
+		}
+		info.Mode = uint32(header.Mode) & 0o777
+		if err := stream.Send(&pb.ArtifactChunk{
+			Payload: &pb.ArtifactChunk_Info{Info: info},
+		}); err != nil {
+			return err
+		}
+
+		for {
+			n, readErr := reader.Read(buf)
+			if n > 0 {
+				if err := stream.Send(&pb.ArtifactChunk{
+					Payload: &pb.ArtifactChunk_Data{Data: buf[:n]},
+				}); err != nil {
+					return err
+				}
+				sent += int64(n)
+			}
+			if readErr == io.EOF {
+				break
+			}
+			if readErr != nil {
+				return status.Errorf(codes.Internal, "failed to read artifact %s: %v", header.Name, readErr)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+// buildGraphTTL is how long the ranks of a build are kept after they were
+// submitted. Builds running longer lose their hints and compile in plain
+// arrival order.
+const buildGraphTTL = 12 * time.Hour
+
+const (
+	// maxBuildGraphRanks bounds the translation units of one build graph.
+	maxBuildGraphRanks = 100000
+	// maxBuildGraphsPerClient bounds the builds a client keeps ranks for;
+	// submitting another drops the client's oldest.
+	maxBuildGraphsPerClient = 16
+)
+
+// buildGraphs holds the upward ranks clients submit for their builds, so
+// Compile can prioritise translation units on a build's critical path.
+// Builds are kept per client, as queueClientKey identifies it, so one
+// client cannot replace or read the ranks of another's build. They are
+// kept in memory only: after a restart or failover, builds continue
+// without hints.
+type buildGraphs struct {
+	mu      sync.Mutex
+	clients map[string]map[string]*buildGraph // client -> build ID -> graph
+}
+
+type buildGraph struct {
+	ranks     map[string]float64 // source path -> upward rank
+	submitted time.Time
+}
+
+func newBuildGraphs() *buildGraphs {
+	return &buildGraphs{clients: make(map[string]map[string]*buildGraph)}
+}
+
+// put replaces the ranks client submitted for buildID, drops expired
+// builds and, beyond maxBuildGraphsPerClient, the client's oldest. It
+// returns how many ranks were stored; ranks outside (0, 1] are ignored.
+func (b *buildGraphs) put(client, buildID string, ranks []*pb.TranslationUnitRank) int {
+	stored := make(map[string]float64, len(ranks))
+	for _, r := range ranks {
+		if r.SourcePath == "" || !(r.UpwardRank > 0 && r.UpwardRank <= 1) {
+			continue
+		}
+		stored[filepath.Clean(r.SourcePath)] = r.UpwardRank
+	}
+
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for c, builds := range b.clients {
+		for id, g := range builds {
+			if now.Sub(g.submitted) > buildGraphTTL {
+				delete(builds, id)
+			}
+		}
+		if len(builds) == 0 {
+			delete(b.clients, c)
+		}
+	}
+
+	builds, ok := b.clients[client]
+	if !ok {
+		builds = make(map[string]*buildGraph)
+		b.clients[client] = builds
+	}
+	delete(builds, buildID)
+	for len(builds) >= maxBuildGraphsPerClient {
+		oldest := ""
+		for id, g := range builds {
+			if oldest == "" || g.submitted.Before(builds[oldest].submitted) {
+				oldest = id
+			}
+		}
+		delete(builds, oldest)
+	}
+	builds[buildID] = &buildGraph{ranks: stored, submitted: now}
+	return len(stored)
+}
+
+// rank returns the upward rank of sourcePath in the build client submitted
+// as buildID, or 0 if either is unknown. An expired build is dropped.
+func (b *buildGraphs) rank(client, buildID, sourcePath string) float64 {
+	if buildID == "" || sourcePath == "" {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	builds := b.clients[client]
+	g, ok := builds[buildID]
+	if !ok {
+		return 0
+	}
+	if time.Since(g.submitted) > buildGraphTTL {
+		delete(builds, buildID)
+		if len(builds) == 0 {
+			delete(b.clients, client)
+		}
+		return 0
+	}
+	return g.ranks[filepath.Clean(sourcePath)]
+}
+
+// SubmitBuildGraph stores the upward ranks of a build's translation units.
+// Compile requests from the same client naming the build are then
+// dispatched in decreasing rank within the client's share of the queue,
+// and the rank is passed to the scheduler.
+func (s *Server) SubmitBuildGraph(ctx context.Context, req *pb.SubmitBuildGraphRequest) (*pb.SubmitBuildGraphResponse, error) {
+	if req.BuildId == "" {
+		return nil, status.Error(codes.InvalidArgument, "build_id required")
+	}
+	if len(req.Ranks) > maxBuildGraphRanks {
+		return nil, status.Errorf(codes.InvalidArgument, "build graph has %d translation units; at most %d are accepted", len(req.Ranks), maxBuildGraphRanks)
+	}
+	accepted := s.buildGraphs.put(queueClientKey(ctx), req.BuildId, req.Ranks)
+	log.Debug().
+		Str("build_id", req.BuildId).
+		Int("ranks", accepted).
+		Msg("Build graph submitted")
+	return &pb.SubmitBuildGraphResponse{Accepted: int32(accepted)}, nil
+}
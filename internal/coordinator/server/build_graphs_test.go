@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+func TestBuildGraphs_Rank(t *testing.T) {
+	b := newBuildGraphs()
+	n := b.put("host-a", "build-1", []*pb.TranslationUnitRank{
+		{SourcePath: "/src/main.c", UpwardRank: 1},
+		{SourcePath: "/src/./util.c", UpwardRank: 0.4},
+		{SourcePath: "/src/bad.c", UpwardRank: 7},
+		{SourcePath: "", UpwardRank: 0.5},
+	})
+	assert.Equal(t, 2, n)
+
+	assert.Equal(t, 1.0, b.rank("host-a", "build-1", "/src/main.c"))
+	assert.Equal(t, 0.4, b.rank("host-a", "build-1", "/src/util.c"))
+	assert.Zero(t, b.rank("host-a", "build-1", "/src/bad.c"))
+	assert.Zero(t, b.rank("host-a", "build-2", "/src/main.c"))
+	assert.Zero(t, b.rank("host-a", "", "/src/main.c"))
+}
+
+func TestBuildGraphs_ScopedToClient(t *testing.T) {
+	b := newBuildGraphs()
+	b.put("host-a", "build-1", []*pb.TranslationUnitRank{{SourcePath: "/src/main.c", UpwardRank: 1}})
+	b.put("host-b", "build-1", []*pb.TranslationUnitRank{{SourcePath: "/src/main.c", UpwardRank: 0.5}})
+
+	assert.Equal(t, 1.0, b.rank("host-a", "build-1", "/src/main.c"))
+	assert.Equal(t, 0.5, b.rank("host-b", "build-1", "/src/main.c"))
+	assert.Zero(t, b.rank("host-c", "build-1", "/src/main.c"))
+}
+
+func TestBuildGraphs_CapsBuildsPerClient(t *testing.T) {
+	b := newBuildGraphs()
+	for i := 0; i <= maxBuildGraphsPerClient; i++ {
+		b.put("host-a", fmt.Sprintf("build-%d", i), []*pb.TranslationUnitRank{{SourcePath: "/src/a.c", UpwardRank: 1}})
+		b.clients["host-a"][fmt.Sprintf("build-%d", i)].submitted = time.Now().Add(time.Duration(i-maxBuildGraphsPerClient) * time.Minute)
+	}
+	b.put("host-b", "build-0", nil)
+
+	assert.Len(t, b.clients["host-a"], maxBuildGraphsPerClient)
+	assert.Zero(t, b.rank("host-a", "build-0", "/src/a.c"))
+	assert.Equal(t, 1.0, b.rank("host-a", fmt.Sprintf("build-%d", maxBuildGraphsPerClient), "/src/a.c"))
+	assert.Contains(t, b.clients["host-b"], "build-0")
+}
+
+func TestBuildGraphs_Expire(t *testing.T) {
+	b := newBuildGraphs()
+	b.put("host-a", "old", []*pb.TranslationUnitRank{{SourcePath: "/src/a.c", UpwardRank: 1}})
+	b.put("host-b", "stale", nil)
+	b.clients["host-a"]["old"].submitted = time.Now().Add(-buildGraphTTL - time.Minute)
+	b.clients["host-b"]["stale"].submitted = time.Now().Add(-buildGraphTTL - time.Minute)
+
+	// Looking up an expired build drops it; put drops the others.
+	assert.Zero(t, b.rank("host-a", "old", "/src/a.c"))
+	assert.NotContains(t, b.clients, "host-a")
+	b.put("host-a", "new", nil)
+	assert.NotContains(t, b.clients, "host-b")
+}
+
+func TestSubmitBuildGraph_RequiresBuildID(t *testing.T) {
+	s := New(Config{HeartbeatTTL: 30 * time.Second})
+	defer s.Stop()
+
+	_, err := s.SubmitBuildGraph(context.Background(), &pb.SubmitBuildGraphRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	resp, err := s.SubmitBuildGraph(context.Background(), &pb.SubmitBuildGraphRequest{
+		BuildId: "build-1",
+		Ranks:   []*pb.TranslationUnitRank{{SourcePath: "/src/main.c", UpwardRank: 1}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), resp.Accepted)
+	assert.Equal(t, 1.0, s.buildGraphs.rank("", "build-1", "/src/main.c"))
+
+	_, err = s.SubmitBuildGraph(context.Background(), &pb.SubmitBuildGraphRequest{
+		BuildId: "build-2",
+		Ranks:   make([]*pb.TranslationUnitRank, maxBuildGraphRanks+1),
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cache"
+	"github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
+)
+
+const (
+	defaultBuildCacheMaxSizeMB = 10 * 1024 // 10 GiB
+	defaultBuildCacheTTLHours  = 7 * 24
+)
+
+// buildCacheEntry is a successful build result served to later requests with
+// the same cache key.
+type buildCacheEntry struct {
+	artifacts    []byte
+	artifactList []*pb.ArtifactInfo
+	stdout       string
+	stderr       string
+	buildTimeMs  int64
+}
+
+// buildResultCache keeps successful Flutter, Unity and routed build results,
+// and C/C++ object files shared by every client, in a cache.Store on disk.
+// The store evicts least recently used results beyond its size limit,
+// expires them after its TTL and persists its index, so warm results
+// survive coordinator restarts without being held in memory.
+type buildResultCache struct {
+	store *cache.Store
+}
+
+// newBuildResultCache opens the cache in dir. Empty dir uses a directory
+// under os.TempDir(); zero limits use the defaults. If the directory cannot
+// be used the cache is disabled rather than failing coordinator startup.
+func newBuildResultCache(dir string, maxSizeMB int64, ttlHours int) *buildResultCache {
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "hg-coord-build-cache")
+	}
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultBuildCacheMaxSizeMB
+	}
+	if ttlHours <= 0 {
+		ttlHours = defaultBuildCacheTTLHours
+	}
+
+	store, err := cache.NewStore(dir, maxSizeMB, ttlHours)
+	if err != nil {
+		log.Warn().Err(err).Str("dir", dir).Msg("Failed to open build cache; build results will not be cached")
+		return &buildResultCache{}
+	}
+	return &buildResultCache{store: store}
+}
+
+// get returns the cached result for key in the kind namespace ("flutter",
+// "unity", or a route name), or nil.
+func (c *buildResultCache) get(kind, key string) *buildCacheEntry {
+	var resp pb.BuildResponse
+	if !c.load(buildCacheStoreKey(kind, key), &resp) {
+		return nil
+	}
+
+	return &buildCacheEntry{
+		artifacts:    resp.Artifacts,
+		artifactList: resp.ArtifactList,
+		stdout:       resp.Stdout,
+		stderr:       resp.Stderr,
+		buildTimeMs:  resp.BuildTimeMs,
+	}
+}
+
+// put stores resp's output and artifacts under key in the kind namespace.
+// Failures are logged; the build result is still returned to the client.
+func (c *buildResultCache) put(kind, key string, resp *pb.BuildResponse) {
+	c.save(buildCacheStoreKey(kind, key), &pb.BuildResponse{
+		Stdout:       resp.Stdout,
+		Stderr:       resp.Stderr,
+		Artifacts:    resp.Artifacts,
+		ArtifactList: resp.ArtifactList,
+		BuildTimeMs:  resp.BuildTimeMs,
+	})
+}
+
+// getObject returns the cached compile result for a CompilationKey, or nil.
+func (c *buildResultCache) getObject(key string) *pb.CompileResponse {
+	var resp pb.CompileResponse
+	if !c.load(buildCacheStoreKey("cpp", key), &resp) {
+		return nil
+	}
+	return &resp
+}
+
+// putObject stores a successful compile's object file and compiler output.
+func (c *buildResultCache) putObject(key string, resp *pb.CompileResponse) {
+	c.save(buildCacheStoreKey("cpp", key), &pb.CompileResponse{
+		Status:            pb.TaskStatus_STATUS_COMPLETED,
+		ObjectFile:        resp.ObjectFile,
+		Stdout:            resp.Stdout,
+		Stderr:            resp.Stderr,
+		CompilationTimeMs: resp.CompilationTimeMs,
+	})
+}
+
+func (c *buildResultCache) load(storeKey string, msg proto.Message) bool {
+	if c == nil || c.store == nil {
+		return false
+	}
+
+	data, ok := c.store.GetBytes(storeKey)
+	if !ok {
+		return false
+	}
+
+	if err := proto.Unmarshal(data, msg); err != nil {
+		log.Warn().Err(err).Str("key", storeKey).Msg("Dropping unreadable build cache entry")
+		_ = c.store.Delete(storeKey)
+		return false
+	}
+
+	metrics.Default().RecordCacheEntrySize("hit", float64(len(data)))
+	return true
+}
+
+func (c *buildResultCache) save(storeKey string, msg proto.Message) {
+	if c == nil || c.store == nil {
+		return
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		log.Warn().Err(err).Str("key", storeKey).Msg("Failed to encode build cache entry")
+		return
+	}
+
+	if err := c.store.Put(storeKey, bytes.NewReader(data)); err != nil {
+		log.Warn().Err(err).Str("key", storeKey).Msg("Failed to write build cache entry")
+		return
+	}
+
+	metrics.Default().RecordCacheEntrySize("store", float64(len(data)))
+}
+
+// compileCacheKey builds the shared object cache key for req with the same
+// cache.CompilationKey clients use locally, including the compiler version
+// the client reports. The source hash also covers bundled headers and
+// include paths for raw-source requests and every input for link requests,
+// and the client OS is included because it decides the object file format.
+func compileCacheKey(req *pb.CompileRequest) string {
+	kb := cache.NewKeyBuilder()
+	if req.Link {
+		hashes := make(map[string]string, len(req.LinkInputs))
+		for name, data := range req.LinkInputs {
+			hashes[name] = cache.HashBytes(data)
+		}
+		kb.AddString(cache.LinkInputsHash(req.CompilerArgs, hashes))
+	} else if len(req.RawSource) > 0 {
+		kb.AddString("raw")
+		kb.AddString(req.SourceFilename)
+		kb.AddString(cache.HashBytes(req.RawSource))
+		kb.AddStrings(req.IncludePaths)
+
+		paths := make([]string, 0, len(req.IncludeFiles))
+		for path := range req.IncludeFiles {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			kb.AddString(path)
+			kb.AddString(cache.HashBytes(req.IncludeFiles[path]))
+		}
+	} else {
+		kb.AddString("preprocessed")
+		kb.AddString(cache.HashBytes(req.PreprocessedSource))
+	}
+
+	key := &cache.CompilationKey{
+		Compiler:    req.Compiler,
+		CompilerVer: req.CompilerVersion,
+		TargetArch:  req.TargetArch.String(),
+		Flags:       req.CompilerArgs,
+		SourceHash:  kb.Sum(),
+		TargetOS:    req.ClientOs,
+	}
+	return key.Build()
+}
+
+// buildCacheStoreKey puts the hex cache key first so cache.Store shards
+// entries by hash rather than by build kind.
+func buildCacheStoreKey(kind, key string) string {
+	return key + "-" + kind
+}
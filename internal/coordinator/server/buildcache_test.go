@@ -0,0 +1,129 @@
+package server
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+func TestBuildResultCache_PersistsAcrossRestart(t *testing.T) {
+	dir := t.TempDir()
+	key := "abcdef0123456789"
+
+	c := newBuildResultCache(dir, 0, 0)
+	c.put("flutter", key, &pb.BuildResponse{
+		Stdout:       "built",
+		Artifacts:    []byte("apk-bytes"),
+		ArtifactList: []*pb.ArtifactInfo{{Name: "app.apk", Path: "build/app.apk", SizeBytes: 9}},
+		BuildTimeMs:  1200,
+	})
+
+	reopened := newBuildResultCache(dir, 0, 0)
+	cached := reopened.get("flutter", key)
+	require.NotNil(t, cached)
+	assert.Equal(t, "built", cached.stdout)
+	assert.Equal(t, []byte("apk-bytes"), cached.artifacts)
+	assert.Equal(t, int64(1200), cached.buildTimeMs)
+	require.Len(t, cached.artifactList, 1)
+	assert.Equal(t, "build/app.apk", cached.artifactList[0].Path)
+
+	assert.Nil(t, reopened.get("unity", key), "kinds must not share entries")
+}
+
+func TestBuildResultCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newBuildResultCache(t.TempDir(), 1, 0)
+	payload := bytes.Repeat([]byte("x"), 400*1024)
+
+	c.put("unity", "aa01", &pb.BuildResponse{Artifacts: payload})
+	c.put("unity", "bb02", &pb.BuildResponse{Artifacts: payload})
+	require.NotNil(t, c.get("unity", "aa01"))
+	c.put("unity", "cc03", &pb.BuildResponse{Artifacts: payload})
+
+	assert.NotNil(t, c.get("unity", "aa01"))
+	assert.Nil(t, c.get("unity", "bb02"))
+	assert.NotNil(t, c.get("unity", "cc03"))
+}
+
+func TestBuildResultCache_DisabledWhenDirUnusable(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "not-a-dir")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0644))
+
+	c := newBuildResultCache(file, 0, 0)
+	c.put("flutter", "abcd", &pb.BuildResponse{Artifacts: []byte("x")})
+	assert.Nil(t, c.get("flutter", "abcd"))
+}
+
+func TestCompileCacheKey(t *testing.T) {
+	base := &pb.CompileRequest{
+		TaskId:             "task-a",
+		Compiler:           "clang",
+		CompilerArgs:       []string{"-c", "-O2"},
+		PreprocessedSource: []byte("int f(void) { return 1; }"),
+		TargetArch:         pb.Architecture_ARCH_ARM64,
+		ClientOs:           "darwin",
+	}
+	key := compileCacheKey(base)
+
+	sameTU := proto.Clone(base).(*pb.CompileRequest)
+	sameTU.TaskId = "task-b"
+	sameTU.ClientArch = pb.Architecture_ARCH_X86_64
+	assert.Equal(t, key, compileCacheKey(sameTU), "task and client details must not affect the key")
+
+	otherOS := proto.Clone(base).(*pb.CompileRequest)
+	otherOS.ClientOs = "linux"
+	assert.NotEqual(t, key, compileCacheKey(otherOS))
+
+	otherFlags := proto.Clone(base).(*pb.CompileRequest)
+	otherFlags.CompilerArgs = []string{"-c", "-O0"}
+	assert.NotEqual(t, key, compileCacheKey(otherFlags))
+
+	raw := &pb.CompileRequest{
+		Compiler:       "clang",
+		RawSource:      []byte(`#include "f.h"`),
+		SourceFilename: "main.c",
+		IncludeFiles:   map[string][]byte{"f.h": []byte("int f(void);")},
+	}
+	rawKey := compileCacheKey(raw)
+
+	otherHeader := proto.Clone(raw).(*pb.CompileRequest)
+	otherHeader.IncludeFiles["f.h"] = []byte("long f(void);")
+	assert.NotEqual(t, rawKey, compileCacheKey(otherHeader))
+
+	link := &pb.CompileRequest{
+		Compiler:        "gcc",
+		CompilerVersion: "gcc (GCC) 13.2.0",
+		CompilerArgs:    []string{"inputs/0-main.o", "-o", "a.out"},
+		Link:            true,
+		LinkInputs:      map[string][]byte{"inputs/0-main.o": []byte("obj")},
+	}
+	linkKey := compileCacheKey(link)
+
+	otherLinker := proto.Clone(link).(*pb.CompileRequest)
+	otherLinker.CompilerVersion = "gcc (GCC) 14.1.0"
+	assert.NotEqual(t, linkKey, compileCacheKey(otherLinker), "links with another compiler version must not share results")
+}
+
+func TestBuildResultCache_Objects(t *testing.T) {
+	c := newBuildResultCache(t.TempDir(), 0, 0)
+	assert.Nil(t, c.getObject("abcd"))
+
+	c.putObject("abcd", &pb.CompileResponse{
+		Status:     pb.TaskStatus_STATUS_COMPLETED,
+		ObjectFile: []byte("obj"),
+		Stdout:     "ok",
+		WorkerId:   "worker-1",
+	})
+
+	cached := c.getObject("abcd")
+	require.NotNil(t, cached)
+	assert.Equal(t, []byte("obj"), cached.ObjectFile)
+	assert.Equal(t, "ok", cached.Stdout)
+	assert.Empty(t, cached.WorkerId)
+}
@@ -0,0 +1,453 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/cache"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/scheduler"
+	"github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
+)
+
+// buildRewardTimeout normalises the reward learning schedulers get for a
+// whole-project build, as Config.RequestTimeout does for compiles, so
+// builds running for hours still earn distinguishable rewards.
+const buildRewardTimeout = 4 * time.Hour
+
+// buildRoute describes how a build type without a dedicated handler is
+// routed to workers.
+type buildRoute struct {
+	// name is the build type reported in task events and logs.
+	name string
+	// supports reports whether worker can run req. Workers have already been
+	// filtered by registry.ListByCapability for the request's build type.
+	supports func(worker *registry.WorkerInfo, req *pb.BuildRequest) bool
+	// cacheKey returns the result cache key for req. Routes without one are
+	// never cached.
+	cacheKey func(req *pb.BuildRequest) string
+}
+
+// buildRoutes maps build types to their routes. Flutter and Unity keep their
+// own handlers and caches.
+var buildRoutes = map[pb.BuildType]buildRoute{
+	pb.BuildType_BUILD_TYPE_COCOS:  {name: "cocos", supports: workerSupportsCocosConfig, cacheKey: cocosBuildCacheKey},
+	pb.BuildType_BUILD_TYPE_RUST:   {name: "rust", supports: workerSupportsRustConfig},
+	pb.BuildType_BUILD_TYPE_GO:     {name: "go", supports: workerSupportsGoConfig, cacheKey: goBuildCacheKey},
+	pb.BuildType_BUILD_TYPE_NODEJS: {name: "node", supports: workerSupportsNodeConfig, cacheKey: nodeBuildCacheKey},
+}
+
+// dispatchBuild sends req to the handler for its build type. It is shared by
+// Build and StreamBuild, which differ only in how the source reaches the
+// worker. CancelTask cancels the build's context while it is queued or
+// running; the worker then kills the build and the response reports
+// STATUS_CANCELLED. WatchTask follows the build's output until it returns.
+func (s *Server) dispatchBuild(ctx context.Context, req *pb.BuildRequest, forward buildForwarder) (*pb.BuildResponse, error) {
+	owner, _ := taskOwner(ctx)
+	ctx, release := s.running.Track(ctx, req.TaskId, owner)
+	defer release()
+	s.taskOutput.Open(req.TaskId, owner)
+	defer s.taskOutput.Close(req.TaskId)
+
+	var (
+		resp *pb.BuildResponse
+		err  error
+	)
+	switch route, routed := buildRoutes[req.BuildType]; {
+	case req.GetFlutterConfig() != nil:
+		resp, err = s.handleFlutterBuild(ctx, req, forward)
+	case req.GetUnityConfig() != nil:
+		resp, err = s.handleUnityBuild(ctx, req, forward)
+	case routed:
+		resp, err = s.handleRoutedBuild(ctx, req, forward, route)
+	default:
+		return &pb.BuildResponse{
+			Status:   pb.TaskStatus_STATUS_FAILED,
+			ExitCode: 1,
+			Stderr:   "build type not implemented yet",
+		}, nil
+	}
+
+	if resp != nil && resp.Status == pb.TaskStatus_STATUS_FAILED && ctx.Err() == context.Canceled {
+		resp.Status = pb.TaskStatus_STATUS_CANCELLED
+	}
+	return resp, err
+}
+
+// handleRoutedBuild forwards req to the worker the scheduler picks among
+// those that support it.
+func (s *Server) handleRoutedBuild(ctx context.Context, req *pb.BuildRequest, forward buildForwarder, route buildRoute) (*pb.BuildResponse, error) {
+	m := metrics.Default()
+
+	var cacheKey string
+	if route.cacheKey != nil {
+		cacheKey = route.cacheKey(req)
+
+		if cached := s.buildCache.get(route.name, cacheKey); cached != nil {
+			atomic.AddInt64(&s.cacheHits, 1)
+			atomic.AddInt64(&s.totalTasks, 1)
+			atomic.AddInt64(&s.successTasks, 1)
+
+			taskStart := time.Now().Unix()
+			s.notifyTaskStarted(ctx, &TaskEvent{
+				ID:        req.TaskId,
+				BuildType: route.name,
+				Status:    "running",
+				StartedAt: taskStart,
+			})
+			s.notifyTaskCompleted(ctx, &TaskEvent{
+				ID:          req.TaskId,
+				BuildType:   route.name,
+				Status:      "completed",
+				StartedAt:   taskStart,
+				CompletedAt: time.Now().Unix(),
+				DurationMs:  cached.buildTimeMs,
+				FromCache:   true,
+			})
+
+			return s.deferArtifacts(ctx, req, &pb.BuildResponse{
+				Status:       pb.TaskStatus_STATUS_COMPLETED,
+				ExitCode:     0,
+				Stdout:       cached.stdout,
+				Stderr:       cached.stderr,
+				Artifacts:    cached.artifacts,
+				ArtifactList: cached.artifactList,
+				BuildTimeMs:  cached.buildTimeMs,
+				FromCache:    true,
+			}), nil
+		}
+
+		atomic.AddInt64(&s.cacheMisses, 1)
+	}
+
+	taskCtx := scheduler.TaskContext{TaskID: req.TaskId, BuildType: req.BuildType}
+	worker, queueTime, err := s.acquireWorker(ctx, route.name, req.Priority, 0, func() (*registry.WorkerInfo, error) {
+		return s.selectRoutedWorker(req, route, taskCtx)
+	})
+	if err != nil {
+		atomic.AddInt64(&s.totalTasks, 1)
+		atomic.AddInt64(&s.failedTasks, 1)
+		log.Error().Err(err).Str("task_id", req.TaskId).
+			Str("build_type", route.name).
+			Msg("No worker available for build")
+
+		taskStart := time.Now().Unix()
+		s.notifyTaskStarted(ctx, &TaskEvent{
+			ID:        req.TaskId,
+			BuildType: route.name,
+			Status:    "running",
+			StartedAt: taskStart,
+		})
+		s.notifyTaskCompleted(ctx, &TaskEvent{
+			ID:           req.TaskId,
+			BuildType:    route.name,
+			Status:       "failed",
+			StartedAt:    taskStart,
+			CompletedAt:  time.Now().Unix(),
+			ExitCode:     1,
+			ErrorMessage: fmt.Sprintf("no worker available: %v", err),
+		})
+
+		return &pb.BuildResponse{
+			Status:   pb.TaskStatus_STATUS_FAILED,
+			ExitCode: 1,
+			Stderr:   fmt.Sprintf("no worker available: %v", err),
+		}, nil
+	}
+
+	conn, err := s.workerConns.get(ctx, worker.Address)
+	if err != nil {
+		s.releaseWorker(worker.ID, false, 0)
+		log.Error().Err(err).Str("worker", worker.ID).Msg("Failed to connect to worker")
+		return &pb.BuildResponse{
+			Status:   pb.TaskStatus_STATUS_FAILED,
+			ExitCode: 1,
+			Stderr:   fmt.Sprintf("failed to connect to worker: %v", err),
+		}, nil
+	}
+
+	atomic.AddInt64(&s.activeTasks, 1)
+	atomic.AddInt64(&s.totalTasks, 1)
+
+	val, _ := s.activeTasksByWorker.LoadOrStore(worker.ID, new(int64))
+	count := atomic.AddInt64(val.(*int64), 1)
+	m.SetActiveTaskCount(worker.ID, float64(count))
+
+	defer func() {
+		atomic.AddInt64(&s.activeTasks, -1)
+		if val, ok := s.activeTasksByWorker.Load(worker.ID); ok {
+			c := atomic.AddInt64(val.(*int64), -1)
+			m.SetActiveTaskCount(worker.ID, float64(c))
+		}
+	}()
+
+	taskStartTime := time.Now()
+
+	s.notifyTaskStarted(ctx, &TaskEvent{
+		ID:        req.TaskId,
+		BuildType: route.name,
+		Status:    "running",
+		WorkerID:  worker.ID,
+		StartedAt: taskStartTime.Unix(),
+	})
+
+	buildResp, err := s.forwardWatched(ctx, req.TaskId, pb.NewBuildServiceClient(conn), forward)
+	if buildResp != nil {
+		buildResp.QueueTimeMs = queueTime.Milliseconds()
+	}
+
+	success := err == nil && buildResp != nil && buildResp.Status == pb.TaskStatus_STATUS_COMPLETED
+
+	taskCompletedTime := time.Now()
+	s.releaseWorker(worker.ID, success, 0)
+	s.recordBuildOutcome(worker.ID, buildResp, success, taskCtx)
+
+	if success {
+		atomic.AddInt64(&s.successTasks, 1)
+	} else {
+		atomic.AddInt64(&s.failedTasks, 1)
+	}
+
+	event := &TaskEvent{
+		ID:          req.TaskId,
+		BuildType:   route.name,
+		WorkerID:    worker.ID,
+		StartedAt:   taskStartTime.Unix(),
+		CompletedAt: taskCompletedTime.Unix(),
+		DurationMs:  taskCompletedTime.Sub(taskStartTime).Milliseconds(),
+	}
+	if success {
+		event.Status = "completed"
+		event.ExitCode = buildResp.ExitCode
+	} else {
+		event.Status = "failed"
+		event.ExitCode = 1
+		if err != nil {
+			event.ErrorMessage = err.Error()
+		} else if buildResp != nil {
+			event.ExitCode = buildResp.ExitCode
+			event.ErrorMessage = buildResp.Stderr
+		}
+	}
+	s.notifyTaskCompleted(ctx, event)
+
+	if err != nil {
+		log.Error().Err(err).Str("task_id", req.TaskId).Str("worker", worker.ID).
+			Msg("Worker build failed")
+		return &pb.BuildResponse{
+			Status:   pb.TaskStatus_STATUS_FAILED,
+			ExitCode: 1,
+			Stderr:   fmt.Sprintf("worker error: %v", err),
+		}, nil
+	}
+
+	if cacheKey != "" && success && len(buildResp.Artifacts) > 0 {
+		s.buildCache.put(route.name, cacheKey, buildResp)
+	}
+
+	return s.deferArtifacts(ctx, req, buildResp), nil
+}
+
+// selectRoutedWorker picks a worker whose capabilities match req. It
+// returns errWorkersBusy when every matching worker is at capacity.
+func (s *Server) selectRoutedWorker(req *pb.BuildRequest, route buildRoute, taskCtx scheduler.TaskContext) (*registry.WorkerInfo, error) {
+	supports := func(w *registry.WorkerInfo) bool {
+		return route.supports == nil || route.supports(w, req)
+	}
+	return s.selectBuildWorker(req.BuildType, supports, taskCtx,
+		fmt.Errorf("no %s worker matches the requested configuration", route.name))
+}
+
+// selectBuildWorker picks a worker for a whole-project build of buildType
+// through the configured scheduler, among the healthy workers supports
+// accepts. It returns none when there are no such workers, and
+// errWorkersBusy when all of them are at capacity.
+func (s *Server) selectBuildWorker(buildType pb.BuildType, supports func(*registry.WorkerInfo) bool, taskCtx scheduler.TaskContext, none error) (*registry.WorkerInfo, error) {
+	taskCtx.Eligible = func(w *registry.WorkerInfo) bool {
+		return w.IsHealthy(s.config.HeartbeatTTL) && supports(w)
+	}
+
+	var matching []*registry.WorkerInfo
+	for _, w := range s.registry.ListByCapability(buildType, pb.Architecture_ARCH_UNSPECIFIED) {
+		if taskCtx.Eligible(w) {
+			matching = append(matching, w)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, none
+	}
+	// Keep the build queued while every matching worker is busy.
+	if _, err := pickLeastLoaded(matching); err != nil {
+		return nil, err
+	}
+
+	w, _, err := scheduler.SelectWith(s.scheduler, buildType, pb.Architecture_ARCH_UNSPECIFIED, "", taskCtx)
+	if errors.Is(err, scheduler.ErrNoMatchingWorkers) {
+		// The scheduler also skips workers whose circuit is open;
+		// wait for them to recover rather than failing the build.
+		return nil, errWorkersBusy
+	}
+	return w, err
+}
+
+// recordBuildOutcome feeds the duration of a whole-project build on
+// workerID to the scheduler, if it learns.
+func (s *Server) recordBuildOutcome(workerID string, resp *pb.BuildResponse, success bool, taskCtx scheduler.TaskContext) {
+	learner, ok := s.scheduler.(scheduler.LearningScheduler)
+	if !ok {
+		return
+	}
+	var latencyMs float64
+	if resp != nil {
+		latencyMs = float64(resp.BuildTimeMs)
+	}
+	reward := scheduler.LatencyReward(latencyMs, success && resp != nil, buildRewardTimeout)
+	learner.RecordOutcome(workerID, reward, success, taskCtx)
+}
+
+// workerSupportsRustConfig checks the requested toolchain and target against
+// the worker's rustup report. Toolchains are listed in full
+// ("stable-x86_64-unknown-linux-gnu"), so a request for "stable" matches by
+// channel prefix.
+func workerSupportsRustConfig(worker *registry.WorkerInfo, req *pb.BuildRequest) bool {
+	if worker == nil || worker.Capabilities == nil || worker.Capabilities.Rust == nil {
+		return false
+	}
+	caps := worker.Capabilities.Rust
+	cfg := req.GetRustConfig()
+
+	if toolchain := strings.TrimSpace(cfg.GetToolchain()); toolchain != "" && len(caps.Toolchains) > 0 {
+		found := false
+		for _, tc := range caps.Toolchains {
+			if tc == toolchain || strings.HasPrefix(tc, toolchain+"-") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if target := strings.TrimSpace(cfg.GetTarget()); target != "" {
+		for _, t := range caps.Targets {
+			if t == target {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// workerSupportsGoConfig checks the requested Go version and target against
+// the worker's toolchain. A version such as "1.22" matches any 1.22.x
+// release; other platforms need a toolchain that can cross-compile.
+func workerSupportsGoConfig(worker *registry.WorkerInfo, req *pb.BuildRequest) bool {
+	if worker == nil || worker.Capabilities == nil || worker.Capabilities.Go == nil {
+		return false
+	}
+	caps := worker.Capabilities.Go
+	cfg := req.GetGoConfig()
+
+	if version := strings.TrimPrefix(strings.TrimSpace(cfg.GetGoVersion()), "go"); version != "" {
+		if caps.Version != version && !strings.HasPrefix(caps.Version, version+".") {
+			return false
+		}
+	}
+
+	if caps.CrossCompile {
+		return true
+	}
+	goos := strings.TrimSpace(cfg.GetGoos())
+	return goos == "" || goos == worker.Capabilities.Os
+}
+
+func goBuildCacheKey(req *pb.BuildRequest) string {
+	cfg := req.GetGoConfig()
+	return cache.GoCacheKey(cfg, cfg.GetGoSumHash(), req.SourceHash, cfg.GetGoVersion())
+}
+
+// workerSupportsNodeConfig checks the requested Node.js version and package
+// manager against the worker. A version such as "20" matches any 20.x
+// release.
+func workerSupportsNodeConfig(worker *registry.WorkerInfo, req *pb.BuildRequest) bool {
+	if worker == nil || worker.Capabilities == nil || worker.Capabilities.Nodejs == nil {
+		return false
+	}
+	caps := worker.Capabilities.Nodejs
+	cfg := req.GetNodeConfig()
+
+	if version := strings.TrimPrefix(strings.TrimSpace(cfg.GetNodeVersion()), "v"); version != "" {
+		found := false
+		for _, v := range caps.Versions {
+			v = strings.TrimPrefix(v, "v")
+			if v == version || strings.HasPrefix(v, version+".") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if manager := strings.ToLower(strings.TrimSpace(cfg.GetPackageManager())); manager != "" && len(caps.PackageManagers) > 0 {
+		for _, m := range caps.PackageManagers {
+			if m == manager {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func nodeBuildCacheKey(req *pb.BuildRequest) string {
+	cfg := req.GetNodeConfig()
+	return cache.NodeCacheKey(cfg, cfg.GetLockfileHash(), req.SourceHash, cfg.GetNodeVersion())
+}
+
+// workerSupportsCocosConfig checks the requested Creator version and target
+// platform against the worker. A version such as "3.8" matches any 3.8.x
+// editor.
+func workerSupportsCocosConfig(worker *registry.WorkerInfo, req *pb.BuildRequest) bool {
+	if worker == nil || worker.Capabilities == nil || worker.Capabilities.Cocos == nil {
+		return false
+	}
+	caps := worker.Capabilities.Cocos
+
+	if version := strings.TrimSpace(req.GetCocosConfig().GetCocosVersion()); version != "" {
+		found := false
+		for _, v := range caps.Versions {
+			if v == version || strings.HasPrefix(v, version+".") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, p := range caps.Platforms {
+		if p == req.TargetPlatform {
+			return true
+		}
+	}
+	return false
+}
+
+func cocosBuildCacheKey(req *pb.BuildRequest) string {
+	cfg := req.GetCocosConfig()
+	return cache.CocosCacheKey(cfg, req.SourceHash, cfg.GetCocosVersion(), req.TargetPlatform)
+}
@@ -0,0 +1,396 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+)
+
+func newRustBuildRequest(taskID string, cfg *pb.RustConfig) *pb.BuildRequest {
+	return &pb.BuildRequest{
+		TaskId:        taskID,
+		BuildType:     pb.BuildType_BUILD_TYPE_RUST,
+		SourceHash:    "rust-source",
+		SourceArchive: []byte("rust-archive"),
+		Config:        &pb.BuildRequest_RustConfig{RustConfig: cfg},
+	}
+}
+
+func rustWorker(id, addr string, rust *pb.RustCapability) *registry.WorkerInfo {
+	return &registry.WorkerInfo{
+		ID:      id,
+		Address: addr,
+		Capabilities: &pb.WorkerCapabilities{
+			WorkerId:   id,
+			Hostname:   id,
+			CpuCores:   4,
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			Rust:       rust,
+		},
+		MaxParallel: 4,
+	}
+}
+
+func TestBuild_Rust_RoutesToMatchingWorker(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 2 * time.Second})
+	defer cleanup()
+
+	var hostCalls, crossCalls int64
+	hostAddr, hostCleanup := setupTestWorker(t, func(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+		atomic.AddInt64(&hostCalls, 1)
+		return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+	})
+	defer hostCleanup()
+	crossAddr, crossCleanup := setupTestWorker(t, func(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+		atomic.AddInt64(&crossCalls, 1)
+		assert.Equal(t, "aarch64-unknown-linux-gnu", req.GetRustConfig().GetTarget())
+		assert.True(t, req.GetRustConfig().GetRelease())
+		return &pb.BuildResponse{
+			Status:       pb.TaskStatus_STATUS_COMPLETED,
+			Stdout:       "cargo build",
+			ArtifactList: []*pb.ArtifactInfo{{Name: "demo", Path: "target/aarch64-unknown-linux-gnu/release/demo"}},
+		}, nil
+	})
+	defer crossCleanup()
+
+	require.NoError(t, s.registry.Add(rustWorker("rust-host", hostAddr, &pb.RustCapability{
+		Toolchains: []string{"stable-x86_64-unknown-linux-gnu"},
+		Targets:    []string{"x86_64-unknown-linux-gnu"},
+	})))
+	require.NoError(t, s.registry.Add(rustWorker("rust-cross", crossAddr, &pb.RustCapability{
+		Toolchains: []string{"stable-x86_64-unknown-linux-gnu"},
+		Targets:    []string{"x86_64-unknown-linux-gnu", "aarch64-unknown-linux-gnu"},
+	})))
+
+	resp, err := client.Build(context.Background(), newRustBuildRequest("rust-cross-build", &pb.RustConfig{
+		Toolchain: "stable",
+		Target:    "aarch64-unknown-linux-gnu",
+		Release:   true,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status)
+	assert.Equal(t, "cargo build", resp.Stdout)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&crossCalls))
+	assert.Equal(t, int64(0), atomic.LoadInt64(&hostCalls))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&s.successTasks))
+}
+
+func TestBuild_Rust_NoMatchingWorker(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second})
+	defer cleanup()
+
+	require.NoError(t, s.registry.Add(rustWorker("rust-host", "127.0.0.1:1", &pb.RustCapability{
+		Targets: []string{"x86_64-unknown-linux-gnu"},
+	})))
+
+	resp, err := client.Build(context.Background(), newRustBuildRequest("rust-wasm", &pb.RustConfig{
+		Target: "wasm32-unknown-unknown",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_FAILED, resp.Status)
+	assert.Contains(t, resp.Stderr, "no worker available")
+	assert.Equal(t, int64(1), atomic.LoadInt64(&s.failedTasks))
+}
+
+func TestWorkerSupportsRustConfig(t *testing.T) {
+	worker := rustWorker("rust", "", &pb.RustCapability{
+		Toolchains: []string{"stable-x86_64-unknown-linux-gnu", "nightly-2024-05-01-x86_64-unknown-linux-gnu"},
+		Targets:    []string{"x86_64-unknown-linux-gnu", "wasm32-unknown-unknown"},
+	})
+
+	tests := []struct {
+		name string
+		cfg  *pb.RustConfig
+		want bool
+	}{
+		{name: "defaults", cfg: &pb.RustConfig{}, want: true},
+		{name: "channel", cfg: &pb.RustConfig{Toolchain: "stable"}, want: true},
+		{name: "dated nightly", cfg: &pb.RustConfig{Toolchain: "nightly-2024-05-01"}, want: true},
+		{name: "missing toolchain", cfg: &pb.RustConfig{Toolchain: "beta"}, want: false},
+		{name: "installed target", cfg: &pb.RustConfig{Target: "wasm32-unknown-unknown"}, want: true},
+		{name: "missing target", cfg: &pb.RustConfig{Target: "aarch64-apple-darwin"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := workerSupportsRustConfig(worker, newRustBuildRequest("t", tt.cfg))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.False(t, workerSupportsRustConfig(rustWorker("no-rust", "", nil), newRustBuildRequest("t", &pb.RustConfig{})))
+}
+
+func newGoBuildRequest(taskID string, cfg *pb.GoConfig) *pb.BuildRequest {
+	return &pb.BuildRequest{
+		TaskId:        taskID,
+		BuildType:     pb.BuildType_BUILD_TYPE_GO,
+		SourceHash:    "go-source",
+		SourceArchive: []byte("go-archive"),
+		Config:        &pb.BuildRequest_GoConfig{GoConfig: cfg},
+	}
+}
+
+func TestBuild_Go_CachesByGoSum(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 2 * time.Second})
+	defer cleanup()
+
+	var buildCalls int64
+	addr, workerCleanup := setupTestWorker(t, func(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+		atomic.AddInt64(&buildCalls, 1)
+		assert.Equal(t, "linux", req.GetGoConfig().GetGoos())
+		return &pb.BuildResponse{
+			Status:       pb.TaskStatus_STATUS_COMPLETED,
+			Stdout:       "go build",
+			Artifacts:    []byte("go-binaries"),
+			ArtifactList: []*pb.ArtifactInfo{{Name: "api", Path: "bin/api"}},
+		}, nil
+	})
+	defer workerCleanup()
+
+	require.NoError(t, s.registry.Add(&registry.WorkerInfo{
+		ID:      "go-worker",
+		Address: addr,
+		Capabilities: &pb.WorkerCapabilities{
+			WorkerId: "go-worker",
+			Os:       "darwin",
+			Go:       &pb.GoCapability{Version: "1.22.4", CrossCompile: true},
+		},
+		MaxParallel: 4,
+	}))
+
+	cfg := &pb.GoConfig{GoVersion: "1.22", Goos: "linux", Goarch: "amd64", GoSumHash: "sum-1"}
+	resp, err := client.Build(context.Background(), newGoBuildRequest("go-1", cfg))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status)
+	assert.False(t, resp.FromCache)
+
+	resp, err = client.Build(context.Background(), newGoBuildRequest("go-2", cfg))
+	require.NoError(t, err)
+	assert.True(t, resp.FromCache)
+	assert.Equal(t, []byte("go-binaries"), resp.Artifacts)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&buildCalls))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&s.cacheHits))
+
+	changed := &pb.GoConfig{GoVersion: "1.22", Goos: "linux", Goarch: "amd64", GoSumHash: "sum-2"}
+	resp, err = client.Build(context.Background(), newGoBuildRequest("go-3", changed))
+	require.NoError(t, err)
+	assert.False(t, resp.FromCache)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&buildCalls))
+}
+
+func TestWorkerSupportsGoConfig(t *testing.T) {
+	goWorker := func(version string, cross bool) *registry.WorkerInfo {
+		return &registry.WorkerInfo{
+			ID: "go",
+			Capabilities: &pb.WorkerCapabilities{
+				Os: "linux",
+				Go: &pb.GoCapability{Version: version, CrossCompile: cross},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		worker *registry.WorkerInfo
+		cfg    *pb.GoConfig
+		want   bool
+	}{
+		{name: "defaults", worker: goWorker("1.22.4", true), cfg: &pb.GoConfig{}, want: true},
+		{name: "minor version", worker: goWorker("1.22.4", true), cfg: &pb.GoConfig{GoVersion: "1.22"}, want: true},
+		{name: "go prefix", worker: goWorker("1.22.4", true), cfg: &pb.GoConfig{GoVersion: "go1.22.4"}, want: true},
+		{name: "other minor", worker: goWorker("1.22.4", true), cfg: &pb.GoConfig{GoVersion: "1.2"}, want: false},
+		{name: "newer version", worker: goWorker("1.22.4", true), cfg: &pb.GoConfig{GoVersion: "1.23"}, want: false},
+		{name: "cross compile", worker: goWorker("1.22.4", true), cfg: &pb.GoConfig{Goos: "windows"}, want: true},
+		{name: "no cross compile", worker: goWorker("1.22.4", false), cfg: &pb.GoConfig{Goos: "windows"}, want: false},
+		{name: "native without cross compile", worker: goWorker("1.22.4", false), cfg: &pb.GoConfig{Goos: "linux"}, want: true},
+		{name: "no go", worker: &registry.WorkerInfo{Capabilities: &pb.WorkerCapabilities{}}, cfg: &pb.GoConfig{}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := workerSupportsGoConfig(tt.worker, newGoBuildRequest("t", tt.cfg))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func newNodeBuildRequest(taskID string, cfg *pb.NodeConfig) *pb.BuildRequest {
+	return &pb.BuildRequest{
+		TaskId:        taskID,
+		BuildType:     pb.BuildType_BUILD_TYPE_NODEJS,
+		SourceHash:    "node-source",
+		SourceArchive: []byte("node-archive"),
+		Config:        &pb.BuildRequest_NodeConfig{NodeConfig: cfg},
+	}
+}
+
+func TestBuild_Node_CachesByLockfile(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 2 * time.Second})
+	defer cleanup()
+
+	var buildCalls int64
+	addr, workerCleanup := setupTestWorker(t, func(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+		atomic.AddInt64(&buildCalls, 1)
+		return &pb.BuildResponse{
+			Status:       pb.TaskStatus_STATUS_COMPLETED,
+			Artifacts:    []byte("dist-archive"),
+			ArtifactList: []*pb.ArtifactInfo{{Name: "index.html", Path: "dist/index.html"}},
+		}, nil
+	})
+	defer workerCleanup()
+
+	require.NoError(t, s.registry.Add(&registry.WorkerInfo{
+		ID:      "node-worker",
+		Address: addr,
+		Capabilities: &pb.WorkerCapabilities{
+			WorkerId: "node-worker",
+			Nodejs:   &pb.NodeCapability{Versions: []string{"20.11.1"}, PackageManagers: []string{"npm", "pnpm"}},
+		},
+		MaxParallel: 4,
+	}))
+
+	cfg := &pb.NodeConfig{NodeVersion: "20", PackageManager: "pnpm", LockfileHash: "lock-1"}
+	resp, err := client.Build(context.Background(), newNodeBuildRequest("node-1", cfg))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status)
+	assert.False(t, resp.FromCache)
+
+	resp, err = client.Build(context.Background(), newNodeBuildRequest("node-2", cfg))
+	require.NoError(t, err)
+	assert.True(t, resp.FromCache)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&buildCalls))
+
+	changed := &pb.NodeConfig{NodeVersion: "20", PackageManager: "pnpm", LockfileHash: "lock-2"}
+	resp, err = client.Build(context.Background(), newNodeBuildRequest("node-3", changed))
+	require.NoError(t, err)
+	assert.False(t, resp.FromCache)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&buildCalls))
+}
+
+func TestWorkerSupportsNodeConfig(t *testing.T) {
+	worker := &registry.WorkerInfo{
+		ID: "node",
+		Capabilities: &pb.WorkerCapabilities{
+			Nodejs: &pb.NodeCapability{Versions: []string{"20.11.1"}, PackageManagers: []string{"npm", "yarn"}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		cfg  *pb.NodeConfig
+		want bool
+	}{
+		{name: "defaults", cfg: &pb.NodeConfig{}, want: true},
+		{name: "major", cfg: &pb.NodeConfig{NodeVersion: "20"}, want: true},
+		{name: "v prefix", cfg: &pb.NodeConfig{NodeVersion: "v20.11"}, want: true},
+		{name: "other major", cfg: &pb.NodeConfig{NodeVersion: "2"}, want: false},
+		{name: "missing version", cfg: &pb.NodeConfig{NodeVersion: "22"}, want: false},
+		{name: "installed manager", cfg: &pb.NodeConfig{PackageManager: "yarn"}, want: true},
+		{name: "missing manager", cfg: &pb.NodeConfig{PackageManager: "pnpm"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := workerSupportsNodeConfig(worker, newNodeBuildRequest("t", tt.cfg))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func newCocosBuildRequest(taskID string, platform pb.TargetPlatform, cfg *pb.CocosConfig) *pb.BuildRequest {
+	return &pb.BuildRequest{
+		TaskId:         taskID,
+		BuildType:      pb.BuildType_BUILD_TYPE_COCOS,
+		TargetPlatform: platform,
+		SourceHash:     "cocos-source",
+		SourceArchive:  []byte("cocos-archive"),
+		Config:         &pb.BuildRequest_CocosConfig{CocosConfig: cfg},
+	}
+}
+
+func TestBuild_Cocos_RoutesByPlatform(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 2 * time.Second})
+	defer cleanup()
+
+	var buildCalls int64
+	addr, workerCleanup := setupTestWorker(t, func(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+		atomic.AddInt64(&buildCalls, 1)
+		return &pb.BuildResponse{
+			Status:       pb.TaskStatus_STATUS_COMPLETED,
+			Artifacts:    []byte("build-archive"),
+			ArtifactList: []*pb.ArtifactInfo{{Name: "index.html", Path: "build/web-mobile/index.html"}},
+		}, nil
+	})
+	defer workerCleanup()
+
+	require.NoError(t, s.registry.Add(&registry.WorkerInfo{
+		ID:      "cocos-worker",
+		Address: addr,
+		Capabilities: &pb.WorkerCapabilities{
+			WorkerId: "cocos-worker",
+			Cocos: &pb.CocosCapability{
+				Versions:  []string{"3.8.2"},
+				Platforms: []pb.TargetPlatform{pb.TargetPlatform_PLATFORM_WEB},
+			},
+		},
+		MaxParallel: 4,
+	}))
+
+	cfg := &pb.CocosConfig{CocosVersion: "3.8"}
+	resp, err := client.Build(context.Background(), newCocosBuildRequest("cocos-1", pb.TargetPlatform_PLATFORM_WEB, cfg))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status)
+	assert.False(t, resp.FromCache)
+
+	resp, err = client.Build(context.Background(), newCocosBuildRequest("cocos-2", pb.TargetPlatform_PLATFORM_WEB, cfg))
+	require.NoError(t, err)
+	assert.True(t, resp.FromCache)
+
+	resp, err = client.Build(context.Background(), newCocosBuildRequest("cocos-3", pb.TargetPlatform_PLATFORM_ANDROID, cfg))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_FAILED, resp.Status)
+	assert.Contains(t, resp.Stderr, "no worker available")
+	assert.Equal(t, int64(1), atomic.LoadInt64(&buildCalls))
+}
+
+func TestWorkerSupportsCocosConfig(t *testing.T) {
+	worker := &registry.WorkerInfo{
+		ID: "cocos",
+		Capabilities: &pb.WorkerCapabilities{
+			Cocos: &pb.CocosCapability{
+				Versions:  []string{"3.8.2", "2.4.13"},
+				Platforms: []pb.TargetPlatform{pb.TargetPlatform_PLATFORM_WEB, pb.TargetPlatform_PLATFORM_ANDROID},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		platform pb.TargetPlatform
+		version  string
+		want     bool
+	}{
+		{name: "any version", platform: pb.TargetPlatform_PLATFORM_WEB, want: true},
+		{name: "minor", platform: pb.TargetPlatform_PLATFORM_ANDROID, version: "3.8", want: true},
+		{name: "exact", platform: pb.TargetPlatform_PLATFORM_WEB, version: "2.4.13", want: true},
+		{name: "missing version", platform: pb.TargetPlatform_PLATFORM_WEB, version: "3.7", want: false},
+		{name: "missing platform", platform: pb.TargetPlatform_PLATFORM_IOS, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newCocosBuildRequest("t", tt.platform, &pb.CocosConfig{CocosVersion: tt.version})
+			assert.Equal(t, tt.want, workerSupportsCocosConfig(worker, req))
+		})
+	}
+
+	assert.False(t, workerSupportsCocosConfig(&registry.WorkerInfo{Capabilities: &pb.WorkerCapabilities{}}, newCocosBuildRequest("t", pb.TargetPlatform_PLATFORM_WEB, nil)))
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/auth"
+	hgtls "github.com/h3nr1-d14z/hybridgrid/internal/security/tls"
+)
+
+// enroller signs worker certificates in exchange for join tokens.
+type enroller struct {
+	ca     *hgtls.CA
+	tokens *auth.JoinTokens
+}
+
+// newEnroller loads the CA and join tokens of the certificate directory
+// dir, as created by `hg-coord certs init`.
+func newEnroller(dir string) (*enroller, error) {
+	ca, err := hgtls.LoadCA(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &enroller{
+		ca:     ca,
+		tokens: auth.NewJoinTokens(filepath.Join(dir, hgtls.JoinTokensFile)),
+	}, nil
+}
+
+// Enroll exchanges a one-time join token for a certificate signed by the
+// coordinator's CA, valid for serving and as an mTLS client. The token, not
+// the caller, decides the certificate's name and IP addresses, and the name
+// lies in hgtls.EnrolledWorkerDomain.
+func (s *Server) Enroll(ctx context.Context, req *pb.EnrollRequest) (*pb.EnrollResponse, error) {
+	if s.enroll == nil {
+		return nil, status.Error(codes.FailedPrecondition, "enrolment is not enabled on this coordinator")
+	}
+
+	// Reject malformed requests before they use up the join token
+	if err := hgtls.CheckCSR(req.CsrPem); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid certificate request: %v", err)
+	}
+	for _, ip := range req.IpAddresses {
+		if net.ParseIP(ip) == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid IP address %q", ip)
+		}
+	}
+
+	entry, err := s.enroll.tokens.Redeem(req.JoinToken)
+	if errors.Is(err, auth.ErrInvalidJoinToken) {
+		log.Warn().Str("name", req.Name).Msg("Enrolment rejected: invalid join token")
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to redeem join token: %v", err)
+	}
+
+	// Tokens created before names were required could enroll any name
+	if entry.Name == "" {
+		log.Warn().Str("name", req.Name).Msg("Enrolment rejected: join token not bound to a worker")
+		return nil, status.Error(codes.PermissionDenied, "join token is not bound to a worker name; create a new one with --worker")
+	}
+	name, err := hgtls.EnrolledWorkerName(entry.Name)
+	if err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "join token: %v", err)
+	}
+	for _, ip := range req.IpAddresses {
+		if !containsIP(entry.IPAddresses, ip) {
+			log.Warn().Str("name", name).Str("ip", ip).Msg("Enrolment rejected: IP address not in join token")
+			return nil, status.Errorf(codes.PermissionDenied, "join token does not allow IP address %s", ip)
+		}
+	}
+
+	certPEM, err := s.enroll.ca.SignCSR(req.CsrPem, name, hgtls.UsageServer|hgtls.UsageClient, entry.IPAddresses, 0)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sign certificate: %v", err)
+	}
+
+	log.Info().
+		Str("name", name).
+		Strs("ip_addresses", entry.IPAddresses).
+		Msg("Worker enrolled")
+
+	return &pb.EnrollResponse{
+		CertificatePem: certPEM,
+		CaPem:          s.enroll.ca.CertPEM(),
+		Name:           name,
+	}, nil
+}
+
+// containsIP reports whether ips holds ip, comparing parsed addresses so
+// that different spellings of one IPv6 address match.
+func containsIP(ips []string, ip string) bool {
+	want := net.ParseIP(ip)
+	for _, candidate := range ips {
+		if got := net.ParseIP(candidate); got != nil && got.Equal(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// serverTLSOptions returns the TLS server options for Start. With mTLS and
+// enrolment both enabled, client certificates are verified when given but
+// only required by requireClientCert, so a new worker can call
+// Enroll to obtain one.
+func (s *Server) serverTLSOptions() ([]grpc.ServerOption, error) {
+	if !s.config.TLS.RequireClientCert || s.enroll == nil {
+		creds, err := hgtls.ServerCredentials(s.config.TLS)
+		if err != nil || creds == nil {
+			return nil, err
+		}
+		return []grpc.ServerOption{grpc.Creds(creds)}, nil
+	}
+
+	tlsConfig, err := hgtls.LoadServerTLS(s.config.TLS)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	return []grpc.ServerOption{
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.ChainUnaryInterceptor(requireClientCertUnary),
+		grpc.ChainStreamInterceptor(requireClientCertStream),
+	}, nil
+}
+
+// requireClientCert rejects calls without a verified client certificate,
+// except Enroll.
+func requireClientCert(ctx context.Context, method string) error {
+	if method == pb.BuildService_Enroll_FullMethodName {
+		return nil
+	}
+	if _, ok := hgtls.PeerCertificate(ctx); !ok {
+		return status.Errorf(codes.Unauthenticated, "%s requires a client certificate", method)
+	}
+	return nil
+}
+
+func requireClientCertUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := requireClientCert(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func requireClientCertStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := requireClientCert(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/auth"
+	hgtls "github.com/h3nr1-d14z/hybridgrid/internal/security/tls"
+)
+
+func setupEnrollServer(t *testing.T) (*Server, *hgtls.CA, *auth.JoinTokens) {
+	t.Helper()
+	dir := t.TempDir()
+	ca, err := hgtls.InitCA(dir, "Test CA", 0)
+	require.NoError(t, err)
+
+	s := New(Config{HeartbeatTTL: 30 * time.Second, BuildCacheDir: t.TempDir(), EnrollDir: dir})
+	t.Cleanup(s.Stop)
+	require.NotNil(t, s.enroll)
+	return s, ca, auth.NewJoinTokens(filepath.Join(dir, hgtls.JoinTokensFile))
+}
+
+func enrollRequest(t *testing.T, token, name string) *pb.EnrollRequest {
+	t.Helper()
+	csrPEM, _, err := hgtls.NewCSR(name)
+	require.NoError(t, err)
+	return &pb.EnrollRequest{JoinToken: token, CsrPem: csrPEM, Name: name, IpAddresses: []string{"10.0.0.5"}}
+}
+
+func TestEnroll_IssuesCertificateOnce(t *testing.T) {
+	s, ca, tokens := setupEnrollServer(t)
+	token, _, err := tokens.Create("farm-1", []string{"10.0.0.5"}, time.Hour)
+	require.NoError(t, err)
+
+	resp, err := s.Enroll(context.Background(), enrollRequest(t, token, "farm-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "farm-1.workers.hybridgrid.internal", resp.Name)
+	assert.Equal(t, ca.CertPEM(), resp.CaPem)
+
+	block, _ := pem.Decode(resp.CertificatePem)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"farm-1.workers.hybridgrid.internal"}, cert.DNSNames)
+	require.Len(t, cert.IPAddresses, 1)
+	assert.Equal(t, "10.0.0.5", cert.IPAddresses[0].String())
+
+	// The enrolled certificate passes worker attestation for its name, and
+	// only an allow-list covering enrolled workers admits it.
+	assert.NoError(t, hgtls.NewWorkerAllowlist([]string{"*." + hgtls.EnrolledWorkerDomain}).Attest(cert, "farm-1", "worker-farm-1"))
+	assert.Error(t, hgtls.NewWorkerAllowlist([]string{"farm-*.build.example.com"}).Attest(cert, "farm-1", "worker-farm-1"))
+
+	_, err = s.Enroll(context.Background(), enrollRequest(t, token, "farm-1"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestEnroll_TokenBoundToName(t *testing.T) {
+	s, _, tokens := setupEnrollServer(t)
+	token, _, err := tokens.Create("farm-7", nil, time.Hour)
+	require.NoError(t, err)
+
+	req := enrollRequest(t, token, "coordinator.example.com")
+	req.IpAddresses = nil
+	resp, err := s.Enroll(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "farm-7.workers.hybridgrid.internal", resp.Name)
+}
+
+func TestEnroll_TokenFixesIPAddresses(t *testing.T) {
+	s, _, tokens := setupEnrollServer(t)
+	token, _, err := tokens.Create("farm-1", []string{"10.0.0.5", "10.0.0.6"}, time.Hour)
+	require.NoError(t, err)
+
+	req := enrollRequest(t, token, "farm-1")
+	req.IpAddresses = []string{"10.0.0.9"}
+	_, err = s.Enroll(context.Background(), req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	token, _, err = tokens.Create("farm-1", []string{"10.0.0.5", "10.0.0.6"}, time.Hour)
+	require.NoError(t, err)
+	req = enrollRequest(t, token, "farm-1")
+	req.IpAddresses = nil
+	resp, err := s.Enroll(context.Background(), req)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(resp.CertificatePem)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	require.Len(t, cert.IPAddresses, 2)
+	assert.Equal(t, "10.0.0.6", cert.IPAddresses[1].String())
+}
+
+func TestEnroll_RejectsUnboundToken(t *testing.T) {
+	s, _, _ := setupEnrollServer(t)
+	token, err := auth.GenerateToken()
+	require.NoError(t, err)
+
+	// A token file written before tokens had to name a worker
+	file := "tokens:\n  - hash: " + auth.HashToken(token) + "\n    expires_at: " + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(s.config.EnrollDir, hgtls.JoinTokensFile), []byte(file), 0o600))
+
+	_, err = s.Enroll(context.Background(), enrollRequest(t, token, "farm-1"))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestEnroll_InvalidRequestKeepsToken(t *testing.T) {
+	s, _, tokens := setupEnrollServer(t)
+	token, _, err := tokens.Create("farm-1", []string{"10.0.0.5"}, time.Hour)
+	require.NoError(t, err)
+
+	req := enrollRequest(t, token, "farm-1")
+	req.CsrPem = []byte("garbage")
+	_, err = s.Enroll(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	req = enrollRequest(t, token, "farm-1")
+	req.IpAddresses = []string{"not-an-ip"}
+	_, err = s.Enroll(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.Enroll(context.Background(), enrollRequest(t, token, "farm-1"))
+	assert.NoError(t, err)
+}
+
+func TestEnroll_Disabled(t *testing.T) {
+	s := New(Config{HeartbeatTTL: 30 * time.Second, BuildCacheDir: t.TempDir()})
+	defer s.Stop()
+
+	_, err := s.Enroll(context.Background(), &pb.EnrollRequest{JoinToken: "x", Name: "farm-1"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestRequireClientCert_ExemptsEnroll(t *testing.T) {
+	assert.NoError(t, requireClientCert(context.Background(), pb.BuildService_Enroll_FullMethodName))
+
+	err := requireClientCert(context.Background(), pb.BuildService_Handshake_FullMethodName)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	cert := &x509.Certificate{}
+	assert.NoError(t, requireClientCert(peerWithCert(cert), pb.BuildService_Handshake_FullMethodName))
+}
@@ -2,8 +2,8 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"math"
 	"net"
 	"sync"
@@ -19,12 +19,16 @@ import (
 
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
 	"github.com/h3nr1-d14z/hybridgrid/internal/cache"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/ha"
 	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
 	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/resilience"
 	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/scheduler"
+	"github.com/h3nr1-d14z/hybridgrid/internal/grpc/cancellation"
 	"github.com/h3nr1-d14z/hybridgrid/internal/grpc/interceptors"
+	"github.com/h3nr1-d14z/hybridgrid/internal/grpc/tasklog"
 	"github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
 	"github.com/h3nr1-d14z/hybridgrid/internal/observability/tracing"
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/auth"
 	hgtls "github.com/h3nr1-d14z/hybridgrid/internal/security/tls"
 )
 
@@ -84,11 +88,25 @@ func (p *connPool) closeAll() {
 
 // Config holds the coordinator gRPC server configuration.
 type Config struct {
-	Port            int
-	AuthToken       string
-	HeartbeatTTL    time.Duration
-	RequestTimeout  time.Duration
-	TLS             hgtls.Config
+	Port      int
+	AuthToken string
+	// TokensFile lists named tokens with scopes, stored as hashes. When
+	// set, every RPC except HealthCheck requires a token with the scope
+	// of the method, and the file is re-read when it changes. AuthToken,
+	// if also set, keeps working as an admin token.
+	TokensFile     string
+	HeartbeatTTL   time.Duration
+	RequestTimeout time.Duration
+	TLS            hgtls.Config
+	// WorkerCertAllowlist, when non-empty, requires workers to present an
+	// mTLS client certificate whose DNS SAN or CN matches one of these
+	// glob patterns, and whose names match the hostname and worker_id
+	// reported at Handshake. It needs TLS.RequireClientCert.
+	WorkerCertAllowlist []string
+	// EnrollDir is a certificate directory created by `hg-coord certs
+	// init`. When set, Enroll exchanges the one-time join tokens recorded
+	// there for worker certificates signed by its CA.
+	EnrollDir       string
 	Tracing         tracing.Config
 	EnableRequestID bool
 	// SchedulerType selects the scheduler implementation.
@@ -101,9 +119,45 @@ type Config struct {
 	// Theoretical form is 1 + sqrt(ln(2/δ)/2); we default to 1.0 and
 	// expect empirical tuning. Ignored for non-LinUCB schedulers.
 	AlphaValue float64
+	// SchedulerStateFile keeps what a learning scheduler learned about
+	// each worker across restarts. Ignored for non-learning schedulers.
+	// Empty starts the learner cold every time.
+	SchedulerStateFile string
 	// TaskLogPath is the path to the JSON Lines per-task log file.
 	// Empty or "stdout" routes records to standard output.
 	TaskLogPath string
+	// ArtifactDir holds the artifacts of builds submitted with
+	// defer_artifacts until FetchArtifacts downloads them. Empty uses a
+	// directory under os.TempDir().
+	ArtifactDir string
+	// ArtifactRetention is how long deferred artifacts stay available.
+	// Zero means one hour.
+	ArtifactRetention time.Duration
+	// BuildCacheDir holds cached Flutter, Unity and other build results so
+	// they survive restarts. Empty uses a directory under os.TempDir().
+	BuildCacheDir string
+	// BuildCacheMaxSizeMB bounds the build result cache; least recently
+	// used results are evicted beyond it. Zero means 10 GiB.
+	BuildCacheMaxSizeMB int64
+	// BuildCacheTTLHours is how long a cached build result stays valid.
+	// Zero means seven days.
+	BuildCacheTTLHours int
+	// MaxQueueDepth bounds how many requests may wait for a free worker;
+	// requests beyond it fail immediately. Zero means 1000.
+	MaxQueueDepth int
+	// QueueTimeout is how long a request may wait for a free worker.
+	// Zero means two minutes.
+	QueueTimeout time.Duration
+	// RegistryFile keeps registered workers and their task statistics
+	// across restarts. Restored workers are not scheduled until they
+	// heartbeat. Empty keeps workers in memory only.
+	RegistryFile string
+	// HA elects one leader among coordinators sharing HA.LeaseFile and
+	// RegistryFile. Standbys reject every call with Unavailable.
+	HA ha.Config
+	// StaticWorkers are registered by the coordinator, which probes them
+	// instead of waiting for them to handshake.
+	StaticWorkers []StaticWorker
 }
 
 // DefaultConfig returns sensible defaults.
@@ -117,9 +171,23 @@ func DefaultConfig() Config {
 	}
 }
 
-// newScheduler constructs a scheduler.Scheduler from the configured type.
+// newRegistry constructs the worker registry, persistent if
+// cfg.RegistryFile is set.
+func newRegistry(cfg Config) registry.Registry {
+	if cfg.RegistryFile == "" {
+		return registry.NewInMemoryRegistry(cfg.HeartbeatTTL)
+	}
+	reg, err := registry.NewPersistentRegistry(cfg.HeartbeatTTL, registry.NewFileStore(cfg.RegistryFile), registry.DefaultFlushInterval)
+	if err != nil {
+		log.Warn().Err(err).Str("path", cfg.RegistryFile).Msg("Failed to load worker registry; keeping workers in memory only")
+		return registry.NewInMemoryRegistry(cfg.HeartbeatTTL)
+	}
+	return reg
+}
+
+// NewScheduler constructs a scheduler.Scheduler from the configured type.
 // Unknown types fall back to LeastLoaded for backward compatibility.
-func newScheduler(cfg Config, reg registry.Registry, cm *resilience.CircuitManager) scheduler.Scheduler {
+func NewScheduler(cfg Config, reg registry.Registry, cm *resilience.CircuitManager) scheduler.Scheduler {
 	switch cfg.SchedulerType {
 	case "simple":
 		return scheduler.NewSimpleScheduler(reg)
@@ -157,12 +225,30 @@ func newScheduler(cfg Config, reg registry.Registry, cm *resilience.CircuitManag
 	}
 }
 
+// newCheckpointer restores the learned state of sched from
+// cfg.SchedulerStateFile and keeps saving it. It returns nil when sched
+// does not learn or no file is set.
+func newCheckpointer(cfg Config, sched scheduler.Scheduler, reg registry.Registry) *scheduler.Checkpointer {
+	learner, ok := sched.(scheduler.LearningScheduler)
+	if !ok || cfg.SchedulerStateFile == "" {
+		return nil
+	}
+	c := scheduler.NewCheckpointer(cfg.SchedulerStateFile, cfg.SchedulerType, learner, reg)
+	if err := c.Restore(); err != nil {
+		log.Warn().Err(err).Str("path", cfg.SchedulerStateFile).Msg("Failed to restore learned scheduler state")
+	}
+	c.Start(scheduler.DefaultCheckpointInterval)
+	return c
+}
+
 // TaskEvent represents a task event for the dashboard.
 type TaskEvent struct {
 	ID           string
 	BuildType    string
 	Status       string
 	WorkerID     string
+	Client       string
+	Project      string
 	StartedAt    int64
 	CompletedAt  int64
 	DurationMs   int64
@@ -185,13 +271,24 @@ type Server struct {
 	server         *grpc.Server
 	registry       registry.Registry
 	scheduler      scheduler.Scheduler
+	checkpoint     *scheduler.Checkpointer
 	circuitManager *resilience.CircuitManager
 	eventNotifier  EventNotifier
 	workerConns    *connPool
 	taskLogger     *TaskLogger
+	artifacts      *artifactSpool
+	queue          *dispatchQueue
+	running        *cancellation.Registry
+	taskOutput     *tasklog.Broker
+	stopTokens     context.CancelFunc
+	authn          atomic.Pointer[auth.Interceptor] // Resolves dashboard tokens; nil until Start
+	workerAllow    *hgtls.WorkerAllowlist
+	enroll         *enroller
+	election       *election
+	static         *staticWorkers
+	buildGraphs    *buildGraphs
 
 	activeTasks         int64
-	queuedTasks         int64
 	totalTasks          int64
 	successTasks        int64
 	failedTasks         int64
@@ -200,37 +297,18 @@ type Server struct {
 	flutterBuilds       int64
 	flutterCacheHits    int64
 	flutterCacheMisses  int64
-	flutterCacheMu      sync.RWMutex
-	flutterCache        map[string]*flutterCacheEntry
 	unityBuilds         int64
 	unityCacheHits      int64
 	unityCacheMisses    int64
-	unityCacheMu        sync.RWMutex
-	unityCache          map[string]*unityCacheEntry
+	buildCache          *buildResultCache
 	activeTasksByWorker sync.Map
 }
 
-type flutterCacheEntry struct {
-	artifacts    []byte
-	artifactList []*pb.ArtifactInfo
-	stdout       string
-	stderr       string
-	buildTimeMs  int64
-}
-
-type unityCacheEntry struct {
-	artifacts    []byte
-	artifactList []*pb.ArtifactInfo
-	stdout       string
-	stderr       string
-	buildTimeMs  int64
-}
-
 // New creates a new coordinator gRPC server.
 func New(cfg Config) *Server {
-	reg := registry.NewInMemoryRegistry(cfg.HeartbeatTTL)
+	reg := newRegistry(cfg)
 	circuitMgr := resilience.NewCircuitManager(resilience.DefaultCircuitConfig())
-	sched := newScheduler(cfg, reg, circuitMgr)
+	sched := NewScheduler(cfg, reg, circuitMgr)
 	log.Info().Str("scheduler", cfg.SchedulerType).Msg("Scheduler initialized")
 
 	taskLogger, err := NewTaskLogger(cfg.TaskLogPath)
@@ -273,16 +351,48 @@ func New(cfg Config) *Server {
 		dialOpts = append(dialOpts, tracing.DialOptions()...)
 	}
 
-	return &Server{
+	var workerAllow *hgtls.WorkerAllowlist
+	if len(cfg.WorkerCertAllowlist) > 0 {
+		workerAllow = hgtls.NewWorkerAllowlist(cfg.WorkerCertAllowlist)
+	}
+
+	var enroll *enroller
+	if cfg.EnrollDir != "" {
+		enroll, err = newEnroller(cfg.EnrollDir)
+		if err != nil {
+			log.Warn().Err(err).Str("dir", cfg.EnrollDir).Msg("Failed to load enrolment CA; enrolment disabled")
+		}
+	}
+
+	s := &Server{
 		config:         cfg,
 		registry:       reg,
 		scheduler:      sched,
+		checkpoint:     newCheckpointer(cfg, sched, reg),
 		circuitManager: circuitMgr,
 		workerConns:    newConnPool(dialOpts),
 		taskLogger:     taskLogger,
-		flutterCache:   make(map[string]*flutterCacheEntry),
-		unityCache:     make(map[string]*unityCacheEntry),
+		artifacts:      newArtifactSpool(cfg.ArtifactDir, cfg.ArtifactRetention),
+		buildCache:     newBuildResultCache(cfg.BuildCacheDir, cfg.BuildCacheMaxSizeMB, cfg.BuildCacheTTLHours),
+		running:        cancellation.NewRegistry(),
+		taskOutput:     tasklog.NewBroker(),
+		workerAllow:    workerAllow,
+		enroll:         enroll,
+		buildGraphs:    newBuildGraphs(),
+		queue: newDispatchQueue(cfg.MaxQueueDepth, cfg.QueueTimeout, func(w *registry.WorkerInfo) {
+			reg.IncrementTasks(w.ID)
+		}, func() bool {
+			for _, w := range reg.List() {
+				if w.IsHealthy(cfg.HeartbeatTTL) && workerHasFreeSlot(w) {
+					return true
+				}
+			}
+			return false
+		}),
 	}
+	s.startElection()
+	s.startStaticWorkers()
+	return s
 }
 
 // Start starts the gRPC server.
@@ -296,14 +406,15 @@ func (s *Server) Start() error {
 
 	// Add TLS credentials if configured
 	if s.config.TLS.Enabled {
-		creds, err := hgtls.ServerCredentials(s.config.TLS)
+		tlsOpts, err := s.serverTLSOptions()
 		if err != nil {
 			return fmt.Errorf("failed to load TLS credentials: %w", err)
 		}
-		if creds != nil {
-			opts = append(opts, grpc.Creds(creds))
+		if tlsOpts != nil {
+			opts = append(opts, tlsOpts...)
 			log.Info().
 				Bool("mtls", s.config.TLS.RequireClientCert).
+				Bool("enrolment", s.enroll != nil).
 				Str("min_version", s.config.TLS.MinVersionName()).
 				Msg("TLS enabled for coordinator gRPC server")
 		}
@@ -318,12 +429,36 @@ func (s *Server) Start() error {
 	// Add request ID interceptors if enabled
 	if s.config.EnableRequestID {
 		opts = append(opts,
-			grpc.UnaryInterceptor(interceptors.UnaryRequestIDInterceptor()),
-			grpc.StreamInterceptor(interceptors.StreamRequestIDInterceptor()),
+			grpc.ChainUnaryInterceptor(interceptors.UnaryRequestIDInterceptor()),
+			grpc.ChainStreamInterceptor(interceptors.StreamRequestIDInterceptor()),
 		)
 		log.Info().Msg("Request ID interceptor enabled for coordinator gRPC server")
 	}
 
+	// Add token authentication if a tokens file is configured
+	tokensCtx, stopTokens := context.WithCancel(context.Background())
+	authInterceptor, err := newAuthInterceptor(tokensCtx, s.config)
+	if err != nil {
+		stopTokens()
+		lis.Close()
+		return fmt.Errorf("failed to load tokens: %w", err)
+	}
+	s.stopTokens = stopTokens
+	if authInterceptor != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(authInterceptor.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(authInterceptor.StreamServerInterceptor()),
+		)
+		s.authn.Store(authInterceptor)
+	} else if s.config.AuthToken != "" {
+		// Handshake checks the shared token itself; the dashboard still
+		// needs to resolve it.
+		s.authn.Store(auth.NewInterceptor(auth.Config{Token: s.config.AuthToken}))
+	}
+
+	// Standby coordinators send clients on to the leader
+	opts = append(opts, s.leaderOptions()...)
+
 	s.server = grpc.NewServer(opts...)
 	pb.RegisterBuildServiceServer(s.server, s)
 
@@ -336,15 +471,29 @@ func (s *Server) Stop() {
 	if s.server != nil {
 		s.server.GracefulStop()
 	}
+	if s.stopTokens != nil {
+		s.stopTokens()
+	}
+	s.stopStaticWorkers()
+	if s.queue != nil {
+		s.queue.close()
+	}
 	if s.workerConns != nil {
 		s.workerConns.closeAll()
 	}
-	if reg, ok := s.registry.(*registry.InMemoryRegistry); ok {
+	if s.checkpoint != nil {
+		s.checkpoint.Stop()
+	}
+	if reg, ok := s.registry.(interface{ Stop() }); ok {
 		reg.Stop()
 	}
+	s.stopElection()
 	if s.taskLogger != nil {
 		_ = s.taskLogger.Close()
 	}
+	if s.artifacts != nil {
+		s.artifacts.close()
+	}
 }
 
 // Registry returns the worker registry.
@@ -363,15 +512,39 @@ func (s *Server) Handshake(ctx context.Context, req *pb.HandshakeRequest) (*pb.H
 		return nil, status.Error(codes.InvalidArgument, "capabilities required")
 	}
 
-	// Validate auth token
-	if s.config.AuthToken != "" && req.AuthToken != s.config.AuthToken {
+	// Validate auth token, unless the auth interceptor already did
+	if _, ok := auth.FromContext(ctx); !ok && s.config.AuthToken != "" && req.AuthToken != s.config.AuthToken {
 		log.Warn().Str("hostname", req.Capabilities.Hostname).Msg("Worker rejected: invalid auth token")
+		metrics.Default().RecordHandshakeRejected("invalid_token")
 		return &pb.HandshakeResponse{
 			Accepted: false,
 			Message:  "invalid auth token",
 		}, nil
 	}
 
+	// Check the worker's certificate against the allow-list
+	if s.workerAllow != nil {
+		cert, _ := hgtls.PeerCertificate(ctx)
+		if err := s.workerAllow.Attest(cert, req.Capabilities.Hostname, req.Capabilities.WorkerId); err != nil {
+			reason := hgtls.RejectNotAllowed
+			var attestErr *hgtls.AttestationError
+			if errors.As(err, &attestErr) {
+				reason = attestErr.Reason
+			}
+			log.Warn().
+				Err(err).
+				Str("hostname", req.Capabilities.Hostname).
+				Str("worker_id", req.Capabilities.WorkerId).
+				Str("reason", reason).
+				Msg("Worker rejected: certificate attestation failed")
+			metrics.Default().RecordHandshakeRejected(reason)
+			return &pb.HandshakeResponse{
+				Accepted: false,
+				Message:  "worker attestation failed: " + err.Error(),
+			}, nil
+		}
+	}
+
 	// Generate worker ID
 	workerID := req.Capabilities.WorkerId
 	if workerID == "" {
@@ -405,15 +578,20 @@ func (s *Server) Handshake(ctx context.Context, req *pb.HandshakeRequest) (*pb.H
 		}
 	}
 
+	// Queued tasks may be waiting for exactly this worker.
+	s.queue.dispatch()
+
 	// Log C++ capabilities for debugging
 	var compilers []string
 	if req.Capabilities.Cpp != nil {
 		compilers = req.Capabilities.Cpp.Compilers
 	}
 
+	registeredBy, _ := taskOwner(ctx)
 	log.Info().
 		Str("worker_id", workerID).
 		Str("hostname", req.Capabilities.Hostname).
+		Str("token", registeredBy).
 		Int32("cpu_cores", req.Capabilities.CpuCores).
 		Int32("max_parallel", maxParallel).
 		Str("arch", req.Capabilities.NativeArch.String()).
@@ -431,6 +609,18 @@ func (s *Server) Handshake(ctx context.Context, req *pb.HandshakeRequest) (*pb.H
 
 // Compile handles compilation requests by forwarding to workers.
 func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.CompileResponse, error) {
+	owner, _ := taskOwner(ctx)
+	ctx, release := s.running.Track(ctx, req.TaskId, owner)
+	defer release()
+
+	resp, err := s.compile(ctx, req)
+	if resp != nil && resp.Status == pb.TaskStatus_STATUS_FAILED && ctx.Err() == context.Canceled {
+		resp.Status = pb.TaskStatus_STATUS_CANCELLED
+	}
+	return resp, err
+}
+
+func (s *Server) compile(ctx context.Context, req *pb.CompileRequest) (*pb.CompileResponse, error) {
 	start := time.Now()
 
 	// Start tracing span for the coordinator compile flow
@@ -444,11 +634,38 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 		return nil, status.Error(codes.InvalidArgument, "task_id required")
 	}
 
-	// Track cache miss (client checked cache first, this is a miss)
-	atomic.AddInt64(&s.cacheMisses, 1)
+	// The client already missed its local cache; check the shared object
+	// cache so a compile done by any other client is reused.
+	cacheKey := compileCacheKey(req)
+	if cached := s.buildCache.getObject(cacheKey); cached != nil {
+		atomic.AddInt64(&s.cacheHits, 1)
+		atomic.AddInt64(&s.totalTasks, 1)
+		atomic.AddInt64(&s.successTasks, 1)
+
+		taskStart := start.Unix()
+		s.notifyTaskStarted(ctx, &TaskEvent{
+			ID:        req.TaskId,
+			BuildType: "cpp",
+			Status:    "running",
+			StartedAt: taskStart,
+		})
+		s.notifyTaskCompleted(ctx, &TaskEvent{
+			ID:          req.TaskId,
+			BuildType:   "cpp",
+			Status:      "completed",
+			StartedAt:   taskStart,
+			CompletedAt: time.Now().Unix(),
+			DurationMs:  cached.CompilationTimeMs,
+			FromCache:   true,
+		})
 
-	atomic.AddInt64(&s.queuedTasks, 1)
-	defer atomic.AddInt64(&s.queuedTasks, -1)
+		span.SetAttributes(tracing.AttrCacheHit.Bool(true))
+		span.SetStatus(otelcodes.Ok, "served from shared cache")
+
+		cached.FromCache = true
+		return cached, nil
+	}
+	atomic.AddInt64(&s.cacheMisses, 1)
 
 	// Determine OS filtering strategy:
 	// - Raw source mode: no OS filter needed, workers with Docker can cross-compile
@@ -459,13 +676,42 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 		clientOSFilter = req.ClientOs
 	}
 
-	// Select worker with tracing
+	// Select worker with tracing. Links bypass the scheduler: they can only
+	// run on workers matching the client's OS, architecture and toolchain.
 	tracing.AddEvent(ctx, "scheduler.select.start")
+	sourceSize := len(req.PreprocessedSource) + len(req.RawSource)
+	for _, data := range req.LinkInputs {
+		sourceSize += len(data)
+	}
 	taskCtx := scheduler.TaskContext{
-		SourceSizeBytes: len(req.PreprocessedSource) + len(req.RawSource),
+		SourceSizeBytes: sourceSize,
 		TaskID:          req.TaskId,
+		UpwardRank:      s.buildGraphs.rank(queueClientKey(ctx), req.BuildId, req.SourcePath),
+		BuildType:       pb.BuildType_BUILD_TYPE_CPP,
 	}
-	worker, dispatchInfo, err := scheduler.SelectWith(s.scheduler, pb.BuildType_BUILD_TYPE_CPP, req.TargetArch, clientOSFilter, taskCtx)
+	var dispatchInfo scheduler.DispatchInfo
+	pick := func() (*registry.WorkerInfo, error) {
+		if req.Link {
+			return s.selectLinkWorker(req)
+		}
+		// Keep the task queued while workers that could run it exist but
+		// are all at capacity; fail straight away when there are none.
+		matching := scheduler.FilterByOS(s.registry.ListByCapability(pb.BuildType_BUILD_TYPE_CPP, req.TargetArch), clientOSFilter)
+		if len(matching) > 0 {
+			if _, err := pickLeastLoaded(matching); err != nil {
+				return nil, err
+			}
+		}
+		w, info, err := scheduler.SelectWith(s.scheduler, pb.BuildType_BUILD_TYPE_CPP, req.TargetArch, clientOSFilter, taskCtx)
+		if len(matching) > 0 && errors.Is(err, scheduler.ErrNoMatchingWorkers) {
+			// The scheduler also skips workers whose circuit is open;
+			// wait for them to recover rather than failing the build.
+			return nil, errWorkersBusy
+		}
+		dispatchInfo = info
+		return w, err
+	}
+	worker, queueTime, err := s.acquireWorker(ctx, "cpp", 0, taskCtx.UpwardRank, pick)
 	if err != nil {
 		span.SetStatus(otelcodes.Error, "no worker available")
 		tracing.RecordError(ctx, err)
@@ -483,14 +729,12 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 	tracing.AddEvent(ctx, "scheduler.select.done")
 	span.SetAttributes(tracing.AttrWorkerID.String(worker.ID))
 
-	// Snapshot dispatch-time worker state for offline analysis. Captured
-	// before IncrementTasks so the value reflects load at the scheduling
-	// decision, not after this task has been booked. The read races with
-	// concurrent dispatches but the log is for offline analysis only.
+	// Snapshot dispatch-time worker state for offline analysis. The
+	// worker was listed before the queue booked this task, so the value
+	// reflects load at the scheduling decision.
 	activeAtDispatch := worker.ActiveTasks
 
 	// Track task
-	s.registry.IncrementTasks(worker.ID)
 	atomic.AddInt64(&s.activeTasks, 1)
 	atomic.AddInt64(&s.totalTasks, 1)
 
@@ -507,7 +751,6 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 		}
 	}()
 
-	queueTime := time.Since(start)
 	taskStartTime := time.Now()
 	span.SetAttributes(tracing.AttrQueueTimeMs.Int64(queueTime.Milliseconds()))
 
@@ -518,19 +761,19 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 		Msg("Forwarding compile request")
 
 	// Notify task started
-	if s.eventNotifier != nil {
-		s.eventNotifier.NotifyTaskStarted(&TaskEvent{
-			ID:        req.TaskId,
-			BuildType: "cpp",
-			Status:    "running",
-			WorkerID:  worker.ID,
-			StartedAt: taskStartTime.Unix(),
-		})
-	}
+	s.notifyTaskStarted(ctx, &TaskEvent{
+		ID:        req.TaskId,
+		BuildType: "cpp",
+		Status:    "running",
+		WorkerID:  worker.ID,
+		StartedAt: taskStartTime.Unix(),
+	})
 
 	uploadBytes := len(req.PreprocessedSource)
 	if len(req.RawSource) > 0 {
 		uploadBytes = len(req.RawSource)
+	} else if req.Link {
+		uploadBytes = sourceSize
 	}
 	m.RecordTransfer("upload", float64(uploadBytes))
 
@@ -551,28 +794,17 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 	if resp != nil {
 		compileTime = time.Duration(resp.CompilationTimeMs) * time.Millisecond
 	}
-	s.registry.DecrementTasks(worker.ID, success, compileTime)
-
-	// Feedback loop for online-learning schedulers. Reward convention:
-	// higher is better. We use a normalised negative log-latency so the
-	// reward magnitude does not dwarf LinUCB's UCB exploration bonus
-	// during warm-up (code-review finding HIGH-2). Normalisation divisor
-	// is log1p(RequestTimeoutMs) so the reward lies in roughly [-1, 0].
-	// The log transform compresses the heavy tail (M1 P99/P50 ≈ 29×);
-	// see docs/thesis/theory-notes.md §4.3 for the empirical-choice
-	// rationale (this is NOT the Decima reward function).
-	if learner, ok := s.scheduler.(scheduler.LearningScheduler); ok {
-		timeoutMs := float64(s.config.RequestTimeout.Milliseconds())
-		if timeoutMs <= 0 {
-			timeoutMs = 120000 // 2-minute fallback
-		}
-		denom := math.Log1p(timeoutMs)
-		var reward float64
-		if success && resp != nil {
-			reward = -math.Log1p(float64(resp.CompilationTimeMs)) / denom
-		} else {
-			reward = -1.0
+	s.releaseWorker(worker.ID, success, compileTime)
+
+	// Feedback loop for online-learning schedulers. The estimated network
+	// cost is added to the compile time so the learners see what a WAN
+	// worker's round trip and upload cost.
+	if learner, ok := s.scheduler.(scheduler.LearningScheduler); ok && !req.Link {
+		var latencyMs float64
+		if resp != nil {
+			latencyMs = float64(resp.CompilationTimeMs) + scheduler.NetworkCostMs(worker, uploadBytes)
 		}
+		reward := scheduler.LatencyReward(latencyMs, success && resp != nil, s.config.RequestTimeout)
 		learner.RecordOutcome(worker.ID, reward, success, taskCtx)
 	}
 
@@ -582,6 +814,7 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 
 	// Per-task structured log for offline analysis / RL training.
 	if s.taskLogger != nil {
+		client, project := taskOwner(ctx)
 		var (
 			workerCPUCores    int32
 			workerMemBytes    int64
@@ -616,9 +849,12 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 			WorkerDiscoverySource:       worker.DiscoverySource,
 			TargetArch:                  req.TargetArch.String(),
 			ClientOS:                    req.ClientOs,
-			SourceSizeBytes:             len(req.PreprocessedSource) + len(req.RawSource),
+			Client:                      client,
+			Project:                     project,
+			SourceSizeBytes:             sourceSize,
 			PreprocessedSizeBytes:       len(req.PreprocessedSource),
 			RawSourceSizeBytes:          len(req.RawSource),
+			UpwardRank:                  taskCtx.UpwardRank,
 			QueueTimeMs:                 queueTime.Milliseconds(),
 			CompileTimeMs:               compileTimeMs,
 			WorkerRPCLatencyMs:          workerLatency.Milliseconds(),
@@ -634,38 +870,39 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 	if success {
 		atomic.AddInt64(&s.successTasks, 1)
 		span.SetStatus(otelcodes.Ok, "compilation succeeded")
+		if resp.ExitCode == 0 && len(resp.ObjectFile) > 0 {
+			s.buildCache.putObject(cacheKey, resp)
+		}
 	} else {
 		atomic.AddInt64(&s.failedTasks, 1)
 		span.SetStatus(otelcodes.Error, "compilation failed")
 	}
 
 	// Notify task completed
-	if s.eventNotifier != nil {
-		event := &TaskEvent{
-			ID:          req.TaskId,
-			BuildType:   "cpp",
-			WorkerID:    worker.ID,
-			StartedAt:   taskStartTime.Unix(),
-			CompletedAt: taskCompletedTime.Unix(),
-			DurationMs:  taskCompletedTime.Sub(taskStartTime).Milliseconds(),
+	event := &TaskEvent{
+		ID:          req.TaskId,
+		BuildType:   "cpp",
+		WorkerID:    worker.ID,
+		StartedAt:   taskStartTime.Unix(),
+		CompletedAt: taskCompletedTime.Unix(),
+		DurationMs:  taskCompletedTime.Sub(taskStartTime).Milliseconds(),
+	}
+	if success {
+		event.Status = "completed"
+		if resp != nil {
+			event.ExitCode = resp.ExitCode
 		}
-		if success {
-			event.Status = "completed"
-			if resp != nil {
-				event.ExitCode = resp.ExitCode
-			}
-		} else {
-			event.Status = "failed"
-			event.ExitCode = 1
-			if err != nil {
-				event.ErrorMessage = err.Error()
-			} else if resp != nil {
-				event.ExitCode = resp.ExitCode
-				event.ErrorMessage = resp.Stderr
-			}
+	} else {
+		event.Status = "failed"
+		event.ExitCode = 1
+		if err != nil {
+			event.ErrorMessage = err.Error()
+		} else if resp != nil {
+			event.ExitCode = resp.ExitCode
+			event.ErrorMessage = resp.Stderr
 		}
-		s.eventNotifier.NotifyTaskCompleted(event)
 	}
+	s.notifyTaskCompleted(ctx, event)
 
 	if err != nil {
 		tracing.RecordError(ctx, err)
@@ -687,7 +924,6 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 	} else {
 		m.RecordTaskComplete(metrics.TaskStatusError, buildType, worker.ID, duration)
 	}
-	m.RecordQueueTime(buildType, queueTime.Seconds())
 
 	return resp, nil
 }
@@ -715,22 +951,30 @@ func (s *Server) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResp
 		return nil, status.Error(codes.InvalidArgument, "task_id required")
 	}
 
-	if req.GetFlutterConfig() != nil {
-		return s.handleFlutterBuild(ctx, req)
-	}
+	return s.dispatchBuild(ctx, req, forwardBuildRequest(req))
+}
 
-	if req.GetUnityConfig() != nil {
-		return s.handleUnityBuild(ctx, req)
+// buildForwarder sends a build to the selected worker. The unary Build path
+// resends the in-memory request; StreamBuild relays the client's chunks.
+type buildForwarder func(ctx context.Context, client pb.BuildServiceClient) (*pb.BuildResponse, error)
+
+// forwardBuildRequest returns a forwarder that sends req to the worker's
+// unary Build RPC.
+func forwardBuildRequest(req *pb.BuildRequest) buildForwarder {
+	return func(ctx context.Context, client pb.BuildServiceClient) (*pb.BuildResponse, error) {
+		return client.Build(ctx, &pb.BuildRequest{
+			TaskId:         req.TaskId,
+			SourceHash:     req.SourceHash,
+			SourceArchive:  req.SourceArchive,
+			BuildType:      req.BuildType,
+			TargetPlatform: req.TargetPlatform,
+			Config:         req.Config,
+			TimeoutSeconds: req.TimeoutSeconds,
+		})
 	}
-
-	return &pb.BuildResponse{
-		Status:   pb.TaskStatus_STATUS_FAILED,
-		ExitCode: 1,
-		Stderr:   "build type not implemented yet",
-	}, nil
 }
 
-func (s *Server) handleFlutterBuild(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+func (s *Server) handleFlutterBuild(ctx context.Context, req *pb.BuildRequest, forward buildForwarder) (*pb.BuildResponse, error) {
 	start := time.Now()
 	m := metrics.Default()
 
@@ -738,55 +982,53 @@ func (s *Server) handleFlutterBuild(ctx context.Context, req *pb.BuildRequest) (
 	flutterVersion := flutterConfig.GetFlutterVersion()
 	cacheKey := cache.FlutterCacheKey(flutterConfig, req.SourceHash, flutterVersion)
 
-	if cached := s.getFlutterCache(cacheKey); cached != nil {
+	if cached := s.buildCache.get("flutter", cacheKey); cached != nil {
 		atomic.AddInt64(&s.cacheHits, 1)
 		atomic.AddInt64(&s.flutterCacheHits, 1)
 		atomic.AddInt64(&s.flutterBuilds, 1)
 		atomic.AddInt64(&s.totalTasks, 1)
 		atomic.AddInt64(&s.successTasks, 1)
 
-		if s.eventNotifier != nil {
-			taskStart := start.Unix()
-			s.eventNotifier.NotifyTaskStarted(&TaskEvent{
-				ID:        req.TaskId,
-				BuildType: "flutter",
-				Status:    "running",
-				WorkerID:  "",
-				StartedAt: taskStart,
-			})
-			s.eventNotifier.NotifyTaskCompleted(&TaskEvent{
-				ID:           req.TaskId,
-				BuildType:    "flutter",
-				Status:       "completed",
-				WorkerID:     "",
-				StartedAt:    taskStart,
-				CompletedAt:  time.Now().Unix(),
-				DurationMs:   cached.buildTimeMs,
-				ExitCode:     0,
-				FromCache:    true,
-				ErrorMessage: "",
-			})
-		}
+		taskStart := start.Unix()
+		s.notifyTaskStarted(ctx, &TaskEvent{
+			ID:        req.TaskId,
+			BuildType: "flutter",
+			Status:    "running",
+			WorkerID:  "",
+			StartedAt: taskStart,
+		})
+		s.notifyTaskCompleted(ctx, &TaskEvent{
+			ID:           req.TaskId,
+			BuildType:    "flutter",
+			Status:       "completed",
+			WorkerID:     "",
+			StartedAt:    taskStart,
+			CompletedAt:  time.Now().Unix(),
+			DurationMs:   cached.buildTimeMs,
+			ExitCode:     0,
+			FromCache:    true,
+			ErrorMessage: "",
+		})
 
-		return &pb.BuildResponse{
+		return s.deferArtifacts(ctx, req, &pb.BuildResponse{
 			Status:       pb.TaskStatus_STATUS_COMPLETED,
 			ExitCode:     0,
 			Stdout:       cached.stdout,
 			Stderr:       cached.stderr,
-			Artifacts:    append([]byte(nil), cached.artifacts...),
-			ArtifactList: cloneArtifactList(cached.artifactList),
+			Artifacts:    cached.artifacts,
+			ArtifactList: cached.artifactList,
 			BuildTimeMs:  cached.buildTimeMs,
 			FromCache:    true,
-		}, nil
+		}), nil
 	}
 
 	atomic.AddInt64(&s.cacheMisses, 1)
 	atomic.AddInt64(&s.flutterCacheMisses, 1)
 
-	atomic.AddInt64(&s.queuedTasks, 1)
-	defer atomic.AddInt64(&s.queuedTasks, -1)
-
-	worker, err := s.selectFlutterWorker(req.TargetPlatform)
+	taskCtx := scheduler.TaskContext{TaskID: req.TaskId, BuildType: pb.BuildType_BUILD_TYPE_FLUTTER}
+	worker, queueTime, err := s.acquireWorker(ctx, "flutter", req.Priority, 0, func() (*registry.WorkerInfo, error) {
+		return s.selectFlutterWorker(req.TargetPlatform, taskCtx)
+	})
 	if err != nil {
 		atomic.AddInt64(&s.totalTasks, 1)
 		atomic.AddInt64(&s.failedTasks, 1)
@@ -794,28 +1036,26 @@ func (s *Server) handleFlutterBuild(ctx context.Context, req *pb.BuildRequest) (
 			Str("target_platform", req.TargetPlatform.String()).
 			Msg("No worker available for flutter build")
 
-		if s.eventNotifier != nil {
-			taskStart := start.Unix()
-			s.eventNotifier.NotifyTaskStarted(&TaskEvent{
-				ID:        req.TaskId,
-				BuildType: "flutter",
-				Status:    "running",
-				WorkerID:  "",
-				StartedAt: taskStart,
-			})
-			s.eventNotifier.NotifyTaskCompleted(&TaskEvent{
-				ID:           req.TaskId,
-				BuildType:    "flutter",
-				Status:       "failed",
-				WorkerID:     "",
-				StartedAt:    taskStart,
-				CompletedAt:  time.Now().Unix(),
-				DurationMs:   0,
-				ExitCode:     1,
-				FromCache:    false,
-				ErrorMessage: fmt.Sprintf("no worker available: %v", err),
-			})
-		}
+		taskStart := start.Unix()
+		s.notifyTaskStarted(ctx, &TaskEvent{
+			ID:        req.TaskId,
+			BuildType: "flutter",
+			Status:    "running",
+			WorkerID:  "",
+			StartedAt: taskStart,
+		})
+		s.notifyTaskCompleted(ctx, &TaskEvent{
+			ID:           req.TaskId,
+			BuildType:    "flutter",
+			Status:       "failed",
+			WorkerID:     "",
+			StartedAt:    taskStart,
+			CompletedAt:  time.Now().Unix(),
+			DurationMs:   0,
+			ExitCode:     1,
+			FromCache:    false,
+			ErrorMessage: fmt.Sprintf("no worker available: %v", err),
+		})
 
 		return &pb.BuildResponse{
 			Status:   pb.TaskStatus_STATUS_FAILED,
@@ -826,6 +1066,7 @@ func (s *Server) handleFlutterBuild(ctx context.Context, req *pb.BuildRequest) (
 
 	conn, err := s.workerConns.get(ctx, worker.Address)
 	if err != nil {
+		s.releaseWorker(worker.ID, false, 0)
 		log.Error().Err(err).Str("worker", worker.ID).Msg("Failed to connect to worker")
 		return &pb.BuildResponse{
 			Status:   pb.TaskStatus_STATUS_FAILED,
@@ -834,7 +1075,6 @@ func (s *Server) handleFlutterBuild(ctx context.Context, req *pb.BuildRequest) (
 		}, nil
 	}
 
-	s.registry.IncrementTasks(worker.ID)
 	atomic.AddInt64(&s.activeTasks, 1)
 	atomic.AddInt64(&s.totalTasks, 1)
 	atomic.AddInt64(&s.flutterBuilds, 1)
@@ -853,30 +1093,23 @@ func (s *Server) handleFlutterBuild(ctx context.Context, req *pb.BuildRequest) (
 
 	taskStartTime := time.Now()
 
-	if s.eventNotifier != nil {
-		s.eventNotifier.NotifyTaskStarted(&TaskEvent{
-			ID:        req.TaskId,
-			BuildType: "flutter",
-			Status:    "running",
-			WorkerID:  worker.ID,
-			StartedAt: taskStartTime.Unix(),
-		})
-	}
-
-	client := pb.NewBuildServiceClient(conn)
-	buildResp, err := client.Build(ctx, &pb.BuildRequest{
-		TaskId:         req.TaskId,
-		SourceHash:     req.SourceHash,
-		SourceArchive:  req.SourceArchive,
-		BuildType:      req.BuildType,
-		TargetPlatform: req.TargetPlatform,
-		Config:         req.Config,
-		TimeoutSeconds: req.TimeoutSeconds,
+	s.notifyTaskStarted(ctx, &TaskEvent{
+		ID:        req.TaskId,
+		BuildType: "flutter",
+		Status:    "running",
+		WorkerID:  worker.ID,
+		StartedAt: taskStartTime.Unix(),
 	})
 
+	buildResp, err := s.forwardWatched(ctx, req.TaskId, pb.NewBuildServiceClient(conn), forward)
+	if buildResp != nil {
+		buildResp.QueueTimeMs = queueTime.Milliseconds()
+	}
+
 	success := err == nil && buildResp != nil && buildResp.Status == pb.TaskStatus_STATUS_COMPLETED
 
-	s.registry.DecrementTasks(worker.ID, success, time.Duration(0))
+	s.releaseWorker(worker.ID, success, 0)
+	s.recordBuildOutcome(worker.ID, buildResp, success, taskCtx)
 
 	taskCompletedTime := time.Now()
 
@@ -886,33 +1119,31 @@ func (s *Server) handleFlutterBuild(ctx context.Context, req *pb.BuildRequest) (
 		atomic.AddInt64(&s.failedTasks, 1)
 	}
 
-	if s.eventNotifier != nil {
-		event := &TaskEvent{
-			ID:          req.TaskId,
-			BuildType:   "flutter",
-			WorkerID:    worker.ID,
-			StartedAt:   taskStartTime.Unix(),
-			CompletedAt: taskCompletedTime.Unix(),
-			DurationMs:  taskCompletedTime.Sub(taskStartTime).Milliseconds(),
-			FromCache:   false,
+	event := &TaskEvent{
+		ID:          req.TaskId,
+		BuildType:   "flutter",
+		WorkerID:    worker.ID,
+		StartedAt:   taskStartTime.Unix(),
+		CompletedAt: taskCompletedTime.Unix(),
+		DurationMs:  taskCompletedTime.Sub(taskStartTime).Milliseconds(),
+		FromCache:   false,
+	}
+	if success {
+		event.Status = "completed"
+		if buildResp != nil {
+			event.ExitCode = buildResp.ExitCode
 		}
-		if success {
-			event.Status = "completed"
-			if buildResp != nil {
-				event.ExitCode = buildResp.ExitCode
-			}
-		} else {
-			event.Status = "failed"
-			event.ExitCode = 1
-			if err != nil {
-				event.ErrorMessage = err.Error()
-			} else if buildResp != nil {
-				event.ExitCode = buildResp.ExitCode
-				event.ErrorMessage = buildResp.Stderr
-			}
+	} else {
+		event.Status = "failed"
+		event.ExitCode = 1
+		if err != nil {
+			event.ErrorMessage = err.Error()
+		} else if buildResp != nil {
+			event.ExitCode = buildResp.ExitCode
+			event.ErrorMessage = buildResp.Stderr
 		}
-		s.eventNotifier.NotifyTaskCompleted(event)
 	}
+	s.notifyTaskCompleted(ctx, event)
 
 	if err != nil {
 		log.Error().Err(err).Str("task_id", req.TaskId).Str("worker", worker.ID).
@@ -925,13 +1156,13 @@ func (s *Server) handleFlutterBuild(ctx context.Context, req *pb.BuildRequest) (
 	}
 
 	if buildResp != nil && len(buildResp.Artifacts) > 0 {
-		s.setFlutterCache(cacheKey, buildResp)
+		s.buildCache.put("flutter", cacheKey, buildResp)
 	}
 
-	return buildResp, nil
+	return s.deferArtifacts(ctx, req, buildResp), nil
 }
 
-func (s *Server) handleUnityBuild(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+func (s *Server) handleUnityBuild(ctx context.Context, req *pb.BuildRequest, forward buildForwarder) (*pb.BuildResponse, error) {
 	start := time.Now()
 	m := metrics.Default()
 
@@ -939,55 +1170,53 @@ func (s *Server) handleUnityBuild(ctx context.Context, req *pb.BuildRequest) (*p
 	unityVersion := unityConfig.GetUnityVersion()
 	cacheKey := cache.UnityCacheKey(unityConfig, req.SourceHash, unityVersion, req.TargetPlatform)
 
-	if cached := s.getUnityCache(cacheKey); cached != nil {
+	if cached := s.buildCache.get("unity", cacheKey); cached != nil {
 		atomic.AddInt64(&s.cacheHits, 1)
 		atomic.AddInt64(&s.unityCacheHits, 1)
 		atomic.AddInt64(&s.unityBuilds, 1)
 		atomic.AddInt64(&s.totalTasks, 1)
 		atomic.AddInt64(&s.successTasks, 1)
 
-		if s.eventNotifier != nil {
-			taskStart := start.Unix()
-			s.eventNotifier.NotifyTaskStarted(&TaskEvent{
-				ID:        req.TaskId,
-				BuildType: "unity",
-				Status:    "running",
-				WorkerID:  "",
-				StartedAt: taskStart,
-			})
-			s.eventNotifier.NotifyTaskCompleted(&TaskEvent{
-				ID:           req.TaskId,
-				BuildType:    "unity",
-				Status:       "completed",
-				WorkerID:     "",
-				StartedAt:    taskStart,
-				CompletedAt:  time.Now().Unix(),
-				DurationMs:   cached.buildTimeMs,
-				ExitCode:     0,
-				FromCache:    true,
-				ErrorMessage: "",
-			})
-		}
+		taskStart := start.Unix()
+		s.notifyTaskStarted(ctx, &TaskEvent{
+			ID:        req.TaskId,
+			BuildType: "unity",
+			Status:    "running",
+			WorkerID:  "",
+			StartedAt: taskStart,
+		})
+		s.notifyTaskCompleted(ctx, &TaskEvent{
+			ID:           req.TaskId,
+			BuildType:    "unity",
+			Status:       "completed",
+			WorkerID:     "",
+			StartedAt:    taskStart,
+			CompletedAt:  time.Now().Unix(),
+			DurationMs:   cached.buildTimeMs,
+			ExitCode:     0,
+			FromCache:    true,
+			ErrorMessage: "",
+		})
 
-		return &pb.BuildResponse{
+		return s.deferArtifacts(ctx, req, &pb.BuildResponse{
 			Status:       pb.TaskStatus_STATUS_COMPLETED,
 			ExitCode:     0,
 			Stdout:       cached.stdout,
 			Stderr:       cached.stderr,
-			Artifacts:    append([]byte(nil), cached.artifacts...),
-			ArtifactList: cloneArtifactList(cached.artifactList),
+			Artifacts:    cached.artifacts,
+			ArtifactList: cached.artifactList,
 			BuildTimeMs:  cached.buildTimeMs,
 			FromCache:    true,
-		}, nil
+		}), nil
 	}
 
 	atomic.AddInt64(&s.cacheMisses, 1)
 	atomic.AddInt64(&s.unityCacheMisses, 1)
 
-	atomic.AddInt64(&s.queuedTasks, 1)
-	defer atomic.AddInt64(&s.queuedTasks, -1)
-
-	worker, err := s.selectUnityWorker(req.TargetPlatform)
+	taskCtx := scheduler.TaskContext{TaskID: req.TaskId, BuildType: pb.BuildType_BUILD_TYPE_UNITY}
+	worker, queueTime, err := s.acquireWorker(ctx, "unity", req.Priority, 0, func() (*registry.WorkerInfo, error) {
+		return s.selectUnityWorker(req.TargetPlatform, taskCtx)
+	})
 	if err != nil {
 		atomic.AddInt64(&s.totalTasks, 1)
 		atomic.AddInt64(&s.failedTasks, 1)
@@ -995,28 +1224,26 @@ func (s *Server) handleUnityBuild(ctx context.Context, req *pb.BuildRequest) (*p
 			Str("target_platform", req.TargetPlatform.String()).
 			Msg("No worker available for unity build")
 
-		if s.eventNotifier != nil {
-			taskStart := start.Unix()
-			s.eventNotifier.NotifyTaskStarted(&TaskEvent{
-				ID:        req.TaskId,
-				BuildType: "unity",
-				Status:    "running",
-				WorkerID:  "",
-				StartedAt: taskStart,
-			})
-			s.eventNotifier.NotifyTaskCompleted(&TaskEvent{
-				ID:           req.TaskId,
-				BuildType:    "unity",
-				Status:       "failed",
-				WorkerID:     "",
-				StartedAt:    taskStart,
-				CompletedAt:  time.Now().Unix(),
-				DurationMs:   0,
-				ExitCode:     1,
-				FromCache:    false,
-				ErrorMessage: fmt.Sprintf("no worker available: %v", err),
-			})
-		}
+		taskStart := start.Unix()
+		s.notifyTaskStarted(ctx, &TaskEvent{
+			ID:        req.TaskId,
+			BuildType: "unity",
+			Status:    "running",
+			WorkerID:  "",
+			StartedAt: taskStart,
+		})
+		s.notifyTaskCompleted(ctx, &TaskEvent{
+			ID:           req.TaskId,
+			BuildType:    "unity",
+			Status:       "failed",
+			WorkerID:     "",
+			StartedAt:    taskStart,
+			CompletedAt:  time.Now().Unix(),
+			DurationMs:   0,
+			ExitCode:     1,
+			FromCache:    false,
+			ErrorMessage: fmt.Sprintf("no worker available: %v", err),
+		})
 
 		return &pb.BuildResponse{
 			Status:   pb.TaskStatus_STATUS_FAILED,
@@ -1027,6 +1254,7 @@ func (s *Server) handleUnityBuild(ctx context.Context, req *pb.BuildRequest) (*p
 
 	conn, err := s.workerConns.get(ctx, worker.Address)
 	if err != nil {
+		s.releaseWorker(worker.ID, false, 0)
 		log.Error().Err(err).Str("worker", worker.ID).Msg("Failed to connect to worker")
 		return &pb.BuildResponse{
 			Status:   pb.TaskStatus_STATUS_FAILED,
@@ -1035,7 +1263,6 @@ func (s *Server) handleUnityBuild(ctx context.Context, req *pb.BuildRequest) (*p
 		}, nil
 	}
 
-	s.registry.IncrementTasks(worker.ID)
 	atomic.AddInt64(&s.activeTasks, 1)
 	atomic.AddInt64(&s.totalTasks, 1)
 	atomic.AddInt64(&s.unityBuilds, 1)
@@ -1054,30 +1281,23 @@ func (s *Server) handleUnityBuild(ctx context.Context, req *pb.BuildRequest) (*p
 
 	taskStartTime := time.Now()
 
-	if s.eventNotifier != nil {
-		s.eventNotifier.NotifyTaskStarted(&TaskEvent{
-			ID:        req.TaskId,
-			BuildType: "unity",
-			Status:    "running",
-			WorkerID:  worker.ID,
-			StartedAt: taskStartTime.Unix(),
-		})
-	}
-
-	client := pb.NewBuildServiceClient(conn)
-	buildResp, err := client.Build(ctx, &pb.BuildRequest{
-		TaskId:         req.TaskId,
-		SourceHash:     req.SourceHash,
-		SourceArchive:  req.SourceArchive,
-		BuildType:      req.BuildType,
-		TargetPlatform: req.TargetPlatform,
-		Config:         req.Config,
-		TimeoutSeconds: req.TimeoutSeconds,
+	s.notifyTaskStarted(ctx, &TaskEvent{
+		ID:        req.TaskId,
+		BuildType: "unity",
+		Status:    "running",
+		WorkerID:  worker.ID,
+		StartedAt: taskStartTime.Unix(),
 	})
 
+	buildResp, err := s.forwardWatched(ctx, req.TaskId, pb.NewBuildServiceClient(conn), forward)
+	if buildResp != nil {
+		buildResp.QueueTimeMs = queueTime.Milliseconds()
+	}
+
 	success := err == nil && buildResp != nil && buildResp.Status == pb.TaskStatus_STATUS_COMPLETED
 
-	s.registry.DecrementTasks(worker.ID, success, time.Duration(0))
+	s.releaseWorker(worker.ID, success, 0)
+	s.recordBuildOutcome(worker.ID, buildResp, success, taskCtx)
 
 	taskCompletedTime := time.Now()
 
@@ -1087,33 +1307,31 @@ func (s *Server) handleUnityBuild(ctx context.Context, req *pb.BuildRequest) (*p
 		atomic.AddInt64(&s.failedTasks, 1)
 	}
 
-	if s.eventNotifier != nil {
-		event := &TaskEvent{
-			ID:          req.TaskId,
-			BuildType:   "unity",
-			WorkerID:    worker.ID,
-			StartedAt:   taskStartTime.Unix(),
-			CompletedAt: taskCompletedTime.Unix(),
-			DurationMs:  taskCompletedTime.Sub(taskStartTime).Milliseconds(),
-			FromCache:   false,
+	event := &TaskEvent{
+		ID:          req.TaskId,
+		BuildType:   "unity",
+		WorkerID:    worker.ID,
+		StartedAt:   taskStartTime.Unix(),
+		CompletedAt: taskCompletedTime.Unix(),
+		DurationMs:  taskCompletedTime.Sub(taskStartTime).Milliseconds(),
+		FromCache:   false,
+	}
+	if success {
+		event.Status = "completed"
+		if buildResp != nil {
+			event.ExitCode = buildResp.ExitCode
 		}
-		if success {
-			event.Status = "completed"
-			if buildResp != nil {
-				event.ExitCode = buildResp.ExitCode
-			}
-		} else {
-			event.Status = "failed"
-			event.ExitCode = 1
-			if err != nil {
-				event.ErrorMessage = err.Error()
-			} else if buildResp != nil {
-				event.ExitCode = buildResp.ExitCode
-				event.ErrorMessage = buildResp.Stderr
-			}
+	} else {
+		event.Status = "failed"
+		event.ExitCode = 1
+		if err != nil {
+			event.ErrorMessage = err.Error()
+		} else if buildResp != nil {
+			event.ExitCode = buildResp.ExitCode
+			event.ErrorMessage = buildResp.Stderr
 		}
-		s.eventNotifier.NotifyTaskCompleted(event)
 	}
+	s.notifyTaskCompleted(ctx, event)
 
 	if err != nil {
 		log.Error().Err(err).Str("task_id", req.TaskId).Str("worker", worker.ID).
@@ -1126,45 +1344,10 @@ func (s *Server) handleUnityBuild(ctx context.Context, req *pb.BuildRequest) (*p
 	}
 
 	if buildResp != nil && len(buildResp.Artifacts) > 0 {
-		s.setUnityCache(cacheKey, buildResp)
-	}
-
-	return buildResp, nil
-}
-
-// StreamBuild handles streaming build requests.
-func (s *Server) StreamBuild(stream pb.BuildService_StreamBuildServer) error {
-	var metadata *pb.BuildMetadata
-	var sourceData []byte
-
-	for {
-		chunk, err := stream.Recv()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return status.Errorf(codes.Internal, "failed to receive chunk: %v", err)
-		}
-
-		switch payload := chunk.Payload.(type) {
-		case *pb.BuildChunk_Metadata:
-			metadata = payload.Metadata
-		case *pb.BuildChunk_SourceChunk:
-			sourceData = append(sourceData, payload.SourceChunk...)
-		}
-	}
-
-	if metadata == nil {
-		return status.Error(codes.InvalidArgument, "metadata required")
+		s.buildCache.put("unity", cacheKey, buildResp)
 	}
 
-	_ = sourceData // TODO: Use source data
-
-	return stream.SendAndClose(&pb.BuildResponse{
-		Status:   pb.TaskStatus_STATUS_FAILED,
-		ExitCode: 1,
-		Stderr:   "stream build not implemented yet",
-	})
+	return s.deferArtifacts(ctx, req, buildResp), nil
 }
 
 // HealthCheck returns coordinator health status.
@@ -1180,7 +1363,7 @@ func (s *Server) HealthCheck(ctx context.Context, req *pb.HealthRequest) (*pb.He
 	return &pb.HealthResponse{
 		Healthy:     healthyCount > 0 || len(workers) == 0,
 		ActiveTasks: clampInt64ToInt32(atomic.LoadInt64(&s.activeTasks)),
-		QueuedTasks: clampInt64ToInt32(atomic.LoadInt64(&s.queuedTasks)),
+		QueuedTasks: clampInt64ToInt32(int64(s.queue.Len())),
 	}, nil
 }
 
@@ -1207,7 +1390,9 @@ func (s *Server) GetWorkerStatus(ctx context.Context, req *pb.WorkerStatusReques
 			MemoryBytes:         caps.MemoryBytes,
 			ActiveTasks:         w.ActiveTasks,
 			TotalTasksCompleted: w.TotalTasks,
+			DiscoverySource:     w.DiscoverySource,
 			LastHeartbeatUnix:   w.LastHeartbeat.Unix(),
+			Load:                w.Load,
 		}
 		infos = append(infos, info)
 	}
@@ -1242,6 +1427,62 @@ func (s *Server) ReportCacheHit(ctx context.Context, req *pb.ReportCacheHitReque
 	return &pb.ReportCacheHitResponse{Acknowledged: true}, nil
 }
 
+// ReportLoad stores the host load a registered worker reports with its
+// heartbeat, for schedulers to steer tasks away from workers busy with
+// other work. Unknown workers are told to handshake again. With a worker
+// certificate allow-list, only the worker itself may report its load.
+func (s *Server) ReportLoad(ctx context.Context, req *pb.ReportLoadRequest) (*pb.ReportLoadResponse, error) {
+	if req.WorkerId == "" || req.Load == nil {
+		return nil, status.Error(codes.InvalidArgument, "worker_id and load required")
+	}
+	worker, ok := s.registry.Get(req.WorkerId)
+	if !ok {
+		return &pb.ReportLoadResponse{Accepted: false}, nil
+	}
+	// Hold the report to the certificate the worker registered with, so a
+	// worker cannot skew the scheduling of another.
+	if s.workerAllow != nil {
+		cert, _ := hgtls.PeerCertificate(ctx)
+		if err := s.workerAllow.Attest(cert, worker.Capabilities.GetHostname(), req.WorkerId); err != nil {
+			log.Warn().
+				Err(err).
+				Str("worker_id", req.WorkerId).
+				Msg("Load report rejected: certificate attestation failed")
+			return nil, status.Errorf(codes.PermissionDenied, "worker attestation failed: %v", err)
+		}
+	}
+	if err := s.registry.UpdateLoad(req.WorkerId, req.Load); err != nil {
+		return &pb.ReportLoadResponse{Accepted: false}, nil
+	}
+	log.Debug().
+		Str("worker_id", req.WorkerId).
+		Float32("cpu_percent", req.Load.CpuUsagePercent).
+		Float32("memory_percent", req.Load.MemoryUsagePercent).
+		Float64("load_1m", req.Load.LoadAverage_1M).
+		Int32("containers", req.Load.RunningContainers).
+		Msg("Worker load reported")
+	return &pb.ReportLoadResponse{Accepted: true}, nil
+}
+
+// CancelTask cancels a queued or running Compile, Build or StreamBuild. A
+// queued task leaves the queue; a running one has its worker call cancelled,
+// which makes the worker kill the build and frees the slot. Only the task's
+// submitter and admins may cancel it.
+func (s *Server) CancelTask(ctx context.Context, req *pb.CancelTaskRequest) (*pb.CancelTaskResponse, error) {
+	if req.TaskId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id required")
+	}
+
+	cancelled, err := s.running.Cancel(req.TaskId, taskAccess(ctx))
+	if errors.Is(err, cancellation.ErrNotOwner) {
+		return nil, status.Errorf(codes.PermissionDenied, "task %s was submitted by another client", req.TaskId)
+	}
+	if cancelled {
+		log.Info().Str("task_id", req.TaskId).Msg("Task cancellation requested")
+	}
+	return &pb.CancelTaskResponse{Cancelled: cancelled}, nil
+}
+
 func clampInt64ToInt32(v int64) int32 {
 	if v > math.MaxInt32 {
 		return math.MaxInt32
@@ -1260,36 +1501,12 @@ func workerCapabilitiesOrDefault(worker *registry.WorkerInfo) *pb.WorkerCapabili
 	return &pb.WorkerCapabilities{}
 }
 
-func (s *Server) getFlutterCache(key string) *flutterCacheEntry {
-	s.flutterCacheMu.RLock()
-	entry := s.flutterCache[key]
-	s.flutterCacheMu.RUnlock()
-	return entry
-}
-
-func (s *Server) setFlutterCache(key string, resp *pb.BuildResponse) {
-	entry := &flutterCacheEntry{
-		stdout:       resp.Stdout,
-		stderr:       resp.Stderr,
-		artifacts:    resp.Artifacts,
-		artifactList: cloneArtifactList(resp.ArtifactList),
-		buildTimeMs:  resp.BuildTimeMs,
-	}
-	s.flutterCacheMu.Lock()
-	s.flutterCache[key] = entry
-	s.flutterCacheMu.Unlock()
-}
-
-func (s *Server) selectFlutterWorker(targetPlatform pb.TargetPlatform) (*registry.WorkerInfo, error) {
-	workers := s.registry.List()
-	for _, w := range workers {
-		if workerSupportsFlutterPlatform(w, targetPlatform) {
-			if w.IsHealthy(s.config.HeartbeatTTL) {
-				return w, nil
-			}
-		}
-	}
-	return nil, fmt.Errorf("no flutter worker available for platform %s", targetPlatform)
+// selectFlutterWorker picks a healthy worker that can build for
+// targetPlatform, or returns errWorkersBusy if all of them are at capacity.
+func (s *Server) selectFlutterWorker(targetPlatform pb.TargetPlatform, taskCtx scheduler.TaskContext) (*registry.WorkerInfo, error) {
+	supports := func(w *registry.WorkerInfo) bool { return workerSupportsFlutterPlatform(w, targetPlatform) }
+	return s.selectBuildWorker(pb.BuildType_BUILD_TYPE_FLUTTER, supports, taskCtx,
+		fmt.Errorf("no flutter worker available for platform %s", targetPlatform))
 }
 
 func workerSupportsFlutterPlatform(worker *registry.WorkerInfo, platform pb.TargetPlatform) bool {
@@ -1307,36 +1524,12 @@ func workerSupportsFlutterPlatform(worker *registry.WorkerInfo, platform pb.Targ
 	return false
 }
 
-func (s *Server) getUnityCache(key string) *unityCacheEntry {
-	s.unityCacheMu.RLock()
-	entry := s.unityCache[key]
-	s.unityCacheMu.RUnlock()
-	return entry
-}
-
-func (s *Server) setUnityCache(key string, resp *pb.BuildResponse) {
-	entry := &unityCacheEntry{
-		stdout:       resp.Stdout,
-		stderr:       resp.Stderr,
-		artifacts:    resp.Artifacts,
-		artifactList: cloneArtifactList(resp.ArtifactList),
-		buildTimeMs:  resp.BuildTimeMs,
-	}
-	s.unityCacheMu.Lock()
-	s.unityCache[key] = entry
-	s.unityCacheMu.Unlock()
-}
-
-func (s *Server) selectUnityWorker(targetPlatform pb.TargetPlatform) (*registry.WorkerInfo, error) {
-	workers := s.registry.List()
-	for _, w := range workers {
-		if workerSupportsUnityPlatform(w, targetPlatform) {
-			if w.IsHealthy(s.config.HeartbeatTTL) {
-				return w, nil
-			}
-		}
-	}
-	return nil, fmt.Errorf("no unity worker available for platform %s", targetPlatform)
+// selectUnityWorker picks a healthy worker that can build for
+// targetPlatform, or returns errWorkersBusy if all of them are at capacity.
+func (s *Server) selectUnityWorker(targetPlatform pb.TargetPlatform, taskCtx scheduler.TaskContext) (*registry.WorkerInfo, error) {
+	supports := func(w *registry.WorkerInfo) bool { return workerSupportsUnityPlatform(w, targetPlatform) }
+	return s.selectBuildWorker(pb.BuildType_BUILD_TYPE_UNITY, supports, taskCtx,
+		fmt.Errorf("no unity worker available for platform %s", targetPlatform))
 }
 
 func workerSupportsUnityPlatform(worker *registry.WorkerInfo, platform pb.TargetPlatform) bool {
@@ -1,8 +1,16 @@
 package server
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"net"
+	"os"
+	"path"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -18,6 +26,7 @@ import (
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
 	"github.com/h3nr1-d14z/hybridgrid/internal/cache"
 	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/scheduler"
 )
 
 const bufSize = 1024 * 1024
@@ -27,8 +36,11 @@ func setupTestServer(t *testing.T, cfg Config) (*Server, pb.BuildServiceClient,
 	t.Helper()
 
 	lis := bufconn.Listen(bufSize)
-	srv := grpc.NewServer()
+	if cfg.BuildCacheDir == "" {
+		cfg.BuildCacheDir = t.TempDir()
+	}
 	s := New(cfg)
+	srv := grpc.NewServer(s.leaderOptions()...)
 	pb.RegisterBuildServiceServer(srv, s)
 
 	go func() {
@@ -57,7 +69,39 @@ func setupTestServer(t *testing.T, cfg Config) (*Server, pb.BuildServiceClient,
 
 type mockWorkerBuildService struct {
 	pb.UnimplementedBuildServiceServer
-	buildFn func(context.Context, *pb.BuildRequest) (*pb.BuildResponse, error)
+	buildFn   func(context.Context, *pb.BuildRequest) (*pb.BuildResponse, error)
+	streamFn  func(pb.BuildService_StreamBuildServer) error
+	compileFn func(context.Context, *pb.CompileRequest) (*pb.CompileResponse, error)
+	watchFn   func(*pb.WatchTaskRequest, pb.BuildService_WatchTaskServer) error
+	statusFn  func(context.Context, *pb.WorkerStatusRequest) (*pb.WorkerStatusResponse, error)
+}
+
+func (m *mockWorkerBuildService) GetWorkerStatus(ctx context.Context, req *pb.WorkerStatusRequest) (*pb.WorkerStatusResponse, error) {
+	if m.statusFn != nil {
+		return m.statusFn(ctx, req)
+	}
+	return m.UnimplementedBuildServiceServer.GetWorkerStatus(ctx, req)
+}
+
+func (m *mockWorkerBuildService) WatchTask(req *pb.WatchTaskRequest, stream pb.BuildService_WatchTaskServer) error {
+	if m.watchFn != nil {
+		return m.watchFn(req, stream)
+	}
+	return m.UnimplementedBuildServiceServer.WatchTask(req, stream)
+}
+
+func (m *mockWorkerBuildService) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.CompileResponse, error) {
+	if m.compileFn != nil {
+		return m.compileFn(ctx, req)
+	}
+	return m.UnimplementedBuildServiceServer.Compile(ctx, req)
+}
+
+func (m *mockWorkerBuildService) StreamBuild(stream pb.BuildService_StreamBuildServer) error {
+	if m.streamFn != nil {
+		return m.streamFn(stream)
+	}
+	return m.UnimplementedBuildServiceServer.StreamBuild(stream)
 }
 
 func (m *mockWorkerBuildService) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
@@ -73,12 +117,17 @@ func (m *mockWorkerBuildService) Build(ctx context.Context, req *pb.BuildRequest
 
 func setupTestWorker(t *testing.T, buildFn func(context.Context, *pb.BuildRequest) (*pb.BuildResponse, error)) (string, func()) {
 	t.Helper()
+	return setupMockWorker(t, &mockWorkerBuildService{buildFn: buildFn})
+}
+
+func setupMockWorker(t *testing.T, svc *mockWorkerBuildService) (string, func()) {
+	t.Helper()
 
 	lis, err := net.Listen("tcp", "127.0.0.1:0")
 	require.NoError(t, err)
 
 	srv := grpc.NewServer()
-	pb.RegisterBuildServiceServer(srv, &mockWorkerBuildService{buildFn: buildFn})
+	pb.RegisterBuildServiceServer(srv, svc)
 
 	go func() {
 		if err := srv.Serve(lis); err != nil {
@@ -341,6 +390,62 @@ func TestHandshake_DuplicateWorker(t *testing.T) {
 	assert.True(t, resp.Accepted)
 }
 
+func TestHandshake_RegistryFileSurvivesRestart(t *testing.T) {
+	cfg := Config{Port: 0, HeartbeatTTL: 30 * time.Second, RegistryFile: filepath.Join(t.TempDir(), "workers.json")}
+	req := &pb.HandshakeRequest{
+		Capabilities: &pb.WorkerCapabilities{
+			WorkerId: "persistent-worker",
+			Hostname: "host",
+		},
+		WorkerAddress: "10.0.0.5:50052",
+	}
+
+	s, client, cleanup := setupTestServer(t, cfg)
+	resp, err := client.Handshake(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, resp.Accepted)
+	require.NoError(t, s.Registry().IncrementTasks("persistent-worker"))
+	require.NoError(t, s.Registry().DecrementTasks("persistent-worker", true, time.Second))
+	cleanup()
+
+	s, client, cleanup = setupTestServer(t, cfg)
+	defer cleanup()
+
+	w, ok := s.Registry().Get("persistent-worker")
+	require.True(t, ok, "worker should be restored")
+	assert.Equal(t, registry.WorkerStateUnknown, w.State)
+	assert.Equal(t, "10.0.0.5:50052", w.Address)
+	assert.Equal(t, int64(1), w.SuccessfulTasks)
+
+	// The next heartbeat makes it schedulable again
+	resp, err = client.Handshake(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, resp.Accepted)
+	w, _ = s.Registry().Get("persistent-worker")
+	assert.Equal(t, registry.WorkerStateIdle, w.State)
+	assert.Equal(t, int64(1), w.SuccessfulTasks)
+}
+
+func TestNew_SchedulerStateSurvivesRestart(t *testing.T) {
+	cfg := Config{
+		Port:               0,
+		HeartbeatTTL:       30 * time.Second,
+		SchedulerType:      "epsilon-greedy",
+		SchedulerStateFile: filepath.Join(t.TempDir(), "scheduler.json"),
+	}
+
+	s := New(cfg)
+	learner, ok := s.scheduler.(scheduler.LearningScheduler)
+	require.True(t, ok)
+	learner.RecordOutcome("worker-1", -2, true, scheduler.TaskContext{})
+	s.Stop()
+
+	s = New(cfg)
+	defer s.Stop()
+	learner = s.scheduler.(scheduler.LearningScheduler)
+	assert.Contains(t, learner.Checkpoint(), "worker-1", "learned state should be restored")
+}
+
 // --- Compile ---
 
 func TestCompile_EmptyTaskId(t *testing.T) {
@@ -388,6 +493,181 @@ func TestCompile_TracksMetrics(t *testing.T) {
 	assert.Equal(t, int64(1), atomic.LoadInt64(&s.cacheMisses))
 }
 
+func TestCompile_SharedCacheHitSkipsWorker(t *testing.T) {
+	var calls int32
+	addr, workerCleanup := setupMockWorker(t, &mockWorkerBuildService{
+		compileFn: func(ctx context.Context, req *pb.CompileRequest) (*pb.CompileResponse, error) {
+			atomic.AddInt32(&calls, 1)
+			return &pb.CompileResponse{
+				Status:            pb.TaskStatus_STATUS_COMPLETED,
+				ObjectFile:        []byte("object-code"),
+				Stderr:            "warning: unused variable",
+				CompilationTimeMs: 40,
+				WorkerId:          "worker-1",
+			}, nil
+		},
+	})
+	defer workerCleanup()
+
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 5 * time.Second})
+	defer cleanup()
+
+	s.registry.Add(&registry.WorkerInfo{
+		ID:          "worker-1",
+		Address:     addr,
+		MaxParallel: 4,
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			Os:         "linux",
+			Cpp:        &pb.CppCapability{Compilers: []string{"gcc"}},
+		},
+	})
+
+	newRequest := func(taskID string) *pb.CompileRequest {
+		return &pb.CompileRequest{
+			TaskId:             taskID,
+			Compiler:           "gcc",
+			CompilerArgs:       []string{"-c", "-O2"},
+			PreprocessedSource: []byte("int main() { return 0; }"),
+			TargetArch:         pb.Architecture_ARCH_X86_64,
+			ClientOs:           "linux",
+		}
+	}
+
+	first, err := client.Compile(context.Background(), newRequest("developer-a"))
+	require.NoError(t, err)
+	require.Equal(t, pb.TaskStatus_STATUS_COMPLETED, first.Status, first.Stderr)
+	assert.False(t, first.FromCache)
+
+	second, err := client.Compile(context.Background(), newRequest("ci-build"))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, second.Status)
+	assert.True(t, second.FromCache)
+	assert.Equal(t, []byte("object-code"), second.ObjectFile)
+	assert.Equal(t, "warning: unused variable", second.Stderr)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&s.cacheHits))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&s.cacheMisses))
+}
+
+func TestCompile_LinkRoutesToMatchingWorker(t *testing.T) {
+	var received *pb.CompileRequest
+	addr, workerCleanup := setupMockWorker(t, &mockWorkerBuildService{
+		compileFn: func(ctx context.Context, req *pb.CompileRequest) (*pb.CompileResponse, error) {
+			received = req
+			return &pb.CompileResponse{
+				Status:     pb.TaskStatus_STATUS_COMPLETED,
+				ObjectFile: []byte("linked-binary"),
+				WorkerId:   "linux-gcc",
+			}, nil
+		},
+	})
+	defer workerCleanup()
+
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 5 * time.Second})
+	defer cleanup()
+
+	// Idle workers that sort first but cannot run the link natively.
+	s.registry.Add(&registry.WorkerInfo{
+		ID:          "a-darwin-clang",
+		Address:     "127.0.0.1:1",
+		MaxParallel: 4,
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			Os:         "darwin",
+			Cpp:        &pb.CppCapability{Compilers: []string{"clang", "clang++"}},
+		},
+	})
+	s.registry.Add(&registry.WorkerInfo{
+		ID:          "b-linux-arm",
+		Address:     "127.0.0.1:1",
+		MaxParallel: 4,
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch:      pb.Architecture_ARCH_ARM64,
+			Os:              "linux",
+			DockerAvailable: true,
+			Cpp:             &pb.CppCapability{Compilers: []string{"gcc", "g++"}},
+		},
+	})
+	s.registry.Add(&registry.WorkerInfo{
+		ID:          "linux-gcc",
+		Address:     addr,
+		MaxParallel: 4,
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			Os:         "linux",
+			Cpp:        &pb.CppCapability{Compilers: []string{"gcc", "g++"}},
+		},
+	})
+
+	newRequest := func(taskID string) *pb.CompileRequest {
+		return &pb.CompileRequest{
+			TaskId:       taskID,
+			Compiler:     "/usr/bin/g++",
+			CompilerArgs: []string{"inputs/0-main.o", "inputs/1-libfoo.a", "-lpthread"},
+			Link:         true,
+			LinkInputs: map[string][]byte{
+				"inputs/0-main.o":   []byte("main-object"),
+				"inputs/1-libfoo.a": []byte("archive"),
+			},
+			TargetArch: pb.Architecture_ARCH_X86_64,
+			ClientOs:   "linux",
+		}
+	}
+
+	resp, err := client.Compile(context.Background(), newRequest("link-1"))
+	require.NoError(t, err)
+	require.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status, resp.Stderr)
+	assert.Equal(t, []byte("linked-binary"), resp.ObjectFile)
+	require.NotNil(t, received)
+	assert.True(t, received.Link)
+	assert.Len(t, received.LinkInputs, 2)
+
+	// The same inputs are served from the shared cache.
+	received = nil
+	cached, err := client.Compile(context.Background(), newRequest("link-2"))
+	require.NoError(t, err)
+	assert.True(t, cached.FromCache)
+	assert.Nil(t, received)
+
+	// Changing any input misses the cache.
+	changed := newRequest("link-3")
+	changed.LinkInputs["inputs/1-libfoo.a"] = []byte("rebuilt-archive")
+	resp, err = client.Compile(context.Background(), changed)
+	require.NoError(t, err)
+	assert.False(t, resp.FromCache)
+	assert.NotNil(t, received)
+}
+
+func TestCompile_LinkWithoutMatchingWorkerFails(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 5 * time.Second})
+	defer cleanup()
+
+	s.registry.Add(&registry.WorkerInfo{
+		ID:          "darwin-clang",
+		Address:     "127.0.0.1:1",
+		MaxParallel: 4,
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			Os:         "darwin",
+			Cpp:        &pb.CppCapability{Compilers: []string{"clang"}},
+		},
+	})
+
+	resp, err := client.Compile(context.Background(), &pb.CompileRequest{
+		TaskId:       "link-1",
+		Compiler:     "gcc",
+		CompilerArgs: []string{"inputs/0-main.o"},
+		Link:         true,
+		LinkInputs:   map[string][]byte{"inputs/0-main.o": []byte("obj")},
+		TargetArch:   pb.Architecture_ARCH_X86_64,
+		ClientOs:     "linux",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_FAILED, resp.Status)
+	assert.Contains(t, resp.Stderr, "no worker available")
+}
+
 // --- Build ---
 
 func TestBuild_EmptyTaskId(t *testing.T) {
@@ -433,7 +713,7 @@ func TestBuild_Flutter_CacheHit(t *testing.T) {
 	flutterConfig := req.GetFlutterConfig()
 	cacheKey := cache.FlutterCacheKey(flutterConfig, req.SourceHash, flutterConfig.GetFlutterVersion())
 
-	s.setFlutterCache(cacheKey, &pb.BuildResponse{
+	s.buildCache.put("flutter", cacheKey, &pb.BuildResponse{
 		Status:      pb.TaskStatus_STATUS_COMPLETED,
 		ExitCode:    0,
 		Stdout:      "cached build",
@@ -506,7 +786,7 @@ func TestBuild_Flutter_CacheMissForwardsAndCaches(t *testing.T) {
 	assert.Equal(t, int64(1), atomic.LoadInt64(&buildCalls))
 
 	cacheKey := cache.FlutterCacheKey(req.GetFlutterConfig(), req.SourceHash, req.GetFlutterConfig().GetFlutterVersion())
-	cached := s.getFlutterCache(cacheKey)
+	cached := s.buildCache.get("flutter", cacheKey)
 	require.NotNil(t, cached)
 	assert.Equal(t, "worker build", cached.stdout)
 	assert.Equal(t, []byte("worker-apk"), cached.artifacts)
@@ -518,6 +798,42 @@ func TestBuild_Flutter_CacheMissForwardsAndCaches(t *testing.T) {
 	assert.Equal(t, int64(1), atomic.LoadInt64(&buildCalls))
 }
 
+func TestBuild_Flutter_FeedsLearningScheduler(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 2 * time.Second, SchedulerType: "epsilon-greedy"})
+	defer cleanup()
+
+	addr, workerCleanup := setupTestWorker(t, func(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+		return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED, BuildTimeMs: 90000}, nil
+	})
+	defer workerCleanup()
+
+	for _, id := range []string{"flutter-ios", "flutter-android"} {
+		platform := pb.TargetPlatform_PLATFORM_IOS
+		if id == "flutter-android" {
+			platform = pb.TargetPlatform_PLATFORM_ANDROID
+		}
+		require.NoError(t, s.registry.Add(&registry.WorkerInfo{
+			ID:      id,
+			Address: addr,
+			Capabilities: &pb.WorkerCapabilities{
+				WorkerId: id,
+				Flutter:  &pb.FlutterCapability{Platforms: []pb.TargetPlatform{platform}},
+			},
+			MaxParallel: 1,
+		}))
+	}
+
+	resp, err := client.Build(context.Background(), newFlutterBuildRequest("flutter-learn", "0011aabb"))
+	require.NoError(t, err)
+	require.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status)
+
+	// The scheduler only considered the Android worker and learned from
+	// the build in the worker's Flutter arm.
+	arms := s.scheduler.(scheduler.LearningScheduler).Checkpoint()
+	assert.Contains(t, arms, "flutter-android/flutter")
+	assert.Len(t, arms, 1)
+}
+
 // --- StreamBuild ---
 
 func TestStreamBuild_NoMetadata(t *testing.T) {
@@ -571,6 +887,281 @@ func TestStreamBuild_WithMetadata(t *testing.T) {
 	assert.Contains(t, resp.Stderr, "not implemented")
 }
 
+func TestStreamBuild_Flutter_RelaysChunksToWorker(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 2 * time.Second})
+	defer cleanup()
+
+	var (
+		gotMetadata *pb.BuildMetadata
+		gotSource   []byte
+		gotChunks   int
+	)
+	addr, workerCleanup := setupMockWorker(t, &mockWorkerBuildService{
+		streamFn: func(stream pb.BuildService_StreamBuildServer) error {
+			for {
+				chunk, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				if md := chunk.GetMetadata(); md != nil {
+					gotMetadata = md
+					continue
+				}
+				gotChunks++
+				gotSource = append(gotSource, chunk.GetSourceChunk()...)
+			}
+			return stream.SendAndClose(&pb.BuildResponse{
+				Status:    pb.TaskStatus_STATUS_COMPLETED,
+				Stdout:    "streamed build",
+				Artifacts: []byte("streamed-apk"),
+			})
+		},
+	})
+	defer workerCleanup()
+
+	require.NoError(t, s.registry.Add(&registry.WorkerInfo{
+		ID:      "flutter-stream-worker",
+		Address: addr,
+		Capabilities: &pb.WorkerCapabilities{
+			WorkerId: "flutter-stream-worker",
+			Flutter: &pb.FlutterCapability{
+				Platforms: []pb.TargetPlatform{pb.TargetPlatform_PLATFORM_ANDROID},
+			},
+		},
+		MaxParallel: 2,
+	}))
+
+	stream, err := client.StreamBuild(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.BuildChunk{
+		Payload: &pb.BuildChunk_Metadata{Metadata: &pb.BuildMetadata{
+			TaskId:         "stream-flutter",
+			SourceHash:     "abcdef01",
+			BuildType:      pb.BuildType_BUILD_TYPE_FLUTTER,
+			TargetPlatform: pb.TargetPlatform_PLATFORM_ANDROID,
+			TotalSizeBytes: 6,
+			ConfigJson:     `{"buildMode":"apk-release","flavor":"demo"}`,
+			TimeoutSeconds: 600,
+		}},
+	}))
+	for _, part := range []string{"ab", "cd", "ef"} {
+		require.NoError(t, stream.Send(&pb.BuildChunk{
+			Payload: &pb.BuildChunk_SourceChunk{SourceChunk: []byte(part)},
+		}))
+	}
+
+	resp, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status)
+	assert.Equal(t, "streamed build", resp.Stdout)
+
+	require.NotNil(t, gotMetadata)
+	assert.Equal(t, "stream-flutter", gotMetadata.TaskId)
+	assert.Equal(t, int32(600), gotMetadata.TimeoutSeconds)
+	assert.Equal(t, []byte("abcdef"), gotSource)
+	assert.Equal(t, 3, gotChunks)
+
+	cacheKey := cache.FlutterCacheKey(&pb.FlutterConfig{BuildMode: "apk-release", Flavor: "demo"}, "abcdef01", "")
+	require.NotNil(t, s.buildCache.get("flutter", cacheKey))
+}
+
+func TestStreamBuild_Flutter_CacheHitSkipsUpload(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second})
+	defer cleanup()
+
+	cacheKey := cache.FlutterCacheKey(&pb.FlutterConfig{BuildMode: "apk-release"}, "0badcafe", "")
+	s.buildCache.put("flutter", cacheKey, &pb.BuildResponse{
+		Status:    pb.TaskStatus_STATUS_COMPLETED,
+		Stdout:    "cached build",
+		Artifacts: []byte("apk-bytes"),
+	})
+
+	stream, err := client.StreamBuild(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.BuildChunk{
+		Payload: &pb.BuildChunk_Metadata{Metadata: &pb.BuildMetadata{
+			TaskId:         "stream-cache-hit",
+			SourceHash:     "0badcafe",
+			BuildType:      pb.BuildType_BUILD_TYPE_FLUTTER,
+			TargetPlatform: pb.TargetPlatform_PLATFORM_ANDROID,
+			ConfigJson:     `{"buildMode":"apk-release"}`,
+		}},
+	}))
+
+	resp, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.True(t, resp.FromCache)
+	assert.Equal(t, "cached build", resp.Stdout)
+}
+
+func TestStreamBuild_InvalidConfigJSON(t *testing.T) {
+	_, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second})
+	defer cleanup()
+
+	stream, err := client.StreamBuild(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.BuildChunk{
+		Payload: &pb.BuildChunk_Metadata{Metadata: &pb.BuildMetadata{
+			TaskId:     "stream-bad-config",
+			BuildType:  pb.BuildType_BUILD_TYPE_UNITY,
+			ConfigJson: `{"buildMethod":`,
+		}},
+	}))
+
+	_, err = stream.CloseAndRecv()
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+}
+
+// --- FetchArtifacts ---
+
+func tarArtifacts(t *testing.T, files map[string][]byte, order ...string) ([]byte, []*pb.ArtifactInfo) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	var list []*pb.ArtifactInfo
+	for _, name := range order {
+		data := files[name]
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))}))
+		_, err := tw.Write(data)
+		require.NoError(t, err)
+
+		sum := sha256.Sum256(data)
+		list = append(list, &pb.ArtifactInfo{
+			Name:      path.Base(name),
+			Path:      name,
+			SizeBytes: int64(len(data)),
+			Checksum:  hex.EncodeToString(sum[:]),
+		})
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes(), list
+}
+
+func fetchAll(t *testing.T, client pb.BuildServiceClient, taskID string) ([]*pb.ArtifactInfo, map[string][]byte) {
+	t.Helper()
+
+	stream, err := client.FetchArtifacts(context.Background(), &pb.FetchArtifactsRequest{TaskId: taskID})
+	require.NoError(t, err)
+
+	var infos []*pb.ArtifactInfo
+	data := make(map[string][]byte)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if info := chunk.GetInfo(); info != nil {
+			infos = append(infos, info)
+			continue
+		}
+		require.NotEmpty(t, infos, "data before artifact header")
+		current := infos[len(infos)-1].Path
+		data[current] = append(data[current], chunk.GetData()...)
+	}
+	return infos, data
+}
+
+func TestFetchArtifacts_DeferredFlutterBuild(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{
+		Port:           0,
+		HeartbeatTTL:   30 * time.Second,
+		RequestTimeout: 2 * time.Second,
+		ArtifactDir:    t.TempDir(),
+	})
+	defer cleanup()
+
+	apk := bytes.Repeat([]byte("a"), artifactChunkSize+100)
+	archive, list := tarArtifacts(t, map[string][]byte{
+		"build/app/outputs/flutter-apk/app-release.apk": apk,
+		"build/app/outputs/flutter-apk/app-debug.apk":   []byte("debug"),
+	}, "build/app/outputs/flutter-apk/app-debug.apk", "build/app/outputs/flutter-apk/app-release.apk")
+
+	addr, workerCleanup := setupTestWorker(t, func(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+		assert.False(t, req.DeferArtifacts, "worker must return artifacts to the coordinator")
+		return &pb.BuildResponse{
+			Status:       pb.TaskStatus_STATUS_COMPLETED,
+			Artifacts:    archive,
+			ArtifactList: list,
+		}, nil
+	})
+	defer workerCleanup()
+
+	require.NoError(t, s.registry.Add(&registry.WorkerInfo{
+		ID:      "flutter-worker-1",
+		Address: addr,
+		Capabilities: &pb.WorkerCapabilities{
+			WorkerId: "flutter-worker-1",
+			Flutter: &pb.FlutterCapability{
+				Platforms:  []pb.TargetPlatform{pb.TargetPlatform_PLATFORM_ANDROID},
+				AndroidSdk: true,
+			},
+		},
+		MaxParallel: 4,
+	}))
+
+	req := newFlutterBuildRequest("flutter-deferred", "deadbeef")
+	req.DeferArtifacts = true
+	resp, err := client.Build(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status)
+	assert.Empty(t, resp.Artifacts)
+	assert.Len(t, resp.ArtifactList, 2)
+
+	infos, data := fetchAll(t, client, "flutter-deferred")
+	require.Len(t, infos, 2)
+	assert.Equal(t, list[0].Checksum, infos[0].Checksum)
+	assert.Equal(t, list[1].Checksum, infos[1].Checksum)
+	assert.Equal(t, []byte("debug"), data[list[0].Path])
+	assert.Equal(t, apk, data[list[1].Path])
+
+	// The cache still holds the archive, so a deferred cache hit is
+	// fetchable under the new task ID.
+	req.TaskId = "flutter-deferred-2"
+	resp, err = client.Build(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, resp.FromCache)
+	assert.Empty(t, resp.Artifacts)
+
+	_, data = fetchAll(t, client, "flutter-deferred-2")
+	assert.Equal(t, apk, data[list[1].Path])
+}
+
+func TestFetchArtifacts_UnknownTask(t *testing.T) {
+	_, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, ArtifactDir: t.TempDir()})
+	defer cleanup()
+
+	stream, err := client.FetchArtifacts(context.Background(), &pb.FetchArtifactsRequest{TaskId: "missing"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestArtifactSpool_Expiry(t *testing.T) {
+	dir := t.TempDir()
+	spool := newArtifactSpool(dir, time.Hour)
+	require.NoError(t, spool.put("task-1", "", []byte("archive"), nil))
+
+	file, _, err := spool.open("task-1", taskAccess(context.Background()))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	spool.evictExpired(time.Now().Add(2 * time.Hour))
+	_, _, err = spool.open("task-1", taskAccess(context.Background()))
+	assert.True(t, os.IsNotExist(err))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
 // --- HealthCheck ---
 
 func TestHealthCheck_NoWorkers(t *testing.T) {
@@ -730,6 +1321,32 @@ func TestReportCacheHit_ZeroHits(t *testing.T) {
 	assert.Equal(t, int64(0), atomic.LoadInt64(&s.cacheHits))
 }
 
+func TestReportLoad(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second})
+	defer cleanup()
+
+	_, err := client.ReportLoad(context.Background(), &pb.ReportLoadRequest{WorkerId: "worker-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	load := &pb.WorkerLoad{CpuUsagePercent: 90, MemoryUsagePercent: 60, LoadAverage_1M: 7.5}
+	resp, err := client.ReportLoad(context.Background(), &pb.ReportLoadRequest{WorkerId: "worker-1", Load: load})
+	require.NoError(t, err)
+	assert.False(t, resp.Accepted, "unregistered workers should handshake again")
+
+	_, err = client.Handshake(context.Background(), &pb.HandshakeRequest{
+		Capabilities: &pb.WorkerCapabilities{WorkerId: "worker-1", Hostname: "laptop", CpuCores: 8},
+	})
+	require.NoError(t, err)
+	resp, err = client.ReportLoad(context.Background(), &pb.ReportLoadRequest{WorkerId: "worker-1", Load: load})
+	require.NoError(t, err)
+	assert.True(t, resp.Accepted)
+
+	w, _ := s.Registry().Get("worker-1")
+	require.NotNil(t, w.Load)
+	assert.Equal(t, float32(90), w.Load.CpuUsagePercent)
+	assert.Equal(t, 7.5, w.Load.LoadAverage_1M)
+}
+
 // --- EventNotifier ---
 
 type mockEventNotifier struct {
@@ -825,3 +1442,117 @@ func TestCompile_WorkerUnreachable(t *testing.T) {
 	assert.Len(t, notifier.completed, 1)
 	assert.Equal(t, "failed", notifier.completed[0].Status)
 }
+
+func TestCancelTask_CancelsRunningCompile(t *testing.T) {
+	workerCancelled := make(chan struct{})
+	addr, workerCleanup := setupMockWorker(t, &mockWorkerBuildService{
+		compileFn: func(ctx context.Context, req *pb.CompileRequest) (*pb.CompileResponse, error) {
+			<-ctx.Done()
+			close(workerCancelled)
+			return nil, ctx.Err()
+		},
+	})
+	defer workerCleanup()
+
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 30 * time.Second})
+	defer cleanup()
+
+	s.registry.Add(&registry.WorkerInfo{
+		ID:          "worker-1",
+		Address:     addr,
+		MaxParallel: 4,
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			Os:         "linux",
+			Cpp:        &pb.CppCapability{Compilers: []string{"gcc"}},
+		},
+	})
+
+	respCh := make(chan *pb.CompileResponse, 1)
+	go func() {
+		resp, err := client.Compile(context.Background(), &pb.CompileRequest{
+			TaskId:             "long-task",
+			Compiler:           "gcc",
+			PreprocessedSource: []byte("int main() { return 0; }"),
+			TargetArch:         pb.Architecture_ARCH_X86_64,
+			ClientOs:           "linux",
+		})
+		assert.NoError(t, err)
+		respCh <- resp
+	}()
+
+	require.Eventually(t, func() bool {
+		w, ok := s.registry.Get("worker-1")
+		return ok && w.ActiveTasks == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancelResp, err := client.CancelTask(context.Background(), &pb.CancelTaskRequest{TaskId: "long-task"})
+	require.NoError(t, err)
+	assert.True(t, cancelResp.Cancelled)
+
+	select {
+	case <-workerCancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancellation did not reach the worker")
+	}
+
+	select {
+	case resp := <-respCh:
+		require.NotNil(t, resp)
+		assert.Equal(t, pb.TaskStatus_STATUS_CANCELLED, resp.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled compile did not return")
+	}
+
+	w, ok := s.registry.Get("worker-1")
+	require.True(t, ok)
+	assert.Equal(t, int32(0), w.ActiveTasks)
+}
+
+func TestCancelTask_RemovesQueuedCompile(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 5 * time.Second})
+	defer cleanup()
+
+	s.registry.Add(&registry.WorkerInfo{
+		ID:          "worker-1",
+		Address:     "127.0.0.1:1",
+		MaxParallel: 1,
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			Os:         "linux",
+			Cpp:        &pb.CppCapability{Compilers: []string{"gcc"}},
+		},
+	})
+	s.registry.IncrementTasks("worker-1")
+
+	respCh := make(chan *pb.CompileResponse, 1)
+	go func() {
+		resp, err := client.Compile(context.Background(), &pb.CompileRequest{
+			TaskId:             "queued-task",
+			Compiler:           "gcc",
+			PreprocessedSource: []byte("int main() { return 0; }"),
+			TargetArch:         pb.Architecture_ARCH_X86_64,
+			ClientOs:           "linux",
+		})
+		assert.NoError(t, err)
+		respCh <- resp
+	}()
+	require.Eventually(t, func() bool { return s.queue.Len() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	cancelResp, err := client.CancelTask(context.Background(), &pb.CancelTaskRequest{TaskId: "queued-task"})
+	require.NoError(t, err)
+	assert.True(t, cancelResp.Cancelled)
+
+	select {
+	case resp := <-respCh:
+		require.NotNil(t, resp)
+		assert.Equal(t, pb.TaskStatus_STATUS_CANCELLED, resp.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled compile did not leave the queue")
+	}
+	assert.Equal(t, 0, s.queue.Len())
+
+	cancelResp, err = client.CancelTask(context.Background(), &pb.CancelTaskRequest{TaskId: "queued-task"})
+	require.NoError(t, err)
+	assert.False(t, cancelResp.Cancelled)
+}
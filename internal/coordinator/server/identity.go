@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/auth"
+)
+
+// tokensReloadInterval is how often the tokens file is checked for changes,
+// which bounds how long a revoked token stays usable.
+const tokensReloadInterval = 5 * time.Second
+
+// methodScopes maps each coordinator RPC to the scope it requires.
+// HealthCheck is open to any caller, and Enroll is authorized by its join
+// token instead.
+var methodScopes = map[string]auth.Scope{
+	pb.BuildService_Handshake_FullMethodName:          auth.ScopeWorkerRegister,
+	pb.BuildService_ReportLoad_FullMethodName:         auth.ScopeWorkerRegister,
+	pb.BuildService_Build_FullMethodName:              auth.ScopeClientSubmit,
+	pb.BuildService_StreamBuild_FullMethodName:        auth.ScopeClientSubmit,
+	pb.BuildService_FetchArtifacts_FullMethodName:     auth.ScopeClientSubmit,
+	pb.BuildService_Compile_FullMethodName:            auth.ScopeClientSubmit,
+	pb.BuildService_GetWorkerStatus_FullMethodName:    auth.ScopeClientSubmit,
+	pb.BuildService_GetWorkersForBuild_FullMethodName: auth.ScopeClientSubmit,
+	pb.BuildService_ReportCacheHit_FullMethodName:     auth.ScopeClientSubmit,
+	pb.BuildService_CancelTask_FullMethodName:         auth.ScopeClientSubmit,
+	pb.BuildService_WatchTask_FullMethodName:          auth.ScopeClientSubmit,
+	pb.BuildService_SubmitBuildGraph_FullMethodName:   auth.ScopeClientSubmit,
+}
+
+// newAuthInterceptor loads the tokens file and starts watching it for
+// changes until ctx is done. It returns nil when no tokens file is
+// configured; the shared AuthToken is then only checked by Handshake.
+func newAuthInterceptor(ctx context.Context, cfg Config) (*auth.Interceptor, error) {
+	if cfg.TokensFile == "" {
+		return nil, nil
+	}
+	store, err := auth.LoadTokenStore(cfg.TokensFile)
+	if err != nil {
+		return nil, err
+	}
+	go store.Watch(ctx, tokensReloadInterval)
+
+	log.Info().Str("path", cfg.TokensFile).Int("tokens", store.Len()).
+		Bool("shared_token", cfg.AuthToken != "").
+		Msg("Token authentication enabled for coordinator gRPC server")
+	return auth.NewInterceptor(auth.Config{
+		Enabled:      true,
+		Token:        cfg.AuthToken,
+		SkipMethods:  []string{pb.BuildService_HealthCheck_FullMethodName, pb.BuildService_Enroll_FullMethodName},
+		Store:        store,
+		MethodScopes: methodScopes,
+	}), nil
+}
+
+// taskOwner returns the name and project of the token that submitted the
+// request in ctx. Both are empty for unauthenticated callers.
+func taskOwner(ctx context.Context) (client, project string) {
+	if id, ok := auth.FromContext(ctx); ok {
+		return id.Name, id.Project
+	}
+	return "", ""
+}
+
+// taskAccess returns which submitters' tasks the caller in ctx may cancel,
+// watch and fetch the artifacts of: its own, or everyone's with the admin
+// scope. Unauthenticated callers reach the tasks of other unauthenticated
+// callers only.
+func taskAccess(ctx context.Context) func(owner string) bool {
+	id, ok := auth.FromContext(ctx)
+	return func(owner string) bool {
+		if !ok {
+			return owner == ""
+		}
+		return owner == id.Name || id.HasScope(auth.ScopeAdmin)
+	}
+}
+
+// notifyTaskStarted attributes event to its submitter and passes it to the
+// event notifier, if one is set.
+func (s *Server) notifyTaskStarted(ctx context.Context, event *TaskEvent) {
+	event.Client, event.Project = taskOwner(ctx)
+	if s.eventNotifier != nil {
+		s.eventNotifier.NotifyTaskStarted(event)
+	}
+}
+
+// notifyTaskCompleted attributes event to its submitter, counts it against
+// the submitter and passes it to the event notifier, if one is set.
+func (s *Server) notifyTaskCompleted(ctx context.Context, event *TaskEvent) {
+	event.Client, event.Project = taskOwner(ctx)
+	client := event.Client
+	if client == "" {
+		client = "anonymous"
+	}
+	metrics.Default().RecordClientTask(client, event.Project, event.BuildType, event.Status)
+	if s.eventNotifier != nil {
+		s.eventNotifier.NotifyTaskCompleted(event)
+	}
+}
@@ -0,0 +1,324 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/auth"
+)
+
+const (
+	workerToken = "worker0000000000000000000000000000000000000000000000000000000000"
+	ciToken     = "ci000000000000000000000000000000000000000000000000000000000000000"
+	sharedToken = "shared00000000000000000000000000000000000000000000000000000000000"
+)
+
+const testTokensFile = `tokens:
+  - name: build-farm
+    scopes: [worker:register]
+    hash: %s
+  - name: ci-android
+    project: mobile
+    scopes: [client:submit]
+    hash: %s
+`
+
+// setupAuthServer is setupTestServer with token authentication installed
+// the way Start installs it.
+func setupAuthServer(t *testing.T, cfg Config) (*Server, pb.BuildServiceClient, func()) {
+	t.Helper()
+
+	cfg.TokensFile = filepath.Join(t.TempDir(), "tokens.yaml")
+	content := []byte(fmt.Sprintf(testTokensFile, auth.HashToken(workerToken), auth.HashToken(ciToken)))
+	require.NoError(t, os.WriteFile(cfg.TokensFile, content, 0o600))
+	if cfg.BuildCacheDir == "" {
+		cfg.BuildCacheDir = t.TempDir()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	interceptor, err := newAuthInterceptor(ctx, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, interceptor)
+
+	lis := bufconn.Listen(bufSize)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptor.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(interceptor.StreamServerInterceptor()),
+	)
+	s := New(cfg)
+	s.authn.Store(interceptor)
+	pb.RegisterBuildServiceServer(srv, s)
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			t.Logf("Server exited: %v", err)
+		}
+	}()
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	return s, pb.NewBuildServiceClient(conn), func() {
+		conn.Close()
+		srv.Stop()
+		s.Stop()
+		cancel()
+	}
+}
+
+func testWorkerCaps(hostname string) *pb.WorkerCapabilities {
+	return &pb.WorkerCapabilities{
+		Hostname:   hostname,
+		NativeArch: pb.Architecture_ARCH_X86_64,
+		Os:         "linux",
+	}
+}
+
+func TestAuth_HandshakeRequiresWorkerScope(t *testing.T) {
+	_, client, cleanup := setupAuthServer(t, Config{HeartbeatTTL: 30 * time.Second})
+	defer cleanup()
+
+	req := &pb.HandshakeRequest{Capabilities: testWorkerCaps("farm-1")}
+
+	_, err := client.Handshake(context.Background(), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.Handshake(auth.ContextWithToken(context.Background(), ciToken), req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	resp, err := client.Handshake(auth.ContextWithToken(context.Background(), workerToken), req)
+	require.NoError(t, err)
+	assert.True(t, resp.Accepted)
+}
+
+func TestAuth_HandshakeAcceptsTokenInRequestBody(t *testing.T) {
+	_, client, cleanup := setupAuthServer(t, Config{HeartbeatTTL: 30 * time.Second, AuthToken: sharedToken})
+	defer cleanup()
+
+	// Workers that predate metadata tokens send the token in the request.
+	resp, err := client.Handshake(context.Background(), &pb.HandshakeRequest{
+		Capabilities: testWorkerCaps("legacy-1"),
+		AuthToken:    sharedToken,
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.Accepted)
+
+	resp, err = client.Handshake(context.Background(), &pb.HandshakeRequest{
+		Capabilities: testWorkerCaps("farm-2"),
+		AuthToken:    workerToken,
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.Accepted)
+}
+
+func TestAuth_SubmitRequiresClientScope(t *testing.T) {
+	_, client, cleanup := setupAuthServer(t, Config{HeartbeatTTL: 30 * time.Second})
+	defer cleanup()
+
+	_, err := client.GetWorkerStatus(auth.ContextWithToken(context.Background(), workerToken), &pb.WorkerStatusRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.GetWorkerStatus(auth.ContextWithToken(context.Background(), ciToken), &pb.WorkerStatusRequest{})
+	assert.NoError(t, err)
+
+	// Streaming RPCs are checked too.
+	stream, err := client.WatchTask(auth.ContextWithToken(context.Background(), workerToken), &pb.WatchTaskRequest{TaskId: "t"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAuth_HealthCheckIsOpen(t *testing.T) {
+	_, client, cleanup := setupAuthServer(t, Config{HeartbeatTTL: 30 * time.Second})
+	defer cleanup()
+
+	resp, err := client.HealthCheck(context.Background(), &pb.HealthRequest{})
+	require.NoError(t, err)
+	assert.True(t, resp.Healthy)
+}
+
+func TestAuth_TasksAreAttributedToToken(t *testing.T) {
+	s, client, cleanup := setupAuthServer(t, Config{HeartbeatTTL: 30 * time.Second, RequestTimeout: 2 * time.Second})
+	defer cleanup()
+
+	notifier := &mockEventNotifier{}
+	s.SetEventNotifier(notifier)
+
+	addr, workerCleanup := setupTestWorker(t, func(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+		return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED}, nil
+	})
+	defer workerCleanup()
+	require.NoError(t, s.registry.Add(rustWorker("rust-host", addr, &pb.RustCapability{
+		Toolchains: []string{"stable-x86_64-unknown-linux-gnu"},
+		Targets:    []string{"x86_64-unknown-linux-gnu"},
+	})))
+
+	resp, err := client.Build(auth.ContextWithToken(context.Background(), ciToken),
+		newRustBuildRequest("attributed", &pb.RustConfig{Toolchain: "stable"}))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status)
+
+	require.Len(t, notifier.started, 1)
+	require.Len(t, notifier.completed, 1)
+	for _, event := range append(notifier.started, notifier.completed...) {
+		assert.Equal(t, "ci-android", event.Client)
+		assert.Equal(t, "mobile", event.Project)
+	}
+}
+
+func TestQueueClientKey_PrefersTokenName(t *testing.T) {
+	ctx := auth.NewContext(context.Background(), &auth.Identity{Name: "ci-android"})
+	assert.Equal(t, "token:ci-android", queueClientKey(ctx))
+	assert.Equal(t, "", queueClientKey(context.Background()))
+}
+
+func TestTaskAccess_OwnerOrAdmin(t *testing.T) {
+	s := New(Config{HeartbeatTTL: 30 * time.Second, BuildCacheDir: t.TempDir()})
+	defer s.Stop()
+
+	alice := auth.NewContext(context.Background(), &auth.Identity{Name: "alice", Scopes: []auth.Scope{auth.ScopeClientSubmit}})
+	bob := auth.NewContext(context.Background(), &auth.Identity{Name: "bob", Scopes: []auth.Scope{auth.ScopeClientSubmit}})
+	admin := auth.NewContext(context.Background(), &auth.Identity{Name: "ops", Scopes: []auth.Scope{auth.ScopeAdmin}})
+
+	taskCtx, release := s.running.Track(context.Background(), "task-1", "alice")
+	defer release()
+	_, err := s.CancelTask(bob, &pb.CancelTaskRequest{TaskId: "task-1"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.NoError(t, taskCtx.Err())
+	resp, err := s.CancelTask(admin, &pb.CancelTaskRequest{TaskId: "task-1"})
+	require.NoError(t, err)
+	assert.True(t, resp.Cancelled)
+
+	require.NoError(t, s.artifacts.put("task-1", "alice", []byte("archive"), nil))
+	_, _, err = s.artifacts.open("task-1", taskAccess(bob))
+	assert.ErrorIs(t, err, errNotArtifactOwner)
+	file, _, err := s.artifacts.open("task-1", taskAccess(alice))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	s.taskOutput.Open("task-1", "alice")
+	s.taskOutput.Close("task-1")
+	send := func(*pb.TaskLogLine) error { return nil }
+	assert.Error(t, s.taskOutput.Serve(bob, "task-1", taskAccess(bob), send))
+	assert.NoError(t, s.taskOutput.Serve(alice, "task-1", taskAccess(alice), send))
+
+	// Without token authentication nobody owns a task.
+	assert.True(t, taskAccess(context.Background())(""))
+	assert.False(t, taskAccess(context.Background())("alice"))
+}
+
+func TestNewAuthInterceptor_WithoutTokensFile(t *testing.T) {
+	interceptor, err := newAuthInterceptor(context.Background(), Config{AuthToken: sharedToken})
+	require.NoError(t, err)
+	assert.Nil(t, interceptor)
+}
+
+func TestNewAuthInterceptor_InvalidTokensFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("tokens:\n  - name: bad\n    scopes: [root]\n    hash: sha256:00\n"), 0o600))
+
+	_, err := newAuthInterceptor(context.Background(), Config{TokensFile: path})
+	assert.Error(t, err)
+}
+
+func peerWithCert(cert *x509.Certificate) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func TestHandshake_WorkerCertAllowlist(t *testing.T) {
+	s := New(Config{
+		HeartbeatTTL:        30 * time.Second,
+		BuildCacheDir:       t.TempDir(),
+		WorkerCertAllowlist: []string{"*.build.example.com"},
+	})
+	defer s.Stop()
+
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "farm-1"},
+		DNSNames: []string{"farm-1.build.example.com"},
+	}
+
+	caps := testWorkerCaps("farm-1")
+	caps.WorkerId = "worker-farm-1"
+	resp, err := s.Handshake(peerWithCert(cert), &pb.HandshakeRequest{Capabilities: caps})
+	require.NoError(t, err)
+	assert.True(t, resp.Accepted, resp.Message)
+	assert.Equal(t, "worker-farm-1", resp.AssignedWorkerId)
+
+	// A worker may not claim a hostname its certificate does not cover.
+	resp, err = s.Handshake(peerWithCert(cert), &pb.HandshakeRequest{Capabilities: testWorkerCaps("farm-2")})
+	require.NoError(t, err)
+	assert.False(t, resp.Accepted)
+	assert.Contains(t, resp.Message, "farm-2")
+
+	// Nor register without a certificate at all.
+	resp, err = s.Handshake(context.Background(), &pb.HandshakeRequest{Capabilities: testWorkerCaps("farm-1")})
+	require.NoError(t, err)
+	assert.False(t, resp.Accepted)
+
+	outsider := &x509.Certificate{Subject: pkix.Name{CommonName: "laptop"}}
+	resp, err = s.Handshake(peerWithCert(outsider), &pb.HandshakeRequest{Capabilities: testWorkerCaps("laptop")})
+	require.NoError(t, err)
+	assert.False(t, resp.Accepted)
+
+	assert.Equal(t, 1, s.registry.Count())
+}
+
+func TestReportLoad_WorkerCertAllowlist(t *testing.T) {
+	s := New(Config{
+		HeartbeatTTL:        30 * time.Second,
+		BuildCacheDir:       t.TempDir(),
+		WorkerCertAllowlist: []string{"*.build.example.com"},
+	})
+	defer s.Stop()
+
+	certA := &x509.Certificate{DNSNames: []string{"farm-a.build.example.com"}}
+	certB := &x509.Certificate{DNSNames: []string{"farm-b.build.example.com"}}
+	for name, cert := range map[string]*x509.Certificate{"farm-a": certA, "farm-b": certB} {
+		caps := testWorkerCaps(name)
+		caps.WorkerId = "worker-" + name
+		resp, err := s.Handshake(peerWithCert(cert), &pb.HandshakeRequest{Capabilities: caps})
+		require.NoError(t, err)
+		require.True(t, resp.Accepted, resp.Message)
+	}
+
+	// Worker B may not report load for worker A.
+	load := &pb.WorkerLoad{CpuUsagePercent: 100}
+	_, err := s.ReportLoad(peerWithCert(certB), &pb.ReportLoadRequest{WorkerId: "worker-farm-a", Load: load})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.ReportLoad(context.Background(), &pb.ReportLoadRequest{WorkerId: "worker-farm-a", Load: load})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	w, _ := s.registry.Get("worker-farm-a")
+	assert.Nil(t, w.Load)
+
+	resp, err := s.ReportLoad(peerWithCert(certA), &pb.ReportLoadRequest{WorkerId: "worker-farm-a", Load: load})
+	require.NoError(t, err)
+	assert.True(t, resp.Accepted)
+}
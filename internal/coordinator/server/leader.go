@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/ha"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+	"github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
+)
+
+// election runs leader election for a coordinator configured with a
+// lease file.
+type election struct {
+	elector *ha.Elector
+	stop    context.CancelFunc
+	done    chan struct{}
+}
+
+// startElection starts competing for the lease. On becoming leader the
+// coordinator reloads the registry and learned scheduler state its
+// predecessor saved; on losing the lease it drops its own unsaved changes
+// so it cannot overwrite the new leader's.
+func (s *Server) startElection() {
+	if s.config.HA.LeaseFile == "" {
+		metrics.Default().SetLeader(true)
+		return
+	}
+
+	e := ha.NewElector(s.config.HA)
+	e.OnChange(func(leader bool) {
+		metrics.Default().SetLeader(leader)
+		if reg, ok := s.registry.(*registry.PersistentRegistry); ok {
+			if err := reg.Reload(); err != nil {
+				log.Warn().Err(err).Msg("Failed to reload worker registry")
+			}
+		}
+		if s.checkpoint != nil {
+			if err := s.checkpoint.Restore(); err != nil {
+				log.Warn().Err(err).Msg("Failed to reload learned scheduler state")
+			}
+		}
+		if leader {
+			s.queue.dispatch()
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.election = &election{elector: e, stop: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(s.election.done)
+		e.Run(ctx)
+	}()
+}
+
+// stopElection releases the lease, after the registry was saved, so a
+// standby coordinator takes over at once.
+func (s *Server) stopElection() {
+	if s.election != nil {
+		s.election.stop()
+		<-s.election.done
+	}
+}
+
+// IsLeader reports whether this coordinator dispatches builds. Without a
+// lease file it always does.
+func (s *Server) IsLeader() bool {
+	return s.election == nil || s.election.elector.IsLeader()
+}
+
+// OnLeadershipChange calls fn with the current role, then whenever it
+// changes. Without a lease file it is called once, with true.
+func (s *Server) OnLeadershipChange(fn func(leader bool)) {
+	if s.election == nil {
+		fn(true)
+		return
+	}
+	s.election.elector.OnChange(fn)
+}
+
+// checkLeader rejects calls to a standby coordinator with Unavailable,
+// naming the leader, so clients move on to the next coordinator.
+func (s *Server) checkLeader() error {
+	if s.IsLeader() {
+		return nil
+	}
+	if lease := s.election.elector.Leader(); lease.Address != "" {
+		return status.Errorf(codes.Unavailable, "coordinator is a standby; the leader is %s", lease.Address)
+	}
+	return status.Error(codes.Unavailable, "coordinator is a standby")
+}
+
+// leaderOptions returns the server options rejecting calls while this
+// coordinator is a standby. Every answer carries the coordinator's role in
+// the ha.RoleMetadataKey trailer.
+func (s *Server) leaderOptions() []grpc.ServerOption {
+	if s.election == nil {
+		return nil
+	}
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			err := s.checkLeader()
+			_ = grpc.SetTrailer(ctx, roleTrailer(err))
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			err := s.checkLeader()
+			ss.SetTrailer(roleTrailer(err))
+			if err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+// roleTrailer reports the role checkLeader found, given its result.
+func roleTrailer(checkErr error) metadata.MD {
+	if checkErr != nil {
+		return metadata.Pairs(ha.RoleMetadataKey, ha.RoleStandby)
+	}
+	return metadata.Pairs(ha.RoleMetadataKey, ha.RoleLeader)
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/ha"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+)
+
+// haConfig configures coordinator id to share the lease and registry in dir.
+func haConfig(dir, id string) Config {
+	return Config{
+		HeartbeatTTL: 30 * time.Second,
+		RegistryFile: filepath.Join(dir, "workers.json"),
+		HA: ha.Config{
+			LeaseFile: filepath.Join(dir, "leader.lease"),
+			LeaseTTL:  300 * time.Millisecond,
+			ID:        id,
+			Address:   id + ":9000",
+		},
+	}
+}
+
+func TestHA_StandbyTakesOver(t *testing.T) {
+	dir := t.TempDir()
+	req := &pb.HandshakeRequest{
+		Capabilities:  &pb.WorkerCapabilities{WorkerId: "ha-worker", Hostname: "host"},
+		WorkerAddress: "10.0.0.5:50052",
+	}
+
+	a, clientA, cleanupA := setupTestServer(t, haConfig(dir, "coord-a"))
+	require.Eventually(t, a.IsLeader, 2*time.Second, 10*time.Millisecond)
+
+	b, clientB, cleanupB := setupTestServer(t, haConfig(dir, "coord-b"))
+	defer cleanupB()
+
+	var trailer metadata.MD
+	resp, err := clientA.Handshake(context.Background(), req, grpc.Trailer(&trailer))
+	require.NoError(t, err)
+	require.True(t, resp.Accepted)
+	assert.Equal(t, []string{ha.RoleLeader}, trailer.Get(ha.RoleMetadataKey))
+
+	// The standby sends callers to the leader
+	_, err = clientB.Handshake(context.Background(), req)
+	require.Error(t, err)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Contains(t, err.Error(), "coord-a:9000")
+	_, err = clientB.HealthCheck(context.Background(), &pb.HealthRequest{}, grpc.Trailer(&trailer))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, []string{ha.RoleStandby}, trailer.Get(ha.RoleMetadataKey))
+	assert.False(t, b.IsLeader())
+
+	// Stopping the leader saves the registry and hands over the lease
+	cleanupA()
+	require.Eventually(t, b.IsLeader, 2*time.Second, 10*time.Millisecond)
+
+	w, ok := b.Registry().Get("ha-worker")
+	require.True(t, ok, "new leader should pick up the worker")
+	assert.Equal(t, registry.WorkerStateUnknown, w.State)
+
+	resp, err = clientB.Handshake(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, resp.Accepted)
+	w, _ = b.Registry().Get("ha-worker")
+	assert.Equal(t, registry.WorkerStateIdle, w.State)
+}
+
+func TestHA_Disabled(t *testing.T) {
+	s := New(Config{HeartbeatTTL: 30 * time.Second, BuildCacheDir: t.TempDir()})
+	defer s.Stop()
+
+	assert.True(t, s.IsLeader())
+	assert.Nil(t, s.leaderOptions())
+
+	var roles []bool
+	s.OnLeadershipChange(func(leader bool) { roles = append(roles, leader) })
+	assert.Equal(t, []bool{true}, roles)
+}
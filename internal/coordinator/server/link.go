@@ -0,0 +1,66 @@
+package server
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+)
+
+// selectLinkWorker picks the least-loaded worker that can run req's link
+// natively. Links are not cross-compiled: the worker must run the client's
+// OS and architecture and have a compiler of the same family.
+func (s *Server) selectLinkWorker(req *pb.CompileRequest) (*registry.WorkerInfo, error) {
+	candidates := s.registry.ListByCapability(pb.BuildType_BUILD_TYPE_CPP, req.TargetArch)
+
+	workers := make([]*registry.WorkerInfo, 0, len(candidates))
+	for _, w := range candidates {
+		if workerSupportsLink(w, req) {
+			workers = append(workers, w)
+		}
+	}
+	if len(workers) == 0 {
+		return nil, fmt.Errorf("no %s/%s worker with %s can run the link", req.ClientOs, req.TargetArch, req.Compiler)
+	}
+	return pickLeastLoaded(workers)
+}
+
+// workerSupportsLink checks OS, native architecture and compiler family.
+// Generic driver names such as cc or c++ match any C/C++ worker.
+func workerSupportsLink(worker *registry.WorkerInfo, req *pb.CompileRequest) bool {
+	caps := worker.Capabilities
+	if caps == nil || caps.Cpp == nil {
+		return false
+	}
+	if req.ClientOs == "" || caps.Os != req.ClientOs {
+		return false
+	}
+	if req.TargetArch != pb.Architecture_ARCH_UNSPECIFIED && caps.NativeArch != req.TargetArch {
+		return false
+	}
+
+	family := compilerFamily(req.Compiler)
+	if family == "" {
+		return true
+	}
+	for _, c := range caps.Cpp.Compilers {
+		if c == family {
+			return true
+		}
+	}
+	return false
+}
+
+// compilerFamily maps a compiler path such as /usr/bin/x86_64-linux-gnu-g++-12
+// to the name workers report in their capabilities, or "" if unknown.
+func compilerFamily(compiler string) string {
+	base := filepath.Base(compiler)
+	for _, family := range []string{"clang++", "clang", "g++", "gcc"} {
+		if strings.Contains(base, family) {
+			return family
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+)
+
+func TestWorkerSupportsLink(t *testing.T) {
+	worker := &registry.WorkerInfo{
+		ID: "w1",
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			Os:         "linux",
+			Cpp:        &pb.CppCapability{Compilers: []string{"gcc", "g++"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		compiler string
+		os       string
+		arch     pb.Architecture
+		want     bool
+	}{
+		{"matching toolchain", "g++", "linux", pb.Architecture_ARCH_X86_64, true},
+		{"versioned path", "/usr/bin/x86_64-linux-gnu-g++-12", "linux", pb.Architecture_ARCH_X86_64, true},
+		{"generic driver", "c++", "linux", pb.Architecture_ARCH_X86_64, true},
+		{"missing compiler", "clang++", "linux", pb.Architecture_ARCH_X86_64, false},
+		{"different os", "g++", "darwin", pb.Architecture_ARCH_X86_64, false},
+		{"unknown os", "g++", "", pb.Architecture_ARCH_X86_64, false},
+		{"different arch", "g++", "linux", pb.Architecture_ARCH_ARM64, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &pb.CompileRequest{Compiler: tt.compiler, ClientOs: tt.os, TargetArch: tt.arch, Link: true}
+			assert.Equal(t, tt.want, workerSupportsLink(worker, req))
+		})
+	}
+
+	assert.False(t, workerSupportsLink(&registry.WorkerInfo{ID: "no-caps"}, &pb.CompileRequest{Compiler: "gcc", ClientOs: "linux"}))
+}
@@ -0,0 +1,391 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/peer"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+	"github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
+)
+
+const (
+	defaultMaxQueueDepth = 1000
+	defaultQueueTimeout  = 2 * time.Minute
+
+	// queuePollInterval re-runs dispatch for capacity changes the queue is
+	// not told about, such as a worker recovering from a missed heartbeat.
+	queuePollInterval = 250 * time.Millisecond
+
+	maxQueuePriority = 100
+)
+
+var (
+	// errWorkersBusy is returned by a pick function when workers that could
+	// run the task exist but all of them are at capacity. The task stays
+	// queued until a slot frees up.
+	errWorkersBusy  = errors.New("all matching workers are at capacity")
+	errQueueFull    = errors.New("dispatch queue is full")
+	errQueueTimeout = errors.New("timed out waiting for a free worker")
+)
+
+// queuedTask is a request waiting in the dispatch queue.
+type queuedTask struct {
+	priority  int32
+	client    string
+	buildType string
+	// rank is the task's upward rank in its build graph, 0 if unknown.
+	// A client's tasks are dispatched in decreasing rank.
+	rank float64
+	// pick selects a worker for the task. It runs with the queue locked, so
+	// the selected worker is booked before any other task is considered.
+	pick func() (*registry.WorkerInfo, error)
+
+	seq      uint64
+	enqueued time.Time
+	done     chan struct{}
+	resolved bool
+	worker   *registry.WorkerInfo
+	wait     time.Duration
+	err      error
+}
+
+// queueClient holds one client's tasks within a priority lane. start is the
+// client's virtual time: it advances by one per dispatched task, so a client
+// with a long backlog does not starve clients that submit a few tasks.
+type queueClient struct {
+	tasks []*queuedTask
+	start uint64
+}
+
+// queueLane holds the tasks of one priority. vtime is the virtual time of
+// the most recent dispatch; clients joining the lane start from it.
+type queueLane struct {
+	clients map[string]*queueClient
+	vtime   uint64
+}
+
+// dispatchQueue holds requests until a worker has a free slot for them.
+// Higher priorities are served first; within a priority, clients take
+// turns and each client's tasks run in decreasing rank, then submission
+// order.
+type dispatchQueue struct {
+	mu       sync.Mutex
+	lanes    map[int32]*queueLane
+	depth    int
+	seq      uint64
+	maxDepth int
+	timeout  time.Duration
+	// reserve books a task on the picked worker.
+	reserve func(worker *registry.WorkerInfo)
+	// hasFreeSlot reports whether any worker could accept another task.
+	hasFreeSlot func() bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newDispatchQueue(maxDepth int, timeout time.Duration, reserve func(*registry.WorkerInfo), hasFreeSlot func() bool) *dispatchQueue {
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxQueueDepth
+	}
+	if timeout <= 0 {
+		timeout = defaultQueueTimeout
+	}
+	q := &dispatchQueue{
+		lanes:       make(map[int32]*queueLane),
+		maxDepth:    maxDepth,
+		timeout:     timeout,
+		reserve:     reserve,
+		hasFreeSlot: hasFreeSlot,
+		stop:        make(chan struct{}),
+	}
+	go q.poll()
+	return q
+}
+
+func (q *dispatchQueue) poll() {
+	ticker := time.NewTicker(queuePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-ticker.C:
+			q.dispatch()
+		}
+	}
+}
+
+func (q *dispatchQueue) close() {
+	q.stopOnce.Do(func() { close(q.stop) })
+}
+
+// Len returns the number of tasks waiting for a worker.
+func (q *dispatchQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.depth
+}
+
+// acquire queues t and blocks until a worker has been booked for it, its
+// pick function fails, the queue timeout expires or ctx is done. It returns
+// the booked worker and how long the task waited.
+func (q *dispatchQueue) acquire(ctx context.Context, t *queuedTask) (*registry.WorkerInfo, time.Duration, error) {
+	if t.priority < 0 {
+		t.priority = 0
+	} else if t.priority > maxQueuePriority {
+		t.priority = maxQueuePriority
+	}
+	t.enqueued = time.Now()
+	t.done = make(chan struct{})
+
+	q.mu.Lock()
+	if q.depth >= q.maxDepth {
+		q.mu.Unlock()
+		return nil, 0, errQueueFull
+	}
+	q.push(t)
+	q.dispatchLocked(t)
+	q.mu.Unlock()
+
+	timer := time.NewTimer(q.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		q.cancel(t, ctx.Err())
+	case <-timer.C:
+		q.cancel(t, errQueueTimeout)
+	}
+	<-t.done
+	return t.worker, t.wait, t.err
+}
+
+// dispatch hands free worker slots to queued tasks.
+func (q *dispatchQueue) dispatch() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.dispatchLocked(nil)
+}
+
+// dispatchLocked offers each queued task, in dispatch order, to its pick
+// function. Booking only reduces free capacity, so a single pass suffices:
+// a task that found its workers busy would find them busy again. The pass
+// stops once no worker has a free slot, so a long queue does not cost a
+// scheduler call per task while the pool is saturated. arrival, the task
+// just queued if any, is still offered, so that a task no worker could
+// ever run fails straight away rather than at the queue timeout.
+func (q *dispatchQueue) dispatchLocked(arrival *queuedTask) {
+	if q.depth == 0 {
+		return
+	}
+	if !q.hasFreeSlot() {
+		if arrival != nil {
+			q.offer(arrival)
+		}
+		return
+	}
+	for _, t := range q.order() {
+		if q.offer(t) && !q.hasFreeSlot() {
+			return
+		}
+	}
+}
+
+// offer runs t's pick function and, unless the matching workers are all
+// busy, removes t from the queue and resolves it. It reports whether a
+// worker was booked.
+func (q *dispatchQueue) offer(t *queuedTask) bool {
+	worker, err := t.pick()
+	if errors.Is(err, errWorkersBusy) {
+		return false
+	}
+	q.remove(t, true)
+	if err == nil {
+		q.reserve(worker)
+	}
+	q.resolve(t, worker, err)
+	return err == nil
+}
+
+// cancel removes t from the queue unless it has already been dispatched.
+func (q *dispatchQueue) cancel(t *queuedTask, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if t.resolved {
+		return
+	}
+	q.remove(t, false)
+	q.resolve(t, nil, err)
+}
+
+func (q *dispatchQueue) push(t *queuedTask) {
+	lane, ok := q.lanes[t.priority]
+	if !ok {
+		lane = &queueLane{clients: make(map[string]*queueClient)}
+		q.lanes[t.priority] = lane
+	}
+	c, ok := lane.clients[t.client]
+	if !ok {
+		c = &queueClient{start: lane.vtime}
+		lane.clients[t.client] = c
+	}
+	q.seq++
+	t.seq = q.seq
+	// Insert after every task ranked at least as high, so tasks on the
+	// critical path of a build overtake the client's other tasks.
+	i := len(c.tasks)
+	for i > 0 && c.tasks[i-1].rank < t.rank {
+		i--
+	}
+	c.tasks = append(c.tasks, nil)
+	copy(c.tasks[i+1:], c.tasks[i:])
+	c.tasks[i] = t
+	q.depth++
+	metrics.Default().SetQueueDepth(float64(q.depth))
+}
+
+// remove takes t out of its lane. When dispatched is set the client is
+// charged one turn and the lane's virtual time advances.
+func (q *dispatchQueue) remove(t *queuedTask, dispatched bool) {
+	lane := q.lanes[t.priority]
+	c := lane.clients[t.client]
+	for i, queued := range c.tasks {
+		if queued != t {
+			continue
+		}
+		c.tasks = append(c.tasks[:i], c.tasks[i+1:]...)
+		if dispatched {
+			if finish := c.start + uint64(i); finish > lane.vtime {
+				lane.vtime = finish
+			}
+			c.start++
+		}
+		break
+	}
+	if len(c.tasks) == 0 {
+		delete(lane.clients, t.client)
+	}
+	if len(lane.clients) == 0 {
+		delete(q.lanes, t.priority)
+	}
+	q.depth--
+	metrics.Default().SetQueueDepth(float64(q.depth))
+}
+
+func (q *dispatchQueue) resolve(t *queuedTask, worker *registry.WorkerInfo, err error) {
+	t.resolved = true
+	t.worker = worker
+	t.err = err
+	t.wait = time.Since(t.enqueued)
+	if worker != nil {
+		metrics.Default().RecordQueueTime(t.buildType, t.wait.Seconds())
+	}
+	close(t.done)
+}
+
+// order returns the queued tasks in dispatch order: by priority, then by
+// each client's virtual finish time, then by arrival.
+func (q *dispatchQueue) order() []*queuedTask {
+	priorities := make([]int32, 0, len(q.lanes))
+	for p := range q.lanes {
+		priorities = append(priorities, p)
+	}
+	sort.Slice(priorities, func(i, j int) bool { return priorities[i] > priorities[j] })
+
+	type entry struct {
+		task   *queuedTask
+		finish uint64
+	}
+	ordered := make([]*queuedTask, 0, q.depth)
+	for _, p := range priorities {
+		var entries []entry
+		for _, c := range q.lanes[p].clients {
+			for i, t := range c.tasks {
+				entries = append(entries, entry{task: t, finish: c.start + uint64(i)})
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].finish != entries[j].finish {
+				return entries[i].finish < entries[j].finish
+			}
+			return entries[i].task.seq < entries[j].task.seq
+		})
+		for _, e := range entries {
+			ordered = append(ordered, e.task)
+		}
+	}
+	return ordered
+}
+
+// queueClientKey identifies the submitting client for fair share. Callers
+// authenticated with a named token are told apart by its name; others by
+// host, so parallel jobs from one machine share a turn.
+func queueClientKey(ctx context.Context) string {
+	if client, _ := taskOwner(ctx); client != "" {
+		return "token:" + client
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// workerHasFreeSlot reports whether worker can accept another task.
+func workerHasFreeSlot(worker *registry.WorkerInfo) bool {
+	maxParallel := worker.MaxParallel
+	if maxParallel <= 0 {
+		maxParallel = 4
+	}
+	return worker.ActiveTasks < maxParallel
+}
+
+// pickLeastLoaded returns the least-loaded worker with a free slot, breaking
+// ties by ID, or errWorkersBusy if every worker is at capacity.
+func pickLeastLoaded(workers []*registry.WorkerInfo) (*registry.WorkerInfo, error) {
+	var best *registry.WorkerInfo
+	for _, w := range workers {
+		if !workerHasFreeSlot(w) {
+			continue
+		}
+		if best == nil || w.ActiveTasks < best.ActiveTasks ||
+			(w.ActiveTasks == best.ActiveTasks && w.ID < best.ID) {
+			best = w
+		}
+	}
+	if best == nil {
+		return nil, errWorkersBusy
+	}
+	return best, nil
+}
+
+// acquireWorker waits in the dispatch queue until pick books a worker.
+// rank orders the task among its client's queued tasks; pass 0 if unknown.
+func (s *Server) acquireWorker(ctx context.Context, buildType string, priority int32, rank float64, pick func() (*registry.WorkerInfo, error)) (*registry.WorkerInfo, time.Duration, error) {
+	return s.queue.acquire(ctx, &queuedTask{
+		priority:  priority,
+		client:    queueClientKey(ctx),
+		buildType: buildType,
+		rank:      rank,
+		pick:      pick,
+	})
+}
+
+// releaseWorker books a finished task off worker and hands its slot to the
+// next queued task.
+func (s *Server) releaseWorker(workerID string, success bool, duration time.Duration) {
+	s.registry.DecrementTasks(workerID, success, duration)
+	s.queue.dispatch()
+}
@@ -0,0 +1,256 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+)
+
+// queueHarness drives a dispatchQueue with a fixed number of free slots and
+// records the order in which tasks are dispatched.
+type queueHarness struct {
+	q     *dispatchQueue
+	slots int64
+	picks int64
+
+	mu    sync.Mutex
+	order []string
+}
+
+func newQueueHarness(t *testing.T, maxDepth int, timeout time.Duration) *queueHarness {
+	h := &queueHarness{}
+	h.q = newDispatchQueue(maxDepth, timeout, func(w *registry.WorkerInfo) {
+		atomic.AddInt64(&h.slots, -1)
+		h.mu.Lock()
+		h.order = append(h.order, w.ID)
+		h.mu.Unlock()
+	}, func() bool {
+		return atomic.LoadInt64(&h.slots) > 0
+	})
+	t.Cleanup(h.q.close)
+	return h
+}
+
+// submit queues a task named id and waits until it is in the queue.
+func (h *queueHarness) submit(t *testing.T, id, client string, priority int32) <-chan error {
+	t.Helper()
+	return h.submitTask(t, id, &queuedTask{priority: priority, client: client})
+}
+
+// submitTask queues task under the name id and waits until it is in the
+// queue.
+func (h *queueHarness) submitTask(t *testing.T, id string, task *queuedTask) <-chan error {
+	t.Helper()
+	want := h.q.Len() + 1
+	errCh := make(chan error, 1)
+	task.buildType = "cpp"
+	task.pick = func() (*registry.WorkerInfo, error) {
+		atomic.AddInt64(&h.picks, 1)
+		if atomic.LoadInt64(&h.slots) <= 0 {
+			return nil, errWorkersBusy
+		}
+		return &registry.WorkerInfo{ID: id}, nil
+	}
+	go func() {
+		_, _, err := h.q.acquire(context.Background(), task)
+		errCh <- err
+	}()
+	require.Eventually(t, func() bool { return h.q.Len() == want }, time.Second, time.Millisecond)
+	return errCh
+}
+
+func (h *queueHarness) release(n int64) {
+	atomic.AddInt64(&h.slots, n)
+	h.q.dispatch()
+}
+
+func (h *queueHarness) dispatched() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.order...)
+}
+
+func TestDispatchQueue_HigherPriorityFirst(t *testing.T) {
+	h := newQueueHarness(t, 10, time.Minute)
+
+	h.submit(t, "low", "client", 0)
+	h.submit(t, "mid", "client", 50)
+	h.submit(t, "high", "client", 100)
+
+	h.release(3)
+	require.Eventually(t, func() bool { return h.q.Len() == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"high", "mid", "low"}, h.dispatched())
+}
+
+func TestDispatchQueue_FairShareAcrossClients(t *testing.T) {
+	h := newQueueHarness(t, 10, time.Minute)
+
+	h.submit(t, "a1", "host-a", 0)
+	h.submit(t, "a2", "host-a", 0)
+	h.submit(t, "a3", "host-a", 0)
+	h.submit(t, "b1", "host-b", 0)
+
+	// One slot at a time: b1 must not wait behind host-a's whole backlog.
+	for i := 0; i < 4; i++ {
+		h.release(1)
+	}
+	assert.Equal(t, []string{"a1", "b1", "a2", "a3"}, h.dispatched())
+}
+
+func TestDispatchQueue_HigherRankFirstWithinClient(t *testing.T) {
+	h := newQueueHarness(t, 10, time.Minute)
+
+	h.submitTask(t, "a1", &queuedTask{client: "host-a", rank: 0.2})
+	h.submitTask(t, "a2", &queuedTask{client: "host-a", rank: 1})
+	h.submitTask(t, "a3", &queuedTask{client: "host-a", rank: 0.5})
+	h.submitTask(t, "b1", &queuedTask{client: "host-b"})
+
+	// The critical task overtakes its client's backlog but not host-b's
+	// turn.
+	for i := 0; i < 4; i++ {
+		h.release(1)
+	}
+	assert.Equal(t, []string{"a2", "b1", "a3", "a1"}, h.dispatched())
+}
+
+func TestDispatchQueue_Timeout(t *testing.T) {
+	h := newQueueHarness(t, 10, 20*time.Millisecond)
+
+	errCh := h.submit(t, "stuck", "client", 0)
+	select {
+	case err := <-errCh:
+		assert.ErrorIs(t, err, errQueueTimeout)
+	case <-time.After(time.Second):
+		t.Fatal("queued task did not time out")
+	}
+	assert.Equal(t, 0, h.q.Len())
+}
+
+func TestDispatchQueue_Full(t *testing.T) {
+	h := newQueueHarness(t, 1, time.Minute)
+
+	h.submit(t, "first", "client", 0)
+	_, _, err := h.q.acquire(context.Background(), &queuedTask{
+		pick: func() (*registry.WorkerInfo, error) { return nil, errWorkersBusy },
+	})
+	assert.ErrorIs(t, err, errQueueFull)
+}
+
+func TestDispatchQueue_CancelledContextLeavesQueue(t *testing.T) {
+	h := newQueueHarness(t, 10, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, _, err := h.q.acquire(ctx, &queuedTask{
+			pick: func() (*registry.WorkerInfo, error) { return nil, errWorkersBusy },
+		})
+		errCh <- err
+	}()
+	require.Eventually(t, func() bool { return h.q.Len() == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+	assert.Equal(t, 0, h.q.Len())
+}
+
+func TestDispatchQueue_SaturatedPoolSkipsPicks(t *testing.T) {
+	h := newQueueHarness(t, 10, time.Minute)
+
+	// Each task is offered once, when it arrives.
+	h.submit(t, "a", "client", 0)
+	h.submit(t, "b", "client", 0)
+	h.submit(t, "c", "client", 0)
+	assert.EqualValues(t, 3, atomic.LoadInt64(&h.picks))
+
+	h.q.dispatch()
+	assert.EqualValues(t, 3, atomic.LoadInt64(&h.picks))
+
+	// The pass stops once the only free slot has been booked.
+	h.release(1)
+	assert.Equal(t, []string{"a"}, h.dispatched())
+	assert.EqualValues(t, 4, atomic.LoadInt64(&h.picks))
+}
+
+func TestDispatchQueue_SaturatedPoolFailsUnrunnableArrival(t *testing.T) {
+	h := newQueueHarness(t, 10, time.Minute)
+	h.submit(t, "waiting", "client", 0)
+
+	errNoWorker := errors.New("no worker matches")
+	_, _, err := h.q.acquire(context.Background(), &queuedTask{
+		pick: func() (*registry.WorkerInfo, error) { return nil, errNoWorker },
+	})
+	assert.ErrorIs(t, err, errNoWorker)
+	assert.Equal(t, 1, h.q.Len())
+}
+
+func TestCompile_WaitsForSaturatedWorker(t *testing.T) {
+	addr, workerCleanup := setupMockWorker(t, &mockWorkerBuildService{
+		compileFn: func(ctx context.Context, req *pb.CompileRequest) (*pb.CompileResponse, error) {
+			return &pb.CompileResponse{
+				Status:     pb.TaskStatus_STATUS_COMPLETED,
+				ObjectFile: []byte("object"),
+			}, nil
+		},
+	})
+	defer workerCleanup()
+
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 5 * time.Second})
+	defer cleanup()
+
+	s.registry.Add(&registry.WorkerInfo{
+		ID:          "worker-1",
+		Address:     addr,
+		MaxParallel: 1,
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			Os:         "linux",
+			Cpp:        &pb.CppCapability{Compilers: []string{"gcc"}},
+		},
+	})
+	// Occupy the worker's only slot.
+	s.registry.IncrementTasks("worker-1")
+
+	type result struct {
+		resp *pb.CompileResponse
+		err  error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		resp, err := client.Compile(context.Background(), &pb.CompileRequest{
+			TaskId:             "queued",
+			Compiler:           "gcc",
+			PreprocessedSource: []byte("int main() { return 0; }"),
+			TargetArch:         pb.Architecture_ARCH_X86_64,
+			ClientOs:           "linux",
+		})
+		resultCh <- result{resp, err}
+	}()
+
+	require.Eventually(t, func() bool { return s.queue.Len() == 1 }, time.Second, time.Millisecond)
+
+	health, err := client.HealthCheck(context.Background(), &pb.HealthRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), health.QueuedTasks)
+
+	s.releaseWorker("worker-1", true, 0)
+
+	select {
+	case r := <-resultCh:
+		require.NoError(t, r.err)
+		assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, r.resp.Status)
+		assert.Equal(t, []byte("object"), r.resp.ObjectFile)
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued compile was not dispatched")
+	}
+	assert.Equal(t, 0, s.queue.Len())
+}
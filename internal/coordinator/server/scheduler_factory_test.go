@@ -26,7 +26,7 @@ func TestNewScheduler_KnownTypes(t *testing.T) {
 
 	for typ, want := range cases {
 		t.Run(typ, func(t *testing.T) {
-			got := newScheduler(Config{SchedulerType: typ}, reg, cm)
+			got := NewScheduler(Config{SchedulerType: typ}, reg, cm)
 			assert.NotNil(t, got)
 			assert.IsType(t, want, got)
 		})
@@ -38,7 +38,7 @@ func TestNewScheduler_UnknownTypeFallsBack(t *testing.T) {
 	defer reg.Stop()
 	cm := resilience.NewCircuitManager(resilience.DefaultCircuitConfig())
 
-	got := newScheduler(Config{SchedulerType: "does-not-exist"}, reg, cm)
+	got := NewScheduler(Config{SchedulerType: "does-not-exist"}, reg, cm)
 	assert.IsType(t, (*scheduler.LeastLoadedScheduler)(nil), got)
 }
 
@@ -51,6 +51,6 @@ func TestNewScheduler_EpsilonValueRespected(t *testing.T) {
 	defer reg.Stop()
 	cm := resilience.NewCircuitManager(resilience.DefaultCircuitConfig())
 
-	got := newScheduler(Config{SchedulerType: "epsilon-greedy", EpsilonValue: 0.5}, reg, cm)
+	got := NewScheduler(Config{SchedulerType: "epsilon-greedy", EpsilonValue: 0.5}, reg, cm)
 	assert.IsType(t, (*scheduler.EpsilonGreedyScheduler)(nil), got)
 }
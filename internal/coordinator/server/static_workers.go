@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+)
+
+// staticProbeTimeout bounds connecting to a static worker and asking for
+// its status.
+const staticProbeTimeout = 10 * time.Second
+
+// StaticWorker is a worker the coordinator registers itself instead of
+// waiting for a handshake, such as a build farm in another office that
+// cannot discover or reach the coordinator.
+type StaticWorker struct {
+	// Address is the worker's gRPC address (host:port).
+	Address string
+	// LAN marks a worker on the local network. Static workers are
+	// otherwise tagged as WAN workers.
+	LAN bool
+	// BandwidthMbps is the bandwidth to the worker, used by schedulers to
+	// estimate upload times. Zero if unknown.
+	BandwidthMbps float64
+}
+
+// staticWorkers probes the configured static workers.
+type staticWorkers struct {
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+// startStaticWorkers registers the configured static workers and keeps
+// them alive by asking each for its status three times per heartbeat TTL,
+// measuring the round trip for the schedulers. Only the leader probes, so
+// a standby does not write to a shared registry.
+func (s *Server) startStaticWorkers() {
+	if len(s.config.StaticWorkers) == 0 {
+		return
+	}
+
+	interval := s.config.HeartbeatTTL / 3
+	if interval <= 0 {
+		interval = 20 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.static = &staticWorkers{stop: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(s.static.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if s.IsLeader() {
+				s.probeStaticWorkers(ctx)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stopStaticWorkers stops probing and waits for probes in flight.
+func (s *Server) stopStaticWorkers() {
+	if s.static != nil {
+		s.static.stop()
+		<-s.static.done
+	}
+}
+
+// probeStaticWorkers probes every static worker concurrently. A worker
+// that does not answer is left to expire like one that stopped
+// heartbeating.
+func (s *Server) probeStaticWorkers(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, sw := range s.config.StaticWorkers {
+		wg.Add(1)
+		go func(sw StaticWorker) {
+			defer wg.Done()
+			if err := s.probeStaticWorker(ctx, sw); err != nil && ctx.Err() == nil {
+				log.Warn().Err(err).Str("address", sw.Address).Msg("Static worker probe failed")
+			}
+		}(sw)
+	}
+	wg.Wait()
+}
+
+// probeStaticWorker asks a static worker for its status and registers it,
+// as a handshake would, along with the host load it reports.
+func (s *Server) probeStaticWorker(ctx context.Context, sw StaticWorker) error {
+	ctx, cancel := context.WithTimeout(ctx, staticProbeTimeout)
+	defer cancel()
+
+	conn, err := s.workerConns.get(ctx, sw.Address)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	start := time.Now()
+	resp, err := pb.NewBuildServiceClient(conn).GetWorkerStatus(ctx, &pb.WorkerStatusRequest{})
+	rtt := time.Since(start)
+	if err != nil {
+		return err
+	}
+	if len(resp.Workers) == 0 || resp.Workers[0].Capabilities == nil {
+		return errors.New("worker does not report its capabilities; upgrade it")
+	}
+
+	caps := resp.Workers[0].Capabilities
+	workerID := caps.WorkerId
+	if workerID == "" {
+		workerID = "worker-" + sw.Address
+	}
+	maxParallel := caps.MaxParallelTasks
+	if maxParallel <= 0 {
+		maxParallel = 4
+	}
+	source := registry.DiscoverySourceWAN
+	if sw.LAN {
+		source = registry.DiscoverySourceLAN
+	}
+
+	_, known := s.registry.Get(workerID)
+	err = s.registry.Add(&registry.WorkerInfo{
+		ID:              workerID,
+		Address:         sw.Address,
+		Capabilities:    caps,
+		MaxParallel:     maxParallel,
+		DiscoverySource: source,
+		RTT:             rtt,
+		BandwidthMbps:   sw.BandwidthMbps,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register worker: %w", err)
+	}
+	if load := resp.Workers[0].Load; load != nil {
+		if err := s.registry.UpdateLoad(workerID, load); err != nil {
+			return fmt.Errorf("failed to store worker load: %w", err)
+		}
+	}
+
+	// Queued tasks may be waiting for exactly this worker.
+	s.queue.dispatch()
+
+	if !known {
+		log.Info().
+			Str("worker_id", workerID).
+			Str("address", sw.Address).
+			Str("source", source).
+			Dur("rtt", rtt).
+			Int32("cpu_cores", caps.CpuCores).
+			Int32("max_parallel", maxParallel).
+			Msg("Static worker registered")
+	}
+	return nil
+}
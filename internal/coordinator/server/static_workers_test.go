@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+)
+
+// staticWorkerStatus answers GetWorkerStatus like an hg-worker.
+func staticWorkerStatus(id string) func(context.Context, *pb.WorkerStatusRequest) (*pb.WorkerStatusResponse, error) {
+	return func(context.Context, *pb.WorkerStatusRequest) (*pb.WorkerStatusResponse, error) {
+		return &pb.WorkerStatusResponse{
+			Workers: []*pb.WorkerStatusResponse_WorkerInfo{{
+				WorkerId: id,
+				Capabilities: &pb.WorkerCapabilities{
+					WorkerId:         id,
+					Hostname:         "farm-1",
+					CpuCores:         32,
+					NativeArch:       pb.Architecture_ARCH_X86_64,
+					MaxParallelTasks: 16,
+					Cpp:              &pb.CppCapability{Compilers: []string{"gcc"}},
+				},
+				Load: &pb.WorkerLoad{CpuUsagePercent: 30, DiskFreeBytes: 1 << 40},
+			}},
+			TotalWorkers:   1,
+			HealthyWorkers: 1,
+		}, nil
+	}
+}
+
+func TestStaticWorkers_RegisteredAsWAN(t *testing.T) {
+	addr, stopWorker := setupMockWorker(t, &mockWorkerBuildService{statusFn: staticWorkerStatus("worker-farm-1")})
+	defer stopWorker()
+
+	cfg := DefaultConfig()
+	cfg.StaticWorkers = []StaticWorker{{Address: addr, BandwidthMbps: 100}}
+	s, client, cleanup := setupTestServer(t, cfg)
+	defer cleanup()
+
+	require.Eventually(t, func() bool {
+		_, ok := s.Registry().Get("worker-farm-1")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	w, _ := s.Registry().Get("worker-farm-1")
+	assert.Equal(t, addr, w.Address)
+	assert.Equal(t, registry.DiscoverySourceWAN, w.DiscoverySource)
+	assert.Equal(t, 100.0, w.BandwidthMbps)
+	assert.Positive(t, w.RTT)
+	assert.Equal(t, int32(16), w.MaxParallel)
+	require.NotNil(t, w.Load)
+	assert.Equal(t, float32(30), w.Load.CpuUsagePercent)
+	assert.Len(t, s.Registry().ListByCapability(pb.BuildType_BUILD_TYPE_CPP, pb.Architecture_ARCH_X86_64), 1)
+
+	resp, err := client.GetWorkerStatus(context.Background(), &pb.WorkerStatusRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Workers, 1)
+	assert.Equal(t, registry.DiscoverySourceWAN, resp.Workers[0].DiscoverySource)
+	assert.Equal(t, int64(1<<40), resp.Workers[0].GetLoad().GetDiskFreeBytes())
+}
+
+func TestStaticWorkers_LAN(t *testing.T) {
+	addr, stopWorker := setupMockWorker(t, &mockWorkerBuildService{statusFn: staticWorkerStatus("")})
+	defer stopWorker()
+
+	cfg := DefaultConfig()
+	cfg.StaticWorkers = []StaticWorker{{Address: addr, LAN: true}}
+	s, _, cleanup := setupTestServer(t, cfg)
+	defer cleanup()
+
+	// Workers not reporting an ID are named after their address
+	require.Eventually(t, func() bool {
+		_, ok := s.Registry().Get("worker-" + addr)
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	w, _ := s.Registry().Get("worker-" + addr)
+	assert.Equal(t, registry.DiscoverySourceLAN, w.DiscoverySource)
+	assert.False(t, w.IsWAN())
+}
+
+func TestStaticWorkers_OldWorkerNotRegistered(t *testing.T) {
+	addr, stopWorker := setupMockWorker(t, &mockWorkerBuildService{
+		statusFn: func(context.Context, *pb.WorkerStatusRequest) (*pb.WorkerStatusResponse, error) {
+			return &pb.WorkerStatusResponse{
+				Workers: []*pb.WorkerStatusResponse_WorkerInfo{{WorkerId: "old-worker"}},
+			}, nil
+		},
+	})
+	defer stopWorker()
+
+	cfg := DefaultConfig()
+	s := New(cfg)
+	defer s.Stop()
+
+	err := s.probeStaticWorker(context.Background(), StaticWorker{Address: addr})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "capabilities")
+	assert.Equal(t, 0, s.Registry().Count())
+}
@@ -62,7 +62,7 @@ func (p *statsProvider) GetStats() *dashboard.Stats {
 		SuccessTasks:        atomic.LoadInt64(&p.server.successTasks),
 		FailedTasks:         atomic.LoadInt64(&p.server.failedTasks),
 		ActiveTasks:         atomic.LoadInt64(&p.server.activeTasks),
-		QueuedTasks:         atomic.LoadInt64(&p.server.queuedTasks),
+		QueuedTasks:         int64(p.server.queue.Len()),
 		CacheHits:           cacheHits,
 		CacheMisses:         cacheMisses,
 		CacheHitRate:        cacheHitRate,
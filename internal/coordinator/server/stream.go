@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/grpc/buildstream"
+	"github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
+)
+
+// StreamBuild handles streaming build requests for projects too large for the
+// unary Build RPC. The first chunk must carry BuildMetadata; the worker is
+// selected and the cache consulted exactly as for Build, then the remaining
+// source chunks are relayed to the worker one by one without buffering the
+// archive on the coordinator.
+func (s *Server) StreamBuild(stream pb.BuildService_StreamBuildServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "metadata required")
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to receive chunk: %v", err)
+	}
+
+	metadata := first.GetMetadata()
+	if metadata == nil {
+		return status.Error(codes.InvalidArgument, "metadata required")
+	}
+	if metadata.TaskId == "" {
+		return status.Error(codes.InvalidArgument, "task_id required")
+	}
+
+	req, err := buildstream.RequestFromMetadata(metadata)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	log.Debug().
+		Str("task_id", req.TaskId).
+		Str("build_type", req.BuildType.String()).
+		Int64("total_size_bytes", metadata.TotalSizeBytes).
+		Msg("Stream build started")
+
+	forward := relayStreamBuild(stream, metadata)
+
+	resp, err := s.dispatchBuild(stream.Context(), req, forward)
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(resp)
+}
+
+// relayStreamBuild returns a forwarder that opens a StreamBuild call on the
+// worker, sends the metadata and copies each client chunk as it arrives.
+func relayStreamBuild(stream pb.BuildService_StreamBuildServer, metadata *pb.BuildMetadata) buildForwarder {
+	return func(ctx context.Context, client pb.BuildServiceClient) (*pb.BuildResponse, error) {
+		workerStream, err := client.StreamBuild(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open worker stream: %w", err)
+		}
+
+		if err := workerStream.Send(&pb.BuildChunk{
+			Payload: &pb.BuildChunk_Metadata{Metadata: metadata},
+		}); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to send metadata: %w", err)
+		}
+
+		var relayed int64
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to receive chunk: %w", err)
+			}
+
+			data := chunk.GetSourceChunk()
+			if len(data) == 0 {
+				continue
+			}
+
+			if err := workerStream.Send(chunk); err != nil {
+				// io.EOF means the worker ended the call early; the real
+				// status is returned by CloseAndRecv below.
+				if err == io.EOF {
+					break
+				}
+				return nil, fmt.Errorf("failed to relay chunk: %w", err)
+			}
+			relayed += int64(len(data))
+		}
+
+		metrics.Default().RecordTransfer("upload", float64(relayed))
+
+		return workerStream.CloseAndRecv()
+	}
+}
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"sync"
@@ -42,9 +45,12 @@ type TaskLogRecord struct {
 	WorkerDiscoverySource       string    `json:"worker_discovery_source"`
 	TargetArch                  string    `json:"target_arch"`
 	ClientOS                    string    `json:"client_os"`
+	Client                      string    `json:"client"`
+	Project                     string    `json:"project"`
 	SourceSizeBytes             int       `json:"source_size_bytes"`
 	PreprocessedSizeBytes       int       `json:"preprocessed_size_bytes"`
 	RawSourceSizeBytes          int       `json:"raw_source_size_bytes"`
+	UpwardRank                  float64   `json:"upward_rank"`
 	QueueTimeMs                 int64     `json:"queue_time_ms"`
 	CompileTimeMs               int64     `json:"compile_time_ms"`
 	WorkerRPCLatencyMs          int64     `json:"worker_rpc_latency_ms"`
@@ -90,3 +96,22 @@ func (l *TaskLogger) Close() error {
 	}
 	return l.closer.Close()
 }
+
+// ReadTaskLog decodes the records of a task log written by TaskLogger.
+// Blank lines are skipped.
+func ReadTaskLog(r io.Reader) ([]TaskLogRecord, error) {
+	var records []TaskLogRecord
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var rec TaskLogRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records, sc.Err()
+}
@@ -99,6 +99,22 @@ func TestTaskLogger_FileBackend(t *testing.T) {
 	assert.Contains(t, string(data), `"task_id":"task-1"`)
 }
 
+func TestReadTaskLog(t *testing.T) {
+	l, buf := loggerWithBuffer()
+	l.Log(sampleRecord())
+	buf.WriteString("\n")
+	l.Log(sampleRecord())
+
+	records, err := ReadTaskLog(buf)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, *sampleRecord(), records[1])
+
+	_, err = ReadTaskLog(strings.NewReader("{}\nnot json\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
 func TestTaskLogger_StdoutFallback(t *testing.T) {
 	l, err := NewTaskLogger("")
 	require.NoError(t, err)
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/grpc/tasklog"
+	"github.com/h3nr1-d14z/hybridgrid/internal/observability/dashboard"
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/auth"
+)
+
+// outputRelayDrain bounds how long a finished build waits for the rest of
+// its output to arrive from the worker.
+const outputRelayDrain = 2 * time.Second
+
+// WatchTask streams the output of a build as its worker produces it. Clients
+// may start watching before the build is submitted or while it is queued;
+// the stream ends when the build finishes. Only the build's submitter and
+// admins may watch it.
+func (s *Server) WatchTask(req *pb.WatchTaskRequest, stream pb.BuildService_WatchTaskServer) error {
+	if req.TaskId == "" {
+		return status.Error(codes.InvalidArgument, "task_id required")
+	}
+	err := s.taskOutput.Serve(stream.Context(), req.TaskId, taskAccess(stream.Context()), stream.Send)
+	if errors.Is(err, tasklog.ErrNotOwner) {
+		return status.Errorf(codes.PermissionDenied, "task %s was submitted by another client", req.TaskId)
+	}
+	return err
+}
+
+// forwardWatched runs forward against the worker while relaying the
+// worker's output for taskID to WatchTask subscribers.
+func (s *Server) forwardWatched(ctx context.Context, taskID string, client pb.BuildServiceClient, forward buildForwarder) (*pb.BuildResponse, error) {
+	relayCtx, stopRelay := context.WithCancel(ctx)
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		s.relayTaskOutput(relayCtx, client, taskID)
+	}()
+
+	resp, err := forward(ctx, client)
+
+	// The worker ends the stream when the build finishes; only wait a
+	// little for it in case the watch never reached the worker.
+	select {
+	case <-relayDone:
+	case <-time.After(outputRelayDrain):
+	}
+	stopRelay()
+	<-relayDone
+
+	return resp, err
+}
+
+// relayTaskOutput copies taskID's output from the worker to WatchTask
+// subscribers until the worker's stream ends or ctx is done.
+func (s *Server) relayTaskOutput(ctx context.Context, client pb.BuildServiceClient, taskID string) {
+	stream, err := client.WatchTask(ctx, &pb.WatchTaskRequest{TaskId: taskID})
+	if err != nil {
+		log.Debug().Err(err).Str("task_id", taskID).Msg("Failed to watch task output on worker")
+		return
+	}
+
+	for {
+		line, err := stream.Recv()
+		if err != nil {
+			if err != io.EOF && ctx.Err() == nil {
+				// Workers that predate WatchTask answer Unimplemented.
+				log.Debug().Err(err).Str("task_id", taskID).Msg("Task output relay ended")
+			}
+			return
+		}
+		s.taskOutput.Publish(line)
+	}
+}
+
+// taskLogSource implements dashboard.TaskLogSource for the coordinator.
+type taskLogSource struct {
+	server *Server
+}
+
+// NewTaskLogSource lets dashboard clients follow the output of builds. The
+// dashboard itself is unauthenticated, so each subscription must carry an
+// API token, and grants the access WatchTask would. It returns nil, leaving
+// task logs off the dashboard, when the coordinator has no tokens.
+func (s *Server) NewTaskLogSource() dashboard.TaskLogSource {
+	if s.config.TokensFile == "" && s.config.AuthToken == "" {
+		return nil
+	}
+	return &taskLogSource{server: s}
+}
+
+// WatchTaskLog follows taskID's output until the build finishes or ctx is
+// done, if token belongs to its submitter or an admin.
+func (p *taskLogSource) WatchTaskLog(ctx context.Context, token, taskID string, send func(*dashboard.TaskLogLine)) error {
+	authn := p.server.authn.Load()
+	if authn == nil {
+		return errors.New("coordinator is not serving")
+	}
+	id, ok := authn.Authenticate(token)
+	if !ok {
+		return errors.New("a valid API token is required to follow task logs")
+	}
+	ctx = auth.NewContext(ctx, id)
+
+	err := p.server.taskOutput.Serve(ctx, taskID, taskAccess(ctx), func(line *pb.TaskLogLine) error {
+		stream := "stdout"
+		if line.Stream == pb.LogStream_LOG_STREAM_STDERR {
+			stream = "stderr"
+		}
+		send(&dashboard.TaskLogLine{
+			TaskID:      line.TaskId,
+			Stream:      stream,
+			Line:        line.Line,
+			TimestampMs: line.TimestampMs,
+		})
+		return nil
+	})
+	if errors.Is(err, tasklog.ErrNotOwner) {
+		return fmt.Errorf("task %s was submitted by another client", taskID)
+	}
+	return err
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/registry"
+	"github.com/h3nr1-d14z/hybridgrid/internal/observability/dashboard"
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/auth"
+)
+
+// setupWatchedFlutterWorker registers a Flutter worker that streams lines
+// for each watched task before completing the build.
+func setupWatchedFlutterWorker(t *testing.T, s *Server, lines ...string) {
+	t.Helper()
+
+	watching := make(chan struct{})
+	sent := make(chan struct{})
+	addr, cleanup := setupMockWorker(t, &mockWorkerBuildService{
+		watchFn: func(req *pb.WatchTaskRequest, stream pb.BuildService_WatchTaskServer) error {
+			close(watching)
+			for _, line := range lines {
+				if err := stream.Send(&pb.TaskLogLine{TaskId: req.TaskId, Stream: pb.LogStream_LOG_STREAM_STDOUT, Line: line}); err != nil {
+					return err
+				}
+			}
+			close(sent)
+			return nil
+		},
+		buildFn: func(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+			<-watching
+			<-sent
+			return &pb.BuildResponse{Status: pb.TaskStatus_STATUS_COMPLETED, Artifacts: []byte("apk")}, nil
+		},
+	})
+	t.Cleanup(cleanup)
+
+	require.NoError(t, s.registry.Add(&registry.WorkerInfo{
+		ID:          "flutter-worker-1",
+		Address:     addr,
+		MaxParallel: 4,
+		Capabilities: &pb.WorkerCapabilities{
+			NativeArch: pb.Architecture_ARCH_X86_64,
+			Flutter: &pb.FlutterCapability{
+				Platforms:  []pb.TargetPlatform{pb.TargetPlatform_PLATFORM_ANDROID},
+				AndroidSdk: true,
+			},
+		},
+	}))
+}
+
+func TestWatchTask_RelaysWorkerOutput(t *testing.T) {
+	s, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 5 * time.Second})
+	defer cleanup()
+	setupWatchedFlutterWorker(t, s, "Running Gradle task 'assembleRelease'...", "Built app-release.apk")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Watch before submitting, as hgbuild does.
+	watch, err := client.WatchTask(ctx, &pb.WatchTaskRequest{TaskId: "flutter-watched"})
+	require.NoError(t, err)
+
+	resp, err := client.Build(ctx, newFlutterBuildRequest("flutter-watched", "0badc0de"))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status)
+
+	var lines []string
+	for {
+		line, err := watch.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, "flutter-watched", line.TaskId)
+		lines = append(lines, line.Line)
+	}
+	assert.Equal(t, []string{"Running Gradle task 'assembleRelease'...", "Built app-release.apk"}, lines)
+}
+
+func TestWatchTask_EndsWhenBuildFails(t *testing.T) {
+	_, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second})
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	watch, err := client.WatchTask(ctx, &pb.WatchTaskRequest{TaskId: "flutter-no-worker"})
+	require.NoError(t, err)
+
+	resp, err := client.Build(ctx, newFlutterBuildRequest("flutter-no-worker", "aabbccdd"))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_FAILED, resp.Status)
+
+	_, err = watch.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestWatchTask_RequiresTaskID(t *testing.T) {
+	_, client, cleanup := setupTestServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second})
+	defer cleanup()
+
+	watch, err := client.WatchTask(context.Background(), &pb.WatchTaskRequest{})
+	require.NoError(t, err)
+	_, err = watch.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTaskLogSource_WatchTaskLog(t *testing.T) {
+	s, client, cleanup := setupAuthServer(t, Config{Port: 0, HeartbeatTTL: 30 * time.Second, RequestTimeout: 5 * time.Second})
+	defer cleanup()
+	setupWatchedFlutterWorker(t, s, "stdout line")
+
+	_, err := client.Build(auth.ContextWithToken(context.Background(), ciToken), newFlutterBuildRequest("flutter-dashboard", "feedface"))
+	require.NoError(t, err)
+
+	// The output of a finished build stays available briefly.
+	var lines []*dashboard.TaskLogLine
+	send := func(line *dashboard.TaskLogLine) { lines = append(lines, line) }
+	source := s.NewTaskLogSource()
+	require.NotNil(t, source)
+
+	err = source.WatchTaskLog(context.Background(), "", "flutter-dashboard", send)
+	assert.ErrorContains(t, err, "API token is required")
+	err = source.WatchTaskLog(context.Background(), workerToken, "flutter-dashboard", send)
+	assert.ErrorContains(t, err, "another client")
+	assert.Empty(t, lines)
+
+	err = source.WatchTaskLog(context.Background(), ciToken, "flutter-dashboard", send)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "stdout", lines[0].Stream)
+	assert.Equal(t, "stdout line", lines[0].Line)
+}
+
+func TestNewTaskLogSource_RequiresTokens(t *testing.T) {
+	s := New(Config{HeartbeatTTL: 30 * time.Second, BuildCacheDir: t.TempDir()})
+	defer s.Stop()
+
+	assert.Nil(t, s.NewTaskLogSource())
+}
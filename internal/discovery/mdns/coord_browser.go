@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grandcat/zeroconf"
@@ -164,10 +165,19 @@ func (b *CoordBrowser) parseEntry(entry *zeroconf.ServiceEntry) *DiscoveredCoord
 }
 
 // DiscoverWithFallback tries mDNS discovery, falls back to provided address.
+// The fallback may be a comma-separated list of coordinators; when discovery
+// succeeds, the discovered coordinator is returned ahead of the list so a
+// client can fail over to the others.
 func (b *CoordBrowser) DiscoverWithFallback(ctx context.Context, fallback string) (string, error) {
 	coord, err := b.Discover(ctx)
 	if err == nil {
-		return coord.Address, nil
+		addrs := []string{coord.Address}
+		for _, a := range strings.Split(fallback, ",") {
+			if a = strings.TrimSpace(a); a != "" && a != coord.Address {
+				addrs = append(addrs, a)
+			}
+		}
+		return strings.Join(addrs, ","), nil
 	}
 
 	log.Warn().
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Cost model of CriticalPathRanks, in bytes of source: compiling a
+// translation unit costs its size plus that of the headers it includes,
+// and linking costs linkBytesPerInput per object or library linked.
+const (
+	// defaultFileBytes stands in for files that do not exist yet or are
+	// include directories rather than headers.
+	defaultFileBytes  = 1024
+	linkBytesPerInput = 16 * 1024
+)
+
+// UpwardRanks returns the upward rank of every node: its own cost plus
+// the largest rank among the nodes that depend on it, so a node's rank is
+// the cost of the longest path from it to the end of the build (Topcuoglu
+// et al., "Performance-effective and low-complexity task scheduling for
+// heterogeneous computing", Eq. 1). An edge closing a cycle is ignored.
+func (g *Graph) UpwardRanks(cost func(*Node) float64) map[string]float64 {
+	dependents := make(map[string][]string, len(g.Nodes))
+	for _, e := range g.Edges {
+		dependents[e.From] = append(dependents[e.From], e.To)
+	}
+
+	ranks := make(map[string]float64, len(g.Nodes))
+	visiting := make(map[string]bool)
+	var rank func(id string) float64
+	rank = func(id string) float64 {
+		if r, ok := ranks[id]; ok {
+			return r
+		}
+		if visiting[id] {
+			return 0
+		}
+		visiting[id] = true
+		var longest float64
+		for _, to := range dependents[id] {
+			if r := rank(to); r > longest {
+				longest = r
+			}
+		}
+		delete(visiting, id)
+
+		var own float64
+		if node := g.Nodes[id]; node != nil {
+			own = cost(node)
+		}
+		ranks[id] = own + longest
+		return ranks[id]
+	}
+	for id := range g.Nodes {
+		rank(id)
+	}
+	return ranks
+}
+
+// CriticalPathRanks returns the upward rank of each source file, scaled so
+// the source on the critical path has rank 1, keyed by its absolute path.
+// Relative paths in the graph are resolved against dir, which should be
+// absolute. The cost of a compile is estimated from the size of the source
+// and the headers it includes, so header-heavy translation units feeding a
+// large link rank highest.
+func (g *Graph) CriticalPathRanks(dir string) map[string]float64 {
+	abs := func(file string) string {
+		if filepath.IsAbs(file) {
+			return filepath.Clean(file)
+		}
+		return filepath.Join(dir, file)
+	}
+
+	edgesIn := make(map[string][]*Edge, len(g.Nodes))
+	for _, e := range g.Edges {
+		edgesIn[e.To] = append(edgesIn[e.To], e)
+	}
+	cost := func(node *Node) float64 {
+		switch node.Type {
+		case NodeSource:
+			bytes := fileBytes(abs(node.File))
+			for _, e := range edgesIn[node.ID] {
+				if header := g.Nodes[e.From]; header != nil && e.Type == EdgeIncludes {
+					bytes += fileBytes(abs(header.File))
+				}
+			}
+			return float64(bytes)
+		case NodeExecutable, NodeLibrary:
+			return float64(linkBytesPerInput * len(edgesIn[node.ID]))
+		default:
+			return 0
+		}
+	}
+
+	ranks := g.UpwardRanks(cost)
+	var highest float64
+	for _, node := range g.Nodes {
+		if node.Type == NodeSource && ranks[node.ID] > highest {
+			highest = ranks[node.ID]
+		}
+	}
+
+	sources := make(map[string]float64)
+	if highest == 0 {
+		return sources
+	}
+	for _, node := range g.Nodes {
+		if node.Type == NodeSource {
+			sources[abs(node.File)] = ranks[node.ID] / highest
+		}
+	}
+	return sources
+}
+
+// fileBytes returns the size of a regular file, or defaultFileBytes for
+// anything else.
+func fileBytes(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return defaultFileBytes
+	}
+	return info.Size()
+}
@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUpwardRanks(t *testing.T) {
+	g := New()
+	for _, id := range []string{"a.c", "b.c", "a.o", "b.o", "app"} {
+		g.AddNode(&Node{ID: id, File: id, Type: inferNodeType(id)})
+	}
+	g.AddEdge("a.c", "a.o", EdgeCompilesTo)
+	g.AddEdge("b.c", "b.o", EdgeCompilesTo)
+	g.AddEdge("a.o", "app", EdgeLinksTo)
+	g.AddEdge("b.o", "app", EdgeLinksTo)
+
+	costs := map[string]float64{"a.c": 5, "b.c": 2, "a.o": 0, "b.o": 1, "app": 10}
+	ranks := g.UpwardRanks(func(n *Node) float64 { return costs[n.ID] })
+
+	want := map[string]float64{"app": 10, "a.o": 10, "b.o": 11, "a.c": 15, "b.c": 13}
+	for id, w := range want {
+		if ranks[id] != w {
+			t.Errorf("rank(%s) = %v, want %v", id, ranks[id], w)
+		}
+	}
+}
+
+func TestUpwardRanksIgnoresCycles(t *testing.T) {
+	g := New()
+	g.AddNode(&Node{ID: "x"})
+	g.AddNode(&Node{ID: "y"})
+	g.AddEdge("x", "y", EdgeDependsOn)
+	g.AddEdge("y", "x", EdgeDependsOn)
+
+	ranks := g.UpwardRanks(func(*Node) float64 { return 1 })
+	if len(ranks) != 2 || ranks["x"]+ranks["y"] != 3 {
+		t.Errorf("ranks = %v, want 1 and 2", ranks)
+	}
+}
+
+func TestCriticalPathRanks(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, size int) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Repeat("x", size)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("heavy.h", 200000)
+	write("main.c", 2000)
+	write("util.c", 8000)
+	write("tool.c", 4000)
+
+	// main.c is small but includes a large header; util.c is larger on
+	// its own. tool.c builds a separate, single-object executable.
+	g := New()
+	for _, id := range []string{"heavy.h", "main.c", "util.c", "tool.c", "main.o", "util.o", "tool.o", "app", "tool"} {
+		g.AddNode(&Node{ID: id, File: id, Type: inferNodeType(id)})
+	}
+	g.AddEdge("heavy.h", "main.c", EdgeIncludes)
+	g.AddEdge("main.c", "main.o", EdgeCompilesTo)
+	g.AddEdge("util.c", "util.o", EdgeCompilesTo)
+	g.AddEdge("tool.c", "tool.o", EdgeCompilesTo)
+	g.AddEdge("main.o", "app", EdgeLinksTo)
+	g.AddEdge("util.o", "app", EdgeLinksTo)
+	g.AddEdge("tool.o", "tool", EdgeLinksTo)
+
+	ranks := g.CriticalPathRanks(dir)
+	if len(ranks) != 3 {
+		t.Fatalf("ranks = %v, want one per source", ranks)
+	}
+	main := ranks[filepath.Join(dir, "main.c")]
+	util := ranks[filepath.Join(dir, "util.c")]
+	tool := ranks[filepath.Join(dir, "tool.c")]
+	if main != 1 {
+		t.Errorf("main.c rank = %v, want 1 on the critical path", main)
+	}
+	if !(util > tool && tool > 0) {
+		t.Errorf("util.c rank %v, tool.c rank %v: want util.c, feeding the larger link, above tool.c", util, tool)
+	}
+	wantUtil := float64(8000+2*linkBytesPerInput) / float64(202000+2*linkBytesPerInput)
+	if math.Abs(util-wantUtil) > 1e-9 {
+		t.Errorf("util.c rank = %v, want %v", util, wantUtil)
+	}
+}
+
+func TestCriticalPathRanksEmpty(t *testing.T) {
+	if ranks := New().CriticalPathRanks(t.TempDir()); len(ranks) != 0 {
+		t.Errorf("ranks = %v, want none", ranks)
+	}
+}
@@ -0,0 +1,150 @@
+package buildstream
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+// MetadataFromRequest builds the StreamBuild header for req. The source
+// archive itself is not included; callers stream it as source chunks.
+func MetadataFromRequest(req *pb.BuildRequest, totalSize int64) (*pb.BuildMetadata, error) {
+	if req == nil {
+		return nil, fmt.Errorf("build request required")
+	}
+
+	configJSON, err := EncodeConfig(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.BuildMetadata{
+		TaskId:         req.TaskId,
+		SourceHash:     req.SourceHash,
+		BuildType:      req.BuildType,
+		TargetPlatform: req.TargetPlatform,
+		TotalSizeBytes: totalSize,
+		ConfigJson:     configJSON,
+		DockerImage:    req.DockerImage,
+		TimeoutSeconds: req.TimeoutSeconds,
+		Priority:       req.Priority,
+		DeferArtifacts: req.DeferArtifacts,
+	}, nil
+}
+
+// RequestFromMetadata reconstructs a BuildRequest (without source archive)
+// from a StreamBuild header. An empty config_json yields an empty config of
+// the type implied by build_type.
+func RequestFromMetadata(meta *pb.BuildMetadata) (*pb.BuildRequest, error) {
+	if meta == nil {
+		return nil, fmt.Errorf("metadata required")
+	}
+
+	req := &pb.BuildRequest{
+		TaskId:         meta.TaskId,
+		SourceHash:     meta.SourceHash,
+		BuildType:      meta.BuildType,
+		TargetPlatform: meta.TargetPlatform,
+		DockerImage:    meta.DockerImage,
+		TimeoutSeconds: meta.TimeoutSeconds,
+		Priority:       meta.Priority,
+		DeferArtifacts: meta.DeferArtifacts,
+	}
+
+	if err := DecodeConfig(req, meta.ConfigJson); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// EncodeConfig returns the protojson encoding of the config set on req, e.g.
+// {"buildMode":"apk-release"} for a FlutterConfig. Requests without a config
+// encode to an empty string.
+func EncodeConfig(req *pb.BuildRequest) (string, error) {
+	msg := configMessage(req)
+	if msg == nil {
+		return "", nil
+	}
+
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode config: %w", err)
+	}
+	return string(data), nil
+}
+
+// DecodeConfig parses configJSON according to req.BuildType and sets the
+// matching oneof config on req.
+func DecodeConfig(req *pb.BuildRequest, configJSON string) error {
+	var msg proto.Message
+
+	switch req.BuildType {
+	case pb.BuildType_BUILD_TYPE_CPP:
+		cfg := &pb.CppConfig{}
+		req.Config = &pb.BuildRequest_CppConfig{CppConfig: cfg}
+		msg = cfg
+	case pb.BuildType_BUILD_TYPE_FLUTTER:
+		cfg := &pb.FlutterConfig{}
+		req.Config = &pb.BuildRequest_FlutterConfig{FlutterConfig: cfg}
+		msg = cfg
+	case pb.BuildType_BUILD_TYPE_UNITY:
+		cfg := &pb.UnityConfig{}
+		req.Config = &pb.BuildRequest_UnityConfig{UnityConfig: cfg}
+		msg = cfg
+	case pb.BuildType_BUILD_TYPE_COCOS:
+		cfg := &pb.CocosConfig{}
+		req.Config = &pb.BuildRequest_CocosConfig{CocosConfig: cfg}
+		msg = cfg
+	case pb.BuildType_BUILD_TYPE_RUST:
+		cfg := &pb.RustConfig{}
+		req.Config = &pb.BuildRequest_RustConfig{RustConfig: cfg}
+		msg = cfg
+	case pb.BuildType_BUILD_TYPE_GO:
+		cfg := &pb.GoConfig{}
+		req.Config = &pb.BuildRequest_GoConfig{GoConfig: cfg}
+		msg = cfg
+	case pb.BuildType_BUILD_TYPE_NODEJS:
+		cfg := &pb.NodeConfig{}
+		req.Config = &pb.BuildRequest_NodeConfig{NodeConfig: cfg}
+		msg = cfg
+	default:
+		if strings.TrimSpace(configJSON) != "" {
+			return fmt.Errorf("config_json set for unsupported build type %s", req.BuildType)
+		}
+		return nil
+	}
+
+	if strings.TrimSpace(configJSON) == "" {
+		return nil
+	}
+	if err := protojson.Unmarshal([]byte(configJSON), msg); err != nil {
+		return fmt.Errorf("invalid config_json: %w", err)
+	}
+	return nil
+}
+
+func configMessage(req *pb.BuildRequest) proto.Message {
+	switch cfg := req.Config.(type) {
+	case *pb.BuildRequest_CppConfig:
+		return cfg.CppConfig
+	case *pb.BuildRequest_FlutterConfig:
+		return cfg.FlutterConfig
+	case *pb.BuildRequest_UnityConfig:
+		return cfg.UnityConfig
+	case *pb.BuildRequest_CocosConfig:
+		return cfg.CocosConfig
+	case *pb.BuildRequest_RustConfig:
+		return cfg.RustConfig
+	case *pb.BuildRequest_GoConfig:
+		return cfg.GoConfig
+	case *pb.BuildRequest_NodeConfig:
+		return cfg.NodeConfig
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,70 @@
+package buildstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+func TestMetadataRoundTrip_Unity(t *testing.T) {
+	req := &pb.BuildRequest{
+		TaskId:         "task-1",
+		SourceHash:     "deadbeef",
+		BuildType:      pb.BuildType_BUILD_TYPE_UNITY,
+		TargetPlatform: pb.TargetPlatform_PLATFORM_WEBGL,
+		TimeoutSeconds: 1800,
+		Priority:       70,
+		DeferArtifacts: true,
+		Config: &pb.BuildRequest_UnityConfig{UnityConfig: &pb.UnityConfig{
+			UnityVersion: "2022.3.0f1",
+			BuildMethod:  "BuildScript.Build",
+			ExtraArgs:    map[string]string{"-quitTimeout": "5"},
+		}},
+	}
+
+	meta, err := MetadataFromRequest(req, 4096)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4096), meta.TotalSizeBytes)
+	assert.Contains(t, meta.ConfigJson, "BuildScript.Build")
+
+	got, err := RequestFromMetadata(meta)
+	require.NoError(t, err)
+	assert.Equal(t, req.TaskId, got.TaskId)
+	assert.Equal(t, req.SourceHash, got.SourceHash)
+	assert.Equal(t, req.TargetPlatform, got.TargetPlatform)
+	assert.Equal(t, int32(1800), got.TimeoutSeconds)
+	assert.Equal(t, int32(70), got.Priority)
+	assert.True(t, got.DeferArtifacts)
+	require.NotNil(t, got.GetUnityConfig())
+	assert.Equal(t, "BuildScript.Build", got.GetUnityConfig().BuildMethod)
+	assert.Equal(t, "5", got.GetUnityConfig().ExtraArgs["-quitTimeout"])
+}
+
+func TestRequestFromMetadata_EmptyConfigUsesBuildType(t *testing.T) {
+	got, err := RequestFromMetadata(&pb.BuildMetadata{
+		TaskId:    "task-2",
+		BuildType: pb.BuildType_BUILD_TYPE_FLUTTER,
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, got.GetFlutterConfig())
+}
+
+func TestRequestFromMetadata_InvalidJSON(t *testing.T) {
+	_, err := RequestFromMetadata(&pb.BuildMetadata{
+		TaskId:     "task-3",
+		BuildType:  pb.BuildType_BUILD_TYPE_FLUTTER,
+		ConfigJson: `{"buildMode": 5}`,
+	})
+	require.Error(t, err)
+}
+
+func TestRequestFromMetadata_ConfigWithoutBuildType(t *testing.T) {
+	_, err := RequestFromMetadata(&pb.BuildMetadata{
+		TaskId:     "task-4",
+		ConfigJson: `{"buildMode":"release"}`,
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,69 @@
+// Package cancellation lets CancelTask reach the context of a task that is
+// queued or running in another RPC.
+package cancellation
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNotOwner is returned by Cancel when the caller may not cancel the task.
+var ErrNotOwner = errors.New("cancellation: task belongs to another owner")
+
+// Registry maps task IDs to the cancel functions of their contexts.
+type Registry struct {
+	mu    sync.Mutex
+	tasks map[string]*entry
+}
+
+type entry struct {
+	cancel context.CancelFunc
+	owner  string
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{tasks: make(map[string]*entry)}
+}
+
+// Track derives a context for taskID, submitted by owner, that Cancel can
+// cancel. The returned release func must be called when the task finishes.
+// If several tasks share an ID, Cancel reaches the most recently tracked one.
+func (r *Registry) Track(ctx context.Context, taskID, owner string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	if taskID == "" {
+		return ctx, cancel
+	}
+
+	e := &entry{cancel: cancel, owner: owner}
+	r.mu.Lock()
+	r.tasks[taskID] = e
+	r.mu.Unlock()
+
+	return ctx, func() {
+		r.mu.Lock()
+		if r.tasks[taskID] == e {
+			delete(r.tasks, taskID)
+		}
+		r.mu.Unlock()
+		cancel()
+	}
+}
+
+// Cancel cancels the context of taskID and reports whether it was tracked.
+// If allowed is not nil and rejects the task's owner, the task keeps
+// running and Cancel returns ErrNotOwner.
+func (r *Registry) Cancel(taskID string, allowed func(owner string) bool) (bool, error) {
+	r.mu.Lock()
+	e, ok := r.tasks[taskID]
+	r.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	if allowed != nil && !allowed(e.owner) {
+		return false, ErrNotOwner
+	}
+	e.cancel()
+	return true, nil
+}
@@ -0,0 +1,69 @@
+package cancellation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_CancelTrackedTask(t *testing.T) {
+	r := NewRegistry()
+
+	ctx, release := r.Track(context.Background(), "task-1", "")
+	defer release()
+
+	cancelled, err := r.Cancel("other", nil)
+	assert.NoError(t, err)
+	assert.False(t, cancelled)
+	assert.NoError(t, ctx.Err())
+
+	cancelled, err = r.Cancel("task-1", nil)
+	assert.NoError(t, err)
+	assert.True(t, cancelled)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestRegistry_ReleaseForgetsTask(t *testing.T) {
+	r := NewRegistry()
+
+	ctx, release := r.Track(context.Background(), "task-1", "")
+	release()
+
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	cancelled, _ := r.Cancel("task-1", nil)
+	assert.False(t, cancelled)
+}
+
+func TestRegistry_DuplicateIDCancelsLatest(t *testing.T) {
+	r := NewRegistry()
+
+	first, releaseFirst := r.Track(context.Background(), "task-1", "")
+	second, releaseSecond := r.Track(context.Background(), "task-1", "")
+	defer releaseSecond()
+
+	// Releasing the older task must not forget the newer one.
+	releaseFirst()
+	assert.ErrorIs(t, first.Err(), context.Canceled)
+
+	cancelled, _ := r.Cancel("task-1", nil)
+	assert.True(t, cancelled)
+	assert.ErrorIs(t, second.Err(), context.Canceled)
+}
+
+func TestRegistry_CancelChecksOwner(t *testing.T) {
+	r := NewRegistry()
+
+	ctx, release := r.Track(context.Background(), "task-1", "alice")
+	defer release()
+
+	cancelled, err := r.Cancel("task-1", func(owner string) bool { return owner == "bob" })
+	assert.ErrorIs(t, err, ErrNotOwner)
+	assert.False(t, cancelled)
+	assert.NoError(t, ctx.Err())
+
+	cancelled, err = r.Cancel("task-1", func(owner string) bool { return owner == "alice" })
+	assert.NoError(t, err)
+	assert.True(t, cancelled)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
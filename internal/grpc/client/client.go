@@ -11,6 +11,7 @@ import (
 
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
 	"github.com/h3nr1-d14z/hybridgrid/internal/observability/tracing"
+	"github.com/h3nr1-d14z/hybridgrid/internal/security/auth"
 	hgtls "github.com/h3nr1-d14z/hybridgrid/internal/security/tls"
 )
 
@@ -22,6 +23,8 @@ const (
 
 // Config holds the gRPC client configuration.
 type Config struct {
+	// Address is the coordinator address, or a comma-separated list of
+	// the coordinators of a highly available setup, tried in order.
 	Address       string
 	AuthToken     string
 	Timeout       time.Duration
@@ -33,11 +36,13 @@ type Config struct {
 // Client wraps the BuildService gRPC client.
 type Client struct {
 	config Config
-	conn   *grpc.ClientConn
+	conn   io.Closer
 	client pb.BuildServiceClient
 }
 
-// New creates a new gRPC client connected to the specified address.
+// New creates a new gRPC client connected to the specified address. With
+// several addresses, a call a coordinator rejects as Unavailable, because
+// it is down or a standby, is retried on the next.
 func New(cfg Config) (*Client, error) {
 	if cfg.Timeout == 0 {
 		cfg.Timeout = defaultTimeout
@@ -67,15 +72,43 @@ func New(cfg Config) (*Client, error) {
 		opts = append(opts, tracing.DialOptions()...)
 	}
 
-	conn, err := grpc.NewClient(cfg.Address, opts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect: %w", err)
+	// Send the auth token with every call so the coordinator can identify
+	// the caller, not only on Handshake
+	if cfg.AuthToken != "" {
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(auth.UnaryClientInterceptor(cfg.AuthToken)),
+			grpc.WithChainStreamInterceptor(auth.StreamClientInterceptor(cfg.AuthToken)),
+		)
+	}
+
+	addrs := SplitAddresses(cfg.Address)
+	if len(addrs) <= 1 {
+		conn, err := grpc.NewClient(cfg.Address, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect: %w", err)
+		}
+
+		return &Client{
+			config: cfg,
+			conn:   conn,
+			client: pb.NewBuildServiceClient(conn),
+		}, nil
+	}
+
+	fc := &failoverConn{}
+	for _, addr := range addrs {
+		conn, err := grpc.NewClient(addr, opts...)
+		if err != nil {
+			fc.Close()
+			return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+		}
+		fc.conns = append(fc.conns, conn)
 	}
 
 	return &Client{
 		config: cfg,
-		conn:   conn,
-		client: pb.NewBuildServiceClient(conn),
+		conn:   fc,
+		client: pb.NewBuildServiceClient(fc),
 	}, nil
 }
 
@@ -116,35 +149,47 @@ func (c *Client) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResp
 	return c.client.Build(ctx, req)
 }
 
-// StreamBuild sends a streaming build request for large projects.
+// StreamBuild sends a streaming build request for large projects. The source
+// is read and sent in chunks, so it never has to be held in memory.
 func (c *Client) StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, source io.Reader) (*pb.BuildResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
 	stream, err := c.client.StreamBuild(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open stream: %w", err)
 	}
 
-	// Send metadata first
+	// Send metadata first. io.EOF from Send means the server already
+	// finished the call (e.g. a cache hit); CloseAndRecv returns its answer.
 	if err := stream.Send(&pb.BuildChunk{
 		Payload: &pb.BuildChunk_Metadata{Metadata: metadata},
 	}); err != nil {
+		if err == io.EOF {
+			return stream.CloseAndRecv()
+		}
 		return nil, fmt.Errorf("failed to send metadata: %w", err)
 	}
 
 	// Stream source data in chunks
 	buf := make([]byte, defaultChunkSize)
 	for {
-		n, err := source.Read(buf)
-		if err == io.EOF {
+		n, readErr := source.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&pb.BuildChunk{
+				Payload: &pb.BuildChunk_SourceChunk{SourceChunk: buf[:n]},
+			}); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return nil, fmt.Errorf("failed to send chunk: %w", err)
+			}
+		}
+		if readErr == io.EOF {
 			break
 		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to read source: %w", err)
-		}
-
-		if err := stream.Send(&pb.BuildChunk{
-			Payload: &pb.BuildChunk_SourceChunk{SourceChunk: buf[:n]},
-		}); err != nil {
-			return nil, fmt.Errorf("failed to send chunk: %w", err)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read source: %w", readErr)
 		}
 	}
 
@@ -152,6 +197,57 @@ func (c *Client) StreamBuild(ctx context.Context, metadata *pb.BuildMetadata, so
 	return stream.CloseAndRecv()
 }
 
+// FetchArtifacts downloads the artifacts of a build submitted with
+// defer_artifacts, calling handle for each chunk as it arrives.
+func (c *Client) FetchArtifacts(ctx context.Context, taskID string, handle func(*pb.ArtifactChunk) error) error {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	stream, err := c.client.FetchArtifacts(ctx, &pb.FetchArtifactsRequest{TaskId: taskID})
+	if err != nil {
+		return fmt.Errorf("failed to open stream: %w", err)
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := handle(chunk); err != nil {
+			return err
+		}
+	}
+}
+
+// WatchTask follows the output of a submitted task, calling handle for each
+// line until the task finishes. It may be called before the task is
+// submitted.
+func (c *Client) WatchTask(ctx context.Context, taskID string, handle func(*pb.TaskLogLine) error) error {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	stream, err := c.client.WatchTask(ctx, &pb.WatchTaskRequest{TaskId: taskID})
+	if err != nil {
+		return fmt.Errorf("failed to open stream: %w", err)
+	}
+
+	for {
+		line, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := handle(line); err != nil {
+			return err
+		}
+	}
+}
+
 // Compile sends a legacy C/C++ compilation request.
 func (c *Client) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.CompileResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
@@ -195,3 +291,50 @@ func (c *Client) ReportCacheHit(ctx context.Context, hits int32) error {
 	_, err := c.client.ReportCacheHit(ctx, &pb.ReportCacheHitRequest{Hits: hits})
 	return err
 }
+
+// ReportLoad sends the host load of a worker to the coordinator. It returns
+// false if the coordinator does not know the worker, which should then
+// handshake again.
+func (c *Client) ReportLoad(ctx context.Context, workerID string, load *pb.WorkerLoad) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	resp, err := c.client.ReportLoad(ctx, &pb.ReportLoadRequest{WorkerId: workerID, Load: load})
+	if err != nil {
+		return false, err
+	}
+	return resp.Accepted, nil
+}
+
+// SubmitBuildGraph sends the upward ranks of a build's translation units
+// to the coordinator. It returns how many ranks the coordinator stored.
+func (c *Client) SubmitBuildGraph(ctx context.Context, buildID string, ranks map[string]float64) (int32, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	req := &pb.SubmitBuildGraphRequest{
+		BuildId: buildID,
+		Ranks:   make([]*pb.TranslationUnitRank, 0, len(ranks)),
+	}
+	for path, rank := range ranks {
+		req.Ranks = append(req.Ranks, &pb.TranslationUnitRank{SourcePath: path, UpwardRank: rank})
+	}
+	resp, err := c.client.SubmitBuildGraph(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Accepted, nil
+}
+
+// CancelTask asks the coordinator to cancel a queued or running task. It
+// reports whether the coordinator knew the task.
+func (c *Client) CancelTask(ctx context.Context, taskID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	resp, err := c.client.CancelTask(ctx, &pb.CancelTaskRequest{TaskId: taskID})
+	if err != nil {
+		return false, err
+	}
+	return resp.Cancelled, nil
+}
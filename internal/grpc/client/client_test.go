@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
@@ -283,6 +285,47 @@ func (m *extendedMockBuildService) Compile(ctx context.Context, req *pb.CompileR
 	}, nil
 }
 
+func (m *extendedMockBuildService) FetchArtifacts(req *pb.FetchArtifactsRequest, stream pb.BuildService_FetchArtifactsServer) error {
+	if req.TaskId != "test-task-1" {
+		return status.Error(codes.NotFound, "no artifacts")
+	}
+	chunks := []*pb.ArtifactChunk{
+		{Payload: &pb.ArtifactChunk_Info{Info: &pb.ArtifactInfo{Path: "out/app.apk", SizeBytes: 6}}},
+		{Payload: &pb.ArtifactChunk_Data{Data: []byte("app")}},
+		{Payload: &pb.ArtifactChunk_Data{Data: []byte("apk")}},
+	}
+	for _, chunk := range chunks {
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *extendedMockBuildService) CancelTask(ctx context.Context, req *pb.CancelTaskRequest) (*pb.CancelTaskResponse, error) {
+	return &pb.CancelTaskResponse{Cancelled: req.TaskId == "running-task"}, nil
+}
+
+func (m *extendedMockBuildService) WatchTask(req *pb.WatchTaskRequest, stream pb.BuildService_WatchTaskServer) error {
+	for _, line := range []string{"Compiling", "Done"} {
+		if err := stream.Send(&pb.TaskLogLine{TaskId: req.TaskId, Line: line}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *extendedMockBuildService) SubmitBuildGraph(ctx context.Context, req *pb.SubmitBuildGraphRequest) (*pb.SubmitBuildGraphResponse, error) {
+	if req.BuildId == "" {
+		return nil, status.Error(codes.InvalidArgument, "build_id required")
+	}
+	return &pb.SubmitBuildGraphResponse{Accepted: int32(len(req.Ranks))}, nil
+}
+
+func (m *extendedMockBuildService) ReportLoad(ctx context.Context, req *pb.ReportLoadRequest) (*pb.ReportLoadResponse, error) {
+	return &pb.ReportLoadResponse{Accepted: req.WorkerId == "worker-1" && req.Load != nil}, nil
+}
+
 func (m *extendedMockBuildService) GetWorkersForBuild(ctx context.Context, req *pb.WorkersForBuildRequest) (*pb.WorkersForBuildResponse, error) {
 	return &pb.WorkersForBuildResponse{
 		WorkerIds:      []string{"worker-1"},
@@ -348,6 +391,53 @@ func TestClient_Build(t *testing.T) {
 	}
 }
 
+func TestClient_FetchArtifacts(t *testing.T) {
+	client, cleanup := setupExtendedMockServer(t)
+	defer cleanup()
+
+	var paths []string
+	var data []byte
+	err := client.FetchArtifacts(context.Background(), "test-task-1", func(chunk *pb.ArtifactChunk) error {
+		if info := chunk.GetInfo(); info != nil {
+			paths = append(paths, info.Path)
+		}
+		data = append(data, chunk.GetData()...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("FetchArtifacts failed: %v", err)
+	}
+
+	if len(paths) != 1 || paths[0] != "out/app.apk" {
+		t.Errorf("Expected one artifact out/app.apk, got %v", paths)
+	}
+	if string(data) != "appapk" {
+		t.Errorf("Expected data %q, got %q", "appapk", data)
+	}
+
+	err = client.FetchArtifacts(context.Background(), "unknown", func(*pb.ArtifactChunk) error { return nil })
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
+
+func TestClient_WatchTask(t *testing.T) {
+	client, cleanup := setupExtendedMockServer(t)
+	defer cleanup()
+
+	var lines []string
+	err := client.WatchTask(context.Background(), "test-task-1", func(line *pb.TaskLogLine) error {
+		lines = append(lines, line.Line)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WatchTask failed: %v", err)
+	}
+	if len(lines) != 2 || lines[0] != "Compiling" || lines[1] != "Done" {
+		t.Errorf("Expected lines [Compiling Done], got %v", lines)
+	}
+}
+
 func TestClient_Compile(t *testing.T) {
 	client, cleanup := setupExtendedMockServer(t)
 	defer cleanup()
@@ -370,6 +460,69 @@ func TestClient_Compile(t *testing.T) {
 	}
 }
 
+func TestClient_CancelTask(t *testing.T) {
+	client, cleanup := setupExtendedMockServer(t)
+	defer cleanup()
+
+	cancelled, err := client.CancelTask(context.Background(), "running-task")
+	if err != nil {
+		t.Fatalf("CancelTask failed: %v", err)
+	}
+	if !cancelled {
+		t.Error("Expected running task to be cancelled")
+	}
+
+	cancelled, err = client.CancelTask(context.Background(), "unknown-task")
+	if err != nil {
+		t.Fatalf("CancelTask failed: %v", err)
+	}
+	if cancelled {
+		t.Error("Expected unknown task not to be cancelled")
+	}
+}
+
+func TestClient_SubmitBuildGraph(t *testing.T) {
+	client, cleanup := setupExtendedMockServer(t)
+	defer cleanup()
+
+	accepted, err := client.SubmitBuildGraph(context.Background(), "build-1", map[string]float64{
+		"/src/main.c": 1,
+		"/src/util.c": 0.5,
+	})
+	if err != nil {
+		t.Fatalf("SubmitBuildGraph failed: %v", err)
+	}
+	if accepted != 2 {
+		t.Errorf("Expected 2 ranks accepted, got %d", accepted)
+	}
+
+	if _, err := client.SubmitBuildGraph(context.Background(), "", nil); err == nil {
+		t.Error("Expected an error without a build ID")
+	}
+}
+
+func TestClient_ReportLoad(t *testing.T) {
+	client, cleanup := setupExtendedMockServer(t)
+	defer cleanup()
+
+	load := &pb.WorkerLoad{CpuUsagePercent: 42, DiskFreeBytes: 1 << 30}
+	accepted, err := client.ReportLoad(context.Background(), "worker-1", load)
+	if err != nil {
+		t.Fatalf("ReportLoad failed: %v", err)
+	}
+	if !accepted {
+		t.Error("Expected the load report to be accepted")
+	}
+
+	accepted, err = client.ReportLoad(context.Background(), "unknown", load)
+	if err != nil {
+		t.Fatalf("ReportLoad failed: %v", err)
+	}
+	if accepted {
+		t.Error("Expected an unknown worker's report to be refused")
+	}
+}
+
 func TestClient_GetWorkersForBuild(t *testing.T) {
 	client, cleanup := setupExtendedMockServer(t)
 	defer cleanup()
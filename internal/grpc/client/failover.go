@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/ha"
+)
+
+// SplitAddresses splits a comma-separated list of coordinator addresses,
+// dropping empty entries.
+func SplitAddresses(address string) []string {
+	var addrs []string
+	for _, a := range strings.Split(address, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+	return addrs
+}
+
+// failoverConn sends calls to one of several coordinators. A call that does
+// not reach a coordinator, or reaches a standby, is retried on the next
+// one, which then receives later calls too. Unavailable errors from the
+// leader itself, such as no free worker, are returned as they are.
+//
+// Streams are opened the same way. A standby only rejects a stream once it
+// is open, so a stream on which just the server sends is reopened on the
+// next coordinator when its first response is such a rejection, replaying
+// the request. Client-streaming calls, whose requests may be too large to
+// keep, fail and leave later calls to the next coordinator.
+type failoverConn struct {
+	conns   []*grpc.ClientConn
+	current atomic.Int32
+}
+
+func (f *failoverConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	start := int(f.current.Load())
+	var err error
+	for i := range f.conns {
+		idx := (start + i) % len(f.conns)
+		var trailer metadata.MD
+		err = f.conns[idx].Invoke(ctx, method, args, reply, append(opts[:len(opts):len(opts)], grpc.Trailer(&trailer))...)
+		if !failsOver(err, trailer) || ctx.Err() != nil {
+			f.current.Store(int32(idx))
+			return err
+		}
+	}
+	return err
+}
+
+func (f *failoverConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream := &failoverStream{conn: f, ctx: ctx, desc: desc, method: method, opts: opts}
+	if err := stream.open(int(f.current.Load())); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// next moves later calls from the coordinator at idx to the one after it,
+// unless another call already moved them, and returns that one's index.
+func (f *failoverConn) next(idx int) int {
+	next := (idx + 1) % len(f.conns)
+	f.current.CompareAndSwap(int32(idx), int32(next))
+	return next
+}
+
+// failsOver reports whether a call that ended with err should be retried
+// on the next coordinator: it failed with Unavailable and the answer did
+// not come from a leader, so the coordinator was unreachable, a standby,
+// or too old to say.
+func failsOver(err error, trailer metadata.MD) bool {
+	if status.Code(err) != codes.Unavailable {
+		return false
+	}
+	role := trailer.Get(ha.RoleMetadataKey)
+	return len(role) == 0 || role[0] != ha.RoleLeader
+}
+
+// failoverStream is a stream to the coordinator at idx that moves to the
+// next coordinator as described for failoverConn.
+type failoverStream struct {
+	grpc.ClientStream
+
+	conn   *failoverConn
+	ctx    context.Context
+	desc   *grpc.StreamDesc
+	method string
+	opts   []grpc.CallOption
+
+	idx      int
+	tried    int           // Coordinators tried so far
+	sent     []interface{} // Requests to replay, until the first response
+	closed   bool          // CloseSend was called before the first response
+	received bool
+}
+
+// open opens the stream on the coordinator at start, or the first one
+// after it that can be reached, and replays the requests sent so far.
+func (s *failoverStream) open(start int) error {
+	var err error
+	for idx := start; s.tried < len(s.conn.conns); idx = s.conn.next(idx) {
+		s.tried++
+		var cs grpc.ClientStream
+		cs, err = s.conn.conns[idx].NewStream(s.ctx, s.desc, s.method, s.opts...)
+		if err != nil {
+			if failsOver(err, nil) && s.ctx.Err() == nil {
+				continue
+			}
+			return err
+		}
+
+		s.ClientStream, s.idx = cs, idx
+		s.conn.current.Store(int32(idx))
+		for _, m := range s.sent {
+			if err := cs.SendMsg(m); err != nil {
+				return err
+			}
+		}
+		if s.closed {
+			return cs.CloseSend()
+		}
+		return nil
+	}
+	return err
+}
+
+func (s *failoverStream) SendMsg(m interface{}) error {
+	if !s.received && !s.desc.ClientStreams {
+		s.sent = append(s.sent, m)
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *failoverStream) CloseSend() error {
+	s.closed = true
+	return s.ClientStream.CloseSend()
+}
+
+func (s *failoverStream) RecvMsg(m interface{}) error {
+	for {
+		err := s.ClientStream.RecvMsg(m)
+		if err == nil {
+			s.received, s.sent = true, nil
+			return nil
+		}
+		if s.received || !failsOver(err, s.ClientStream.Trailer()) || s.ctx.Err() != nil {
+			return err
+		}
+
+		next := s.conn.next(s.idx)
+		if s.desc.ClientStreams || s.tried >= len(s.conn.conns) {
+			return err
+		}
+		if openErr := s.open(next); openErr != nil {
+			return err
+		}
+	}
+}
+
+// Close closes the connections to all coordinators.
+func (f *failoverConn) Close() error {
+	var firstErr error
+	for _, conn := range f.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+	"github.com/h3nr1-d14z/hybridgrid/internal/coordinator/ha"
+)
+
+// standbyBuildService answers like a standby coordinator.
+type standbyBuildService struct {
+	pb.UnimplementedBuildServiceServer
+	calls atomic.Int32
+}
+
+func (s *standbyBuildService) HealthCheck(ctx context.Context, req *pb.HealthRequest) (*pb.HealthResponse, error) {
+	s.calls.Add(1)
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(ha.RoleMetadataKey, ha.RoleStandby))
+	return nil, status.Error(codes.Unavailable, "coordinator is a standby")
+}
+
+func (s *standbyBuildService) WatchTask(req *pb.WatchTaskRequest, stream pb.BuildService_WatchTaskServer) error {
+	s.calls.Add(1)
+	stream.SetTrailer(metadata.Pairs(ha.RoleMetadataKey, ha.RoleStandby))
+	return status.Error(codes.Unavailable, "coordinator is a standby")
+}
+
+// busyLeaderBuildService answers like a leader without a free worker.
+type busyLeaderBuildService struct {
+	pb.UnimplementedBuildServiceServer
+}
+
+func (s *busyLeaderBuildService) HealthCheck(ctx context.Context, req *pb.HealthRequest) (*pb.HealthResponse, error) {
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(ha.RoleMetadataKey, ha.RoleLeader))
+	return nil, status.Error(codes.Unavailable, "no workers available")
+}
+
+func (s *busyLeaderBuildService) WatchTask(req *pb.WatchTaskRequest, stream pb.BuildService_WatchTaskServer) error {
+	stream.SetTrailer(metadata.Pairs(ha.RoleMetadataKey, ha.RoleLeader))
+	return stream.Send(&pb.TaskLogLine{TaskId: req.TaskId, Line: "from the leader"})
+}
+
+// dialService serves svc over bufconn and returns a connection to it. A nil
+// svc gives a connection to a coordinator that is down.
+func dialService(t *testing.T, svc pb.BuildServiceServer) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(bufSize)
+	if svc != nil {
+		srv := grpc.NewServer()
+		pb.RegisterBuildServiceServer(srv, svc)
+		go srv.Serve(lis)
+		t.Cleanup(srv.Stop)
+	} else {
+		lis.Close()
+	}
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	return conn
+}
+
+func TestSplitAddresses(t *testing.T) {
+	got := SplitAddresses(" coord-a:9000, ,coord-b:9000,")
+	want := []string{"coord-a:9000", "coord-b:9000"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitAddresses() = %v, want %v", got, want)
+	}
+}
+
+func TestClient_FailsOverToNextCoordinator(t *testing.T) {
+	standby := &standbyBuildService{}
+	fc := &failoverConn{conns: []*grpc.ClientConn{
+		dialService(t, nil),
+		dialService(t, standby),
+		dialService(t, &mockBuildService{}),
+	}}
+	defer fc.Close()
+	client := &Client{
+		config: Config{Timeout: 5 * time.Second},
+		conn:   fc,
+		client: pb.NewBuildServiceClient(fc),
+	}
+
+	resp, err := client.HealthCheck(context.Background())
+	if err != nil {
+		t.Fatalf("HealthCheck failed: %v", err)
+	}
+	if !resp.Healthy {
+		t.Error("Expected the leader's answer")
+	}
+	if standby.calls.Load() != 1 {
+		t.Errorf("Expected the standby to be tried once, got %d calls", standby.calls.Load())
+	}
+
+	// Later calls go straight to the coordinator that answered
+	if _, err := client.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck failed: %v", err)
+	}
+	if standby.calls.Load() != 1 {
+		t.Errorf("Expected no further calls to the standby, got %d", standby.calls.Load())
+	}
+}
+
+func TestClient_AllCoordinatorsUnavailable(t *testing.T) {
+	fc := &failoverConn{conns: []*grpc.ClientConn{
+		dialService(t, nil),
+		dialService(t, &standbyBuildService{}),
+	}}
+	defer fc.Close()
+	client := &Client{
+		config: Config{Timeout: 5 * time.Second},
+		conn:   fc,
+		client: pb.NewBuildServiceClient(fc),
+	}
+
+	_, err := client.HealthCheck(context.Background())
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable, got %v", err)
+	}
+}
+
+func TestNew_MultipleAddresses(t *testing.T) {
+	c, err := New(Config{Address: "coord-a:9000,coord-b:9000", Insecure: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer c.Close()
+
+	fc, ok := c.conn.(*failoverConn)
+	if !ok || len(fc.conns) != 2 {
+		t.Errorf("Expected a connection per coordinator, got %T", c.conn)
+	}
+}
+
+func TestClient_LeaderUnavailableIsFinal(t *testing.T) {
+	other := &standbyBuildService{}
+	fc := &failoverConn{conns: []*grpc.ClientConn{
+		dialService(t, &busyLeaderBuildService{}),
+		dialService(t, other),
+	}}
+	defer fc.Close()
+	client := &Client{
+		config: Config{Timeout: 5 * time.Second},
+		conn:   fc,
+		client: pb.NewBuildServiceClient(fc),
+	}
+
+	_, err := client.HealthCheck(context.Background())
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected the leader's Unavailable, got %v", err)
+	}
+	if other.calls.Load() != 0 {
+		t.Errorf("Expected no failover from the leader, got %d calls to the next coordinator", other.calls.Load())
+	}
+}
+
+func TestClient_StreamFailsOverToNextCoordinator(t *testing.T) {
+	standby := &standbyBuildService{}
+	fc := &failoverConn{conns: []*grpc.ClientConn{
+		dialService(t, nil),
+		dialService(t, standby),
+		dialService(t, &busyLeaderBuildService{}),
+	}}
+	defer fc.Close()
+	client := &Client{
+		config: Config{Timeout: 5 * time.Second},
+		conn:   fc,
+		client: pb.NewBuildServiceClient(fc),
+	}
+
+	var lines []string
+	err := client.WatchTask(context.Background(), "task-1", func(line *pb.TaskLogLine) error {
+		lines = append(lines, line.Line)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WatchTask failed: %v", err)
+	}
+	if !reflect.DeepEqual(lines, []string{"from the leader"}) {
+		t.Errorf("Expected the leader's output, got %v", lines)
+	}
+	if standby.calls.Load() != 1 {
+		t.Errorf("Expected the standby to be tried once, got %d calls", standby.calls.Load())
+	}
+	if got := fc.current.Load(); got != 2 {
+		t.Errorf("Expected later calls to go to the leader, got coordinator %d", got)
+	}
+}
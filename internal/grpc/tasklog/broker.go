@@ -0,0 +1,213 @@
+// Package tasklog fans out the live output of running tasks to WatchTask
+// subscribers.
+package tasklog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+const (
+	// backlogLines is how many recent lines a task keeps for subscribers
+	// that join after it started.
+	backlogLines = 500
+
+	// subscriberBuffer is the number of lines a subscriber may fall behind
+	// before further lines are dropped for it. It holds a full backlog.
+	subscriberBuffer = backlogLines + 500
+
+	// retention is how long the output of a finished task stays available,
+	// so a subscriber racing the task's completion still sees its tail.
+	retention = time.Minute
+)
+
+// ErrNotOwner is returned by Serve when the caller may not follow the
+// task's output.
+var ErrNotOwner = errors.New("tasklog: task belongs to another owner")
+
+// Broker holds the output of tasks by ID. Subscribers may join before a task
+// opens; they then wait for it to start.
+type Broker struct {
+	mu     sync.Mutex
+	topics map[string]*topic
+}
+
+type topic struct {
+	owner    string
+	backlog  []*pb.TaskLogLine
+	subs     map[*subscriber]struct{}
+	opened   bool
+	closed   bool
+	closedAt time.Time
+}
+
+type subscriber struct {
+	ch      chan *pb.TaskLogLine
+	allowed func(owner string) bool // nil allows every owner
+	denied  bool
+}
+
+// NewBroker creates an empty broker.
+func NewBroker() *Broker {
+	return &Broker{topics: make(map[string]*topic)}
+}
+
+// Open starts a new run of taskID, submitted by owner. Output kept from an
+// earlier, finished run with the same ID is discarded; waiting subscribers
+// are kept if they may follow owner's tasks.
+func (b *Broker) Open(taskID, owner string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.evictLocked(time.Now())
+
+	t, ok := b.topics[taskID]
+	if !ok || t.closed {
+		t = &topic{subs: make(map[*subscriber]struct{})}
+		b.topics[taskID] = t
+	}
+	if t.opened && t.owner != owner {
+		t.backlog = nil
+	}
+	t.opened = true
+	t.owner = owner
+	for sub := range t.subs {
+		if sub.allowed != nil && !sub.allowed(owner) {
+			sub.denied = true
+			delete(t.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// Publish sends line to the subscribers of its task. Lines for tasks that
+// are not open are dropped, as are lines a subscriber has no room for.
+func (b *Broker) Publish(line *pb.TaskLogLine) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[line.TaskId]
+	if !ok || !t.opened || t.closed {
+		return
+	}
+	t.backlog = append(t.backlog, line)
+	if len(t.backlog) > backlogLines {
+		t.backlog = t.backlog[len(t.backlog)-backlogLines:]
+	}
+	for sub := range t.subs {
+		select {
+		case sub.ch <- line:
+		default:
+		}
+	}
+}
+
+// Close ends the current run of taskID. Subscriber channels are closed once
+// they have received the remaining output.
+func (b *Broker) Close(taskID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[taskID]
+	if !ok || t.closed {
+		return
+	}
+	t.closed = true
+	t.closedAt = time.Now()
+	for sub := range t.subs {
+		close(sub.ch)
+	}
+	t.subs = nil
+}
+
+// Subscribe follows the output of taskID, starting with its recent backlog.
+// The channel is closed when the task finishes; cancel stops the
+// subscription early and must be called once the caller is done.
+func (b *Broker) Subscribe(taskID string) (<-chan *pb.TaskLogLine, func()) {
+	sub, cancel, _ := b.subscribe(taskID, nil)
+	return sub.ch, cancel
+}
+
+// subscribe is Subscribe for a caller that may only follow the tasks of
+// the owners allowed accepts. It fails with ErrNotOwner if the task is
+// already known to belong to someone else.
+func (b *Broker) subscribe(taskID string, allowed func(owner string) bool) (*subscriber, func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.evictLocked(time.Now())
+
+	t, ok := b.topics[taskID]
+	if ok && t.opened && allowed != nil && !allowed(t.owner) {
+		return nil, nil, ErrNotOwner
+	}
+	sub := &subscriber{ch: make(chan *pb.TaskLogLine, subscriberBuffer), allowed: allowed}
+	if !ok {
+		t = &topic{subs: make(map[*subscriber]struct{})}
+		b.topics[taskID] = t
+	}
+	for _, line := range t.backlog {
+		sub.ch <- line
+	}
+	if t.closed {
+		close(sub.ch)
+		return sub, func() {}, nil
+	}
+	t.subs[sub] = struct{}{}
+
+	return sub, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := t.subs[sub]; !ok {
+			return
+		}
+		delete(t.subs, sub)
+		close(sub.ch)
+		if !t.opened && len(t.subs) == 0 && b.topics[taskID] == t {
+			delete(b.topics, taskID)
+		}
+	}, nil
+}
+
+// Serve subscribes to taskID and passes its output to send until the task
+// finishes, ctx is done or send fails. It backs the WatchTask handlers. If
+// allowed is not nil and rejects the task's owner, Serve returns
+// ErrNotOwner, also when the task starts after Serve was called.
+func (b *Broker) Serve(ctx context.Context, taskID string, allowed func(owner string) bool, send func(*pb.TaskLogLine) error) error {
+	sub, cancel, err := b.subscribe(taskID, allowed)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case line, ok := <-sub.ch:
+			if !ok {
+				b.mu.Lock()
+				denied := sub.denied
+				b.mu.Unlock()
+				if denied {
+					return ErrNotOwner
+				}
+				return nil
+			}
+			if err := send(line); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// evictLocked drops finished tasks whose output has outlived retention.
+func (b *Broker) evictLocked(now time.Time) {
+	for id, t := range b.topics {
+		if t.closed && now.Sub(t.closedAt) > retention {
+			delete(b.topics, id)
+		}
+	}
+}
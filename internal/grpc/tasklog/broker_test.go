@@ -0,0 +1,135 @@
+package tasklog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+func logLine(taskID, text string) *pb.TaskLogLine {
+	return &pb.TaskLogLine{TaskId: taskID, Stream: pb.LogStream_LOG_STREAM_STDOUT, Line: text}
+}
+
+func drain(ch <-chan *pb.TaskLogLine) []string {
+	var lines []string
+	for line := range ch {
+		lines = append(lines, line.Line)
+	}
+	return lines
+}
+
+func TestBroker_SubscribeBeforeOpen(t *testing.T) {
+	b := NewBroker()
+
+	ch, cancel := b.Subscribe("task-1")
+	defer cancel()
+
+	// Output published before the task opens is not part of any run.
+	b.Publish(logLine("task-1", "stale"))
+	b.Open("task-1", "")
+	b.Publish(logLine("task-1", "one"))
+	b.Publish(logLine("other", "ignored"))
+	b.Publish(logLine("task-1", "two"))
+	b.Close("task-1")
+
+	assert.Equal(t, []string{"one", "two"}, drain(ch))
+}
+
+func TestBroker_LateSubscriberGetsBacklog(t *testing.T) {
+	b := NewBroker()
+
+	b.Open("task-1", "")
+	b.Publish(logLine("task-1", "one"))
+	ch, cancel := b.Subscribe("task-1")
+	defer cancel()
+	b.Publish(logLine("task-1", "two"))
+	b.Close("task-1")
+
+	assert.Equal(t, []string{"one", "two"}, drain(ch))
+}
+
+func TestBroker_SubscribeAfterClose(t *testing.T) {
+	b := NewBroker()
+
+	b.Open("task-1", "")
+	b.Publish(logLine("task-1", "done"))
+	b.Close("task-1")
+
+	ch, cancel := b.Subscribe("task-1")
+	defer cancel()
+	assert.Equal(t, []string{"done"}, drain(ch))
+}
+
+func TestBroker_BacklogIsBounded(t *testing.T) {
+	b := NewBroker()
+
+	b.Open("task-1", "")
+	for i := 0; i < backlogLines+10; i++ {
+		b.Publish(logLine("task-1", "line"))
+	}
+	b.Close("task-1")
+
+	ch, cancel := b.Subscribe("task-1")
+	defer cancel()
+	assert.Len(t, drain(ch), backlogLines)
+}
+
+func TestBroker_CancelStopsSubscription(t *testing.T) {
+	b := NewBroker()
+
+	b.Open("task-1", "")
+	ch, cancel := b.Subscribe("task-1")
+	cancel()
+	cancel()
+
+	b.Publish(logLine("task-1", "after cancel"))
+	assert.Empty(t, drain(ch))
+	b.Close("task-1")
+}
+
+func TestBroker_ReopenStartsFreshRun(t *testing.T) {
+	b := NewBroker()
+
+	b.Open("task-1", "")
+	b.Publish(logLine("task-1", "first run"))
+	b.Close("task-1")
+
+	b.Open("task-1", "")
+	ch, cancel := b.Subscribe("task-1")
+	defer cancel()
+	b.Publish(logLine("task-1", "second run"))
+	b.Close("task-1")
+
+	assert.Equal(t, []string{"second run"}, drain(ch))
+}
+
+func TestBroker_ServeChecksOwner(t *testing.T) {
+	b := NewBroker()
+	isBob := func(owner string) bool { return owner == "bob" }
+	send := func(*pb.TaskLogLine) error { return nil }
+
+	// A subscriber waiting for the task is turned away once it starts.
+	waiting, cancel, err := b.subscribe("task-1", isBob)
+	assert.NoError(t, err)
+	defer cancel()
+	b.Open("task-1", "alice")
+	b.Publish(logLine("task-1", "secret"))
+	assert.Empty(t, drain(waiting.ch))
+	assert.True(t, waiting.denied)
+
+	assert.ErrorIs(t, b.Serve(context.Background(), "task-1", isBob, send), ErrNotOwner)
+
+	b.Close("task-1")
+	b.Open("task-2", "bob")
+	b.Publish(logLine("task-2", "mine"))
+	b.Close("task-2")
+	var lines []string
+	assert.NoError(t, b.Serve(context.Background(), "task-2", isBob, func(line *pb.TaskLogLine) error {
+		lines = append(lines, line.Line)
+		return nil
+	}))
+	assert.Equal(t, []string{"mine"}, lines)
+}
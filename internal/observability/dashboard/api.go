@@ -67,6 +67,8 @@ type TaskInfo struct {
 	BuildType    string `json:"build_type"`
 	Status       string `json:"status"`
 	WorkerID     string `json:"worker_id"`
+	Client       string `json:"client,omitempty"`
+	Project      string `json:"project,omitempty"`
 	StartedAt    int64  `json:"started_at"`
 	CompletedAt  int64  `json:"completed_at,omitempty"`
 	DurationMs   int64  `json:"duration_ms,omitempty"`
@@ -75,6 +77,14 @@ type TaskInfo struct {
 	ErrorMessage string `json:"error_message,omitempty"`
 }
 
+// TaskLogLine is one line of a running task's output.
+type TaskLogLine struct {
+	TaskID      string `json:"task_id"`
+	Stream      string `json:"stream"`
+	Line        string `json:"line"`
+	TimestampMs int64  `json:"timestamp_ms"`
+}
+
 // handleStats returns cluster statistics.
 func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -1,7 +1,9 @@
 package dashboard
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -302,6 +304,119 @@ func TestServer_WebSocketUpgrade(t *testing.T) {
 	}
 }
 
+// mockLogSource replays fixed lines for any task to holders of token.
+type mockLogSource struct {
+	token string
+	lines []string
+}
+
+func (m *mockLogSource) WatchTaskLog(ctx context.Context, token, taskID string, send func(*TaskLogLine)) error {
+	if token != m.token {
+		return errors.New("invalid token")
+	}
+	for _, line := range m.lines {
+		send(&TaskLogLine{TaskID: taskID, Stream: "stdout", Line: line})
+	}
+	return nil
+}
+
+func TestServer_WebSocketTaskLog(t *testing.T) {
+	s := New(DefaultConfig(), &mockProvider{})
+	s.hub.SetTaskLogSource(&mockLogSource{token: "secret", lines: []string{"Running Gradle task", "Built app.apk"}})
+
+	go s.hub.Run()
+	defer s.hub.Stop()
+
+	ts := httptest.NewServer(s.server.Handler)
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+	ws, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("WebSocket dial failed: %v", err)
+	}
+	defer ws.Close()
+
+	// Give time for connection to register
+	time.Sleep(50 * time.Millisecond)
+
+	for taskID, token := range map[string]string{"flutter-2": "wrong", "flutter-1": "secret"} {
+		if err := ws.WriteJSON(map[string]interface{}{
+			"type": "subscribe_log",
+			"data": map[string]string{"task_id": taskID, "token": token},
+		}); err != nil {
+			t.Fatalf("WriteJSON failed: %v", err)
+		}
+	}
+
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var lines []string
+	ended, denied := false, false
+	for !ended || !denied {
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			t.Fatalf("ReadMessage failed: %v", err)
+		}
+		// Queued messages are batched into one frame, one per line.
+		for _, raw := range strings.Split(string(data), "\n") {
+			var msg struct {
+				Type MessageType     `json:"type"`
+				Data json.RawMessage `json:"data"`
+			}
+			if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			switch msg.Type {
+			case MessageTypeTaskLog:
+				var line TaskLogLine
+				if err := json.Unmarshal(msg.Data, &line); err != nil {
+					t.Fatalf("Unmarshal line failed: %v", err)
+				}
+				if line.TaskID != "flutter-1" {
+					t.Errorf("TaskID = %q, want flutter-1", line.TaskID)
+				}
+				lines = append(lines, line.Line)
+			case MessageTypeTaskLogError:
+				// The subscription with a wrong token is refused.
+				denied = true
+			case MessageTypeTaskLogEnd:
+				ended = true
+			}
+		}
+	}
+
+	if len(lines) != 2 || lines[0] != "Running Gradle task" || lines[1] != "Built app.apk" {
+		t.Errorf("lines = %q", lines)
+	}
+}
+
+func TestServer_WebSocketRejectsCrossOrigin(t *testing.T) {
+	s := New(DefaultConfig(), &mockProvider{})
+
+	go s.hub.Run()
+	defer s.hub.Stop()
+
+	ts := httptest.NewServer(s.server.Handler)
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+	header := http.Header{"Origin": []string{"http://evil.example.com"}}
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL, header)
+	if err == nil {
+		t.Fatal("cross-origin WebSocket upgrade succeeded")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("response = %v, want 403", resp)
+	}
+
+	header = http.Header{"Origin": []string{ts.URL}}
+	ws, _, err := websocket.DefaultDialer.Dial(wsURL, header)
+	if err != nil {
+		t.Fatalf("same-origin WebSocket dial failed: %v", err)
+	}
+	ws.Close()
+}
+
 func TestDefaultConfig(t *testing.T) {
 	cfg := DefaultConfig()
 
@@ -601,6 +716,10 @@ func TestMessageType_AllTypes(t *testing.T) {
 		{MessageTypeTaskComplete, "task_completed"},
 		{MessageTypePing, "ping"},
 		{MessageTypePong, "pong"},
+		{MessageTypeSubscribeLog, "subscribe_log"},
+		{MessageTypeUnsubscribeLog, "unsubscribe_log"},
+		{MessageTypeTaskLog, "task_log"},
+		{MessageTypeTaskLogEnd, "task_log_end"},
 	}
 
 	for _, tt := range types {
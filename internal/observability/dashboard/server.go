@@ -128,22 +128,26 @@ type EventNotifierFunc struct {
 }
 
 // CreateEventNotifier creates event notifier callbacks for the coordinator.
-func (s *Server) CreateEventNotifier() (onStart func(id, buildType, status, workerID string, startedAt int64), onComplete func(id, buildType, status, workerID string, startedAt, completedAt, durationMs int64, exitCode int32, errorMsg string)) {
-	onStart = func(id, buildType, status, workerID string, startedAt int64) {
+func (s *Server) CreateEventNotifier() (onStart func(id, buildType, status, workerID, client, project string, startedAt int64), onComplete func(id, buildType, status, workerID, client, project string, startedAt, completedAt, durationMs int64, exitCode int32, errorMsg string)) {
+	onStart = func(id, buildType, status, workerID, client, project string, startedAt int64) {
 		s.hub.BroadcastTaskStarted(&TaskInfo{
 			ID:        id,
 			BuildType: buildType,
 			Status:    status,
 			WorkerID:  workerID,
+			Client:    client,
+			Project:   project,
 			StartedAt: startedAt,
 		})
 	}
-	onComplete = func(id, buildType, status, workerID string, startedAt, completedAt, durationMs int64, exitCode int32, errorMsg string) {
+	onComplete = func(id, buildType, status, workerID, client, project string, startedAt, completedAt, durationMs int64, exitCode int32, errorMsg string) {
 		s.hub.BroadcastTaskCompleted(&TaskInfo{
 			ID:           id,
 			BuildType:    buildType,
 			Status:       status,
 			WorkerID:     workerID,
+			Client:       client,
+			Project:      project,
 			StartedAt:    startedAt,
 			CompletedAt:  completedAt,
 			DurationMs:   durationMs,
@@ -1,6 +1,7 @@
 package dashboard
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"sync"
@@ -10,12 +11,12 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// upgrader keeps the default origin check, which refuses upgrades from
+// pages served by another host, so a web page open in a browser on the
+// network cannot read the dashboard's streams.
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for dashboard
-	},
 }
 
 // MessageType represents the type of WebSocket message.
@@ -29,6 +30,16 @@ const (
 	MessageTypeTaskComplete MessageType = "task_completed"
 	MessageTypePing         MessageType = "ping"
 	MessageTypePong         MessageType = "pong"
+
+	// Task log messages. A client sends subscribe_log with {"task_id": ...,
+	// "token": ...} as data, or unsubscribe_log with {"task_id": ...}; it
+	// then receives a task_log message per output line and task_log_end
+	// once the task finishes, or task_log_error if it may not follow it.
+	MessageTypeSubscribeLog   MessageType = "subscribe_log"
+	MessageTypeUnsubscribeLog MessageType = "unsubscribe_log"
+	MessageTypeTaskLog        MessageType = "task_log"
+	MessageTypeTaskLogEnd     MessageType = "task_log_end"
+	MessageTypeTaskLogError   MessageType = "task_log_error"
 )
 
 // Message represents a WebSocket message.
@@ -38,11 +49,26 @@ type Message struct {
 	Data      interface{} `json:"data,omitempty"`
 }
 
+// TaskLogSource provides the live output of tasks.
+type TaskLogSource interface {
+	// WatchTaskLog calls send with each output line of taskID until the
+	// task finishes or ctx is done, if token grants access to the task. It
+	// returns nil when the task finished.
+	WatchTaskLog(ctx context.Context, token, taskID string, send func(*TaskLogLine)) error
+}
+
 // Client represents a WebSocket client connection.
 type Client struct {
 	hub  *Hub
 	conn *websocket.Conn
 	send chan []byte
+
+	logMu sync.Mutex
+	logs  map[string]*logSubscription // Task log subscriptions by task ID
+}
+
+type logSubscription struct {
+	cancel context.CancelFunc
 }
 
 // Hub manages WebSocket client connections.
@@ -56,6 +82,7 @@ type Hub struct {
 	recentEvents [][]byte // Store recent events for new clients
 	eventsMu     sync.RWMutex
 	maxEvents    int
+	logSource    TaskLogSource
 }
 
 // NewHub creates a new WebSocket hub.
@@ -125,6 +152,12 @@ func (h *Hub) Stop() {
 	close(h.done)
 }
 
+// SetTaskLogSource lets clients subscribe to task logs. It must be called
+// before the dashboard starts serving.
+func (h *Hub) SetTaskLogSource(source TaskLogSource) {
+	h.logSource = source
+}
+
 // ClientCount returns the number of connected clients.
 func (h *Hub) ClientCount() int {
 	h.mu.RLock()
@@ -174,6 +207,12 @@ func (h *Hub) GetTasks() []*TaskInfo {
 		if wid, ok := dataMap["worker_id"].(string); ok {
 			task.WorkerID = wid
 		}
+		if client, ok := dataMap["client"].(string); ok {
+			task.Client = client
+		}
+		if project, ok := dataMap["project"].(string); ok {
+			task.Project = project
+		}
 		if startedAt, ok := dataMap["started_at"].(float64); ok {
 			task.StartedAt = int64(startedAt)
 		}
@@ -264,6 +303,88 @@ func (h *Hub) BroadcastTaskCompleted(task *TaskInfo) {
 	})
 }
 
+// subscribeLog starts streaming taskID's log to c, with the access token
+// grants, replacing any earlier subscription of c to the same task.
+func (h *Hub) subscribeLog(c *Client, token, taskID string) {
+	if h.logSource == nil || taskID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := &logSubscription{cancel: cancel}
+	c.logMu.Lock()
+	if c.logs == nil {
+		c.logs = make(map[string]*logSubscription)
+	}
+	if prev, ok := c.logs[taskID]; ok {
+		prev.cancel()
+	}
+	c.logs[taskID] = sub
+	c.logMu.Unlock()
+
+	go func() {
+		defer func() {
+			c.logMu.Lock()
+			if c.logs[taskID] == sub {
+				delete(c.logs, taskID)
+			}
+			c.logMu.Unlock()
+			cancel()
+		}()
+
+		err := h.logSource.WatchTaskLog(ctx, token, taskID, func(line *TaskLogLine) {
+			h.sendTo(c, &Message{Type: MessageTypeTaskLog, Data: line})
+		})
+		switch {
+		case err == nil:
+			h.sendTo(c, &Message{Type: MessageTypeTaskLogEnd, Data: map[string]string{"task_id": taskID}})
+		case ctx.Err() == nil:
+			h.sendTo(c, &Message{Type: MessageTypeTaskLogError, Data: map[string]string{"task_id": taskID, "error": err.Error()}})
+		}
+	}()
+}
+
+// unsubscribeLog stops streaming taskID's log to c.
+func (h *Hub) unsubscribeLog(c *Client, taskID string) {
+	c.logMu.Lock()
+	defer c.logMu.Unlock()
+	if sub, ok := c.logs[taskID]; ok {
+		sub.cancel()
+		delete(c.logs, taskID)
+	}
+}
+
+// unsubscribeAllLogs stops every task log subscription of c.
+func (h *Hub) unsubscribeAllLogs(c *Client) {
+	c.logMu.Lock()
+	defer c.logMu.Unlock()
+	for taskID, sub := range c.logs {
+		sub.cancel()
+		delete(c.logs, taskID)
+	}
+}
+
+// sendTo delivers msg to c alone, dropping it if c is gone or its send
+// buffer is full.
+func (h *Hub) sendTo(c *Client, msg *Message) {
+	msg.Timestamp = time.Now().Unix()
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal WebSocket message")
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if !h.clients[c] {
+		return
+	}
+	select {
+	case c.send <- data:
+	default:
+	}
+}
+
 // handleWebSocket handles WebSocket upgrade requests.
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrader.Upgrade(w, r, nil)
@@ -288,6 +409,7 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 // readPump reads messages from the WebSocket connection.
 func (c *Client) readPump() {
 	defer func() {
+		c.hub.unsubscribeAllLogs(c)
 		c.hub.unregister <- c
 		c.conn.Close()
 	}()
@@ -308,6 +430,24 @@ func (c *Client) readPump() {
 			break
 		}
 
+		var logReq struct {
+			Type MessageType `json:"type"`
+			Data struct {
+				TaskID string `json:"task_id"`
+				Token  string `json:"token"`
+			} `json:"data"`
+		}
+		if json.Unmarshal(message, &logReq) == nil {
+			switch logReq.Type {
+			case MessageTypeSubscribeLog:
+				c.hub.subscribeLog(c, logReq.Data.Token, logReq.Data.TaskID)
+				continue
+			case MessageTypeUnsubscribeLog:
+				c.hub.unsubscribeLog(c, logReq.Data.TaskID)
+				continue
+			}
+		}
+
 		// Handle ping messages
 		var msg Message
 		if json.Unmarshal(message, &msg) == nil && msg.Type == MessageTypePing {
@@ -13,21 +13,26 @@ const namespace = "hybridgrid"
 // Metrics contains all Prometheus metrics for Hybrid-Grid.
 type Metrics struct {
 	// Counters
-	TasksTotal     *prometheus.CounterVec
-	CacheHits      prometheus.Counter
-	CacheMisses    prometheus.Counter
-	FallbacksTotal *prometheus.CounterVec
+	TasksTotal          *prometheus.CounterVec
+	CacheHits           prometheus.Counter
+	CacheMisses         prometheus.Counter
+	FallbacksTotal      *prometheus.CounterVec
+	ClientTasks         *prometheus.CounterVec
+	HandshakeRejections *prometheus.CounterVec
+	TLSReloads          *prometheus.CounterVec
 
 	// Gauges
 	WorkersTotal *prometheus.GaugeVec
 	ActiveTasks  *prometheus.GaugeVec
 	QueueDepth   prometheus.Gauge
+	Leader       prometheus.Gauge
 
 	// Histograms
 	TaskDuration    *prometheus.HistogramVec
 	QueueTime       *prometheus.HistogramVec
 	TransferBytes   *prometheus.HistogramVec
 	WorkerLatencyMs *prometheus.HistogramVec
+	CacheEntryBytes *prometheus.HistogramVec
 
 	// Circuit breaker states
 	CircuitState *prometheus.GaugeVec
@@ -82,6 +87,33 @@ func New() *Metrics {
 			[]string{"reason"},
 		),
 
+		ClientTasks: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "client_tasks_total",
+				Help:      "Total number of tasks by submitting client and project",
+			},
+			[]string{"client", "project", "build_type", "status"},
+		),
+
+		HandshakeRejections: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "worker_handshake_rejections_total",
+				Help:      "Total number of rejected worker handshakes",
+			},
+			[]string{"reason"},
+		),
+
+		TLSReloads: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "tls_reloads_total",
+				Help:      "Total number of TLS certificate reloads by result",
+			},
+			[]string{"result"},
+		),
+
 		// Gauges
 		WorkersTotal: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -106,6 +138,13 @@ func New() *Metrics {
 				Help:      "Number of tasks waiting in queue",
 			},
 		),
+		Leader: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "coordinator_leader",
+				Help:      "1 if this coordinator is the leader dispatching builds, 0 if it is a standby",
+			},
+		),
 
 		// Histograms
 		TaskDuration: prometheus.NewHistogramVec(
@@ -144,6 +183,15 @@ func New() *Metrics {
 			},
 			[]string{"worker"},
 		),
+		CacheEntryBytes: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "cache_entry_bytes",
+				Help:      "Size of cache entries served (hit) or written (store) in bytes",
+				Buckets:   prometheus.ExponentialBuckets(1024, 4, 10), // 1KB to 256MB
+			},
+			[]string{"event"},
+		),
 
 		// Circuit breaker
 		CircuitState: prometheus.NewGaugeVec(
@@ -164,13 +212,18 @@ func (m *Metrics) Register(reg prometheus.Registerer) {
 		m.CacheHits,
 		m.CacheMisses,
 		m.FallbacksTotal,
+		m.ClientTasks,
+		m.HandshakeRejections,
+		m.TLSReloads,
 		m.WorkersTotal,
 		m.ActiveTasks,
 		m.QueueDepth,
+		m.Leader,
 		m.TaskDuration,
 		m.QueueTime,
 		m.TransferBytes,
 		m.WorkerLatencyMs,
+		m.CacheEntryBytes,
 		m.CircuitState,
 	)
 }
@@ -195,6 +248,23 @@ func (m *Metrics) RecordTaskComplete(status TaskStatus, buildType, workerID stri
 	m.TaskDuration.WithLabelValues(buildType, string(status)).Observe(durationSec)
 }
 
+// RecordClientTask records a finished task against the client that
+// submitted it.
+func (m *Metrics) RecordClientTask(client, project, buildType, status string) {
+	m.ClientTasks.WithLabelValues(client, project, buildType, status).Inc()
+}
+
+// RecordHandshakeRejected records a worker handshake refused for reason.
+func (m *Metrics) RecordHandshakeRejected(reason string) {
+	m.HandshakeRejections.WithLabelValues(reason).Inc()
+}
+
+// RecordTLSReload records a TLS certificate reload with result "success"
+// or "failure".
+func (m *Metrics) RecordTLSReload(result string) {
+	m.TLSReloads.WithLabelValues(result).Inc()
+}
+
 // RecordCacheHit records a cache hit.
 func (m *Metrics) RecordCacheHit() {
 	m.CacheHits.Inc()
@@ -205,6 +275,12 @@ func (m *Metrics) RecordCacheMiss() {
 	m.CacheMisses.Inc()
 }
 
+// RecordCacheEntrySize records the size of a cache entry that was served
+// ("hit") or written ("store").
+func (m *Metrics) RecordCacheEntrySize(event string, bytes float64) {
+	m.CacheEntryBytes.WithLabelValues(event).Observe(bytes)
+}
+
 // RecordFallback records a local fallback with reason.
 func (m *Metrics) RecordFallback(reason string) {
 	m.FallbacksTotal.WithLabelValues(reason).Inc()
@@ -225,6 +301,15 @@ func (m *Metrics) SetQueueDepth(depth float64) {
 	m.QueueDepth.Set(depth)
 }
 
+// SetLeader updates the coordinator leader gauge.
+func (m *Metrics) SetLeader(leader bool) {
+	if leader {
+		m.Leader.Set(1)
+	} else {
+		m.Leader.Set(0)
+	}
+}
+
 // RecordQueueTime records time spent in queue.
 func (m *Metrics) RecordQueueTime(buildType string, durationSec float64) {
 	m.QueueTime.WithLabelValues(buildType).Observe(durationSec)
@@ -57,6 +57,59 @@ func TestMetrics_RecordTaskComplete(t *testing.T) {
 	}
 }
 
+func TestMetrics_RecordClientTask(t *testing.T) {
+	m, reg := newTestMetrics()
+
+	m.RecordClientTask("ci-android", "mobile", "flutter", "completed")
+	m.RecordClientTask("ci-android", "mobile", "flutter", "completed")
+	m.RecordClientTask("alice", "", "cpp", "failed")
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+
+	for _, mf := range mfs {
+		if mf.GetName() != "hybridgrid_client_tasks_total" {
+			continue
+		}
+		if len(mf.GetMetric()) != 2 {
+			t.Errorf("Expected 2 metrics, got %d", len(mf.GetMetric()))
+		}
+		return
+	}
+	t.Error("hybridgrid_client_tasks_total metric not found")
+}
+
+func TestMetrics_RecordHandshakeRejected(t *testing.T) {
+	m, reg := newTestMetrics()
+
+	m.RecordHandshakeRejected("not_allowed")
+	m.RecordHandshakeRejected("not_allowed")
+	m.RecordHandshakeRejected("hostname_mismatch")
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+
+	for _, mf := range mfs {
+		if mf.GetName() != "hybridgrid_worker_handshake_rejections_total" {
+			continue
+		}
+		if len(mf.GetMetric()) != 2 {
+			t.Errorf("Expected 2 metrics, got %d", len(mf.GetMetric()))
+		}
+		for _, metric := range mf.GetMetric() {
+			if metric.GetLabel()[0].GetValue() == "not_allowed" && metric.GetCounter().GetValue() != 2 {
+				t.Errorf("Expected 2 not_allowed rejections, got %v", metric.GetCounter().GetValue())
+			}
+		}
+		return
+	}
+	t.Error("hybridgrid_worker_handshake_rejections_total metric not found")
+}
+
 func TestMetrics_RecordCacheHitMiss(t *testing.T) {
 	m, reg := newTestMetrics()
 
@@ -120,6 +173,7 @@ func TestMetrics_WorkerGauges(t *testing.T) {
 	m.SetWorkerCount("unhealthy", "mdns", 1)
 	m.SetActiveTaskCount("worker-1", 5)
 	m.SetQueueDepth(10)
+	m.SetLeader(true)
 
 	mfs, err := reg.Gather()
 	if err != nil {
@@ -142,6 +196,11 @@ func TestMetrics_WorkerGauges(t *testing.T) {
 			if val != 10 {
 				t.Errorf("queue_depth = %f, want 10", val)
 			}
+		case "hybridgrid_coordinator_leader":
+			val := mf.GetMetric()[0].GetGauge().GetValue()
+			if val != 1 {
+				t.Errorf("coordinator_leader = %f, want 1", val)
+			}
 		}
 	}
 }
@@ -194,6 +253,31 @@ func TestMetrics_RecordTransfer(t *testing.T) {
 	}
 }
 
+func TestMetrics_RecordCacheEntrySize(t *testing.T) {
+	m, reg := newTestMetrics()
+
+	m.RecordCacheEntrySize("store", 2048)
+	m.RecordCacheEntrySize("hit", 2048)
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+
+	found := false
+	for _, mf := range mfs {
+		if mf.GetName() == "hybridgrid_cache_entry_bytes" {
+			found = true
+			if len(mf.GetMetric()) != 2 {
+				t.Errorf("Expected 2 metrics, got %d", len(mf.GetMetric()))
+			}
+		}
+	}
+	if !found {
+		t.Error("hybridgrid_cache_entry_bytes metric not found")
+	}
+}
+
 func TestMetrics_RecordWorkerLatency(t *testing.T) {
 	m, reg := newTestMetrics()
 
@@ -335,3 +419,32 @@ func TestMetrics_QueueTime(t *testing.T) {
 		t.Error("hybridgrid_queue_time_seconds metric not found")
 	}
 }
+
+func TestMetrics_RecordTLSReload(t *testing.T) {
+	m, reg := newTestMetrics()
+
+	m.RecordTLSReload("success")
+	m.RecordTLSReload("failure")
+	m.RecordTLSReload("success")
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+
+	for _, mf := range mfs {
+		if mf.GetName() != "hybridgrid_tls_reloads_total" {
+			continue
+		}
+		if len(mf.GetMetric()) != 2 {
+			t.Errorf("Expected 2 metrics, got %d", len(mf.GetMetric()))
+		}
+		for _, metric := range mf.GetMetric() {
+			if metric.GetLabel()[0].GetValue() == "success" && metric.GetCounter().GetValue() != 2 {
+				t.Errorf("Expected 2 successful reloads, got %v", metric.GetCounter().GetValue())
+			}
+		}
+		return
+	}
+	t.Error("hybridgrid_tls_reloads_total metric not found")
+}
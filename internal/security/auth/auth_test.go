@@ -397,3 +397,127 @@ func TestStreamInterceptor_InvalidToken(t *testing.T) {
 		t.Errorf("Error code = %v, want Unauthenticated", status.Code(err))
 	}
 }
+
+func newScopedInterceptor(t *testing.T) *Interceptor {
+	t.Helper()
+	store, err := NewTokenStore(testEntries())
+	if err != nil {
+		t.Fatalf("NewTokenStore failed: %v", err)
+	}
+	return NewInterceptor(Config{
+		Enabled: true,
+		Token:   "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4",
+		Store:   store,
+		MethodScopes: map[string]Scope{
+			"/test/Register": ScopeWorkerRegister,
+			"/test/Submit":   ScopeClientSubmit,
+		},
+	})
+}
+
+func TestInterceptor_ScopedTokens(t *testing.T) {
+	interceptor := newScopedInterceptor(t)
+
+	tests := []struct {
+		name     string
+		token    string
+		method   string
+		wantCode codes.Code
+		wantName string
+	}{
+		{"worker registers", testWorkerToken, "/test/Register", codes.OK, "build-farm"},
+		{"worker cannot submit", testWorkerToken, "/test/Submit", codes.PermissionDenied, ""},
+		{"client submits", testClientToken, "/test/Submit", codes.OK, "ci"},
+		{"client cannot register", testClientToken, "/test/Register", codes.PermissionDenied, ""},
+		{"unscoped method", testClientToken, "/test/Other", codes.OK, "ci"},
+		{"shared token is admin", "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4", "/test/Register", codes.OK, "shared"},
+		{"unknown token", "00000000000000000000000000000000", "/test/Submit", codes.Unauthenticated, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				if id, ok := FromContext(ctx); ok {
+					gotName = id.Name
+				}
+				return "success", nil
+			}
+			md := metadata.New(map[string]string{AuthorizationKey: "Bearer " + tt.token})
+			_, err := interceptor.UnaryServerInterceptor()(
+				metadata.NewIncomingContext(context.Background(), md),
+				nil,
+				&grpc.UnaryServerInfo{FullMethod: tt.method},
+				handler,
+			)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("code = %v, want %v (%v)", status.Code(err), tt.wantCode, err)
+			}
+			if gotName != tt.wantName {
+				t.Errorf("identity = %q, want %q", gotName, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestInterceptor_Authenticate(t *testing.T) {
+	interceptor := newScopedInterceptor(t)
+
+	if id, ok := interceptor.Authenticate(testClientToken); !ok || id.Name != "ci" {
+		t.Errorf("Authenticate(client token) = %v, %v; want ci", id, ok)
+	}
+	if id, ok := interceptor.Authenticate("a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4"); !ok || !id.HasScope(ScopeAdmin) {
+		t.Errorf("Authenticate(shared token) = %v, %v; want an admin", id, ok)
+	}
+	if _, ok := interceptor.Authenticate(""); ok {
+		t.Error("Authenticate accepted an empty token")
+	}
+}
+
+type tokenRequest struct{ token string }
+
+func (r *tokenRequest) GetAuthToken() string { return r.token }
+
+func TestInterceptor_TokenInRequestBody(t *testing.T) {
+	interceptor := newScopedInterceptor(t)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "success", nil
+	}
+
+	_, err := interceptor.UnaryServerInterceptor()(
+		context.Background(),
+		&tokenRequest{token: testWorkerToken},
+		&grpc.UnaryServerInfo{FullMethod: "/test/Register"},
+		handler,
+	)
+	if err != nil {
+		t.Errorf("token in request body returned error: %v", err)
+	}
+}
+
+func TestStreamInterceptor_AttachesIdentity(t *testing.T) {
+	interceptor := newScopedInterceptor(t)
+
+	var gotName string
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		if id, ok := FromContext(stream.Context()); ok {
+			gotName = id.Name
+		}
+		return nil
+	}
+
+	md := metadata.New(map[string]string{AuthorizationKey: "Bearer " + testClientToken})
+	err := interceptor.StreamServerInterceptor()(
+		nil,
+		&mockServerStream{ctx: metadata.NewIncomingContext(context.Background(), md)},
+		&grpc.StreamServerInfo{FullMethod: "/test/Submit"},
+		handler,
+	)
+	if err != nil {
+		t.Fatalf("stream interceptor returned error: %v", err)
+	}
+	if gotName != "ci" {
+		t.Errorf("identity = %q, want %q", gotName, "ci")
+	}
+}
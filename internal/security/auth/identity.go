@@ -0,0 +1,64 @@
+package auth
+
+import "context"
+
+// Scope is a permission granted to a token.
+type Scope string
+
+const (
+	// ScopeWorkerRegister allows a worker to register with the coordinator.
+	ScopeWorkerRegister Scope = "worker:register"
+
+	// ScopeClientSubmit allows a client to submit, watch and cancel builds.
+	ScopeClientSubmit Scope = "client:submit"
+
+	// ScopeAdmin grants every scope.
+	ScopeAdmin Scope = "admin"
+)
+
+// ParseScope validates a scope name.
+func ParseScope(s string) (Scope, bool) {
+	switch scope := Scope(s); scope {
+	case ScopeWorkerRegister, ScopeClientSubmit, ScopeAdmin:
+		return scope, true
+	default:
+		return "", false
+	}
+}
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	// Name identifies the token, such as a user, team or CI pipeline.
+	Name string
+
+	// Project groups tokens for attribution. May be empty.
+	Project string
+
+	// Scopes lists the permissions granted to the caller.
+	Scopes []Scope
+}
+
+// HasScope reports whether the identity was granted scope, either directly
+// or through ScopeAdmin.
+func (id *Identity) HasScope(scope Scope) bool {
+	for _, s := range id.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+type identityKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the identity attached by the server interceptor, if
+// any.
+func FromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(*Identity)
+	return id, ok && id != nil
+}
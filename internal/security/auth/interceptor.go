@@ -25,6 +25,23 @@ type Config struct {
 
 	// SkipMethods lists method names that skip authentication
 	SkipMethods []string `yaml:"skip_methods" json:"skip_methods"`
+
+	// Store holds named, scoped tokens. Token, if also set, is accepted as
+	// an admin token named "shared".
+	Store *TokenStore `yaml:"-" json:"-"`
+
+	// MethodScopes maps full method names to the scope they require.
+	// Methods not listed only require a valid token.
+	MethodScopes map[string]Scope `yaml:"method_scopes" json:"method_scopes"`
+}
+
+// sharedIdentity is the caller authenticated by the shared Config.Token.
+var sharedIdentity = &Identity{Name: "shared", Scopes: []Scope{ScopeAdmin}}
+
+// legacyTokenRequest is implemented by requests that carry a token in their
+// body, such as HandshakeRequest from workers that predate metadata tokens.
+type legacyTokenRequest interface {
+	GetAuthToken() string
 }
 
 // DefaultConfig returns default auth configuration.
@@ -37,9 +54,11 @@ func DefaultConfig() Config {
 
 // Interceptor provides gRPC authentication interceptors.
 type Interceptor struct {
-	enabled     bool
-	token       string
-	skipMethods map[string]bool
+	enabled      bool
+	token        string
+	skipMethods  map[string]bool
+	store        *TokenStore
+	methodScopes map[string]Scope
 }
 
 // NewInterceptor creates a new authentication interceptor.
@@ -50,9 +69,11 @@ func NewInterceptor(cfg Config) *Interceptor {
 	}
 
 	return &Interceptor{
-		enabled:     cfg.Enabled,
-		token:       cfg.Token,
-		skipMethods: skipMethods,
+		enabled:      cfg.Enabled,
+		token:        cfg.Token,
+		skipMethods:  skipMethods,
+		store:        cfg.Store,
+		methodScopes: cfg.MethodScopes,
 	}
 }
 
@@ -72,11 +93,16 @@ func (i *Interceptor) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 			return handler(ctx, req)
 		}
 
-		if err := i.validateContext(ctx, info.FullMethod); err != nil {
+		var bodyToken string
+		if r, ok := req.(legacyTokenRequest); ok {
+			bodyToken = r.GetAuthToken()
+		}
+		id, err := i.authenticate(ctx, info.FullMethod, bodyToken)
+		if err != nil {
 			return nil, err
 		}
 
-		return handler(ctx, req)
+		return handler(NewContext(ctx, id), req)
 	}
 }
 
@@ -96,40 +122,84 @@ func (i *Interceptor) StreamServerInterceptor() grpc.StreamServerInterceptor {
 			return handler(srv, ss)
 		}
 
-		if err := i.validateContext(ss.Context(), info.FullMethod); err != nil {
+		id, err := i.authenticate(ss.Context(), info.FullMethod, "")
+		if err != nil {
 			return err
 		}
 
-		return handler(srv, ss)
+		return handler(srv, &identityStream{ServerStream: ss, ctx: NewContext(ss.Context(), id)})
 	}
 }
 
-// validateContext validates the authentication token from context metadata.
-func (i *Interceptor) validateContext(ctx context.Context, method string) error {
+// identityStream carries the caller's identity in its context.
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate resolves the caller of method from the token in the context
+// metadata, falling back to bodyToken, and checks the method's scope.
+func (i *Interceptor) authenticate(ctx context.Context, method, bodyToken string) (*Identity, error) {
+	token, err := tokenFromContext(ctx)
+	if err != nil {
+		if bodyToken == "" {
+			log.Warn().Str("method", method).Err(err).Msg("Auth failed")
+			return nil, err
+		}
+		token = bodyToken
+	}
+
+	id, ok := i.Authenticate(token)
+	if !ok {
+		log.Warn().Str("method", method).Msg("Auth failed: invalid token")
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if scope := i.methodScopes[method]; scope != "" && !id.HasScope(scope) {
+		log.Warn().Str("method", method).Str("identity", id.Name).Str("scope", string(scope)).
+			Msg("Auth failed: missing scope")
+		return nil, status.Errorf(codes.PermissionDenied, "token %q lacks scope %s", id.Name, scope)
+	}
+
+	return id, nil
+}
+
+// Authenticate returns the identity of token: a named token from the store,
+// or the shared token, which is an admin. It lets callers outside gRPC,
+// such as the dashboard, resolve tokens the same way.
+func (i *Interceptor) Authenticate(token string) (*Identity, bool) {
+	var id *Identity
+	if i.store != nil {
+		id, _ = i.store.Authenticate(token)
+	}
+	if id == nil && i.token != "" && ValidateToken(token, i.token) {
+		id = sharedIdentity
+	}
+	return id, id != nil
+}
+
+// tokenFromContext extracts the bearer token from the context metadata.
+func tokenFromContext(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		log.Warn().Str("method", method).Msg("Auth failed: no metadata")
-		return status.Error(codes.Unauthenticated, "no metadata provided")
+		return "", status.Error(codes.Unauthenticated, "no metadata provided")
 	}
 
 	values := md.Get(AuthorizationKey)
 	if len(values) == 0 {
-		log.Warn().Str("method", method).Msg("Auth failed: no authorization header")
-		return status.Error(codes.Unauthenticated, "authorization token required")
+		return "", status.Error(codes.Unauthenticated, "authorization token required")
 	}
 
 	token, ok := ParseBearerToken(values[0])
 	if !ok {
-		log.Warn().Str("method", method).Msg("Auth failed: invalid bearer format")
-		return status.Error(codes.Unauthenticated, "invalid authorization format")
-	}
-
-	if !ValidateToken(token, i.token) {
-		log.Warn().Str("method", method).Msg("Auth failed: invalid token")
-		return status.Error(codes.Unauthenticated, "invalid token")
+		return "", status.Error(codes.Unauthenticated, "invalid authorization format")
 	}
 
-	return nil
+	return token, nil
 }
 
 // ContextWithToken adds an authentication token to an outgoing context.
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidJoinToken is returned for a join token that is unknown, used or
+// expired.
+var ErrInvalidJoinToken = errors.New("invalid or expired join token")
+
+// JoinTokenEntry describes one unused join token. Like API tokens, only the
+// hash is stored.
+type JoinTokenEntry struct {
+	// Name is the only worker name the token can enroll.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// IPAddresses are the only IP addresses the enrolled certificate is
+	// valid for.
+	IPAddresses []string  `yaml:"ip_addresses,omitempty" json:"ip_addresses,omitempty"`
+	Hash        string    `yaml:"hash" json:"hash"`
+	ExpiresAt   time.Time `yaml:"expires_at" json:"expires_at"`
+}
+
+// JoinTokenFile is the on-disk format of a join tokens file.
+type JoinTokenFile struct {
+	Tokens []JoinTokenEntry `yaml:"tokens" json:"tokens"`
+}
+
+// JoinTokens hands out and redeems one-time join tokens kept in a file, so
+// unused tokens survive a coordinator restart and a used token stays used.
+type JoinTokens struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJoinTokens manages the join tokens file at path. The file need not
+// exist yet.
+func NewJoinTokens(path string) *JoinTokens {
+	return &JoinTokens{path: path}
+}
+
+// Create generates a join token valid for ttl, bound to the worker name and
+// IP addresses ips, and records its hash. The token itself is not stored.
+func (j *JoinTokens) Create(name string, ips []string, ttl time.Duration) (string, JoinTokenEntry, error) {
+	if name == "" {
+		return "", JoinTokenEntry{}, fmt.Errorf("join token requires a worker name")
+	}
+	for _, ip := range ips {
+		if net.ParseIP(ip) == nil {
+			return "", JoinTokenEntry{}, fmt.Errorf("invalid IP address %q", ip)
+		}
+	}
+	if ttl <= 0 {
+		return "", JoinTokenEntry{}, fmt.Errorf("join token lifetime must be positive")
+	}
+	token, err := GenerateToken()
+	if err != nil {
+		return "", JoinTokenEntry{}, err
+	}
+	entry := JoinTokenEntry{Name: name, IPAddresses: ips, Hash: HashToken(token), ExpiresAt: time.Now().Add(ttl).UTC()}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	file, err := j.read()
+	if err != nil {
+		return "", JoinTokenEntry{}, err
+	}
+	file.Tokens = append(pruneExpired(file.Tokens, time.Now()), entry)
+	if err := writeYAMLFile(j.path, file); err != nil {
+		return "", JoinTokenEntry{}, err
+	}
+	return token, entry, nil
+}
+
+// Redeem consumes token and returns its entry. A token can be redeemed only
+// once, and not after it expires.
+func (j *JoinTokens) Redeem(token string) (JoinTokenEntry, error) {
+	if len(token) < MinTokenLength {
+		return JoinTokenEntry{}, ErrInvalidJoinToken
+	}
+	hash := HashToken(token)
+	now := time.Now()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	file, err := j.read()
+	if err != nil {
+		return JoinTokenEntry{}, err
+	}
+
+	found := -1
+	for i, e := range file.Tokens {
+		if e.Hash == hash {
+			found = i
+			break
+		}
+	}
+	if found < 0 {
+		return JoinTokenEntry{}, ErrInvalidJoinToken
+	}
+	entry := file.Tokens[found]
+	file.Tokens = pruneExpired(append(file.Tokens[:found], file.Tokens[found+1:]...), now)
+	if err := writeYAMLFile(j.path, file); err != nil {
+		return JoinTokenEntry{}, err
+	}
+	if !now.Before(entry.ExpiresAt) {
+		return JoinTokenEntry{}, ErrInvalidJoinToken
+	}
+	return entry, nil
+}
+
+func (j *JoinTokens) read() (*JoinTokenFile, error) {
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &JoinTokenFile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file JoinTokenFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse join tokens file %s: %w", j.path, err)
+	}
+	return &file, nil
+}
+
+func pruneExpired(entries []JoinTokenEntry, now time.Time) []JoinTokenEntry {
+	kept := entries[:0]
+	for _, e := range entries {
+		if now.Before(e.ExpiresAt) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJoinTokens_RedeemOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "join-tokens.yaml")
+	tokens := NewJoinTokens(path)
+
+	token, entry, err := tokens.Create("farm-1", []string{"10.0.0.5"}, time.Hour)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if entry.Hash != HashToken(token) {
+		t.Error("entry does not hold the token's hash")
+	}
+
+	// A second instance sees the same file, as after a restart.
+	got, err := NewJoinTokens(path).Redeem(token)
+	if err != nil {
+		t.Fatalf("Redeem failed: %v", err)
+	}
+	if got.Name != "farm-1" {
+		t.Errorf("Name = %q, want farm-1", got.Name)
+	}
+	if len(got.IPAddresses) != 1 || got.IPAddresses[0] != "10.0.0.5" {
+		t.Errorf("IPAddresses = %v, want [10.0.0.5]", got.IPAddresses)
+	}
+
+	if _, err := tokens.Redeem(token); !errors.Is(err, ErrInvalidJoinToken) {
+		t.Errorf("second Redeem() error = %v, want ErrInvalidJoinToken", err)
+	}
+}
+
+func TestJoinTokens_RejectsExpiredAndUnknown(t *testing.T) {
+	tokens := NewJoinTokens(filepath.Join(t.TempDir(), "join-tokens.yaml"))
+
+	token, _, err := tokens.Create("farm-1", nil, time.Nanosecond)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := tokens.Redeem(token); !errors.Is(err, ErrInvalidJoinToken) {
+		t.Errorf("Redeem() of expired token error = %v, want ErrInvalidJoinToken", err)
+	}
+
+	if _, err := tokens.Redeem(testClientToken); !errors.Is(err, ErrInvalidJoinToken) {
+		t.Errorf("Redeem() of unknown token error = %v, want ErrInvalidJoinToken", err)
+	}
+	if _, _, err := tokens.Create("farm-1", nil, 0); err == nil {
+		t.Error("expected error for zero lifetime")
+	}
+	if _, _, err := tokens.Create("", nil, time.Hour); err == nil {
+		t.Error("expected error for a token without a worker name")
+	}
+	if _, _, err := tokens.Create("farm-1", []string{"not-an-ip"}, time.Hour); err == nil {
+		t.Error("expected error for an invalid IP address")
+	}
+}
@@ -0,0 +1,275 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// hashPrefix marks the hash algorithm of a stored token.
+const hashPrefix = "sha256:"
+
+// TokenEntry describes one named token in a tokens file. Only the hash of
+// the token is stored; the token itself is shown once, when it is created.
+type TokenEntry struct {
+	Name    string   `yaml:"name" json:"name"`
+	Project string   `yaml:"project,omitempty" json:"project,omitempty"`
+	Scopes  []string `yaml:"scopes" json:"scopes"`
+	Hash    string   `yaml:"hash" json:"hash"`
+}
+
+// TokenFile is the on-disk format of a tokens file.
+type TokenFile struct {
+	Tokens []TokenEntry `yaml:"tokens" json:"tokens"`
+}
+
+// HashToken returns the form in which token is stored in a tokens file.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hashPrefix + hex.EncodeToString(sum[:])
+}
+
+// NewTokenEntry generates a token and the entry that grants it scopes. The
+// token is not stored anywhere and must be handed to its user.
+func NewTokenEntry(name, project string, scopes []string) (string, TokenEntry, error) {
+	if name == "" {
+		return "", TokenEntry{}, fmt.Errorf("token name is required")
+	}
+	if len(scopes) == 0 {
+		return "", TokenEntry{}, fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if _, ok := ParseScope(scope); !ok {
+			return "", TokenEntry{}, fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+
+	token, err := GenerateToken()
+	if err != nil {
+		return "", TokenEntry{}, err
+	}
+	return token, TokenEntry{Name: name, Project: project, Scopes: scopes, Hash: HashToken(token)}, nil
+}
+
+// ReadTokenFile parses the tokens file at path.
+func ReadTokenFile(path string) (*TokenFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file TokenFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse tokens file %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// WriteTokenFile replaces the tokens file at path. The file is swapped in
+// atomically so a watching coordinator never reads it half-written.
+func WriteTokenFile(path string, file *TokenFile) error {
+	return writeYAMLFile(path, file)
+}
+
+// writeYAMLFile atomically replaces path with v encoded as YAML, readable
+// only by its owner.
+func writeYAMLFile(path string, v interface{}) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Add appends entry, refusing a name that is already taken.
+func (f *TokenFile) Add(entry TokenEntry) error {
+	for _, e := range f.Tokens {
+		if e.Name == entry.Name {
+			return fmt.Errorf("token %q already exists", entry.Name)
+		}
+	}
+	f.Tokens = append(f.Tokens, entry)
+	return nil
+}
+
+// Remove deletes the token called name and reports whether it existed.
+func (f *TokenFile) Remove(name string) bool {
+	for i, e := range f.Tokens {
+		if e.Name == name {
+			f.Tokens = append(f.Tokens[:i], f.Tokens[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// TokenStore authenticates named tokens. It is safe for concurrent use and
+// may be reloaded while serving requests, so tokens can be revoked without
+// a restart.
+type TokenStore struct {
+	mu     sync.RWMutex
+	byHash map[string]*Identity
+
+	// Set for stores loaded from a file.
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// NewTokenStore creates a store holding entries.
+func NewTokenStore(entries []TokenEntry) (*TokenStore, error) {
+	s := &TokenStore{}
+	if err := s.Set(entries); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// LoadTokenStore creates a store from the tokens file at path. Use Reload or
+// Watch to pick up later changes to the file.
+func LoadTokenStore(path string) (*TokenStore, error) {
+	s := &TokenStore{path: path}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Set replaces the tokens in the store. On error the store is unchanged.
+func (s *TokenStore) Set(entries []TokenEntry) error {
+	byHash := make(map[string]*Identity, len(entries))
+	names := make(map[string]bool, len(entries))
+	for i, e := range entries {
+		if e.Name == "" {
+			return fmt.Errorf("token %d: name is required", i+1)
+		}
+		if names[e.Name] {
+			return fmt.Errorf("token %q: duplicate name", e.Name)
+		}
+		names[e.Name] = true
+
+		hash := strings.ToLower(e.Hash)
+		digest, ok := strings.CutPrefix(hash, hashPrefix)
+		if !ok {
+			return fmt.Errorf("token %q: hash must start with %q", e.Name, hashPrefix)
+		}
+		if b, err := hex.DecodeString(digest); err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("token %q: hash is not a SHA-256 digest", e.Name)
+		}
+		if _, dup := byHash[hash]; dup {
+			return fmt.Errorf("token %q: duplicate hash", e.Name)
+		}
+
+		if len(e.Scopes) == 0 {
+			return fmt.Errorf("token %q: at least one scope is required", e.Name)
+		}
+		scopes := make([]Scope, 0, len(e.Scopes))
+		for _, name := range e.Scopes {
+			scope, ok := ParseScope(name)
+			if !ok {
+				return fmt.Errorf("token %q: unknown scope %q", e.Name, name)
+			}
+			scopes = append(scopes, scope)
+		}
+
+		byHash[hash] = &Identity{Name: e.Name, Project: e.Project, Scopes: scopes}
+	}
+
+	s.mu.Lock()
+	s.byHash = byHash
+	s.mu.Unlock()
+	return nil
+}
+
+// Authenticate returns the identity of token, or false if the store does not
+// hold it.
+func (s *TokenStore) Authenticate(token string) (*Identity, bool) {
+	if len(token) < MinTokenLength {
+		return nil, false
+	}
+	hash := HashToken(token)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	id, ok := s.byHash[hash]
+	return id, ok
+}
+
+// Len returns the number of tokens in the store.
+func (s *TokenStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.byHash)
+}
+
+// Reload re-reads the tokens file if it changed since it was last read. It
+// reports whether the tokens were replaced. Stores not loaded from a file
+// are left alone.
+func (s *TokenStore) Reload() (bool, error) {
+	if s.path == "" {
+		return false, nil
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false, fmt.Errorf("stat tokens file: %w", err)
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return false, nil
+	}
+
+	file, err := ReadTokenFile(s.path)
+	if err != nil {
+		return false, err
+	}
+	if err := s.Set(file.Tokens); err != nil {
+		return false, fmt.Errorf("tokens file %s: %w", s.path, err)
+	}
+	s.modTime = info.ModTime()
+	s.size = info.Size()
+	return true, nil
+}
+
+// Watch reloads the tokens file every interval until ctx is done. A file
+// that fails to load is logged and the previous tokens stay in effect.
+func (s *TokenStore) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := s.Reload()
+			if err != nil {
+				log.Warn().Err(err).Msg("Failed to reload tokens file; keeping previous tokens")
+				continue
+			}
+			if changed {
+				log.Info().Str("path", s.path).Int("tokens", s.Len()).Msg("Reloaded tokens file")
+			}
+		}
+	}
+}
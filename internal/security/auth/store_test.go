@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	testWorkerToken = "w1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+	testClientToken = "c1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+)
+
+func testEntries() []TokenEntry {
+	return []TokenEntry{
+		{Name: "build-farm", Scopes: []string{"worker:register"}, Hash: HashToken(testWorkerToken)},
+		{Name: "ci", Project: "mobile", Scopes: []string{"client:submit"}, Hash: HashToken(testClientToken)},
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	hash := HashToken(testWorkerToken)
+	if hash[:len(hashPrefix)] != hashPrefix {
+		t.Errorf("HashToken() = %q, want %q prefix", hash, hashPrefix)
+	}
+	if hash != HashToken(testWorkerToken) {
+		t.Error("HashToken() is not deterministic")
+	}
+	if hash == HashToken(testClientToken) {
+		t.Error("different tokens have the same hash")
+	}
+}
+
+func TestTokenStore_Authenticate(t *testing.T) {
+	store, err := NewTokenStore(testEntries())
+	if err != nil {
+		t.Fatalf("NewTokenStore failed: %v", err)
+	}
+
+	id, ok := store.Authenticate(testClientToken)
+	if !ok {
+		t.Fatal("client token not authenticated")
+	}
+	if id.Name != "ci" || id.Project != "mobile" {
+		t.Errorf("identity = %+v, want ci/mobile", id)
+	}
+	if !id.HasScope(ScopeClientSubmit) || id.HasScope(ScopeWorkerRegister) {
+		t.Errorf("scopes = %v, want only %s", id.Scopes, ScopeClientSubmit)
+	}
+
+	if _, ok := store.Authenticate("unknown0000000000000000000000000000000000000"); ok {
+		t.Error("unknown token authenticated")
+	}
+	if _, ok := store.Authenticate(""); ok {
+		t.Error("empty token authenticated")
+	}
+}
+
+func TestTokenStore_SetRejectsInvalidEntries(t *testing.T) {
+	valid := HashToken(testWorkerToken)
+	tests := []struct {
+		name  string
+		entry TokenEntry
+	}{
+		{"missing name", TokenEntry{Scopes: []string{"admin"}, Hash: valid}},
+		{"plaintext hash", TokenEntry{Name: "a", Scopes: []string{"admin"}, Hash: testWorkerToken}},
+		{"short digest", TokenEntry{Name: "a", Scopes: []string{"admin"}, Hash: "sha256:abcd"}},
+		{"no scopes", TokenEntry{Name: "a", Hash: valid}},
+		{"unknown scope", TokenEntry{Name: "a", Scopes: []string{"root"}, Hash: valid}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTokenStore([]TokenEntry{tt.entry}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	t.Run("duplicate name", func(t *testing.T) {
+		entries := testEntries()
+		entries[1].Name = entries[0].Name
+		if _, err := NewTokenStore(entries); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestIdentity_AdminHasEveryScope(t *testing.T) {
+	id := &Identity{Name: "ops", Scopes: []Scope{ScopeAdmin}}
+	for _, scope := range []Scope{ScopeWorkerRegister, ScopeClientSubmit, ScopeAdmin} {
+		if !id.HasScope(scope) {
+			t.Errorf("admin lacks %s", scope)
+		}
+	}
+}
+
+func TestTokenStore_ReloadRevokesRemovedTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.yaml")
+	write := func(content string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	write("tokens:\n"+
+		"  - name: build-farm\n    scopes: [worker:register]\n    hash: "+HashToken(testWorkerToken)+"\n"+
+		"  - name: ci\n    scopes: [client:submit]\n    hash: "+HashToken(testClientToken)+"\n",
+		now.Add(-time.Minute))
+
+	store, err := LoadTokenStore(path)
+	if err != nil {
+		t.Fatalf("LoadTokenStore failed: %v", err)
+	}
+	if store.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", store.Len())
+	}
+
+	changed, err := store.Reload()
+	if err != nil || changed {
+		t.Fatalf("Reload() of unchanged file = %v, %v", changed, err)
+	}
+
+	write("tokens:\n  - name: build-farm\n    scopes: [worker:register]\n    hash: "+HashToken(testWorkerToken)+"\n", now)
+	changed, err = store.Reload()
+	if err != nil || !changed {
+		t.Fatalf("Reload() = %v, %v, want reload", changed, err)
+	}
+	if _, ok := store.Authenticate(testClientToken); ok {
+		t.Error("revoked token still authenticated")
+	}
+	if _, ok := store.Authenticate(testWorkerToken); !ok {
+		t.Error("remaining token no longer authenticated")
+	}
+
+	// A broken file leaves the previous tokens in effect.
+	write("tokens: [", now.Add(time.Minute))
+	if _, err := store.Reload(); err == nil {
+		t.Error("expected error for invalid file")
+	}
+	if _, ok := store.Authenticate(testWorkerToken); !ok {
+		t.Error("tokens lost after failed reload")
+	}
+}
+
+func TestTokenFile_CreateAndRevoke(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.yaml")
+
+	token, entry, err := NewTokenEntry("ci", "mobile", []string{"client:submit"})
+	if err != nil {
+		t.Fatalf("NewTokenEntry failed: %v", err)
+	}
+	if entry.Hash != HashToken(token) {
+		t.Error("entry does not hold the token's hash")
+	}
+
+	file := &TokenFile{}
+	if err := file.Add(entry); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := file.Add(entry); err == nil {
+		t.Error("expected error adding a duplicate name")
+	}
+	if err := WriteTokenFile(path, file); err != nil {
+		t.Fatalf("WriteTokenFile failed: %v", err)
+	}
+
+	store, err := LoadTokenStore(path)
+	if err != nil {
+		t.Fatalf("LoadTokenStore failed: %v", err)
+	}
+	if id, ok := store.Authenticate(token); !ok || id.Project != "mobile" {
+		t.Fatalf("Authenticate() = %+v, %v", id, ok)
+	}
+
+	file, err = ReadTokenFile(path)
+	if err != nil {
+		t.Fatalf("ReadTokenFile failed: %v", err)
+	}
+	if !file.Remove("ci") || file.Remove("ci") {
+		t.Fatal("Remove() should succeed exactly once")
+	}
+	if err := WriteTokenFile(path, file); err != nil {
+		t.Fatalf("WriteTokenFile failed: %v", err)
+	}
+	if err := os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if _, ok := store.Authenticate(token); ok {
+		t.Error("revoked token still authenticated")
+	}
+}
+
+func TestNewTokenEntry_RejectsUnknownScope(t *testing.T) {
+	if _, _, err := NewTokenEntry("ci", "", []string{"root"}); err == nil {
+		t.Error("expected error")
+	}
+	if _, _, err := NewTokenEntry("", "", []string{"admin"}); err == nil {
+		t.Error("expected error")
+	}
+}
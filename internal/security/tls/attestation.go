@@ -0,0 +1,181 @@
+package tls
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Reasons a worker fails attestation, used as metric labels.
+const (
+	RejectNoClientCert     = "no_client_cert"
+	RejectNotAllowed       = "not_allowed"
+	RejectHostnameMismatch = "hostname_mismatch"
+	RejectWorkerIDMismatch = "worker_id_mismatch"
+)
+
+// workerIDPrefix is how hg-worker derives its worker ID from its hostname.
+const workerIDPrefix = "worker-"
+
+// EnrolledWorkerDomain is the DNS domain Enroll issues certificates under.
+// Coordinator and client certificates may not use it, so the allow-list
+// pattern "*.workers.hybridgrid.internal" admits enrolled workers only and
+// an enrolled certificate can never pass for the coordinator.
+const EnrolledWorkerDomain = "workers.hybridgrid.internal"
+
+// dnsLabel matches a single DNS label, such as a short hostname.
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// EnrolledWorkerName returns the certificate name Enroll issues for the
+// worker name, a single DNS label: "<name>.workers.hybridgrid.internal".
+// Attestation matches the worker's hostname against the first label.
+func EnrolledWorkerName(name string) (string, error) {
+	name = strings.ToLower(name)
+	if !dnsLabel.MatchString(name) {
+		return "", fmt.Errorf("tls: worker name %q is not a single DNS label", name)
+	}
+	return name + "." + EnrolledWorkerDomain, nil
+}
+
+// InEnrolledWorkerDomain reports whether name lies in EnrolledWorkerDomain.
+func InEnrolledWorkerDomain(name string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	return name == EnrolledWorkerDomain || strings.HasSuffix(name, "."+EnrolledWorkerDomain)
+}
+
+// AttestationError explains why a worker's certificate was not accepted.
+type AttestationError struct {
+	// Reason is one of the Reject* constants.
+	Reason string
+	msg    string
+}
+
+func (e *AttestationError) Error() string {
+	return e.msg
+}
+
+func attestationErrorf(reason, format string, args ...interface{}) error {
+	return &AttestationError{Reason: reason, msg: fmt.Sprintf(format, args...)}
+}
+
+// WorkerAllowlist decides which mTLS client certificates may register as
+// workers. Patterns are shell globs such as "*.build.example.com" matched,
+// case-insensitively, against the certificate's DNS SANs and common name.
+type WorkerAllowlist struct {
+	patterns []string
+}
+
+// ValidatePatterns checks that every allow-list pattern is a valid glob.
+func ValidatePatterns(patterns []string) error {
+	for _, p := range patterns {
+		if p == "" {
+			return fmt.Errorf("tls: empty worker allow-list pattern")
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("tls: invalid worker allow-list pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// NewWorkerAllowlist creates an allow-list from patterns. Invalid patterns
+// never match; use ValidatePatterns to reject them up front.
+func NewWorkerAllowlist(patterns []string) *WorkerAllowlist {
+	lower := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		lower = append(lower, strings.ToLower(p))
+	}
+	return &WorkerAllowlist{patterns: lower}
+}
+
+// Match returns the first identity of cert matched by the allow-list.
+func (a *WorkerAllowlist) Match(cert *x509.Certificate) (string, bool) {
+	for _, id := range CertIdentities(cert) {
+		for _, p := range a.patterns {
+			if ok, err := path.Match(p, id); err == nil && ok {
+				return id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Attest checks that cert is allowed and that the hostname and worker ID a
+// worker reports belong to it. The hostname must be one of the certificate's
+// identities, or the first label of one. A worker ID, if set, must be such a
+// name too, optionally prefixed with "worker-". Errors are
+// *AttestationError.
+func (a *WorkerAllowlist) Attest(cert *x509.Certificate, hostname, workerID string) error {
+	if cert == nil {
+		return attestationErrorf(RejectNoClientCert, "no verified client certificate")
+	}
+	if _, ok := a.Match(cert); !ok {
+		return attestationErrorf(RejectNotAllowed, "certificate %s is not in the worker allow-list", describeCert(cert))
+	}
+
+	ids := CertIdentities(cert)
+	if !matchesHost(hostname, ids) {
+		return attestationErrorf(RejectHostnameMismatch, "hostname %q does not match certificate %s", hostname, describeCert(cert))
+	}
+	if workerID != "" && !matchesHost(workerID, ids) && !matchesHost(strings.TrimPrefix(workerID, workerIDPrefix), ids) {
+		return attestationErrorf(RejectWorkerIDMismatch, "worker_id %q does not match certificate %s", workerID, describeCert(cert))
+	}
+	return nil
+}
+
+// CertIdentities returns the lower-cased DNS SANs and common name of cert,
+// without duplicates.
+func CertIdentities(cert *x509.Certificate) []string {
+	ids := make([]string, 0, len(cert.DNSNames)+1)
+	for _, name := range cert.DNSNames {
+		ids = append(ids, strings.ToLower(name))
+	}
+	if cn := strings.ToLower(cert.Subject.CommonName); cn != "" && !slices.Contains(ids, cn) {
+		ids = append(ids, cn)
+	}
+	return ids
+}
+
+// PeerCertificate returns the verified client certificate of the gRPC peer
+// in ctx. It is absent unless the connection uses mTLS.
+func PeerCertificate(ctx context.Context) (*x509.Certificate, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return nil, false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	return info.State.VerifiedChains[0][0], true
+}
+
+// matchesHost reports whether name equals one of ids or the first label of
+// one, so a short hostname matches its fully qualified certificate name.
+func matchesHost(name string, ids []string) bool {
+	name = strings.ToLower(name)
+	if name == "" {
+		return false
+	}
+	for _, id := range ids {
+		if id == name || strings.HasPrefix(id, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func describeCert(cert *x509.Certificate) string {
+	ids := CertIdentities(cert)
+	if len(ids) == 0 {
+		return fmt.Sprintf("(serial %s)", cert.SerialNumber)
+	}
+	return fmt.Sprintf("%q", strings.Join(ids, ", "))
+}
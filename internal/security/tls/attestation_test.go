@@ -0,0 +1,148 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func workerCert(cn string, dnsNames ...string) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(7),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     dnsNames,
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	if err := ValidatePatterns([]string{"*.build.example.com", "farm-[0-9]*"}); err != nil {
+		t.Errorf("ValidatePatterns() error = %v", err)
+	}
+	if err := ValidatePatterns([]string{"farm-[0-9"}); err == nil {
+		t.Error("expected error for malformed pattern")
+	}
+	if err := ValidatePatterns([]string{""}); err == nil {
+		t.Error("expected error for empty pattern")
+	}
+}
+
+func TestWorkerAllowlist_Match(t *testing.T) {
+	allow := NewWorkerAllowlist([]string{"*.Build.Example.com", "mac-mini-?"})
+
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		want bool
+	}{
+		{"SAN matches", workerCert("", "farm-1.build.example.com"), true},
+		{"CN matches", workerCert("mac-mini-3"), true},
+		{"case-insensitive", workerCert("", "FARM-1.BUILD.EXAMPLE.COM"), true},
+		{"other domain", workerCert("", "farm-1.build.example.org"), false},
+		{"no match", workerCert("laptop", "laptop.example.com"), false},
+		{"no names", workerCert(""), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := allow.Match(tt.cert); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkerAllowlist_Attest(t *testing.T) {
+	allow := NewWorkerAllowlist([]string{"*.build.example.com"})
+	cert := workerCert("farm-1", "farm-1.build.example.com")
+
+	tests := []struct {
+		name       string
+		cert       *x509.Certificate
+		hostname   string
+		workerID   string
+		wantReason string
+	}{
+		{"fqdn hostname", cert, "farm-1.build.example.com", "", ""},
+		{"short hostname", cert, "farm-1", "", ""},
+		{"worker id from hostname", cert, "farm-1", "worker-farm-1", ""},
+		{"worker id is cert name", cert, "farm-1", "farm-1.build.example.com", ""},
+		{"no cert", nil, "farm-1", "", RejectNoClientCert},
+		{"not allowed", workerCert("laptop", "laptop.example.com"), "laptop", "", RejectNotAllowed},
+		{"hostname mismatch", cert, "farm-2", "", RejectHostnameMismatch},
+		{"partial label", cert, "farm", "", RejectHostnameMismatch},
+		{"empty hostname", cert, "", "", RejectHostnameMismatch},
+		{"worker id mismatch", cert, "farm-1", "worker-farm-2", RejectWorkerIDMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := allow.Attest(tt.cert, tt.hostname, tt.workerID)
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Errorf("Attest() error = %v", err)
+				}
+				return
+			}
+			var attestErr *AttestationError
+			if !errors.As(err, &attestErr) {
+				t.Fatalf("Attest() error = %v, want *AttestationError", err)
+			}
+			if attestErr.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", attestErr.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestEnrolledWorkerName(t *testing.T) {
+	name, err := EnrolledWorkerName("Farm-1")
+	if err != nil {
+		t.Fatalf("EnrolledWorkerName() error = %v", err)
+	}
+	if name != "farm-1.workers.hybridgrid.internal" {
+		t.Errorf("EnrolledWorkerName() = %q", name)
+	}
+	if !InEnrolledWorkerDomain(name) {
+		t.Errorf("InEnrolledWorkerDomain(%q) = false", name)
+	}
+
+	for _, bad := range []string{"", "coordinator.example.com", "farm_1", "-farm"} {
+		if _, err := EnrolledWorkerName(bad); err == nil {
+			t.Errorf("EnrolledWorkerName(%q) accepted", bad)
+		}
+	}
+	for _, other := range []string{"coordinator.example.com", "hybridgrid.internal", "evilworkers.hybridgrid.internal"} {
+		if InEnrolledWorkerDomain(other) {
+			t.Errorf("InEnrolledWorkerDomain(%q) = true", other)
+		}
+	}
+}
+
+func TestPeerCertificate(t *testing.T) {
+	if _, ok := PeerCertificate(context.Background()); ok {
+		t.Error("expected no certificate without a peer")
+	}
+
+	plain := peer.NewContext(context.Background(), &peer.Peer{})
+	if _, ok := PeerCertificate(plain); ok {
+		t.Error("expected no certificate for an insecure peer")
+	}
+
+	cert := workerCert("farm-1")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+	got, ok := PeerCertificate(ctx)
+	if !ok || got != cert {
+		t.Errorf("PeerCertificate() = %v, %v, want the verified leaf", got, ok)
+	}
+}
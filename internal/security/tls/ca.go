@@ -0,0 +1,366 @@
+package tls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// File names in a certificate directory.
+const (
+	CACertFile     = "ca.crt"
+	CAKeyFile      = "ca.key"
+	JoinTokensFile = "join-tokens.yaml"
+)
+
+// Default certificate lifetimes.
+const (
+	DefaultCAValidity   = 10 * 365 * 24 * time.Hour
+	DefaultLeafValidity = 365 * 24 * time.Hour
+)
+
+// fingerprintPrefix marks the hash algorithm of a certificate fingerprint.
+const fingerprintPrefix = "sha256:"
+
+// Usage selects the extended key usages of an issued certificate.
+type Usage int
+
+const (
+	// UsageServer allows the certificate to serve TLS.
+	UsageServer Usage = 1 << iota
+	// UsageClient allows the certificate to authenticate as an mTLS client.
+	UsageClient
+)
+
+// CA is a certificate authority that issues the certificates used for
+// TLS and mTLS between coordinator, workers and clients.
+type CA struct {
+	Cert    *x509.Certificate
+	certPEM []byte
+	key     crypto.Signer
+}
+
+// InitCA creates a self-signed CA in dir. It refuses to overwrite an
+// existing CA, since every certificate it issued would stop verifying.
+func InitCA(dir, commonName string, validity time.Duration) (*CA, error) {
+	certFile := filepath.Join(dir, CACertFile)
+	keyFile := filepath.Join(dir, CAKeyFile)
+	for _, f := range []string{certFile, keyFile} {
+		if _, err := os.Stat(f); err == nil {
+			return nil, fmt.Errorf("tls: %s already exists", f)
+		}
+	}
+	if validity <= 0 {
+		validity = DefaultCAValidity
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"Hybrid-Grid"}},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("tls: create CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ca := &CA{Cert: cert, certPEM: encodeCert(der), key: key}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	if err := writePEMFiles(certFile, ca.certPEM, keyFile, keyPEM); err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+// LoadCA reads the CA created by InitCA from dir.
+func LoadCA(dir string) (*CA, error) {
+	pair, err := tls.LoadX509KeyPair(filepath.Join(dir, CACertFile), filepath.Join(dir, CAKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("tls: load CA: %w", err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("tls: %s is not a CA certificate", filepath.Join(dir, CACertFile))
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("tls: unsupported CA key type %T", pair.PrivateKey)
+	}
+	return &CA{Cert: cert, certPEM: encodeCert(cert.Raw), key: key}, nil
+}
+
+// CertPEM returns the PEM encoding of the CA certificate.
+func (ca *CA) CertPEM() []byte {
+	return ca.certPEM
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the CA certificate, which
+// lets a new worker trust the coordinator before it has the CA.
+func (ca *CA) Fingerprint() string {
+	return Fingerprint(ca.Cert)
+}
+
+// Issue creates a key and a certificate for name. name is the certificate's
+// CN and, unless it is an IP address, its first DNS SAN; hosts adds further
+// DNS names or IP addresses. The returned certificate PEM is followed by the
+// CA certificate, so peers that pin the CA by fingerprint receive it.
+func (ca *CA) Issue(name string, usage Usage, hosts []string, validity time.Duration) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	certPEM, err = ca.sign(&key.PublicKey, name, usage, hosts, validity)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err = encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certPEM, keyPEM, nil
+}
+
+// SignCSR issues a certificate for the key in a PEM-encoded certificate
+// request. The names in the request are ignored; the certificate is issued
+// for name and hosts as in Issue.
+func (ca *CA) SignCSR(csrPEM []byte, name string, usage Usage, hosts []string, validity time.Duration) ([]byte, error) {
+	csr, err := parseCSR(csrPEM)
+	if err != nil {
+		return nil, err
+	}
+	return ca.sign(csr.PublicKey, name, usage, hosts, validity)
+}
+
+// CheckCSR reports whether csrPEM is a well-formed, self-signed certificate
+// request that SignCSR would accept.
+func CheckCSR(csrPEM []byte) error {
+	_, err := parseCSR(csrPEM)
+	return err
+}
+
+func parseCSR(csrPEM []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, errors.New("tls: invalid certificate request PEM")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("tls: parse certificate request: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("tls: certificate request signature: %w", err)
+	}
+	return csr, nil
+}
+
+func (ca *CA) sign(pub crypto.PublicKey, name string, usage Usage, hosts []string, validity time.Duration) ([]byte, error) {
+	if name == "" {
+		return nil, errors.New("tls: certificate name is required")
+	}
+	if validity <= 0 {
+		validity = DefaultLeafValidity
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	if notAfter := ca.Cert.NotAfter; template.NotAfter.After(notAfter) {
+		template.NotAfter = notAfter
+	}
+	if usage&UsageServer != 0 {
+		template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageServerAuth)
+	}
+	if usage&UsageClient != 0 {
+		template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+	}
+	for _, host := range append([]string{name}, hosts...) {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if host != "" {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, pub, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("tls: sign certificate: %w", err)
+	}
+	return append(encodeCert(der), ca.certPEM...), nil
+}
+
+// NewCSR creates a key and a PEM-encoded certificate request for name, for
+// a worker to send to Enroll.
+func NewCSR(name string) (csrPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: name},
+	}, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err = encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), keyPEM, nil
+}
+
+// WriteKeyPair writes certPEM and keyPEM to <name>.crt and <name>.key in
+// dir and returns their paths. The key is readable only by its owner.
+func WriteKeyPair(dir, name string, certPEM, keyPEM []byte) (certFile, keyFile string, err error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", "", err
+	}
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	if err := writePEMFiles(certFile, certPEM, keyFile, keyPEM); err != nil {
+		return "", "", err
+	}
+	return certFile, keyFile, nil
+}
+
+// Fingerprint returns the SHA-256 fingerprint of cert as "sha256:<hex>".
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return fingerprintPrefix + hex.EncodeToString(sum[:])
+}
+
+// PinnedCATLS returns a client TLS configuration that trusts a server whose
+// chain includes a CA with the given fingerprint. It is used to enroll
+// before the CA certificate is available locally.
+func PinnedCATLS(fingerprint string) (*tls.Config, error) {
+	want := strings.ToLower(fingerprint)
+	if !strings.HasPrefix(want, fingerprintPrefix) {
+		return nil, fmt.Errorf("tls: CA fingerprint must start with %q", fingerprintPrefix)
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Verification is done against the pinned CA below.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			certs := make([]*x509.Certificate, 0, len(rawCerts))
+			for _, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return err
+				}
+				certs = append(certs, cert)
+			}
+			if len(certs) == 0 {
+				return errors.New("tls: server sent no certificate")
+			}
+
+			roots := x509.NewCertPool()
+			intermediates := x509.NewCertPool()
+			for _, cert := range certs[1:] {
+				if Fingerprint(cert) == want {
+					roots.AddCert(cert)
+				} else {
+					intermediates.AddCert(cert)
+				}
+			}
+			_, err := certs[0].Verify(x509.VerifyOptions{
+				Roots:         roots,
+				Intermediates: intermediates,
+				KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			})
+			if err != nil {
+				return fmt.Errorf("tls: server certificate is not signed by CA %s: %w", fingerprint, err)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// CertMatchesFingerprint reports whether the PEM-encoded certificate has
+// the given fingerprint.
+func CertMatchesFingerprint(certPEM []byte, fingerprint string) bool {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+	return Fingerprint(cert) == strings.ToLower(fingerprint)
+}
+
+func randomSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func encodeCert(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func writePEMFiles(certFile string, certPEM []byte, keyFile string, keyPEM []byte) error {
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(certFile, certPEM, 0o644)
+}
@@ -0,0 +1,219 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"os"
+	"testing"
+)
+
+func parseCertChain(t *testing.T, data []byte) []*x509.Certificate {
+	t.Helper()
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("ParseCertificate failed: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		t.Fatal("no certificates in PEM")
+	}
+	return certs
+}
+
+func TestInitCA_RefusesToOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := InitCA(dir, "Test CA", 0)
+	if err != nil {
+		t.Fatalf("InitCA failed: %v", err)
+	}
+	if !ca.Cert.IsCA {
+		t.Error("CA certificate is not a CA")
+	}
+	if _, err := InitCA(dir, "Test CA", 0); err == nil {
+		t.Error("expected error re-initializing an existing CA")
+	}
+
+	loaded, err := LoadCA(dir)
+	if err != nil {
+		t.Fatalf("LoadCA failed: %v", err)
+	}
+	if loaded.Fingerprint() != ca.Fingerprint() {
+		t.Errorf("loaded fingerprint = %s, want %s", loaded.Fingerprint(), ca.Fingerprint())
+	}
+}
+
+func TestCA_Issue(t *testing.T) {
+	ca, err := InitCA(t.TempDir(), "Test CA", 0)
+	if err != nil {
+		t.Fatalf("InitCA failed: %v", err)
+	}
+
+	certPEM, keyPEM, err := ca.Issue("farm-1.build.example.com", UsageServer|UsageClient, []string{"10.0.0.5"}, 0)
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		t.Fatalf("issued key pair does not load: %v", err)
+	}
+
+	chain := parseCertChain(t, certPEM)
+	if len(chain) != 2 || Fingerprint(chain[1]) != ca.Fingerprint() {
+		t.Fatal("issued certificate is not followed by the CA")
+	}
+	leaf := chain[0]
+	if len(leaf.DNSNames) != 1 || leaf.DNSNames[0] != "farm-1.build.example.com" {
+		t.Errorf("DNSNames = %v", leaf.DNSNames)
+	}
+	if len(leaf.IPAddresses) != 1 || leaf.IPAddresses[0].String() != "10.0.0.5" {
+		t.Errorf("IPAddresses = %v", leaf.IPAddresses)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	for _, usage := range []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth} {
+		if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{usage}}); err != nil {
+			t.Errorf("Verify(%v) failed: %v", usage, err)
+		}
+	}
+
+	clientPEM, _, err := ca.Issue("alice", UsageClient, nil, 0)
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	client := parseCertChain(t, clientPEM)[0]
+	if _, err := client.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}); err == nil {
+		t.Error("client certificate should not be valid for serving")
+	}
+}
+
+func TestCA_SignCSR(t *testing.T) {
+	ca, err := InitCA(t.TempDir(), "Test CA", 0)
+	if err != nil {
+		t.Fatalf("InitCA failed: %v", err)
+	}
+
+	csrPEM, keyPEM, err := NewCSR("requested-name")
+	if err != nil {
+		t.Fatalf("NewCSR failed: %v", err)
+	}
+	if err := CheckCSR(csrPEM); err != nil {
+		t.Fatalf("CheckCSR failed: %v", err)
+	}
+	if err := CheckCSR([]byte("not a csr")); err == nil {
+		t.Error("expected error for invalid CSR")
+	}
+
+	certPEM, err := ca.SignCSR(csrPEM, "farm-1", UsageServer|UsageClient, nil, 0)
+	if err != nil {
+		t.Fatalf("SignCSR failed: %v", err)
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		t.Fatalf("signed certificate does not match the CSR key: %v", err)
+	}
+	if cn := parseCertChain(t, certPEM)[0].Subject.CommonName; cn != "farm-1" {
+		t.Errorf("CN = %q, want the name given to SignCSR", cn)
+	}
+}
+
+func TestPinnedCATLS(t *testing.T) {
+	ca, err := InitCA(t.TempDir(), "Test CA", 0)
+	if err != nil {
+		t.Fatalf("InitCA failed: %v", err)
+	}
+	certPEM, keyPEM, err := ca.Issue("127.0.0.1", UsageServer, nil, 0)
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{pair}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_, _ = io.Copy(io.Discard, conn)
+			conn.Close()
+		}
+	}()
+
+	dial := func(fingerprint string) error {
+		cfg, err := PinnedCATLS(fingerprint)
+		if err != nil {
+			return err
+		}
+		conn, err := tls.Dial("tcp", lis.Addr().String(), cfg)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	if err := dial(ca.Fingerprint()); err != nil {
+		t.Errorf("dial with the CA's fingerprint failed: %v", err)
+	}
+
+	other, err := InitCA(t.TempDir(), "Other CA", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dial(other.Fingerprint()); err == nil {
+		t.Error("dial with another CA's fingerprint succeeded")
+	}
+	if _, err := PinnedCATLS("abcd"); err == nil {
+		t.Error("expected error for fingerprint without algorithm")
+	}
+
+	if !CertMatchesFingerprint(ca.CertPEM(), ca.Fingerprint()) {
+		t.Error("CertMatchesFingerprint() = false for the CA itself")
+	}
+	if CertMatchesFingerprint(other.CertPEM(), ca.Fingerprint()) {
+		t.Error("CertMatchesFingerprint() = true for another CA")
+	}
+}
+
+func TestWriteKeyPair(t *testing.T) {
+	ca, err := InitCA(t.TempDir(), "Test CA", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM, err := ca.Issue("alice", UsageClient, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile, err := WriteKeyPair(dir, "alice", certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("WriteKeyPair failed: %v", err)
+	}
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		t.Fatalf("written key pair does not load: %v", err)
+	}
+	info, err := os.Stat(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("key file mode = %o, want 600", perm)
+	}
+}
@@ -1,10 +1,10 @@
 package tls
 
 import (
+	"context"
 	"crypto/tls"
-	"crypto/x509"
 	"fmt"
-	"os"
+	"net"
 
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc/credentials"
@@ -20,33 +20,33 @@ func LoadServerTLS(cfg Config) (*tls.Config, error) {
 		return nil, nil
 	}
 
-	// Load server certificate
-	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	// Load server certificate, and client CA if mTLS is enabled
+	caFile := ""
+	if cfg.RequireClientCert {
+		caFile = cfg.ClientCA
+	}
+	r, err := reloaderFor(cfg.CertFile, cfg.KeyFile, caFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load server certificate: %w", err)
 	}
+	if r.current() == nil {
+		return nil, fmt.Errorf("failed to load server certificate: cert_file and key_file are required")
+	}
 
 	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cert},
+		Certificates: []tls.Certificate{*r.current()},
 		MinVersion:   cfg.MinVersion,
 	}
-
-	// Load client CA if mTLS is enabled
-	if cfg.RequireClientCert && cfg.ClientCA != "" {
-		caCert, err := os.ReadFile(cfg.ClientCA)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read client CA: %w", err)
-		}
-
-		caPool := x509.NewCertPool()
-		if !caPool.AppendCertsFromPEM(caCert) {
-			return nil, fmt.Errorf("failed to parse client CA certificate")
-		}
-
-		tlsConfig.ClientCAs = caPool
+	if caFile != "" {
+		tlsConfig.ClientCAs = r.caPool()
 		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
 	}
 
+	// Pick up rotated certificates for new connections
+	tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return r.serverConfig(tlsConfig), nil
+	}
+
 	log.Info().
 		Str("cert", cfg.CertFile).
 		Bool("mtls", cfg.RequireClientCert).
@@ -56,14 +56,24 @@ func LoadServerTLS(cfg Config) (*tls.Config, error) {
 	return tlsConfig, nil
 }
 
-// LoadClientTLS creates a TLS configuration for the client.
+// LoadClientTLS creates a TLS configuration for the client. When it
+// verifies the server, set ServerName to the host being dialled, including
+// an IP address; configs without one only accept servers reached by a DNS
+// name. ClientCredentials takes the name from the address gRPC dials.
 func LoadClientTLS(cfg Config) (*tls.Config, error) {
+	tlsConfig, _, err := loadClientTLS(cfg)
+	return tlsConfig, err
+}
+
+// loadClientTLS is LoadClientTLS, also returning the reloader that verifies
+// the server, or nil if the built-in verification applies.
+func loadClientTLS(cfg Config) (*tls.Config, *reloader, error) {
 	if err := cfg.Validate(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if !cfg.Enabled {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	tlsConfig := &tls.Config{
@@ -71,28 +81,36 @@ func LoadClientTLS(cfg Config) (*tls.Config, error) {
 		InsecureSkipVerify: cfg.InsecureSkipVerify,
 	}
 
-	// Load client certificate if mTLS is enabled
+	// Load client certificate if mTLS is enabled, and CA certificate to
+	// verify server
+	certFile, keyFile := "", ""
 	if cfg.CertFile != "" && cfg.KeyFile != "" {
-		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load client certificate: %w", err)
-		}
-		tlsConfig.Certificates = []tls.Certificate{cert}
+		certFile, keyFile = cfg.CertFile, cfg.KeyFile
+	}
+	if certFile == "" && cfg.ClientCA == "" {
+		return tlsConfig, nil, nil
+	}
+	r, err := reloaderFor(certFile, keyFile, cfg.ClientCA)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load client certificate: %w", err)
 	}
 
-	// Load CA certificate to verify server
+	// Present and verify against the current files, so rotated
+	// certificates are used for new connections
+	if certFile != "" {
+		tlsConfig.Certificates = []tls.Certificate{*r.current()}
+		tlsConfig.GetClientCertificate = r.getClientCertificate
+	}
+	var verifier *reloader
 	if cfg.ClientCA != "" {
-		caCert, err := os.ReadFile(cfg.ClientCA)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		tlsConfig.RootCAs = r.caPool()
+		if !cfg.InsecureSkipVerify {
+			verifier = r
+			tlsConfig.InsecureSkipVerify = true // verified by VerifyConnection
+			tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+				return r.verifyServer(cs, tlsConfig.ServerName)
+			}
 		}
-
-		caPool := x509.NewCertPool()
-		if !caPool.AppendCertsFromPEM(caCert) {
-			return nil, fmt.Errorf("failed to parse CA certificate")
-		}
-
-		tlsConfig.RootCAs = caPool
 	}
 
 	log.Debug().
@@ -100,7 +118,7 @@ func LoadClientTLS(cfg Config) (*tls.Config, error) {
 		Bool("skip_verify", cfg.InsecureSkipVerify).
 		Msg("Loaded client TLS configuration")
 
-	return tlsConfig, nil
+	return tlsConfig, verifier, nil
 }
 
 // ServerCredentials returns gRPC server credentials from the config.
@@ -115,16 +133,69 @@ func ServerCredentials(cfg Config) (credentials.TransportCredentials, error) {
 	return credentials.NewTLS(tlsConfig), nil
 }
 
-// ClientCredentials returns gRPC client credentials from the config.
+// ClientCredentials returns gRPC client credentials from the config. The
+// server is verified against the host of the address being dialled.
 func ClientCredentials(cfg Config) (credentials.TransportCredentials, error) {
-	tlsConfig, err := LoadClientTLS(cfg)
+	tlsConfig, verifier, err := loadClientTLS(cfg)
 	if err != nil {
 		return nil, err
 	}
 	if tlsConfig == nil {
 		return nil, nil // TLS not enabled
 	}
-	return credentials.NewTLS(tlsConfig), nil
+	if verifier == nil {
+		return credentials.NewTLS(tlsConfig), nil
+	}
+	return &verifyingCredentials{
+		TransportCredentials: credentials.NewTLS(tlsConfig),
+		config:               tlsConfig,
+		verifier:             verifier,
+	}, nil
+}
+
+// verifyingCredentials are TLS credentials whose server verification,
+// done by a reloader, is bound to the host of each dialled address. gRPC
+// hands that host to crypto/tls on a clone of the config, which the
+// config's own VerifyConnection does not see.
+type verifyingCredentials struct {
+	credentials.TransportCredentials
+	config   *tls.Config
+	verifier *reloader
+}
+
+// ClientHandshake verifies the server against the configured ServerName,
+// or else the host of authority.
+func (c *verifyingCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	cfg := c.config.Clone()
+	if cfg.ServerName == "" {
+		host, _, err := net.SplitHostPort(authority)
+		if err != nil {
+			host = authority
+		}
+		cfg.ServerName = host
+	}
+	serverName := cfg.ServerName
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		return c.verifier.verifyServer(cs, serverName)
+	}
+	return credentials.NewTLS(cfg).ClientHandshake(ctx, authority, rawConn)
+}
+
+// Clone returns a copy of the credentials.
+func (c *verifyingCredentials) Clone() credentials.TransportCredentials {
+	return &verifyingCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		config:               c.config.Clone(),
+		verifier:             c.verifier,
+	}
+}
+
+// OverrideServerName sets the name servers are verified against.
+//
+// Deprecated: as for credentials.TransportCredentials.
+func (c *verifyingCredentials) OverrideServerName(serverName string) error {
+	c.config.ServerName = serverName
+	return c.TransportCredentials.OverrideServerName(serverName)
 }
 
 // MustLoadServerTLS loads server TLS config, panics on error.
@@ -0,0 +1,280 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/h3nr1-d14z/hybridgrid/internal/observability/metrics"
+)
+
+// DefaultReloadInterval is how often WatchReloads checks certificate files
+// for changes.
+const DefaultReloadInterval = 30 * time.Second
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// reloader holds the key pair and CA of a Config and re-reads them when
+// their files change, so certificates can be rotated without a restart.
+// Configs returned by LoadServerTLS and LoadClientTLS read the current
+// files on every handshake; connections already established keep theirs.
+type reloader struct {
+	certFile, keyFile, caFile string
+
+	mu     sync.RWMutex
+	cert   *tls.Certificate
+	pool   *x509.CertPool
+	stamps map[string]fileStamp
+}
+
+// reloaders holds one reloader per set of files, shared by the server and
+// client configs that use them.
+var reloaders = struct {
+	sync.Mutex
+	byFiles map[string]*reloader
+}{byFiles: make(map[string]*reloader)}
+
+// reloaderFor returns the reloader for the files of cfg, loading them if
+// this is the first config to use them.
+func reloaderFor(certFile, keyFile, caFile string) (*reloader, error) {
+	key := reloaderKey(certFile, keyFile, caFile)
+
+	reloaders.Lock()
+	defer reloaders.Unlock()
+	if r, ok := reloaders.byFiles[key]; ok {
+		if _, err := r.reload(false); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+	r := &reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	reloaders.byFiles[key] = r
+	return r, nil
+}
+
+func reloaderKey(certFile, keyFile, caFile string) string {
+	return certFile + "\x00" + keyFile + "\x00" + caFile
+}
+
+func (r *reloader) files() []string {
+	var files []string
+	for _, f := range []string{r.certFile, r.keyFile, r.caFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// load reads the files. On error the previous certificate and CA are kept.
+func (r *reloader) load() error {
+	stamps := make(map[string]fileStamp)
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+		stamps[f] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	var cert *tls.Certificate
+	if r.certFile != "" && r.keyFile != "" {
+		pair, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load certificate: %w", err)
+		}
+		cert = &pair
+	}
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		caCert, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return fmt.Errorf("failed to parse CA certificate")
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.pool, r.stamps = cert, pool, stamps
+	r.mu.Unlock()
+	return nil
+}
+
+// changed reports whether any file differs from when it was last loaded.
+func (r *reloader) changed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			// Missing mid-rotation; reload and report it
+			return true
+		}
+		if s := r.stamps[f]; !info.ModTime().Equal(s.modTime) || info.Size() != s.size {
+			return true
+		}
+	}
+	return false
+}
+
+// reload re-reads the files if they changed, or unconditionally if force
+// is set, and logs and counts the outcome. It reports whether a reload was
+// attempted.
+func (r *reloader) reload(force bool) (bool, error) {
+	if !force && !r.changed() {
+		return false, nil
+	}
+
+	err := r.load()
+	m := metrics.Default()
+	if err != nil {
+		m.RecordTLSReload("failure")
+		log.Error().Err(err).Str("cert", r.certFile).Str("ca", r.caFile).
+			Msg("Failed to reload TLS certificates; keeping previous ones")
+		return true, err
+	}
+
+	m.RecordTLSReload("success")
+	event := log.Info().Str("cert", r.certFile).Str("ca", r.caFile)
+	if cert := r.current(); cert != nil && cert.Leaf != nil {
+		event = event.Time("not_after", cert.Leaf.NotAfter)
+	}
+	event.Msg("Reloaded TLS certificates")
+	return true, nil
+}
+
+func (r *reloader) current() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+func (r *reloader) caPool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.pool
+}
+
+// serverConfig returns a copy of base with the current certificate and
+// client CA, for GetConfigForClient.
+func (r *reloader) serverConfig(base *tls.Config) *tls.Config {
+	c := base.Clone()
+	c.GetConfigForClient = nil
+	if cert := r.current(); cert != nil {
+		c.Certificates = []tls.Certificate{*cert}
+	}
+	if c.ClientCAs != nil {
+		c.ClientCAs = r.caPool()
+	}
+	return c
+}
+
+// getClientCertificate presents the current certificate, if any.
+func (r *reloader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	if cert := r.current(); cert != nil {
+		return cert, nil
+	}
+	return &tls.Certificate{}, nil
+}
+
+// verifyServer verifies the server's chain against the current CA and its
+// certificate against serverName, the host that was dialled. It replaces
+// the built-in verification, which only sees the CA the config was created
+// with. The name the handshake reports cannot stand in for serverName:
+// crypto/tls leaves it empty when an IP address was dialled.
+func (r *reloader) verifyServer(cs tls.ConnectionState, serverName string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: server sent no certificate")
+	}
+	if serverName == "" {
+		return errors.New("tls: no server name to verify the certificate against")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         r.caPool(),
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// ReloadCertificates re-reads the certificate and CA files of every TLS
+// config in the process, as on SIGHUP. It returns the errors of files that
+// failed to load; those keep their previous certificates.
+func ReloadCertificates() error {
+	var errs []error
+	for _, r := range allReloaders() {
+		if _, err := r.reload(true); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WatchReloads reloads the certificate and CA files of every TLS config in
+// the process when they change, checking every interval until ctx is done.
+func WatchReloads(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, r := range allReloaders() {
+				_, _ = r.reload(false)
+			}
+		}
+	}
+}
+
+// ReloadOnSignal calls ReloadCertificates whenever the process receives one
+// of sigs, typically SIGHUP, until ctx is done.
+func ReloadOnSignal(ctx context.Context, sigs ...os.Signal) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, sigs...)
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-sigCh:
+				log.Info().Str("signal", sig.String()).Msg("Reloading TLS certificates")
+				_ = ReloadCertificates()
+			}
+		}
+	}()
+}
+
+func allReloaders() []*reloader {
+	reloaders.Lock()
+	defer reloaders.Unlock()
+	list := make([]*reloader, 0, len(reloaders.byFiles))
+	for _, r := range reloaders.byFiles {
+		list = append(list, r)
+	}
+	return list
+}
@@ -0,0 +1,288 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// rotateFile replaces path with data and moves its mtime forward, as a
+// certificate rotation tool would.
+func rotateFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// reloadFiles forces the reloader of the given files to re-read them, as
+// ReloadCertificates does for every reloader in the process.
+func reloadFiles(t *testing.T, certFile, keyFile, caFile string) error {
+	t.Helper()
+	reloaders.Lock()
+	r := reloaders.byFiles[reloaderKey(certFile, keyFile, caFile)]
+	reloaders.Unlock()
+	if r == nil {
+		t.Fatal("no reloader for files")
+	}
+	_, err := r.reload(true)
+	return err
+}
+
+// serveTLS accepts TLS connections with cfg and returns the address.
+func serveTLS(t *testing.T, cfg *tls.Config) string {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.(*tls.Conn).Handshake()
+				_, _ = io.Copy(io.Discard, conn)
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+// peerLeaf connects to addr and returns the certificate the server presented.
+func peerLeaf(addr string, cfg *tls.Config) (*x509.Certificate, error) {
+	conn, err := tls.Dial("tcp", addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestLoadServerTLS_ReloadsRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := InitCA(filepath.Join(dir, "ca"), "Test CA", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM, err := ca.Issue("127.0.0.1", UsageServer, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile, err := WriteKeyPair(dir, "server", certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverCfg, err := LoadServerTLS(Config{Enabled: true, CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("LoadServerTLS failed: %v", err)
+	}
+	addr := serveTLS(t, serverCfg)
+	clientCfg, err := PinnedCATLS(ca.Fingerprint())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := peerLeaf(addr, clientCfg)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+
+	// Rotate the key pair; new connections get the new certificate.
+	certPEM, keyPEM, err = ca.Issue("127.0.0.1", UsageServer, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotateFile(t, keyFile, keyPEM)
+	rotateFile(t, certFile, certPEM)
+	if err := reloadFiles(t, certFile, keyFile, ""); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+
+	second, err := peerLeaf(addr, clientCfg)
+	if err != nil {
+		t.Fatalf("dial after rotation failed: %v", err)
+	}
+	if second.SerialNumber.Cmp(first.SerialNumber) == 0 {
+		t.Error("server still presents the certificate from before the rotation")
+	}
+
+	// A broken file is reported and the current certificate stays.
+	rotateFile(t, certFile, []byte("not a certificate"))
+	if err := reloadFiles(t, certFile, keyFile, ""); err == nil {
+		t.Error("expected error reloading a broken certificate")
+	}
+	third, err := peerLeaf(addr, clientCfg)
+	if err != nil {
+		t.Fatalf("dial after failed reload failed: %v", err)
+	}
+	if third.SerialNumber.Cmp(second.SerialNumber) != 0 {
+		t.Error("failed reload replaced the certificate")
+	}
+}
+
+func TestLoadClientTLS_ReloadsRotatedCA(t *testing.T) {
+	dir := t.TempDir()
+	oldCA, err := InitCA(filepath.Join(dir, "old"), "Old CA", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newCA, err := InitCA(filepath.Join(dir, "new"), "New CA", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The server already uses a certificate from the new CA.
+	certPEM, keyPEM, err := newCA.Issue("127.0.0.1", UsageServer, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := serveTLS(t, &tls.Config{Certificates: []tls.Certificate{pair}})
+
+	clientPEM, clientKeyPEM, err := oldCA.Issue("client", UsageClient, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCert, clientKey, err := WriteKeyPair(dir, "client", clientPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := filepath.Join(dir, "trusted-ca.crt")
+	if err := os.WriteFile(caFile, oldCA.CertPEM(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	clientCfg, err := LoadClientTLS(Config{Enabled: true, CertFile: clientCert, KeyFile: clientKey, ClientCA: caFile})
+	if err != nil {
+		t.Fatalf("LoadClientTLS failed: %v", err)
+	}
+	clientCfg.ServerName = "127.0.0.1"
+
+	if _, err := peerLeaf(addr, clientCfg); err == nil {
+		t.Fatal("server verified against a CA that did not sign it")
+	}
+
+	rotateFile(t, caFile, newCA.CertPEM())
+	if err := reloadFiles(t, clientCert, clientKey, caFile); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if _, err := peerLeaf(addr, clientCfg); err != nil {
+		t.Errorf("dial after CA rotation failed: %v", err)
+	}
+}
+
+func TestClientCredentials_VerifiesDialledIP(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := InitCA(filepath.Join(dir, "ca"), "Test CA", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := filepath.Join(dir, "ca.crt")
+	if err := os.WriteFile(caFile, ca.CertPEM(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	serve := func(name string) string {
+		certPEM, keyPEM, err := ca.Issue(name, UsageServer, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pair, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// gRPC requires the server to negotiate HTTP/2
+		return serveTLS(t, &tls.Config{Certificates: []tls.Certificate{pair}, NextProtos: []string{"h2"}})
+	}
+	wrongSAN := serve("other.example.com")
+	rightSAN := serve("127.0.0.1")
+
+	clientPEM, clientKeyPEM, err := ca.Issue("client", UsageClient, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCert, clientKey, err := WriteKeyPair(dir, "client", clientPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{Enabled: true, CertFile: clientCert, KeyFile: clientKey, ClientCA: caFile}
+
+	creds, err := ClientCredentials(cfg)
+	if err != nil {
+		t.Fatalf("ClientCredentials failed: %v", err)
+	}
+	handshake := func(addr string) error {
+		raw, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer raw.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, _, err = creds.ClientHandshake(ctx, addr, raw)
+		return err
+	}
+	if err := handshake(wrongSAN); err == nil {
+		t.Error("accepted a certificate without a SAN for the dialled IP")
+	}
+	if err := handshake(rightSAN); err != nil {
+		t.Errorf("handshake with a certificate for the dialled IP failed: %v", err)
+	}
+
+	// Without ServerName, a config dialling an IP cannot verify the server
+	clientCfg, err := LoadClientTLS(cfg)
+	if err != nil {
+		t.Fatalf("LoadClientTLS failed: %v", err)
+	}
+	if _, err := peerLeaf(rightSAN, clientCfg); err == nil {
+		t.Error("verified an IP server without a server name")
+	}
+	clientCfg.ServerName = "127.0.0.1"
+	if _, err := peerLeaf(wrongSAN, clientCfg); err == nil {
+		t.Error("accepted a certificate without a SAN for ServerName")
+	}
+}
+
+func TestReloader_UnchangedFilesAreNotReloaded(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, err := generateTestCert(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := reloaderFor(certFile, keyFile, "")
+	if err != nil {
+		t.Fatalf("reloaderFor failed: %v", err)
+	}
+
+	reloaded, err := r.reload(false)
+	if err != nil || reloaded {
+		t.Errorf("reload() of unchanged files = %v, %v", reloaded, err)
+	}
+
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotateFile(t, certFile, data)
+	reloaded, err = r.reload(false)
+	if err != nil || !reloaded {
+		t.Errorf("reload() of changed files = %v, %v, want reload", reloaded, err)
+	}
+}
@@ -0,0 +1,315 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+const (
+	maxCocosLogBytes = 256 * 1024
+
+	// cocosBuildSucceeded is the exit code Creator 3.x uses for a successful
+	// command-line build. Creator 2.x exits 0.
+	cocosBuildSucceeded = 36
+)
+
+// cocosReservedOptions are build options set from the request itself.
+var cocosReservedOptions = map[string]bool{
+	"platform":  true,
+	"debug":     true,
+	"buildPath": true,
+}
+
+type CocosExecutor struct {
+	command string
+}
+
+func NewCocosExecutor() *CocosExecutor {
+	command := detectCocosCommand()
+	if command == "" {
+		command = "CocosCreator"
+	}
+
+	return &CocosExecutor{command: command}
+}
+
+func NewCocosExecutorWithCommand(command string) *CocosExecutor {
+	return &CocosExecutor{command: command}
+}
+
+func (e *CocosExecutor) Name() string {
+	return "cocos"
+}
+
+func (e *CocosExecutor) CanExecute(targetArch pb.Architecture, nativeArch pb.Architecture) bool {
+	return true
+}
+
+func (e *CocosExecutor) Execute(ctx context.Context, req *Request) (*Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if req == nil {
+		return nil, fmt.Errorf("build request required")
+	}
+	if req.BuildType != pb.BuildType_BUILD_TYPE_COCOS {
+		return nil, fmt.Errorf("unsupported build type: %s", req.BuildType.String())
+	}
+
+	cocosConfig := req.CocosConfig
+	if cocosConfig == nil {
+		cocosConfig = &pb.CocosConfig{}
+	}
+
+	buildOptions, err := buildCocosOptions(req.TargetPlatform, cocosConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	execCtx := ctx
+	var cancel context.CancelFunc
+	if req.Timeout > 0 {
+		execCtx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	} else if req.TimeoutSeconds > 0 {
+		execCtx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	workDir, err := os.MkdirTemp("", fmt.Sprintf("hg-cocos-%s-", req.TaskID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := extractRequestSource(workDir, req); err != nil {
+		return nil, fmt.Errorf("failed to extract source archive: %w", err)
+	}
+
+	if info, err := os.Stat(filepath.Join(workDir, "assets")); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("source archive has no assets directory")
+	}
+
+	cmd := exec.CommandContext(execCtx, e.command, "--project", workDir, "--build", buildOptions)
+	killTreeOnCancel(cmd)
+	cmd.Dir = workDir
+
+	stdout := newLimitedBuffer(maxCocosLogBytes)
+	stderr := newLimitedBuffer(maxCocosLogBytes)
+	flushOutput := attachOutput(cmd, req, stdout, stderr)
+
+	start := time.Now()
+	err = cmd.Run()
+	flushOutput()
+	buildTime := time.Since(start)
+
+	result := &Result{
+		Stdout:          strings.TrimSpace(stdout.String()),
+		Stderr:          strings.TrimSpace(stderr.String()),
+		CompilationTime: buildTime,
+	}
+
+	if execCtx.Err() == context.DeadlineExceeded {
+		result.ExitCode = -1
+		result.Success = false
+		result.Stderr = strings.TrimSpace(joinNonEmpty(result.Stderr, "cocos build timed out"))
+		return result, nil
+	}
+
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return nil, fmt.Errorf("execution failed: %w", err)
+		}
+		if exitErr.ExitCode() != cocosBuildSucceeded {
+			result.ExitCode = int32(exitErr.ExitCode())
+			result.Success = false
+			return result, nil
+		}
+	}
+
+	result.ExitCode = 0
+
+	artifacts, archive, err := collectCocosArtifacts(workDir)
+	if err != nil {
+		result.Success = false
+		result.ExitCode = 1
+		result.Stderr = strings.TrimSpace(joinNonEmpty(result.Stderr, err.Error()))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Artifacts = artifacts
+	result.ArtifactArchive = archive
+	if len(artifacts) > 0 {
+		result.ArtifactPath = artifacts[0].Path
+	}
+
+	return result, nil
+}
+
+// buildCocosOptions renders the value of Creator's --build flag:
+// "platform=android;debug=false;buildPath=project://build" followed by the
+// platform options sorted by key.
+func buildCocosOptions(target pb.TargetPlatform, cfg *pb.CocosConfig) (string, error) {
+	platform, err := targetPlatformToCocosString(target)
+	if err != nil {
+		return "", err
+	}
+
+	var debug bool
+	switch strings.ToLower(strings.TrimSpace(cfg.GetBuildMode())) {
+	case "", "release":
+		debug = false
+	case "debug":
+		debug = true
+	default:
+		return "", fmt.Errorf("invalid build_mode %q (supported: debug, release)", cfg.GetBuildMode())
+	}
+
+	options := []string{
+		"platform=" + platform,
+		fmt.Sprintf("debug=%t", debug),
+		"buildPath=project://build",
+	}
+
+	keys := make([]string, 0, len(cfg.GetPlatformOptions()))
+	for key := range cfg.GetPlatformOptions() {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := cfg.GetPlatformOptions()[key]
+		if key == "" || strings.ContainsAny(key, ";=") || strings.Contains(value, ";") {
+			return "", fmt.Errorf("invalid platform option %q", key)
+		}
+		if cocosReservedOptions[key] {
+			return "", fmt.Errorf("platform option %q is set by the build request", key)
+		}
+		options = append(options, key+"="+value)
+	}
+
+	return strings.Join(options, ";"), nil
+}
+
+func targetPlatformToCocosString(target pb.TargetPlatform) (string, error) {
+	switch target {
+	case pb.TargetPlatform_PLATFORM_ANDROID:
+		return "android", nil
+	case pb.TargetPlatform_PLATFORM_IOS:
+		return "ios", nil
+	case pb.TargetPlatform_PLATFORM_WEB:
+		return "web-mobile", nil
+	case pb.TargetPlatform_PLATFORM_WINDOWS:
+		return "windows", nil
+	case pb.TargetPlatform_PLATFORM_MACOS:
+		return "mac", nil
+	default:
+		return "", fmt.Errorf("unsupported target platform: %s", target.String())
+	}
+}
+
+// collectCocosArtifacts archives every file Creator wrote under build/.
+func collectCocosArtifacts(workDir string) ([]*pb.ArtifactInfo, []byte, error) {
+	searchRoot := filepath.Join(workDir, "build")
+	if info, err := os.Stat(searchRoot); err != nil || !info.IsDir() {
+		return nil, nil, fmt.Errorf("build output directory not found")
+	}
+
+	entries := make([]flutterArtifact, 0)
+	walkErr := filepath.WalkDir(searchRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(workDir, path)
+		if err != nil {
+			return err
+		}
+
+		checksum, size, err := checksumFile(path)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, flutterArtifact{
+			info: &pb.ArtifactInfo{
+				Name:      filepath.Base(path),
+				Path:      filepath.ToSlash(relPath),
+				SizeBytes: size,
+				Checksum:  checksum,
+			},
+			absPath: path,
+		})
+		return nil
+	})
+	if walkErr != nil {
+		return nil, nil, fmt.Errorf("failed to scan artifacts: %w", walkErr)
+	}
+
+	if len(entries) == 0 {
+		return nil, nil, fmt.Errorf("build output directory is empty")
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].info.Path < entries[j].info.Path
+	})
+
+	archive, err := archiveArtifacts(workDir, entries)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to archive artifacts: %w", err)
+	}
+
+	artifactList := make([]*pb.ArtifactInfo, 0, len(entries))
+	for _, entry := range entries {
+		artifactList = append(artifactList, entry.info)
+	}
+
+	return artifactList, archive, nil
+}
+
+func detectCocosCommand() string {
+	var patterns []string
+	switch runtime.GOOS {
+	case "darwin":
+		patterns = []string{
+			"/Applications/Cocos/Creator/*/CocosCreator.app/Contents/MacOS/CocosCreator",
+			"/Applications/CocosCreator.app/Contents/MacOS/CocosCreator",
+		}
+	case "windows":
+		patterns = []string{
+			`C:\ProgramData\cocos\editors\Creator\*\CocosCreator.exe`,
+		}
+	}
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil || len(matches) == 0 {
+			continue
+		}
+		sort.Strings(matches)
+		for i := len(matches) - 1; i >= 0; i-- {
+			if _, statErr := os.Stat(matches[i]); statErr == nil {
+				return matches[i]
+			}
+		}
+	}
+
+	if path, err := exec.LookPath("CocosCreator"); err == nil {
+		return path
+	}
+
+	return ""
+}
@@ -0,0 +1,217 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+var _ Executor = (*CocosExecutor)(nil)
+
+func TestNewCocosExecutor(t *testing.T) {
+	executor := NewCocosExecutor()
+	if strings.TrimSpace(executor.command) == "" {
+		t.Fatal("NewCocosExecutor() returned empty command")
+	}
+	if executor.Name() != "cocos" {
+		t.Fatalf("Name() = %q, want %q", executor.Name(), "cocos")
+	}
+}
+
+func TestCocosExecutor_Execute_ValidatesBuildType(t *testing.T) {
+	executor := NewCocosExecutorWithCommand("CocosCreator")
+	_, err := executor.Execute(context.Background(), &Request{
+		BuildType:      pb.BuildType_BUILD_TYPE_UNITY,
+		TargetPlatform: pb.TargetPlatform_PLATFORM_WEB,
+	})
+	if err == nil {
+		t.Fatal("Execute() expected error for non-cocos build type")
+	}
+}
+
+func TestCocosExecutor_Execute_RequiresAssets(t *testing.T) {
+	executor := NewCocosExecutorWithCommand("CocosCreator")
+	_, err := executor.Execute(context.Background(), &Request{
+		TaskID:         "cocos-no-assets",
+		BuildType:      pb.BuildType_BUILD_TYPE_COCOS,
+		TargetPlatform: pb.TargetPlatform_PLATFORM_WEB,
+		SourceArchive:  writeTarArchive(t, map[string]string{"package.json": "{}"}),
+	})
+	if err == nil || !strings.Contains(err.Error(), "assets") {
+		t.Fatalf("Execute() error = %v, want missing assets", err)
+	}
+}
+
+func TestBuildCocosOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  pb.TargetPlatform
+		cfg     *pb.CocosConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "web release default",
+			target: pb.TargetPlatform_PLATFORM_WEB,
+			cfg:    &pb.CocosConfig{},
+			want:   "platform=web-mobile;debug=false;buildPath=project://build",
+		},
+		{
+			name:   "android debug with sorted options",
+			target: pb.TargetPlatform_PLATFORM_ANDROID,
+			cfg: &pb.CocosConfig{
+				BuildMode: "Debug",
+				PlatformOptions: map[string]string{
+					"packageName": "com.example.game",
+					"md5Cache":    "true",
+				},
+			},
+			want: "platform=android;debug=true;buildPath=project://build;md5Cache=true;packageName=com.example.game",
+		},
+		{name: "macos", target: pb.TargetPlatform_PLATFORM_MACOS, cfg: &pb.CocosConfig{}, want: "platform=mac;debug=false;buildPath=project://build"},
+		{name: "unsupported platform", target: pb.TargetPlatform_PLATFORM_LINUX, cfg: &pb.CocosConfig{}, wantErr: true},
+		{name: "invalid build mode", target: pb.TargetPlatform_PLATFORM_WEB, cfg: &pb.CocosConfig{BuildMode: "profile"}, wantErr: true},
+		{name: "reserved option", target: pb.TargetPlatform_PLATFORM_WEB, cfg: &pb.CocosConfig{PlatformOptions: map[string]string{"buildPath": "/tmp"}}, wantErr: true},
+		{name: "separator in value", target: pb.TargetPlatform_PLATFORM_WEB, cfg: &pb.CocosConfig{PlatformOptions: map[string]string{"title": "a;platform=ios"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildCocosOptions(tt.target, tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("buildCocosOptions() = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildCocosOptions() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("buildCocosOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCocosExecutor_Execute_CommandConstruction(t *testing.T) {
+	cocosCmd, argsFile := setupFakeCocos(t)
+	archive := writeTarArchive(t, map[string]string{
+		"package.json":          `{"creator":{"version":"3.8.2"}}`,
+		"assets/scene.scene":    "[]",
+		"settings/project.json": "{}",
+	})
+
+	executor := NewCocosExecutorWithCommand(cocosCmd)
+	req := &Request{
+		TaskID:         "cocos-build",
+		BuildType:      pb.BuildType_BUILD_TYPE_COCOS,
+		TargetPlatform: pb.TargetPlatform_PLATFORM_WEB,
+		SourceArchive:  archive,
+		CocosConfig: &pb.CocosConfig{
+			BuildMode:       "release",
+			PlatformOptions: map[string]string{"md5Cache": "true"},
+		},
+		TimeoutSeconds: 10,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	result, err := executor.Execute(ctx, req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !result.Success || result.ExitCode != 0 {
+		t.Fatalf("Execute() = success %v exit %d, stderr=%q", result.Success, result.ExitCode, result.Stderr)
+	}
+
+	var paths []string
+	for _, artifact := range result.Artifacts {
+		paths = append(paths, artifact.Path)
+	}
+	if strings.Join(paths, ",") != "build/web-mobile/index.html,build/web-mobile/main.js" {
+		t.Fatalf("artifacts = %v", paths)
+	}
+	if len(result.ArtifactArchive) == 0 {
+		t.Fatal("expected artifact archive")
+	}
+
+	args := readArgsFile(t, argsFile)
+	assertArgsContain(t, args,
+		"--project",
+		"--build",
+		"platform=web-mobile;debug=false;buildPath=project://build;md5Cache=true",
+	)
+}
+
+func TestCocosExecutor_Execute_BuildFailure(t *testing.T) {
+	cocosCmd, _ := setupFakeCocos(t)
+	t.Setenv("HG_COCOS_EXIT_CODE", "34")
+
+	executor := NewCocosExecutorWithCommand(cocosCmd)
+	result, err := executor.Execute(context.Background(), &Request{
+		TaskID:         "cocos-fail",
+		BuildType:      pb.BuildType_BUILD_TYPE_COCOS,
+		TargetPlatform: pb.TargetPlatform_PLATFORM_WEB,
+		SourceArchive:  writeTarArchive(t, map[string]string{"assets/scene.scene": "[]"}),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Success || result.ExitCode != 34 {
+		t.Fatalf("Execute() = success %v exit %d, want exit 34", result.Success, result.ExitCode)
+	}
+}
+
+func TestManager_SelectForRequest_CocosRoute(t *testing.T) {
+	m := &Manager{
+		native: &fakeExecutor{name: "native"},
+		cocos:  &fakeExecutor{name: "cocos"},
+	}
+
+	got := m.SelectForRequest(&Request{BuildType: pb.BuildType_BUILD_TYPE_COCOS})
+	if got.Name() != "cocos" {
+		t.Fatalf("SelectForRequest() executor = %s, want cocos", got.Name())
+	}
+}
+
+// setupFakeCocos writes a Creator stand-in that records its arguments, writes
+// a web-mobile build under <project>/build and exits with HG_COCOS_EXIT_CODE
+// (default 36, Creator 3.x's success code).
+func setupFakeCocos(t *testing.T) (string, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake Cocos Creator script requires a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	argsFile := filepath.Join(t.TempDir(), "cocos-args.txt")
+	scriptPath := filepath.Join(binDir, "CocosCreator")
+	contents := "#!/bin/sh\n" +
+		"set -e\n" +
+		"printf '%s\\n' \"$@\" > \"$HG_COCOS_ARGS_FILE\"\n" +
+		"PROJECT=\"\"\n" +
+		"while [ $# -gt 0 ]; do\n" +
+		"  case \"$1\" in\n" +
+		"    --project) PROJECT=\"$2\"; shift 2 ;;\n" +
+		"    *) shift ;;\n" +
+		"  esac\n" +
+		"done\n" +
+		"mkdir -p \"$PROJECT/build/web-mobile\"\n" +
+		"printf '<html></html>' > \"$PROJECT/build/web-mobile/index.html\"\n" +
+		"printf 'main()' > \"$PROJECT/build/web-mobile/main.js\"\n" +
+		"exit \"${HG_COCOS_EXIT_CODE:-36}\"\n"
+
+	if err := os.WriteFile(scriptPath, []byte(contents), 0755); err != nil {
+		t.Fatalf("Failed to write fake CocosCreator: %v", err)
+	}
+
+	t.Setenv("HG_COCOS_ARGS_FILE", argsFile)
+	return scriptPath, argsFile
+}
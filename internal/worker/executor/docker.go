@@ -217,15 +217,19 @@ func (e *DockerExecutor) Execute(ctx context.Context, req *Request) (*Result, er
 	select {
 	case err := <-errCh:
 		if err != nil {
-			if ctx.Err() == context.DeadlineExceeded {
-				// Kill container on timeout
+			if ctx.Err() != nil {
+				// Kill the container on timeout or cancellation
 				killCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 				defer cancel()
 				e.client.ContainerKill(killCtx, containerID, "KILL")
+				stderr := "compilation timed out"
+				if ctx.Err() == context.Canceled {
+					stderr = "compilation cancelled"
+				}
 				return &Result{
 					Success:         false,
 					ExitCode:        -1,
-					Stderr:          "compilation timed out",
+					Stderr:          stderr,
 					CompilationTime: time.Since(start),
 				}, nil
 			}
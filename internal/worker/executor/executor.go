@@ -13,6 +13,10 @@ import (
 	"github.com/h3nr1-d14z/hybridgrid/internal/observability/tracing"
 )
 
+// processWaitDelay bounds how long a cancelled command waits for output
+// pipes held open by processes that outlived it.
+const processWaitDelay = 5 * time.Second
+
 // Result represents the outcome of a compilation execution.
 type Result struct {
 	Success         bool
@@ -43,16 +47,29 @@ type Request struct {
 	IncludeFiles   map[string][]byte // Bundled project headers (path -> content)
 	IncludePaths   []string          // -I paths for headers
 
+	// Link mode: Args are link flags referencing LinkInputs by name
+	Link       bool
+	LinkInputs map[string][]byte // Object files and static libraries (name -> content)
+
 	// Client info for OS-aware executor selection
 	ClientOs string // OS where the build was initiated (e.g., "linux", "darwin", "windows")
 
 	// Flutter build fields (optional, used by FlutterExecutor)
-	FlutterConfig  *pb.FlutterConfig // Flutter-specific configuration
-	UnityConfig    *pb.UnityConfig
-	SourceArchive  []byte            // Tar archive of Flutter project source
-	TargetPlatform pb.TargetPlatform // Target platform (e.g., PLATFORM_ANDROID)
-	BuildType      pb.BuildType      // Build type (e.g., BUILD_TYPE_FLUTTER)
-	TimeoutSeconds int32             // Timeout in seconds for Flutter builds
+	FlutterConfig     *pb.FlutterConfig // Flutter-specific configuration
+	UnityConfig       *pb.UnityConfig
+	CocosConfig       *pb.CocosConfig
+	RustConfig        *pb.RustConfig
+	GoConfig          *pb.GoConfig
+	NodeConfig        *pb.NodeConfig
+	SourceArchive     []byte            // Tar archive of Flutter project source
+	SourceArchivePath string            // Spooled archive on disk (StreamBuild); used when SourceArchive is empty
+	TargetPlatform    pb.TargetPlatform // Target platform (e.g., PLATFORM_ANDROID)
+	BuildType         pb.BuildType      // Build type (e.g., BUILD_TYPE_FLUTTER)
+	TimeoutSeconds    int32             // Timeout in seconds for Flutter builds
+
+	// Output, when set, receives build output line by line as it is
+	// produced. Only the project build executors stream output.
+	Output OutputFunc
 }
 
 // Executor defines the interface for compilation executors.
@@ -74,6 +91,10 @@ type Manager struct {
 	msvc       Executor
 	flutter    Executor
 	unity      Executor
+	cocos      Executor
+	rust       Executor
+	golang     Executor
+	node       Executor
 	nativeArch pb.Architecture
 }
 
@@ -100,6 +121,10 @@ func NewManager(nativeArch pb.Architecture, dockerAvailable bool) *Manager {
 	// Initialize Flutter executor
 	m.flutter = NewFlutterExecutor()
 	m.unity = NewUnityExecutor()
+	m.cocos = NewCocosExecutor()
+	m.rust = NewRustExecutor()
+	m.golang = NewGoExecutor()
+	m.node = NewNodeExecutor()
 
 	return m
 }
@@ -133,6 +158,28 @@ func (m *Manager) SelectForRequest(req *Request) Executor {
 		return m.unity
 	}
 
+	if req.BuildType == pb.BuildType_BUILD_TYPE_COCOS {
+		return m.cocos
+	}
+
+	if req.BuildType == pb.BuildType_BUILD_TYPE_RUST {
+		return m.rust
+	}
+
+	if req.BuildType == pb.BuildType_BUILD_TYPE_GO {
+		return m.golang
+	}
+
+	if req.BuildType == pb.BuildType_BUILD_TYPE_NODEJS {
+		return m.node
+	}
+
+	// Links run with the host toolchain; the coordinator only routes them
+	// to workers matching the client's OS and architecture
+	if req.Link {
+		return m.native
+	}
+
 	// If client OS is set and differs from this worker's OS,
 	// raw source needs Docker for cross-OS compilation
 	if req.ClientOs != "" && req.ClientOs != runtime.GOOS && len(req.RawSource) > 0 {
@@ -184,6 +231,26 @@ func (m *Manager) GetUnity() Executor {
 	return m.unity
 }
 
+// GetCocos returns the Cocos Creator executor.
+func (m *Manager) GetCocos() Executor {
+	return m.cocos
+}
+
+// GetRust returns the Rust (cargo) executor.
+func (m *Manager) GetRust() Executor {
+	return m.rust
+}
+
+// GetGo returns the Go executor.
+func (m *Manager) GetGo() Executor {
+	return m.golang
+}
+
+// GetNode returns the Node.js executor.
+func (m *Manager) GetNode() Executor {
+	return m.node
+}
+
 // isMSVCCompiler checks if the compiler is MSVC.
 func isMSVCCompiler(compiler string) bool {
 	lower := strings.ToLower(compiler)
@@ -120,10 +120,8 @@ func (e *FlutterExecutor) Execute(ctx context.Context, req *Request) (*Result, e
 	defer os.RemoveAll(workDir)
 	defer stopGradleDaemon(workDir)
 
-	if len(req.SourceArchive) > 0 {
-		if err := extractSourceArchive(workDir, req.SourceArchive); err != nil {
-			return nil, fmt.Errorf("failed to extract source archive: %w", err)
-		}
+	if err := extractRequestSource(workDir, req); err != nil {
+		return nil, fmt.Errorf("failed to extract source archive: %w", err)
 	}
 
 	outputType, modeFlag, err := resolveFlutterBuildOptions(flutterConfig.BuildMode)
@@ -133,7 +131,7 @@ func (e *FlutterExecutor) Execute(ctx context.Context, req *Request) (*Result, e
 
 	pubGetOut := newLimitedBuffer(maxFlutterLogBytes)
 	pubGetErr := newLimitedBuffer(maxFlutterLogBytes)
-	if err := runFlutterPubGet(execCtx, e.command, workDir, pubGetOut, pubGetErr); err != nil {
+	if err := runFlutterPubGet(execCtx, req, e.command, workDir, pubGetOut, pubGetErr); err != nil {
 		result := &Result{
 			Success:  false,
 			ExitCode: 1,
@@ -147,16 +145,17 @@ func (e *FlutterExecutor) Execute(ctx context.Context, req *Request) (*Result, e
 
 	args := buildFlutterArgs(outputType, modeFlag, flutterConfig)
 	cmd := exec.CommandContext(execCtx, e.command, args...)
+	killTreeOnCancel(cmd)
 	cmd.Dir = workDir
 	cmd.Env = append(os.Environ(), "GRADLE_OPTS=-Dorg.gradle.vfs.watch=false")
 
 	stdout := newLimitedBuffer(maxFlutterLogBytes)
 	stderr := newLimitedBuffer(maxFlutterLogBytes)
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
+	flushOutput := attachOutput(cmd, req, stdout, stderr)
 
 	start := time.Now()
 	err = cmd.Run()
+	flushOutput()
 	buildTime := time.Since(start)
 
 	result := &Result{
@@ -200,11 +199,12 @@ func (e *FlutterExecutor) Execute(ctx context.Context, req *Request) (*Result, e
 	return result, nil
 }
 
-func runFlutterPubGet(ctx context.Context, flutterCmd, workDir string, stdout, stderr *limitedBuffer) error {
+func runFlutterPubGet(ctx context.Context, req *Request, flutterCmd, workDir string, stdout, stderr *limitedBuffer) error {
 	cmd := exec.CommandContext(ctx, flutterCmd, "pub", "get")
+	killTreeOnCancel(cmd)
 	cmd.Dir = workDir
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
+	flushOutput := attachOutput(cmd, req, stdout, stderr)
+	defer flushOutput()
 	cmd.Env = append(os.Environ(), "GRADLE_OPTS=-Dorg.gradle.vfs.watch=false")
 	return cmd.Run()
 }
@@ -424,45 +424,89 @@ func checksumFile(path string) (string, int64, error) {
 	return hex.EncodeToString(hash.Sum(nil)), written, nil
 }
 
+// extractRequestSource unpacks the request's source into dest, preferring the
+// in-memory archive and falling back to a spooled archive file.
+func extractRequestSource(dest string, req *Request) error {
+	if len(req.SourceArchive) > 0 {
+		return extractSourceArchive(dest, req.SourceArchive)
+	}
+	if req.SourceArchivePath != "" {
+		return extractSourceArchiveFile(dest, req.SourceArchivePath)
+	}
+	return nil
+}
+
 func extractSourceArchive(dest string, data []byte) error {
 	if len(data) == 0 {
 		return nil
 	}
 
 	if isZstdArchive(data) {
-		zstdPath, err := exec.LookPath("zstd")
-		if err != nil {
-			return fmt.Errorf("zstd not found for compressed archive")
-		}
+		return extractZstdArchive(dest, bytes.NewReader(data))
+	}
 
-		cmd := exec.Command(zstdPath, "-d", "-q", "-c")
-		cmd.Stdin = bytes.NewReader(data)
+	return extractTarStream(dest, tar.NewReader(bytes.NewReader(data)))
+}
 
-		stdout, err := cmd.StdoutPipe()
-		if err != nil {
-			return fmt.Errorf("failed to create decompressor stdout pipe: %w", err)
-		}
+// extractSourceArchiveFile extracts an archive spooled to disk without
+// loading it into memory.
+func extractSourceArchiveFile(dest, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open source archive: %w", err)
+	}
+	defer file.Close()
 
-		var stderr bytes.Buffer
-		cmd.Stderr = &stderr
+	magic := make([]byte, 4)
+	n, err := io.ReadFull(file, magic)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("failed to read source archive: %w", err)
+	}
+	if n == 0 {
+		return nil
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind source archive: %w", err)
+	}
 
-		if err := cmd.Start(); err != nil {
-			return fmt.Errorf("failed to start zstd decompressor: %w", err)
-		}
+	if isZstdArchive(magic[:n]) {
+		return extractZstdArchive(dest, file)
+	}
 
-		if err := extractTarStream(dest, tar.NewReader(stdout)); err != nil {
-			_ = cmd.Wait()
-			return err
-		}
+	return extractTarStream(dest, tar.NewReader(file))
+}
 
-		if err := cmd.Wait(); err != nil {
-			return fmt.Errorf("zstd decompression failed: %v", err)
-		}
+func extractZstdArchive(dest string, src io.Reader) error {
+	zstdPath, err := exec.LookPath("zstd")
+	if err != nil {
+		return fmt.Errorf("zstd not found for compressed archive")
+	}
 
-		return nil
+	cmd := exec.Command(zstdPath, "-d", "-q", "-c")
+	cmd.Stdin = src
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create decompressor stdout pipe: %w", err)
 	}
 
-	return extractTarStream(dest, tar.NewReader(bytes.NewReader(data)))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start zstd decompressor: %w", err)
+	}
+
+	if err := extractTarStream(dest, tar.NewReader(stdout)); err != nil {
+		_ = cmd.Wait()
+		return err
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("zstd decompression failed: %v", err)
+	}
+
+	return nil
 }
 
 func extractTarStream(dest string, reader *tar.Reader) error {
@@ -0,0 +1,286 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+const maxGoLogBytes = 256 * 1024
+
+type GoExecutor struct {
+	command string
+}
+
+func NewGoExecutor() *GoExecutor {
+	return &GoExecutor{command: "go"}
+}
+
+func NewGoExecutorWithCommand(command string) *GoExecutor {
+	return &GoExecutor{command: command}
+}
+
+func (e *GoExecutor) Name() string {
+	return "go"
+}
+
+func (e *GoExecutor) CanExecute(targetArch pb.Architecture, nativeArch pb.Architecture) bool {
+	return true
+}
+
+func (e *GoExecutor) Execute(ctx context.Context, req *Request) (*Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if req == nil {
+		return nil, fmt.Errorf("build request required")
+	}
+	if req.BuildType != pb.BuildType_BUILD_TYPE_GO {
+		return nil, fmt.Errorf("unsupported build type: %s", req.BuildType.String())
+	}
+
+	goConfig := req.GoConfig
+	if goConfig == nil {
+		goConfig = &pb.GoConfig{}
+	}
+
+	execCtx := ctx
+	var cancel context.CancelFunc
+	if req.Timeout > 0 {
+		execCtx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	} else if req.TimeoutSeconds > 0 {
+		execCtx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	workDir, err := os.MkdirTemp("", fmt.Sprintf("hg-go-%s-", req.TaskID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	// The module is extracted next to the output directory rather than
+	// into it, so artifact paths never collide with source files.
+	srcDir := filepath.Join(workDir, "src")
+	binDir := filepath.Join(workDir, "bin")
+
+	if err := extractRequestSource(srcDir, req); err != nil {
+		return nil, fmt.Errorf("failed to extract source archive: %w", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(srcDir, "go.mod")); err != nil {
+		return nil, fmt.Errorf("source archive has no go.mod")
+	}
+
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output dir: %w", err)
+	}
+
+	cmd := exec.CommandContext(execCtx, e.command, buildGoArgs(goConfig, binDir)...)
+	killTreeOnCancel(cmd)
+	cmd.Dir = srcDir
+	cmd.Env = append(os.Environ(), goBuildEnv(goConfig)...)
+
+	stdout := newLimitedBuffer(maxGoLogBytes)
+	stderr := newLimitedBuffer(maxGoLogBytes)
+	flushOutput := attachOutput(cmd, req, stdout, stderr)
+
+	start := time.Now()
+	err = cmd.Run()
+	flushOutput()
+	buildTime := time.Since(start)
+
+	result := &Result{
+		Stdout:          strings.TrimSpace(stdout.String()),
+		Stderr:          strings.TrimSpace(stderr.String()),
+		CompilationTime: buildTime,
+	}
+
+	if execCtx.Err() == context.DeadlineExceeded {
+		result.ExitCode = -1
+		result.Success = false
+		result.Stderr = strings.TrimSpace(joinNonEmpty(result.Stderr, "go build timed out"))
+		return result, nil
+	}
+
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = int32(exitErr.ExitCode())
+			result.Success = false
+			return result, nil
+		}
+		return nil, fmt.Errorf("execution failed: %w", err)
+	}
+
+	result.ExitCode = 0
+
+	artifacts, archive, err := collectGoArtifacts(workDir, binDir)
+	if err != nil {
+		result.Success = false
+		result.ExitCode = 1
+		result.Stderr = strings.TrimSpace(joinNonEmpty(result.Stderr, err.Error()))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Artifacts = artifacts
+	result.ArtifactArchive = archive
+	if len(artifacts) > 0 {
+		result.ArtifactPath = artifacts[0].Path
+	}
+
+	return result, nil
+}
+
+// buildGoArgs returns the go build arguments for cfg. Binaries are written
+// into outputDir; with no package patterns the module root is built.
+func buildGoArgs(cfg *pb.GoConfig, outputDir string) []string {
+	args := []string{"build", "-trimpath", "-o", outputDir + string(filepath.Separator)}
+
+	tags := make([]string, 0, len(cfg.GetBuildTags()))
+	for _, tag := range cfg.GetBuildTags() {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		args = append(args, "-tags", strings.Join(tags, ","))
+	}
+
+	if ldflags := formatLdflags(cfg.GetLdflags()); ldflags != "" {
+		args = append(args, "-ldflags", ldflags)
+	}
+
+	packages := make([]string, 0, len(cfg.GetPackages()))
+	for _, pkg := range cfg.GetPackages() {
+		if pkg = strings.TrimSpace(pkg); pkg != "" {
+			packages = append(packages, pkg)
+		}
+	}
+	if len(packages) == 0 {
+		packages = []string{"."}
+	}
+
+	return append(args, packages...)
+}
+
+// formatLdflags renders the ldflags map as a single -ldflags value. Keys
+// starting with "-" are linker flags (e.g. "-s", or "-extldflags" with its
+// value); every other key is a symbol set with -X. Keys are sorted so the
+// command line is stable.
+func formatLdflags(ldflags map[string]string) string {
+	keys := make([]string, 0, len(ldflags))
+	for k := range ldflags {
+		if strings.TrimSpace(k) != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := ldflags[k]
+		switch {
+		case strings.HasPrefix(k, "-") && v == "":
+			parts = append(parts, k)
+		case strings.HasPrefix(k, "-"):
+			parts = append(parts, k, quoteLdflagValue(v))
+		default:
+			parts = append(parts, "-X", quoteLdflagValue(k+"="+v))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteLdflagValue quotes values containing spaces; the go command splits
+// -ldflags like a shell would.
+func quoteLdflagValue(v string) string {
+	if strings.ContainsAny(v, " \t'\"") {
+		return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+	}
+	return v
+}
+
+// goBuildEnv returns GOOS/GOARCH overrides for cfg. cgo is disabled when
+// cross-compiling, since the worker has no cross C toolchain.
+func goBuildEnv(cfg *pb.GoConfig) []string {
+	var env []string
+	goos := strings.TrimSpace(cfg.GetGoos())
+	goarch := strings.TrimSpace(cfg.GetGoarch())
+
+	if goos != "" {
+		env = append(env, "GOOS="+goos)
+	}
+	if goarch != "" {
+		env = append(env, "GOARCH="+goarch)
+	}
+	if (goos != "" && goos != runtime.GOOS) || (goarch != "" && goarch != runtime.GOARCH) {
+		env = append(env, "CGO_ENABLED=0")
+	}
+	return env
+}
+
+// collectGoArtifacts archives the binaries go build wrote into outputDir.
+func collectGoArtifacts(workDir, outputDir string) ([]*pb.ArtifactInfo, []byte, error) {
+	dirEntries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build output directory not found")
+	}
+
+	entries := make([]flutterArtifact, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.Type().IsRegular() {
+			continue
+		}
+
+		path := filepath.Join(outputDir, dirEntry.Name())
+		relPath, err := filepath.Rel(workDir, path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		checksum, size, err := checksumFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan artifacts: %w", err)
+		}
+
+		entries = append(entries, flutterArtifact{
+			info: &pb.ArtifactInfo{
+				Name:      dirEntry.Name(),
+				Path:      filepath.ToSlash(relPath),
+				SizeBytes: size,
+				Checksum:  checksum,
+			},
+			absPath: path,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil, nil, fmt.Errorf("no go build outputs found (are the packages main packages?)")
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].info.Path < entries[j].info.Path
+	})
+
+	archive, err := archiveArtifacts(workDir, entries)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to archive artifacts: %w", err)
+	}
+
+	artifactList := make([]*pb.ArtifactInfo, 0, len(entries))
+	for _, entry := range entries {
+		artifactList = append(artifactList, entry.info)
+	}
+
+	return artifactList, archive, nil
+}
@@ -0,0 +1,240 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+var _ Executor = (*GoExecutor)(nil)
+
+func TestGoExecutor_Name(t *testing.T) {
+	executor := NewGoExecutor()
+	if executor.Name() != "go" {
+		t.Fatalf("Name() = %q, want %q", executor.Name(), "go")
+	}
+	if executor.command != "go" {
+		t.Fatalf("command = %q, want %q", executor.command, "go")
+	}
+}
+
+func TestGoExecutor_Execute_ValidatesBuildType(t *testing.T) {
+	executor := NewGoExecutorWithCommand("go")
+	_, err := executor.Execute(context.Background(), &Request{
+		BuildType: pb.BuildType_BUILD_TYPE_RUST,
+		GoConfig:  &pb.GoConfig{},
+	})
+	if err == nil {
+		t.Fatal("Execute() expected error for non-go build type")
+	}
+}
+
+func TestGoExecutor_Execute_RequiresGoMod(t *testing.T) {
+	executor := NewGoExecutorWithCommand("go")
+	_, err := executor.Execute(context.Background(), &Request{
+		TaskID:        "go-no-module",
+		BuildType:     pb.BuildType_BUILD_TYPE_GO,
+		SourceArchive: writeTarArchive(t, map[string]string{"main.go": "package main"}),
+	})
+	if err == nil || !strings.Contains(err.Error(), "go.mod") {
+		t.Fatalf("Execute() error = %v, want missing go.mod", err)
+	}
+}
+
+func TestBuildGoArgs(t *testing.T) {
+	out := filepath.Join("work", "bin")
+	tests := []struct {
+		name string
+		cfg  *pb.GoConfig
+		want []string
+	}{
+		{
+			name: "defaults",
+			cfg:  &pb.GoConfig{},
+			want: []string{"build", "-trimpath", "-o", out + string(filepath.Separator), "."},
+		},
+		{
+			name: "full",
+			cfg: &pb.GoConfig{
+				BuildTags: []string{"netgo", " ", "osusergo"},
+				Ldflags:   map[string]string{"main.version": "1.2.3", "-s": "", "-w": ""},
+				Packages:  []string{"./cmd/api", "./cmd/worker"},
+			},
+			want: []string{
+				"build", "-trimpath", "-o", out + string(filepath.Separator),
+				"-tags", "netgo,osusergo",
+				"-ldflags", "-s -w -X main.version=1.2.3",
+				"./cmd/api", "./cmd/worker",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildGoArgs(tt.cfg, out)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("buildGoArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatLdflags(t *testing.T) {
+	got := formatLdflags(map[string]string{
+		"main.commit":  "abc123",
+		"main.message": "hello world",
+		"-extldflags":  "-static",
+	})
+	want := "-extldflags -static -X main.commit=abc123 -X 'main.message=hello world'"
+	if got != want {
+		t.Fatalf("formatLdflags() = %q, want %q", got, want)
+	}
+}
+
+func TestGoBuildEnv(t *testing.T) {
+	if env := goBuildEnv(&pb.GoConfig{}); len(env) != 0 {
+		t.Fatalf("goBuildEnv() = %v, want no overrides", env)
+	}
+
+	env := strings.Join(goBuildEnv(&pb.GoConfig{Goos: runtime.GOOS, Goarch: runtime.GOARCH}), " ")
+	if strings.Contains(env, "CGO_ENABLED") {
+		t.Fatalf("native build should keep cgo, got %q", env)
+	}
+
+	cross := "windows"
+	if runtime.GOOS == "windows" {
+		cross = "linux"
+	}
+	env = strings.Join(goBuildEnv(&pb.GoConfig{Goos: cross, Goarch: "arm64"}), " ")
+	for _, want := range []string{"GOOS=" + cross, "GOARCH=arm64", "CGO_ENABLED=0"} {
+		if !strings.Contains(env, want) {
+			t.Fatalf("goBuildEnv() = %q, missing %s", env, want)
+		}
+	}
+}
+
+func TestGoExecutor_Execute_CollectsBinaries(t *testing.T) {
+	goCmd, argsFile := setupFakeGo(t)
+	archive := writeTarArchive(t, map[string]string{
+		"go.mod":              "module example.com/demo\n",
+		"cmd/api/main.go":     "package main",
+		"cmd/worker/main.go":  "package main",
+		"internal/lib/lib.go": "package lib",
+	})
+
+	executor := NewGoExecutorWithCommand(goCmd)
+	req := &Request{
+		TaskID:        "go-build",
+		BuildType:     pb.BuildType_BUILD_TYPE_GO,
+		SourceArchive: archive,
+		GoConfig: &pb.GoConfig{
+			Goos:      "linux",
+			Goarch:    "arm64",
+			BuildTags: []string{"netgo"},
+			Packages:  []string{"./cmd/api", "./cmd/worker"},
+		},
+		TimeoutSeconds: 10,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	result, err := executor.Execute(ctx, req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Execute() expected success, stderr=%q", result.Stderr)
+	}
+
+	var paths []string
+	for _, artifact := range result.Artifacts {
+		paths = append(paths, artifact.Path)
+	}
+	if strings.Join(paths, ",") != "bin/api,bin/worker" {
+		t.Fatalf("artifacts = %v, want [bin/api bin/worker]", paths)
+	}
+	if len(result.ArtifactArchive) == 0 {
+		t.Fatal("expected artifact archive")
+	}
+
+	args := readArgsFile(t, argsFile)
+	assertArgsContain(t, args, "build", "-trimpath", "-tags", "netgo", "./cmd/api", "./cmd/worker", "GOOS=linux", "GOARCH=arm64")
+}
+
+func TestGoExecutor_Execute_BuildFailure(t *testing.T) {
+	goCmd, _ := setupFakeGo(t)
+	t.Setenv("HG_GO_EXIT_CODE", "2")
+
+	executor := NewGoExecutorWithCommand(goCmd)
+	result, err := executor.Execute(context.Background(), &Request{
+		TaskID:        "go-fail",
+		BuildType:     pb.BuildType_BUILD_TYPE_GO,
+		SourceArchive: writeTarArchive(t, map[string]string{"go.mod": "module example.com/demo\n"}),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Success || result.ExitCode != 2 {
+		t.Fatalf("Execute() = success %v exit %d, want failure with exit 2", result.Success, result.ExitCode)
+	}
+}
+
+func TestManager_SelectForRequest_GoRoute(t *testing.T) {
+	m := &Manager{
+		native: &fakeExecutor{name: "native"},
+		golang: &fakeExecutor{name: "go"},
+	}
+
+	got := m.SelectForRequest(&Request{BuildType: pb.BuildType_BUILD_TYPE_GO})
+	if got.Name() != "go" {
+		t.Fatalf("SelectForRequest() executor = %s, want go", got.Name())
+	}
+}
+
+// setupFakeGo writes a go stand-in that records its arguments plus
+// GOOS/GOARCH and writes one binary per package pattern into the -o
+// directory, named after the pattern's last element.
+func setupFakeGo(t *testing.T) (string, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake go script requires a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	argsFile := filepath.Join(t.TempDir(), "go-args.txt")
+	scriptPath := filepath.Join(binDir, "go")
+	contents := "#!/bin/sh\n" +
+		"set -e\n" +
+		"printf '%s\\n' \"$@\" \"GOOS=$GOOS\" \"GOARCH=$GOARCH\" > \"$HG_GO_ARGS_FILE\"\n" +
+		"if [ -n \"$HG_GO_EXIT_CODE\" ]; then\n" +
+		"  exit \"$HG_GO_EXIT_CODE\"\n" +
+		"fi\n" +
+		"test -f go.mod || exit 3\n" +
+		"OUT=\"\"\n" +
+		"PKGS=\"\"\n" +
+		"while [ $# -gt 0 ]; do\n" +
+		"  case \"$1\" in\n" +
+		"    -o) OUT=\"$2\"; shift 2 ;;\n" +
+		"    -tags|-ldflags) shift 2 ;;\n" +
+		"    build|-*) shift ;;\n" +
+		"    *) PKGS=\"$PKGS $1\"; shift ;;\n" +
+		"  esac\n" +
+		"done\n" +
+		"for pkg in $PKGS; do\n" +
+		"  printf 'binary' > \"$OUT$(basename \"$pkg\")\"\n" +
+		"done\n" +
+		"exit 0\n"
+
+	if err := os.WriteFile(scriptPath, []byte(contents), 0755); err != nil {
+		t.Fatalf("Failed to write fake go: %v", err)
+	}
+
+	t.Setenv("HG_GO_ARGS_FILE", argsFile)
+	return scriptPath, argsFile
+}
@@ -221,6 +221,26 @@ func TestManager_SelectForRequest_UsesDockerForCrossOSRawSource(t *testing.T) {
 	}
 }
 
+func TestManager_SelectForRequest_LinkUsesNative(t *testing.T) {
+	m := &Manager{
+		native:     &fakeExecutor{name: "native"},
+		docker:     &fakeExecutor{name: "docker", canExecute: true},
+		nativeArch: pb.Architecture_ARCH_X86_64,
+	}
+
+	req := &Request{
+		Link:       true,
+		LinkInputs: map[string][]byte{"inputs/0-main.o": []byte("obj")},
+		ClientOs:   "different-os",
+		TargetArch: pb.Architecture_ARCH_ARM64,
+	}
+
+	got := m.SelectForRequest(req)
+	if got.Name() != "native" {
+		t.Fatalf("SelectForRequest() executor = %s, want native", got.Name())
+	}
+}
+
 // TestManager_SelectForCompiler tests compiler-based executor selection
 func TestManager_SelectForCompiler(t *testing.T) {
 	if runtime.GOOS == "windows" {
@@ -119,6 +119,7 @@ func (e *MSVCExecutor) Execute(ctx context.Context, req *Request) (*Result, erro
 
 	// Create command with context for timeout
 	cmd := exec.CommandContext(ctx, e.clExePath, args...)
+	killTreeOnCancel(cmd)
 	cmd.Dir = workDir
 	cmd.Env = e.env
 
@@ -48,8 +48,13 @@ func (e *NativeExecutor) Execute(ctx context.Context, req *Request) (*Result, er
 	// Determine output file path
 	outFile := filepath.Join(workDir, "output.o")
 
-	// Check if using raw source mode (cross-compilation) or preprocessed mode
-	if len(req.RawSource) > 0 {
+	// Check if linking, using raw source mode (cross-compilation) or preprocessed mode
+	if req.Link {
+		args, err = e.setupLinkMode(workDir, req, outFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup link: %w", err)
+		}
+	} else if len(req.RawSource) > 0 {
 		// Mode 2: Raw source - need to set up includes and compile from scratch
 		// srcFile is embedded in args, so we discard it
 		_, args, err = e.setupRawSourceMode(workDir, req, outFile)
@@ -67,6 +72,7 @@ func (e *NativeExecutor) Execute(ctx context.Context, req *Request) (*Result, er
 
 	// Create command with context for timeout
 	cmd := exec.CommandContext(ctx, req.Compiler, args...)
+	killTreeOnCancel(cmd)
 	cmd.Dir = workDir
 
 	var stdout, stderr bytes.Buffer
@@ -249,3 +255,26 @@ func (e *NativeExecutor) setupRawSourceMode(workDir string, req *Request, outFil
 
 	return srcFile, args, nil
 }
+
+// setupLinkMode writes the shipped object files and static libraries into
+// the working directory and returns the link arguments. Input names are the
+// ones the client put into req.Args, so they must stay inside workDir.
+func (e *NativeExecutor) setupLinkMode(workDir string, req *Request, outFile string) ([]string, error) {
+	for name, content := range req.LinkInputs {
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return nil, fmt.Errorf("invalid link input name %q", name)
+		}
+		fullPath := filepath.Join(workDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create dir for %s: %w", name, err)
+		}
+		if err := os.WriteFile(fullPath, content, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write link input %s: %w", name, err)
+		}
+	}
+
+	args := make([]string, 0, len(req.Args)+2)
+	args = append(args, req.Args...)
+	args = append(args, "-o", outFile)
+	return args, nil
+}
@@ -291,3 +291,67 @@ func TestNativeExecutor_Execute_TimeoutReturnsFailedResult(t *testing.T) {
 		t.Fatalf("Execute() exit code = %d, want -1", result.ExitCode)
 	}
 }
+
+func TestNativeExecutor_Execute_LinkMode(t *testing.T) {
+	if _, err := exec.LookPath("gcc"); err != nil {
+		t.Skip("gcc not found, skipping test")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	e := NewNativeExecutor()
+	objects := make(map[string][]byte)
+	for name, source := range map[string]string{
+		"inputs/0-main.o": "int add(int, int);\nint main(void) { return add(1, 2) - 3; }",
+		"inputs/1-add.o":  "int add(int a, int b) { return a + b; }",
+	} {
+		result, err := e.Execute(ctx, &Request{
+			TaskID:             "link-obj",
+			Compiler:           "gcc",
+			Args:               []string{"-c"},
+			PreprocessedSource: []byte(source),
+			Timeout:            30 * time.Second,
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("compile %s failed: %v %v", name, err, result)
+		}
+		objects[name] = result.ObjectCode
+	}
+
+	result, err := e.Execute(ctx, &Request{
+		TaskID:     "link-001",
+		Compiler:   "gcc",
+		Args:       []string{"inputs/0-main.o", "inputs/1-add.o"},
+		Link:       true,
+		LinkInputs: objects,
+		Timeout:    30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Link failed: %s", result.Stderr)
+	}
+
+	binary := filepath.Join(t.TempDir(), "app")
+	if err := os.WriteFile(binary, result.ObjectCode, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command(binary).Run(); err != nil {
+		t.Fatalf("linked binary failed: %v", err)
+	}
+}
+
+func TestNativeExecutor_SetupLinkMode_RejectsEscapingInputs(t *testing.T) {
+	e := NewNativeExecutor()
+
+	for _, name := range []string{"../evil.o", "/tmp/evil.o", ""} {
+		_, err := e.setupLinkMode(t.TempDir(), &Request{
+			LinkInputs: map[string][]byte{name: []byte("obj")},
+		}, "output")
+		if err == nil {
+			t.Errorf("setupLinkMode(%q) expected error", name)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+const (
+	maxNodeLogBytes = 256 * 1024
+
+	defaultNodeBuildScript = "build"
+)
+
+// nodeLockfiles maps each package manager to the lockfiles it installs from,
+// in detection order.
+var nodeLockfiles = []struct {
+	manager string
+	files   []string
+}{
+	{manager: "pnpm", files: []string{"pnpm-lock.yaml"}},
+	{manager: "yarn", files: []string{"yarn.lock"}},
+	{manager: "npm", files: []string{"package-lock.json", "npm-shrinkwrap.json"}},
+}
+
+// nodeOutputDirs are the build output directories tried, in order, when the
+// request does not name one.
+var nodeOutputDirs = []string{"dist", "build", "out"}
+
+type NodeExecutor struct {
+	commands map[string]string
+}
+
+func NewNodeExecutor() *NodeExecutor {
+	return &NodeExecutor{commands: map[string]string{
+		"npm":  "npm",
+		"yarn": "yarn",
+		"pnpm": "pnpm",
+	}}
+}
+
+// NewNodeExecutorWithCommands uses the given executable for each package
+// manager ("npm", "yarn", "pnpm"); managers missing from commands are looked
+// up by name.
+func NewNodeExecutorWithCommands(commands map[string]string) *NodeExecutor {
+	return &NodeExecutor{commands: commands}
+}
+
+func (e *NodeExecutor) Name() string {
+	return "node"
+}
+
+func (e *NodeExecutor) CanExecute(targetArch pb.Architecture, nativeArch pb.Architecture) bool {
+	return true
+}
+
+func (e *NodeExecutor) Execute(ctx context.Context, req *Request) (*Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if req == nil {
+		return nil, fmt.Errorf("build request required")
+	}
+	if req.BuildType != pb.BuildType_BUILD_TYPE_NODEJS {
+		return nil, fmt.Errorf("unsupported build type: %s", req.BuildType.String())
+	}
+
+	nodeConfig := req.NodeConfig
+	if nodeConfig == nil {
+		nodeConfig = &pb.NodeConfig{}
+	}
+
+	outputDir := strings.TrimSpace(nodeConfig.GetOutputDir())
+	if outputDir != "" && !filepath.IsLocal(filepath.FromSlash(outputDir)) {
+		return nil, fmt.Errorf("invalid output_dir %q", outputDir)
+	}
+
+	execCtx := ctx
+	var cancel context.CancelFunc
+	if req.Timeout > 0 {
+		execCtx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	} else if req.TimeoutSeconds > 0 {
+		execCtx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	workDir, err := os.MkdirTemp("", fmt.Sprintf("hg-node-%s-", req.TaskID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := extractRequestSource(workDir, req); err != nil {
+		return nil, fmt.Errorf("failed to extract source archive: %w", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(workDir, "package.json")); err != nil {
+		return nil, fmt.Errorf("source archive has no package.json")
+	}
+
+	manager, err := resolvePackageManager(workDir, nodeConfig.GetPackageManager())
+	if err != nil {
+		return nil, err
+	}
+	command := e.commands[manager]
+	if command == "" {
+		command = manager
+	}
+
+	env := append(os.Environ(), nodeEnv(nodeConfig)...)
+
+	stdout := newLimitedBuffer(maxNodeLogBytes)
+	stderr := newLimitedBuffer(maxNodeLogBytes)
+
+	start := time.Now()
+	steps := [][]string{
+		nodeInstallArgs(manager, hasLockfile(workDir, manager)),
+		nodeBuildArgs(nodeConfig),
+	}
+	for _, args := range steps {
+		cmd := exec.CommandContext(execCtx, command, args...)
+		killTreeOnCancel(cmd)
+		cmd.Dir = workDir
+		cmd.Env = env
+		flushOutput := attachOutput(cmd, req, stdout, stderr)
+
+		err = cmd.Run()
+		flushOutput()
+		if err != nil {
+			break
+		}
+	}
+	buildTime := time.Since(start)
+
+	result := &Result{
+		Stdout:          strings.TrimSpace(stdout.String()),
+		Stderr:          strings.TrimSpace(stderr.String()),
+		CompilationTime: buildTime,
+	}
+
+	if execCtx.Err() == context.DeadlineExceeded {
+		result.ExitCode = -1
+		result.Success = false
+		result.Stderr = strings.TrimSpace(joinNonEmpty(result.Stderr, "node build timed out"))
+		return result, nil
+	}
+
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = int32(exitErr.ExitCode())
+			result.Success = false
+			return result, nil
+		}
+		return nil, fmt.Errorf("execution failed: %w", err)
+	}
+
+	result.ExitCode = 0
+
+	artifacts, archive, err := collectNodeArtifacts(workDir, outputDir)
+	if err != nil {
+		result.Success = false
+		result.ExitCode = 1
+		result.Stderr = strings.TrimSpace(joinNonEmpty(result.Stderr, err.Error()))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Artifacts = artifacts
+	result.ArtifactArchive = archive
+	if len(artifacts) > 0 {
+		result.ArtifactPath = artifacts[0].Path
+	}
+
+	return result, nil
+}
+
+// resolvePackageManager returns the requested package manager, or the one
+// whose lockfile is present in projectDir. Projects without a lockfile use
+// npm.
+func resolvePackageManager(projectDir, requested string) (string, error) {
+	requested = strings.ToLower(strings.TrimSpace(requested))
+	if requested != "" {
+		for _, lf := range nodeLockfiles {
+			if lf.manager == requested {
+				return requested, nil
+			}
+		}
+		return "", fmt.Errorf("unsupported package manager %q (supported: npm, yarn, pnpm)", requested)
+	}
+
+	for _, lf := range nodeLockfiles {
+		if hasLockfile(projectDir, lf.manager) {
+			return lf.manager, nil
+		}
+	}
+	return "npm", nil
+}
+
+func hasLockfile(projectDir, manager string) bool {
+	for _, lf := range nodeLockfiles {
+		if lf.manager != manager {
+			continue
+		}
+		for _, name := range lf.files {
+			if _, err := os.Stat(filepath.Join(projectDir, name)); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nodeInstallArgs installs exactly what the lockfile pins when there is one,
+// failing rather than updating it.
+func nodeInstallArgs(manager string, locked bool) []string {
+	if !locked {
+		return []string{"install"}
+	}
+	if manager == "npm" {
+		return []string{"ci"}
+	}
+	return []string{"install", "--frozen-lockfile"}
+}
+
+func nodeBuildArgs(cfg *pb.NodeConfig) []string {
+	script := strings.TrimSpace(cfg.GetBuildScript())
+	if script == "" {
+		script = defaultNodeBuildScript
+	}
+	return []string{"run", script}
+}
+
+// nodeEnv returns the request's environment variables sorted by name.
+func nodeEnv(cfg *pb.NodeConfig) []string {
+	keys := make([]string, 0, len(cfg.GetEnvVars()))
+	for k := range cfg.GetEnvVars() {
+		if strings.TrimSpace(k) != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	env := make([]string, 0, len(keys))
+	for _, k := range keys {
+		env = append(env, k+"="+cfg.GetEnvVars()[k])
+	}
+	return env
+}
+
+// collectNodeArtifacts archives every file under the build output
+// directory: outputDir when set, otherwise the first of dist, build or out
+// that exists.
+func collectNodeArtifacts(workDir, outputDir string) ([]*pb.ArtifactInfo, []byte, error) {
+	candidates := nodeOutputDirs
+	if outputDir != "" {
+		candidates = []string{outputDir}
+	}
+
+	searchRoot := ""
+	for _, dir := range candidates {
+		path := filepath.Join(workDir, filepath.FromSlash(dir))
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			searchRoot = path
+			break
+		}
+	}
+	if searchRoot == "" {
+		return nil, nil, fmt.Errorf("build output directory not found (looked for %s)", strings.Join(candidates, ", "))
+	}
+
+	entries := make([]flutterArtifact, 0)
+	walkErr := filepath.WalkDir(searchRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(workDir, path)
+		if err != nil {
+			return err
+		}
+
+		checksum, size, err := checksumFile(path)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, flutterArtifact{
+			info: &pb.ArtifactInfo{
+				Name:      filepath.Base(path),
+				Path:      filepath.ToSlash(relPath),
+				SizeBytes: size,
+				Checksum:  checksum,
+			},
+			absPath: path,
+		})
+		return nil
+	})
+	if walkErr != nil {
+		return nil, nil, fmt.Errorf("failed to scan artifacts: %w", walkErr)
+	}
+
+	if len(entries) == 0 {
+		return nil, nil, fmt.Errorf("build output directory is empty")
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].info.Path < entries[j].info.Path
+	})
+
+	archive, err := archiveArtifacts(workDir, entries)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to archive artifacts: %w", err)
+	}
+
+	artifactList := make([]*pb.ArtifactInfo, 0, len(entries))
+	for _, entry := range entries {
+		artifactList = append(artifactList, entry.info)
+	}
+
+	return artifactList, archive, nil
+}
@@ -0,0 +1,241 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+var _ Executor = (*NodeExecutor)(nil)
+
+func TestNodeExecutor_Name(t *testing.T) {
+	executor := NewNodeExecutor()
+	if executor.Name() != "node" {
+		t.Fatalf("Name() = %q, want %q", executor.Name(), "node")
+	}
+	for _, manager := range []string{"npm", "yarn", "pnpm"} {
+		if executor.commands[manager] != manager {
+			t.Fatalf("commands[%s] = %q", manager, executor.commands[manager])
+		}
+	}
+}
+
+func TestNodeExecutor_Execute_ValidatesBuildType(t *testing.T) {
+	executor := NewNodeExecutor()
+	_, err := executor.Execute(context.Background(), &Request{
+		BuildType:  pb.BuildType_BUILD_TYPE_GO,
+		NodeConfig: &pb.NodeConfig{},
+	})
+	if err == nil {
+		t.Fatal("Execute() expected error for non-node build type")
+	}
+}
+
+func TestNodeExecutor_Execute_RequiresPackageJSON(t *testing.T) {
+	executor := NewNodeExecutor()
+	_, err := executor.Execute(context.Background(), &Request{
+		TaskID:        "node-no-package",
+		BuildType:     pb.BuildType_BUILD_TYPE_NODEJS,
+		SourceArchive: writeTarArchive(t, map[string]string{"index.js": ""}),
+	})
+	if err == nil || !strings.Contains(err.Error(), "package.json") {
+		t.Fatalf("Execute() error = %v, want missing package.json", err)
+	}
+}
+
+func TestNodeExecutor_Execute_RejectsEscapingOutputDir(t *testing.T) {
+	executor := NewNodeExecutor()
+	_, err := executor.Execute(context.Background(), &Request{
+		TaskID:     "node-escape",
+		BuildType:  pb.BuildType_BUILD_TYPE_NODEJS,
+		NodeConfig: &pb.NodeConfig{OutputDir: "../etc"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "output_dir") {
+		t.Fatalf("Execute() error = %v, want invalid output_dir", err)
+	}
+}
+
+func TestResolvePackageManager(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     []string
+		requested string
+		want      string
+		wantErr   bool
+	}{
+		{name: "no lockfile", want: "npm"},
+		{name: "package-lock", files: []string{"package-lock.json"}, want: "npm"},
+		{name: "yarn.lock", files: []string{"yarn.lock"}, want: "yarn"},
+		{name: "pnpm-lock", files: []string{"pnpm-lock.yaml", "package-lock.json"}, want: "pnpm"},
+		{name: "requested wins", files: []string{"yarn.lock"}, requested: "PNPM", want: "pnpm"},
+		{name: "unsupported", requested: "bun", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", name, err)
+				}
+			}
+
+			got, err := resolvePackageManager(dir, tt.requested)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolvePackageManager() = %q, want error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("resolvePackageManager() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeInstallArgs(t *testing.T) {
+	tests := []struct {
+		manager string
+		locked  bool
+		want    string
+	}{
+		{manager: "npm", locked: true, want: "ci"},
+		{manager: "npm", locked: false, want: "install"},
+		{manager: "yarn", locked: true, want: "install --frozen-lockfile"},
+		{manager: "pnpm", locked: true, want: "install --frozen-lockfile"},
+		{manager: "pnpm", locked: false, want: "install"},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(nodeInstallArgs(tt.manager, tt.locked), " "); got != tt.want {
+			t.Errorf("nodeInstallArgs(%s, %v) = %q, want %q", tt.manager, tt.locked, got, tt.want)
+		}
+	}
+}
+
+func TestNodeExecutor_Execute_InstallsAndCollectsDist(t *testing.T) {
+	pnpmCmd, argsFile := setupFakeNodeManager(t, "pnpm")
+	archive := writeTarArchive(t, map[string]string{
+		"package.json":   `{"name":"demo","scripts":{"build:prod":"vite build"}}`,
+		"pnpm-lock.yaml": "lockfileVersion: '9.0'\n",
+		"src/index.ts":   "export {}",
+	})
+
+	executor := NewNodeExecutorWithCommands(map[string]string{"pnpm": pnpmCmd})
+	req := &Request{
+		TaskID:        "node-build",
+		BuildType:     pb.BuildType_BUILD_TYPE_NODEJS,
+		SourceArchive: archive,
+		NodeConfig: &pb.NodeConfig{
+			BuildScript: "build:prod",
+			EnvVars:     map[string]string{"HG_NODE_MARKER": "prod"},
+		},
+		TimeoutSeconds: 10,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	result, err := executor.Execute(ctx, req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Execute() expected success, stderr=%q", result.Stderr)
+	}
+
+	var paths []string
+	for _, artifact := range result.Artifacts {
+		paths = append(paths, artifact.Path)
+	}
+	if strings.Join(paths, ",") != "dist/assets/app.js,dist/index.html" {
+		t.Fatalf("artifacts = %v", paths)
+	}
+	if len(result.ArtifactArchive) == 0 {
+		t.Fatal("expected artifact archive")
+	}
+
+	calls := readArgsFile(t, argsFile)
+	want := []string{"install --frozen-lockfile", "run build:prod marker=prod"}
+	if strings.Join(calls, "|") != strings.Join(want, "|") {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestNodeExecutor_Execute_InstallFailure(t *testing.T) {
+	npmCmd, argsFile := setupFakeNodeManager(t, "npm")
+	t.Setenv("HG_NODE_FAIL_STEP", "ci")
+
+	executor := NewNodeExecutorWithCommands(map[string]string{"npm": npmCmd})
+	result, err := executor.Execute(context.Background(), &Request{
+		TaskID:    "node-fail",
+		BuildType: pb.BuildType_BUILD_TYPE_NODEJS,
+		SourceArchive: writeTarArchive(t, map[string]string{
+			"package.json":      `{"name":"demo"}`,
+			"package-lock.json": "{}",
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Success || result.ExitCode != 1 {
+		t.Fatalf("Execute() = success %v exit %d, want install failure", result.Success, result.ExitCode)
+	}
+	if calls := readArgsFile(t, argsFile); len(calls) != 1 {
+		t.Fatalf("expected build script to be skipped, calls = %q", calls)
+	}
+}
+
+func TestManager_SelectForRequest_NodeRoute(t *testing.T) {
+	m := &Manager{
+		native: &fakeExecutor{name: "native"},
+		node:   &fakeExecutor{name: "node"},
+	}
+
+	got := m.SelectForRequest(&Request{BuildType: pb.BuildType_BUILD_TYPE_NODEJS})
+	if got.Name() != "node" {
+		t.Fatalf("SelectForRequest() executor = %s, want node", got.Name())
+	}
+}
+
+// setupFakeNodeManager writes a package manager stand-in that appends one
+// line per invocation to the args file. "run <script>" writes a small dist/
+// tree and records HG_NODE_MARKER; HG_NODE_FAIL_STEP makes the matching
+// subcommand exit 1.
+func setupFakeNodeManager(t *testing.T, name string) (string, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake package manager script requires a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	argsFile := filepath.Join(t.TempDir(), name+"-args.txt")
+	scriptPath := filepath.Join(binDir, name)
+	contents := "#!/bin/sh\n" +
+		"set -e\n" +
+		"if [ \"$1\" = \"run\" ]; then\n" +
+		"  echo \"$* marker=$HG_NODE_MARKER\" >> \"$HG_NODE_ARGS_FILE\"\n" +
+		"  mkdir -p dist/assets\n" +
+		"  printf '<html></html>' > dist/index.html\n" +
+		"  printf 'console.log(1)' > dist/assets/app.js\n" +
+		"else\n" +
+		"  echo \"$*\" >> \"$HG_NODE_ARGS_FILE\"\n" +
+		"fi\n" +
+		"if [ -n \"$HG_NODE_FAIL_STEP\" ] && [ \"$1\" = \"$HG_NODE_FAIL_STEP\" ]; then\n" +
+		"  exit 1\n" +
+		"fi\n" +
+		"exit 0\n"
+
+	if err := os.WriteFile(scriptPath, []byte(contents), 0755); err != nil {
+		t.Fatalf("Failed to write fake %s: %v", name, err)
+	}
+
+	t.Setenv("HG_NODE_ARGS_FILE", argsFile)
+	return scriptPath, argsFile
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"strings"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+// maxOutputLineBytes splits lines that never end, such as progress bars
+// redrawn with carriage returns, so they still reach the output func.
+const maxOutputLineBytes = 16 * 1024
+
+// OutputFunc receives a build's output one line at a time while it runs.
+// It is called from the goroutines copying stdout and stderr, so it must be
+// safe for concurrent use.
+type OutputFunc func(stream pb.LogStream, line string)
+
+// lineWriter passes each complete line written to it to an OutputFunc.
+type lineWriter struct {
+	output  OutputFunc
+	stream  pb.LogStream
+	partial []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.partial[:i])
+		w.partial = w.partial[i+1:]
+	}
+	if len(w.partial) >= maxOutputLineBytes {
+		w.emit(w.partial)
+		w.partial = nil
+	}
+	return len(p), nil
+}
+
+// Flush emits an unterminated final line.
+func (w *lineWriter) Flush() {
+	if len(w.partial) > 0 {
+		w.emit(w.partial)
+		w.partial = nil
+	}
+}
+
+func (w *lineWriter) emit(line []byte) {
+	w.output(w.stream, strings.TrimRight(string(line), "\r"))
+}
+
+// attachOutput connects cmd's stdout and stderr to the given writers and,
+// when req.Output is set, streams them to it as well. The returned func
+// flushes unterminated lines and must be called after cmd exits.
+func attachOutput(cmd *exec.Cmd, req *Request, stdout, stderr io.Writer) func() {
+	if req.Output == nil {
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return func() {}
+	}
+
+	outLines := &lineWriter{output: req.Output, stream: pb.LogStream_LOG_STREAM_STDOUT}
+	errLines := &lineWriter{output: req.Output, stream: pb.LogStream_LOG_STREAM_STDERR}
+	cmd.Stdout = io.MultiWriter(stdout, outLines)
+	cmd.Stderr = io.MultiWriter(stderr, errLines)
+	return func() {
+		outLines.Flush()
+		errLines.Flush()
+	}
+}
@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+// outputRecorder collects the lines passed to an OutputFunc.
+type outputRecorder struct {
+	mu    sync.Mutex
+	lines map[pb.LogStream][]string
+}
+
+func (r *outputRecorder) record(stream pb.LogStream, line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lines == nil {
+		r.lines = make(map[pb.LogStream][]string)
+	}
+	r.lines[stream] = append(r.lines[stream], line)
+}
+
+func (r *outputRecorder) get(stream pb.LogStream) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.lines[stream]...)
+}
+
+func TestLineWriter_SplitsWrites(t *testing.T) {
+	rec := &outputRecorder{}
+	w := &lineWriter{output: rec.record, stream: pb.LogStream_LOG_STREAM_STDOUT}
+
+	w.Write([]byte("first\r\nsec"))
+	w.Write([]byte("ond\n\nthi"))
+	if got := rec.get(pb.LogStream_LOG_STREAM_STDOUT); !reflect.DeepEqual(got, []string{"first", "second", ""}) {
+		t.Fatalf("lines before flush = %q", got)
+	}
+
+	w.Flush()
+	w.Flush()
+	if got := rec.get(pb.LogStream_LOG_STREAM_STDOUT); !reflect.DeepEqual(got, []string{"first", "second", "", "thi"}) {
+		t.Fatalf("lines after flush = %q", got)
+	}
+}
+
+func TestLineWriter_SplitsLongLines(t *testing.T) {
+	rec := &outputRecorder{}
+	w := &lineWriter{output: rec.record, stream: pb.LogStream_LOG_STREAM_STDOUT}
+
+	w.Write([]byte(strings.Repeat("x", maxOutputLineBytes+1)))
+	if got := rec.get(pb.LogStream_LOG_STREAM_STDOUT); len(got) != 1 {
+		t.Fatalf("got %d lines, want 1", len(got))
+	}
+}
+
+func TestAttachOutput_TeesBothStreams(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	rec := &outputRecorder{}
+	stdout := newLimitedBuffer(1024)
+	stderr := newLimitedBuffer(1024)
+	cmd := exec.Command("sh", "-c", "echo out; echo err >&2; printf tail")
+	flush := attachOutput(cmd, &Request{Output: rec.record}, stdout, stderr)
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	flush()
+
+	if got := rec.get(pb.LogStream_LOG_STREAM_STDOUT); !reflect.DeepEqual(got, []string{"out", "tail"}) {
+		t.Fatalf("stdout lines = %q", got)
+	}
+	if got := rec.get(pb.LogStream_LOG_STREAM_STDERR); !reflect.DeepEqual(got, []string{"err"}) {
+		t.Fatalf("stderr lines = %q", got)
+	}
+	if stdout.String() != "out\ntail" || stderr.String() != "err\n" {
+		t.Fatalf("buffers = %q, %q", stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package executor
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killTreeOnCancel runs cmd in its own process group and makes context
+// cancellation kill the whole group rather than only the direct child, so
+// compiler drivers, Gradle and Unity do not leave helpers running after a
+// timeout or CancelTask.
+func killTreeOnCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = processWaitDelay
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package executor
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// killTreeOnCancel makes context cancellation kill cmd together with every
+// process it started, so compiler drivers, Gradle and Unity do not leave
+// helpers running after a timeout or CancelTask.
+func killTreeOnCancel(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+		if err := kill.Run(); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = processWaitDelay
+}
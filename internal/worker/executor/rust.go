@@ -0,0 +1,247 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+const maxRustLogBytes = 256 * 1024
+
+type RustExecutor struct {
+	command string
+}
+
+func NewRustExecutor() *RustExecutor {
+	return &RustExecutor{command: "cargo"}
+}
+
+func NewRustExecutorWithCommand(command string) *RustExecutor {
+	return &RustExecutor{command: command}
+}
+
+func (e *RustExecutor) Name() string {
+	return "rust"
+}
+
+func (e *RustExecutor) CanExecute(targetArch pb.Architecture, nativeArch pb.Architecture) bool {
+	return true
+}
+
+func (e *RustExecutor) Execute(ctx context.Context, req *Request) (*Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if req == nil {
+		return nil, fmt.Errorf("build request required")
+	}
+	if req.BuildType != pb.BuildType_BUILD_TYPE_RUST {
+		return nil, fmt.Errorf("unsupported build type: %s", req.BuildType.String())
+	}
+
+	rustConfig := req.RustConfig
+	if rustConfig == nil {
+		rustConfig = &pb.RustConfig{}
+	}
+
+	execCtx := ctx
+	var cancel context.CancelFunc
+	if req.Timeout > 0 {
+		execCtx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	} else if req.TimeoutSeconds > 0 {
+		execCtx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	workDir, err := os.MkdirTemp("", fmt.Sprintf("hg-rust-%s-", req.TaskID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := extractRequestSource(workDir, req); err != nil {
+		return nil, fmt.Errorf("failed to extract source archive: %w", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(workDir, "Cargo.toml")); err != nil {
+		return nil, fmt.Errorf("source archive has no Cargo.toml")
+	}
+
+	targetDir := filepath.Join(workDir, "target")
+	cmd := exec.CommandContext(execCtx, e.command, buildCargoArgs(rustConfig)...)
+	killTreeOnCancel(cmd)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "CARGO_TARGET_DIR="+targetDir)
+
+	stdout := newLimitedBuffer(maxRustLogBytes)
+	stderr := newLimitedBuffer(maxRustLogBytes)
+	flushOutput := attachOutput(cmd, req, stdout, stderr)
+
+	start := time.Now()
+	err = cmd.Run()
+	flushOutput()
+	buildTime := time.Since(start)
+
+	result := &Result{
+		Stdout:          strings.TrimSpace(stdout.String()),
+		Stderr:          strings.TrimSpace(stderr.String()),
+		CompilationTime: buildTime,
+	}
+
+	if execCtx.Err() == context.DeadlineExceeded {
+		result.ExitCode = -1
+		result.Success = false
+		result.Stderr = strings.TrimSpace(joinNonEmpty(result.Stderr, "cargo build timed out"))
+		return result, nil
+	}
+
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = int32(exitErr.ExitCode())
+			result.Success = false
+			return result, nil
+		}
+		return nil, fmt.Errorf("execution failed: %w", err)
+	}
+
+	result.ExitCode = 0
+
+	artifacts, archive, err := collectRustArtifacts(workDir, rustOutputDir(targetDir, rustConfig))
+	if err != nil {
+		result.Success = false
+		result.ExitCode = 1
+		result.Stderr = strings.TrimSpace(joinNonEmpty(result.Stderr, err.Error()))
+		return result, nil
+	}
+
+	result.Success = true
+	result.Artifacts = artifacts
+	result.ArtifactArchive = archive
+	if len(artifacts) > 0 {
+		result.ArtifactPath = artifacts[0].Path
+	}
+
+	return result, nil
+}
+
+// buildCargoArgs returns the cargo arguments for cfg. A toolchain is
+// selected with rustup's "+toolchain" syntax.
+func buildCargoArgs(cfg *pb.RustConfig) []string {
+	args := make([]string, 0, 8)
+
+	if toolchain := strings.TrimSpace(cfg.GetToolchain()); toolchain != "" {
+		args = append(args, "+"+toolchain)
+	}
+
+	args = append(args, "build")
+
+	if cfg.GetRelease() {
+		args = append(args, "--release")
+	}
+	if target := strings.TrimSpace(cfg.GetTarget()); target != "" {
+		args = append(args, "--target", target)
+	}
+
+	features := make([]string, 0, len(cfg.GetFeatures()))
+	for _, feature := range cfg.GetFeatures() {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			features = append(features, feature)
+		}
+	}
+	if len(features) > 0 {
+		args = append(args, "--features", strings.Join(features, ","))
+	}
+
+	return args
+}
+
+// rustOutputDir returns target/<triple>/<profile>, or target/<profile> for
+// host builds.
+func rustOutputDir(targetDir string, cfg *pb.RustConfig) string {
+	profile := "debug"
+	if cfg.GetRelease() {
+		profile = "release"
+	}
+
+	if target := strings.TrimSpace(cfg.GetTarget()); target != "" {
+		return filepath.Join(targetDir, target, profile)
+	}
+	return filepath.Join(targetDir, profile)
+}
+
+// collectRustArtifacts archives the binaries and libraries cargo left at the
+// top level of outputDir. Intermediate directories (deps, build, incremental)
+// and dep-info files are skipped.
+func collectRustArtifacts(workDir, outputDir string) ([]*pb.ArtifactInfo, []byte, error) {
+	dirEntries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build output directory not found")
+	}
+
+	entries := make([]flutterArtifact, 0)
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.Type().IsRegular() || !isRustArtifact(dirEntry.Name()) {
+			continue
+		}
+
+		path := filepath.Join(outputDir, dirEntry.Name())
+		relPath, err := filepath.Rel(workDir, path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		checksum, size, err := checksumFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan artifacts: %w", err)
+		}
+
+		entries = append(entries, flutterArtifact{
+			info: &pb.ArtifactInfo{
+				Name:      dirEntry.Name(),
+				Path:      filepath.ToSlash(relPath),
+				SizeBytes: size,
+				Checksum:  checksum,
+			},
+			absPath: path,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil, nil, fmt.Errorf("no cargo build outputs found")
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].info.Path < entries[j].info.Path
+	})
+
+	archive, err := archiveArtifacts(workDir, entries)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to archive artifacts: %w", err)
+	}
+
+	artifactList := make([]*pb.ArtifactInfo, 0, len(entries))
+	for _, entry := range entries {
+		artifactList = append(artifactList, entry.info)
+	}
+
+	return artifactList, archive, nil
+}
+
+func isRustArtifact(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	switch filepath.Ext(name) {
+	case ".d", ".lock", ".json":
+		return false
+	}
+	return true
+}
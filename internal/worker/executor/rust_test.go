@@ -0,0 +1,216 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
+)
+
+var _ Executor = (*RustExecutor)(nil)
+
+func TestRustExecutor_Name(t *testing.T) {
+	executor := NewRustExecutor()
+	if executor.Name() != "rust" {
+		t.Fatalf("Name() = %q, want %q", executor.Name(), "rust")
+	}
+	if executor.command != "cargo" {
+		t.Fatalf("command = %q, want %q", executor.command, "cargo")
+	}
+}
+
+func TestRustExecutor_Execute_ValidatesBuildType(t *testing.T) {
+	executor := NewRustExecutorWithCommand("cargo")
+	_, err := executor.Execute(context.Background(), &Request{
+		BuildType:  pb.BuildType_BUILD_TYPE_GO,
+		RustConfig: &pb.RustConfig{},
+	})
+	if err == nil {
+		t.Fatal("Execute() expected error for non-rust build type")
+	}
+}
+
+func TestRustExecutor_Execute_RequiresCargoToml(t *testing.T) {
+	executor := NewRustExecutorWithCommand("cargo")
+	_, err := executor.Execute(context.Background(), &Request{
+		TaskID:        "rust-no-manifest",
+		BuildType:     pb.BuildType_BUILD_TYPE_RUST,
+		SourceArchive: writeTarArchive(t, map[string]string{"src/main.rs": "fn main() {}"}),
+	})
+	if err == nil || !strings.Contains(err.Error(), "Cargo.toml") {
+		t.Fatalf("Execute() error = %v, want missing Cargo.toml", err)
+	}
+}
+
+func TestBuildCargoArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *pb.RustConfig
+		want []string
+	}{
+		{name: "defaults", cfg: &pb.RustConfig{}, want: []string{"build"}},
+		{
+			name: "full",
+			cfg: &pb.RustConfig{
+				Toolchain: "nightly",
+				Target:    "aarch64-unknown-linux-gnu",
+				Features:  []string{"tls", " ", "metrics"},
+				Release:   true,
+			},
+			want: []string{"+nightly", "build", "--release", "--target", "aarch64-unknown-linux-gnu", "--features", "tls,metrics"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildCargoArgs(tt.cfg)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Fatalf("buildCargoArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRustOutputDir(t *testing.T) {
+	if got := rustOutputDir("target", &pb.RustConfig{}); got != filepath.Join("target", "debug") {
+		t.Fatalf("rustOutputDir() = %q", got)
+	}
+	got := rustOutputDir("target", &pb.RustConfig{Target: "wasm32-unknown-unknown", Release: true})
+	if got != filepath.Join("target", "wasm32-unknown-unknown", "release") {
+		t.Fatalf("rustOutputDir() = %q", got)
+	}
+}
+
+func TestRustExecutor_Execute_CollectsTargetArtifacts(t *testing.T) {
+	cargoCmd, argsFile := setupFakeCargo(t)
+	archive := writeTarArchive(t, map[string]string{
+		"Cargo.toml":  "[package]\nname = \"demo\"\n",
+		"src/main.rs": "fn main() {}",
+	})
+
+	executor := NewRustExecutorWithCommand(cargoCmd)
+	req := &Request{
+		TaskID:        "rust-build",
+		BuildType:     pb.BuildType_BUILD_TYPE_RUST,
+		SourceArchive: archive,
+		RustConfig: &pb.RustConfig{
+			Toolchain: "stable",
+			Target:    "x86_64-unknown-linux-musl",
+			Features:  []string{"cli"},
+			Release:   true,
+		},
+		TimeoutSeconds: 10,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	result, err := executor.Execute(ctx, req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Execute() expected success, stderr=%q", result.Stderr)
+	}
+
+	var paths []string
+	for _, artifact := range result.Artifacts {
+		paths = append(paths, artifact.Path)
+		if artifact.Checksum == "" {
+			t.Fatalf("artifact %s has no checksum", artifact.Path)
+		}
+	}
+	want := []string{
+		"target/x86_64-unknown-linux-musl/release/demo",
+		"target/x86_64-unknown-linux-musl/release/libdemo.rlib",
+	}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Fatalf("artifacts = %v, want %v", paths, want)
+	}
+	if len(result.ArtifactArchive) == 0 {
+		t.Fatal("expected artifact archive")
+	}
+
+	args := readArgsFile(t, argsFile)
+	assertArgsContain(t, args, "+stable", "build", "--release", "--target", "x86_64-unknown-linux-musl", "--features", "cli")
+}
+
+func TestRustExecutor_Execute_CargoFailure(t *testing.T) {
+	cargoCmd, _ := setupFakeCargo(t)
+	t.Setenv("HG_CARGO_EXIT_CODE", "101")
+
+	executor := NewRustExecutorWithCommand(cargoCmd)
+	result, err := executor.Execute(context.Background(), &Request{
+		TaskID:        "rust-fail",
+		BuildType:     pb.BuildType_BUILD_TYPE_RUST,
+		SourceArchive: writeTarArchive(t, map[string]string{"Cargo.toml": "[package]\nname = \"demo\"\n"}),
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Success || result.ExitCode != 101 {
+		t.Fatalf("Execute() = success %v exit %d, want failure with exit 101", result.Success, result.ExitCode)
+	}
+}
+
+func TestManager_SelectForRequest_RustRoute(t *testing.T) {
+	m := &Manager{
+		native: &fakeExecutor{name: "native"},
+		rust:   &fakeExecutor{name: "rust"},
+	}
+
+	got := m.SelectForRequest(&Request{BuildType: pb.BuildType_BUILD_TYPE_RUST})
+	if got.Name() != "rust" {
+		t.Fatalf("SelectForRequest() executor = %s, want rust", got.Name())
+	}
+}
+
+// setupFakeCargo writes a cargo stand-in that records its arguments and
+// drops a binary, an rlib and the usual intermediate files into the profile
+// directory under $CARGO_TARGET_DIR.
+func setupFakeCargo(t *testing.T) (string, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake cargo script requires a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	argsFile := filepath.Join(t.TempDir(), "cargo-args.txt")
+	scriptPath := filepath.Join(binDir, "cargo")
+	contents := "#!/bin/sh\n" +
+		"set -e\n" +
+		"printf '%s\\n' \"$@\" > \"$HG_CARGO_ARGS_FILE\"\n" +
+		"if [ -n \"$HG_CARGO_EXIT_CODE\" ]; then\n" +
+		"  exit \"$HG_CARGO_EXIT_CODE\"\n" +
+		"fi\n" +
+		"PROFILE=debug\n" +
+		"TARGET=\"\"\n" +
+		"while [ $# -gt 0 ]; do\n" +
+		"  case \"$1\" in\n" +
+		"    --release) PROFILE=release; shift ;;\n" +
+		"    --target) TARGET=\"$2\"; shift 2 ;;\n" +
+		"    *) shift ;;\n" +
+		"  esac\n" +
+		"done\n" +
+		"OUT=\"$CARGO_TARGET_DIR/$PROFILE\"\n" +
+		"if [ -n \"$TARGET\" ]; then\n" +
+		"  OUT=\"$CARGO_TARGET_DIR/$TARGET/$PROFILE\"\n" +
+		"fi\n" +
+		"mkdir -p \"$OUT/deps\" \"$OUT/.fingerprint\"\n" +
+		"printf 'binary' > \"$OUT/demo\"\n" +
+		"printf 'rlib' > \"$OUT/libdemo.rlib\"\n" +
+		"printf 'deps' > \"$OUT/demo.d\"\n" +
+		"printf 'obj' > \"$OUT/deps/demo-1234\"\n" +
+		"exit 0\n"
+
+	if err := os.WriteFile(scriptPath, []byte(contents), 0755); err != nil {
+		t.Fatalf("Failed to write fake cargo: %v", err)
+	}
+
+	t.Setenv("HG_CARGO_ARGS_FILE", argsFile)
+	return scriptPath, argsFile
+}
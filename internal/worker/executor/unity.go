@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -16,6 +17,10 @@ import (
 
 const maxUnityLogBytes = 256 * 1024
 
+// unityLogPollInterval is how often the Unity log file is checked for new
+// output while a build streams it.
+const unityLogPollInterval = 500 * time.Millisecond
+
 type UnityExecutor struct {
 	command string
 }
@@ -76,10 +81,8 @@ func (e *UnityExecutor) Execute(ctx context.Context, req *Request) (*Result, err
 	}
 	defer os.RemoveAll(workDir)
 
-	if len(req.SourceArchive) > 0 {
-		if err := extractSourceArchive(workDir, req.SourceArchive); err != nil {
-			return nil, fmt.Errorf("failed to extract source archive: %w", err)
-		}
+	if err := extractRequestSource(workDir, req); err != nil {
+		return nil, fmt.Errorf("failed to extract source archive: %w", err)
 	}
 
 	logPath := filepath.Join(workDir, "unity-build.log")
@@ -89,15 +92,18 @@ func (e *UnityExecutor) Execute(ctx context.Context, req *Request) (*Result, err
 	}
 
 	cmd := exec.CommandContext(execCtx, e.command, args...)
+	killTreeOnCancel(cmd)
 	cmd.Dir = workDir
 
 	stdout := newLimitedBuffer(maxUnityLogBytes)
 	stderr := newLimitedBuffer(maxUnityLogBytes)
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
+	flushOutput := attachOutput(cmd, req, stdout, stderr)
+	stopTail := tailUnityLog(logPath, req.Output)
 
 	start := time.Now()
 	err = cmd.Run()
+	flushOutput()
+	stopTail()
 	buildTime := time.Since(start)
 
 	unityLog := readUnityLog(logPath)
@@ -367,6 +373,55 @@ func unityEditorPathPatterns() []string {
 	}
 }
 
+// tailUnityLog streams lines appended to the Unity log at path to output.
+// Unity writes its build log to the file rather than stdout, so this is how
+// a Unity build's progress is followed. The returned func stops tailing
+// after emitting the rest of the log.
+func tailUnityLog(path string, output OutputFunc) func() {
+	if output == nil {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		lines := &lineWriter{output: output, stream: pb.LogStream_LOG_STREAM_STDOUT}
+		var offset int64
+		readNew := func() {
+			f, err := os.Open(path)
+			if err != nil {
+				return
+			}
+			defer f.Close()
+			if _, err := f.Seek(offset, io.SeekStart); err != nil {
+				return
+			}
+			n, _ := io.Copy(lines, f)
+			offset += n
+		}
+
+		ticker := time.NewTicker(unityLogPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				readNew()
+			case <-stop:
+				readNew()
+				lines.Flush()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
 func readUnityLog(path string) string {
 	data, err := os.ReadFile(path)
 	if err != nil || len(data) == 0 {
@@ -169,6 +169,46 @@ func TestUnityExecutor_Execute_CommandConstruction(t *testing.T) {
 	assertArgOrder(t, args, "-customArg", "-flagOnly")
 }
 
+func TestUnityExecutor_Execute_StreamsLogFile(t *testing.T) {
+	unityCmd, _ := setupFakeUnity(t)
+	archive := writeTarArchive(t, map[string]string{
+		"ProjectSettings/ProjectVersion.txt": "m_EditorVersion: 2022.3.10f1",
+	})
+
+	rec := &outputRecorder{}
+	req := &Request{
+		TaskID:         "unity-stream",
+		BuildType:      pb.BuildType_BUILD_TYPE_UNITY,
+		TargetPlatform: pb.TargetPlatform_PLATFORM_ANDROID,
+		SourceArchive:  archive,
+		UnityConfig:    &pb.UnityConfig{BuildMethod: "BuildScript.Build"},
+		TimeoutSeconds: 10,
+		Output:         rec.record,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	result, err := NewUnityExecutorWithCommand(unityCmd).Execute(ctx, req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Execute() expected success, stderr=%q", result.Stderr)
+	}
+
+	lines := rec.get(pb.LogStream_LOG_STREAM_STDOUT)
+	found := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "unity-log" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("streamed lines = %q, want the Unity log file", lines)
+	}
+}
+
 func TestManager_SelectForRequest_UnityRoute(t *testing.T) {
 	m := &Manager{
 		native: &fakeExecutor{name: "native"},
@@ -3,7 +3,10 @@ package server
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
+	"os"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -15,7 +18,10 @@ import (
 
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
 	"github.com/h3nr1-d14z/hybridgrid/internal/capability"
+	"github.com/h3nr1-d14z/hybridgrid/internal/grpc/buildstream"
+	"github.com/h3nr1-d14z/hybridgrid/internal/grpc/cancellation"
 	"github.com/h3nr1-d14z/hybridgrid/internal/grpc/interceptors"
+	"github.com/h3nr1-d14z/hybridgrid/internal/grpc/tasklog"
 	"github.com/h3nr1-d14z/hybridgrid/internal/observability/tracing"
 	hgtls "github.com/h3nr1-d14z/hybridgrid/internal/security/tls"
 	"github.com/h3nr1-d14z/hybridgrid/internal/worker/executor"
@@ -49,6 +55,9 @@ type Server struct {
 	server       *grpc.Server
 	executor     *executor.Manager
 	capabilities *pb.WorkerCapabilities
+	load         *capability.LoadSampler
+	running      *cancellation.Registry
+	taskLogs     *tasklog.Broker
 
 	activeTasks  int64
 	totalTasks   int64
@@ -66,7 +75,10 @@ func New(cfg Config) *Server {
 	return &Server{
 		config:       cfg,
 		capabilities: caps,
+		load:         capability.NewLoadSampler(os.TempDir(), caps.DockerAvailable),
 		executor:     executor.NewManager(caps.NativeArch, caps.DockerAvailable),
+		running:      cancellation.NewRegistry(),
+		taskLogs:     tasklog.NewBroker(),
 	}
 }
 
@@ -75,6 +87,12 @@ func (s *Server) Capabilities() *pb.WorkerCapabilities {
 	return s.capabilities
 }
 
+// Load returns the current load of the worker's host, which the worker
+// reports to the coordinator with each heartbeat.
+func (s *Server) Load() *pb.WorkerLoad {
+	return s.load.Sample()
+}
+
 // Start starts the gRPC server.
 func (s *Server) Start() error {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.config.Port))
@@ -174,7 +192,9 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 		timeout = time.Duration(req.TimeoutSeconds) * time.Second
 	}
 
-	// Create context with timeout
+	// Create context with timeout that CancelTask can also cancel
+	ctx, release := s.running.Track(ctx, req.TaskId, "")
+	defer release()
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -191,6 +211,9 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 		SourceFilename: req.SourceFilename,
 		IncludeFiles:   req.IncludeFiles,
 		IncludePaths:   req.IncludePaths,
+		// Link mode fields
+		Link:       req.Link,
+		LinkInputs: req.LinkInputs,
 		// Client info for OS-aware executor selection
 		ClientOs: req.ClientOs,
 	}
@@ -198,6 +221,16 @@ func (s *Server) Compile(ctx context.Context, req *pb.CompileRequest) (*pb.Compi
 	// Execute compilation with tracing
 	tracing.AddEvent(ctx, "executor.start")
 	result, err := s.executor.Execute(execCtx, execReq)
+	if execCtx.Err() == context.Canceled {
+		atomic.AddInt64(&s.failedTasks, 1)
+		span.SetStatus(otelcodes.Error, "compilation cancelled")
+		log.Info().Str("task_id", req.TaskId).Msg("Compilation cancelled")
+		return &pb.CompileResponse{
+			Status:   pb.TaskStatus_STATUS_CANCELLED,
+			ExitCode: -1,
+			Stderr:   "compilation cancelled",
+		}, nil
+	}
 	if err != nil {
 		atomic.AddInt64(&s.failedTasks, 1)
 		span.SetStatus(otelcodes.Error, err.Error())
@@ -258,14 +291,156 @@ func (s *Server) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResp
 		return nil, status.Error(codes.InvalidArgument, "task_id required")
 	}
 
-	if req.BuildType != pb.BuildType_BUILD_TYPE_FLUTTER {
-		return nil, status.Error(codes.Unimplemented, "use Compile for compilation tasks")
+	execReq, err := newBuildExecRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	execReq.SourceArchive = req.SourceArchive
+
+	return s.runBuild(ctx, execReq)
+}
+
+// StreamBuild receives a chunked source archive, spools it to disk and runs
+// the build once the upload completes. The archive is never held in memory.
+func (s *Server) StreamBuild(stream pb.BuildService_StreamBuildServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "metadata required")
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to receive chunk: %v", err)
+	}
+
+	metadata := first.GetMetadata()
+	if metadata == nil {
+		return status.Error(codes.InvalidArgument, "metadata required")
+	}
+	if metadata.TaskId == "" {
+		return status.Error(codes.InvalidArgument, "task_id required")
+	}
+
+	req, err := buildstream.RequestFromMetadata(metadata)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	execReq, err := newBuildExecRequest(req)
+	if err != nil {
+		return err
+	}
+
+	spool, err := os.CreateTemp("", fmt.Sprintf("hg-stream-%s-*.tar", sanitizeTaskID(req.TaskId)))
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create spool file: %v", err)
+	}
+	defer os.Remove(spool.Name())
+
+	received, err := spoolSourceChunks(stream, spool)
+	if closeErr := spool.Close(); err == nil && closeErr != nil {
+		err = status.Errorf(codes.Internal, "failed to write spool file: %v", closeErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	if metadata.TotalSizeBytes > 0 && received != metadata.TotalSizeBytes {
+		return status.Errorf(codes.InvalidArgument, "incomplete source upload: received %d of %d bytes", received, metadata.TotalSizeBytes)
+	}
+
+	log.Debug().
+		Str("task_id", req.TaskId).
+		Str("build_type", req.BuildType.String()).
+		Int64("source_bytes", received).
+		Msg("Streamed source received")
+
+	execReq.SourceArchivePath = spool.Name()
+
+	resp, err := s.runBuild(stream.Context(), execReq)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp)
+}
+
+// spoolSourceChunks copies source chunks from stream into w until the client
+// closes its side, returning the number of bytes written.
+func spoolSourceChunks(stream pb.BuildService_StreamBuildServer, w io.Writer) (int64, error) {
+	var written int64
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, status.Errorf(codes.Internal, "failed to receive chunk: %v", err)
+		}
+
+		data := chunk.GetSourceChunk()
+		if len(data) == 0 {
+			continue
+		}
+		n, err := w.Write(data)
+		written += int64(n)
+		if err != nil {
+			return written, status.Errorf(codes.Internal, "failed to write spool file: %v", err)
+		}
+	}
+}
+
+// newBuildExecRequest maps a generic build request onto an executor request,
+// rejecting build types this worker has no executor for.
+func newBuildExecRequest(req *pb.BuildRequest) (*executor.Request, error) {
+	execReq := &executor.Request{
+		TaskID:         req.TaskId,
+		BuildType:      req.BuildType,
+		TargetPlatform: req.TargetPlatform,
+		TimeoutSeconds: req.TimeoutSeconds,
 	}
 
-	if req.GetFlutterConfig() == nil {
-		return nil, status.Error(codes.InvalidArgument, "flutter_config required")
+	switch req.BuildType {
+	case pb.BuildType_BUILD_TYPE_FLUTTER:
+		if req.GetFlutterConfig() == nil {
+			return nil, status.Error(codes.InvalidArgument, "flutter_config required")
+		}
+		execReq.FlutterConfig = req.GetFlutterConfig()
+	case pb.BuildType_BUILD_TYPE_UNITY:
+		if req.GetUnityConfig() == nil {
+			return nil, status.Error(codes.InvalidArgument, "unity_config required")
+		}
+		execReq.UnityConfig = req.GetUnityConfig()
+	case pb.BuildType_BUILD_TYPE_COCOS:
+		// Every CocosConfig field is optional; the target platform selects
+		// what Creator publishes.
+		execReq.CocosConfig = req.GetCocosConfig()
+		if execReq.CocosConfig == nil {
+			execReq.CocosConfig = &pb.CocosConfig{}
+		}
+	case pb.BuildType_BUILD_TYPE_RUST:
+		// Every RustConfig field is optional; cargo's defaults apply.
+		execReq.RustConfig = req.GetRustConfig()
+		if execReq.RustConfig == nil {
+			execReq.RustConfig = &pb.RustConfig{}
+		}
+	case pb.BuildType_BUILD_TYPE_GO:
+		execReq.GoConfig = req.GetGoConfig()
+		if execReq.GoConfig == nil {
+			execReq.GoConfig = &pb.GoConfig{}
+		}
+	case pb.BuildType_BUILD_TYPE_NODEJS:
+		execReq.NodeConfig = req.GetNodeConfig()
+		if execReq.NodeConfig == nil {
+			execReq.NodeConfig = &pb.NodeConfig{}
+		}
+	default:
+		return nil, status.Error(codes.Unimplemented, "use Compile for compilation tasks")
 	}
 
+	return execReq, nil
+}
+
+// runBuild executes a build request under the worker's concurrency limit and
+// converts the executor result into a BuildResponse.
+func (s *Server) runBuild(ctx context.Context, execReq *executor.Request) (*pb.BuildResponse, error) {
 	active := atomic.AddInt64(&s.activeTasks, 1)
 	defer atomic.AddInt64(&s.activeTasks, -1)
 
@@ -276,23 +451,40 @@ func (s *Server) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResp
 	atomic.AddInt64(&s.totalTasks, 1)
 
 	timeout := s.config.DefaultTimeout
-	if req.TimeoutSeconds > 0 {
-		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+	if execReq.TimeoutSeconds > 0 {
+		timeout = time.Duration(execReq.TimeoutSeconds) * time.Second
 	}
+	execReq.Timeout = timeout
 
+	ctx, release := s.running.Track(ctx, execReq.TaskID, "")
+	defer release()
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	if taskID := execReq.TaskID; taskID != "" {
+		s.taskLogs.Open(taskID, "")
+		defer s.taskLogs.Close(taskID)
+		execReq.Output = func(stream pb.LogStream, line string) {
+			s.taskLogs.Publish(&pb.TaskLogLine{
+				TaskId:      taskID,
+				Stream:      stream,
+				Line:        line,
+				TimestampMs: time.Now().UnixMilli(),
+			})
+		}
+	}
+
 	start := time.Now()
-	result, err := s.executor.Execute(execCtx, &executor.Request{
-		TaskID:         req.TaskId,
-		BuildType:      req.BuildType,
-		TargetPlatform: req.TargetPlatform,
-		FlutterConfig:  req.GetFlutterConfig(),
-		SourceArchive:  req.SourceArchive,
-		TimeoutSeconds: req.TimeoutSeconds,
-		Timeout:        timeout,
-	})
+	result, err := s.executor.Execute(execCtx, execReq)
+	if execCtx.Err() == context.Canceled {
+		atomic.AddInt64(&s.failedTasks, 1)
+		log.Info().Str("task_id", execReq.TaskID).Msg("Build cancelled")
+		return &pb.BuildResponse{
+			Status:   pb.TaskStatus_STATUS_CANCELLED,
+			ExitCode: -1,
+			Stderr:   "build cancelled",
+		}, nil
+	}
 	if err != nil {
 		atomic.AddInt64(&s.failedTasks, 1)
 		return &pb.BuildResponse{
@@ -325,20 +517,54 @@ func (s *Server) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResp
 	return resp, nil
 }
 
-// StreamBuild is not implemented for workers.
-func (s *Server) StreamBuild(stream pb.BuildService_StreamBuildServer) error {
-	return status.Error(codes.Unimplemented, "stream build not supported by workers")
+// sanitizeTaskID keeps task IDs safe for use in temp file names.
+func sanitizeTaskID(taskID string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, taskID)
+}
+
+// CancelTask cancels a running compile or build. Its executor kills the
+// process tree or container and the task's slot is freed once it returns.
+func (s *Server) CancelTask(ctx context.Context, req *pb.CancelTaskRequest) (*pb.CancelTaskResponse, error) {
+	if req.TaskId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id required")
+	}
+
+	// Only the coordinator reaches a worker, and it checks task ownership
+	cancelled, _ := s.running.Cancel(req.TaskId, nil)
+	if cancelled {
+		log.Info().Str("task_id", req.TaskId).Msg("Task cancellation requested")
+	}
+	return &pb.CancelTaskResponse{Cancelled: cancelled}, nil
+}
+
+// WatchTask streams the output of a build as it runs. Watching may start
+// before the build arrives; the stream ends when the build finishes.
+func (s *Server) WatchTask(req *pb.WatchTaskRequest, stream pb.BuildService_WatchTaskServer) error {
+	if req.TaskId == "" {
+		return status.Error(codes.InvalidArgument, "task_id required")
+	}
+	return s.taskLogs.Serve(stream.Context(), req.TaskId, nil, stream.Send)
 }
 
 // HealthCheck returns worker health status.
 func (s *Server) HealthCheck(ctx context.Context, req *pb.HealthRequest) (*pb.HealthResponse, error) {
 	active := atomic.LoadInt64(&s.activeTasks)
 	healthy := active < int64(s.config.MaxConcurrent)
+	load := s.Load()
 
 	return &pb.HealthResponse{
-		Healthy:     healthy,
-		ActiveTasks: int32(active),
-		QueuedTasks: 0,
+		Healthy:            healthy,
+		ActiveTasks:        int32(active),
+		QueuedTasks:        0,
+		CpuUsagePercent:    load.CpuUsagePercent,
+		MemoryUsagePercent: load.MemoryUsagePercent,
 	}, nil
 }
 
@@ -360,6 +586,8 @@ func (s *Server) GetWorkerStatus(ctx context.Context, req *pb.WorkerStatusReques
 		ActiveTasks:         int32(atomic.LoadInt64(&s.activeTasks)),
 		TotalTasksCompleted: total,
 		AvgLatencyMs:        float32(avgTime),
+		Capabilities:        s.capabilities,
+		Load:                s.Load(),
 	}
 
 	return &pb.WorkerStatusResponse{
@@ -1,15 +1,25 @@
 package server
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 
 	pb "github.com/h3nr1-d14z/hybridgrid/gen/go/hybridgrid/v1"
 )
@@ -78,14 +88,56 @@ func TestBuild_Unimplemented(t *testing.T) {
 	assert.Equal(t, codes.Unimplemented, st.Code())
 }
 
-// --- StreamBuild (not implemented) ---
+// --- StreamBuild ---
 
-func TestStreamBuild_Unimplemented(t *testing.T) {
-	s := New(DefaultConfig())
+func setupStreamClient(t *testing.T, s *Server) pb.BuildServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	pb.RegisterBuildServiceServer(srv, s)
+	go func() { _ = srv.Serve(lis) }()
 
-	// StreamBuild takes a stream interface; calling with nil will cause a nil pointer.
-	// We test the actual gRPC method returns Unimplemented via direct call.
-	err := s.StreamBuild(nil)
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+	})
+	return pb.NewBuildServiceClient(conn)
+}
+
+func sendStreamBuild(t *testing.T, client pb.BuildServiceClient, metadata *pb.BuildMetadata, source []byte) (*pb.BuildResponse, error) {
+	t.Helper()
+
+	stream, err := client.StreamBuild(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.BuildChunk{
+		Payload: &pb.BuildChunk_Metadata{Metadata: metadata},
+	}))
+	for len(source) > 0 {
+		n := min(4, len(source))
+		require.NoError(t, stream.Send(&pb.BuildChunk{
+			Payload: &pb.BuildChunk_SourceChunk{SourceChunk: source[:n]},
+		}))
+		source = source[n:]
+	}
+	return stream.CloseAndRecv()
+}
+
+func TestStreamBuild_UnsupportedBuildType(t *testing.T) {
+	client := setupStreamClient(t, New(DefaultConfig()))
+
+	_, err := sendStreamBuild(t, client, &pb.BuildMetadata{
+		TaskId:    "stream-cpp",
+		BuildType: pb.BuildType_BUILD_TYPE_CPP,
+	}, []byte("int main() {}"))
 	require.Error(t, err)
 
 	st, ok := status.FromError(err)
@@ -93,6 +145,165 @@ func TestStreamBuild_Unimplemented(t *testing.T) {
 	assert.Equal(t, codes.Unimplemented, st.Code())
 }
 
+func TestStreamBuild_IncompleteUpload(t *testing.T) {
+	client := setupStreamClient(t, New(DefaultConfig()))
+
+	_, err := sendStreamBuild(t, client, &pb.BuildMetadata{
+		TaskId:         "stream-short",
+		BuildType:      pb.BuildType_BUILD_TYPE_FLUTTER,
+		TargetPlatform: pb.TargetPlatform_PLATFORM_ANDROID,
+		TotalSizeBytes: 1024,
+	}, []byte("partial"))
+	require.Error(t, err)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Contains(t, st.Message(), "incomplete source upload")
+}
+
+func TestStreamBuild_FlutterSpoolsArchiveForExecutor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake flutter script requires a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"if [ \"$1\" = \"build\" ]; then\n" +
+		"  test -f pubspec.yaml || exit 3\n" +
+		"  mkdir -p build/app/outputs/flutter-apk\n" +
+		"  printf 'fake apk' > build/app/outputs/flutter-apk/app-release.apk\n" +
+		"fi\n" +
+		"exit 0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "flutter"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	content := []byte("name: demo\n")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "pubspec.yaml", Mode: 0644, Size: int64(len(content))}))
+	_, err := tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	client := setupStreamClient(t, New(DefaultConfig()))
+	resp, err := sendStreamBuild(t, client, &pb.BuildMetadata{
+		TaskId:         "stream-flutter",
+		BuildType:      pb.BuildType_BUILD_TYPE_FLUTTER,
+		TargetPlatform: pb.TargetPlatform_PLATFORM_ANDROID,
+		TotalSizeBytes: int64(archive.Len()),
+		ConfigJson:     `{"buildMode":"apk-release"}`,
+	}, archive.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status, resp.Stderr)
+	require.Len(t, resp.ArtifactList, 1)
+	assert.Equal(t, "app-release.apk", resp.ArtifactList[0].Name)
+}
+
+func TestStreamBuild_RustRunsCargo(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake cargo script requires a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"test -f Cargo.toml || exit 3\n" +
+		"mkdir -p \"$CARGO_TARGET_DIR/release\"\n" +
+		"printf 'fake binary' > \"$CARGO_TARGET_DIR/release/demo\"\n" +
+		"exit 0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "cargo"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	content := []byte("[package]\nname = \"demo\"\n")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "Cargo.toml", Mode: 0644, Size: int64(len(content))}))
+	_, err := tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	client := setupStreamClient(t, New(DefaultConfig()))
+	resp, err := sendStreamBuild(t, client, &pb.BuildMetadata{
+		TaskId:         "stream-rust",
+		BuildType:      pb.BuildType_BUILD_TYPE_RUST,
+		TotalSizeBytes: int64(archive.Len()),
+		ConfigJson:     `{"release":true}`,
+	}, archive.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status, resp.Stderr)
+	require.Len(t, resp.ArtifactList, 1)
+	assert.Equal(t, "target/release/demo", resp.ArtifactList[0].Path)
+}
+
+// --- WatchTask ---
+
+func TestWatchTask_StreamsBuildOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake cargo script requires a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo '   Compiling demo v0.1.0'\n" +
+		"echo 'warning: unused variable' >&2\n" +
+		"mkdir -p \"$CARGO_TARGET_DIR/release\"\n" +
+		"printf 'fake binary' > \"$CARGO_TARGET_DIR/release/demo\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "cargo"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	content := []byte("[package]\nname = \"demo\"\n")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "Cargo.toml", Mode: 0644, Size: int64(len(content))}))
+	_, err := tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	client := setupStreamClient(t, New(DefaultConfig()))
+
+	// Watch before the build arrives, as the coordinator does.
+	watch, err := client.WatchTask(context.Background(), &pb.WatchTaskRequest{TaskId: "watched-rust"})
+	require.NoError(t, err)
+
+	resp, err := sendStreamBuild(t, client, &pb.BuildMetadata{
+		TaskId:         "watched-rust",
+		BuildType:      pb.BuildType_BUILD_TYPE_RUST,
+		TotalSizeBytes: int64(archive.Len()),
+		ConfigJson:     `{"release":true}`,
+	}, archive.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, pb.TaskStatus_STATUS_COMPLETED, resp.Status, resp.Stderr)
+
+	var lines []*pb.TaskLogLine
+	for {
+		line, err := watch.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, "watched-rust", lines[0].TaskId)
+	assert.ElementsMatch(t,
+		[]string{"   Compiling demo v0.1.0", "warning: unused variable"},
+		[]string{lines[0].Line, lines[1].Line})
+	for _, line := range lines {
+		if line.Line == "warning: unused variable" {
+			assert.Equal(t, pb.LogStream_LOG_STREAM_STDERR, line.Stream)
+		}
+	}
+}
+
+func TestWatchTask_RequiresTaskID(t *testing.T) {
+	client := setupStreamClient(t, New(DefaultConfig()))
+
+	watch, err := client.WatchTask(context.Background(), &pb.WatchTaskRequest{})
+	require.NoError(t, err)
+	_, err = watch.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 // --- GetWorkersForBuild (not applicable) ---
 
 func TestGetWorkersForBuild_Unimplemented(t *testing.T) {
@@ -189,6 +400,57 @@ func TestCompile_MetricsTracking(t *testing.T) {
 	assert.Equal(t, int64(0), s.activeTasks)
 }
 
+func TestCancelTask_StopsRunningCompile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the compiler")
+	}
+
+	// A "compiler" that never finishes on its own.
+	compiler := filepath.Join(t.TempDir(), "slow-cc")
+	require.NoError(t, os.WriteFile(compiler, []byte("#!/bin/sh\nsleep 60\n"), 0755))
+
+	s := New(Config{Port: 0, MaxConcurrent: 4, DefaultTimeout: 2 * time.Minute})
+
+	type result struct {
+		resp *pb.CompileResponse
+		err  error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		resp, err := s.Compile(context.Background(), &pb.CompileRequest{
+			TaskId:             "slow-task",
+			PreprocessedSource: []byte("int main() {}"),
+			Compiler:           compiler,
+		})
+		resultCh <- result{resp, err}
+	}()
+
+	require.Eventually(t, func() bool {
+		resp, err := s.CancelTask(context.Background(), &pb.CancelTaskRequest{TaskId: "slow-task"})
+		return err == nil && resp.Cancelled
+	}, 5*time.Second, 10*time.Millisecond)
+
+	select {
+	case r := <-resultCh:
+		require.NoError(t, r.err)
+		assert.Equal(t, pb.TaskStatus_STATUS_CANCELLED, r.resp.Status)
+	case <-time.After(10 * time.Second):
+		t.Fatal("cancelled compile did not return")
+	}
+	assert.Equal(t, int64(0), s.activeTasks)
+}
+
+func TestCancelTask_UnknownTask(t *testing.T) {
+	s := New(Config{Port: 0, MaxConcurrent: 4})
+
+	resp, err := s.CancelTask(context.Background(), &pb.CancelTaskRequest{TaskId: "missing"})
+	require.NoError(t, err)
+	assert.False(t, resp.Cancelled)
+
+	_, err = s.CancelTask(context.Background(), &pb.CancelTaskRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 // --- HealthCheck ---
 
 func TestHealthCheck_Healthy(t *testing.T) {
@@ -241,6 +503,8 @@ func TestGetWorkerStatus(t *testing.T) {
 	assert.Equal(t, int32(2), w.ActiveTasks)
 	assert.Equal(t, int64(10), w.TotalTasksCompleted)
 	assert.Equal(t, float32(100), w.AvgLatencyMs) // 800ms / 8 success = 100ms
+	require.NotNil(t, w.Load)
+	assert.LessOrEqual(t, w.Load.MemoryUsagePercent, float32(100))
 }
 
 func TestGetWorkerStatus_ZeroSuccess(t *testing.T) {